@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/config"
+)
+
+// usage prints the top-level CLI help.
+func usage(w io.Writer) {
+	fmt.Fprintf(w, `usage: pulse [--config path] [command]
+
+with no command, pulse starts the api server and background workers.
+
+commands:
+  config validate   load the config file and environment, report any errors
+
+flags:
+  --config path     YAML or TOML config file, overridden by env vars (env: %s)
+`, config.ConfigPathEnv)
+}
+
+// runCommand dispatches CLI subcommands.
+// returns the process exit code.
+func runCommand(args []string, configPath string) int {
+	switch {
+	case len(args) >= 2 && args[0] == "config" && args[1] == "validate":
+		return runConfigValidate(args[2:], configPath)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", strings.Join(args, " "))
+		usage(os.Stderr)
+		return 2
+	}
+}
+
+// runConfigValidate loads configuration exactly like the server would
+// and reports whether it is valid, without connecting to anything.
+func runConfigValidate(args []string, configPath string) int {
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	path := fs.String("config", configPath, "YAML or TOML config file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if _, err := config.Load(*path); err != nil {
+		fmt.Fprintf(os.Stderr, "configuration invalid: %v\n", err)
+		return 1
+	}
+
+	source := "environment only"
+	if *path != "" {
+		source = *path + " + environment"
+	}
+	fmt.Printf("configuration valid (%s)\n", source)
+	return 0
+}
@@ -2,49 +2,67 @@ package main
 
 import (
 	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // quiet hours load timezones, and the image has no zoneinfo
 
 	"github.com/joacominatel/pulse/internal/application"
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/api"
 	"github.com/joacominatel/pulse/internal/infrastructure/auth"
+	"github.com/joacominatel/pulse/internal/infrastructure/billing"
 	"github.com/joacominatel/pulse/internal/infrastructure/cache"
 	"github.com/joacominatel/pulse/internal/infrastructure/config"
 	"github.com/joacominatel/pulse/internal/infrastructure/database"
+	"github.com/joacominatel/pulse/internal/infrastructure/eventbus"
+	"github.com/joacominatel/pulse/internal/infrastructure/health"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 	"github.com/joacominatel/pulse/internal/infrastructure/metrics"
 	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+	"github.com/joacominatel/pulse/internal/infrastructure/standby"
 	"github.com/joacominatel/pulse/internal/infrastructure/worker"
 )
 
-const (
-	// momentumCalculationInterval is how often momentum is recalculated
-	momentumCalculationInterval = 5 * time.Minute
-)
-
 func main() {
+	configPath := flag.String("config", os.Getenv(config.ConfigPathEnv), "YAML or TOML config file, overridden by env vars")
+	flag.Usage = func() { usage(flag.CommandLine.Output()) }
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Args(), *configPath))
+	}
+
 	logger := logging.New()
 	logger.Info("pulse starting up")
 
-	if err := run(logger); err != nil {
+	if err := run(logger, *configPath); err != nil {
 		logger.Error("application failed", "error", err.Error())
 		os.Exit(1)
 	}
 }
 
-func run(logger *logging.Logger) error {
+func run(logger *logging.Logger, configPath string) error {
 	// load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(configPath)
 	if err != nil {
 		logger.Error("failed to load configuration", "error", err.Error())
 		return err
 	}
 
-	// establish database connection
-	conn, err := database.New(&cfg.Database, logger)
+	// apply configured log level, already validated by config.Load
+	logLevel, _ := logging.ParseLevel(cfg.Log.Level)
+	logger.SetLevel(logLevel)
+
+	// establish database connection, waiting for postgres if it's still starting
+	var conn *database.Connection
+	err = waitFor(context.Background(), "database", cfg.Startup, logger, func(context.Context) error {
+		var connErr error
+		conn, connErr = database.New(&cfg.Database, logger)
+		return connErr
+	})
 	if err != nil {
 		return err
 	}
@@ -55,7 +73,13 @@ func run(logger *logging.Logger) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	if err := migrator.Run(ctx); err != nil {
+	migrationsApplied, err := migrator.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	appliedVersions, err := migrator.GetAppliedMigrations(ctx)
+	if err != nil {
 		return err
 	}
 
@@ -67,7 +91,15 @@ func run(logger *logging.Logger) error {
 	logger.Info("pulse infrastructure ready", "schema", conn.Schema())
 
 	// initialize prometheus metrics
-	appMetrics := metrics.New()
+	var metricsOpts []metrics.Option
+	if cfg.Metrics.TopCommunities > 0 {
+		// per-community ingest counters, bounded to the busiest communities
+		metricsOpts = append(metricsOpts, metrics.WithTopCommunities(cfg.Metrics.TopCommunities))
+	}
+	if cfg.Metrics.CommunityMomentum > 0 {
+		metricsOpts = append(metricsOpts, metrics.WithCommunityMomentum())
+	}
+	appMetrics := metrics.New(metricsOpts...)
 	logger.Info("prometheus metrics initialized")
 
 	// initialize jwt validator
@@ -82,6 +114,7 @@ func run(logger *logging.Logger) error {
 	// initialize redis (optional - disabled if REDIS_URL is empty)
 	var redisClient *cache.RedisClient
 	var communityRepo domain.CommunityRepository = postgresCommunityRepo
+	var cachedCommunityRepo *cache.CommunityRepositoryWithCache
 
 	if cfg.Redis.URL != "" {
 		redisClient, err = cache.NewRedisClient(cache.RedisConfig{URL: cfg.Redis.URL}, logger)
@@ -90,87 +123,625 @@ func run(logger *logging.Logger) error {
 			return err
 		}
 
-		if err := redisClient.Connect(ctx); err != nil {
+		if err := waitFor(ctx, "redis", cfg.Startup, logger, redisClient.Connect); err != nil {
 			logger.Warn("redis connection failed, continuing without cache", "error", err.Error())
 			redisClient = nil
 		} else {
 			defer func() { _ = redisClient.Close() }()
 			// wrap community repo with redis cache for reads
-			communityRepo = cache.NewCommunityRepositoryWithCache(postgresCommunityRepo, redisClient, logger)
+			cachedCommunityRepo = cache.NewCommunityRepositoryWithCache(postgresCommunityRepo, redisClient, logger).
+				WithSummaries(postgres.NewCommunitySummaryRepository(pool))
+			communityRepo = cachedCommunityRepo
 			logger.Info("redis leaderboard cache enabled")
 		}
 	}
 
+	// dependency latency and worker heartbeats, kept for 24 hours and served on /statusz
+	healthMonitor := health.NewMonitor(health.DefaultMonitorConfig(), logger)
+	healthMonitor.AddCheck("database", pool.Ping)
+	if redisClient != nil {
+		healthMonitor.AddCheck("redis", redisClient.HealthCheck)
+	}
+
+	// billable usage, accumulated in memory and flushed by the metering worker
+	meter := application.NewMeter()
+	meteringOpts, err := meteringExporters(cfg.Metering, logger)
+	if err != nil {
+		logger.Error("failed to configure metering export", "error", err.Error())
+		return err
+	}
+
+	// initialize community existence cache for high-throughput ingestion
+	// caches community exists/active checks to avoid DB hits on every event
+	communityExistsCache := cache.NewCommunityExistsCache(postgresCommunityRepo, 1*time.Minute)
+
+	// same for the user behind authenticated events
+	userExistsCache := cache.NewUserExistsCache(userRepo, 5*time.Minute)
+
+	// already validated by config.Load
+	validationMode, _ := domain.ParseValidationMode(cfg.Ingest.Validation)
+
 	// initialize event ingestion worker (async buffer pattern)
 	ingestionWorkerConfig := worker.DefaultEventIngestionConfig()
+	ingestionWorkerConfig.MaxQueueAge = cfg.Ingest.MaxQueueAge
+	rejectedEventRepo := postgres.NewRejectedEventRepository(pool)
 	ingestionWorker := worker.NewEventIngestionWorker(eventRepo, ingestionWorkerConfig, logger).
-		WithMetrics(appMetrics)
+		WithMetrics(appMetrics).
+		WithMeter(meter).
+		WithHeartbeat(healthMonitor).
+		WithQuarantine(rejectedEventRepo) // stale events, retried by clients that timed out
+	healthMonitor.ExpectHeartbeat("ingestion", time.Minute)
+	if redisClient != nil {
+		// unique contributor counts for community stats
+		ingestionWorker.WithContributors(redisClient)
+	}
+	if validationMode == domain.ValidationDeferred {
+		// the existence checks skipped on accept run here, failures go to the quarantine
+		ingestionWorker.WithValidator(application.NewDeferredValidator(communityExistsCache, userExistsCache, rejectedEventRepo, logger))
+	}
 
-	// start the ingestion worker before accepting requests
 	workerCtx, workerCancel := context.WithCancel(context.Background())
-	ingestionWorker.Start(workerCtx)
 
 	// initialize webhook subscription repository
 	webhookSubRepo := postgres.NewWebhookSubscriptionRepository(pool)
 
+	// subscribers' muted communities, quiet hours and hourly caps, cached since every delivery reads them
+	notificationPrefsRepo := cache.NewNotificationPreferencesCache(postgres.NewNotificationPreferencesRepository(pool), 1*time.Minute)
+
 	// initialize webhook worker for momentum spike notifications
 	webhookWorkerConfig := worker.DefaultWebhookWorkerConfig()
-	webhookWorker := worker.NewWebhookWorker(webhookSubRepo, webhookWorkerConfig, logger)
+	webhookWorkerConfig.Thresholds = cfg.Momentum.SpikeThresholds()
+	webhookWorkerConfig.DigestWindow = cfg.Webhook.DigestWindow
+	webhookWorkerConfig.AllowSubscriptionProxy = cfg.Webhook.AllowSubscriptionProxy
+	if cfg.Webhook.Proxy != "" {
+		// already validated by config.Load
+		webhookWorkerConfig.Proxy, _ = domain.ParseWebhookProxy(cfg.Webhook.Proxy)
+	}
+	webhookDeliveryRepo := postgres.NewWebhookDeliveryRepository(pool)
+	// domains each community's webhooks may go to, checked on every dispatch
+	webhookAllowlistRepo := postgres.NewCommunityWebhookAllowlistRepository(pool)
+	webhookWorker := worker.NewWebhookWorker(webhookSubRepo, webhookWorkerConfig, logger).
+		WithMetrics(appMetrics).
+		WithMeter(meter).
+		WithPreferences(notificationPrefsRepo).
+		WithDeliveryLog(webhookDeliveryRepo).
+		WithAllowlists(webhookAllowlistRepo).
+		WithOutbox(postgres.NewWebhookOutboxRepository(pool)) // held during subscribers' pause windows
+	if redisClient != nil {
+		// spike payloads carry the community's leaderboard move
+		webhookWorker.WithRanks(cachedCommunityRepo)
+	}
 	webhookWorker.Start(workerCtx)
 
-	// initialize community existence cache for high-throughput ingestion
-	// caches community exists/active checks to avoid DB hits on every event
-	communityExistsCache := cache.NewCommunityExistsCache(postgresCommunityRepo, 1*time.Minute)
+	// domain events published by the use cases, relayed between instances through redis when available
+	eventBus := eventbus.New(logger)
+	if redisClient != nil {
+		eventBus.WithRedis(redisClient.Client())
+	}
+	eventBus.Subscribe(domain.EventSpikeDetected, webhookWorker.HandleSpikeDetected)
+	eventBus.SubscribeCluster(domain.EventCommunityCreated, communityExistsCache.HandleCommunityCreated)
+	eventBus.SubscribeCluster(domain.EventCommunityDeactivated, communityExistsCache.HandleCommunityDeactivated)
+
+	// live momentum feeds, fed the updates of every instance's momentum worker
+	liveHub := eventbus.NewHub(eventbus.DefaultHubBuffer)
+	eventBus.SubscribeCluster(domain.EventMomentumCalculated, liveHub.Handle)
+	eventBus.SubscribeCluster(domain.EventSpikeDetected, liveHub.Handle)
+	eventBus.Start(workerCtx)
+
+	// flush metering records into the database and any configured exports
+	meteringUseCase := application.NewMeteringUseCase(meter, postgres.NewMeteringRepository(pool), logger, meteringOpts...)
+	meteringWorkerConfig := worker.DefaultMeteringWorkerConfig()
+	meteringWorkerConfig.Interval = cfg.Metering.Interval
+	meteringWorker := worker.NewMeteringWorker(meteringUseCase, meteringWorkerConfig, logger).
+		WithMetrics(appMetrics).
+		WithHeartbeat(healthMonitor)
+	healthMonitor.ExpectHeartbeat("metering", 3*meteringWorkerConfig.Interval)
+	meteringWorker.Start(workerCtx)
+
+	// daily ingestion usage, shared through redis when available
+	var usageCounter application.UsageCounter = cache.NewMemoryUsageCounter()
+	if redisClient != nil {
+		usageCounter = cache.NewRedisUsageCounter(redisClient)
+	}
+
+	// already validated by config.Load
+	quotaMode, _ := domain.ParseQuotaMode(cfg.Quota.Mode)
+	quotaRepo := cache.NewIngestionQuotaCache(postgres.NewIngestionQuotaRepository(pool), 1*time.Minute)
+	quotaEnforcer := application.NewQuotaEnforcer(
+		usageCounter,
+		application.QuotaPolicy{
+			CommunityDailyEvents:    cfg.Quota.CommunityDailyEvents,
+			OrganizationDailyEvents: cfg.Quota.OrganizationDailyEvents,
+			Mode:                    quotaMode,
+		},
+		logger,
+		application.WithQuotaOverrides(quotaRepo),
+		application.WithOrganizationResolver(communityExistsCache), // count against the organization too
+		application.WithQuotaExceededHook(func(scope domain.UsageScope, mode domain.QuotaMode) {
+			appMetrics.RecordQuotaExceeded(scope.String(), mode.String())
+		}),
+	)
+
+	organizationRepo := postgres.NewOrganizationRepository(pool)
+	communityMemberRepo := postgres.NewCommunityMemberRepository(pool)
+
+	// bans and mutes are checked on every authenticated event
+	moderationRepo := cache.NewModerationCache(postgres.NewModerationRepository(pool), 1*time.Minute)
+
+	// private communities only take events from, and report to, their members
+	communityAccess := application.NewCommunityAccess(
+		postgresCommunityRepo,
+		communityMemberRepo,
+		application.WithOrganizationMembers(organizationRepo),
+		application.WithVisibilityResolver(communityExistsCache),
+	)
+
+	// per-community event weights, cached since every event sent without a weight reads them
+	eventWeightsRepo := cache.NewEventWeightsCache(postgres.NewCommunityEventWeightsRepository(pool), 1*time.Minute)
 
 	// initialize use cases
-	ingestEventUseCase := application.NewIngestEventUseCase(
+	ingestOpts := []application.IngestEventOption{
+		application.WithEventChannel(ingestionWorker.EventChannel()),  // enable async mode
+		application.WithCommunityChecker(communityExistsCache),        // use cache for existence checks
+		application.WithUserChecker(userExistsCache),                  // and for the event's user
+		application.WithQuotas(quotaEnforcer),                         // daily ingestion quotas
+		application.WithCommunityAccess(communityAccess),              // members only for private communities
+		application.WithSanctions(moderationRepo),                     // reject banned, drop muted users
+		application.WithCommunityEventWeights(eventWeightsRepo),       // community weights for events sent without one
+		application.WithEventTimeBounds(cfg.Ingest.EventTimeBounds()), // how far occurred_at may be from now
+		application.WithPersistenceNotifier(ingestionWorker, cfg.Ingest.AckTimeout),
+	}
+	if validationMode == domain.ValidationDeferred {
+		ingestOpts = append(ingestOpts, application.WithDeferredValidation()) // existence checked by the worker
+	}
+	ingestEventUseCase := application.NewIngestEventUseCase(eventRepo, communityRepo, userRepo, logger, ingestOpts...)
+
+	// event groups are saved at once with their idempotency key, bypassing the buffer
+	ingestEventGroupUseCase := application.NewIngestEventGroupUseCase(
+		ingestEventUseCase,
+		postgres.NewEventGroupRepository(pool),
+		postgres.NewUnitOfWork(pool),
+		logger,
+		application.WithSavedEventsHandler(ingestionWorker),
+	)
+
+	// per-community momentum overrides, cached since every cycle reads them
+	momentumSettingsRepo := cache.NewMomentumSettingsCache(postgres.NewCommunityMomentumSettingsRepository(pool), 1*time.Minute)
+
+	// suspicious ingest rates are flagged and kept out of momentum until reviewed
+	anomalyRepo := postgres.NewAnomalyFlagRepository(pool)
+	anomalyUseCase := application.NewAnomalyUseCase(
 		eventRepo,
+		anomalyRepo,
+		application.AnomalyConfig{
+			Window:     cfg.Anomaly.Window,
+			Baseline:   cfg.Anomaly.Baseline,
+			Thresholds: cfg.Anomaly.Thresholds(),
+		},
+		logger,
+		application.WithAnomalyNotifier(webhookWorker), // anomaly_flagged webhooks
+	)
+	// a standby serves reads and skips the scheduled workers until an admin makes it active
+	standbySwitch := standby.New(cfg.Standby, logger)
+
+	var anomalyWorker *worker.AnomalyWorker
+	if cfg.Anomaly.Interval > 0 {
+		anomalyWorkerConfig := worker.DefaultAnomalyWorkerConfig()
+		anomalyWorkerConfig.Interval = cfg.Anomaly.Interval
+		anomalyWorker = worker.NewAnomalyWorker(anomalyUseCase, anomalyWorkerConfig, logger).
+			WithMetrics(appMetrics).
+			WithHeartbeat(healthMonitor).
+			WithStandby(standbySwitch)
+		healthMonitor.ExpectHeartbeat("anomaly", 3*anomalyWorkerConfig.Interval)
+		anomalyWorker.Start(workerCtx)
+	}
+
+	// member counts and recent activity for community listings, rolled up in the background
+	var communitySummaries api.CommunitySummaries
+	var summaryRollupWorker *worker.SummaryRollupWorker
+	if cfg.Summary.Interval > 0 {
+		summaryUseCase := application.NewCommunitySummaryUseCase(postgres.NewCommunitySummaryRepository(pool), logger)
+		communitySummaries = summaryUseCase
+		summaryRollupConfig := worker.DefaultSummaryRollupConfig()
+		summaryRollupConfig.Interval = cfg.Summary.Interval
+		summaryRollupWorker = worker.NewSummaryRollupWorker(summaryUseCase, summaryRollupConfig, logger).
+			WithMetrics(appMetrics).
+			WithHeartbeat(healthMonitor).
+			WithStandby(standbySwitch)
+		healthMonitor.ExpectHeartbeat("summary_rollup", 3*summaryRollupConfig.Interval)
+		summaryRollupWorker.Start(workerCtx)
+	}
+
+	momentumRunRepo := postgres.NewMomentumRunRepository(pool)
+	momentumHistoryRepo := postgres.NewMomentumHistoryRepository(pool)
+	rankingSnapshotRepo := postgres.NewRankingSnapshotRepository(pool)
+	momentumOpts := []application.CalculateMomentumOption{
+		application.WithMomentumEvents(eventBus),       // spikes reach the webhook worker as events
+		application.WithSpikeThresholds(webhookWorker), // reloadable spike thresholds
+		application.WithSettings(momentumSettingsRepo), // per-community overrides
+		application.WithQuarantine(anomalyRepo),        // leave flagged events out
+		application.WithMomentumDecay(cfg.Momentum.DecayHalfLife),
+		application.WithCycleRetry(cfg.Momentum.RetryAttempts, cfg.Momentum.RetryBackoff),
+		application.WithStaleAfter(cfg.Momentum.StaleAfterCycles),
+		application.WithRunLog(momentumRunRepo),               // audit trail of every cycle
+		application.WithMomentumHistory(momentumHistoryRepo),  // history charts
+		application.WithRankingSnapshots(rankingSnapshotRepo), // leaderboard changes
+	}
+
+	// one spike notification per community per cooldown, shared through redis when available
+	var spikeCooldown application.SpikeCooldown
+	memorySpikeCooldown := cache.NewMemorySpikeCooldown()
+	if redisClient != nil {
+		spikeCooldown = redisClient
+	} else {
+		spikeCooldown = memorySpikeCooldown
+	}
+	momentumOpts = append(momentumOpts, application.WithSpikeCooldown(spikeCooldown, cfg.Webhook.SpikeCooldown))
+
+	// wire redis leaderboard to momentum use case if available
+	var rebuildLeaderboardUseCase *application.RebuildLeaderboardUseCase
+	if redisClient != nil {
+		momentumOpts = append(momentumOpts,
+			application.WithLeaderboard(redisClient),
+			application.WithLeaderboardSnapshots(redisClient),
+			application.WithRegionalLeaderboards(eventRepo, redisClient),
+			application.WithPercentiles(redisClient),
+		)
+		// rebuild reads postgres directly, the cached repo would read the leaderboard itself
+		rebuildLeaderboardUseCase = application.NewRebuildLeaderboardUseCase(postgresCommunityRepo, redisClient, logger)
+	}
+
+	// rebuild the leaderboard whenever redis comes back without it
+	var leaderboardResyncWorker *worker.LeaderboardResyncWorker
+	if redisClient != nil {
+		leaderboardResyncWorker = worker.NewLeaderboardResyncWorker(redisClient, rebuildLeaderboardUseCase, worker.DefaultLeaderboardResyncConfig(), logger).
+			WithMetrics(appMetrics).
+			WithStandby(standbySwitch)
+		cachedCommunityRepo.OnEmptyLeaderboard(leaderboardResyncWorker.Kick)
+		leaderboardResyncWorker.Start(workerCtx)
+	}
+
+	// regional rankings only live in redis
+	var regionalLeaderboard application.RegionalLeaderboardReader
+	if redisClient != nil {
+		regionalLeaderboard = redisClient
+	}
+	// pins are read for every first leaderboard page, cached briefly
+	communityPinRepo := cache.NewCommunityPinCache(postgres.NewCommunityPinRepository(pool), 30*time.Second)
+	communityPinUseCase := application.NewCommunityPinUseCase(communityPinRepo, communityRepo, logger)
+	leaderboardUseCase := application.NewLeaderboardUseCase(communityRepo, regionalLeaderboard, logger,
+		application.WithPins(communityPinRepo),
+		application.WithRankingHistory(rankingSnapshotRepo),
+	)
+
+	var feedOpts []application.FeedOption
+	if redisClient != nil {
+		feedOpts = append(feedOpts, application.WithFeedCache(redisClient)) // shared across instances
+	}
+	feedUseCase := application.NewFeedUseCase(
 		communityRepo,
+		postgresCommunityRepo,
+		eventRepo,
 		userRepo,
+		communityAccess,
+		application.DefaultFeedConfig(),
 		logger,
-	).WithEventChannel(ingestionWorker.EventChannel()). // enable async mode
-								WithCommunityChecker(communityExistsCache) // use cache for existence checks
+		feedOpts...,
+	)
 
+	momentumConfig := application.DefaultMomentumConfig()
 	calculateMomentumUseCase := application.NewCalculateMomentumUseCase(
 		eventRepo,
 		communityRepo,
-		application.DefaultMomentumConfig(),
+		momentumConfig,
 		logger,
-	).WithNotifier(webhookWorker) // wire spike notifications
+		momentumOpts...,
+	)
 
-	// wire redis leaderboard to momentum use case if available
+	// recalculates every community each interval, started once the config reloader is running
+	momentumWorkerConfig := worker.DefaultMomentumWorkerConfig()
+	momentumWorkerConfig.Interval = cfg.Momentum.Interval
+	momentumWorkerConfig.StartPaused = cfg.Momentum.StartPaused
+	momentumWorker := worker.NewMomentumWorker(calculateMomentumUseCase, momentumWorkerConfig, logger).
+		WithMetrics(appMetrics).
+		WithHeartbeat(healthMonitor).
+		WithStandby(standbySwitch)
+	if cfg.Metrics.CommunityMomentum > 0 {
+		momentumWorker.WithCommunityMomentum(communityRepo, appMetrics, cfg.Metrics.CommunityMomentum)
+	}
+
+	// recalculate communities right away when events arrive for a window that was already calculated
+	lateEventWorker := worker.NewLateEventWorker(calculateMomentumUseCase, worker.DefaultLateEventConfig(), logger).
+		WithMetrics(appMetrics)
+	ingestionWorker.WithLateEvents(lateEventWorker)
+	lateEventWorker.Start(workerCtx)
+
+	// start the ingestion worker before accepting requests
+	ingestionWorker.Start(workerCtx)
+
+	// events published on kafka go through the same buffer as the ones sent to the API
+	var kafkaSource *worker.KafkaIngestionSource
+	if cfg.Kafka.Enabled() {
+		kafkaConfig := worker.DefaultKafkaIngestionConfig()
+		kafkaConfig.Brokers = cfg.Kafka.Brokers
+		kafkaConfig.Topic = cfg.Kafka.Topic
+		kafkaConfig.GroupID = cfg.Kafka.GroupID
+		kafkaConfig.BatchSize = cfg.Kafka.BatchSize
+		kafkaConfig.BatchWait = cfg.Kafka.BatchWait
+		kafkaSource = worker.NewKafkaIngestionSource(ingestEventUseCase, kafkaConfig, logger).
+			WithStandby(standbySwitch)
+		kafkaSource.Start(workerCtx)
+	}
+
+	// slugs and usernames nobody can claim, the built-in list plus the configured names
+	reservedNames := cfg.Names.ReservedNames()
+
+	createCommunityOpts := []application.CreateCommunityOption{
+		application.WithReservedCommunitySlugs(reservedNames),
+		application.WithCommunityEvents(eventBus),
+		application.WithCreatedCommunityCache(communityExistsCache), // events can be sent right after creation
+	}
 	if redisClient != nil {
-		calculateMomentumUseCase = calculateMomentumUseCase.WithLeaderboard(redisClient)
+		createCommunityOpts = append(createCommunityOpts, application.WithLeaderboardEntry(redisClient)) // listed before its first cycle
 	}
+	createCommunityUseCase := application.NewCreateCommunityUseCase(communityRepo, userRepo, logger, createCommunityOpts...)
 
-	createCommunityUseCase := application.NewCreateCommunityUseCase(
+	apiKeyRepo := postgres.NewAPIKeyRepository(pool)
+
+	momentumSettingsUseCase := application.NewMomentumSettingsUseCase(
+		momentumSettingsRepo,
 		communityRepo,
 		userRepo,
+		momentumConfig,
+		logger,
+		application.WithOrganizationAdmins(organizationRepo), // org admins manage org communities
+		application.WithEventWeightOverrides(eventWeightsRepo),
+	)
+
+	// status of background work, followed through the jobs endpoint
+	jobRepo := postgres.NewJobRepository(pool)
+	jobUseCase := application.NewJobUseCase(jobRepo, userRepo, logger)
+
+	// community owners restrict which domains subscriptions to their community deliver to
+	webhookAllowlistUseCase := application.NewWebhookAllowlistUseCase(webhookAllowlistRepo, communityRepo, userRepo, organizationRepo, logger)
+
+	// owners and admins inspect the recent events of a community
+	communityEventsUseCase := application.NewCommunityEventsUseCase(eventRepo, communityRepo, userRepo, organizationRepo, logger)
+
+	// admins move subscriptions between environments, with new secrets
+	webhookTransferUseCase := application.NewWebhookTransferUseCase(
+		webhookSubRepo,
+		communityRepo,
+		userRepo,
+		organizationRepo,
+		postgres.NewUnitOfWork(pool),
+		logger,
+		application.WithTransferAllowlist(webhookAllowlistUseCase),
+		application.WithTransferProxies(cfg.Webhook.AllowSubscriptionProxy),
+	)
+
+	// bulk imports of historical events, uploads are spooled to disk and run one at a time
+	var eventImportUseCase *application.EventImportUseCase
+	var eventImportWorker *worker.EventImportWorker
+	if cfg.Import.QueueSize > 0 {
+		eventImportUseCase = application.NewEventImportUseCase(
+			postgres.NewEventImportRepository(pool),
+			eventRepo,
+			communityRepo,
+			userRepo,
+			logger,
+			application.WithImportOrganizationAdmins(organizationRepo),
+			application.WithImportEventWeights(eventWeightsRepo),
+			application.WithImportJobs(jobRepo),
+		)
+		eventImportWorker = worker.NewEventImportWorker(eventImportUseCase, cfg.Import.QueueSize, logger)
+		eventImportWorker.Start(workerCtx)
+	}
+
+	organizationUseCase := application.NewOrganizationUseCase(
+		organizationRepo,
+		communityRepo,
+		userRepo,
+		apiKeyRepo,
+		logger,
+		application.WithReservedOrganizationSlugs(reservedNames),
+	)
+
+	invitationUseCase := application.NewInvitationUseCase(
+		postgres.NewCommunityInvitationRepository(pool),
+		communityMemberRepo,
+		communityRepo,
+		userRepo,
+		logger,
+		application.WithInvitationOrganizationAdmins(organizationRepo),
+		application.WithJoinEvents(ingestEventUseCase), // accepting emits a join event
+		application.WithInvitationBans(moderationRepo), // banned users can't rejoin
+	)
+
+	moderationUseCase := application.NewModerationUseCase(
+		moderationRepo,
+		communityMemberRepo,
+		communityRepo,
+		userRepo,
+		logger,
+		application.WithModerationOrganizationAdmins(organizationRepo),
+	)
+
+	// users' own limits on the webhooks they receive
+	notificationPrefsUseCase := application.NewNotificationPreferencesUseCase(notificationPrefsRepo, logger)
+
+	userLookupUseCase := application.NewUserLookupUseCase(userRepo, logger, application.WithReservedUsernames(reservedNames))
+	userProfileUseCase := application.NewUserProfileUseCase(userRepo, logger, application.WithReservedSignupUsernames(reservedNames))
+
+	statsOpts := []application.CommunityStatsOption{
+		application.WithMomentumHistoryReader(momentumHistoryRepo),
+	}
+	if redisClient != nil {
+		statsOpts = append(statsOpts,
+			application.WithUniqueContributors(redisClient),
+			application.WithMomentumPercentile(redisClient),
+		)
+	}
+	communityStatsUseCase := application.NewCommunityStatsUseCase(
+		communityRepo,
+		eventRepo,
+		userRepo,
+		communityAccess,
+		logger,
+		statsOpts...,
+	)
+
+	visibilityOpts := []application.CommunityVisibilityOption{
+		application.WithVisibilityOrganizationAdmins(organizationRepo),
+	}
+	if redisClient != nil {
+		visibilityOpts = append(visibilityOpts, application.WithVisibilityLeaderboard(redisClient))
+	}
+	communityVisibilityUseCase := application.NewCommunityVisibilityUseCase(
+		communityRepo,
+		userRepo,
+		logger,
+		visibilityOpts...,
+	)
+
+	detailsOpts := []application.CommunityDetailsOption{
+		application.WithDeactivationEvents(eventBus),
+	}
+	if redisClient != nil {
+		detailsOpts = append(detailsOpts, application.WithDeactivationLeaderboard(redisClient))
+	}
+	communityDetailsUseCase := application.NewCommunityDetailsUseCase(
+		communityRepo,
+		userRepo,
+		logger,
+		detailsOpts...,
+	)
+
+	usageUseCase := application.NewUsageUseCase(
+		usageCounter,
+		quotaEnforcer,
+		quotaRepo,
+		communityRepo,
+		organizationRepo,
+		userRepo,
+		logger,
+	)
+
+	apiKeyUseCase := application.NewAPIKeyUseCase(
+		apiKeyRepo,
+		userRepo,
 		logger,
 	)
 
 	// initialize http server
-	serverConfig := api.DefaultServerConfig()
-	if port := os.Getenv("PORT"); port != "" {
-		serverConfig.Port = ":" + port
+	serverConfig := api.ServerConfig{
+		Port:            ":" + cfg.Server.Port,
+		ReadTimeout:     cfg.Server.ReadTimeout,
+		WriteTimeout:    cfg.Server.WriteTimeout,
+		IdleTimeout:     cfg.Server.IdleTimeout,
+		ShutdownTimeout: cfg.Server.ShutdownTimeout,
+		DrainDelay:      cfg.Server.DrainDelay,
+		ReusePort:       cfg.Server.ReusePort,
 	}
 
-	server := api.NewServer(serverConfig, logger)
+	server := api.NewServer(serverConfig, logger).WithStandby(standbySwitch)
+
+	// request budget headers, counted per instance
+	var rateLimiter api.RequestRateLimiter
+	if cfg.RateLimit.Requests > 0 {
+		rateLimiter = application.NewRateLimiter(cfg.RateLimit.Requests, cfg.RateLimit.Window, domain.SystemClock)
+	}
+
+	// enforced rate limits, shared through redis when available. the policies are reloadable
+	rateLimitPolicies := api.NewRateLimitPolicies(rateLimitPoliciesOf(cfg.RateLimit))
+	memoryTokenBuckets := application.NewMemoryTokenBuckets(domain.SystemClock)
+	var rateLimitBuckets application.TokenBuckets = memoryTokenBuckets
+	if redisClient != nil {
+		rateLimitBuckets = cache.NewRedisTokenBuckets(redisClient, memoryTokenBuckets, logger)
+	}
+
+	var eventImportQueue api.EventImportQueue
+	if eventImportWorker != nil {
+		eventImportQueue = eventImportWorker
+	}
 
 	// register routes
 	api.RegisterRoutes(server.Echo(), &api.RouterConfig{
 		IngestEventUseCase:       ingestEventUseCase,
+		IngestEventGroupUseCase:  ingestEventGroupUseCase,
 		CalculateMomentumUseCase: calculateMomentumUseCase,
 		CreateCommunityUseCase:   createCommunityUseCase,
+		MomentumSettingsUseCase:  momentumSettingsUseCase,
+		RebuildLeaderboard:       rebuildLeaderboardUseCase,
+		AnomalyUseCase:           anomalyUseCase,
+		LeaderboardUseCase:       leaderboardUseCase,
+		CommunityPinUseCase:      communityPinUseCase,
+		FeedUseCase:              feedUseCase,
+		OrganizationUseCase:      organizationUseCase,
+		UsageUseCase:             usageUseCase,
+		InvitationUseCase:        invitationUseCase,
+		CommunityStatsUseCase:    communityStatsUseCase,
+		CommunityVisibility:      communityVisibilityUseCase,
+		CommunityDetails:         communityDetailsUseCase,
+		CommunitySummaries:       communitySummaries,
+		ModerationUseCase:        moderationUseCase,
+		NotificationPreferences:  notificationPrefsUseCase,
+		UserLookupUseCase:        userLookupUseCase,
+		UserProfileUseCase:       userProfileUseCase,
+		EventImportUseCase:       eventImportUseCase,
+		LiveHub:                  liveHub,
+		JobUseCase:               jobUseCase,
+		WebhookAllowlistUseCase:  webhookAllowlistUseCase,
+		WebhookTransferUseCase:   webhookTransferUseCase,
+		CommunityEventsUseCase:   communityEventsUseCase,
+		EventImportQueue:         eventImportQueue,
+		EventImportMaxBytes:      cfg.Import.MaxUploadBytes,
+		EventImportUploadTimeout: cfg.Import.UploadTimeout,
+		Meter:                    meter,
+		RateLimiter:              rateLimiter,
+		RateLimitBuckets:         rateLimitBuckets,
+		RateLimitPolicies:        rateLimitPolicies,
+		TrustForwardedFor:        cfg.RateLimit.TrustForwardedFor,
 		CommunityRepo:            communityRepo,
 		WebhookSubscriptionRepo:  webhookSubRepo,
+		WebhookDeliveryRepo:      webhookDeliveryRepo,
+		RejectedEventRepo:        rejectedEventRepo,
+		MomentumRunRepo:          momentumRunRepo,
+		CommunityCache:           communityExistsCache,
+		MomentumWorker:           momentumWorker,
+		Standby:                  standbySwitch,
+		AllowSubscriptionProxy:   cfg.Webhook.AllowSubscriptionProxy,
+		HealthMonitor:            healthMonitor,
 		JWTValidator:             jwtValidator,
+		APIKeyAuthenticator:      apiKeyUseCase,
 		Logger:                   logger,
 		Metrics:                  appMetrics,
 	})
 
-	// start background momentum worker
-	go runMomentumWorker(workerCtx, calculateMomentumUseCase, appMetrics, logger)
+	logStartupReport(logger, startupReport{
+		Config:            cfg,
+		Server:            serverConfig,
+		Ingestion:         ingestionWorkerConfig,
+		Webhook:           webhookWorkerConfig,
+		Momentum:          momentumConfig,
+		MomentumInterval:  cfg.Momentum.Interval,
+		RedisConnected:    redisClient != nil,
+		MigrationsApplied: migrationsApplied,
+		MigrationsTotal:   len(appliedVersions),
+	})
+
+	// reload log level, spike thresholds, momentum interval and rate limits on SIGHUP
+	configReloader := newReloader(configPath, cfg, logger, webhookWorker, rateLimitPolicies)
+	go configReloader.Run(workerCtx)
+
+	// drop expired entries from the in-memory caches, which grow with every community and user seen
+	go runCacheCleanup(workerCtx, 5*time.Minute, communityExistsCache, userExistsCache, memorySpikeCooldown, notificationPrefsRepo, eventWeightsRepo, memoryTokenBuckets)
+
+	// start background momentum worker, paused and resumed through the admin api
+	momentumWorker.WithIntervals(configReloader.Intervals())
+	momentumWorker.Start(workerCtx)
+
+	go healthMonitor.Run(workerCtx)
 
 	// start server in goroutine
 	go func() {
@@ -186,75 +757,118 @@ func run(logger *logging.Logger) error {
 
 	logger.Info("pulse shutting down")
 
-	// stop background workers
+	// stop taking requests before the workers behind them: fail readiness until load
+	// balancers notice, then wait for in-flight requests, so accepted events reach the
+	// ingestion buffer that's drained below
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), serverConfig.DrainDelay+serverConfig.ShutdownTimeout)
+	defer shutdownCancel()
+
+	server.Drain(shutdownCtx)
+	shutdownErr := server.Shutdown(shutdownCtx)
+	if shutdownErr != nil {
+		logger.Error("http server shutdown error", "error", shutdownErr.Error())
+	}
+
+	// websocket connections aren't waited for by the shutdown above, end them here
+	liveHub.Close()
+
+	// stop background workers. the ingestion and webhook workers keep draining their
+	// buffers until they're stopped below
 	workerCancel()
 
+	// stop feeding the buffer before it's drained, uncommitted batches are consumed again
+	if kafkaSource != nil {
+		kafkaSource.Stop()
+	}
+
 	// stop ingestion worker and drain buffer
 	ingestionWorker.Stop()
 
+	// a cycle in progress is cancelled, the next start runs one right away
+	momentumWorker.Stop()
+
+	// pending late events are dropped, the first momentum cycle after a restart counts them
+	lateEventWorker.Stop()
+
+	// the running import is interrupted and the queued ones failed, their uploaders retry
+	if eventImportWorker != nil {
+		eventImportWorker.Stop()
+	}
+
+	// stop detection before the webhook worker it notifies
+	if anomalyWorker != nil {
+		anomalyWorker.Stop()
+	}
+
+	if summaryRollupWorker != nil {
+		summaryRollupWorker.Stop()
+	}
+
+	if leaderboardResyncWorker != nil {
+		leaderboardResyncWorker.Stop()
+	}
+
+	eventBus.Stop()
+
 	// stop webhook worker and drain buffer
 	webhookWorker.Stop()
 
-	// graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
-	defer shutdownCancel()
+	// flush the last metering period, after the workers above metered their drained work
+	meteringWorker.Stop()
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Error("http server shutdown error", "error", err.Error())
-		return err
+	if shutdownErr != nil {
+		return shutdownErr
 	}
 
 	logger.Info("pulse shutdown complete")
 	return nil
 }
 
-// runMomentumWorker runs the momentum calculation in the background
-// every momentumCalculationInterval until context is cancelled
-func runMomentumWorker(ctx context.Context, useCase *application.CalculateMomentumUseCase, appMetrics *metrics.Metrics, logger *logging.Logger) {
-	logger.Info("momentum worker started", "interval", momentumCalculationInterval.String())
+// meteringExporters builds the export hooks enabled in the config.
+func meteringExporters(cfg config.MeteringConfig, logger *logging.Logger) ([]application.MeteringOption, error) {
+	var opts []application.MeteringOption
 
-	ticker := time.NewTicker(momentumCalculationInterval)
-	defer ticker.Stop()
+	if cfg.CSVDir != "" {
+		exporter, err := billing.NewCSVExporter(cfg.CSVDir)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, application.WithMeteringExporter(exporter))
+	}
 
-	// run immediately on startup
-	runMomentumCalculation(ctx, useCase, appMetrics, logger)
+	if cfg.StripeAPIKey != "" {
+		customers, err := billing.LoadStripeCustomers(cfg.StripeCustomers)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, application.WithMeteringExporter(billing.NewStripeExporter(billing.StripeConfig{
+			APIKey:      cfg.StripeAPIKey,
+			EventPrefix: cfg.StripeEventPrefix,
+			Customers:   customers,
+		}, logger)))
+	}
+
+	return opts, nil
+}
+
+// expiringCache is an in-memory cache that needs expired entries removed.
+type expiringCache interface {
+	Cleanup()
+}
+
+// runCacheCleanup cleans up the caches every interval until context is cancelled.
+func runCacheCleanup(ctx context.Context, interval time.Duration, caches ...expiringCache) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			logger.Info("momentum worker stopping")
 			return
 		case <-ticker.C:
-			runMomentumCalculation(ctx, useCase, appMetrics, logger)
+			for _, c := range caches {
+				c.Cleanup()
+			}
 		}
 	}
 }
-
-// runMomentumCalculation executes a single momentum calculation cycle
-func runMomentumCalculation(ctx context.Context, useCase *application.CalculateMomentumUseCase, appMetrics *metrics.Metrics, logger *logging.Logger) {
-	start := time.Now()
-	result, err := useCase.ExecuteAll(ctx, application.CalculateAllInput{
-		Limit: 0, // process all communities
-	})
-	duration := time.Since(start)
-
-	// record metric regardless of success/failure
-	if appMetrics != nil {
-		appMetrics.RecordMomentumCalculation(duration.Seconds())
-	}
-
-	if err != nil {
-		logger.Error("momentum calculation failed",
-			"error", err.Error(),
-			"duration_ms", duration.Milliseconds(),
-		)
-		return
-	}
-
-	logger.Info("momentum calculation completed",
-		"processed", result.Processed,
-		"succeeded", result.Succeeded,
-		"failed", result.Failed,
-		"duration_ms", duration.Milliseconds(),
-	)
-}
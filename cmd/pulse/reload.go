@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/api"
+	"github.com/joacominatel/pulse/internal/infrastructure/config"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+	"github.com/joacominatel/pulse/internal/infrastructure/worker"
+)
+
+// reloader applies the runtime-tunable subset of the config without a restart.
+// only log level, spike thresholds, momentum interval and the enforced rate limits
+// are reloadable; everything else (database, redis, port, the request budget headers)
+// still needs a restart.
+type reloader struct {
+	configPath    string
+	logger        *logging.Logger
+	webhookWorker *worker.WebhookWorker
+	rateLimits    *api.RateLimitPolicies
+
+	// intervalCh feeds new momentum intervals to the momentum worker loop
+	intervalCh chan time.Duration
+
+	current config.Config
+}
+
+// newReloader creates a reloader seeded with the config the process booted with.
+func newReloader(configPath string, cfg *config.Config, logger *logging.Logger, webhookWorker *worker.WebhookWorker, rateLimits *api.RateLimitPolicies) *reloader {
+	return &reloader{
+		configPath:    configPath,
+		logger:        logger.WithComponent("reloader"),
+		webhookWorker: webhookWorker,
+		rateLimits:    rateLimits,
+		intervalCh:    make(chan time.Duration, 1),
+		current:       *cfg,
+	}
+}
+
+// Intervals returns the channel the momentum worker listens on for interval changes.
+func (r *reloader) Intervals() <-chan time.Duration {
+	return r.intervalCh
+}
+
+// Run reloads the config on every SIGHUP until the context is cancelled.
+func (r *reloader) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.Reload()
+		}
+	}
+}
+
+// Reload re-reads the config file and env vars and applies what changed.
+// an invalid config is rejected as a whole and the running settings are kept.
+func (r *reloader) Reload() {
+	r.logger.Info("reloading configuration", "config_path", r.configPath)
+
+	cfg, err := config.Load(r.configPath)
+	if err != nil {
+		r.logger.Error("config reload failed, keeping current settings", "error", err.Error())
+		return
+	}
+
+	if cfg.Log.Level != r.current.Log.Level {
+		// already validated by config.Load
+		level, _ := logging.ParseLevel(cfg.Log.Level)
+		r.logger.SetLevel(level)
+		r.logger.Info("log level changed", "from", r.current.Log.Level, "to", cfg.Log.Level)
+	}
+
+	if cfg.Momentum.SpikeThresholds() != r.current.Momentum.SpikeThresholds() {
+		r.webhookWorker.SetThresholds(cfg.Momentum.SpikeThresholds())
+		r.logger.Info("spike thresholds changed",
+			"absolute_threshold", cfg.Momentum.SpikeAbsoluteThreshold,
+			"growth_percentage", cfg.Momentum.SpikeGrowthPercentage,
+		)
+	}
+
+	if cfg.Momentum.Interval != r.current.Momentum.Interval {
+		// drop a pending value the worker hasn't picked up yet, the newest wins
+		select {
+		case <-r.intervalCh:
+		default:
+		}
+		r.intervalCh <- cfg.Momentum.Interval
+		r.logger.Info("momentum interval changed",
+			"from", r.current.Momentum.Interval.String(),
+			"to", cfg.Momentum.Interval.String(),
+		)
+	}
+
+	if cfg.RateLimit.Events != r.current.RateLimit.Events || cfg.RateLimit.API != r.current.RateLimit.API {
+		// applies from the next request, buckets keep their tokens
+		r.rateLimits.Set(rateLimitPoliciesOf(cfg.RateLimit))
+		r.logger.Info("rate limits changed",
+			"events_rate", cfg.RateLimit.Events.Rate,
+			"events_burst", cfg.RateLimit.Events.Burst,
+			"api_rate", cfg.RateLimit.API.Rate,
+			"api_burst", cfg.RateLimit.API.Burst,
+		)
+	}
+
+	r.current = *cfg
+	r.logger.Info("configuration reloaded")
+}
+
+// rateLimitPoliciesOf returns the enforced rate limit of each route group.
+func rateLimitPoliciesOf(cfg config.RateLimitConfig) map[string]application.TokenBucketPolicy {
+	return map[string]application.TokenBucketPolicy{
+		api.RateLimitGroupEvents: {Rate: cfg.Events.Rate, Burst: cfg.Events.Burst},
+		api.RateLimitGroupAPI:    {Rate: cfg.API.Rate, Burst: cfg.API.Burst},
+	}
+}
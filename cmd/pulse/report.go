@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log/slog"
+	"net/url"
+	"runtime"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/api"
+	"github.com/joacominatel/pulse/internal/infrastructure/config"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+	"github.com/joacominatel/pulse/internal/infrastructure/worker"
+)
+
+// startupReport is a snapshot of the effective runtime configuration.
+// logged once on boot so "what is this instance actually running" has an answer.
+type startupReport struct {
+	Config           *config.Config
+	Server           api.ServerConfig
+	Ingestion        worker.EventIngestionWorkerConfig
+	Webhook          worker.WebhookWorkerConfig
+	Momentum         application.MomentumConfig
+	MomentumInterval time.Duration
+	RedisConnected   bool
+
+	// MigrationsApplied is how many migrations this boot applied.
+	MigrationsApplied int
+
+	// MigrationsTotal is how many migrations are recorded in the database.
+	MigrationsTotal int
+}
+
+// logStartupReport logs the sanitized effective configuration.
+// secrets are redacted by the config LogValue implementations.
+func logStartupReport(logger *logging.Logger, r startupReport) {
+	logger.Info("effective configuration",
+		slog.String("go_version", runtime.Version()),
+		slog.Any("config", r.Config),
+		slog.Group("server",
+			slog.String("port", r.Server.Port),
+			slog.String("read_timeout", r.Server.ReadTimeout.String()),
+			slog.String("write_timeout", r.Server.WriteTimeout.String()),
+			slog.String("idle_timeout", r.Server.IdleTimeout.String()),
+			slog.String("shutdown_timeout", r.Server.ShutdownTimeout.String()),
+			slog.String("drain_delay", r.Server.DrainDelay.String()),
+			slog.Bool("reuse_port", r.Server.ReusePort),
+		),
+		slog.Group("ingestion_worker",
+			slog.Int("buffer_size", r.Ingestion.BufferSize),
+			slog.Int("batch_size", r.Ingestion.BatchSize),
+			slog.String("flush_interval", r.Ingestion.FlushInterval.String()),
+			slog.Int("worker_count", r.Ingestion.WorkerCount),
+			slog.String("max_queue_age", r.Ingestion.MaxQueueAge.String()),
+		),
+		slog.Group("webhook_worker",
+			slog.Int("buffer_size", r.Webhook.BufferSize),
+			slog.Int("worker_count", r.Webhook.WorkerCount),
+			slog.String("request_timeout", r.Webhook.RequestTimeout.String()),
+			slog.Float64("spike_absolute_threshold", r.Webhook.Thresholds.AbsoluteThreshold),
+			slog.Float64("spike_growth_percentage", r.Webhook.Thresholds.GrowthPercentage),
+			slog.String("digest_window", r.Webhook.DigestWindow.String()),
+			slog.Int("max_idle_conns", r.Webhook.MaxIdleConns),
+			slog.Int("max_idle_conns_per_host", r.Webhook.MaxIdleConnsPerHost),
+			slog.Int("max_conns_per_host", r.Webhook.MaxConnsPerHost),
+			slog.String("idle_conn_timeout", r.Webhook.IdleConnTimeout.String()),
+			slog.Int("tls_session_cache_size", r.Webhook.TLSSessionCacheSize),
+			slog.String("proxy", webhookProxy(r.Webhook.Proxy)),
+			slog.Bool("allow_subscription_proxy", r.Webhook.AllowSubscriptionProxy),
+		),
+		slog.Group("momentum",
+			slog.String("interval", r.MomentumInterval.String()),
+			slog.String("time_window", r.Momentum.TimeWindow.String()),
+			slog.Float64("decay_factor", r.Momentum.DecayFactor),
+		),
+		slog.Bool("redis_connected", r.RedisConnected),
+		slog.Group("migrations",
+			slog.Int("applied_this_boot", r.MigrationsApplied),
+			slog.Int("total_applied", r.MigrationsTotal),
+		),
+	)
+}
+
+// webhookProxy describes the webhook proxy with its password redacted.
+func webhookProxy(proxy *url.URL) string {
+	if proxy == nil {
+		return "environment"
+	}
+	return proxy.Redacted()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/config"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// waitFor calls connect until it succeeds, doubling the wait between attempts up to
+// cfg.RetryMax, and returns the last error once cfg.MaxWait has passed.
+// a zero MaxWait makes a single attempt.
+func waitFor(ctx context.Context, dependency string, cfg config.StartupConfig, logger *logging.Logger, connect func(context.Context) error) error {
+	deadline := time.Now().Add(cfg.MaxWait)
+	backoff := cfg.RetryInitial
+
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("dependency available",
+					"dependency", dependency,
+					"attempts", attempt,
+				)
+			}
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("%s unavailable after %d attempts: %w", dependency, attempt, err)
+		}
+		wait := min(backoff, remaining)
+
+		logger.Warn("dependency unavailable, retrying",
+			"dependency", dependency,
+			"attempt", attempt,
+			"retry_in", wait.String(),
+			"error", err.Error(),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s unavailable: %w", dependency, ctx.Err())
+		case <-time.After(wait):
+		}
+
+		backoff = min(backoff*2, cfg.RetryMax)
+	}
+}
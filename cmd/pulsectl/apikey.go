@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+func newCreateAPIKeyCmd(flags *globalFlags) *cobra.Command {
+	var (
+		username string
+		name     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create-api-key",
+		Short: "issue an api key that acts on behalf of a user",
+		Long: `issue an api key that acts on behalf of a user.
+send it in the X-API-Key header. the key is printed once and cannot be recovered.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&username, "user", "", "username of the key owner (required)")
+	cmd.Flags().StringVar(&name, "name", "", "label for the key, e.g. ingestion-service (required)")
+	_ = cmd.MarkFlagRequired("user")
+	_ = cmd.MarkFlagRequired("name")
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		useCase := application.NewAPIKeyUseCase(postgres.NewAPIKeyRepository(a.conn.Pool()), a.users, a.logger)
+
+		result, err := useCase.Create(ctx, application.CreateAPIKeyInput{
+			Username: username,
+			Name:     name,
+		})
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "id:   %s\n", result.ID)
+		fmt.Fprintf(out, "user: %s\n", result.UserID)
+		fmt.Fprintf(out, "name: %s\n", result.Name)
+		fmt.Fprintf(out, "key:  %s\n", result.Key)
+		fmt.Fprintln(out, "\nstore the key now, it won't be shown again")
+		return nil
+	})
+
+	return cmd
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/application"
+)
+
+func newRebuildLeaderboardCmd(flags *globalFlags) *cobra.Command {
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "rebuild-leaderboard",
+		Short: "truncate and repopulate the redis leaderboard from postgres",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.Flags().IntVar(&batchSize, "batch-size", 0, "communities read and staged per batch, 0 for the default")
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		// read from postgres directly, the cached repo would read the leaderboard itself
+		useCase := application.NewRebuildLeaderboardUseCase(a.communities, a.redis, a.logger)
+
+		result, err := useCase.Execute(ctx, application.RebuildLeaderboardInput{BatchSize: batchSize})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "rebuilt leaderboard with %d communities in %d batches (%s)\n",
+			result.Communities, result.Batches, result.Duration.Round(time.Millisecond))
+		return nil
+	}, withRedis())
+
+	return cmd
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/api"
+)
+
+// loadtestOptions configures a load test run.
+type loadtestOptions struct {
+	target      string
+	rps         int
+	duration    time.Duration
+	maxInFlight int
+	timeout     time.Duration
+	mix         string
+	communities []string
+	apiKey      string
+	token       string
+}
+
+func newLoadtestCmd() *cobra.Command {
+	opts := loadtestOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "drive POST /events at a fixed rate and report latency and drops",
+		Long: `send events to a running pulse server at a constant rate (open loop, so a slow
+server doesn't slow the generator down) and report latency percentiles, the
+drop rate and ingestion buffer saturation sampled from /metrics.
+
+communities default to the top 100 from GET /api/v1/communities.
+
+the server limits each caller on /events, 100 requests per second by default. raise
+PULSE_RATE_LIMIT_EVENTS_RATE and PULSE_RATE_LIMIT_EVENTS_BURST on the server, or set
+the rate to 0, before testing above it.`,
+		Example: "  pulsectl loadtest --rps 2000 --duration 60s\n" +
+			"  pulsectl loadtest --mix view=80,post=10,comment=10 --community <id>",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			return runLoadtest(ctx, cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.target, "target", "http://localhost:8080", "base url of the pulse server")
+	cmd.Flags().IntVar(&opts.rps, "rps", 500, "requests per second to send")
+	cmd.Flags().DurationVar(&opts.duration, "duration", 30*time.Second, "how long to send for")
+	cmd.Flags().IntVar(&opts.maxInFlight, "max-in-flight", 2000, "cap on concurrent requests, extra ticks count as client drops")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Second, "per-request timeout")
+	cmd.Flags().StringVar(&opts.mix, "mix", "view=60,reaction=15,comment=10,post=6,join=5,share=3,leave=1", "event type weights")
+	cmd.Flags().StringSliceVar(&opts.communities, "community", nil, "community ids to target (repeatable)")
+	cmd.Flags().StringVar(&opts.apiKey, "api-key", os.Getenv("PULSE_API_KEY"), "api key sent as X-API-Key")
+	cmd.Flags().StringVar(&opts.token, "token", "", "bearer token, alternative to --api-key")
+
+	return cmd
+}
+
+// eventMix picks event types by weight.
+type eventMix struct {
+	types []domain.EventType
+	cdf   []float64
+}
+
+// parseEventMix parses "view=60,post=10" into a weighted picker.
+func parseEventMix(s string) (*eventMix, error) {
+	mix := &eventMix{}
+	var total float64
+
+	for _, part := range strings.Split(s, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected type=weight", part)
+		}
+		eventType, err := domain.ParseEventType(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mix entry %q: %w", part, err)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight in mix entry %q", part)
+		}
+		total += w
+		mix.types = append(mix.types, eventType)
+		mix.cdf = append(mix.cdf, total)
+	}
+
+	if total == 0 {
+		return nil, errors.New("event mix has no weight")
+	}
+	for i := range mix.cdf {
+		mix.cdf[i] /= total
+	}
+	return mix, nil
+}
+
+func (m *eventMix) pick(r float64) domain.EventType {
+	i := sort.SearchFloat64s(m.cdf, r)
+	if i >= len(m.types) {
+		i = len(m.types) - 1
+	}
+	return m.types[i]
+}
+
+// loadtestStats collects results from concurrent requests.
+type loadtestStats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	statuses  map[int]int
+
+	sent          atomic.Int64
+	clientDropped atomic.Int64
+	transportErrs atomic.Int64
+}
+
+func (s *loadtestStats) record(status int, latency time.Duration) {
+	s.mu.Lock()
+	s.statuses[status]++
+	s.latencies = append(s.latencies, latency)
+	s.mu.Unlock()
+}
+
+// bufferSampler polls the server's ingestion buffer gauges.
+type bufferSampler struct {
+	samples []float64 // saturation ratios in [0, 1]
+}
+
+func runLoadtest(ctx context.Context, out io.Writer, opts loadtestOptions) error {
+	if opts.rps <= 0 || opts.duration <= 0 {
+		return errors.New("--rps and --duration must be positive")
+	}
+
+	mix, err := parseEventMix(opts.mix)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: opts.timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        opts.maxInFlight,
+			MaxIdleConnsPerHost: opts.maxInFlight,
+			IdleConnTimeout:     30 * time.Second,
+		},
+	}
+	target := strings.TrimRight(opts.target, "/")
+
+	communities := opts.communities
+	if len(communities) == 0 {
+		communities, err = fetchCommunityIDs(ctx, client, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "loadtest: %d rps for %s against %s (%d communities)\n",
+		opts.rps, opts.duration, target, len(communities))
+
+	stats := &loadtestStats{
+		latencies: make([]time.Duration, 0, opts.rps*int(opts.duration.Seconds()+1)),
+		statuses:  make(map[int]int),
+	}
+	sampler := &bufferSampler{}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	// sample buffer saturation once a second while the test runs
+	samplerDone := make(chan struct{})
+	go func() {
+		defer close(samplerDone)
+		sampler.run(runCtx, client, target)
+	}()
+
+	var (
+		wg        sync.WaitGroup
+		inFlight  = make(chan struct{}, opts.maxInFlight)
+		start     = time.Now()
+		attempted int64
+	)
+
+	// tick faster than the send rate and catch up to the schedule each tick,
+	// tickers can't fire every 500µs reliably but the schedule stays exact
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-runCtx.Done():
+			break loop
+		case <-ticker.C:
+		}
+
+		due := int64(time.Since(start).Seconds() * float64(opts.rps))
+		for ; attempted < due; attempted++ {
+			select {
+			case inFlight <- struct{}{}:
+			default:
+				// the server is too slow to keep max-in-flight requests moving
+				stats.clientDropped.Add(1)
+				continue
+			}
+
+			body, _ := json.Marshal(api.IngestEventRequest{
+				CommunityID: communities[rand.IntN(len(communities))],
+				EventType:   mix.pick(rand.Float64()).String(),
+				Metadata:    map[string]any{"source": "loadtest"},
+			})
+
+			stats.sent.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-inFlight }()
+				sendEvent(ctx, client, target, opts, body, stats)
+			}()
+		}
+	}
+
+	elapsed := time.Since(start)
+	wg.Wait()
+	<-samplerDone
+
+	printLoadtestReport(out, stats, sampler, elapsed)
+	return nil
+}
+
+func sendEvent(ctx context.Context, client *http.Client, target string, opts loadtestOptions, body []byte, stats *loadtestStats) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target+"/api/v1/events", bytes.NewReader(body))
+	if err != nil {
+		stats.transportErrs.Add(1)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setAuth(req, opts)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		stats.transportErrs.Add(1)
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	stats.record(resp.StatusCode, time.Since(start))
+}
+
+func setAuth(req *http.Request, opts loadtestOptions) {
+	if opts.apiKey != "" {
+		req.Header.Set(api.APIKeyHeader, opts.apiKey)
+	} else if opts.token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.token)
+	}
+}
+
+// fetchCommunityIDs loads the top communities to spread events across.
+func fetchCommunityIDs(ctx context.Context, client *http.Client, target string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target+"/api/v1/communities?limit=100", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("listing communities: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing communities: unexpected status %d", resp.StatusCode)
+	}
+
+	var list struct {
+		Communities []struct {
+			ID string `json:"id"`
+		} `json:"communities"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decoding communities: %w", err)
+	}
+	if len(list.Communities) == 0 {
+		return nil, errors.New("no communities found, run `pulsectl seed` or pass --community")
+	}
+
+	ids := make([]string, len(list.Communities))
+	for i, c := range list.Communities {
+		ids[i] = c.ID
+	}
+	return ids, nil
+}
+
+// run samples /metrics every second until ctx is done.
+// a server without metrics just yields no samples.
+func (b *bufferSampler) run(ctx context.Context, client *http.Client, target string) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if ratio, ok := sampleBufferSaturation(ctx, client, target); ok {
+				b.samples = append(b.samples, ratio)
+			}
+		}
+	}
+}
+
+// sampleBufferSaturation reads pulse_buffer_size / pulse_buffer_capacity from /metrics.
+func sampleBufferSaturation(ctx context.Context, client *http.Client, target string) (float64, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target+"/metrics", nil)
+	if err != nil {
+		return 0, false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+
+	var size, capacity float64
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "pulse_buffer_size":
+			size, _ = strconv.ParseFloat(value, 64)
+		case "pulse_buffer_capacity":
+			capacity, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+
+	if capacity == 0 {
+		return 0, false
+	}
+	return size / capacity, true
+}
+
+func printLoadtestReport(out io.Writer, stats *loadtestStats, sampler *bufferSampler, elapsed time.Duration) {
+	latencies := stats.latencies
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	sent := stats.sent.Load()
+	clientDropped := stats.clientDropped.Load()
+	transportErrs := stats.transportErrs.Load()
+	attempted := sent + clientDropped
+
+	var accepted, overloaded, limited, otherErrs int
+	for status, n := range stats.statuses {
+		switch {
+		case status >= 200 && status < 300:
+			accepted += n
+		case status == http.StatusServiceUnavailable:
+			overloaded += n
+		case status == http.StatusTooManyRequests:
+			limited += n
+		default:
+			otherErrs += n
+		}
+	}
+
+	dropped := int64(overloaded) + clientDropped + transportErrs
+	dropRate := 0.0
+	if attempted > 0 {
+		dropRate = float64(dropped) / float64(attempted)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "requests:   %d sent, %d accepted, achieved %.0f rps\n",
+		sent, accepted, float64(sent)/elapsed.Seconds())
+	fmt.Fprintf(out, "drops:      %.2f%% (%d overloaded/503, %d client-side, %d transport errors)\n",
+		dropRate*100, overloaded, clientDropped, transportErrs)
+	if limited > 0 {
+		fmt.Fprintf(out, "limited:    %d rate limited or over quota/429, see PULSE_RATE_LIMIT_EVENTS_RATE\n", limited)
+	}
+	if otherErrs > 0 {
+		fmt.Fprintf(out, "errors:     %d non-503 error responses %v\n", otherErrs, stats.statuses)
+	}
+	if len(latencies) > 0 {
+		fmt.Fprintf(out, "latency:    p50 %s  p95 %s  p99 %s  max %s\n",
+			percentile(latencies, 0.50), percentile(latencies, 0.95),
+			percentile(latencies, 0.99), latencies[len(latencies)-1])
+	}
+	if len(sampler.samples) > 0 {
+		var sum, peak float64
+		for _, s := range sampler.samples {
+			sum += s
+			peak = max(peak, s)
+		}
+		fmt.Fprintf(out, "buffer:     avg %.1f%%  peak %.1f%% saturated (%d samples)\n",
+			sum/float64(len(sampler.samples))*100, peak*100, len(sampler.samples))
+	} else {
+		fmt.Fprintln(out, "buffer:     n/a (no pulse_buffer_* metrics at /metrics)")
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	idx = max(0, min(idx, len(sorted)-1))
+	return sorted[idx].Round(10 * time.Microsecond)
+}
@@ -0,0 +1,14 @@
+// pulsectl is the operator CLI for pulse.
+// it talks to the same database and cache as the server and goes through
+// the application layer, so maintenance doesn't need hand-written SQL.
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/database"
+)
+
+func newMigrateCmd(flags *globalFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "apply pending database migrations",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		applied, err := database.NewMigrator(a.conn, a.logger).Run(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "applied %d migration(s)\n", applied)
+		return nil
+	})
+
+	cmd.AddCommand(newMigrateStatusCmd(flags))
+	return cmd
+}
+
+func newMigrateStatusCmd(flags *globalFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show which embedded migrations are applied",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		migrator := database.NewMigrator(a.conn, a.logger)
+
+		embedded, err := migrator.EmbeddedMigrations()
+		if err != nil {
+			return err
+		}
+		applied, err := migrator.GetAppliedMigrations(ctx)
+		if err != nil {
+			return err
+		}
+
+		appliedSet := make(map[string]bool, len(applied))
+		for _, v := range applied {
+			appliedSet[v] = true
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tDESCRIPTION\tSTATUS")
+		for _, m := range embedded {
+			status := "pending"
+			if appliedSet[m.Version] {
+				status = "applied"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", m.Version, m.Description, status)
+		}
+		return w.Flush()
+	})
+
+	return cmd
+}
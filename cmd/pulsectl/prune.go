@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/application"
+)
+
+func newPruneEventsCmd(flags *globalFlags) *cobra.Command {
+	var (
+		olderThan time.Duration
+		batchSize int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune-events",
+		Short: "delete activity events older than a retention period",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 30*24*time.Hour, "retention period, must cover the longest momentum window")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 0, "rows deleted per statement, 0 for the default")
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		useCase := application.NewPruneEventsUseCase(a.events, a.logger)
+
+		result, err := useCase.Execute(ctx, application.PruneEventsInput{
+			OlderThan: olderThan,
+			BatchSize: batchSize,
+		})
+		if result != nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "deleted %d events created before %s\n",
+				result.Deleted, result.Cutoff.Format(time.RFC3339))
+		}
+		return err
+	})
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+func newRecalcMomentumCmd(flags *globalFlags) *cobra.Command {
+	var (
+		communityID string
+		limit       int
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "recalc-momentum",
+		Short: "recalculate momentum for one or all communities",
+		Long: `recalculate momentum using the same use case as the background worker.
+the momentum settings and spike thresholds come from the config file and
+per-community overrides are applied. webhooks are not sent, run this from
+the server if spike notifications are needed.
+
+with --dry-run scores are printed but not stored, and spikes that would fire
+are listed, for checking a config change before it goes live.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&communityID, "community", "", "community id, all active communities if empty")
+	cmd.Flags().IntVar(&limit, "limit", 0, "max communities when recalculating all, 0 for the default")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "compute and print scores without writing them")
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		opts := []application.CalculateMomentumOption{
+			application.WithSettings(postgres.NewCommunityMomentumSettingsRepository(a.conn.Pool())),
+			application.WithRunLog(postgres.NewMomentumRunRepository(a.conn.Pool())),
+			application.WithRankingSnapshots(postgres.NewRankingSnapshotRepository(a.conn.Pool())),
+			application.WithSpikeThresholds(configThresholds(a.cfg.Momentum.SpikeThresholds())),
+			application.WithMomentumDecay(a.cfg.Momentum.DecayHalfLife),
+			application.WithCycleRetry(a.cfg.Momentum.RetryAttempts, a.cfg.Momentum.RetryBackoff),
+			application.WithStaleAfter(a.cfg.Momentum.StaleAfterCycles),
+		}
+
+		// keep the cached leaderboard in step with postgres when redis is configured
+		if a.redis != nil {
+			opts = append(opts,
+				application.WithLeaderboard(a.redis),
+				application.WithLeaderboardSnapshots(a.redis),
+			)
+		}
+
+		useCase := application.NewCalculateMomentumUseCase(
+			a.events,
+			a.communities,
+			application.DefaultMomentumConfig(),
+			a.logger,
+			opts...,
+		)
+
+		out := cmd.OutOrStdout()
+
+		if communityID != "" {
+			result, err := useCase.Execute(ctx, application.CalculateMomentumInput{CommunityID: communityID, DryRun: dryRun})
+			if err != nil {
+				return err
+			}
+			printMomentumResult(out, result)
+			return nil
+		}
+
+		result, err := useCase.ExecuteAll(ctx, application.CalculateAllInput{
+			Limit:       limit,
+			DryRun:      dryRun,
+			TriggeredBy: domain.MomentumRunCLI,
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range result.Results {
+			printMomentumResult(out, r)
+		}
+		fmt.Fprintf(out, "processed %d, succeeded %d, failed %d\n", result.Processed, result.Succeeded, result.Failed)
+		for _, f := range result.Failures {
+			kind := "permanent"
+			if f.Transient {
+				kind = "transient"
+			}
+			fmt.Fprintf(out, "  %s (%s): %s\n", f.CommunityID, kind, f.Reason)
+		}
+		if result.Failed > 0 {
+			return fmt.Errorf("%d communities failed, rerun with -v for details", result.Failed)
+		}
+		return nil
+	}, withOptionalRedis())
+
+	return cmd
+}
+
+func printMomentumResult(out io.Writer, result *application.CalculateMomentumOutput) {
+	fmt.Fprintf(out, "%s: %.4f -> %.4f (%d events in %s, decay %.2f)",
+		result.CommunityID, result.OldMomentum, result.NewMomentum, result.EventCount, result.TimeWindow, result.DecayFactor)
+	if result.Spike != nil {
+		fmt.Fprintf(out, " spike %+.0f%%", result.Spike.PercentChange*100)
+	}
+	fmt.Fprintln(out)
+}
+
+// configThresholds reports the spike thresholds loaded from the config file,
+// which the server reads from its webhook worker instead.
+type configThresholds domain.MomentumSpikeThresholds
+
+func (t configThresholds) Thresholds() domain.MomentumSpikeThresholds {
+	return domain.MomentumSpikeThresholds(t)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/archive"
+)
+
+func newReplayCmd(flags *globalFlags) *cobra.Command {
+	var (
+		from       string
+		startAfter string
+		batchSize  int
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "re-ingest archived NDJSON events, keeping their original ids and timestamps",
+		Long: `replay reads archived activity events (NDJSON, optionally gzipped) and writes
+them through the event repository in batches. files are replayed in lexical order.
+
+  pulsectl replay --from s3://pulse-archive/2024-05/
+  pulsectl replay --from ./archive --start-after ./archive/2024-05-03.ndjson.gz
+
+s3 credentials and region are read from the standard AWS environment and config files.
+run recalc-momentum afterwards to refresh scores.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "archive location: s3://bucket/prefix/, a directory or a file")
+	cmd.Flags().StringVar(&startAfter, "start-after", "", "skip files up to and including this one, to resume a replay")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 0, "events per batch insert, 0 for the default")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "read and validate the archive without writing")
+	_ = cmd.MarkFlagRequired("from")
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		source, err := archive.Open(ctx, from)
+		if err != nil {
+			return fmt.Errorf("opening archive: %w", err)
+		}
+
+		useCase := application.NewReplayEventsUseCase(a.events, a.logger)
+
+		result, err := useCase.Execute(ctx, application.ReplayEventsInput{
+			Archive:    source,
+			StartAfter: startAfter,
+			BatchSize:  batchSize,
+			DryRun:     dryRun,
+		})
+		if result != nil {
+			verb := "replayed"
+			if dryRun {
+				verb = "validated"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %d events from %d files\n", verb, result.Events, result.Files)
+			if err != nil && result.LastFile != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "resume with --start-after %s\n", result.LastFile)
+			}
+		}
+		return err
+	})
+
+	return cmd
+}
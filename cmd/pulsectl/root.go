@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/cache"
+	"github.com/joacominatel/pulse/internal/infrastructure/config"
+	"github.com/joacominatel/pulse/internal/infrastructure/database"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+// globalFlags are shared by every subcommand.
+type globalFlags struct {
+	configPath string
+	verbose    bool
+}
+
+// newRootCmd builds the pulsectl command tree.
+func newRootCmd() *cobra.Command {
+	flags := &globalFlags{}
+
+	root := &cobra.Command{
+		Use:          "pulsectl",
+		Short:        "operator CLI for pulse",
+		SilenceUsage: true,
+	}
+
+	root.PersistentFlags().StringVar(&flags.configPath, "config", os.Getenv(config.ConfigPathEnv),
+		"YAML or TOML config file, overridden by env vars")
+	root.PersistentFlags().BoolVarP(&flags.verbose, "verbose", "v", false, "log at info level instead of warn")
+
+	root.AddCommand(
+		newMigrateCmd(flags),
+		newDoctorCmd(flags),
+		newSeedCmd(flags),
+		newRecalcMomentumCmd(flags),
+		newRebuildLeaderboardCmd(flags),
+		newPruneEventsCmd(flags),
+		newReplayCmd(flags),
+		newCreateAPIKeyCmd(flags),
+		newListSubscriptionsCmd(flags),
+		newLoadtestCmd(),
+	)
+
+	return root
+}
+
+// app holds the infrastructure a command needs.
+// built per invocation and closed when the command returns.
+type app struct {
+	cfg    *config.Config
+	conn   *database.Connection
+	redis  *cache.RedisClient
+	logger *logging.Logger
+
+	users       *postgres.UserRepository
+	communities *postgres.CommunityRepository
+	events      *postgres.ActivityEventRepository
+}
+
+// redisMode says whether a command needs redis.
+type redisMode int
+
+const (
+	redisNone     redisMode = iota
+	redisOptional           // connect if REDIS_URL is set, continue without it otherwise
+	redisRequired           // fail if redis is not configured or unreachable
+)
+
+// appOption customizes the infrastructure built for a command.
+type appOption func(*appOptions)
+
+type appOptions struct {
+	redis redisMode
+}
+
+// withRedis makes the command fail unless redis is reachable.
+func withRedis() appOption {
+	return func(o *appOptions) { o.redis = redisRequired }
+}
+
+// withOptionalRedis connects to redis when it's configured.
+func withOptionalRedis() appOption {
+	return func(o *appOptions) { o.redis = redisOptional }
+}
+
+// runWithApp wires up config, database and (optionally) redis, then calls fn.
+// the context is cancelled on SIGINT/SIGTERM so long commands stop cleanly.
+func runWithApp(flags *globalFlags, fn func(ctx context.Context, a *app) error, opts ...appOption) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		var o appOptions
+		for _, opt := range opts {
+			opt(&o)
+		}
+
+		level := slog.LevelWarn
+		if flags.verbose {
+			level = slog.LevelInfo
+		}
+		logger := logging.NewWithWriter(os.Stderr, level)
+
+		cfg, err := config.Load(flags.configPath)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		conn, err := database.New(&cfg.Database, logger)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		pool := conn.Pool()
+		a := &app{
+			cfg:         cfg,
+			conn:        conn,
+			logger:      logger,
+			users:       postgres.NewUserRepository(pool),
+			communities: postgres.NewCommunityRepository(pool),
+			events:      postgres.NewActivityEventRepository(pool),
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		if o.redis != redisNone {
+			redisClient, err := connectRedis(ctx, cfg, logger, o.redis)
+			if err != nil {
+				return err
+			}
+			if redisClient != nil {
+				defer func() { _ = redisClient.Close() }()
+				a.redis = redisClient
+			}
+		}
+
+		return fn(ctx, a)
+	}
+}
+
+// connectRedis returns a connected client, or nil when redis is optional and unavailable.
+func connectRedis(ctx context.Context, cfg *config.Config, logger *logging.Logger, mode redisMode) (*cache.RedisClient, error) {
+	if cfg.Redis.URL == "" {
+		if mode == redisRequired {
+			return nil, fmt.Errorf("REDIS_URL is not set")
+		}
+		return nil, nil
+	}
+
+	redisClient, err := cache.NewRedisClient(cache.RedisConfig{URL: cfg.Redis.URL}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := redisClient.Connect(ctx); err != nil {
+		if mode == redisRequired {
+			return nil, fmt.Errorf("connecting to redis: %w", err)
+		}
+		logger.Warn("redis unavailable, continuing without it", "error", err.Error())
+		return nil, nil
+	}
+
+	return redisClient, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+func newListSubscriptionsCmd(flags *globalFlags) *cobra.Command {
+	var (
+		communityID string
+		username    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list-subscriptions",
+		Short: "list webhook subscriptions for a community or user",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&communityID, "community", "", "community id, lists active subscriptions")
+	cmd.Flags().StringVar(&username, "user", "", "username, lists all of the user's subscriptions")
+	cmd.MarkFlagsOneRequired("community", "user")
+	cmd.MarkFlagsMutuallyExclusive("community", "user")
+
+	cmd.RunE = runWithApp(flags, func(ctx context.Context, a *app) error {
+		repo := postgres.NewWebhookSubscriptionRepository(a.conn.Pool())
+
+		var (
+			subs []*domain.WebhookSubscription
+			err  error
+		)
+
+		switch {
+		case communityID != "":
+			id, parseErr := domain.ParseCommunityID(communityID)
+			if parseErr != nil {
+				return parseErr
+			}
+			subs, err = repo.FindByCommunity(ctx, id)
+		default:
+			name, parseErr := domain.ParseUsername(username)
+			if parseErr != nil {
+				return parseErr
+			}
+			user, findErr := a.users.FindByUsername(ctx, name)
+			if errors.Is(findErr, domain.ErrNotFound) {
+				return fmt.Errorf("user %q not found", username)
+			}
+			if findErr != nil {
+				return findErr
+			}
+			subs, err = repo.FindByUser(ctx, user.ID())
+		}
+		if err != nil {
+			return err
+		}
+
+		// secrets are never printed
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCOMMUNITY\tUSER\tTARGET URL\tVERSION\tMODE\tACTIVE\tCREATED")
+		for _, sub := range subs {
+			community := sub.CommunityID().String()
+			if sub.IsGlobal() {
+				community = "all"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+				sub.ID().String(),
+				community,
+				sub.UserID().String(),
+				sub.TargetURL(),
+				sub.PayloadVersion().String(),
+				sub.DeliveryMode().String(),
+				sub.IsActive(),
+				sub.CreatedAt().Format(time.RFC3339),
+			)
+		}
+		return w.Flush()
+	})
+
+	return cmd
+}
@@ -0,0 +1,292 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// AnomalyConfig contains parameters for anomaly detection.
+type AnomalyConfig struct {
+	// Window is the recent period whose ingest rate is checked.
+	Window time.Duration
+
+	// Baseline is the period before the window the rate is compared to.
+	Baseline time.Duration
+
+	// Thresholds decide how far above the baseline a window has to be to flag it.
+	Thresholds domain.AnomalyThresholds
+}
+
+// DefaultAnomalyConfig returns sensible defaults.
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		Window:     15 * time.Minute,
+		Baseline:   24 * time.Hour,
+		Thresholds: domain.DefaultAnomalyThresholds(),
+	}
+}
+
+// IngestRateReader counts recent events against their baseline, per community and per user.
+// implemented by the postgres activity event repository.
+type IngestRateReader interface {
+	CommunityIngestRates(ctx context.Context, baselineStart, windowStart time.Time, minEvents int64) ([]domain.IngestRate, error)
+	UserIngestRates(ctx context.Context, baselineStart, windowStart time.Time, minEvents int64) ([]domain.IngestRate, error)
+}
+
+// AnomalyNotifier tells a community's webhook subscribers about a new flag.
+type AnomalyNotifier interface {
+	NotifyAnomaly(ctx context.Context, flag *domain.AnomalyFlag) error
+}
+
+// AnomalyUseCase flags communities and users whose ingest rate jumps far above baseline,
+// and lets admins review the flags. flagged events stay out of momentum while pending.
+type AnomalyUseCase struct {
+	rates    IngestRateReader
+	flagRepo domain.AnomalyFlagRepository
+	notifier AnomalyNotifier
+	config   AnomalyConfig
+	clock    domain.Clock
+	logger   *logging.Logger
+}
+
+// AnomalyOption configures an AnomalyUseCase at construction.
+type AnomalyOption func(*AnomalyUseCase)
+
+// WithAnomalyNotifier sends an anomaly_flagged webhook for every new flag.
+func WithAnomalyNotifier(n AnomalyNotifier) AnomalyOption {
+	return func(uc *AnomalyUseCase) {
+		uc.notifier = n
+	}
+}
+
+// WithAnomalyClock sets the clock windows are measured from. for tests.
+func WithAnomalyClock(clock domain.Clock) AnomalyOption {
+	return func(uc *AnomalyUseCase) {
+		uc.clock = clock
+	}
+}
+
+// NewAnomalyUseCase creates a new AnomalyUseCase.
+func NewAnomalyUseCase(
+	rates IngestRateReader,
+	flagRepo domain.AnomalyFlagRepository,
+	config AnomalyConfig,
+	logger *logging.Logger,
+	opts ...AnomalyOption,
+) *AnomalyUseCase {
+	uc := &AnomalyUseCase{
+		rates:    rates,
+		flagRepo: flagRepo,
+		config:   config,
+		clock:    domain.SystemClock,
+		logger:   logger.WithComponent("anomalies"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// DetectAnomaliesOutput reports a detection run.
+type DetectAnomaliesOutput struct {
+	// Checked is the communities and users with enough events in the window to be checked.
+	Checked int
+	Flagged int
+}
+
+// AnomalyFlagOutput describes a flag.
+type AnomalyFlagOutput struct {
+	ID          string
+	CommunityID string
+	UserID      *string
+	Status      domain.AnomalyStatus
+	Observed    int64
+	Expected    float64
+	Ratio       float64
+	WindowStart time.Time
+	DetectedAt  time.Time
+	ReviewedAt  *time.Time
+	ReviewedBy  string
+}
+
+// Detect compares the last window's ingest rate with the baseline and flags the outliers.
+// subjects that already have a pending flag are skipped. a failure on one subject
+// doesn't stop the others, so the run only fails if the rates can't be read.
+func (uc *AnomalyUseCase) Detect(ctx context.Context) (*DetectAnomaliesOutput, error) {
+	now := uc.clock.Now()
+	windowStart := now.Add(-uc.config.Window)
+	baselineStart := windowStart.Add(-uc.config.Baseline)
+	windows := float64(uc.config.Baseline) / float64(uc.config.Window)
+	minEvents := uc.config.Thresholds.MinEvents
+
+	communityRates, err := uc.rates.CommunityIngestRates(ctx, baselineStart, windowStart, minEvents)
+	if err != nil {
+		uc.logger.Error("anomaly detection failed: reading community rates",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("reading community ingest rates: %w", err)
+	}
+	userRates, err := uc.rates.UserIngestRates(ctx, baselineStart, windowStart, minEvents)
+	if err != nil {
+		uc.logger.Error("anomaly detection failed: reading user rates",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("reading user ingest rates: %w", err)
+	}
+
+	output := &DetectAnomaliesOutput{}
+	for _, rate := range append(communityRates, userRates...) {
+		output.Checked++
+
+		expected := rate.Expected(windows)
+		if !uc.config.Thresholds.IsAnomalous(rate.Observed, expected) {
+			continue
+		}
+		if uc.flag(ctx, rate, expected, windowStart) {
+			output.Flagged++
+		}
+	}
+
+	uc.logger.Info("anomaly detection completed",
+		"checked", output.Checked,
+		"flagged", output.Flagged,
+		"window", uc.config.Window.String(),
+	)
+	return output, nil
+}
+
+// flag stores a new pending flag for the rate and notifies subscribers.
+// reports whether a flag was created.
+func (uc *AnomalyUseCase) flag(ctx context.Context, rate domain.IngestRate, expected float64, windowStart time.Time) bool {
+	ctx = logging.ContextWithCommunityID(ctx, rate.CommunityID.String())
+	subject := "community"
+	if rate.UserID != nil {
+		// runs in the background, so user_id is free for the flagged user
+		ctx = logging.ContextWithUserID(ctx, rate.UserID.String())
+		subject = "user"
+	}
+	log := uc.logger.WithContext(ctx)
+
+	_, err := uc.flagRepo.FindPending(ctx, rate.CommunityID, rate.UserID)
+	if err == nil {
+		return false
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		log.Warn("pending anomaly lookup failed",
+			"error", err.Error(),
+		)
+		return false
+	}
+
+	flag, err := domain.NewAnomalyFlag(uc.clock, rate, expected, windowStart)
+	if err != nil {
+		log.Warn("anomaly flag rejected",
+			"error", err.Error(),
+		)
+		return false
+	}
+	if err := uc.flagRepo.Save(ctx, flag); err != nil {
+		// another instance may have flagged it first, the unique index keeps one pending flag
+		log.Warn("anomaly flag not saved",
+			"error", err.Error(),
+		)
+		return false
+	}
+
+	log.Warn("anomalous ingest rate, events quarantined",
+		"anomaly_id", flag.ID().String(),
+		"subject", subject,
+		"observed", rate.Observed,
+		"expected", expected,
+		"ratio", flag.Ratio(),
+	)
+
+	if uc.notifier != nil {
+		if err := uc.notifier.NotifyAnomaly(ctx, flag); err != nil {
+			log.Warn("anomaly notification failed",
+				"anomaly_id", flag.ID().String(),
+				"error", err.Error(),
+			)
+		}
+	}
+	return true
+}
+
+// List returns flags with the given status, newest first.
+func (uc *AnomalyUseCase) List(ctx context.Context, status domain.AnomalyStatus, limit, offset int) ([]AnomalyFlagOutput, error) {
+	flags, err := uc.flagRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing anomaly flags: %w", err)
+	}
+
+	out := make([]AnomalyFlagOutput, len(flags))
+	for i, f := range flags {
+		out[i] = toAnomalyFlagOutput(f)
+	}
+	return out, nil
+}
+
+// Review confirms or dismisses a pending flag. confirming keeps the quarantined events
+// out of momentum for good, dismissing lets them count again from the next cycle.
+func (uc *AnomalyUseCase) Review(ctx context.Context, id string, confirm bool, reviewer string) (*AnomalyFlagOutput, error) {
+	flagID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anomaly id: %w", err)
+	}
+
+	flag, err := uc.flagRepo.FindByID(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = logging.ContextWithCommunityID(ctx, flag.CommunityID().String())
+
+	if confirm {
+		err = flag.Confirm(uc.clock, reviewer)
+	} else {
+		err = flag.Dismiss(uc.clock, reviewer)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.flagRepo.Save(ctx, flag); err != nil {
+		return nil, fmt.Errorf("saving anomaly flag: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("anomaly flag reviewed",
+		"anomaly_id", flag.ID().String(),
+		"status", flag.Status().String(),
+		"reviewed_by", reviewer,
+	)
+
+	out := toAnomalyFlagOutput(flag)
+	return &out, nil
+}
+
+func toAnomalyFlagOutput(f *domain.AnomalyFlag) AnomalyFlagOutput {
+	out := AnomalyFlagOutput{
+		ID:          f.ID().String(),
+		CommunityID: f.CommunityID().String(),
+		Status:      f.Status(),
+		Observed:    f.Observed(),
+		Expected:    f.Expected(),
+		Ratio:       f.Ratio(),
+		WindowStart: f.WindowStart(),
+		DetectedAt:  f.DetectedAt(),
+		ReviewedAt:  f.ReviewedAt(),
+		ReviewedBy:  f.ReviewedBy(),
+	}
+	if u := f.UserID(); u != nil {
+		s := u.String()
+		out.UserID = &s
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrAPIKeyOwnerNotFound is returned when creating a key for a user that doesn't exist.
+var ErrAPIKeyOwnerNotFound = errors.New("api key owner not found")
+
+// APIKeyUseCase issues api keys and resolves them back to users.
+type APIKeyUseCase struct {
+	apiKeyRepo domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	clock      domain.Clock
+	logger     *logging.Logger
+}
+
+// NewAPIKeyUseCase creates a new APIKeyUseCase.
+func NewAPIKeyUseCase(
+	apiKeyRepo domain.APIKeyRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		clock:      domain.SystemClock,
+		logger:     logger.WithComponent("api_keys"),
+	}
+}
+
+// CreateAPIKeyInput contains the data needed to issue a key.
+type CreateAPIKeyInput struct {
+	// Username of the user the key acts on behalf of
+	Username string
+
+	// Name is a human label, e.g. "ingestion-service"
+	Name string
+}
+
+// CreateAPIKeyOutput contains the issued key.
+// Key is the only time the plaintext is available.
+type CreateAPIKeyOutput struct {
+	ID     string
+	Name   string
+	UserID string
+	Key    string
+}
+
+// Create issues a new api key for a user.
+func (uc *APIKeyUseCase) Create(ctx context.Context, input CreateAPIKeyInput) (*CreateAPIKeyOutput, error) {
+	username, err := domain.ParseUsername(input.Username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username: %w", err)
+	}
+
+	user, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrAPIKeyOwnerNotFound
+		}
+		return nil, fmt.Errorf("looking up user: %w", err)
+	}
+
+	key, plaintext, err := domain.NewAPIKey(uc.clock, user.ID(), input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.apiKeyRepo.Save(ctx, key); err != nil {
+		uc.logger.Error("api key save failed",
+			"user_id", user.ID().String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving api key: %w", err)
+	}
+
+	uc.logger.Info("api key created",
+		"api_key_id", key.ID().String(),
+		"user_id", user.ID().String(),
+		"name", key.Name(),
+	)
+
+	return &CreateAPIKeyOutput{
+		ID:     key.ID().String(),
+		Name:   key.Name(),
+		UserID: user.ID().String(),
+		Key:    plaintext,
+	}, nil
+}
+
+// APIKeyPrincipal is who a key acts as.
+type APIKeyPrincipal struct {
+	User *domain.User
+
+	// OrganizationID is set for organization keys, which may only touch that organization.
+	OrganizationID *domain.OrganizationID
+}
+
+// Authenticate resolves a plaintext key to the user it belongs to.
+// returns domain.ErrAPIKeyInvalid for unknown keys and domain.ErrAPIKeyRevoked for revoked ones.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, plaintext string) (*APIKeyPrincipal, error) {
+	key, err := uc.apiKeyRepo.FindByHash(ctx, domain.HashAPIKey(plaintext))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrAPIKeyInvalid
+		}
+		return nil, fmt.Errorf("looking up api key: %w", err)
+	}
+
+	if key.IsRevoked() {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, key.UserID())
+	if err != nil {
+		return nil, fmt.Errorf("looking up api key owner: %w", err)
+	}
+
+	return &APIKeyPrincipal{User: user, OrganizationID: key.OrganizationID()}, nil
+}
@@ -2,23 +2,17 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 )
 
-// TimeProvider abstracts time acquisition for testability.
-// inject a custom implementation to control time in tests.
-type TimeProvider func() time.Time
-
-// RealTime returns the current UTC time.
-// use this in production.
-func RealTime() time.Time {
-	return time.Now().UTC()
-}
-
 // MomentumConfig contains parameters for momentum calculation.
 type MomentumConfig struct {
 	// TimeWindow is the sliding window for counting activity.
@@ -38,9 +32,32 @@ func DefaultMomentumConfig() MomentumConfig {
 	}
 }
 
+// WithOverrides returns the config with any per-community overrides applied.
+// nil settings or nil fields keep the global value.
+func (c MomentumConfig) WithOverrides(settings *domain.CommunityMomentumSettings) MomentumConfig {
+	if settings == nil {
+		return c
+	}
+	if tw := settings.TimeWindow(); tw != nil {
+		c.TimeWindow = *tw
+	}
+	if df := settings.DecayFactor(); df != nil {
+		c.DecayFactor = *df
+	}
+	return c
+}
+
 // CalculateMomentumInput contains the data needed to calculate momentum.
 type CalculateMomentumInput struct {
 	CommunityID string
+
+	// DryRun computes the score and spike without writing to postgres/redis
+	// or dispatching webhooks. for testing config changes safely.
+	DryRun bool
+
+	// Percentile also places the new momentum among the listed communities.
+	// off for batches, where it would cost a redis round trip per community.
+	Percentile bool
 }
 
 // CalculateMomentumOutput contains the result of momentum calculation.
@@ -49,21 +66,108 @@ type CalculateMomentumOutput struct {
 	OldMomentum float64
 	NewMomentum float64
 	EventCount  int64
+	// Quarantined is the weight of events left out pending anomaly review.
+	Quarantined float64
 	TimeWindow  time.Duration
+	DecayFactor float64
 	WasUpdated  bool
+	DryRun      bool
+
+	// Decayed is set when the community had no events since its last calculation and its
+	// momentum was faded instead of recalculated, see WithMomentumDecay.
+	Decayed bool
+
+	// Spike is set when the change crosses the spike thresholds.
+	// in a dry run it's the notification that would have been sent.
+	Spike *domain.MomentumSpike
+
+	// SpikeSuppressed is set when the spike wasn't notified because the community
+	// was notified within the spike cooldown.
+	SpikeSuppressed bool
+
+	// Percentile is the share of listed communities at or below the new momentum.
+	// nil unless requested, or when it couldn't be read.
+	Percentile *float64
 }
 
 // LeaderboardUpdater abstracts the cache layer for momentum rankings.
 // allows the use case to remain decoupled from redis specifics.
 type LeaderboardUpdater interface {
 	UpdateLeaderboardScore(ctx context.Context, communityID string, momentum float64) error
+
+	// RemoveFromLeaderboard drops a community that shouldn't be listed, like a private one.
+	RemoveFromLeaderboard(ctx context.Context, communityID string) error
+}
+
+// LeaderboardSnapshotter builds a cycle's leaderboard on the side and swaps it in at once,
+// so readers never see a mix of old and new scores. implemented by the redis client.
+type LeaderboardSnapshotter interface {
+	// BeginLeaderboardSnapshot starts a snapshot from the live scores. returns the token
+	// that commits or aborts it, empty when another cycle is building one.
+	BeginLeaderboardSnapshot(ctx context.Context) (string, error)
+	StageLeaderboardScore(ctx context.Context, communityID string, momentum float64) error
+	UnstageLeaderboardScore(ctx context.Context, communityID string) error
+	CommitLeaderboardSnapshot(ctx context.Context, token string) error
+	AbortLeaderboardSnapshot(ctx context.Context, token string) error
+}
+
+// snapshotLeaderboard sends a cycle's leaderboard writes to its snapshot.
+type snapshotLeaderboard struct {
+	snapshots LeaderboardSnapshotter
+}
+
+func (l snapshotLeaderboard) UpdateLeaderboardScore(ctx context.Context, communityID string, momentum float64) error {
+	return l.snapshots.StageLeaderboardScore(ctx, communityID, momentum)
+}
+
+func (l snapshotLeaderboard) RemoveFromLeaderboard(ctx context.Context, communityID string) error {
+	return l.snapshots.UnstageLeaderboardScore(ctx, communityID)
+}
+
+// SpikeThresholdSource supplies the thresholds a momentum change must cross to be a spike.
+// implemented by the webhook worker, whose thresholds are reloadable.
+type SpikeThresholdSource interface {
+	Thresholds() domain.MomentumSpikeThresholds
 }
 
 // SpikeNotifier abstracts the notification layer for momentum spikes.
 // allows the use case to remain decoupled from webhook specifics.
 type SpikeNotifier interface {
 	NotifyMomentumSpike(ctx context.Context, spike *domain.MomentumSpike) (int, error)
-	Thresholds() domain.MomentumSpikeThresholds
+	SpikeThresholdSource
+}
+
+// QuarantineReader reports the weight of a community's events held back by anomaly flags.
+// satisfied by domain.AnomalyFlagRepository.
+type QuarantineReader interface {
+	SumQuarantinedWeights(ctx context.Context, communityID domain.CommunityID, since time.Time) (float64, error)
+	SumQuarantinedWeightsByRegion(ctx context.Context, communityID domain.CommunityID, since time.Time) (map[string]float64, error)
+}
+
+// SpikeCooldown suppresses repeat spike notifications for a community hovering around
+// the thresholds. implemented in redis, shared by every instance, or in memory without it.
+type SpikeCooldown interface {
+	// AcquireSpikeCooldown starts a cooldown for the community, recording the notified momentum,
+	// and returns true. returns false if a cooldown is already running.
+	AcquireSpikeCooldown(ctx context.Context, communityID string, momentum float64, cooldown time.Duration) (bool, error)
+}
+
+// PercentileReader places a momentum score among the listed communities, 0 to 100.
+// implemented by the redis client from the leaderboard.
+type PercentileReader interface {
+	MomentumPercentile(ctx context.Context, momentum float64) (float64, error)
+}
+
+// RegionWeightReader sums a community's event weights per region.
+// implemented by the postgres activity event repository.
+type RegionWeightReader interface {
+	SumWeightsByRegion(ctx context.Context, communityID domain.CommunityID, since time.Time) (map[string]float64, error)
+}
+
+// RegionalLeaderboardUpdater stores a community's momentum per region.
+// regions missing from scores drop the community from their leaderboard.
+type RegionalLeaderboardUpdater interface {
+	UpdateRegionalScores(ctx context.Context, communityID string, scores map[string]float64) error
 }
 
 // CalculateMomentumUseCase handles momentum calculation for communities.
@@ -71,55 +175,246 @@ type CalculateMomentumUseCase struct {
 	eventRepo     domain.ActivityEventRepository
 	communityRepo domain.CommunityRepository
 	leaderboard   LeaderboardUpdater
+	snapshots     LeaderboardSnapshotter
 	notifier      SpikeNotifier
+	thresholds    SpikeThresholdSource
+	events        EventPublisher
+	settingsRepo  domain.CommunityMomentumSettingsRepository
+	quarantine    QuarantineReader
+	regionWeights RegionWeightReader
+	regional      RegionalLeaderboardUpdater
+	percentiles   PercentileReader
+	cooldowns     SpikeCooldown
+	runs          domain.MomentumRunRepository
+	history       domain.MomentumHistoryRepository
+	rankings      domain.RankingSnapshotRepository
+	cooldown      time.Duration
+	decayHalfLife time.Duration
+	retries       int
+	retryBackoff  time.Duration
+	staleAfter    int
 	config        MomentumConfig
-	timeProvider  TimeProvider
+	clock         domain.Clock
 	logger        *logging.Logger
+
+	// missed counts the consecutive cycles each failing community missed, see WithStaleAfter
+	missedMu sync.Mutex
+	missed   map[domain.CommunityID]int
+}
+
+// CalculateMomentumOption configures a CalculateMomentumUseCase at construction.
+type CalculateMomentumOption func(*CalculateMomentumUseCase)
+
+// WithClock sets the clock momentum windows are measured from. for tests.
+func WithClock(clock domain.Clock) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.clock = clock
+	}
+}
+
+// WithLeaderboard sets the leaderboard updater (redis cache).
+// when set, momentum updates are also pushed to the cache.
+func WithLeaderboard(lb LeaderboardUpdater) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.leaderboard = lb
+	}
+}
+
+// WithRunLog records every ExecuteAll cycle, except dry runs, and prunes the records
+// older than domain.MomentumRunRetention.
+func WithRunLog(runs domain.MomentumRunRepository) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.runs = runs
+	}
+}
+
+// WithMomentumHistory stores every calculated or decayed score for history charts.
+// ExecuteAll prunes the scores older than domain.MomentumHistoryRetention.
+func WithMomentumHistory(history domain.MomentumHistoryRepository) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.history = history
+	}
+}
+
+// WithRankingSnapshots stores the top of the public leaderboard after every ExecuteAll
+// cycle, except dry runs, to tell how it changed since. ExecuteAll prunes the snapshots
+// older than domain.RankingSnapshotRetention.
+func WithRankingSnapshots(rankings domain.RankingSnapshotRepository) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.rankings = rankings
+	}
+}
+
+// WithLeaderboardSnapshots makes ExecuteAll swap in the leaderboard once the whole cycle
+// is calculated, instead of updating it community by community. needs WithLeaderboard.
+func WithLeaderboardSnapshots(s LeaderboardSnapshotter) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.snapshots = s
+	}
+}
+
+// WithNotifier sets the spike notifier (webhook dispatcher).
+// when set, momentum spikes trigger webhook notifications.
+func WithNotifier(n SpikeNotifier) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.notifier = n
+	}
+}
+
+// WithSpikeThresholds sets where spike thresholds come from when there's no notifier,
+// typically along with WithMomentumEvents.
+func WithSpikeThresholds(source SpikeThresholdSource) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.thresholds = source
+	}
+}
+
+// WithMomentumEvents publishes domain.MomentumCalculated for every stored momentum and
+// domain.SpikeDetected for every spike to notify, after the cooldown.
+func WithMomentumEvents(events EventPublisher) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.events = events
+	}
+}
+
+// WithSettings sets the per-community momentum overrides source.
+// when set, each community is calculated with its effective config.
+func WithSettings(repo domain.CommunityMomentumSettingsRepository) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.settingsRepo = repo
+	}
+}
+
+// WithQuarantine leaves events under a pending or confirmed anomaly flag out of momentum.
+func WithQuarantine(q QuarantineReader) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.quarantine = q
+	}
+}
+
+// WithRegionalLeaderboards also ranks listed communities per region, from the events carrying one.
+func WithRegionalLeaderboards(weights RegionWeightReader, lb RegionalLeaderboardUpdater) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.regionWeights = weights
+		uc.regional = lb
+	}
+}
+
+// WithPercentiles lets Execute report where the new momentum ranks, see CalculateMomentumInput.Percentile.
+func WithPercentiles(reader PercentileReader) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.percentiles = reader
+	}
+}
+
+// WithSpikeCooldown notifies at most one spike per community every cooldown.
+// the spike is still reported in the output, with SpikeSuppressed set.
+func WithSpikeCooldown(store SpikeCooldown, cooldown time.Duration) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.cooldowns = store
+		uc.cooldown = cooldown
+	}
+}
+
+// WithMomentumDecay lets ExecuteAll fade the momentum of communities without new events
+// since their last calculation, halving it every halfLife, instead of recalculating it.
+func WithMomentumDecay(halfLife time.Duration) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.decayHalfLife = halfLife
+	}
+}
+
+// WithCycleRetry retries a community that failed transiently, like on a timeout, up to
+// retries more times within an ExecuteAll cycle, waiting backoff before the first retry
+// and doubling it after each. permanent failures aren't retried.
+func WithCycleRetry(retries int, backoff time.Duration) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.retries = retries
+		uc.retryBackoff = backoff
+	}
+}
+
+// WithStaleAfter counts a community as stale once it failed more than cycles ExecuteAll
+// cycles in a row, see CalculateAllOutput.Stale. 0 counts none.
+func WithStaleAfter(cycles int) CalculateMomentumOption {
+	return func(uc *CalculateMomentumUseCase) {
+		uc.staleAfter = cycles
+	}
 }
 
 // NewCalculateMomentumUseCase creates a new CalculateMomentumUseCase.
+// optional collaborators are passed as options; apart from the missed cycle counts,
+// which are guarded, the use case is not modified after construction, so it's safe
+// to share between goroutines.
 func NewCalculateMomentumUseCase(
 	eventRepo domain.ActivityEventRepository,
 	communityRepo domain.CommunityRepository,
 	config MomentumConfig,
 	logger *logging.Logger,
+	opts ...CalculateMomentumOption,
 ) *CalculateMomentumUseCase {
-	return &CalculateMomentumUseCase{
+	uc := &CalculateMomentumUseCase{
 		eventRepo:     eventRepo,
 		communityRepo: communityRepo,
 		config:        config,
-		timeProvider:  RealTime,
+		clock:         domain.SystemClock,
 		logger:        logger.WithComponent("calculate_momentum"),
+		missed:        make(map[domain.CommunityID]int),
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
-}
-
-// WithTimeProvider sets a custom time provider for testing.
-func (uc *CalculateMomentumUseCase) WithTimeProvider(tp TimeProvider) *CalculateMomentumUseCase {
-	uc.timeProvider = tp
 	return uc
 }
 
-// WithLeaderboard sets the leaderboard updater (redis cache).
-// when set, momentum updates are also pushed to the cache.
-func (uc *CalculateMomentumUseCase) WithLeaderboard(lb LeaderboardUpdater) *CalculateMomentumUseCase {
-	uc.leaderboard = lb
-	return uc
+// effectiveConfig returns the global config with the community's overrides applied.
+// a failed lookup falls back to the global config rather than skipping the community.
+// ctx is expected to carry the community id for logging, as in Execute.
+func (uc *CalculateMomentumUseCase) effectiveConfig(ctx context.Context, communityID domain.CommunityID) MomentumConfig {
+	if uc.settingsRepo == nil {
+		return uc.config
+	}
+
+	settings, err := uc.settingsRepo.FindByCommunity(ctx, communityID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			uc.logger.WithContext(ctx).Warn("momentum settings lookup failed, using global config",
+				"error", err.Error(),
+			)
+		}
+		return uc.config
+	}
+
+	return uc.config.WithOverrides(settings)
 }
 
-// WithNotifier sets the spike notifier (webhook dispatcher).
-// when set, momentum spikes trigger webhook notifications.
-func (uc *CalculateMomentumUseCase) WithNotifier(n SpikeNotifier) *CalculateMomentumUseCase {
-	uc.notifier = n
-	return uc
+// spikeThresholds returns the configured thresholds, or the defaults without a source
+// so dry runs can still report spikes.
+func (uc *CalculateMomentumUseCase) spikeThresholds() domain.MomentumSpikeThresholds {
+	if uc.thresholds != nil {
+		return uc.thresholds.Thresholds()
+	}
+	if uc.notifier != nil {
+		return uc.notifier.Thresholds()
+	}
+	return domain.DefaultSpikeThresholds()
 }
 
 // Execute calculates and updates momentum for a community.
+// with DryRun set nothing is written and no webhooks are sent.
 func (uc *CalculateMomentumUseCase) Execute(ctx context.Context, input CalculateMomentumInput) (*CalculateMomentumOutput, error) {
+	return uc.execute(ctx, input, uc.leaderboard)
+}
+
+// execute is Execute writing the leaderboard through lb, which ExecuteAll points at its snapshot.
+func (uc *CalculateMomentumUseCase) execute(ctx context.Context, input CalculateMomentumInput, lb LeaderboardUpdater) (*CalculateMomentumOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
 	// parse and validate community id
 	communityID, err := domain.ParseCommunityID(input.CommunityID)
 	if err != nil {
-		uc.logger.Warn("momentum calculation rejected: invalid community id",
-			"community_id", input.CommunityID,
+		log.Warn("momentum calculation rejected: invalid community id",
 			"reason", err.Error(),
 		)
 		return nil, fmt.Errorf("invalid community id: %w", err)
@@ -128,8 +423,7 @@ func (uc *CalculateMomentumUseCase) Execute(ctx context.Context, input Calculate
 	// load community
 	community, err := uc.communityRepo.FindByID(ctx, communityID)
 	if err != nil {
-		uc.logger.Warn("momentum calculation failed: community lookup failed",
-			"community_id", communityID.String(),
+		log.Warn("momentum calculation failed: community lookup failed",
 			"reason", err.Error(),
 		)
 		return nil, fmt.Errorf("community lookup: %w", err)
@@ -137,15 +431,17 @@ func (uc *CalculateMomentumUseCase) Execute(ctx context.Context, input Calculate
 
 	oldMomentum := community.CurrentMomentum().Value()
 
-	// use injected time provider for testability
-	now := uc.timeProvider()
-	since := now.Add(-uc.config.TimeWindow)
+	// apply per-community overrides on top of the global config
+	config := uc.effectiveConfig(ctx, communityID)
+
+	// use injected clock for testability
+	now := uc.clock.Now()
+	since := now.Add(-config.TimeWindow)
 
 	// get event count for logging context
 	eventCount, err := uc.eventRepo.CountByCommunity(ctx, communityID, since)
 	if err != nil {
-		uc.logger.Error("momentum calculation failed: event count failed",
-			"community_id", communityID.String(),
+		log.Error("momentum calculation failed: event count failed",
 			"error", err.Error(),
 		)
 		return nil, fmt.Errorf("counting events: %w", err)
@@ -154,98 +450,364 @@ func (uc *CalculateMomentumUseCase) Execute(ctx context.Context, input Calculate
 	// calculate weighted sum of events in window
 	weightedSum, err := uc.eventRepo.SumWeightsByCommunity(ctx, communityID, since)
 	if err != nil {
-		uc.logger.Error("momentum calculation failed: weight sum failed",
-			"community_id", communityID.String(),
+		log.Error("momentum calculation failed: weight sum failed",
 			"error", err.Error(),
 		)
 		return nil, fmt.Errorf("summing weights: %w", err)
 	}
 
+	// suspected gaming doesn't count until an admin dismisses the flag
+	var quarantined float64
+	if uc.quarantine != nil {
+		quarantined, err = uc.quarantine.SumQuarantinedWeights(ctx, communityID, since)
+		if err != nil {
+			log.Error("momentum calculation failed: quarantine lookup failed",
+				"error", err.Error(),
+			)
+			return nil, fmt.Errorf("summing quarantined weights: %w", err)
+		}
+		weightedSum -= quarantined
+	}
+
 	// use pure domain function for momentum calculation
 	// using simpler model with pre-aggregated weights from db
-	newMomentum := domain.SimpleMomentum(weightedSum, uc.config.DecayFactor)
+	newMomentum := domain.SimpleMomentum(weightedSum, config.DecayFactor)
+
+	output := &CalculateMomentumOutput{
+		CommunityID: communityID.String(),
+		OldMomentum: oldMomentum,
+		NewMomentum: newMomentum.Value(),
+		EventCount:  eventCount,
+		Quarantined: quarantined,
+		TimeWindow:  config.TimeWindow,
+		DecayFactor: config.DecayFactor,
+		DryRun:      input.DryRun,
+	}
+
+	if uc.spikeThresholds().IsSpike(oldMomentum, newMomentum.Value()) {
+		percentChange := 0.0
+		if oldMomentum > 0 {
+			percentChange = (newMomentum.Value() - oldMomentum) / oldMomentum
+		}
+
+		output.Spike = &domain.MomentumSpike{
+			CommunityID:   communityID,
+			CommunityName: community.Name(),
+			OldMomentum:   oldMomentum,
+			NewMomentum:   newMomentum.Value(),
+			PercentChange: percentChange,
+			Timestamp:     now,
+		}
+	}
+
+	if input.DryRun {
+		if input.Percentile {
+			output.Percentile = uc.percentile(ctx, newMomentum.Value())
+		}
+		log.Info("momentum calculated",
+			"old_momentum", oldMomentum,
+			"new_momentum", newMomentum.Value(),
+			"event_count", eventCount,
+			"time_window", config.TimeWindow.String(),
+			"decay_factor", config.DecayFactor,
+			"spike", output.Spike != nil,
+			"outcome", "dry_run",
+		)
+		return output, nil
+	}
 
 	// update community momentum in postgres
 	if err := uc.communityRepo.UpdateMomentum(ctx, communityID, newMomentum); err != nil {
-		uc.logger.Error("momentum update failed",
-			"community_id", communityID.String(),
+		log.Error("momentum update failed",
 			"old_momentum", oldMomentum,
 			"new_momentum", newMomentum.Value(),
 			"error", err.Error(),
 		)
 		return nil, fmt.Errorf("updating momentum: %w", err)
 	}
+	output.WasUpdated = true
+	uc.publishMomentum(ctx, communityID, output, now)
+	uc.recordHistory(ctx, communityID, newMomentum, now)
 
-	// sync to redis leaderboard (best-effort, don't fail on cache errors)
-	if uc.leaderboard != nil {
-		if err := uc.leaderboard.UpdateLeaderboardScore(ctx, communityID.String(), newMomentum.Value()); err != nil {
-			// log but don't fail - postgres is the source of truth
-			uc.logger.Warn("leaderboard sync failed",
-				"community_id", communityID.String(),
-				"momentum", newMomentum.Value(),
-				"error", err.Error(),
-			)
-		}
-	}
+	uc.syncLeaderboards(ctx, lb, community, newMomentum, since, config.DecayFactor)
 
-	// check for spike and notify (best-effort, don't fail on notification errors)
-	if uc.notifier != nil {
-		thresholds := uc.notifier.Thresholds()
-		if thresholds.IsSpike(oldMomentum, newMomentum.Value()) {
-			percentChange := 0.0
-			if oldMomentum > 0 {
-				percentChange = (newMomentum.Value() - oldMomentum) / oldMomentum
-			}
-
-			spike := &domain.MomentumSpike{
-				CommunityID:   communityID,
-				CommunityName: community.Name(),
-				OldMomentum:   oldMomentum,
-				NewMomentum:   newMomentum.Value(),
-				PercentChange: percentChange,
-				Timestamp:     now,
-			}
+	// notify on spike (best-effort, don't fail on notification errors)
+	notifying := uc.notifier != nil || uc.events != nil
+	if notifying && output.Spike != nil {
+		output.SpikeSuppressed = uc.coolingDown(ctx, output.Spike)
+	}
+	if output.SpikeSuppressed {
+		log.Info("momentum spike suppressed by cooldown",
+			"old_momentum", oldMomentum,
+			"new_momentum", newMomentum.Value(),
+			"cooldown", uc.cooldown.String(),
+		)
+	} else if notifying && output.Spike != nil {
+		uc.notifySpike(ctx, output.Spike)
+	}
 
-			if _, err := uc.notifier.NotifyMomentumSpike(ctx, spike); err != nil {
-				uc.logger.Warn("spike notification failed",
-					"community_id", communityID.String(),
-					"error", err.Error(),
-				)
-			} else {
-				uc.logger.Info("momentum spike detected",
-					"community_id", communityID.String(),
-					"old_momentum", oldMomentum,
-					"new_momentum", newMomentum.Value(),
-					"percent_change", percentChange,
-				)
-			}
-		}
+	// after the leaderboard sync, so a listed community counts itself
+	if input.Percentile {
+		output.Percentile = uc.percentile(ctx, newMomentum.Value())
 	}
 
-	uc.logger.Info("momentum calculated",
-		"community_id", communityID.String(),
+	log.Info("momentum calculated",
 		"old_momentum", oldMomentum,
 		"new_momentum", newMomentum.Value(),
 		"event_count", eventCount,
-		"time_window", uc.config.TimeWindow.String(),
+		"quarantined_weight", quarantined,
+		"time_window", config.TimeWindow.String(),
+		"decay_factor", config.DecayFactor,
 		"leaderboard_enabled", uc.leaderboard != nil,
 		"notifier_enabled", uc.notifier != nil,
 		"outcome", "updated",
 	)
 
-	return &CalculateMomentumOutput{
-		CommunityID: communityID.String(),
-		OldMomentum: oldMomentum,
+	return output, nil
+}
+
+// notifySpike publishes a spike and hands it to the notifier, whichever are set.
+func (uc *CalculateMomentumUseCase) notifySpike(ctx context.Context, spike *domain.MomentumSpike) {
+	log := uc.logger.WithContext(ctx)
+
+	if uc.events != nil {
+		uc.events.Publish(ctx, domain.SpikeDetected{Spike: *spike})
+	}
+	if uc.notifier != nil {
+		if _, err := uc.notifier.NotifyMomentumSpike(ctx, spike); err != nil {
+			log.Warn("spike notification failed",
+				"error", err.Error(),
+			)
+			return
+		}
+	}
+
+	log.Info("momentum spike detected",
+		"old_momentum", spike.OldMomentum,
+		"new_momentum", spike.NewMomentum,
+		"percent_change", spike.PercentChange,
+	)
+}
+
+// publishMomentum publishes a stored momentum, see WithMomentumEvents.
+func (uc *CalculateMomentumUseCase) publishMomentum(ctx context.Context, communityID domain.CommunityID, output *CalculateMomentumOutput, at time.Time) {
+	if uc.events == nil {
+		return
+	}
+	uc.events.Publish(ctx, domain.MomentumCalculated{
+		CommunityID:  communityID,
+		OldMomentum:  output.OldMomentum,
+		NewMomentum:  output.NewMomentum,
+		Decayed:      output.Decayed,
+		CalculatedAt: at,
+	})
+}
+
+// recordHistory stores a score for history charts. best effort like the leaderboard sync:
+// a gap in the history doesn't undo the update.
+func (uc *CalculateMomentumUseCase) recordHistory(ctx context.Context, communityID domain.CommunityID, momentum domain.Momentum, at time.Time) {
+	if uc.history == nil {
+		return
+	}
+	if err := uc.history.Record(ctx, communityID, momentum, at); err != nil {
+		uc.logger.WithContext(ctx).Warn("momentum history record failed", "error", err.Error())
+	}
+}
+
+// syncLeaderboards pushes the new momentum to the leaderboard and the regional rankings.
+// best-effort: postgres is the source of truth, so failures are only logged.
+func (uc *CalculateMomentumUseCase) syncLeaderboards(ctx context.Context, lb LeaderboardUpdater, community *domain.Community, momentum domain.Momentum, since time.Time, decayFactor float64) {
+	log := uc.logger.WithContext(ctx)
+
+	// only public communities are listed; the rest are removed in case their visibility changed
+	if lb != nil {
+		var err error
+		if community.Visibility().IsListed() {
+			err = lb.UpdateLeaderboardScore(ctx, community.ID().String(), momentum.Value())
+		} else {
+			err = lb.RemoveFromLeaderboard(ctx, community.ID().String())
+		}
+		if err != nil {
+			log.Warn("leaderboard sync failed",
+				"momentum", momentum.Value(),
+				"error", err.Error(),
+			)
+		}
+	}
+
+	// regional rankings only for listed communities: the leaderboard removal above
+	// already took unlisted ones off every region
+	if uc.regional != nil && community.Visibility().IsListed() {
+		if err := uc.syncRegionalLeaderboards(ctx, community.ID(), since, decayFactor); err != nil {
+			log.Warn("regional leaderboard sync failed",
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+// decay fades the momentum of a community without events since its last calculation,
+// see WithMomentumDecay. returns nil when the community needs a full calculation.
+func (uc *CalculateMomentumUseCase) decay(ctx context.Context, lb LeaderboardUpdater, community *domain.Community, dryRun bool) (*CalculateMomentumOutput, error) {
+	updatedAt := community.MomentumUpdatedAt()
+	if uc.decayHalfLife <= 0 || updatedAt == nil {
+		return nil, nil
+	}
+
+	ctx = logging.ContextWithCommunityID(ctx, community.ID().String())
+	log := uc.logger.WithContext(ctx)
+
+	// counted by occurred_at, so late events land on the full calculation of RecalculateLate
+	newEvents, err := uc.eventRepo.CountByCommunity(ctx, community.ID(), *updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("counting events: %w", err)
+	}
+	if newEvents > 0 {
+		return nil, nil
+	}
+
+	config := uc.effectiveConfig(ctx, community.ID())
+	now := uc.clock.Now()
+	oldMomentum := community.CurrentMomentum()
+	newMomentum := domain.DecayMomentum(oldMomentum, now.Sub(*updatedAt), uc.decayHalfLife)
+
+	output := &CalculateMomentumOutput{
+		CommunityID: community.ID().String(),
+		OldMomentum: oldMomentum.Value(),
 		NewMomentum: newMomentum.Value(),
-		EventCount:  eventCount,
-		TimeWindow:  uc.config.TimeWindow,
-		WasUpdated:  true,
-	}, nil
+		TimeWindow:  config.TimeWindow,
+		DecayFactor: config.DecayFactor,
+		DryRun:      dryRun,
+		Decayed:     true,
+	}
+	// already faded out, nothing to write
+	if dryRun || oldMomentum.IsZero() {
+		return output, nil
+	}
+
+	if err := uc.communityRepo.UpdateMomentum(ctx, community.ID(), newMomentum); err != nil {
+		log.Error("momentum update failed",
+			"old_momentum", oldMomentum.Value(),
+			"new_momentum", newMomentum.Value(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("updating momentum: %w", err)
+	}
+	output.WasUpdated = true
+	uc.publishMomentum(ctx, community.ID(), output, now)
+	uc.recordHistory(ctx, community.ID(), newMomentum, now)
+
+	uc.syncLeaderboards(ctx, lb, community, newMomentum, now.Add(-config.TimeWindow), config.DecayFactor)
+
+	log.Debug("momentum decayed",
+		"old_momentum", oldMomentum.Value(),
+		"new_momentum", newMomentum.Value(),
+		"half_life", uc.decayHalfLife.String(),
+		"outcome", "decayed",
+	)
+	return output, nil
+}
+
+// coolingDown reports whether the community's spike falls within its cooldown, starting
+// a new cooldown when it doesn't. a failed lookup notifies, a duplicate beats a lost spike.
+func (uc *CalculateMomentumUseCase) coolingDown(ctx context.Context, spike *domain.MomentumSpike) bool {
+	if uc.cooldowns == nil || uc.cooldown <= 0 {
+		return false
+	}
+	acquired, err := uc.cooldowns.AcquireSpikeCooldown(ctx, spike.CommunityID.String(), spike.NewMomentum, uc.cooldown)
+	if err != nil {
+		uc.logger.WithContext(ctx).Warn("spike cooldown lookup failed, notifying", "error", err.Error())
+		return false
+	}
+	return !acquired
+}
+
+// percentile places a momentum score among the listed communities, nil if it can't be read.
+// best effort like the leaderboard sync: the score is still returned without it.
+func (uc *CalculateMomentumUseCase) percentile(ctx context.Context, momentum float64) *float64 {
+	if uc.percentiles == nil {
+		return nil
+	}
+	p, err := uc.percentiles.MomentumPercentile(ctx, momentum)
+	if err != nil {
+		uc.logger.WithContext(ctx).Warn("momentum percentile lookup failed", "error", err.Error())
+		return nil
+	}
+	return &p
+}
+
+// syncRegionalLeaderboards computes the community's momentum in each region and stores it.
+func (uc *CalculateMomentumUseCase) syncRegionalLeaderboards(ctx context.Context, communityID domain.CommunityID, since time.Time, decayFactor float64) error {
+	sums, err := uc.regionWeights.SumWeightsByRegion(ctx, communityID, since)
+	if err != nil {
+		return fmt.Errorf("summing region weights: %w", err)
+	}
+
+	if uc.quarantine != nil && len(sums) > 0 {
+		quarantined, err := uc.quarantine.SumQuarantinedWeightsByRegion(ctx, communityID, since)
+		if err != nil {
+			return fmt.Errorf("summing quarantined region weights: %w", err)
+		}
+		for region, weight := range quarantined {
+			sums[region] -= weight
+		}
+	}
+
+	scores := make(map[string]float64, len(sums))
+	for region, sum := range sums {
+		scores[region] = domain.SimpleMomentum(sum, decayFactor).Value()
+	}
+	return uc.regional.UpdateRegionalScores(ctx, communityID.String(), scores)
+}
+
+// RecalculateLateInput names a community that received events with a past occurred_at.
+type RecalculateLateInput struct {
+	CommunityID string
+
+	// OccurredAt is the earliest occurred_at among the late events.
+	OccurredAt time.Time
+}
+
+// RecalculateLate recalculates a community's momentum when late events landed in a window
+// that was already calculated: they occurred before the last calculation but weren't stored
+// in time for it. returns nil when the last calculation predates them or they fall outside
+// the window, the next cycle counts them as usual then.
+func (uc *CalculateMomentumUseCase) RecalculateLate(ctx context.Context, input RecalculateLateInput) (*CalculateMomentumOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+
+	communityID, err := domain.ParseCommunityID(input.CommunityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("community lookup: %w", err)
+	}
+
+	calculatedAt := community.MomentumUpdatedAt()
+	if calculatedAt == nil || input.OccurredAt.After(*calculatedAt) {
+		return nil, nil
+	}
+	config := uc.effectiveConfig(ctx, communityID)
+	if input.OccurredAt.Before(uc.clock.Now().Add(-config.TimeWindow)) {
+		return nil, nil
+	}
+
+	uc.logger.WithContext(ctx).Debug("late events in a calculated window, recalculating",
+		"occurred_at", input.OccurredAt,
+		"momentum_updated_at", *calculatedAt,
+	)
+	return uc.Execute(ctx, CalculateMomentumInput{CommunityID: input.CommunityID})
 }
 
 // CalculateAllInput is empty as we process all active communities.
 type CalculateAllInput struct {
-	Limit int // max communities to process, 0 for all
+	Limit  int  // max communities to process, 0 for all
+	DryRun bool // compute without writing, see CalculateMomentumInput.DryRun
+
+	// TriggeredBy is what started the cycle, for its run record.
+	TriggeredBy domain.MomentumRunTrigger
 }
 
 // CalculateAllOutput contains the result of batch momentum calculation.
@@ -253,11 +815,115 @@ type CalculateAllOutput struct {
 	Processed int
 	Succeeded int
 	Failed    int
+	Spikes    int // communities whose change crossed the spike thresholds
+	Decayed   int // communities without new events, faded instead of recalculated
+
+	// Retried counts the retries of communities that failed transiently, see WithCycleRetry.
+	Retried int
+
+	// Stale counts the communities that failed more cycles in a row than WithStaleAfter
+	// allows, this one included. their momentum stopped moving.
+	Stale int
+
+	// Failures says why communities failed, the first domain.MaxMomentumRunFailures of them.
+	Failures []domain.MomentumFailure
+
+	// Results holds every computed score, only filled in for dry runs.
+	Results []*CalculateMomentumOutput
 }
 
 // ExecuteAll calculates momentum for all active communities.
 // useful for background jobs.
 func (uc *CalculateMomentumUseCase) ExecuteAll(ctx context.Context, input CalculateAllInput) (*CalculateAllOutput, error) {
+	run := uc.startRun(input)
+	output, err := uc.executeAll(ctx, input)
+	if run != nil {
+		uc.finishRun(ctx, run, output, err)
+	}
+	if err == nil && !input.DryRun {
+		uc.snapshotRankings(ctx, run)
+	}
+	return output, err
+}
+
+// snapshotRankings stores the leaderboard a cycle left, under its run's id when it has one,
+// and prunes old snapshots. a failed snapshot only leaves a gap in the changes.
+func (uc *CalculateMomentumUseCase) snapshotRankings(ctx context.Context, run *domain.MomentumRun) {
+	if uc.rankings == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	communities, err := uc.communityRepo.ListPublicByMomentum(ctx, domain.RankingSnapshotSize, 0)
+	if err != nil {
+		uc.logger.Warn("ranking snapshot failed: listing communities", "error", err.Error())
+		return
+	}
+	entries := make([]domain.RankedCommunity, len(communities))
+	for i, c := range communities {
+		entries[i] = domain.RankedCommunity{
+			CommunityID: c.ID(),
+			Rank:        i + 1,
+			Momentum:    c.CurrentMomentum().Value(),
+		}
+	}
+
+	id := uuid.New()
+	if run != nil {
+		id = run.ID()
+	}
+	snapshot, err := domain.NewRankingSnapshot(uc.clock, id, entries)
+	if err != nil {
+		uc.logger.Warn("ranking snapshot failed", "error", err.Error())
+		return
+	}
+	if err := uc.rankings.Save(ctx, snapshot); err != nil {
+		uc.logger.Warn("ranking snapshot failed", "error", err.Error())
+		return
+	}
+	if _, err := uc.rankings.DeleteBefore(ctx, snapshot.TakenAt().Add(-domain.RankingSnapshotRetention)); err != nil {
+		uc.logger.Warn("ranking snapshot pruning failed", "error", err.Error())
+	}
+}
+
+// startRun begins the run record of a cycle, nil when runs aren't recorded.
+func (uc *CalculateMomentumUseCase) startRun(input CalculateAllInput) *domain.MomentumRun {
+	if uc.runs == nil || input.DryRun {
+		return nil
+	}
+	run, err := domain.StartMomentumRun(uc.clock, input.TriggeredBy)
+	if err != nil {
+		uc.logger.Warn("momentum run not recorded",
+			"triggered_by", input.TriggeredBy.String(),
+			"error", err.Error(),
+		)
+		return nil
+	}
+	return run
+}
+
+// finishRun saves the run record, even when the cycle was cancelled, and prunes old ones.
+func (uc *CalculateMomentumUseCase) finishRun(ctx context.Context, run *domain.MomentumRun, output *CalculateAllOutput, cause error) {
+	if output == nil {
+		output = &CalculateAllOutput{}
+	}
+	run.Finish(uc.clock, output.Processed, output.Succeeded, output.Failed, output.Failures, cause)
+
+	ctx = context.WithoutCancel(ctx)
+	if err := uc.runs.Save(ctx, run); err != nil {
+		uc.logger.Warn("momentum run record failed",
+			"run_id", run.ID().String(),
+			"error", err.Error(),
+		)
+		return
+	}
+	if _, err := uc.runs.DeleteBefore(ctx, run.StartedAt().Add(-domain.MomentumRunRetention)); err != nil {
+		uc.logger.Warn("momentum run pruning failed", "error", err.Error())
+	}
+}
+
+// executeAll is ExecuteAll without the run record.
+func (uc *CalculateMomentumUseCase) executeAll(ctx context.Context, input CalculateAllInput) (*CalculateAllOutput, error) {
 	limit := input.Limit
 	if limit == 0 {
 		limit = 1000 // reasonable default
@@ -275,23 +941,170 @@ func (uc *CalculateMomentumUseCase) ExecuteAll(ctx context.Context, input Calcul
 		Processed: len(communities),
 	}
 
+	lb, snapshotToken := uc.beginSnapshot(ctx, input.DryRun)
+	failed := make(map[domain.CommunityID]bool)
 	for _, community := range communities {
-		_, err := uc.Execute(ctx, CalculateMomentumInput{
-			CommunityID: community.ID().String(),
-		})
+		result, retried, err := uc.calculateWithRetry(ctx, lb, community, input.DryRun)
+		output.Retried += retried
 		if err != nil {
 			output.Failed++
+			failed[community.ID()] = true
+			if len(output.Failures) < domain.MaxMomentumRunFailures {
+				output.Failures = append(output.Failures, domain.MomentumFailure{
+					CommunityID: community.ID(),
+					Reason:      err.Error(),
+					Transient:   isTransientMomentumFailure(err),
+				})
+			}
 			// don't fail the whole batch, continue with others
 			continue
 		}
 		output.Succeeded++
+		if result.Spike != nil {
+			output.Spikes++
+		}
+		if result.Decayed {
+			output.Decayed++
+		}
+		if input.DryRun {
+			output.Results = append(output.Results, result)
+		}
+	}
+
+	if snapshotToken != "" {
+		uc.commitSnapshot(ctx, snapshotToken)
+	}
+	if !input.DryRun {
+		output.Stale = uc.trackMissedCycles(communities, failed, input.Limit == 0)
+	}
+	if uc.history != nil && !input.DryRun {
+		if _, err := uc.history.DeleteBefore(ctx, uc.clock.Now().Add(-domain.MomentumHistoryRetention)); err != nil {
+			uc.logger.Warn("momentum history pruning failed", "error", err.Error())
+		}
 	}
 
 	uc.logger.Info("batch momentum calculation completed",
 		"processed", output.Processed,
 		"succeeded", output.Succeeded,
 		"failed", output.Failed,
+		"retried", output.Retried,
+		"stale", output.Stale,
+		"spikes", output.Spikes,
+		"decayed", output.Decayed,
+		"dry_run", input.DryRun,
 	)
 
 	return output, nil
 }
+
+// calculateWithRetry calculates one community for ExecuteAll, decaying it when it can,
+// and retries transient failures as WithCycleRetry allows. returns how many retries it took.
+func (uc *CalculateMomentumUseCase) calculateWithRetry(ctx context.Context, lb LeaderboardUpdater, community *domain.Community, dryRun bool) (*CalculateMomentumOutput, int, error) {
+	backoff := uc.retryBackoff
+	for attempt := 0; ; attempt++ {
+		result, err := uc.decay(ctx, lb, community, dryRun)
+		if err == nil && result == nil {
+			result, err = uc.execute(ctx, CalculateMomentumInput{
+				CommunityID: community.ID().String(),
+				DryRun:      dryRun,
+			}, lb)
+		}
+		if err == nil || attempt >= uc.retries || !isTransientMomentumFailure(err) {
+			return result, attempt, err
+		}
+
+		uc.logger.WithContext(logging.ContextWithCommunityID(ctx, community.ID().String())).Debug("retrying momentum calculation",
+			"attempt", attempt+1,
+			"backoff", backoff.String(),
+			"error", err.Error(),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// trackMissedCycles counts the cycles each community failed in a row and returns how many
+// are stale. a partial cycle, with a limit, leaves the communities it didn't list alone.
+func (uc *CalculateMomentumUseCase) trackMissedCycles(communities []*domain.Community, failed map[domain.CommunityID]bool, full bool) int {
+	uc.missedMu.Lock()
+	defer uc.missedMu.Unlock()
+
+	missed := make(map[domain.CommunityID]int, len(failed))
+	if !full {
+		for id, n := range uc.missed {
+			missed[id] = n
+		}
+	}
+	for _, community := range communities {
+		id := community.ID()
+		if !failed[id] {
+			delete(missed, id)
+			continue
+		}
+		missed[id] = uc.missed[id] + 1
+		if uc.staleAfter > 0 && missed[id] == uc.staleAfter+1 {
+			uc.logger.Warn("community momentum is stale",
+				"community_id", id.String(),
+				"missed_cycles", missed[id],
+			)
+		}
+	}
+	uc.missed = missed
+
+	if uc.staleAfter <= 0 {
+		return 0
+	}
+	stale := 0
+	for _, n := range missed {
+		if n > uc.staleAfter {
+			stale++
+		}
+	}
+	return stale
+}
+
+// isTransientMomentumFailure reports whether a community that failed with err will likely
+// succeed on the next cycle. only missing or invalid data is permanent, storage errors
+// and timeouts pass.
+func isTransientMomentumFailure(err error) bool {
+	return !errors.Is(err, domain.ErrNotFound) && !errors.Is(err, domain.ErrInvalidInput)
+}
+
+// beginSnapshot returns where ExecuteAll writes the leaderboard: a new snapshot when it could
+// start one, with the token that commits it, else the live leaderboard, as without WithLeaderboardSnapshots.
+func (uc *CalculateMomentumUseCase) beginSnapshot(ctx context.Context, dryRun bool) (LeaderboardUpdater, string) {
+	if uc.snapshots == nil || uc.leaderboard == nil || dryRun {
+		return uc.leaderboard, ""
+	}
+
+	token, err := uc.snapshots.BeginLeaderboardSnapshot(ctx)
+	if err != nil {
+		uc.logger.Warn("leaderboard snapshot failed, updating the live leaderboard",
+			"error", err.Error(),
+		)
+		return uc.leaderboard, ""
+	}
+	if token == "" {
+		uc.logger.Debug("another cycle is building a leaderboard snapshot, updating the live leaderboard")
+		return uc.leaderboard, ""
+	}
+	return snapshotLeaderboard{snapshots: uc.snapshots}, token
+}
+
+// commitSnapshot swaps in the cycle's leaderboard. an interrupted cycle still swaps in what it
+// calculated, the rest keep their previous scores.
+func (uc *CalculateMomentumUseCase) commitSnapshot(ctx context.Context, token string) {
+	ctx = context.WithoutCancel(ctx)
+	if err := uc.snapshots.CommitLeaderboardSnapshot(ctx, token); err != nil {
+		uc.logger.Error("leaderboard snapshot swap failed, the live leaderboard keeps its previous scores",
+			"error", err.Error(),
+		)
+		if err := uc.snapshots.AbortLeaderboardSnapshot(ctx, token); err != nil {
+			uc.logger.Warn("leaderboard snapshot cleanup failed", "error", err.Error())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// ErrCommunityPrivate is returned when someone outside a private community reaches its activity.
+var ErrCommunityPrivate = errors.New("community is private")
+
+// VisibilityResolver looks up a community's visibility.
+// typically a cache, so public communities don't cost a query per event.
+type VisibilityResolver interface {
+	VisibilityOf(ctx context.Context, id domain.CommunityID) (domain.CommunityVisibility, error)
+}
+
+// CommunityAccess decides who can see and contribute to private communities.
+// members are the creator, users who joined through an invitation, and
+// members of the organization the community belongs to.
+type CommunityAccess struct {
+	communityRepo domain.CommunityRepository
+	memberRepo    domain.CommunityMemberRepository
+	orgRepo       domain.OrganizationRepository
+	visibility    VisibilityResolver
+}
+
+// CommunityAccessOption configures a CommunityAccess at construction.
+type CommunityAccessOption func(*CommunityAccess)
+
+// WithOrganizationMembers counts every member of a community's organization as a member.
+func WithOrganizationMembers(repo domain.OrganizationRepository) CommunityAccessOption {
+	return func(a *CommunityAccess) {
+		a.orgRepo = repo
+	}
+}
+
+// WithVisibilityResolver looks visibility up through resolver instead of loading the community.
+func WithVisibilityResolver(resolver VisibilityResolver) CommunityAccessOption {
+	return func(a *CommunityAccess) {
+		a.visibility = resolver
+	}
+}
+
+// NewCommunityAccess creates a new CommunityAccess.
+func NewCommunityAccess(
+	communityRepo domain.CommunityRepository,
+	memberRepo domain.CommunityMemberRepository,
+	opts ...CommunityAccessOption,
+) *CommunityAccess {
+	a := &CommunityAccess{
+		communityRepo: communityRepo,
+		memberRepo:    memberRepo,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// IsMember reports whether a user belongs to a community.
+func (a *CommunityAccess) IsMember(ctx context.Context, community *domain.Community, userID domain.UserID) (bool, error) {
+	if community.CreatorID() == userID {
+		return true, nil
+	}
+
+	_, err := a.memberRepo.FindMember(ctx, community.ID(), userID)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return false, fmt.Errorf("looking up membership: %w", err)
+	}
+
+	orgID := community.OrganizationID()
+	if orgID == nil || a.orgRepo == nil {
+		return false, nil
+	}
+	_, err = a.orgRepo.FindMember(ctx, *orgID, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("looking up organization membership: %w", err)
+	}
+	return true, nil
+}
+
+// CheckView returns ErrCommunityPrivate when the community is private and
+// the user isn't a member. userID is nil for anonymous callers.
+func (a *CommunityAccess) CheckView(ctx context.Context, community *domain.Community, userID *domain.UserID) error {
+	if !community.IsPrivate() {
+		return nil
+	}
+	if userID == nil {
+		return ErrCommunityPrivate
+	}
+
+	member, err := a.IsMember(ctx, community, *userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrCommunityPrivate
+	}
+	return nil
+}
+
+// CheckCommunity is CheckView by id. the community is only loaded when it's
+// private, so with a resolver public communities cost a cache lookup.
+func (a *CommunityAccess) CheckCommunity(ctx context.Context, id domain.CommunityID, userID *domain.UserID) error {
+	if a.visibility != nil {
+		visibility, err := a.visibility.VisibilityOf(ctx, id)
+		if err != nil {
+			return &lookupError{op: "visibility lookup", err: err}
+		}
+		if visibility != domain.VisibilityPrivate {
+			return nil
+		}
+	}
+
+	community, err := a.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return &lookupError{op: "community lookup", err: err}
+	}
+	return a.CheckView(ctx, community, userID)
+}
@@ -0,0 +1,173 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// CommunityDetailsUseCase lets a community's creator edit its details or deactivate it.
+type CommunityDetailsUseCase struct {
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	leaderboard   LeaderboardUpdater
+	events        EventPublisher
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// CommunityDetailsOption configures a CommunityDetailsUseCase at construction.
+type CommunityDetailsOption func(*CommunityDetailsUseCase)
+
+// WithDeactivationLeaderboard removes deactivated communities from the leaderboard cache
+// right away, instead of on the next momentum cycle.
+func WithDeactivationLeaderboard(lb LeaderboardUpdater) CommunityDetailsOption {
+	return func(uc *CommunityDetailsUseCase) {
+		uc.leaderboard = lb
+	}
+}
+
+// WithDeactivationEvents publishes domain.CommunityDeactivated for every deactivated
+// community, so every instance drops it from its community cache.
+func WithDeactivationEvents(events EventPublisher) CommunityDetailsOption {
+	return func(uc *CommunityDetailsUseCase) {
+		uc.events = events
+	}
+}
+
+// NewCommunityDetailsUseCase creates a new CommunityDetailsUseCase.
+func NewCommunityDetailsUseCase(
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+	opts ...CommunityDetailsOption,
+) *CommunityDetailsUseCase {
+	uc := &CommunityDetailsUseCase{
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("community_details"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// UpdateCommunityInput holds the fields to change. nil fields are left as they are.
+type UpdateCommunityInput struct {
+	CommunityID         string
+	Name                *string
+	Description         *string
+	AvatarURL           *string
+	RequesterExternalID string
+}
+
+// Update changes a community's name, description or avatar.
+func (uc *CommunityDetailsUseCase) Update(ctx context.Context, input UpdateCommunityInput) (*domain.Community, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
+	community, requester, err := uc.authorize(ctx, input.CommunityID, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, description, avatarURL := community.Name(), community.Description(), community.AvatarURL()
+	if input.Name != nil {
+		name = *input.Name
+	}
+	if input.Description != nil {
+		description = *input.Description
+	}
+	if input.AvatarURL != nil {
+		avatarURL = *input.AvatarURL
+	}
+	if err := community.UpdateDetails(name, description, avatarURL); err != nil {
+		return nil, err
+	}
+
+	if err := uc.communityRepo.Save(ctx, community); err != nil {
+		log.Error("community update failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving community: %w", err)
+	}
+
+	log.Info("community updated",
+		"requester_id", requester.ID().String(),
+	)
+	return community, nil
+}
+
+// Deactivate stops a community from accepting events and takes it off the leaderboard.
+// deactivating an inactive community does nothing.
+func (uc *CommunityDetailsUseCase) Deactivate(ctx context.Context, communityID, requesterExternalID string) (*domain.Community, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+	log := uc.logger.WithContext(ctx)
+
+	community, requester, err := uc.authorize(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+	if !community.IsActive() {
+		return community, nil
+	}
+
+	community.Deactivate()
+	if err := uc.communityRepo.Save(ctx, community); err != nil {
+		log.Error("community deactivation failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving community: %w", err)
+	}
+
+	if uc.leaderboard != nil {
+		if err := uc.leaderboard.RemoveFromLeaderboard(ctx, community.ID().String()); err != nil {
+			// the leaderboard read skips inactive communities, and the next cycle removes it
+			log.Warn("leaderboard removal failed",
+				"error", err.Error(),
+			)
+		}
+	}
+
+	if uc.events != nil {
+		uc.events.Publish(ctx, domain.CommunityDeactivated{
+			CommunityID:   community.ID(),
+			DeactivatedAt: uc.clock.Now(),
+		})
+	}
+
+	log.Info("community deactivated",
+		"requester_id", requester.ID().String(),
+	)
+	return community, nil
+}
+
+// authorize loads the community and checks the requester created it.
+func (uc *CommunityDetailsUseCase) authorize(ctx context.Context, communityID, requesterExternalID string) (*domain.Community, *domain.User, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, ErrNotCommunityOwner
+		}
+		return nil, nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	if requester.ID() != community.CreatorID() {
+		return nil, nil, ErrNotCommunityOwner
+	}
+	return community, requester, nil
+}
@@ -0,0 +1,190 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+const (
+	// defaultEventsWindow is how far back events are listed when no since is given.
+	defaultEventsWindow = 24 * time.Hour
+
+	defaultEventsLimit = 50
+	maxEventsLimit     = 100
+)
+
+// CommunityEventsUseCase lists the recent activity events of a community,
+// so operators can inspect what feeds its momentum without querying the database.
+type CommunityEventsUseCase struct {
+	eventRepo     domain.ActivityEventRepository
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// NewCommunityEventsUseCase creates a new CommunityEventsUseCase.
+// orgRepo lets owners and admins of a community's organization list its events,
+// nil leaves it to the creator and admins.
+func NewCommunityEventsUseCase(
+	eventRepo domain.ActivityEventRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	orgRepo domain.OrganizationRepository,
+	logger *logging.Logger,
+) *CommunityEventsUseCase {
+	return &CommunityEventsUseCase{
+		eventRepo:     eventRepo,
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("community_events"),
+	}
+}
+
+// ListCommunityEventsInput selects the events to list.
+type ListCommunityEventsInput struct {
+	CommunityID string
+
+	// Since defaults to 24 hours ago when zero.
+	Since time.Time
+
+	// Types restricts the listing to these event types, empty lists every type.
+	Types []string
+
+	// Limit defaults to 50, and is capped at 100.
+	Limit int
+
+	// RequesterExternalID comes from the validated JWT
+	RequesterExternalID string
+
+	// Admin lists the events of any community.
+	Admin bool
+}
+
+// CommunityEventOutput is one activity event of a community.
+type CommunityEventOutput struct {
+	ID         string
+	UserID     string // empty for anonymous events
+	EventType  string
+	Weight     float64
+	Metadata   map[string]any
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
+
+// ListCommunityEventsOutput contains the events, newest first.
+type ListCommunityEventsOutput struct {
+	CommunityID string
+	Since       time.Time
+	Events      []CommunityEventOutput
+}
+
+// List returns the events of a community that occurred since the given time, newest first.
+// only the community owner, its organization's owners and admins, and platform admins may list them.
+func (uc *CommunityEventsUseCase) List(ctx context.Context, input ListCommunityEventsInput) (*ListCommunityEventsOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultEventsLimit
+	}
+	limit = min(limit, maxEventsLimit)
+
+	types := make([]domain.EventType, 0, len(input.Types))
+	for _, s := range input.Types {
+		t, err := domain.ParseEventType(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, s)
+		}
+		types = append(types, t)
+	}
+
+	since := input.Since
+	if since.IsZero() {
+		since = uc.clock.Now().Add(-defaultEventsWindow)
+	}
+
+	id, err := uc.authorize(ctx, input.CommunityID, input.RequesterExternalID, input.Admin)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.eventRepo.FindByCommunity(ctx, id, since, types, limit)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("community events lookup failed",
+			"community_id", id.String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("finding events: %w", err)
+	}
+
+	out := &ListCommunityEventsOutput{
+		CommunityID: id.String(),
+		Since:       since,
+		Events:      make([]CommunityEventOutput, len(events)),
+	}
+	for i, event := range events {
+		out.Events[i] = toCommunityEventOutput(event)
+	}
+	return out, nil
+}
+
+// authorize checks the requester may list the community's events.
+func (uc *CommunityEventsUseCase) authorize(ctx context.Context, communityID, requesterExternalID string, admin bool) (domain.CommunityID, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return domain.CommunityID{}, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.CommunityID{}, err
+	}
+	if admin {
+		return id, nil
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.CommunityID{}, ErrNotCommunityOwner
+		}
+		return domain.CommunityID{}, fmt.Errorf("looking up requester: %w", err)
+	}
+	if requester.ID() == community.CreatorID() {
+		return id, nil
+	}
+
+	orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+	if err != nil {
+		return domain.CommunityID{}, err
+	}
+	if !orgAdmin {
+		uc.logger.WithContext(ctx).Info("community events listing rejected: not owner",
+			"requester_id", requester.ID().String(),
+		)
+		return domain.CommunityID{}, ErrNotCommunityOwner
+	}
+	return id, nil
+}
+
+func toCommunityEventOutput(event *domain.ActivityEvent) CommunityEventOutput {
+	out := CommunityEventOutput{
+		ID:         event.ID().String(),
+		EventType:  event.EventType().String(),
+		Weight:     event.Weight().Value(),
+		Metadata:   event.Metadata(),
+		OccurredAt: event.OccurredAt(),
+		CreatedAt:  event.CreatedAt(),
+	}
+	if userID := event.UserID(); userID != nil {
+		out.UserID = userID.String()
+	}
+	return out
+}
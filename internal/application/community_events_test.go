@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// recordingEvents remembers what FindByCommunity was asked for and finds nothing.
+type recordingEvents struct {
+	domain.ActivityEventRepository
+	types []domain.EventType
+	limit int
+}
+
+func (r *recordingEvents) FindByCommunity(_ context.Context, _ domain.CommunityID, _ time.Time, types []domain.EventType, limit int) ([]*domain.ActivityEvent, error) {
+	r.types, r.limit = types, limit
+	return nil, nil
+}
+
+func TestCommunityEventsUseCase_List(t *testing.T) {
+	community := domain.NewCommunityID()
+	events := &recordingEvents{}
+	users := &memoryUsers{byExternalID: map[string]*domain.User{}}
+	uc := NewCommunityEventsUseCase(events, knownCommunities{ids: map[domain.CommunityID]bool{community: true}}, users, nil, logging.New())
+	ctx := context.Background()
+
+	if _, err := NewUserProfileUseCase(users, logging.New()).Bootstrap(ctx, BootstrapUserInput{ExternalID: "auth-1", Username: "someone"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := uc.List(ctx, ListCommunityEventsInput{CommunityID: community.String(), RequesterExternalID: "auth-1"})
+	if !errors.Is(err, ErrNotCommunityOwner) {
+		t.Fatalf("expected ErrNotCommunityOwner for someone else's community, got %v", err)
+	}
+
+	_, err = uc.List(ctx, ListCommunityEventsInput{CommunityID: community.String(), Types: []string{"post", "like"}, Admin: true})
+	if !errors.Is(err, domain.ErrInvalidEventType) {
+		t.Fatalf("expected ErrInvalidEventType, got %v", err)
+	}
+
+	output, err := uc.List(ctx, ListCommunityEventsInput{CommunityID: community.String(), Types: []string{"post", "comment"}, Limit: 1000, Admin: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(events.types, []domain.EventType{domain.EventTypePost, domain.EventTypeComment}) {
+		t.Errorf("expected the post and comment types, got %v", events.types)
+	}
+	if events.limit != maxEventsLimit {
+		t.Errorf("expected the limit capped at %d, got %d", maxEventsLimit, events.limit)
+	}
+	if output.Since.IsZero() || len(output.Events) != 0 {
+		t.Errorf("expected a default since and no events, got %+v", output)
+	}
+}
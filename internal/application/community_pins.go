@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrCommunityNotListed is returned when pinning a community that isn't on the public leaderboard.
+var ErrCommunityNotListed = errors.New("invalid community: only active public communities can be pinned")
+
+// CommunityPinUseCase lets admins pin communities to the top of the global leaderboard.
+type CommunityPinUseCase struct {
+	pins          domain.CommunityPinRepository
+	communityRepo domain.CommunityRepository
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// NewCommunityPinUseCase creates a new CommunityPinUseCase.
+func NewCommunityPinUseCase(
+	pins domain.CommunityPinRepository,
+	communityRepo domain.CommunityRepository,
+	logger *logging.Logger,
+) *CommunityPinUseCase {
+	return &CommunityPinUseCase{
+		pins:          pins,
+		communityRepo: communityRepo,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("community_pins"),
+	}
+}
+
+// PinCommunityInput schedules a pin.
+type PinCommunityInput struct {
+	CommunityID string
+	StartsAt    *time.Time // optional, now if not provided
+	EndsAt      time.Time
+	Sponsored   bool
+}
+
+// Pin schedules a community to be pinned, at most domain.MaxPinnedCommunities at a time.
+func (uc *CommunityPinUseCase) Pin(ctx context.Context, input PinCommunityInput) (*domain.CommunityPin, error) {
+	communityID, err := domain.ParseCommunityID(input.CommunityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	// pinning a private community would list it publicly
+	if !community.IsActive() || !community.Visibility().IsListed() {
+		return nil, ErrCommunityNotListed
+	}
+
+	now := uc.clock.Now()
+	startsAt := now
+	if input.StartsAt != nil {
+		startsAt = *input.StartsAt
+	}
+	pin, err := domain.NewCommunityPin(uc.clock, communityID, startsAt, input.EndsAt, input.Sponsored)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled, err := uc.pins.ListEndingAfter(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("listing pins: %w", err)
+	}
+	if err := domain.CheckPinCapacity(scheduled, pin); err != nil {
+		return nil, err
+	}
+
+	if err := uc.pins.Save(ctx, pin); err != nil {
+		return nil, fmt.Errorf("saving pin: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("community pinned",
+		"community_id", communityID.String(),
+		"pin_id", pin.ID().String(),
+		"starts_at", pin.StartsAt(),
+		"ends_at", pin.EndsAt(),
+		"sponsored", pin.Sponsored(),
+	)
+	return pin, nil
+}
+
+// List returns the pins that are active or scheduled, by start time.
+func (uc *CommunityPinUseCase) List(ctx context.Context) ([]*domain.CommunityPin, error) {
+	pins, err := uc.pins.ListEndingAfter(ctx, uc.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("listing pins: %w", err)
+	}
+	return pins, nil
+}
+
+// Unpin removes a pin, whether it already started or not.
+func (uc *CommunityPinUseCase) Unpin(ctx context.Context, id string) error {
+	pinID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid pin id: %w", err)
+	}
+	if err := uc.pins.Delete(ctx, pinID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("pin %s not found", id)
+		}
+		return fmt.Errorf("deleting pin: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("community unpinned", "pin_id", pinID.String())
+	return nil
+}
@@ -0,0 +1,277 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// CommunityStatsUseCase reports a community's momentum and recent activity.
+// private communities only report to their members.
+type CommunityStatsUseCase struct {
+	communityRepo domain.CommunityRepository
+	eventRepo     domain.ActivityEventRepository
+	userRepo      domain.UserRepository
+	access        *CommunityAccess
+	contributors  ContributorCounter
+	percentiles   PercentileReader
+	history       domain.MomentumHistoryRepository
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// ContributorCounter approximates the distinct users behind a community's events.
+// implemented by the redis client with HyperLogLogs filled in by the ingestion worker.
+type ContributorCounter interface {
+	UniqueContributors(ctx context.Context, communityID domain.CommunityID, since, now time.Time) (int64, error)
+}
+
+// CommunityStatsOption configures a CommunityStatsUseCase at construction.
+type CommunityStatsOption func(*CommunityStatsUseCase)
+
+// WithUniqueContributors adds approximate unique contributor counts to the stats.
+func WithUniqueContributors(counter ContributorCounter) CommunityStatsOption {
+	return func(uc *CommunityStatsUseCase) {
+		uc.contributors = counter
+	}
+}
+
+// WithMomentumPercentile adds where the community's momentum ranks among the listed communities.
+func WithMomentumPercentile(reader PercentileReader) CommunityStatsOption {
+	return func(uc *CommunityStatsUseCase) {
+		uc.percentiles = reader
+	}
+}
+
+// WithMomentumHistoryReader serves a community's momentum history, see History.
+func WithMomentumHistoryReader(history domain.MomentumHistoryRepository) CommunityStatsOption {
+	return func(uc *CommunityStatsUseCase) {
+		uc.history = history
+	}
+}
+
+// NewCommunityStatsUseCase creates a new CommunityStatsUseCase.
+func NewCommunityStatsUseCase(
+	communityRepo domain.CommunityRepository,
+	eventRepo domain.ActivityEventRepository,
+	userRepo domain.UserRepository,
+	access *CommunityAccess,
+	logger *logging.Logger,
+	opts ...CommunityStatsOption,
+) *CommunityStatsUseCase {
+	uc := &CommunityStatsUseCase{
+		communityRepo: communityRepo,
+		eventRepo:     eventRepo,
+		userRepo:      userRepo,
+		access:        access,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("community_stats"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// CommunityStatsOutput describes a community's momentum and recent activity.
+type CommunityStatsOutput struct {
+	CommunityID       string
+	Visibility        domain.CommunityVisibility
+	Momentum          float64
+	MomentumUpdatedAt *time.Time
+
+	// MomentumPercentile is the share of listed communities at or below this one's momentum.
+	// nil when it can't be read.
+	MomentumPercentile *float64
+
+	// EventsLastDay and EventsLastWeek count events in the trailing 24 hours and 7 days.
+	EventsLastDay  int64
+	EventsLastWeek int64
+
+	// UniqueUsersLastDay and UniqueUsersLastWeek approximate the distinct users behind those events.
+	// nil when they can't be counted.
+	UniqueUsersLastDay  *int64
+	UniqueUsersLastWeek *int64
+}
+
+// Execute returns a community's stats. requesterExternalID is empty for anonymous callers.
+func (uc *CommunityStatsUseCase) Execute(ctx context.Context, communityID, requesterExternalID string) (*CommunityStatsOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorizeView(ctx, community, requesterExternalID); err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	lastDay, err := uc.eventRepo.CountByCommunity(ctx, id, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("counting events: %w", err)
+	}
+	lastWeek, err := uc.eventRepo.CountByCommunity(ctx, id, now.Add(-7*24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("counting events: %w", err)
+	}
+
+	output := &CommunityStatsOutput{
+		CommunityID:       community.ID().String(),
+		Visibility:        community.Visibility(),
+		Momentum:          community.CurrentMomentum().Value(),
+		MomentumUpdatedAt: community.MomentumUpdatedAt(),
+		EventsLastDay:     lastDay,
+		EventsLastWeek:    lastWeek,
+	}
+
+	// unique counts are best effort, the rest of the stats don't depend on redis
+	if uc.contributors != nil {
+		output.UniqueUsersLastDay = uc.uniqueContributors(ctx, id, now.Add(-24*time.Hour), now)
+		output.UniqueUsersLastWeek = uc.uniqueContributors(ctx, id, now.Add(-7*24*time.Hour), now)
+	}
+
+	if uc.percentiles != nil {
+		percentile, err := uc.percentiles.MomentumPercentile(ctx, output.Momentum)
+		if err != nil {
+			uc.logger.WithContext(ctx).Warn("momentum percentile lookup failed", "error", err.Error())
+		} else {
+			output.MomentumPercentile = &percentile
+		}
+	}
+
+	return output, nil
+}
+
+// ErrMomentumHistoryDisabled is returned by History without WithMomentumHistoryReader.
+var ErrMomentumHistoryDisabled = errors.New("momentum history is not enabled")
+
+// MomentumHistoryOutput is a community's momentum over a window, in buckets for charting.
+type MomentumHistoryOutput struct {
+	CommunityID string
+	Window      domain.MomentumHistoryWindow
+	Since       time.Time
+	Buckets     []domain.MomentumBucket
+}
+
+// History returns a community's momentum over a window, like "24h". private communities
+// only answer their members, like Execute.
+func (uc *CommunityStatsUseCase) History(ctx context.Context, communityID, window, requesterExternalID string) (*MomentumHistoryOutput, error) {
+	if uc.history == nil {
+		return nil, ErrMomentumHistoryDisabled
+	}
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	w, err := domain.ParseMomentumHistoryWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorizeView(ctx, community, requesterExternalID); err != nil {
+		return nil, err
+	}
+
+	// start on a bucket boundary, so the first bucket isn't a partial one
+	since := uc.clock.Now().Add(-w.Duration()).Truncate(w.Bucket())
+	buckets, err := uc.history.Buckets(ctx, id, since, w.Bucket())
+	if err != nil {
+		return nil, fmt.Errorf("reading momentum history: %w", err)
+	}
+
+	return &MomentumHistoryOutput{
+		CommunityID: community.ID().String(),
+		Window:      w,
+		Since:       since,
+		Buckets:     buckets,
+	}, nil
+}
+
+// LiveMomentumOutput is where a community's momentum stands when a live feed starts.
+type LiveMomentumOutput struct {
+	CommunityID       domain.CommunityID
+	Momentum          float64
+	MomentumUpdatedAt *time.Time
+}
+
+// Live checks that the requester may follow a community's momentum and returns its current
+// value, for live feeds that push every update after it. private communities only answer
+// their members, like Execute.
+func (uc *CommunityStatsUseCase) Live(ctx context.Context, communityID, requesterExternalID string) (*LiveMomentumOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorizeView(ctx, community, requesterExternalID); err != nil {
+		return nil, err
+	}
+
+	return &LiveMomentumOutput{
+		CommunityID:       community.ID(),
+		Momentum:          community.CurrentMomentum().Value(),
+		MomentumUpdatedAt: community.MomentumUpdatedAt(),
+	}, nil
+}
+
+// uniqueContributors counts distinct users since a time, nil if the count failed.
+func (uc *CommunityStatsUseCase) uniqueContributors(ctx context.Context, id domain.CommunityID, since, now time.Time) *int64 {
+	count, err := uc.contributors.UniqueContributors(ctx, id, since, now)
+	if err != nil {
+		uc.logger.WithContext(ctx).Warn("counting unique contributors failed", "error", err.Error())
+		return nil
+	}
+	return &count
+}
+
+// authorizeView checks the requester may see the community. private communities only
+// answer their members; the requester is only looked up for those.
+func (uc *CommunityStatsUseCase) authorizeView(ctx context.Context, community *domain.Community, requesterExternalID string) error {
+	if !community.IsPrivate() {
+		return nil
+	}
+	requester, err := uc.requester(ctx, requesterExternalID)
+	if err != nil {
+		return err
+	}
+	return uc.access.CheckView(ctx, community, requester)
+}
+
+// requester resolves the caller's user id, nil for anonymous callers or callers without a profile.
+func (uc *CommunityStatsUseCase) requester(ctx context.Context, externalID string) (*domain.UserID, error) {
+	if externalID == "" {
+		return nil, nil
+	}
+	user, err := uc.userRepo.FindByExternalID(ctx, externalID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	id := user.ID()
+	return &id, nil
+}
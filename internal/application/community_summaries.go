@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// CommunitySummaryUseCase rolls up the member counts and recent activity of communities,
+// and serves them to listings, which would otherwise count them on every request.
+type CommunitySummaryUseCase struct {
+	summaryRepo domain.CommunitySummaryRepository
+	clock       domain.Clock
+	logger      *logging.Logger
+}
+
+// NewCommunitySummaryUseCase creates a new CommunitySummaryUseCase.
+func NewCommunitySummaryUseCase(summaryRepo domain.CommunitySummaryRepository, logger *logging.Logger) *CommunitySummaryUseCase {
+	return &CommunitySummaryUseCase{
+		summaryRepo: summaryRepo,
+		clock:       domain.SystemClock,
+		logger:      logger.WithComponent("community_summaries"),
+	}
+}
+
+// Refresh rolls up every community's summary, returning how many were written.
+func (uc *CommunitySummaryUseCase) Refresh(ctx context.Context) (int64, error) {
+	start := uc.clock.Now()
+	refreshed, err := uc.summaryRepo.Refresh(ctx, start)
+	if err != nil {
+		uc.logger.Error("community summary rollup failed",
+			"error", err.Error(),
+		)
+		return 0, fmt.Errorf("refreshing community summaries: %w", err)
+	}
+
+	uc.logger.Debug("community summaries refreshed",
+		"communities", refreshed,
+		"duration_ms", uc.clock.Now().Sub(start).Milliseconds(),
+	)
+	return refreshed, nil
+}
+
+// ForCommunities returns the summaries of the listed communities that have one.
+// a failed lookup is logged and returns none, listings are served without summaries.
+func (uc *CommunitySummaryUseCase) ForCommunities(ctx context.Context, communities []*domain.Community) map[domain.CommunityID]*domain.CommunitySummary {
+	if len(communities) == 0 {
+		return nil
+	}
+	ids := make([]domain.CommunityID, len(communities))
+	for i, c := range communities {
+		ids[i] = c.ID()
+	}
+
+	summaries, err := uc.summaryRepo.FindByCommunityIDs(ctx, ids)
+	if err != nil {
+		uc.logger.WithContext(ctx).Warn("community summary lookup failed",
+			"communities", len(ids),
+			"error", err.Error(),
+		)
+		return nil
+	}
+	return summaries
+}
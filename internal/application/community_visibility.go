@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// CommunityVisibilityUseCase lets community owners change who can find and see their community.
+type CommunityVisibilityUseCase struct {
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	leaderboard   LeaderboardUpdater
+	logger        *logging.Logger
+}
+
+// CommunityVisibilityOption configures a CommunityVisibilityUseCase at construction.
+type CommunityVisibilityOption func(*CommunityVisibilityUseCase)
+
+// WithVisibilityOrganizationAdmins lets owners and admins of a community's organization
+// change its visibility, not only the creator.
+func WithVisibilityOrganizationAdmins(repo domain.OrganizationRepository) CommunityVisibilityOption {
+	return func(uc *CommunityVisibilityUseCase) {
+		uc.orgRepo = repo
+	}
+}
+
+// WithVisibilityLeaderboard removes communities from the leaderboard cache as soon as
+// they stop being public. communities made public are listed on the next momentum cycle.
+func WithVisibilityLeaderboard(lb LeaderboardUpdater) CommunityVisibilityOption {
+	return func(uc *CommunityVisibilityUseCase) {
+		uc.leaderboard = lb
+	}
+}
+
+// NewCommunityVisibilityUseCase creates a new CommunityVisibilityUseCase.
+func NewCommunityVisibilityUseCase(
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+	opts ...CommunityVisibilityOption,
+) *CommunityVisibilityUseCase {
+	uc := &CommunityVisibilityUseCase{
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		logger:        logger.WithComponent("community_visibility"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Update changes a community's visibility.
+// ingestion sees the change once its community cache entry expires.
+func (uc *CommunityVisibilityUseCase) Update(ctx context.Context, communityID, visibility, requesterExternalID string) (*domain.Community, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+	log := uc.logger.WithContext(ctx)
+
+	v, err := domain.ParseCommunityVisibility(visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrNotCommunityOwner
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	if requester.ID() != community.CreatorID() {
+		orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+		if err != nil {
+			return nil, err
+		}
+		if !orgAdmin {
+			return nil, ErrNotCommunityOwner
+		}
+	}
+
+	previous := community.Visibility()
+	if previous == v {
+		return community, nil
+	}
+
+	if err := community.SetVisibility(v); err != nil {
+		return nil, err
+	}
+	if err := uc.communityRepo.Save(ctx, community); err != nil {
+		log.Error("visibility update failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving community: %w", err)
+	}
+
+	if uc.leaderboard != nil && !v.IsListed() {
+		if err := uc.leaderboard.RemoveFromLeaderboard(ctx, id.String()); err != nil {
+			// the next momentum cycle removes it too
+			log.Warn("leaderboard removal failed",
+				"error", err.Error(),
+			)
+		}
+	}
+
+	log.Info("community visibility changed",
+		"from", previous.String(),
+		"to", v.String(),
+		"requester_id", requester.ID().String(),
+	)
+	return community, nil
+}
@@ -13,20 +13,82 @@ import (
 type CreateCommunityUseCase struct {
 	communityRepo domain.CommunityRepository
 	userRepo      domain.UserRepository
+	reserved      domain.ReservedNames
+	events        EventPublisher
+	warmer        CommunityCacheWarmer
+	leaderboard   LeaderboardUpdater
+	clock         domain.Clock
 	logger        *logging.Logger
 }
 
+// CreateCommunityOption configures a CreateCommunityUseCase at construction.
+type CreateCommunityOption func(*CreateCommunityUseCase)
+
+// WithReservedCommunitySlugs rejects slugs on the blocklist, on top of the built-in one NewSlug enforces.
+func WithReservedCommunitySlugs(reserved domain.ReservedNames) CreateCommunityOption {
+	return func(uc *CreateCommunityUseCase) {
+		uc.reserved = reserved
+	}
+}
+
+// WithCommunityEvents publishes domain.CommunityCreated for every new community.
+func WithCommunityEvents(events EventPublisher) CreateCommunityOption {
+	return func(uc *CreateCommunityUseCase) {
+		uc.events = events
+	}
+}
+
+// CommunityCacheWarmer caches a community that was just created, so lookups right after
+// creation see it. implemented by cache.CommunityExistsCache.
+type CommunityCacheWarmer interface {
+	Warm(community *domain.Community)
+}
+
+// WithCreatedCommunityCache caches new communities as soon as they're saved, so events
+// sent right after creation aren't rejected by a stale existence cache.
+func WithCreatedCommunityCache(warmer CommunityCacheWarmer) CreateCommunityOption {
+	return func(uc *CreateCommunityUseCase) {
+		uc.warmer = warmer
+	}
+}
+
+// WithLeaderboardEntry adds new public communities to the leaderboard with a zero score,
+// instead of leaving them off until the next momentum cycle.
+func WithLeaderboardEntry(lb LeaderboardUpdater) CreateCommunityOption {
+	return func(uc *CreateCommunityUseCase) {
+		uc.leaderboard = lb
+	}
+}
+
 // NewCreateCommunityUseCase creates a new CreateCommunityUseCase.
 func NewCreateCommunityUseCase(
 	communityRepo domain.CommunityRepository,
 	userRepo domain.UserRepository,
 	logger *logging.Logger,
+	opts ...CreateCommunityOption,
 ) *CreateCommunityUseCase {
-	return &CreateCommunityUseCase{
+	uc := &CreateCommunityUseCase{
 		communityRepo: communityRepo,
 		userRepo:      userRepo,
+		clock:         domain.SystemClock,
 		logger:        logger,
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// parseSlug validates a new community's slug against the format and every blocklist.
+func (uc *CreateCommunityUseCase) parseSlug(s string) (domain.Slug, error) {
+	slug, err := domain.NewSlug(s)
+	if err != nil {
+		return domain.Slug{}, err
+	}
+	if uc.reserved.Contains(s) {
+		return domain.Slug{}, domain.ErrNameReserved
+	}
+	return slug, nil
 }
 
 // CreateCommunityInput contains the data needed to create a community.
@@ -40,6 +102,9 @@ type CreateCommunityInput struct {
 	// Description is optional, can be empty
 	Description string
 
+	// Visibility is public, unlisted or private; public when empty
+	Visibility string
+
 	// CreatorExternalID is the authenticated user's external ID from JWT (sub claim)
 	// this comes from the validated JWT, NOT from the request body
 	CreatorExternalID string
@@ -51,6 +116,7 @@ type CreateCommunityOutput struct {
 	Slug        string
 	Name        string
 	CreatorID   string
+	Visibility  string
 }
 
 // use case specific errors
@@ -62,16 +128,18 @@ var (
 // Execute creates a new community.
 // validates input, looks up the creator, and persists the community.
 func (uc *CreateCommunityUseCase) Execute(ctx context.Context, input CreateCommunityInput) (*CreateCommunityOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
 	// validate creator external id is provided
 	if input.CreatorExternalID == "" {
-		uc.logger.Error("create community failed: missing creator external id")
+		log.Error("create community failed: missing creator external id")
 		return nil, fmt.Errorf("creator external id is required")
 	}
 
 	// validate slug format
-	slug, err := domain.NewSlug(input.Slug)
+	slug, err := uc.parseSlug(input.Slug)
 	if err != nil {
-		uc.logger.Info("create community failed: invalid slug",
+		log.Info("create community failed: invalid slug",
 			"slug", input.Slug,
 			"error", err.Error(),
 		)
@@ -85,17 +153,29 @@ func (uc *CreateCommunityUseCase) Execute(ctx context.Context, input CreateCommu
 	if len(input.Name) > 255 {
 		return nil, domain.ErrCommunityNameTooLong
 	}
+	// checked again when it's set, here so a client error isn't logged as a failure
+	if _, err := domain.DescriptionRules.Clean(input.Description); err != nil {
+		return nil, err
+	}
+
+	visibility := domain.VisibilityPublic
+	if input.Visibility != "" {
+		visibility, err = domain.ParseCommunityVisibility(input.Visibility)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// look up the creator by external id
 	creator, err := uc.userRepo.FindByExternalID(ctx, input.CreatorExternalID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			uc.logger.Info("create community failed: creator not found",
+			log.Info("create community failed: creator not found",
 				"external_id", input.CreatorExternalID,
 			)
 			return nil, ErrCreatorNotFound
 		}
-		uc.logger.Error("create community failed: error looking up creator",
+		log.Error("create community failed: error looking up creator",
 			"external_id", input.CreatorExternalID,
 			"error", err.Error(),
 		)
@@ -105,23 +185,23 @@ func (uc *CreateCommunityUseCase) Execute(ctx context.Context, input CreateCommu
 	// check if slug already exists
 	existingCommunity, err := uc.communityRepo.FindBySlug(ctx, slug)
 	if err != nil && !errors.Is(err, domain.ErrNotFound) {
-		uc.logger.Error("create community failed: error checking slug",
+		log.Error("create community failed: error checking slug",
 			"slug", input.Slug,
 			"error", err.Error(),
 		)
 		return nil, fmt.Errorf("checking slug availability: %w", err)
 	}
 	if existingCommunity != nil {
-		uc.logger.Info("create community failed: slug already exists",
+		log.Info("create community failed: slug already exists",
 			"slug", input.Slug,
 		)
 		return nil, ErrSlugAlreadyExists
 	}
 
 	// create the community
-	community, err := domain.NewCommunity(slug, input.Name, creator.ID())
+	community, err := domain.NewCommunity(uc.clock, slug, input.Name, creator.ID())
 	if err != nil {
-		uc.logger.Error("create community failed: domain error",
+		log.Error("create community failed: domain error",
 			"error", err.Error(),
 		)
 		return nil, fmt.Errorf("creating community: %w", err)
@@ -130,7 +210,7 @@ func (uc *CreateCommunityUseCase) Execute(ctx context.Context, input CreateCommu
 	// set description if provided (uses UpdateDetails to preserve name)
 	if input.Description != "" {
 		if err := community.UpdateDetails(input.Name, input.Description, ""); err != nil {
-			uc.logger.Error("create community failed: update details error",
+			log.Error("create community failed: update details error",
 				"slug", input.Slug,
 				"error", err.Error(),
 			)
@@ -138,25 +218,97 @@ func (uc *CreateCommunityUseCase) Execute(ctx context.Context, input CreateCommu
 		}
 	}
 
+	if err := community.SetVisibility(visibility); err != nil {
+		return nil, fmt.Errorf("setting visibility: %w", err)
+	}
+
 	// persist
 	if err := uc.communityRepo.Save(ctx, community); err != nil {
-		uc.logger.Error("create community failed: save error",
+		log.Error("create community failed: save error",
 			"slug", input.Slug,
 			"error", err.Error(),
 		)
 		return nil, fmt.Errorf("saving community: %w", err)
 	}
 
-	uc.logger.Info("community created",
+	log.Info("community created",
 		"community_id", community.ID().String(),
 		"slug", community.Slug().String(),
 		"creator_id", creator.ID().String(),
+		"visibility", visibility.String(),
 	)
 
+	// read-your-writes: the community is saved, so the caches in front of it can't fail creation
+	if uc.warmer != nil {
+		uc.warmer.Warm(community)
+	}
+	if uc.leaderboard != nil && community.Visibility().IsListed() {
+		if err := uc.leaderboard.UpdateLeaderboardScore(ctx, community.ID().String(), 0); err != nil {
+			log.Warn("new community not added to leaderboard, it will be on the next momentum cycle",
+				"community_id", community.ID().String(),
+				"error", err.Error(),
+			)
+		}
+	}
+
+	if uc.events != nil {
+		uc.events.Publish(ctx, domain.CommunityCreated{
+			CommunityID: community.ID(),
+			Slug:        community.Slug().String(),
+			Visibility:  community.Visibility(),
+			CreatedAt:   community.CreatedAt(),
+		})
+	}
+
 	return &CreateCommunityOutput{
 		CommunityID: community.ID().String(),
 		Slug:        community.Slug().String(),
 		Name:        community.Name(),
 		CreatorID:   creator.ID().String(),
+		Visibility:  community.Visibility().String(),
 	}, nil
 }
+
+// SlugAvailabilityOutput tells a client whether a slug can be used for a new community.
+type SlugAvailabilityOutput struct {
+	Slug      string
+	Available bool
+
+	// Reason explains why an unavailable slug can't be used: invalid, reserved or taken.
+	Reason string
+	// Detail is the validation error for an invalid slug.
+	Detail string
+}
+
+// CheckSlug runs the slug checks Execute does, without creating anything.
+// deactivated communities keep their slug, so it stays taken.
+// an invalid slug isn't an error here, forms show the reason next to the field.
+func (uc *CreateCommunityUseCase) CheckSlug(ctx context.Context, input string) (*SlugAvailabilityOutput, error) {
+	output := &SlugAvailabilityOutput{Slug: input}
+
+	slug, err := uc.parseSlug(input)
+	if errors.Is(err, domain.ErrNameReserved) {
+		output.Reason = "reserved"
+		return output, nil
+	}
+	if err != nil {
+		output.Reason = "invalid"
+		output.Detail = err.Error()
+		return output, nil
+	}
+
+	_, err = uc.communityRepo.FindBySlug(ctx, slug)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		output.Available = true
+	case err != nil:
+		uc.logger.WithContext(ctx).Error("slug availability check failed",
+			"slug", input,
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("checking slug availability: %w", err)
+	default:
+		output.Reason = "taken"
+	}
+	return output, nil
+}
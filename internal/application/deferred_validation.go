@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// BatchUserChecker checks many users' existence at once, so a batch costs one lookup.
+type BatchUserChecker interface {
+	ExistsBatch(ctx context.Context, ids []domain.UserID) (map[domain.UserID]bool, error)
+}
+
+// DeferredValidator runs the existence checks that ingestion skips in deferred validation mode.
+// the ingestion worker calls it before persisting a batch; events that fail are quarantined
+// instead of stored, so one bad event never fails the whole batch on a foreign key.
+type DeferredValidator struct {
+	communities CommunityChecker
+	users       BatchUserChecker
+	quarantine  domain.RejectedEventRepository
+	clock       domain.Clock
+	logger      *logging.Logger
+}
+
+// NewDeferredValidator creates a new DeferredValidator.
+// the checkers are typically the same caches the handler uses in strict mode.
+func NewDeferredValidator(
+	communities CommunityChecker,
+	users BatchUserChecker,
+	quarantine domain.RejectedEventRepository,
+	logger *logging.Logger,
+) *DeferredValidator {
+	return &DeferredValidator{
+		communities: communities,
+		users:       users,
+		quarantine:  quarantine,
+		clock:       domain.SystemClock,
+		logger:      logger.WithComponent("deferred_validation"),
+	}
+}
+
+// Validate returns the events that pass the existence checks and quarantines the rest.
+// a failed lookup fails the whole batch, since nothing can be said about its events.
+// a failed quarantine write is only logged; the valid events are still returned.
+func (v *DeferredValidator) Validate(ctx context.Context, events []*domain.ActivityEvent) ([]*domain.ActivityEvent, error) {
+	// batches are usually a handful of hot communities, so look each one up once.
+	// an empty reason means the id passed
+	communityReasons := make(map[domain.CommunityID]domain.RejectReason)
+	for _, event := range events {
+		if _, seen := communityReasons[event.CommunityID()]; seen {
+			continue
+		}
+		reason, err := v.checkCommunity(ctx, event.CommunityID())
+		if err != nil {
+			return nil, err
+		}
+		communityReasons[event.CommunityID()] = reason
+	}
+
+	// users are looked up together, only for events their community didn't already reject
+	var userIDs []domain.UserID
+	seen := make(map[domain.UserID]bool)
+	for _, event := range events {
+		if communityReasons[event.CommunityID()] != "" || event.UserID() == nil || seen[*event.UserID()] {
+			continue
+		}
+		seen[*event.UserID()] = true
+		userIDs = append(userIDs, *event.UserID())
+	}
+	var users map[domain.UserID]bool
+	if len(userIDs) > 0 {
+		var err error
+		if users, err = v.users.ExistsBatch(ctx, userIDs); err != nil {
+			return nil, fmt.Errorf("user lookup: %w", err)
+		}
+	}
+
+	valid := make([]*domain.ActivityEvent, 0, len(events))
+	var rejected []*domain.RejectedEvent
+	for _, event := range events {
+		reason := communityReasons[event.CommunityID()]
+		if reason == "" && event.UserID() != nil && !users[*event.UserID()] {
+			reason = domain.RejectUserNotFound
+		}
+		if reason == "" {
+			valid = append(valid, event)
+			continue
+		}
+		rejected = append(rejected, domain.NewRejectedEvent(v.clock, event, reason))
+	}
+
+	if len(rejected) > 0 {
+		if err := v.quarantine.SaveBatch(ctx, rejected); err != nil {
+			v.logger.Error("saving rejected events failed",
+				"rejected", len(rejected),
+				"error", err.Error(),
+			)
+		} else {
+			v.logger.Warn("events quarantined",
+				"rejected", len(rejected),
+				"batch_size", len(events),
+				"outcome", "rejected",
+			)
+		}
+	}
+	return valid, nil
+}
+
+// checkCommunity returns why the community's events must be rejected, or an empty reason.
+func (v *DeferredValidator) checkCommunity(ctx context.Context, communityID domain.CommunityID) (domain.RejectReason, error) {
+	exists, isActive, err := v.communities.CheckActive(ctx, communityID)
+	if err != nil {
+		return "", fmt.Errorf("community check: %w", err)
+	}
+	switch {
+	case !exists:
+		return domain.RejectCommunityNotFound, nil
+	case !isActive:
+		return domain.RejectCommunityInactive, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// fakeCommunities reports whether each known community is active; unknown ones don't exist.
+type fakeCommunities map[domain.CommunityID]bool
+
+func (f fakeCommunities) CheckActive(_ context.Context, id domain.CommunityID) (bool, bool, error) {
+	active, ok := f[id]
+	return ok, active, nil
+}
+
+type fakeUsers map[domain.UserID]bool
+
+func (f fakeUsers) ExistsBatch(_ context.Context, ids []domain.UserID) (map[domain.UserID]bool, error) {
+	found := make(map[domain.UserID]bool, len(ids))
+	for _, id := range ids {
+		found[id] = f[id]
+	}
+	return found, nil
+}
+
+type fakeQuarantine struct{ saved []*domain.RejectedEvent }
+
+func (f *fakeQuarantine) SaveBatch(_ context.Context, rejected []*domain.RejectedEvent) error {
+	f.saved = append(f.saved, rejected...)
+	return nil
+}
+
+func (f *fakeQuarantine) List(context.Context, int, int) ([]*domain.RejectedEvent, error) {
+	return f.saved, nil
+}
+
+func TestDeferredValidator_Validate(t *testing.T) {
+	active, inactive, missing := domain.NewCommunityID(), domain.NewCommunityID(), domain.NewCommunityID()
+	known, unknown := domain.NewUserID(), domain.NewUserID()
+
+	newEvent := func(communityID domain.CommunityID, userID *domain.UserID) *domain.ActivityEvent {
+		event, err := domain.NewActivityEventWithDefaultWeight(domain.SystemClock, communityID, userID, domain.EventTypeView, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return event
+	}
+	anonymous := newEvent(active, nil)
+	member := newEvent(active, &known)
+	stranger := newEvent(active, &unknown)
+	closed := newEvent(inactive, &known)
+	ghost := newEvent(missing, nil)
+
+	quarantine := &fakeQuarantine{}
+	v := NewDeferredValidator(
+		fakeCommunities{active: true, inactive: false},
+		fakeUsers{known: true},
+		quarantine,
+		logging.New(),
+	)
+
+	valid, err := v.Validate(context.Background(), []*domain.ActivityEvent{anonymous, member, stranger, closed, ghost})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(valid) != 2 || valid[0] != anonymous || valid[1] != member {
+		t.Fatalf("expected the anonymous and member events to pass, got %d events", len(valid))
+	}
+
+	want := map[*domain.ActivityEvent]domain.RejectReason{
+		stranger: domain.RejectUserNotFound,
+		closed:   domain.RejectCommunityInactive,
+		ghost:    domain.RejectCommunityNotFound,
+	}
+	if len(quarantine.saved) != len(want) {
+		t.Fatalf("expected %d quarantined events, got %d", len(want), len(quarantine.saved))
+	}
+	for _, rej := range quarantine.saved {
+		if reason := want[rej.Event()]; rej.Reason() != reason {
+			t.Errorf("event %s: reason %q, want %q", rej.Event().ID(), rej.Reason(), reason)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package application
+
+import (
+	"context"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// EventPublisher publishes domain events to whoever subscribed to them, like the webhook
+// worker or the caches. implemented by the event bus in infrastructure.
+// publishing never fails the use case: subscribers react on their own terms.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.DomainEvent)
+}
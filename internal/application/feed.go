@@ -0,0 +1,320 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// FeedActivityReader aggregates recent activity for the feed. implemented by the event repository.
+type FeedActivityReader interface {
+	// CommunityVelocities returns active public communities whose weighted activity since windowStart
+	// grew over the period from previousStart to windowStart, fastest growing first.
+	CommunityVelocities(ctx context.Context, previousStart, windowStart time.Time, limit int) ([]domain.CommunityVelocity, error)
+
+	// UserParticipation returns the communities a user sent events to since a time, most recent first.
+	UserParticipation(ctx context.Context, userID domain.UserID, since time.Time, limit int) ([]domain.CommunityParticipation, error)
+}
+
+// NewCommunityLister lists recently created communities. implemented by the community repository.
+type NewCommunityLister interface {
+	// ListNewPublic returns active public communities created since a time, newest first.
+	ListNewPublic(ctx context.Context, since time.Time, limit int) ([]*domain.Community, error)
+}
+
+// FeedCache keeps computed feeds for a short time. implemented by the redis client.
+type FeedCache interface {
+	// GetFeed returns a cached feed, with ok false on a miss.
+	GetFeed(ctx context.Context, key string) (entries []domain.FeedEntry, ok bool, err error)
+
+	// SetFeed caches a feed for ttl.
+	SetFeed(ctx context.Context, key string, entries []domain.FeedEntry, ttl time.Duration) error
+}
+
+// FeedConfig tunes how the feed is built.
+type FeedConfig struct {
+	// VelocityWindow is the recent window movers are measured over, against the one before it.
+	VelocityWindow time.Duration
+
+	// NewCommunityAge is how recently a community must have been created to count as new.
+	NewCommunityAge time.Duration
+
+	// ParticipationAge is how far back a user's events make a community familiar.
+	ParticipationAge time.Duration
+
+	// HalfLife is how fast new and familiar communities fade from the feed.
+	HalfLife time.Duration
+
+	// PerReason caps the candidates of each reason.
+	PerReason int
+
+	// CacheTTL is how long a user's feed is cached.
+	CacheTTL time.Duration
+}
+
+// DefaultFeedConfig returns sensible defaults.
+func DefaultFeedConfig() FeedConfig {
+	return FeedConfig{
+		VelocityWindow:   time.Hour,
+		NewCommunityAge:  7 * 24 * time.Hour,
+		ParticipationAge: 30 * 24 * time.Hour,
+		HalfLife:         24 * time.Hour,
+		PerReason:        50,
+		CacheTTL:         time.Minute,
+	}
+}
+
+// maxFeedSize is the most entries a feed holds, and so the largest page.
+const maxFeedSize = 100
+
+// anonymousFeedKey is the cache key of the feed shared by anonymous callers.
+const anonymousFeedKey = "anonymous"
+
+// FeedUseCase builds the "hot now" discovery feed: the fastest growing public communities,
+// new ones, and ones the caller has been active in, mixed into one time-decayed ranking.
+type FeedUseCase struct {
+	communityRepo domain.CommunityRepository
+	newCommunity  NewCommunityLister
+	activity      FeedActivityReader
+	userRepo      domain.UserRepository
+	access        *CommunityAccess
+	cache         FeedCache
+	config        FeedConfig
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// FeedOption configures a FeedUseCase at construction.
+type FeedOption func(*FeedUseCase)
+
+// WithFeedCache caches each user's feed for the configured TTL.
+func WithFeedCache(cache FeedCache) FeedOption {
+	return func(uc *FeedUseCase) {
+		uc.cache = cache
+	}
+}
+
+// NewFeedUseCase creates a new FeedUseCase.
+func NewFeedUseCase(
+	communityRepo domain.CommunityRepository,
+	newCommunity NewCommunityLister,
+	activity FeedActivityReader,
+	userRepo domain.UserRepository,
+	access *CommunityAccess,
+	config FeedConfig,
+	logger *logging.Logger,
+	opts ...FeedOption,
+) *FeedUseCase {
+	uc := &FeedUseCase{
+		communityRepo: communityRepo,
+		newCommunity:  newCommunity,
+		activity:      activity,
+		userRepo:      userRepo,
+		access:        access,
+		config:        config,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("feed"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// FeedItem is one community in the feed.
+type FeedItem struct {
+	Community *domain.Community
+	Reasons   []domain.FeedReason
+	Score     float64
+}
+
+// Execute returns the first limit entries of the caller's feed.
+// requesterExternalID is empty for anonymous callers, who get no familiar communities.
+func (uc *FeedUseCase) Execute(ctx context.Context, requesterExternalID string, limit int) ([]FeedItem, error) {
+	requester, err := uc.requester(ctx, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := anonymousFeedKey
+	if requester != nil {
+		key = requester.String()
+	}
+
+	entries, err := uc.entries(ctx, key, requester)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if len(entries) == 0 {
+		return []FeedItem{}, nil
+	}
+
+	// communities are loaded fresh, so momentum is current even when the ranking is cached
+	ids := make([]domain.CommunityID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.CommunityID
+	}
+	communities, err := uc.communityRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading communities: %w", err)
+	}
+	byID := make(map[domain.CommunityID]*domain.Community, len(communities))
+	for _, c := range communities {
+		byID[c.ID()] = c
+	}
+
+	items := make([]FeedItem, 0, len(entries))
+	for _, e := range entries {
+		c, ok := byID[e.CommunityID]
+		if !ok || !c.IsActive() {
+			continue
+		}
+		items = append(items, FeedItem{
+			Community: c,
+			Reasons:   e.Reasons,
+			Score:     e.Score,
+		})
+	}
+	return items, nil
+}
+
+// entries returns the ranked feed, from the cache when it's fresh.
+func (uc *FeedUseCase) entries(ctx context.Context, key string, requester *domain.UserID) ([]domain.FeedEntry, error) {
+	log := uc.logger.WithContext(ctx)
+
+	if uc.cache != nil {
+		entries, ok, err := uc.cache.GetFeed(ctx, key)
+		if err != nil {
+			log.Warn("feed cache read failed", "error", err.Error())
+		} else if ok {
+			return entries, nil
+		}
+	}
+
+	entries, err := uc.build(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.cache != nil {
+		if err := uc.cache.SetFeed(ctx, key, entries, uc.config.CacheTTL); err != nil {
+			log.Warn("feed cache write failed", "error", err.Error())
+		}
+	}
+	return entries, nil
+}
+
+// build collects the candidates of every reason and merges them into one ranking.
+func (uc *FeedUseCase) build(ctx context.Context, requester *domain.UserID) ([]domain.FeedEntry, error) {
+	now := uc.clock.Now()
+	cfg := uc.config
+
+	var candidates []domain.FeedCandidate
+
+	// movers score by how much weighted activity they gained
+	windowStart := now.Add(-cfg.VelocityWindow)
+	velocities, err := uc.activity.CommunityVelocities(ctx, windowStart.Add(-cfg.VelocityWindow), windowStart, cfg.PerReason)
+	if err != nil {
+		return nil, fmt.Errorf("loading movers: %w", err)
+	}
+	for _, v := range velocities {
+		candidates = append(candidates, domain.FeedCandidate{
+			CommunityID: v.CommunityID,
+			Reason:      domain.FeedReasonMover,
+			Score:       v.Delta(),
+		})
+	}
+
+	// new communities have little momentum yet, so they start from 1 and fade with age
+	fresh, err := uc.newCommunity.ListNewPublic(ctx, now.Add(-cfg.NewCommunityAge), cfg.PerReason)
+	if err != nil {
+		return nil, fmt.Errorf("loading new communities: %w", err)
+	}
+	for _, c := range fresh {
+		candidates = append(candidates, domain.FeedCandidate{
+			CommunityID: c.ID(),
+			Reason:      domain.FeedReasonNew,
+			Score:       (c.CurrentMomentum().Value() + 1) * domain.FeedDecay(now.Sub(c.CreatedAt()), cfg.HalfLife),
+		})
+	}
+
+	if requester != nil {
+		familiar, err := uc.participating(ctx, *requester, now)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, familiar...)
+	}
+
+	return domain.MergeFeed(candidates, maxFeedSize), nil
+}
+
+// participating scores the communities the user has been active in by their momentum,
+// fading with the time since the user's last event there. private ones need membership.
+func (uc *FeedUseCase) participating(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.FeedCandidate, error) {
+	cfg := uc.config
+
+	participation, err := uc.activity.UserParticipation(ctx, userID, now.Add(-cfg.ParticipationAge), cfg.PerReason)
+	if err != nil {
+		return nil, fmt.Errorf("loading participation: %w", err)
+	}
+	if len(participation) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]domain.CommunityID, len(participation))
+	for i, p := range participation {
+		ids[i] = p.CommunityID
+	}
+	communities, err := uc.communityRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading communities: %w", err)
+	}
+	byID := make(map[domain.CommunityID]*domain.Community, len(communities))
+	for _, c := range communities {
+		byID[c.ID()] = c
+	}
+
+	candidates := make([]domain.FeedCandidate, 0, len(participation))
+	for _, p := range participation {
+		c, ok := byID[p.CommunityID]
+		if !ok || !c.IsActive() {
+			continue
+		}
+		if err := uc.access.CheckView(ctx, c, &userID); err != nil {
+			if errors.Is(err, ErrCommunityPrivate) {
+				continue
+			}
+			return nil, err
+		}
+		candidates = append(candidates, domain.FeedCandidate{
+			CommunityID: c.ID(),
+			Reason:      domain.FeedReasonParticipating,
+			Score:       (c.CurrentMomentum().Value() + 1) * domain.FeedDecay(now.Sub(p.LastActiveAt), cfg.HalfLife),
+		})
+	}
+	return candidates, nil
+}
+
+// requester resolves the caller to a user id, nil for anonymous callers or users without a profile.
+func (uc *FeedUseCase) requester(ctx context.Context, externalID string) (*domain.UserID, error) {
+	if externalID == "" {
+		return nil, nil
+	}
+	user, err := uc.userRepo.FindByExternalID(ctx, externalID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	id := user.ID()
+	return &id, nil
+}
@@ -0,0 +1,534 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// defaultImportBatchSize is how many imported events are written per SaveBatch call,
+// and how often the import record's progress is saved.
+const defaultImportBatchSize = 5000
+
+var (
+	// ErrImportInterrupted is recorded on imports cut short by a shutdown.
+	ErrImportInterrupted = errors.New("import interrupted by a server shutdown, upload the remaining rows again")
+
+	// ErrImportCommunityInactive is returned when importing into a deactivated community.
+	ErrImportCommunityInactive = errors.New("community is not active")
+)
+
+// EventImportUseCase imports historical events from CSV or NDJSON files into a community,
+// tracking each upload in a domain.EventImport record.
+// imported events keep the occurred_at of their row as their creation time too, like
+// replayed archives, so they count toward momentum for when they happened and don't
+// look like a burst of new activity to anomaly detection.
+type EventImportUseCase struct {
+	imports       domain.EventImportRepository
+	eventRepo     domain.ActivityEventRepository
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	weights       domain.CommunityEventWeightsRepository
+	jobs          domain.JobRepository
+	batchSize     int
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// EventImportOption configures an EventImportUseCase at construction.
+type EventImportOption func(*EventImportUseCase)
+
+// WithImportOrganizationAdmins lets owners and admins of a community's organization
+// import into it, not only the creator.
+func WithImportOrganizationAdmins(repo domain.OrganizationRepository) EventImportOption {
+	return func(uc *EventImportUseCase) {
+		uc.orgRepo = repo
+	}
+}
+
+// WithImportEventWeights gives rows without a weight the community's weight for their
+// event type, like events ingested one by one.
+func WithImportEventWeights(repo domain.CommunityEventWeightsRepository) EventImportOption {
+	return func(uc *EventImportUseCase) {
+		uc.weights = repo
+	}
+}
+
+// WithImportJobs mirrors each import's progress on a job sharing its id, so it can be
+// followed through the jobs endpoint like any other background work.
+func WithImportJobs(repo domain.JobRepository) EventImportOption {
+	return func(uc *EventImportUseCase) {
+		uc.jobs = repo
+	}
+}
+
+// NewEventImportUseCase creates a new EventImportUseCase.
+func NewEventImportUseCase(
+	imports domain.EventImportRepository,
+	eventRepo domain.ActivityEventRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+	opts ...EventImportOption,
+) *EventImportUseCase {
+	uc := &EventImportUseCase{
+		imports:       imports,
+		eventRepo:     eventRepo,
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		batchSize:     defaultImportBatchSize,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("import_events"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// CreateEventImportInput describes an upload about to be imported.
+type CreateEventImportInput struct {
+	CommunityID string
+	Format      string
+
+	// RequesterExternalID comes from the validated JWT or api key
+	RequesterExternalID string
+
+	// OrganizationID is set for organization api keys, which only import into their organization.
+	OrganizationID string
+}
+
+// Create records a queued import into a community the requester manages.
+// the file itself is handed to Run once it's uploaded.
+func (uc *EventImportUseCase) Create(ctx context.Context, input CreateEventImportInput) (*domain.EventImport, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
+	format, err := domain.ParseEventImportFormat(input.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	communityID, err := domain.ParseCommunityID(input.CommunityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+	community, err := uc.communityRepo.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	if !community.IsActive() {
+		return nil, ErrImportCommunityInactive
+	}
+	if input.OrganizationID != "" {
+		if orgID := community.OrganizationID(); orgID == nil || orgID.String() != input.OrganizationID {
+			return nil, ErrCommunityNotInOrganization
+		}
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, input.RequesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrNotCommunityOwner
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	if requester.ID() != community.CreatorID() {
+		orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+		if err != nil {
+			return nil, err
+		}
+		if !orgAdmin {
+			log.Info("event import rejected: not owner",
+				"requester_id", requester.ID().String(),
+			)
+			return nil, ErrNotCommunityOwner
+		}
+	}
+
+	imp, err := domain.NewEventImport(uc.clock, communityID, requester.ID(), format)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.imports.Save(ctx, imp); err != nil {
+		log.Error("event import save failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving event import: %w", err)
+	}
+	uc.syncJob(ctx, imp)
+
+	log.Info("event import queued",
+		"import_id", imp.ID().String(),
+		"format", format.String(),
+	)
+	return imp, nil
+}
+
+// Get returns an import started by the requester.
+// other users' imports are reported as not found, so their ids don't leak.
+func (uc *EventImportUseCase) Get(ctx context.Context, importID, requesterExternalID string) (*domain.EventImport, error) {
+	id, err := uuid.Parse(importID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import id: %w", domain.ErrInvalidInput)
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+
+	imp, err := uc.imports.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if imp.RequestedBy() != requester.ID() {
+		return nil, domain.ErrNotFound
+	}
+	return imp, nil
+}
+
+// Run imports the rows of source into the import's community, saving progress after
+// every batch. bad rows are skipped and recorded; a read or storage error stops the
+// import as failed, keeping the batches already written.
+func (uc *EventImportUseCase) Run(ctx context.Context, imp *domain.EventImport, source io.Reader) error {
+	ctx = logging.ContextWithCommunityID(ctx, imp.CommunityID().String())
+	log := uc.logger.WithContext(ctx).With("import_id", imp.ID().String())
+
+	imp.Start(uc.clock)
+	if err := uc.imports.Save(ctx, imp); err != nil {
+		log.Error("event import start failed",
+			"error", err.Error(),
+		)
+		return fmt.Errorf("saving event import: %w", err)
+	}
+	uc.syncJob(ctx, imp)
+
+	var weights *domain.CommunityEventWeights
+	if uc.weights != nil {
+		found, err := uc.weights.FindByCommunity(ctx, imp.CommunityID())
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return uc.finish(ctx, imp, fmt.Errorf("loading event weights: %w", err))
+		}
+		weights = found
+	}
+
+	batch := make([]*domain.ActivityEvent, 0, uc.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := uc.eventRepo.SaveBatch(ctx, batch); err != nil {
+			return fmt.Errorf("saving events: %w", err)
+		}
+		imp.AddImported(uc.clock, len(batch))
+		batch = batch[:0]
+		if err := uc.imports.Save(ctx, imp); err != nil {
+			return fmt.Errorf("saving progress: %w", err)
+		}
+		uc.syncJob(ctx, imp)
+		return nil
+	}
+
+	maxSkew := domain.DefaultEventTimeBounds().MaxSkew
+	err := decodeImportRows(imp.Format(), source, func(line int, row importRow, rowErr error) error {
+		if rowErr == nil {
+			var event *domain.ActivityEvent
+			event, rowErr = row.toEvent(imp.CommunityID(), weights, uc.clock.Now().Add(maxSkew))
+			if rowErr == nil {
+				batch = append(batch, event)
+			}
+		}
+		if rowErr != nil {
+			imp.Reject(line, rowErr)
+		}
+		if len(batch) >= uc.batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil && ctx.Err() != nil {
+		err = ErrImportInterrupted
+	}
+	return uc.finish(ctx, imp, err)
+}
+
+// Fail marks an import that never ran as failed, e.g. when its upload broke off.
+func (uc *EventImportUseCase) Fail(ctx context.Context, imp *domain.EventImport, cause error) error {
+	return uc.finish(ctx, imp, cause)
+}
+
+// finish records the outcome of an import and returns cause.
+func (uc *EventImportUseCase) finish(ctx context.Context, imp *domain.EventImport, cause error) error {
+	log := uc.logger.WithContext(ctx).With("import_id", imp.ID().String())
+
+	imp.Finish(uc.clock, cause)
+	// recorded even when the import was interrupted by its context ending
+	if err := uc.imports.Save(context.WithoutCancel(ctx), imp); err != nil {
+		log.Error("event import outcome not saved",
+			"status", imp.Status().String(),
+			"error", err.Error(),
+		)
+	}
+	uc.syncJob(context.WithoutCancel(ctx), imp)
+
+	if cause != nil {
+		log.Error("event import failed",
+			"imported", imp.Imported(),
+			"rejected", imp.Rejected(),
+			"error", cause.Error(),
+		)
+		return cause
+	}
+	log.Info("event import completed",
+		"imported", imp.Imported(),
+		"rejected", imp.Rejected(),
+	)
+	return nil
+}
+
+// syncJob mirrors the import's status and progress on its job, see WithImportJobs.
+// best effort: the import record has the details, a job that fails to update is only logged.
+func (uc *EventImportUseCase) syncJob(ctx context.Context, imp *domain.EventImport) {
+	if uc.jobs == nil {
+		return
+	}
+
+	job, err := uc.jobs.FindByID(ctx, imp.ID())
+	if errors.Is(err, domain.ErrNotFound) {
+		owner := imp.RequestedBy()
+		job, err = domain.NewJob(uc.clock, imp.ID(), domain.JobKindEventImport, &owner)
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Warn("event import job not updated",
+			"import_id", imp.ID().String(),
+			"error", err.Error(),
+		)
+		return
+	}
+
+	// rows are read as they're imported, the total is only known once the file is done
+	done, total := imp.Imported()+imp.Rejected(), int64(0)
+	if imp.Status() == domain.ImportCompleted {
+		total = done
+	}
+	job.Progress(uc.clock, done, total)
+	switch imp.Status() {
+	case domain.ImportRunning:
+		if job.Status() == domain.JobQueued {
+			job.Start(uc.clock)
+		}
+	case domain.ImportCompleted:
+		job.Finish(uc.clock, nil)
+	case domain.ImportFailed:
+		job.Finish(uc.clock, errors.New(imp.Err()))
+	}
+
+	if err := uc.jobs.Save(ctx, job); err != nil {
+		uc.logger.WithContext(ctx).Warn("event import job not updated",
+			"import_id", imp.ID().String(),
+			"error", err.Error(),
+		)
+	}
+}
+
+// importRow is one row of an import file, before validation.
+type importRow struct {
+	EventType  string         `json:"event_type"`
+	OccurredAt string         `json:"occurred_at"`
+	Weight     *float64       `json:"weight"`
+	Metadata   map[string]any `json:"metadata"`
+}
+
+// toEvent validates the row and builds its event. rows without a weight get the
+// community's weight for their type. occurred_at is required and may be as old as
+// needed, but no later than notAfter.
+func (r importRow) toEvent(communityID domain.CommunityID, weights *domain.CommunityEventWeights, notAfter time.Time) (*domain.ActivityEvent, error) {
+	eventType, err := domain.ParseEventType(r.EventType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, r.EventType)
+	}
+
+	if r.OccurredAt == "" {
+		return nil, errors.New("occurred_at is required")
+	}
+	occurredAt, err := time.Parse(time.RFC3339, r.OccurredAt)
+	if err != nil {
+		return nil, fmt.Errorf("occurred_at must be an RFC 3339 time: %q", r.OccurredAt)
+	}
+	if occurredAt.After(notAfter) {
+		return nil, domain.ErrOccurredInFuture
+	}
+
+	weight := weights.WeightFor(eventType)
+	if r.Weight != nil {
+		weight, err = domain.NewWeight(*r.Weight)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata, err := withRegion(r.Metadata, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	occurredAt = occurredAt.UTC()
+	return domain.ReconstructActivityEvent(domain.NewEventID(), communityID, nil, eventType, weight, metadata, occurredAt, occurredAt), nil
+}
+
+// decodeImportRows streams the rows of an import file to fn with their line number.
+// rows that can't be decoded are passed with their error, so the import can skip them;
+// a read error or an error from fn stops decoding.
+func decodeImportRows(format domain.EventImportFormat, r io.Reader, fn func(line int, row importRow, rowErr error) error) error {
+	switch format {
+	case domain.ImportFormatCSV:
+		return decodeCSVRows(r, fn)
+	case domain.ImportFormatNDJSON:
+		return decodeNDJSONRows(r, fn)
+	default:
+		return domain.ErrInvalidEventImportFormat
+	}
+}
+
+// decodeCSVRows reads a CSV file whose header names the columns. event_type and
+// occurred_at are required columns, weight is optional, and every other column is
+// kept as string metadata, leaving out empty cells.
+func decodeCSVRows(r io.Reader, fn func(line int, row importRow, rowErr error) error) error {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading csv header: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		// a byte order mark, as spreadsheets write, would hide the first column's name
+		columns[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+	if !containsAll(columns, "event_type", "occurred_at") {
+		return errors.New("csv header must name the event_type and occurred_at columns")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if err := fn(parseErr.Line, importRow{}, parseErr.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, rowErr := csvRow(columns, record)
+		if err := fn(line, row, rowErr); err != nil {
+			return err
+		}
+	}
+}
+
+func csvRow(columns, record []string) (importRow, error) {
+	var row importRow
+	for i, value := range record {
+		switch name := columns[i]; name {
+		case "event_type":
+			row.EventType = value
+		case "occurred_at":
+			row.OccurredAt = value
+		case "weight":
+			if value == "" {
+				continue
+			}
+			w, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return row, fmt.Errorf("weight must be a number: %q", value)
+			}
+			row.Weight = &w
+		default:
+			if value == "" || name == "" {
+				continue
+			}
+			if row.Metadata == nil {
+				row.Metadata = make(map[string]any)
+			}
+			row.Metadata[name] = value
+		}
+	}
+	return row, nil
+}
+
+func containsAll(columns []string, names ...string) bool {
+	for _, name := range names {
+		found := false
+		for _, c := range columns {
+			if c == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeNDJSONRows reads one JSON object per line, skipping blank lines.
+func decodeNDJSONRows(r io.Reader, fn func(line int, row importRow, rowErr error) error) error {
+	scanner := bufio.NewScanner(r)
+	// metadata can make lines long, allow up to 1MB per row like archives
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Bytes()
+		if len(strings.TrimSpace(string(text))) == 0 {
+			continue
+		}
+
+		var row importRow
+		var rowErr error
+		if err := json.Unmarshal(text, &row); err != nil {
+			rowErr = fmt.Errorf("invalid json: %w", err)
+		}
+		if err := fn(line, row, rowErr); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading line %d: %w", line+1, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+func TestDecodeImportRows_CSV(t *testing.T) {
+	file := "event_type,occurred_at,weight,source\n" +
+		"post,2024-01-02T10:00:00Z,,forum\n" +
+		"view,2024-01-02T10:05:00Z,0.5,\n" +
+		"comment,2024-01-02T10:06:00Z,heavy,forum\n" +
+		"share,2024-01-02T10:07:00Z\n"
+
+	var lines []int
+	var rows []importRow
+	var rowErrs []error
+	err := decodeImportRows(domain.ImportFormatCSV, strings.NewReader(file), func(line int, row importRow, rowErr error) error {
+		lines = append(lines, line)
+		rows = append(rows, row)
+		rowErrs = append(rowErrs, rowErr)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decodeImportRows: %v", err)
+	}
+
+	if want := []int{2, 3, 4, 5}; !slices.Equal(lines, want) {
+		t.Fatalf("lines = %v, want %v", lines, want)
+	}
+	if rows[0].Metadata["source"] != "forum" || rows[0].Weight != nil {
+		t.Errorf("first row = %+v, want source metadata and no weight", rows[0])
+	}
+	if rows[1].Weight == nil || *rows[1].Weight != 0.5 || rows[1].Metadata != nil {
+		t.Errorf("second row = %+v, want weight 0.5 and no metadata", rows[1])
+	}
+	if rowErrs[2] == nil {
+		t.Error("expected a non-numeric weight to reject the row")
+	}
+	if rowErrs[3] == nil {
+		t.Error("expected a short row to be rejected")
+	}
+}
+
+func TestDecodeImportRows_CSVRequiresColumns(t *testing.T) {
+	err := decodeImportRows(domain.ImportFormatCSV, strings.NewReader("type,when\npost,now\n"), func(int, importRow, error) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected a header without event_type and occurred_at to fail the import")
+	}
+}
+
+func TestDecodeImportRows_NDJSON(t *testing.T) {
+	file := `{"event_type":"post","occurred_at":"2024-01-02T10:00:00Z","metadata":{"source":"forum"}}` + "\n" +
+		"\n" +
+		`{"event_type":` + "\n"
+
+	var lines []int
+	var rowErrs []error
+	err := decodeImportRows(domain.ImportFormatNDJSON, strings.NewReader(file), func(line int, _ importRow, rowErr error) error {
+		lines = append(lines, line)
+		rowErrs = append(rowErrs, rowErr)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decodeImportRows: %v", err)
+	}
+	if !slices.Equal(lines, []int{1, 3}) {
+		t.Fatalf("lines = %v, want [1 3], blank lines skipped", lines)
+	}
+	if rowErrs[0] != nil || rowErrs[1] == nil {
+		t.Errorf("row errors = %v, want only the truncated line rejected", rowErrs)
+	}
+}
+
+func TestImportRow_ToEvent(t *testing.T) {
+	communityID := domain.NewCommunityID()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	weights, _ := domain.NewCommunityEventWeights(domain.FixedClock(now), communityID, map[domain.EventType]float64{domain.EventTypePost: 8})
+
+	event, err := importRow{EventType: "post", OccurredAt: "2023-06-01T08:00:00+02:00"}.toEvent(communityID, weights, now)
+	if err != nil {
+		t.Fatalf("toEvent: %v", err)
+	}
+	want := time.Date(2023, 6, 1, 6, 0, 0, 0, time.UTC)
+	if !event.OccurredAt().Equal(want) || !event.CreatedAt().Equal(want) {
+		t.Errorf("occurred %v, created %v, want both %v", event.OccurredAt(), event.CreatedAt(), want)
+	}
+	if event.Weight().Value() != 8 {
+		t.Errorf("weight = %v, want the community's 8", event.Weight().Value())
+	}
+
+	heavy := 20.0
+	tests := []struct {
+		name string
+		row  importRow
+		want error
+	}{
+		{"unknown type", importRow{EventType: "upvote", OccurredAt: "2024-01-01T00:00:00Z"}, domain.ErrInvalidEventType},
+		{"future", importRow{EventType: "post", OccurredAt: "2025-03-02T00:00:00Z"}, domain.ErrOccurredInFuture},
+		{"weight out of range", importRow{EventType: "post", OccurredAt: "2024-01-01T00:00:00Z", Weight: &heavy}, domain.ErrWeightOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.row.toEvent(communityID, nil, now); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
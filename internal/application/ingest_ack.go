@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+var (
+	ErrInvalidAckLevel = errors.New("invalid ack level, must be queued or persisted")
+
+	// ErrEventNotPersisted is returned with ack=persisted when saving the event's batch failed.
+	// the event is lost and safe to send again.
+	ErrEventNotPersisted = errors.New("event was not persisted")
+
+	// ErrEventQuarantined is returned with ack=persisted when the ingestion worker moved the
+	// event to the rejected events instead of saving it.
+	ErrEventQuarantined = errors.New("event was quarantined")
+
+	// ErrEventStale is returned with ack=persisted when the event waited in the ingestion buffer
+	// past its deadline. it wraps ErrEventQuarantined. the event wasn't saved and is safe to send again.
+	ErrEventStale = fmt.Errorf("%w: stale", ErrEventQuarantined)
+
+	// ErrEventBufferFull is returned in async mode when the ingestion buffer has no room left.
+	// the event is lost and safe to send again once the buffer drains.
+	ErrEventBufferFull = errors.New("event buffer full, try again later")
+)
+
+// AckLevel is when an ingested event is acknowledged in async mode.
+type AckLevel string
+
+const (
+	// AckQueued answers once the event is in the ingestion buffer. the default.
+	AckQueued AckLevel = "queued"
+	// AckPersisted waits for the batch holding the event to be saved, up to the ack timeout.
+	AckPersisted AckLevel = "persisted"
+)
+
+// ParseAckLevel validates an ack level. empty is AckQueued.
+func ParseAckLevel(s string) (AckLevel, error) {
+	switch level := AckLevel(s); level {
+	case "":
+		return AckQueued, nil
+	case AckQueued, AckPersisted:
+		return level, nil
+	default:
+		return "", ErrInvalidAckLevel
+	}
+}
+
+// PersistenceNotifier tells waiting requests when their queued event was saved.
+// implemented by the ingestion worker.
+type PersistenceNotifier interface {
+	// AwaitPersisted returns a channel receiving nil once the event is saved, or the reason it
+	// wasn't: ErrEventNotPersisted or ErrEventQuarantined. call it before the event is queued,
+	// and stop once done waiting.
+	AwaitPersisted(id domain.EventID) (result <-chan error, stop func())
+}
+
+// WithPersistenceNotifier enables AckPersisted in async mode: requests asking for it wait up
+// to timeout for their event to be saved. without it, every event is acknowledged when queued.
+func WithPersistenceNotifier(notifier PersistenceNotifier, timeout time.Duration) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.persistence = notifier
+		uc.ackTimeout = timeout
+	}
+}
+
+// awaitPersisted waits for a queued event to be saved. it reports false when the ack timeout
+// or the request ran out first, the event is still queued then and will likely be saved.
+// timeout replaces the use case's ack timeout when positive.
+func (uc *IngestEventUseCase) awaitPersisted(ctx context.Context, result <-chan error, timeout time.Duration) (bool, error) {
+	if timeout <= 0 {
+		timeout = uc.ackTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err == nil, err
+	case <-timer.C:
+		return false, nil
+	case <-ctx.Done():
+		return false, nil
+	}
+}
@@ -2,7 +2,9 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
@@ -15,6 +17,23 @@ type IngestEventInput struct {
 	EventType   string
 	Weight      *float64       // optional, uses default if not provided
 	Metadata    map[string]any // optional
+
+	// OccurredAt is when the client says the event happened, nil for now.
+	// must be within the use case's EventTimeBounds of the server clock.
+	OccurredAt *time.Time
+
+	// Region is used when the metadata has no region, typically from a request header.
+	Region string
+
+	// OrganizationID is set for organization api keys; the community must belong to it.
+	OrganizationID string
+
+	// Ack is when a queued event is acknowledged, AckQueued when empty.
+	Ack AckLevel
+
+	// AckTimeout replaces the ack timeout with AckPersisted, for callers that can wait
+	// longer than a request, like stream consumers. 0 keeps the use case's.
+	AckTimeout time.Duration
 }
 
 // IngestEventOutput contains the result of ingesting an event.
@@ -25,6 +44,28 @@ type IngestEventOutput struct {
 	Weight      float64
 	Accepted    bool
 	Queued      bool // true if event was queued for async processing
+	Degraded    bool // true if the event was over quota and stored at minimum weight
+	Muted       bool // true if the user is muted in the community and the event was dropped
+	Persisted   bool // true once the event is saved: always in sync mode, with AckPersisted in async mode
+}
+
+// ErrLookupFailed is wrapped by errors from a store an event is checked against that couldn't
+// be reached. the event itself may be fine, so it's safe to send again.
+var ErrLookupFailed = errors.New("lookup failed")
+
+// lookupError is a failed lookup, e.g. "community lookup: connection refused".
+// it unwraps to both ErrLookupFailed and the store's error.
+type lookupError struct {
+	op  string
+	err error
+}
+
+func (e *lookupError) Error() string {
+	return e.op + ": " + e.err.Error()
+}
+
+func (e *lookupError) Unwrap() []error {
+	return []error{ErrLookupFailed, e.err}
 }
 
 // IngestEventUseCase handles the ingestion of activity events.
@@ -34,11 +75,26 @@ type IngestEventUseCase struct {
 	communityRepo    domain.CommunityRepository
 	userRepo         domain.UserRepository
 	communityChecker CommunityChecker
+	userChecker      UserChecker
+	quotas           *QuotaEnforcer
+	access           *CommunityAccess
+	sanctions        SanctionChecker
+	eventWeights     domain.CommunityEventWeightsRepository
+	timeBounds       domain.EventTimeBounds
+	clock            domain.Clock
 	logger           *logging.Logger
 
 	// async mode: if eventChan is set, events are pushed to the channel
 	// instead of being saved directly to the repository
 	eventChan chan<- *domain.ActivityEvent
+
+	// deferValidation skips the community and user existence checks in async mode,
+	// leaving them to the ingestion worker
+	deferValidation bool
+
+	// persistence lets AckPersisted requests wait for their event to be saved
+	persistence PersistenceNotifier
+	ackTimeout  time.Duration
 }
 
 // CommunityChecker abstracts community existence checks.
@@ -47,127 +103,346 @@ type CommunityChecker interface {
 	CheckActive(ctx context.Context, id domain.CommunityID) (exists bool, isActive bool, err error)
 }
 
-// NewIngestEventUseCase creates a new IngestEventUseCase in synchronous mode.
+// UserChecker abstracts user existence checks, so a cache can stand in for the repository.
+type UserChecker interface {
+	Exists(ctx context.Context, id domain.UserID) (bool, error)
+}
+
+// IngestEventOption configures an IngestEventUseCase at construction.
+type IngestEventOption func(*IngestEventUseCase)
+
+// WithEventChannel enables async mode.
+// events are pushed to the channel instead of saved directly.
+func WithEventChannel(ch chan<- *domain.ActivityEvent) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.eventChan = ch
+	}
+}
+
+// WithDeferredValidation enables fast-accept: events are checked for syntax only and
+// queued at once, and the ingestion worker checks that their community and user exist,
+// quarantining those that don't. access, sanctions and quotas are still checked here.
+// ignored in sync mode, where nothing runs the checks later.
+func WithDeferredValidation() IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.deferValidation = true
+	}
+}
+
+// WithCommunityChecker sets the community existence checker.
+// when set, uses the checker (typically a cache) instead of the repository.
+func WithCommunityChecker(checker CommunityChecker) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.communityChecker = checker
+	}
+}
+
+// WithUserChecker sets the user existence checker.
+// when set, uses the checker (typically a cache) instead of the repository.
+func WithUserChecker(checker UserChecker) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.userChecker = checker
+	}
+}
+
+// WithQuotas counts events against daily quotas, rejecting or degrading those over it.
+func WithQuotas(enforcer *QuotaEnforcer) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.quotas = enforcer
+	}
+}
+
+// WithCommunityAccess only accepts events for private communities from their members,
+// or from api keys of the organization they belong to.
+func WithCommunityAccess(access *CommunityAccess) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.access = access
+	}
+}
+
+// WithSanctions rejects events from users banned from the community
+// and drops those from muted users, so they add nothing to momentum.
+func WithSanctions(checker SanctionChecker) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.sanctions = checker
+	}
+}
+
+// WithCommunityEventWeights gives events sent without a weight their community's
+// weight for the event type, instead of the type's default weight.
+func WithCommunityEventWeights(repo domain.CommunityEventWeightsRepository) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.eventWeights = repo
+	}
+}
+
+// WithEventTimeBounds sets how far a client's occurred_at may be from the server clock.
+// defaults to domain.DefaultEventTimeBounds.
+func WithEventTimeBounds(bounds domain.EventTimeBounds) IngestEventOption {
+	return func(uc *IngestEventUseCase) {
+		uc.timeBounds = bounds
+	}
+}
+
+// NewIngestEventUseCase creates a new IngestEventUseCase.
+// synchronous unless WithEventChannel is passed. the use case is not
+// modified after construction, so it's safe to share between handlers.
 func NewIngestEventUseCase(
 	eventRepo domain.ActivityEventRepository,
 	communityRepo domain.CommunityRepository,
 	userRepo domain.UserRepository,
 	logger *logging.Logger,
+	opts ...IngestEventOption,
 ) *IngestEventUseCase {
-	return &IngestEventUseCase{
+	uc := &IngestEventUseCase{
 		eventRepo:     eventRepo,
 		communityRepo: communityRepo,
 		userRepo:      userRepo,
+		timeBounds:    domain.DefaultEventTimeBounds(),
+		clock:         domain.SystemClock,
 		logger:        logger.WithComponent("ingest_event"),
 	}
-}
-
-// WithEventChannel sets the async event channel.
-// when set, events will be pushed to the channel instead of saved directly.
-// returns the use case for chaining.
-func (uc *IngestEventUseCase) WithEventChannel(ch chan<- *domain.ActivityEvent) *IngestEventUseCase {
-	uc.eventChan = ch
+	for _, opt := range opts {
+		opt(uc)
+	}
 	return uc
 }
 
-// WithCommunityChecker sets the community existence checker.
-// when set, uses the checker (typically a cache) instead of the repository.
-func (uc *IngestEventUseCase) WithCommunityChecker(checker CommunityChecker) *IngestEventUseCase {
-	uc.communityChecker = checker
-	return uc
+// preparedEvent is an event that passed every check but the quota.
+type preparedEvent struct {
+	communityID domain.CommunityID
+	userID      *domain.UserID
+	eventType   domain.EventType
+	weight      domain.Weight
+	metadata    map[string]any
+	occurredAt  *time.Time
+
+	// muted events are acknowledged but never stored
+	muted bool
 }
 
 // Execute ingests a new activity event.
 func (uc *IngestEventUseCase) Execute(ctx context.Context, input IngestEventInput) (*IngestEventOutput, error) {
-	// parse and validate community id
-	communityID, err := domain.ParseCommunityID(input.CommunityID)
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
+	prepared, err := uc.prepare(ctx, input, uc.deferred())
 	if err != nil {
-		uc.logger.Warn("event rejected: invalid community id",
-			"community_id", input.CommunityID,
-			"reason", err.Error(),
-		)
-		return nil, fmt.Errorf("invalid community id: %w", err)
+		return nil, err
+	}
+	if prepared.muted {
+		return &IngestEventOutput{
+			CommunityID: prepared.communityID.String(),
+			EventType:   prepared.eventType.String(),
+			Accepted:    false,
+			Muted:       true,
+		}, nil
 	}
 
-	// verify community exists and is active
-	// use cache if available for high-throughput scenarios
-	var exists, isActive bool
-	if uc.communityChecker != nil {
-		exists, isActive, err = uc.communityChecker.CheckActive(ctx, communityID)
-		if err != nil {
-			uc.logger.Warn("event rejected: community check failed",
-				"community_id", communityID.String(),
-				"reason", err.Error(),
-			)
-			return nil, fmt.Errorf("community check: %w", err)
+	event, degraded, err := uc.create(ctx, prepared)
+	if err != nil {
+		return nil, err
+	}
+	communityID, eventType, weight := event.CommunityID(), event.EventType(), event.Weight()
+
+	// async mode: push to channel (non-blocking with select)
+	if uc.eventChan != nil {
+		// registered before the event is queued, a fast flush could save it before we wait
+		var persisted <-chan error
+		if input.Ack == AckPersisted && uc.persistence != nil {
+			var stop func()
+			persisted, stop = uc.persistence.AwaitPersisted(event.ID())
+			defer stop()
 		}
-	} else {
-		// fallback to direct repository lookup
-		community, err := uc.communityRepo.FindByID(ctx, communityID)
-		if err != nil {
-			uc.logger.Warn("event rejected: community lookup failed",
-				"community_id", communityID.String(),
-				"reason", err.Error(),
+
+		select {
+		case uc.eventChan <- event:
+			log.Debug("event queued",
+				"event_id", event.ID().String(),
+				"event_type", eventType.String(),
 			)
-			return nil, fmt.Errorf("community lookup: %w", err)
+		default:
+			// channel full, log warning but don't block
+			log.Warn("event buffer full, dropping event",
+				"event_id", event.ID().String(),
+			)
+			return nil, ErrEventBufferFull
 		}
-		exists = true
-		isActive = community.IsActive()
+
+		output := &IngestEventOutput{
+			EventID:     event.ID().String(),
+			CommunityID: communityID.String(),
+			EventType:   eventType.String(),
+			Weight:      weight.Value(),
+			Accepted:    true,
+			Queued:      true,
+			Degraded:    degraded,
+		}
+		if persisted != nil {
+			if output.Persisted, err = uc.awaitPersisted(ctx, persisted, input.AckTimeout); err != nil {
+				log.Warn("queued event not persisted",
+					"event_id", event.ID().String(),
+					"error", err.Error(),
+				)
+				return nil, err
+			}
+		}
+		return output, nil
 	}
 
-	if !exists {
-		uc.logger.Warn("event rejected: community not found",
-			"community_id", communityID.String(),
-			"outcome", "rejected",
+	// sync mode: persist directly
+	if err := uc.eventRepo.Save(ctx, event); err != nil {
+		log.Error("event save failed",
+			"event_id", event.ID().String(),
+			"error", err.Error(),
 		)
-		return nil, fmt.Errorf("community %s not found", communityID.String())
+		return nil, fmt.Errorf("saving event: %w", err)
 	}
-	if !isActive {
-		uc.logger.Warn("event rejected: community inactive",
-			"community_id", communityID.String(),
-			"outcome", "rejected",
+
+	log.Info("event ingested",
+		"event_id", event.ID().String(),
+		"event_type", eventType.String(),
+		"weight", weight.Value(),
+		"outcome", "accepted",
+	)
+
+	return &IngestEventOutput{
+		EventID:     event.ID().String(),
+		CommunityID: communityID.String(),
+		EventType:   eventType.String(),
+		Weight:      weight.Value(),
+		Accepted:    true,
+		Queued:      false,
+		Degraded:    degraded,
+		Persisted:   true,
+	}, nil
+}
+
+// prepare validates an event and runs the community, user, access and sanction checks,
+// the existence checks only without deferChecks.
+func (uc *IngestEventUseCase) prepare(ctx context.Context, input IngestEventInput, deferChecks bool) (*preparedEvent, error) {
+	log := uc.logger.WithContext(ctx)
+
+	// parse and validate community id
+	communityID, err := domain.ParseCommunityID(input.CommunityID)
+	if err != nil {
+		log.Warn("event rejected: invalid community id",
+			"reason", err.Error(),
 		)
-		return nil, fmt.Errorf("community %s is not active", communityID.String())
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	// verify community exists and is active, unless the worker does it later
+	if !deferChecks {
+		if err := uc.checkCommunity(ctx, communityID); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.OrganizationID != "" {
+		if err := uc.checkOrganizationScope(ctx, communityID, input.OrganizationID); err != nil {
+			log.Warn("event rejected: community outside api key organization",
+				"organization_id", input.OrganizationID,
+				"outcome", "rejected",
+			)
+			return nil, err
+		}
 	}
 
 	// parse and validate event type
 	eventType, err := domain.ParseEventType(input.EventType)
 	if err != nil {
-		uc.logger.Warn("event rejected: invalid event type",
-			"community_id", communityID.String(),
+		log.Warn("event rejected: invalid event type",
 			"event_type", input.EventType,
 			"reason", err.Error(),
 		)
 		return nil, fmt.Errorf("invalid event type: %w", err)
 	}
 
+	metadata, err := withRegion(input.Metadata, input.Region)
+	if err != nil {
+		log.Warn("event rejected: invalid region",
+			"reason", err.Error(),
+		)
+		return nil, fmt.Errorf("invalid region: %w", err)
+	}
+
+	// checked here too, so oversized or binary metadata is rejected before quotas count it
+	if err := domain.ValidateMetadata(metadata); err != nil {
+		log.Warn("event rejected: invalid metadata",
+			"reason", err.Error(),
+		)
+		return nil, err
+	}
+
 	// parse optional user id
 	var userID *domain.UserID
 	if input.UserID != nil {
 		parsed, err := domain.ParseUserID(*input.UserID)
 		if err != nil {
-			uc.logger.Warn("event rejected: invalid user id",
-				"community_id", communityID.String(),
-				"user_id", *input.UserID,
+			log.Warn("event rejected: invalid user id",
+				"event_user_id", *input.UserID,
 				"reason", err.Error(),
 			)
 			return nil, fmt.Errorf("invalid user id: %w", err)
 		}
 
-		// verify user exists
-		exists, err := uc.userRepo.Exists(ctx, parsed)
-		if err != nil {
-			return nil, fmt.Errorf("user lookup: %w", err)
+		// verify user exists, unless the worker does it later
+		if !deferChecks {
+			var checker UserChecker = uc.userRepo
+			if uc.userChecker != nil {
+				checker = uc.userChecker
+			}
+			exists, err := checker.Exists(ctx, parsed)
+			if err != nil {
+				return nil, &lookupError{op: "user lookup", err: err}
+			}
+			if !exists {
+				log.Warn("event rejected: user not found",
+					"event_user_id", parsed.String(),
+					"outcome", "rejected",
+				)
+				return nil, fmt.Errorf("user %s not found", parsed.String())
+			}
 		}
-		if !exists {
-			uc.logger.Warn("event rejected: user not found",
-				"community_id", communityID.String(),
-				"user_id", parsed.String(),
+		userID = &parsed
+	}
+
+	// organization keys already passed the scope check, so they reach private communities too
+	if uc.access != nil && input.OrganizationID == "" {
+		if err := uc.access.CheckCommunity(ctx, communityID, userID); err != nil {
+			log.Warn("event rejected: private community",
+				"reason", err.Error(),
 				"outcome", "rejected",
 			)
-			return nil, fmt.Errorf("user %s not found", parsed.String())
+			return nil, err
+		}
+	}
+
+	if uc.sanctions != nil && userID != nil {
+		muted, err := uc.checkSanctions(ctx, communityID, *userID)
+		if err != nil {
+			log.Warn("event rejected: user banned",
+				"event_user_id", userID.String(),
+				"reason", err.Error(),
+				"outcome", "rejected",
+			)
+			return nil, err
+		}
+		if muted {
+			// acknowledged so clients don't retry, but never stored
+			log.Debug("event dropped: user muted",
+				"event_user_id", userID.String(),
+				"event_type", eventType.String(),
+				"outcome", "dropped",
+			)
+			return &preparedEvent{
+				communityID: communityID,
+				userID:      userID,
+				eventType:   eventType,
+				muted:       true,
+			}, nil
 		}
-		userID = &parsed
 	}
 
 	// determine weight
@@ -175,79 +450,217 @@ func (uc *IngestEventUseCase) Execute(ctx context.Context, input IngestEventInpu
 	if input.Weight != nil {
 		weight, err = domain.NewWeight(*input.Weight)
 		if err != nil {
-			uc.logger.Warn("event rejected: invalid weight",
-				"community_id", communityID.String(),
+			log.Warn("event rejected: invalid weight",
 				"weight", *input.Weight,
 				"reason", err.Error(),
 			)
 			return nil, fmt.Errorf("invalid weight: %w", err)
 		}
 	} else {
-		weight = eventType.DefaultWeight()
+		weight = uc.defaultWeight(ctx, communityID, eventType)
+	}
+
+	if input.OccurredAt != nil {
+		if err := uc.timeBounds.Check(*input.OccurredAt, uc.clock.Now()); err != nil {
+			log.Warn("event rejected: invalid occurred_at",
+				"occurred_at", input.OccurredAt.Format(time.RFC3339),
+				"reason", err.Error(),
+			)
+			return nil, fmt.Errorf("invalid occurred_at: %w", err)
+		}
+	}
+
+	return &preparedEvent{
+		communityID: communityID,
+		userID:      userID,
+		eventType:   eventType,
+		weight:      weight,
+		metadata:    metadata,
+		occurredAt:  input.OccurredAt,
+	}, nil
+}
+
+// defaultWeight returns the weight of an event sent without one: the community's override
+// for its type, or the type's default weight. a failed lookup falls back to the default
+// rather than rejecting the event.
+func (uc *IngestEventUseCase) defaultWeight(ctx context.Context, communityID domain.CommunityID, eventType domain.EventType) domain.Weight {
+	if uc.eventWeights == nil {
+		return eventType.DefaultWeight()
+	}
+
+	weights, err := uc.eventWeights.FindByCommunity(ctx, communityID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			uc.logger.Warn("failed to load community event weights, using the default weight",
+				"community_id", communityID.String(),
+				"error", err.Error(),
+			)
+		}
+		return eventType.DefaultWeight()
+	}
+	return weights.WeightFor(eventType)
+}
+
+// create counts a prepared event against the quotas and builds it, reporting whether it
+// was degraded to the minimum weight for being over quota.
+func (uc *IngestEventUseCase) create(ctx context.Context, p *preparedEvent) (*domain.ActivityEvent, bool, error) {
+	log := uc.logger.WithContext(ctx)
+	weight := p.weight
+
+	// count against quotas last, so invalid events don't use them up
+	var degraded bool
+	if uc.quotas != nil {
+		decision, err := uc.quotas.Consume(ctx, p.communityID)
+		if err != nil {
+			log.Warn("event rejected: quota exceeded",
+				"reason", err.Error(),
+				"outcome", "rejected",
+			)
+			return nil, false, err
+		}
+		if decision.Degraded {
+			degraded = true
+			weight, _ = domain.NewWeight(domain.MinWeight)
+		}
 	}
 
 	// create the domain event
-	event, err := domain.NewActivityEvent(communityID, userID, eventType, weight, input.Metadata)
+	event, err := domain.NewActivityEvent(uc.clock, p.communityID, p.userID, p.eventType, weight, p.metadata)
 	if err != nil {
-		uc.logger.Error("event creation failed",
-			"community_id", communityID.String(),
-			"event_type", eventType.String(),
+		log.Error("event creation failed",
+			"event_type", p.eventType.String(),
 			"error", err.Error(),
 		)
-		return nil, fmt.Errorf("creating event: %w", err)
+		return nil, false, fmt.Errorf("creating event: %w", err)
 	}
+	if p.occurredAt != nil {
+		event.SetOccurredAt(*p.occurredAt)
+	}
+	return event, degraded, nil
+}
 
-	// async mode: push to channel (non-blocking with select)
-	if uc.eventChan != nil {
-		select {
-		case uc.eventChan <- event:
-			uc.logger.Debug("event queued",
-				"event_id", event.ID().String(),
-				"community_id", communityID.String(),
-				"event_type", eventType.String(),
+// checkCommunity rejects events for communities that don't exist or aren't active.
+// uses the checker (typically a cache) if available for high-throughput scenarios.
+func (uc *IngestEventUseCase) checkCommunity(ctx context.Context, communityID domain.CommunityID) error {
+	log := uc.logger.WithContext(ctx)
+
+	var (
+		exists, isActive bool
+		err              error
+	)
+	if uc.communityChecker != nil {
+		exists, isActive, err = uc.communityChecker.CheckActive(ctx, communityID)
+		if err != nil {
+			log.Warn("event rejected: community check failed",
+				"reason", err.Error(),
 			)
-			return &IngestEventOutput{
-				EventID:     event.ID().String(),
-				CommunityID: communityID.String(),
-				EventType:   eventType.String(),
-				Weight:      weight.Value(),
-				Accepted:    true,
-				Queued:      true,
-			}, nil
-		default:
-			// channel full, log warning but don't block
-			uc.logger.Warn("event buffer full, dropping event",
-				"event_id", event.ID().String(),
-				"community_id", communityID.String(),
+			return &lookupError{op: "community check", err: err}
+		}
+	} else {
+		// fallback to direct repository lookup
+		community, err := uc.communityRepo.FindByID(ctx, communityID)
+		if err != nil {
+			log.Warn("event rejected: community lookup failed",
+				"reason", err.Error(),
 			)
-			return nil, fmt.Errorf("event buffer full, try again later")
+			return &lookupError{op: "community lookup", err: err}
 		}
+		exists = true
+		isActive = community.IsActive()
 	}
 
-	// sync mode: persist directly
-	if err := uc.eventRepo.Save(ctx, event); err != nil {
-		uc.logger.Error("event save failed",
-			"community_id", communityID.String(),
-			"event_id", event.ID().String(),
-			"error", err.Error(),
+	if !exists {
+		log.Warn("event rejected: community not found",
+			"outcome", "rejected",
 		)
-		return nil, fmt.Errorf("saving event: %w", err)
+		return fmt.Errorf("community %s not found", communityID.String())
+	}
+	if !isActive {
+		log.Warn("event rejected: community inactive",
+			"outcome", "rejected",
+		)
+		return fmt.Errorf("community %s is not active", communityID.String())
 	}
+	return nil
+}
 
-	uc.logger.Info("event ingested",
-		"event_id", event.ID().String(),
-		"community_id", communityID.String(),
-		"event_type", eventType.String(),
-		"weight", weight.Value(),
-		"outcome", "accepted",
-	)
+// Async reports whether events are queued for the ingestion worker instead of saved directly.
+func (uc *IngestEventUseCase) Async() bool {
+	return uc.eventChan != nil
+}
 
-	return &IngestEventOutput{
-		EventID:     event.ID().String(),
-		CommunityID: communityID.String(),
-		EventType:   eventType.String(),
-		Weight:      weight.Value(),
-		Accepted:    true,
-		Queued:      false,
-	}, nil
+// QueueDepth reports how many events wait in the buffer and how many it holds, 0 and 0 in sync mode.
+func (uc *IngestEventUseCase) QueueDepth() (depth, capacity int) {
+	if uc.eventChan == nil {
+		return 0, 0
+	}
+	return len(uc.eventChan), cap(uc.eventChan)
+}
+
+// deferred reports whether existence checks are left to the ingestion worker.
+func (uc *IngestEventUseCase) deferred() bool {
+	return uc.deferValidation && uc.eventChan != nil
+}
+
+// checkSanctions returns ErrUserBanned for banned users and reports whether the user is muted.
+func (uc *IngestEventUseCase) checkSanctions(ctx context.Context, communityID domain.CommunityID, userID domain.UserID) (bool, error) {
+	sanctions, err := uc.sanctions.FindActive(ctx, communityID, userID)
+	if err != nil {
+		return false, &lookupError{op: "sanction lookup", err: err}
+	}
+
+	now := uc.clock.Now()
+	var muted bool
+	for _, s := range sanctions {
+		if !s.IsActiveAt(now) {
+			continue
+		}
+		switch s.Kind() {
+		case domain.SanctionBan:
+			return false, ErrUserBanned
+		case domain.SanctionMute:
+			muted = true
+		}
+	}
+	return muted, nil
+}
+
+// checkOrganizationScope rejects communities outside the api key's organization.
+// organization keys are rare on the hot path, so this reads the repository directly.
+func (uc *IngestEventUseCase) checkOrganizationScope(ctx context.Context, communityID domain.CommunityID, organizationID string) error {
+	community, err := uc.communityRepo.FindByID(ctx, communityID)
+	if err != nil {
+		return &lookupError{op: "community lookup", err: err}
+	}
+	if orgID := community.OrganizationID(); orgID == nil || orgID.String() != organizationID {
+		return ErrCommunityNotInOrganization
+	}
+	return nil
+}
+
+// withRegion returns the metadata with its region validated and normalized.
+// the metadata region wins over the fallback; neither set leaves the metadata untouched.
+func withRegion(metadata map[string]any, fallback string) (map[string]any, error) {
+	s, err := domain.Metadata(metadata).GetString(domain.MetadataRegionKey)
+	switch {
+	case errors.Is(err, domain.ErrMetadataKeyMissing):
+		if fallback == "" {
+			return metadata, nil
+		}
+		s = fallback
+	case err != nil:
+		return nil, domain.ErrRegionInvalid
+	}
+	region, err := domain.NewRegion(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// NewActivityEvent copies the map, but the caller's map must not change either
+	out := make(map[string]any, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[domain.MetadataRegionKey] = region.String()
+	return out, nil
 }
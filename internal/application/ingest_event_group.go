@@ -0,0 +1,234 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// EventGroupItem is one event of a group. the sender fields are shared, see IngestEventGroupInput.
+type EventGroupItem struct {
+	CommunityID string
+	EventType   string
+	Weight      *float64       // optional, uses default if not provided
+	Metadata    map[string]any // optional
+	OccurredAt  *time.Time     // optional, see IngestEventInput
+}
+
+// IngestEventGroupInput contains the events of one client action, like a post and a share.
+type IngestEventGroupInput struct {
+	// IdempotencyKey identifies the action, a retry with the same key isn't saved twice.
+	IdempotencyKey string
+
+	// UserID, Region and OrganizationID apply to every event, as in IngestEventInput.
+	UserID         *string
+	Region         string
+	OrganizationID string
+
+	Events []EventGroupItem
+}
+
+// IngestEventGroupOutput contains the result of ingesting an event group.
+type IngestEventGroupOutput struct {
+	// Events are the saved events in request order, without the muted ones.
+	// for a duplicate only their ids are set.
+	Events []IngestEventOutput
+
+	// Muted is how many events were dropped because the user is muted in their community.
+	Muted int
+
+	// Duplicate is true when the key was already used: nothing was saved this time and
+	// Events are the ones saved then.
+	Duplicate bool
+}
+
+// SavedEventsHandler runs what follows saving events, like metrics and metering.
+// implemented by the ingestion worker, which does the same for the events it flushes.
+type SavedEventsHandler interface {
+	EventsSaved(ctx context.Context, events []*domain.ActivityEvent)
+}
+
+// IngestEventGroupUseCase saves the events of one client action all or nothing.
+// the events pass the same checks as single events, always including the existence
+// checks, and are saved with their idempotency key in one transaction, bypassing the
+// ingestion buffer.
+type IngestEventGroupUseCase struct {
+	ingest *IngestEventUseCase
+	groups domain.EventGroupRepository
+	uow    UnitOfWork
+	saved  SavedEventsHandler
+	logger *logging.Logger
+}
+
+// IngestEventGroupOption configures an IngestEventGroupUseCase at construction.
+type IngestEventGroupOption func(*IngestEventGroupUseCase)
+
+// WithSavedEventsHandler runs the handler after every saved group.
+func WithSavedEventsHandler(handler SavedEventsHandler) IngestEventGroupOption {
+	return func(uc *IngestEventGroupUseCase) {
+		uc.saved = handler
+	}
+}
+
+// NewIngestEventGroupUseCase creates a new IngestEventGroupUseCase.
+// checks, quotas and sanctions are those of the ingest use case.
+func NewIngestEventGroupUseCase(
+	ingest *IngestEventUseCase,
+	groups domain.EventGroupRepository,
+	uow UnitOfWork,
+	logger *logging.Logger,
+	opts ...IngestEventGroupOption,
+) *IngestEventGroupUseCase {
+	uc := &IngestEventGroupUseCase{
+		ingest: ingest,
+		groups: groups,
+		uow:    uow,
+		logger: logger.WithComponent("ingest_event_group"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute ingests an event group. if any event is rejected, none is saved.
+func (uc *IngestEventGroupUseCase) Execute(ctx context.Context, input IngestEventGroupInput) (*IngestEventGroupOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
+	key, err := domain.NewIdempotencyKey(input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Events) == 0 || len(input.Events) > domain.MaxEventGroupSize {
+		return nil, domain.ErrEventGroupSize
+	}
+
+	scope := groupScope(input)
+	since := uc.ingest.clock.Now().Add(-domain.IdempotencyKeyTTL)
+
+	// a retry answers with the first attempt's events, before anything counts against quotas
+	ids, err := uc.groups.FindGroup(ctx, scope, key, since)
+	if err == nil {
+		log.Debug("event group already ingested",
+			"idempotency_key", key.String(),
+			"outcome", "duplicate",
+		)
+		return duplicateGroup(ids), nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("finding event group: %w", err)
+	}
+
+	prepared := make([]*preparedEvent, 0, len(input.Events))
+	for i, item := range input.Events {
+		p, err := uc.ingest.prepare(ctx, IngestEventInput{
+			CommunityID:    item.CommunityID,
+			UserID:         input.UserID,
+			EventType:      item.EventType,
+			Weight:         item.Weight,
+			Metadata:       item.Metadata,
+			OccurredAt:     item.OccurredAt,
+			Region:         input.Region,
+			OrganizationID: input.OrganizationID,
+		}, false)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		prepared = append(prepared, p)
+	}
+
+	// quotas are counted once every event passed its checks. a group over quota halfway
+	// still uses up what the events before it counted.
+	output := &IngestEventGroupOutput{}
+	var events []*domain.ActivityEvent
+	for i, p := range prepared {
+		if p.muted {
+			output.Muted++
+			continue
+		}
+		event, degraded, err := uc.ingest.create(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		events = append(events, event)
+		output.Events = append(output.Events, IngestEventOutput{
+			EventID:     event.ID().String(),
+			CommunityID: event.CommunityID().String(),
+			EventType:   event.EventType().String(),
+			Weight:      event.Weight().Value(),
+			Accepted:    true,
+			Degraded:    degraded,
+			Persisted:   true,
+		})
+	}
+	if len(events) == 0 {
+		return output, nil
+	}
+
+	eventIDs := make([]domain.EventID, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID()
+	}
+
+	err = RunInTransaction(ctx, uc.uow, func(ctx context.Context) error {
+		if ids, err = uc.groups.SaveGroup(ctx, scope, key, eventIDs, since); err != nil {
+			return err
+		}
+		return uc.ingest.eventRepo.SaveBatch(ctx, events)
+	})
+	if errors.Is(err, domain.ErrDuplicateEventGroup) {
+		// a concurrent request with the same key saved its events first
+		log.Debug("event group already ingested",
+			"idempotency_key", key.String(),
+			"outcome", "duplicate",
+		)
+		return duplicateGroup(ids), nil
+	}
+	if err != nil {
+		log.Error("event group save failed",
+			"group_size", len(events),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving event group: %w", err)
+	}
+
+	if uc.saved != nil {
+		uc.saved.EventsSaved(ctx, events)
+	}
+
+	log.Info("event group ingested",
+		"group_size", len(events),
+		"muted", output.Muted,
+		"outcome", "accepted",
+	)
+	return output, nil
+}
+
+// groupScope is who sent the group: the organization of an organization api key, else
+// the user, else anyone anonymous.
+func groupScope(input IngestEventGroupInput) string {
+	switch {
+	case input.OrganizationID != "":
+		return "organization:" + input.OrganizationID
+	case input.UserID != nil:
+		return "user:" + *input.UserID
+	default:
+		return "anonymous"
+	}
+}
+
+func duplicateGroup(ids []domain.EventID) *IngestEventGroupOutput {
+	output := &IngestEventGroupOutput{Duplicate: true}
+	for _, id := range ids {
+		output.Events = append(output.Events, IngestEventOutput{
+			EventID:   id.String(),
+			Accepted:  true,
+			Persisted: true,
+		})
+	}
+	return output
+}
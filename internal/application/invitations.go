@@ -0,0 +1,380 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrAlreadyCommunityMember is returned when accepting an invitation to a community you're in.
+var ErrAlreadyCommunityMember = errors.New("user is already a member of the community")
+
+// InvitationUseCase lets community managers invite members with single-use links.
+type InvitationUseCase struct {
+	invitationRepo domain.CommunityInvitationRepository
+	memberRepo     domain.CommunityMemberRepository
+	communityRepo  domain.CommunityRepository
+	userRepo       domain.UserRepository
+	orgRepo        domain.OrganizationRepository
+	ingest         *IngestEventUseCase
+	sanctions      SanctionChecker
+	clock          domain.Clock
+	logger         *logging.Logger
+}
+
+// InvitationOption configures an InvitationUseCase at construction.
+type InvitationOption func(*InvitationUseCase)
+
+// WithInvitationOrganizationAdmins lets owners and admins of a community's organization
+// manage its invitations, not only the creator.
+func WithInvitationOrganizationAdmins(repo domain.OrganizationRepository) InvitationOption {
+	return func(uc *InvitationUseCase) {
+		uc.orgRepo = repo
+	}
+}
+
+// WithJoinEvents ingests a join event for every accepted invitation.
+func WithJoinEvents(ingest *IngestEventUseCase) InvitationOption {
+	return func(uc *InvitationUseCase) {
+		uc.ingest = ingest
+	}
+}
+
+// WithInvitationBans stops users banned from a community from joining it with an invitation.
+func WithInvitationBans(checker SanctionChecker) InvitationOption {
+	return func(uc *InvitationUseCase) {
+		uc.sanctions = checker
+	}
+}
+
+// NewInvitationUseCase creates a new InvitationUseCase.
+func NewInvitationUseCase(
+	invitationRepo domain.CommunityInvitationRepository,
+	memberRepo domain.CommunityMemberRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+	opts ...InvitationOption,
+) *InvitationUseCase {
+	uc := &InvitationUseCase{
+		invitationRepo: invitationRepo,
+		memberRepo:     memberRepo,
+		communityRepo:  communityRepo,
+		userRepo:       userRepo,
+		clock:          domain.SystemClock,
+		logger:         logger.WithComponent("invitations"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// InvitationOutput describes an invitation without its token.
+type InvitationOutput struct {
+	ID          string
+	CommunityID string
+	CreatedBy   string
+	Hint        string
+	Status      string
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	AcceptedBy  *string
+	AcceptedAt  *time.Time
+}
+
+// CreateInvitationInput contains the data needed to invite someone.
+type CreateInvitationInput struct {
+	CommunityID string
+	// TTL is how long the invitation is valid, domain.DefaultInvitationTTL if zero.
+	TTL                 time.Duration
+	RequesterExternalID string
+}
+
+// CreateInvitationOutput includes the token, shown only once.
+type CreateInvitationOutput struct {
+	InvitationOutput
+	Token string
+}
+
+// AcceptInvitationOutput describes the membership an invitation created.
+type AcceptInvitationOutput struct {
+	CommunityID string
+	UserID      string
+	JoinedAt    time.Time
+}
+
+// CommunityMemberOutput describes a community member.
+type CommunityMemberOutput struct {
+	UserID   string
+	JoinedAt time.Time
+}
+
+// Create issues a new invitation for a community the requester manages.
+func (uc *InvitationUseCase) Create(ctx context.Context, input CreateInvitationInput) (*CreateInvitationOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+
+	community, requester, err := uc.authorizeManager(ctx, input.CommunityID, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := input.TTL
+	if ttl == 0 {
+		ttl = domain.DefaultInvitationTTL
+	}
+
+	invitation, token, err := domain.NewCommunityInvitation(uc.clock, community.ID(), requester.ID(), ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("saving invitation: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("invitation created",
+		"invitation_id", invitation.ID().String(),
+		"expires_at", invitation.ExpiresAt(),
+	)
+
+	return &CreateInvitationOutput{
+		InvitationOutput: toInvitationOutput(invitation),
+		Token:            token,
+	}, nil
+}
+
+// List returns a community's invitations, newest first.
+func (uc *InvitationUseCase) List(ctx context.Context, communityID, requesterExternalID string) ([]InvitationOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	community, _, err := uc.authorizeManager(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := uc.invitationRepo.ListByCommunity(ctx, community.ID())
+	if err != nil {
+		return nil, fmt.Errorf("listing invitations: %w", err)
+	}
+
+	out := make([]InvitationOutput, len(invitations))
+	for i, inv := range invitations {
+		out[i] = toInvitationOutput(inv)
+	}
+	return out, nil
+}
+
+// Revoke stops a pending invitation from being accepted.
+func (uc *InvitationUseCase) Revoke(ctx context.Context, communityID, invitationID, requesterExternalID string) error {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	community, _, err := uc.authorizeManager(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(invitationID)
+	if err != nil {
+		return fmt.Errorf("invalid invitation id: %w", err)
+	}
+
+	invitation, err := uc.invitationRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if invitation.CommunityID() != community.ID() {
+		return domain.ErrNotFound
+	}
+
+	if err := invitation.Revoke(); err != nil {
+		return err
+	}
+	if err := uc.invitationRepo.Revoke(ctx, invitation); err != nil {
+		return err
+	}
+
+	uc.logger.WithContext(ctx).Info("invitation revoked",
+		"invitation_id", invitation.ID().String(),
+	)
+	return nil
+}
+
+// Accept makes the requester a member of the invitation's community.
+// the token is single use; a join event is ingested once the membership is stored.
+func (uc *InvitationUseCase) Accept(ctx context.Context, token, requesterExternalID string) (*AcceptInvitationOutput, error) {
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, domain.InvitationTokenPrefix) {
+		return nil, domain.ErrInvitationInvalid
+	}
+
+	invitation, err := uc.invitationRepo.FindByTokenHash(ctx, domain.HashAPIKey(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up invitation: %w", err)
+	}
+
+	ctx = logging.ContextWithCommunityID(ctx, invitation.CommunityID().String())
+	log := uc.logger.WithContext(ctx)
+
+	requester, err := uc.requester(ctx, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, invitation.CommunityID())
+	if err != nil {
+		return nil, err
+	}
+	if !community.IsActive() {
+		return nil, fmt.Errorf("community %s is not active", community.ID().String())
+	}
+
+	if community.CreatorID() == requester.ID() {
+		return nil, ErrAlreadyCommunityMember
+	}
+	if _, err := uc.memberRepo.FindMember(ctx, community.ID(), requester.ID()); err == nil {
+		return nil, ErrAlreadyCommunityMember
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("looking up membership: %w", err)
+	}
+
+	if uc.sanctions != nil {
+		ban, err := activeSanction(ctx, uc.sanctions, uc.clock, community.ID(), requester.ID(), domain.SanctionBan)
+		if err != nil {
+			return nil, err
+		}
+		if ban != nil {
+			return nil, ErrUserBanned
+		}
+	}
+
+	if err := invitation.Accept(requester.ID()); err != nil {
+		return nil, err
+	}
+
+	member, err := domain.NewCommunityMember(uc.clock, community.ID(), requester.ID())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.invitationRepo.Accept(ctx, invitation, member); err != nil {
+		return nil, err
+	}
+
+	log.Info("invitation accepted",
+		"invitation_id", invitation.ID().String(),
+		"member_id", requester.ID().String(),
+	)
+
+	if uc.ingest != nil {
+		memberID := requester.ID().String()
+		_, err := uc.ingest.Execute(ctx, IngestEventInput{
+			CommunityID: community.ID().String(),
+			UserID:      &memberID,
+			EventType:   domain.EventTypeJoin.String(),
+			Metadata:    map[string]any{"source": "invitation"},
+		})
+		if err != nil {
+			// the membership is stored, a missing join event only affects momentum
+			log.Warn("join event for accepted invitation not ingested",
+				"error", err.Error(),
+			)
+		}
+	}
+
+	return &AcceptInvitationOutput{
+		CommunityID: community.ID().String(),
+		UserID:      requester.ID().String(),
+		JoinedAt:    member.JoinedAt(),
+	}, nil
+}
+
+// ListMembers returns a community's members to its managers.
+func (uc *InvitationUseCase) ListMembers(ctx context.Context, communityID, requesterExternalID string, limit, offset int) ([]CommunityMemberOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	community, _, err := uc.authorizeManager(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := uc.memberRepo.ListMembers(ctx, community.ID(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing members: %w", err)
+	}
+
+	out := make([]CommunityMemberOutput, len(members))
+	for i, m := range members {
+		out[i] = CommunityMemberOutput{UserID: m.UserID().String(), JoinedAt: m.JoinedAt()}
+	}
+	return out, nil
+}
+
+// authorizeManager checks that the requester created the community,
+// or manages the organization it belongs to.
+func (uc *InvitationUseCase) authorizeManager(ctx context.Context, communityID, requesterExternalID string) (*domain.Community, *domain.User, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requester, err := uc.requester(ctx, requesterExternalID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if requester.ID() == community.CreatorID() {
+		return community, requester, nil
+	}
+
+	orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !orgAdmin {
+		return nil, nil, ErrNotCommunityOwner
+	}
+	return community, requester, nil
+}
+
+func (uc *InvitationUseCase) requester(ctx context.Context, externalID string) (*domain.User, error) {
+	user, err := uc.userRepo.FindByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrCreatorNotFound
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	return user, nil
+}
+
+func toInvitationOutput(inv *domain.CommunityInvitation) InvitationOutput {
+	out := InvitationOutput{
+		ID:          inv.ID().String(),
+		CommunityID: inv.CommunityID().String(),
+		CreatedBy:   inv.CreatedBy().String(),
+		Hint:        inv.Hint(),
+		Status:      inv.Status(),
+		ExpiresAt:   inv.ExpiresAt(),
+		CreatedAt:   inv.CreatedAt(),
+		AcceptedAt:  inv.AcceptedAt(),
+	}
+	if by := inv.AcceptedBy(); by != nil {
+		s := by.String()
+		out.AcceptedBy = &s
+	}
+	return out
+}
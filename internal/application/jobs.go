@@ -0,0 +1,57 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// JobUseCase reports the status of background jobs to whoever started them.
+type JobUseCase struct {
+	jobs     domain.JobRepository
+	userRepo domain.UserRepository
+	logger   *logging.Logger
+}
+
+// NewJobUseCase creates a new JobUseCase.
+func NewJobUseCase(jobs domain.JobRepository, userRepo domain.UserRepository, logger *logging.Logger) *JobUseCase {
+	return &JobUseCase{
+		jobs:     jobs,
+		userRepo: userRepo,
+		logger:   logger.WithComponent("jobs"),
+	}
+}
+
+// Get returns a job started by the requester, or any job for admins.
+// other users' jobs are reported as not found, so their ids don't leak.
+func (uc *JobUseCase) Get(ctx context.Context, jobID, requesterExternalID string, admin bool) (*domain.Job, error) {
+	id, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job id: %w", domain.ErrInvalidInput)
+	}
+
+	job, err := uc.jobs.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if admin {
+		return job, nil
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	if !job.IsOwnedBy(requester.ID()) {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
@@ -0,0 +1,294 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrRegionalLeaderboardsDisabled is returned for a region when there's no redis to rank regions in.
+var ErrRegionalLeaderboardsDisabled = errors.New("regional leaderboards require redis")
+
+// ErrLeaderboardChangesDisabled is returned by Changes without WithRankingHistory.
+var ErrLeaderboardChangesDisabled = errors.New("leaderboard changes are not enabled")
+
+// RegionalLeaderboardReader pages through a region's rankings, highest momentum first.
+// implemented by the redis client.
+type RegionalLeaderboardReader interface {
+	GetTopCommunitiesInRegion(ctx context.Context, region string, limit, offset int) ([]domain.RegionalScore, error)
+}
+
+// LeaderboardUseCase ranks public communities globally or within a region.
+type LeaderboardUseCase struct {
+	communityRepo domain.CommunityRepository
+	regional      RegionalLeaderboardReader
+	pins          domain.CommunityPinRepository
+	rankings      domain.RankingSnapshotRepository
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// LeaderboardOption configures a LeaderboardUseCase at construction.
+type LeaderboardOption func(*LeaderboardUseCase)
+
+// WithPins puts the communities pinned by admins above the first page of the global
+// leaderboard, see CommunityPinUseCase.
+func WithPins(pins domain.CommunityPinRepository) LeaderboardOption {
+	return func(uc *LeaderboardUseCase) {
+		uc.pins = pins
+	}
+}
+
+// WithRankingHistory enables Changes, from the snapshots stored by the momentum cycles,
+// see WithRankingSnapshots.
+func WithRankingHistory(rankings domain.RankingSnapshotRepository) LeaderboardOption {
+	return func(uc *LeaderboardUseCase) {
+		uc.rankings = rankings
+	}
+}
+
+// NewLeaderboardUseCase creates a new LeaderboardUseCase.
+// regional may be nil, regional rankings are then unavailable.
+func NewLeaderboardUseCase(
+	communityRepo domain.CommunityRepository,
+	regional RegionalLeaderboardReader,
+	logger *logging.Logger,
+	opts ...LeaderboardOption,
+) *LeaderboardUseCase {
+	uc := &LeaderboardUseCase{
+		communityRepo: communityRepo,
+		regional:      regional,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("leaderboard"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// LeaderboardEntry is a community's place on a leaderboard.
+type LeaderboardEntry struct {
+	// Rank is 1-based, counting from the start of the leaderboard rather than the page.
+	Rank      int
+	Community *domain.Community
+
+	// Momentum is the regional momentum on a regional leaderboard, the community's own otherwise.
+	Momentum float64
+
+	// Pinned entries come first on the first page and have no rank, the community may
+	// also show up at its rank. Sponsored pins are paid placements.
+	Pinned    bool
+	Sponsored bool
+}
+
+// Execute returns a page of the global leaderboard, or of a region's when region isn't empty.
+func (uc *LeaderboardUseCase) Execute(ctx context.Context, region string, limit, offset int) ([]LeaderboardEntry, error) {
+	if region == "" {
+		communities, err := uc.communityRepo.ListPublicByMomentum(ctx, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("listing communities: %w", err)
+		}
+
+		var entries []LeaderboardEntry
+		if offset == 0 {
+			if entries, err = uc.pinned(ctx); err != nil {
+				return nil, err
+			}
+		}
+		for i, c := range communities {
+			entries = append(entries, LeaderboardEntry{
+				Rank:      offset + i + 1,
+				Community: c,
+				Momentum:  c.CurrentMomentum().Value(),
+			})
+		}
+		if entries == nil {
+			entries = []LeaderboardEntry{}
+		}
+		return entries, nil
+	}
+
+	r, err := domain.NewRegion(region)
+	if err != nil {
+		return nil, fmt.Errorf("invalid region: %w", err)
+	}
+	if uc.regional == nil {
+		return nil, ErrRegionalLeaderboardsDisabled
+	}
+
+	scores, err := uc.regional.GetTopCommunitiesInRegion(ctx, r.String(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("reading regional leaderboard: %w", err)
+	}
+	if len(scores) == 0 {
+		return []LeaderboardEntry{}, nil
+	}
+
+	ids := make([]domain.CommunityID, len(scores))
+	for i, s := range scores {
+		ids[i] = s.CommunityID
+	}
+	communities, err := uc.communityRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading communities: %w", err)
+	}
+	byID := make(map[domain.CommunityID]*domain.Community, len(communities))
+	for _, c := range communities {
+		byID[c.ID()] = c
+	}
+
+	// a community deactivated or made private since its last score is left out
+	// until the next momentum cycle takes it off the regional leaderboard
+	entries := make([]LeaderboardEntry, 0, len(scores))
+	for i, s := range scores {
+		c, ok := byID[s.CommunityID]
+		if !ok || !c.IsActive() || !c.Visibility().IsListed() {
+			uc.logger.WithContext(ctx).Debug("stale community on regional leaderboard skipped",
+				"region", r.String(),
+				"community_id", s.CommunityID.String(),
+			)
+			continue
+		}
+		entries = append(entries, LeaderboardEntry{
+			Rank:      offset + i + 1,
+			Community: c,
+			Momentum:  s.Momentum,
+		})
+	}
+	return entries, nil
+}
+
+// pinned returns the entries of the communities pinned right now, by when their pin started.
+func (uc *LeaderboardUseCase) pinned(ctx context.Context) ([]LeaderboardEntry, error) {
+	if uc.pins == nil {
+		return nil, nil
+	}
+
+	now := uc.clock.Now()
+	scheduled, err := uc.pins.ListEndingAfter(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("listing pins: %w", err)
+	}
+	var active []*domain.CommunityPin
+	for _, pin := range scheduled {
+		if pin.IsActiveAt(now) {
+			active = append(active, pin)
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]domain.CommunityID, len(active))
+	for i, pin := range active {
+		ids[i] = pin.CommunityID()
+	}
+	communities, err := uc.communityRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading pinned communities: %w", err)
+	}
+	byID := make(map[domain.CommunityID]*domain.Community, len(communities))
+	for _, c := range communities {
+		byID[c.ID()] = c
+	}
+
+	// a community deactivated or made private since it was pinned is left out
+	entries := make([]LeaderboardEntry, 0, len(active))
+	for _, pin := range active {
+		c, ok := byID[pin.CommunityID()]
+		if !ok || !c.IsActive() || !c.Visibility().IsListed() {
+			continue
+		}
+		entries = append(entries, LeaderboardEntry{
+			Community: c,
+			Momentum:  c.CurrentMomentum().Value(),
+			Pinned:    true,
+			Sponsored: pin.Sponsored(),
+		})
+	}
+	return entries, nil
+}
+
+// LeaderboardChange is a community that entered, left or moved on the leaderboard.
+type LeaderboardChange struct {
+	domain.RankingChange
+	Community *domain.Community
+}
+
+// LeaderboardChangesOutput compares two snapshots of the leaderboard.
+type LeaderboardChangesOutput struct {
+	// From is the snapshot since, To the latest one. their ids are the momentum runs'.
+	From *domain.RankingSnapshot
+	To   *domain.RankingSnapshot
+
+	Changes []LeaderboardChange
+}
+
+// Changes returns the communities that entered or left the top of the leaderboard, or
+// moved by more than minMove places, since a momentum run id or an RFC 3339 time.
+// a time is compared from the last snapshot taken at or before it. communities that
+// aren't listed anymore are left out, so a community made private doesn't show up.
+func (uc *LeaderboardUseCase) Changes(ctx context.Context, since string, minMove int) (*LeaderboardChangesOutput, error) {
+	if uc.rankings == nil {
+		return nil, ErrLeaderboardChangesDisabled
+	}
+	if minMove < 0 {
+		return nil, fmt.Errorf("invalid positions, must be zero or more: %w", domain.ErrInvalidInput)
+	}
+
+	from, err := uc.rankingSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	to, err := uc.rankings.LatestAt(ctx, uc.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("loading latest ranking snapshot: %w", err)
+	}
+
+	changes := to.ChangesSince(from, minMove)
+	if len(changes) == 0 {
+		return &LeaderboardChangesOutput{From: from, To: to, Changes: []LeaderboardChange{}}, nil
+	}
+
+	ids := make([]domain.CommunityID, len(changes))
+	for i, c := range changes {
+		ids[i] = c.CommunityID
+	}
+	communities, err := uc.communityRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading communities: %w", err)
+	}
+	byID := make(map[domain.CommunityID]*domain.Community, len(communities))
+	for _, c := range communities {
+		byID[c.ID()] = c
+	}
+
+	out := &LeaderboardChangesOutput{From: from, To: to, Changes: make([]LeaderboardChange, 0, len(changes))}
+	for _, change := range changes {
+		c, ok := byID[change.CommunityID]
+		if !ok || !c.IsActive() || !c.Visibility().IsListed() {
+			continue
+		}
+		out.Changes = append(out.Changes, LeaderboardChange{RankingChange: change, Community: c})
+	}
+	return out, nil
+}
+
+// rankingSince finds the snapshot of a momentum run, or the last one taken at a time.
+func (uc *LeaderboardUseCase) rankingSince(ctx context.Context, since string) (*domain.RankingSnapshot, error) {
+	if id, err := uuid.Parse(since); err == nil {
+		return uc.rankings.FindByID(ctx, id)
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since, must be a momentum run id or an RFC 3339 time: %w", domain.ErrInvalidInput)
+	}
+	return uc.rankings.LatestAt(ctx, t)
+}
@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// Meter accumulates billable usage in memory between metering flushes.
+// adding is a map increment under a mutex, cheap enough for request paths.
+type Meter struct {
+	mu     sync.Mutex
+	counts map[meterKey]int64
+}
+
+type meterKey struct {
+	subject   domain.MeterSubject
+	subjectID string
+	metric    domain.MeterMetric
+}
+
+// MeterReading is the usage accumulated for one subject and metric.
+type MeterReading struct {
+	Subject   domain.MeterSubject
+	SubjectID string
+	Metric    domain.MeterMetric
+	Quantity  int64
+}
+
+// NewMeter creates an empty Meter.
+func NewMeter() *Meter {
+	return &Meter{counts: make(map[meterKey]int64)}
+}
+
+// Add records n units of a metric for a subject. empty subject ids are ignored.
+func (m *Meter) Add(subject domain.MeterSubject, subjectID string, metric domain.MeterMetric, n int64) {
+	if subjectID == "" || n <= 0 {
+		return
+	}
+	m.mu.Lock()
+	m.counts[meterKey{subject, subjectID, metric}] += n
+	m.mu.Unlock()
+}
+
+// Drain returns everything accumulated since the last drain and resets the meter.
+func (m *Meter) Drain() []MeterReading {
+	m.mu.Lock()
+	counts := m.counts
+	m.counts = make(map[meterKey]int64, len(counts))
+	m.mu.Unlock()
+
+	readings := make([]MeterReading, 0, len(counts))
+	for k, n := range counts {
+		readings = append(readings, MeterReading{
+			Subject:   k.subject,
+			SubjectID: k.subjectID,
+			Metric:    k.metric,
+			Quantity:  n,
+		})
+	}
+	return readings
+}
+
+// restore puts readings back after a failed flush, so they're retried next time.
+func (m *Meter) restore(readings []MeterReading) {
+	for _, r := range readings {
+		m.Add(r.Subject, r.SubjectID, r.Metric, r.Quantity)
+	}
+}
+
+// MeteringExporter sends stored metering records to a billing system.
+type MeteringExporter interface {
+	// Name identifies the exporter in logs.
+	Name() string
+
+	// Export sends records. it may be called again with the same records,
+	// so exporters should deduplicate on record id where the target supports it.
+	Export(ctx context.Context, records []*domain.MeteringRecord) error
+}
+
+// MeteringUseCase turns accumulated usage into metering records and exports them.
+type MeteringUseCase struct {
+	meter       *Meter
+	repo        domain.MeteringRepository
+	exporters   []MeteringExporter
+	clock       domain.Clock
+	logger      *logging.Logger
+	periodStart time.Time
+}
+
+// MeteringOption configures a MeteringUseCase at construction.
+type MeteringOption func(*MeteringUseCase)
+
+// WithMeteringExporter adds an export hook called after every flush.
+func WithMeteringExporter(exporter MeteringExporter) MeteringOption {
+	return func(uc *MeteringUseCase) {
+		uc.exporters = append(uc.exporters, exporter)
+	}
+}
+
+// NewMeteringUseCase creates a new MeteringUseCase.
+// the first period starts at construction.
+func NewMeteringUseCase(meter *Meter, repo domain.MeteringRepository, logger *logging.Logger, opts ...MeteringOption) *MeteringUseCase {
+	uc := &MeteringUseCase{
+		meter:  meter,
+		repo:   repo,
+		clock:  domain.SystemClock,
+		logger: logger.WithComponent("metering"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	uc.periodStart = uc.clock.Now()
+	return uc
+}
+
+// FlushOutput reports one metering flush.
+type FlushOutput struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Records     int
+	// ExportErrors maps exporter name to its error, for exporters that failed.
+	ExportErrors map[string]error
+}
+
+// Flush closes the current period: usage is stored as metering records, then handed to exporters.
+// if storing fails the usage is kept for the next flush; export failures are reported but
+// don't undo the flush, since the records are in the metering table and can be exported again.
+// not safe to call concurrently.
+func (uc *MeteringUseCase) Flush(ctx context.Context) (*FlushOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
+	start, end := uc.periodStart, uc.clock.Now()
+	readings := uc.meter.Drain()
+	out := &FlushOutput{PeriodStart: start, PeriodEnd: end}
+
+	if len(readings) == 0 {
+		uc.periodStart = end
+		return out, nil
+	}
+
+	records := make([]*domain.MeteringRecord, 0, len(readings))
+	for _, r := range readings {
+		record, err := domain.NewMeteringRecord(uc.clock, r.Subject, r.SubjectID, r.Metric, r.Quantity, start, end)
+		if err != nil {
+			log.Warn("metering reading dropped",
+				"subject", r.Subject.String(),
+				"metric", r.Metric.String(),
+				"error", err.Error(),
+			)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	if err := uc.repo.SaveBatch(ctx, records); err != nil {
+		uc.meter.restore(readings)
+		log.Error("metering records save failed, keeping usage for next flush",
+			"records", len(records),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving metering records: %w", err)
+	}
+	uc.periodStart = end
+	out.Records = len(records)
+
+	for _, exporter := range uc.exporters {
+		if err := exporter.Export(ctx, records); err != nil {
+			if out.ExportErrors == nil {
+				out.ExportErrors = make(map[string]error)
+			}
+			out.ExportErrors[exporter.Name()] = err
+			log.Error("metering export failed",
+				"exporter", exporter.Name(),
+				"records", len(records),
+				"error", err.Error(),
+			)
+		}
+	}
+
+	log.Info("metering flushed",
+		"period_start", start,
+		"period_end", end,
+		"records", len(records),
+	)
+	return out, nil
+}
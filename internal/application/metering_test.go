@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+type fakeMeteringRepo struct {
+	saved []*domain.MeteringRecord
+	err   error
+}
+
+func (f *fakeMeteringRepo) SaveBatch(_ context.Context, records []*domain.MeteringRecord) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.saved = append(f.saved, records...)
+	return nil
+}
+
+func (f *fakeMeteringRepo) ListByPeriod(context.Context, time.Time, time.Time) ([]*domain.MeteringRecord, error) {
+	return f.saved, nil
+}
+
+func TestMeteringUseCase_Flush(t *testing.T) {
+	meter := NewMeter()
+	repo := &fakeMeteringRepo{err: errors.New("db down")}
+	uc := NewMeteringUseCase(meter, repo, logging.New())
+	uc.periodStart = time.Now().Add(-time.Hour)
+
+	meter.Add(domain.MeterSubjectCommunity, "c1", domain.MeterEventsIngested, 3)
+	meter.Add(domain.MeterSubjectCommunity, "c1", domain.MeterEventsIngested, 2)
+	meter.Add(domain.MeterSubjectUser, "u1", domain.MeterAPICalls, 1)
+
+	// a failed save keeps the usage for the next flush
+	if _, err := uc.Flush(context.Background()); err == nil {
+		t.Fatal("expected an error when the repository fails")
+	}
+
+	repo.err = nil
+	out, err := uc.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Records != 2 || len(repo.saved) != 2 {
+		t.Fatalf("expected 2 records, got %d saved", len(repo.saved))
+	}
+	for _, r := range repo.saved {
+		if r.Metric() == domain.MeterEventsIngested && r.Quantity() != 5 {
+			t.Errorf("events ingested = %d, want 5", r.Quantity())
+		}
+	}
+
+	// nothing left once flushed
+	if readings := meter.Drain(); len(readings) != 0 {
+		t.Errorf("expected an empty meter, got %v", readings)
+	}
+}
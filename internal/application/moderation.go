@@ -0,0 +1,402 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+var (
+	// ErrNotCommunityModerator is returned when someone who can't moderate a community tries to.
+	ErrNotCommunityModerator = errors.New("user is not a moderator of the community")
+	// ErrCannotModerateUser is returned for targets above the requester: the creator,
+	// organization admins, other moderators (for moderators) and yourself.
+	ErrCannotModerateUser = errors.New("user cannot be moderated by the requester")
+	// ErrUserBanned is returned when a banned user sends events to, or joins, a community.
+	ErrUserBanned = errors.New("user is banned from the community")
+)
+
+// SanctionChecker reports a user's active sanctions in a community.
+// satisfied by domain.ModerationRepository, usually through a cache.
+type SanctionChecker interface {
+	FindActive(ctx context.Context, communityID domain.CommunityID, userID domain.UserID) ([]*domain.CommunitySanction, error)
+}
+
+// ModerationUseCase lets community owners and moderators ban and mute users.
+// owners are the creator and owners or admins of the community's organization;
+// they also appoint moderators among the members.
+type ModerationUseCase struct {
+	moderationRepo domain.ModerationRepository
+	memberRepo     domain.CommunityMemberRepository
+	communityRepo  domain.CommunityRepository
+	userRepo       domain.UserRepository
+	orgRepo        domain.OrganizationRepository
+	clock          domain.Clock
+	logger         *logging.Logger
+}
+
+// ModerationOption configures a ModerationUseCase at construction.
+type ModerationOption func(*ModerationUseCase)
+
+// WithModerationOrganizationAdmins lets owners and admins of a community's organization
+// moderate it, not only the creator.
+func WithModerationOrganizationAdmins(repo domain.OrganizationRepository) ModerationOption {
+	return func(uc *ModerationUseCase) {
+		uc.orgRepo = repo
+	}
+}
+
+// NewModerationUseCase creates a new ModerationUseCase.
+func NewModerationUseCase(
+	moderationRepo domain.ModerationRepository,
+	memberRepo domain.CommunityMemberRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+	opts ...ModerationOption,
+) *ModerationUseCase {
+	uc := &ModerationUseCase{
+		moderationRepo: moderationRepo,
+		memberRepo:     memberRepo,
+		communityRepo:  communityRepo,
+		userRepo:       userRepo,
+		clock:          domain.SystemClock,
+		logger:         logger.WithComponent("moderation"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// SanctionInput describes a ban or mute.
+type SanctionInput struct {
+	CommunityID  string
+	TargetUserID string
+	Kind         domain.SanctionKind
+	Reason       string
+	// Duration is how long the sanction lasts, until lifted if zero.
+	Duration            time.Duration
+	RequesterExternalID string
+}
+
+// SanctionOutput describes an active ban or mute.
+type SanctionOutput struct {
+	CommunityID string
+	UserID      string
+	Kind        domain.SanctionKind
+	Reason      string
+	IssuedBy    string
+	CreatedAt   time.Time
+	ExpiresAt   *time.Time
+}
+
+// ModerationLogOutput describes one entry of the audit trail.
+type ModerationLogOutput struct {
+	ID        string
+	ActorID   string
+	TargetID  string
+	Action    domain.ModerationAction
+	Reason    string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
+
+// moderator is who is moderating, and how much they can do.
+type moderator struct {
+	community *domain.Community
+	user      *domain.User
+	owner     bool
+}
+
+// Sanction bans or mutes a user. sanctioning again replaces the previous one of the same kind.
+func (uc *ModerationUseCase) Sanction(ctx context.Context, input SanctionInput) (*SanctionOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+
+	mod, err := uc.authorize(ctx, input.CommunityID, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.target(ctx, mod, input.TargetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sanction, err := domain.NewCommunitySanction(uc.clock, mod.community.ID(), target, input.Kind, input.Reason, mod.user.ID(), input.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.ModerationMute
+	if input.Kind == domain.SanctionBan {
+		action = domain.ModerationBan
+	}
+	entry := domain.NewModerationLogEntry(uc.clock, mod.community.ID(), mod.user.ID(), target, action, input.Reason, sanction.ExpiresAt())
+
+	if err := uc.moderationRepo.Apply(ctx, sanction, entry); err != nil {
+		return nil, fmt.Errorf("saving sanction: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("user sanctioned",
+		"kind", input.Kind.String(),
+		"target_id", target.String(),
+		"moderator_id", mod.user.ID().String(),
+		"expires_at", sanction.ExpiresAt(),
+	)
+
+	out := toSanctionOutput(sanction)
+	return &out, nil
+}
+
+// Lift removes a ban or mute before it expires.
+func (uc *ModerationUseCase) Lift(ctx context.Context, communityID, targetUserID string, kind domain.SanctionKind, requesterExternalID string) error {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	mod, err := uc.authorize(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return err
+	}
+	target, err := domain.ParseUserID(targetUserID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+
+	action := domain.ModerationUnmute
+	if kind == domain.SanctionBan {
+		action = domain.ModerationUnban
+	}
+	entry := domain.NewModerationLogEntry(uc.clock, mod.community.ID(), mod.user.ID(), target, action, "", nil)
+
+	if err := uc.moderationRepo.Lift(ctx, mod.community.ID(), target, kind, entry); err != nil {
+		return err
+	}
+
+	uc.logger.WithContext(ctx).Info("sanction lifted",
+		"kind", kind.String(),
+		"target_id", target.String(),
+		"moderator_id", mod.user.ID().String(),
+	)
+	return nil
+}
+
+// SetModerator promotes a member to moderator, or demotes them back. owners only.
+func (uc *ModerationUseCase) SetModerator(ctx context.Context, communityID, targetUserID string, moderator bool, requesterExternalID string) error {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	mod, err := uc.authorize(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return err
+	}
+	if !mod.owner {
+		return ErrNotCommunityOwner
+	}
+
+	target, err := domain.ParseUserID(targetUserID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+
+	member, err := uc.memberRepo.FindMember(ctx, mod.community.ID(), target)
+	if err != nil {
+		return err
+	}
+
+	role, action := domain.CommunityRoleMember, domain.ModerationRemoveModerator
+	if moderator {
+		role, action = domain.CommunityRoleModerator, domain.ModerationAddModerator
+	}
+	if member.Role() == role {
+		return nil
+	}
+	if err := member.SetRole(role); err != nil {
+		return err
+	}
+	if err := uc.memberRepo.UpdateRole(ctx, member); err != nil {
+		return fmt.Errorf("saving member role: %w", err)
+	}
+
+	entry := domain.NewModerationLogEntry(uc.clock, mod.community.ID(), mod.user.ID(), target, action, "", nil)
+	if err := uc.moderationRepo.Log(ctx, entry); err != nil {
+		// the role change is stored, a missing entry only affects the audit trail
+		uc.logger.WithContext(ctx).Warn("moderation log entry not saved",
+			"action", action.String(),
+			"error", err.Error(),
+		)
+	}
+
+	uc.logger.WithContext(ctx).Info("community role changed",
+		"target_id", target.String(),
+		"role", role.String(),
+		"owner_id", mod.user.ID().String(),
+	)
+	return nil
+}
+
+// ListSanctions returns a community's active bans and mutes.
+func (uc *ModerationUseCase) ListSanctions(ctx context.Context, communityID, requesterExternalID string) ([]SanctionOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	mod, err := uc.authorize(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	sanctions, err := uc.moderationRepo.ListActive(ctx, mod.community.ID())
+	if err != nil {
+		return nil, fmt.Errorf("listing sanctions: %w", err)
+	}
+
+	out := make([]SanctionOutput, len(sanctions))
+	for i, s := range sanctions {
+		out[i] = toSanctionOutput(s)
+	}
+	return out, nil
+}
+
+// AuditLog returns a community's moderation history, newest first.
+func (uc *ModerationUseCase) AuditLog(ctx context.Context, communityID, requesterExternalID string, limit, offset int) ([]ModerationLogOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	mod, err := uc.authorize(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.moderationRepo.ListLog(ctx, mod.community.ID(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing moderation log: %w", err)
+	}
+
+	out := make([]ModerationLogOutput, len(entries))
+	for i, e := range entries {
+		out[i] = ModerationLogOutput{
+			ID:        e.ID().String(),
+			ActorID:   e.ActorID().String(),
+			TargetID:  e.TargetID().String(),
+			Action:    e.Action(),
+			Reason:    e.Reason(),
+			ExpiresAt: e.ExpiresAt(),
+			CreatedAt: e.CreatedAt(),
+		}
+	}
+	return out, nil
+}
+
+// authorize checks that the requester owns or moderates the community.
+func (uc *ModerationUseCase) authorize(ctx context.Context, communityID, requesterExternalID string) (*moderator, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrNotCommunityModerator
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+
+	owner, err := uc.isOwner(ctx, community, requester.ID())
+	if err != nil {
+		return nil, err
+	}
+	if owner {
+		return &moderator{community: community, user: requester, owner: true}, nil
+	}
+
+	member, err := uc.memberRepo.FindMember(ctx, id, requester.ID())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, ErrNotCommunityModerator
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up membership: %w", err)
+	}
+	if !member.IsModerator() {
+		return nil, ErrNotCommunityModerator
+	}
+	return &moderator{community: community, user: requester}, nil
+}
+
+// target parses the user to sanction and checks the moderator outranks them.
+func (uc *ModerationUseCase) target(ctx context.Context, mod *moderator, targetUserID string) (domain.UserID, error) {
+	target, err := domain.ParseUserID(targetUserID)
+	if err != nil {
+		return domain.UserID{}, fmt.Errorf("invalid user id: %w", err)
+	}
+	if target == mod.user.ID() {
+		return domain.UserID{}, ErrCannotModerateUser
+	}
+
+	exists, err := uc.userRepo.Exists(ctx, target)
+	if err != nil {
+		return domain.UserID{}, fmt.Errorf("looking up user: %w", err)
+	}
+	if !exists {
+		return domain.UserID{}, fmt.Errorf("user %s not found", target.String())
+	}
+
+	owner, err := uc.isOwner(ctx, mod.community, target)
+	if err != nil {
+		return domain.UserID{}, err
+	}
+	if owner {
+		return domain.UserID{}, ErrCannotModerateUser
+	}
+
+	if !mod.owner {
+		member, err := uc.memberRepo.FindMember(ctx, mod.community.ID(), target)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return domain.UserID{}, fmt.Errorf("looking up membership: %w", err)
+		}
+		if member != nil && member.IsModerator() {
+			return domain.UserID{}, ErrCannotModerateUser
+		}
+	}
+	return target, nil
+}
+
+// isOwner reports whether the user created the community or manages its organization.
+func (uc *ModerationUseCase) isOwner(ctx context.Context, community *domain.Community, userID domain.UserID) (bool, error) {
+	if community.CreatorID() == userID {
+		return true, nil
+	}
+	return canManageCommunity(ctx, uc.orgRepo, community, userID)
+}
+
+// activeSanction returns the user's sanction of kind, nil if they have none.
+// cached results may have expired since, so expiry is checked against clock.
+func activeSanction(ctx context.Context, checker SanctionChecker, clock domain.Clock, communityID domain.CommunityID, userID domain.UserID, kind domain.SanctionKind) (*domain.CommunitySanction, error) {
+	sanctions, err := checker.FindActive(ctx, communityID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("sanction lookup: %w", err)
+	}
+	now := clock.Now()
+	for _, s := range sanctions {
+		if s.Kind() == kind && s.IsActiveAt(now) {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func toSanctionOutput(s *domain.CommunitySanction) SanctionOutput {
+	return SanctionOutput{
+		CommunityID: s.CommunityID().String(),
+		UserID:      s.UserID().String(),
+		Kind:        s.Kind(),
+		Reason:      s.Reason(),
+		IssuedBy:    s.IssuedBy().String(),
+		CreatedAt:   s.CreatedAt(),
+		ExpiresAt:   s.ExpiresAt(),
+	}
+}
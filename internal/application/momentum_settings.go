@@ -0,0 +1,359 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrNotCommunityOwner is returned when a user tries to manage a community they didn't create.
+var ErrNotCommunityOwner = errors.New("user is not the community owner")
+
+// ErrEventWeightOverridesDisabled is returned by UpdateWeights and ResetWeights without WithEventWeightOverrides.
+var ErrEventWeightOverridesDisabled = errors.New("event weight overrides are not enabled")
+
+// MomentumSettingsUseCase lets community owners read and change their momentum overrides.
+type MomentumSettingsUseCase struct {
+	settingsRepo  domain.CommunityMomentumSettingsRepository
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	weightsRepo   domain.CommunityEventWeightsRepository
+	defaults      MomentumConfig
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// MomentumSettingsOption configures a MomentumSettingsUseCase at construction.
+type MomentumSettingsOption func(*MomentumSettingsUseCase)
+
+// WithOrganizationAdmins lets owners and admins of a community's organization
+// change its settings, not only the creator.
+func WithOrganizationAdmins(repo domain.OrganizationRepository) MomentumSettingsOption {
+	return func(uc *MomentumSettingsUseCase) {
+		uc.orgRepo = repo
+	}
+}
+
+// WithEventWeightOverrides lets owners replace the default weight of event types
+// in their community.
+func WithEventWeightOverrides(repo domain.CommunityEventWeightsRepository) MomentumSettingsOption {
+	return func(uc *MomentumSettingsUseCase) {
+		uc.weightsRepo = repo
+	}
+}
+
+// NewMomentumSettingsUseCase creates a new MomentumSettingsUseCase.
+// defaults is the global config the overrides are layered on.
+func NewMomentumSettingsUseCase(
+	settingsRepo domain.CommunityMomentumSettingsRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	defaults MomentumConfig,
+	logger *logging.Logger,
+	opts ...MomentumSettingsOption,
+) *MomentumSettingsUseCase {
+	uc := &MomentumSettingsUseCase{
+		settingsRepo:  settingsRepo,
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		defaults:      defaults,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("momentum_settings"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// MomentumSettingsOutput describes both the overrides and the config they produce.
+type MomentumSettingsOutput struct {
+	CommunityID string
+
+	// TimeWindow and DecayFactor are the overrides, nil when using the default.
+	TimeWindow  *time.Duration
+	DecayFactor *float64
+
+	// Effective is the config the momentum worker will use for this community.
+	Effective MomentumConfig
+}
+
+// UpdateMomentumSettingsInput contains the overrides to store.
+// nil fields are cleared back to the global default.
+type UpdateMomentumSettingsInput struct {
+	CommunityID string
+	TimeWindow  *time.Duration
+	DecayFactor *float64
+
+	// RequesterExternalID comes from the validated JWT
+	RequesterExternalID string
+}
+
+// Get returns the momentum overrides and effective config for a community.
+// readable by anyone, like the community itself.
+func (uc *MomentumSettingsUseCase) Get(ctx context.Context, communityID string) (*MomentumSettingsOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	if _, err := uc.communityRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.settingsRepo.FindByCommunity(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		log.Error("momentum settings lookup failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("loading momentum settings: %w", err)
+	}
+
+	return uc.output(id, settings), nil
+}
+
+// EventWeight is how much one event of a type moves a community's momentum.
+type EventWeight struct {
+	EventType domain.EventType
+
+	// Configured is the weight stored for events sent without one.
+	Configured float64
+
+	// Overridden is set when Configured is the community's weight, not the type's default.
+	Overridden bool
+
+	// Effective is what such an event adds to the community's momentum: the configured
+	// weight, negative for negative signals, times the community's decay factor, see
+	// domain.SimpleMomentum.
+	Effective float64
+}
+
+// EventWeightsOutput lists the weight of every event type for a community.
+type EventWeightsOutput struct {
+	CommunityID string
+	DecayFactor float64
+	Weights     []EventWeight
+}
+
+// Weights returns the configured and effective weight of every event type for a community,
+// to explain how its momentum moves. readable by anyone, like Get.
+func (uc *MomentumSettingsUseCase) Weights(ctx context.Context, communityID string) (*EventWeightsOutput, error) {
+	settings, err := uc.Get(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides *domain.CommunityEventWeights
+	if uc.weightsRepo != nil {
+		id, _ := domain.ParseCommunityID(settings.CommunityID)
+		overrides, err = uc.weightsRepo.FindByCommunity(ctx, id)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			uc.logger.WithContext(ctx).Error("event weights lookup failed",
+				"error", err.Error(),
+			)
+			return nil, fmt.Errorf("loading event weights: %w", err)
+		}
+	}
+
+	decayFactor := settings.Effective.DecayFactor
+	out := &EventWeightsOutput{
+		CommunityID: settings.CommunityID,
+		DecayFactor: decayFactor,
+	}
+	for _, et := range domain.EventTypes() {
+		configured := overrides.WeightFor(et).Value()
+		signed := configured
+		if !et.IsPositiveSignal() {
+			signed = -signed
+		}
+		out.Weights = append(out.Weights, EventWeight{
+			EventType:  et,
+			Configured: configured,
+			Overridden: overrides.Overrides(et),
+			Effective:  signed * decayFactor,
+		})
+	}
+	return out, nil
+}
+
+// Update stores momentum overrides for a community owned by the requester.
+func (uc *MomentumSettingsUseCase) Update(ctx context.Context, input UpdateMomentumSettingsInput) (*MomentumSettingsOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := uc.authorizeOwner(ctx, input.CommunityID, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := domain.NewCommunityMomentumSettings(uc.clock, id, input.TimeWindow, input.DecayFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.settingsRepo.Save(ctx, settings); err != nil {
+		log.Error("momentum settings save failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving momentum settings: %w", err)
+	}
+
+	out := uc.output(id, settings)
+	log.Info("momentum settings updated",
+		"time_window", out.Effective.TimeWindow.String(),
+		"decay_factor", out.Effective.DecayFactor,
+	)
+
+	return out, nil
+}
+
+// Reset removes all overrides so the community uses the global config again.
+func (uc *MomentumSettingsUseCase) Reset(ctx context.Context, communityID, requesterExternalID string) error {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := uc.authorizeOwner(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.settingsRepo.Delete(ctx, id); err != nil {
+		log.Error("momentum settings reset failed",
+			"error", err.Error(),
+		)
+		return fmt.Errorf("resetting momentum settings: %w", err)
+	}
+
+	log.Info("momentum settings reset")
+	return nil
+}
+
+// UpdateEventWeightsInput contains the event weight overrides to store.
+// event types left out keep their default weight.
+type UpdateEventWeightsInput struct {
+	CommunityID string
+	Weights     map[string]float64
+
+	// RequesterExternalID comes from the validated JWT
+	RequesterExternalID string
+}
+
+// UpdateWeights replaces the event weight overrides for a community owned by the requester.
+// they apply to events ingested from then on, stored events keep their weight.
+func (uc *MomentumSettingsUseCase) UpdateWeights(ctx context.Context, input UpdateEventWeightsInput) (*EventWeightsOutput, error) {
+	if uc.weightsRepo == nil {
+		return nil, ErrEventWeightOverridesDisabled
+	}
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := uc.authorizeOwner(ctx, input.CommunityID, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[domain.EventType]float64, len(input.Weights))
+	for et, v := range input.Weights {
+		values[domain.EventType(et)] = v
+	}
+	weights, err := domain.NewCommunityEventWeights(uc.clock, id, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.weightsRepo.Save(ctx, weights); err != nil {
+		log.Error("event weights save failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving event weights: %w", err)
+	}
+
+	log.Info("event weights updated",
+		"overridden", len(values),
+	)
+
+	return uc.Weights(ctx, input.CommunityID)
+}
+
+// ResetWeights removes the event weight overrides so the community uses the default weights again.
+func (uc *MomentumSettingsUseCase) ResetWeights(ctx context.Context, communityID, requesterExternalID string) error {
+	if uc.weightsRepo == nil {
+		return ErrEventWeightOverridesDisabled
+	}
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := uc.authorizeOwner(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.weightsRepo.Delete(ctx, id); err != nil {
+		log.Error("event weights reset failed",
+			"error", err.Error(),
+		)
+		return fmt.Errorf("resetting event weights: %w", err)
+	}
+
+	log.Info("event weights reset")
+	return nil
+}
+
+// authorizeOwner checks that the requester created the community,
+// or manages the organization it belongs to.
+func (uc *MomentumSettingsUseCase) authorizeOwner(ctx context.Context, communityID, requesterExternalID string) (domain.CommunityID, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return domain.CommunityID{}, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.CommunityID{}, err
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.CommunityID{}, ErrNotCommunityOwner
+		}
+		return domain.CommunityID{}, fmt.Errorf("looking up requester: %w", err)
+	}
+
+	if requester.ID() == community.CreatorID() {
+		return id, nil
+	}
+
+	orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+	if err != nil {
+		return domain.CommunityID{}, err
+	}
+	if !orgAdmin {
+		uc.logger.WithContext(ctx).Info("momentum settings change rejected: not owner",
+			"requester_id", requester.ID().String(),
+		)
+		return domain.CommunityID{}, ErrNotCommunityOwner
+	}
+
+	return id, nil
+}
+
+func (uc *MomentumSettingsUseCase) output(id domain.CommunityID, settings *domain.CommunityMomentumSettings) *MomentumSettingsOutput {
+	out := &MomentumSettingsOutput{
+		CommunityID: id.String(),
+		Effective:   uc.defaults.WithOverrides(settings),
+	}
+	if settings != nil {
+		out.TimeWindow = settings.TimeWindow()
+		out.DecayFactor = settings.DecayFactor()
+	}
+	return out
+}
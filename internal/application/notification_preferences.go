@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// NotificationPreferencesUseCase lets users limit the webhook notifications they receive.
+type NotificationPreferencesUseCase struct {
+	repo   domain.NotificationPreferencesRepository
+	clock  domain.Clock
+	logger *logging.Logger
+}
+
+// NewNotificationPreferencesUseCase creates a new NotificationPreferencesUseCase.
+func NewNotificationPreferencesUseCase(
+	repo domain.NotificationPreferencesRepository,
+	logger *logging.Logger,
+) *NotificationPreferencesUseCase {
+	return &NotificationPreferencesUseCase{
+		repo:   repo,
+		clock:  domain.SystemClock,
+		logger: logger.WithComponent("notification_preferences"),
+	}
+}
+
+// QuietHoursSettings is a daily span as HH:MM times in an IANA timezone.
+type QuietHoursSettings struct {
+	Start    string
+	End      string
+	Timezone string
+}
+
+// UpdateNotificationPreferencesInput contains the preferences to store, replacing any previous ones.
+type UpdateNotificationPreferencesInput struct {
+	// RequesterExternalID comes from the validated JWT
+	RequesterExternalID string
+
+	// MaxPerHour caps deliveries per rolling hour, 0 is unlimited.
+	MaxPerHour        int
+	MutedCommunityIDs []string
+
+	// QuietHours is nil for none.
+	QuietHours *QuietHoursSettings
+}
+
+// NotificationPreferencesOutput describes a user's preferences.
+// users who never set any get the defaults, with a nil UpdatedAt.
+type NotificationPreferencesOutput struct {
+	MaxPerHour        int
+	MutedCommunityIDs []string
+	QuietHours        *QuietHoursSettings
+	UpdatedAt         *time.Time
+}
+
+// Get returns the requester's preferences, or the defaults if they never set any.
+func (uc *NotificationPreferencesUseCase) Get(ctx context.Context, requesterExternalID string) (*NotificationPreferencesOutput, error) {
+	userID, err := preferencesUserID(requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := uc.repo.FindByUser(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &NotificationPreferencesOutput{MutedCommunityIDs: []string{}}, nil
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("notification preferences lookup failed",
+			"user_id", userID.String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("loading notification preferences: %w", err)
+	}
+
+	return toNotificationPreferencesOutput(prefs), nil
+}
+
+// Update replaces the requester's preferences.
+func (uc *NotificationPreferencesUseCase) Update(ctx context.Context, input UpdateNotificationPreferencesInput) (*NotificationPreferencesOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
+	userID, err := preferencesUserID(input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	muted := make([]domain.CommunityID, len(input.MutedCommunityIDs))
+	for i, raw := range input.MutedCommunityIDs {
+		muted[i], err = domain.ParseCommunityID(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid muted community id: %w", err)
+		}
+	}
+
+	var quietHours *domain.QuietHours
+	if q := input.QuietHours; q != nil {
+		parsed, err := domain.ParseQuietHours(q.Start, q.End, q.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		quietHours = &parsed
+	}
+
+	prefs, err := domain.NewNotificationPreferences(uc.clock, userID, input.MaxPerHour, muted, quietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Save(ctx, prefs); err != nil {
+		log.Error("notification preferences save failed",
+			"user_id", userID.String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving notification preferences: %w", err)
+	}
+
+	log.Info("notification preferences updated",
+		"user_id", userID.String(),
+		"max_per_hour", prefs.MaxPerHour(),
+		"muted_communities", len(prefs.MutedCommunities()),
+		"quiet_hours", prefs.QuietHours() != nil,
+	)
+
+	return toNotificationPreferencesOutput(prefs), nil
+}
+
+// Reset removes the requester's preferences, so they get every notification again.
+func (uc *NotificationPreferencesUseCase) Reset(ctx context.Context, requesterExternalID string) error {
+	log := uc.logger.WithContext(ctx)
+
+	userID, err := preferencesUserID(requesterExternalID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, userID); err != nil {
+		log.Error("notification preferences reset failed",
+			"user_id", userID.String(),
+			"error", err.Error(),
+		)
+		return fmt.Errorf("resetting notification preferences: %w", err)
+	}
+
+	log.Info("notification preferences reset", "user_id", userID.String())
+	return nil
+}
+
+// preferencesUserID keys preferences by the same id webhook subscriptions are stored under.
+func preferencesUserID(externalID string) (domain.UserID, error) {
+	userID, err := domain.ParseUserID(externalID)
+	if err != nil {
+		return domain.UserID{}, fmt.Errorf("invalid user id: %w", err)
+	}
+	return userID, nil
+}
+
+func toNotificationPreferencesOutput(prefs *domain.NotificationPreferences) *NotificationPreferencesOutput {
+	updatedAt := prefs.UpdatedAt()
+	out := &NotificationPreferencesOutput{
+		MaxPerHour:        prefs.MaxPerHour(),
+		MutedCommunityIDs: make([]string, len(prefs.MutedCommunities())),
+		UpdatedAt:         &updatedAt,
+	}
+	for i, id := range prefs.MutedCommunities() {
+		out.MutedCommunityIDs[i] = id.String()
+	}
+	if q := prefs.QuietHours(); q != nil {
+		out.QuietHours = &QuietHoursSettings{
+			Start:    q.Start(),
+			End:      q.End(),
+			Timezone: q.Timezone(),
+		}
+	}
+	return out
+}
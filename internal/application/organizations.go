@@ -0,0 +1,538 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// organization use case errors
+var (
+	ErrOrganizationSlugTaken        = errors.New("organization with this slug already exists")
+	ErrNotOrganizationMember        = errors.New("user is not a member of the organization")
+	ErrOrganizationPermissionDenied = errors.New("organization role does not allow this")
+	ErrOrganizationMemberExists     = errors.New("user is already a member of the organization")
+	ErrCommunityInOtherOrganization = errors.New("community belongs to another organization")
+	ErrCommunityNotInOrganization   = errors.New("community does not belong to the organization")
+)
+
+// OrganizationUseCase manages organizations, their members, communities and api keys.
+// every method takes the requester's external id from the validated JWT and checks
+// their role: members can read, admins and owners can change things.
+type OrganizationUseCase struct {
+	orgRepo       domain.OrganizationRepository
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	apiKeyRepo    domain.APIKeyRepository
+	reserved      domain.ReservedNames
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// OrganizationOption configures an OrganizationUseCase at construction.
+type OrganizationOption func(*OrganizationUseCase)
+
+// WithReservedOrganizationSlugs rejects slugs on the blocklist, on top of the built-in one NewSlug enforces.
+func WithReservedOrganizationSlugs(reserved domain.ReservedNames) OrganizationOption {
+	return func(uc *OrganizationUseCase) {
+		uc.reserved = reserved
+	}
+}
+
+// NewOrganizationUseCase creates a new OrganizationUseCase.
+func NewOrganizationUseCase(
+	orgRepo domain.OrganizationRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	apiKeyRepo domain.APIKeyRepository,
+	logger *logging.Logger,
+	opts ...OrganizationOption,
+) *OrganizationUseCase {
+	uc := &OrganizationUseCase{
+		orgRepo:       orgRepo,
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		apiKeyRepo:    apiKeyRepo,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("organizations"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// OrganizationOutput describes an organization as seen by the requester.
+type OrganizationOutput struct {
+	ID        string
+	Slug      string
+	Name      string
+	CreatedBy string
+	CreatedAt time.Time
+
+	// Role is the requester's role in the organization.
+	Role string
+}
+
+// OrganizationMemberOutput describes one membership.
+type OrganizationMemberOutput struct {
+	UserID   string
+	Role     string
+	JoinedAt time.Time
+}
+
+// CreateOrganizationInput contains the data needed to create an organization.
+type CreateOrganizationInput struct {
+	Slug string
+	Name string
+
+	// RequesterExternalID becomes the first owner
+	RequesterExternalID string
+}
+
+// Create creates an organization with the requester as its owner.
+func (uc *OrganizationUseCase) Create(ctx context.Context, input CreateOrganizationInput) (*OrganizationOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
+	slug, err := domain.NewSlug(input.Slug)
+	if err == nil && uc.reserved.Contains(input.Slug) {
+		err = domain.ErrNameReserved
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid slug: %w", err)
+	}
+
+	creator, err := uc.requester(ctx, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := domain.NewOrganization(uc.clock, slug, input.Name, creator.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := domain.NewOrganizationMember(uc.clock, org.ID(), creator.ID(), domain.OrganizationRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.orgRepo.Create(ctx, org, owner); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, ErrOrganizationSlugTaken
+		}
+		log.Error("create organization failed",
+			"slug", input.Slug,
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving organization: %w", err)
+	}
+
+	log.Info("organization created",
+		"organization_id", org.ID().String(),
+		"slug", org.Slug().String(),
+	)
+
+	return toOrganizationOutput(org, owner.Role()), nil
+}
+
+// Get returns an organization the requester belongs to.
+func (uc *OrganizationUseCase) Get(ctx context.Context, organizationID, requesterExternalID string) (*OrganizationOutput, error) {
+	org, member, err := uc.authorize(ctx, organizationID, requesterExternalID, false)
+	if err != nil {
+		return nil, err
+	}
+	return toOrganizationOutput(org, member.Role()), nil
+}
+
+// ListMembers returns every member of the organization.
+func (uc *OrganizationUseCase) ListMembers(ctx context.Context, organizationID, requesterExternalID string) ([]OrganizationMemberOutput, error) {
+	org, _, err := uc.authorize(ctx, organizationID, requesterExternalID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := uc.orgRepo.ListMembers(ctx, org.ID())
+	if err != nil {
+		return nil, fmt.Errorf("listing members: %w", err)
+	}
+
+	out := make([]OrganizationMemberOutput, 0, len(members))
+	for _, m := range members {
+		out = append(out, toMemberOutput(m))
+	}
+	return out, nil
+}
+
+// AddMemberInput contains the data needed to add a member.
+type AddMemberInput struct {
+	OrganizationID      string
+	Username            string
+	Role                string
+	RequesterExternalID string
+}
+
+// AddMember adds an existing user to the organization.
+// only owners can add other owners.
+func (uc *OrganizationUseCase) AddMember(ctx context.Context, input AddMemberInput) (*OrganizationMemberOutput, error) {
+	org, requester, err := uc.authorize(ctx, input.OrganizationID, input.RequesterExternalID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := domain.ParseOrganizationRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.Role().CanGrant(role) {
+		return nil, ErrOrganizationPermissionDenied
+	}
+
+	username, err := domain.ParseUsername(input.Username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username: %w", err)
+	}
+	user, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.orgRepo.FindMember(ctx, org.ID(), user.ID()); err == nil {
+		return nil, ErrOrganizationMemberExists
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("checking membership: %w", err)
+	}
+
+	member, err := domain.NewOrganizationMember(uc.clock, org.ID(), user.ID(), role)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.orgRepo.SaveMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("saving member: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("organization member added",
+		"organization_id", org.ID().String(),
+		"member_id", user.ID().String(),
+		"role", role.String(),
+	)
+
+	out := toMemberOutput(member)
+	return &out, nil
+}
+
+// UpdateMemberRoleInput contains the data needed to change a member's role.
+type UpdateMemberRoleInput struct {
+	OrganizationID      string
+	UserID              string
+	Role                string
+	RequesterExternalID string
+}
+
+// UpdateMemberRole changes a member's role.
+// only owners can promote to or demote from owner, and the last owner can't be demoted.
+func (uc *OrganizationUseCase) UpdateMemberRole(ctx context.Context, input UpdateMemberRoleInput) (*OrganizationMemberOutput, error) {
+	org, requester, err := uc.authorize(ctx, input.OrganizationID, input.RequesterExternalID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := domain.ParseOrganizationRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := uc.findMember(ctx, org.ID(), input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !requester.Role().CanGrant(role) || !requester.Role().CanGrant(member.Role()) {
+		return nil, ErrOrganizationPermissionDenied
+	}
+	if member.Role() == domain.OrganizationRoleOwner && role != domain.OrganizationRoleOwner {
+		if err := uc.ensureAnotherOwner(ctx, org.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := member.ChangeRole(role); err != nil {
+		return nil, err
+	}
+	if err := uc.orgRepo.SaveMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("saving member: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("organization member role changed",
+		"organization_id", org.ID().String(),
+		"member_id", member.UserID().String(),
+		"role", role.String(),
+	)
+
+	out := toMemberOutput(member)
+	return &out, nil
+}
+
+// RemoveMember removes a member. members may always remove themselves,
+// except the last owner.
+func (uc *OrganizationUseCase) RemoveMember(ctx context.Context, organizationID, userID, requesterExternalID string) error {
+	org, requester, err := uc.authorize(ctx, organizationID, requesterExternalID, false)
+	if err != nil {
+		return err
+	}
+
+	member, err := uc.findMember(ctx, org.ID(), userID)
+	if err != nil {
+		return err
+	}
+
+	leaving := member.UserID() == requester.UserID()
+	if !leaving && !requester.Role().CanGrant(member.Role()) {
+		return ErrOrganizationPermissionDenied
+	}
+	if member.Role() == domain.OrganizationRoleOwner {
+		if err := uc.ensureAnotherOwner(ctx, org.ID()); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.orgRepo.RemoveMember(ctx, org.ID(), member.UserID()); err != nil {
+		return fmt.Errorf("removing member: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("organization member removed",
+		"organization_id", org.ID().String(),
+		"member_id", member.UserID().String(),
+	)
+	return nil
+}
+
+// AttachCommunity moves a community into the organization.
+// the requester must manage the organization and have created the community.
+func (uc *OrganizationUseCase) AttachCommunity(ctx context.Context, organizationID, communityID, requesterExternalID string) error {
+	org, requester, err := uc.authorize(ctx, organizationID, requesterExternalID, true)
+	if err != nil {
+		return err
+	}
+
+	community, err := uc.findCommunity(ctx, communityID)
+	if err != nil {
+		return err
+	}
+
+	if community.CreatorID() != requester.UserID() {
+		return ErrNotCommunityOwner
+	}
+	if current := community.OrganizationID(); current != nil {
+		if *current == org.ID() {
+			return nil
+		}
+		return ErrCommunityInOtherOrganization
+	}
+
+	orgID := org.ID()
+	community.MoveToOrganization(&orgID)
+	if err := uc.communityRepo.Save(ctx, community); err != nil {
+		return fmt.Errorf("saving community: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("community attached to organization",
+		"organization_id", org.ID().String(),
+		"community_id", community.ID().String(),
+	)
+	return nil
+}
+
+// DetachCommunity makes an organization community standalone again.
+func (uc *OrganizationUseCase) DetachCommunity(ctx context.Context, organizationID, communityID, requesterExternalID string) error {
+	org, _, err := uc.authorize(ctx, organizationID, requesterExternalID, true)
+	if err != nil {
+		return err
+	}
+
+	community, err := uc.findCommunity(ctx, communityID)
+	if err != nil {
+		return err
+	}
+	if current := community.OrganizationID(); current == nil || *current != org.ID() {
+		return ErrCommunityNotInOrganization
+	}
+
+	community.MoveToOrganization(nil)
+	if err := uc.communityRepo.Save(ctx, community); err != nil {
+		return fmt.Errorf("saving community: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("community detached from organization",
+		"organization_id", org.ID().String(),
+		"community_id", community.ID().String(),
+	)
+	return nil
+}
+
+// Leaderboard returns the organization's communities ranked by momentum.
+func (uc *OrganizationUseCase) Leaderboard(ctx context.Context, organizationID, requesterExternalID string, limit, offset int) ([]*domain.Community, error) {
+	org, _, err := uc.authorize(ctx, organizationID, requesterExternalID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	communities, err := uc.communityRepo.ListByOrganization(ctx, org.ID(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing organization communities: %w", err)
+	}
+	return communities, nil
+}
+
+// CreateOrganizationAPIKeyOutput contains the issued key.
+// Key is the only time the plaintext is available.
+type CreateOrganizationAPIKeyOutput struct {
+	ID             string
+	Name           string
+	OrganizationID string
+	Key            string
+}
+
+// CreateAPIKey issues a key scoped to the organization, acting as the requester.
+func (uc *OrganizationUseCase) CreateAPIKey(ctx context.Context, organizationID, name, requesterExternalID string) (*CreateOrganizationAPIKeyOutput, error) {
+	org, requester, err := uc.authorize(ctx, organizationID, requesterExternalID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	key, plaintext, err := domain.NewOrganizationAPIKey(uc.clock, org.ID(), requester.UserID(), name)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.apiKeyRepo.Save(ctx, key); err != nil {
+		return nil, fmt.Errorf("saving api key: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("organization api key created",
+		"organization_id", org.ID().String(),
+		"api_key_id", key.ID().String(),
+		"name", key.Name(),
+	)
+
+	return &CreateOrganizationAPIKeyOutput{
+		ID:             key.ID().String(),
+		Name:           key.Name(),
+		OrganizationID: org.ID().String(),
+		Key:            plaintext,
+	}, nil
+}
+
+// canManageCommunity reports whether a user is an owner or admin of the organization a community belongs to.
+// standalone communities are only managed by their creator, so this returns false for them.
+func canManageCommunity(ctx context.Context, orgRepo domain.OrganizationRepository, community *domain.Community, userID domain.UserID) (bool, error) {
+	orgID := community.OrganizationID()
+	if orgID == nil || orgRepo == nil {
+		return false, nil
+	}
+
+	member, err := orgRepo.FindMember(ctx, *orgID, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("looking up organization membership: %w", err)
+	}
+	return member.Role().CanManage(), nil
+}
+
+// authorize loads the organization and the requester's membership.
+// manage requires an owner or admin.
+func (uc *OrganizationUseCase) authorize(ctx context.Context, organizationID, requesterExternalID string, manage bool) (*domain.Organization, *domain.OrganizationMember, error) {
+	id, err := domain.ParseOrganizationID(organizationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid organization id: %w", err)
+	}
+
+	requester, err := uc.requester(ctx, requesterExternalID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := uc.orgRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, err := uc.orgRepo.FindMember(ctx, id, requester.ID())
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			// don't reveal organizations to outsiders
+			return nil, nil, domain.ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("looking up membership: %w", err)
+	}
+
+	if manage && !member.Role().CanManage() {
+		return nil, nil, ErrOrganizationPermissionDenied
+	}
+	return org, member, nil
+}
+
+func (uc *OrganizationUseCase) requester(ctx context.Context, externalID string) (*domain.User, error) {
+	if externalID == "" {
+		return nil, ErrNotOrganizationMember
+	}
+	user, err := uc.userRepo.FindByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrCreatorNotFound
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	return user, nil
+}
+
+func (uc *OrganizationUseCase) findMember(ctx context.Context, orgID domain.OrganizationID, userID string) (*domain.OrganizationMember, error) {
+	id, err := domain.ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.orgRepo.FindMember(ctx, orgID, id)
+}
+
+func (uc *OrganizationUseCase) findCommunity(ctx context.Context, communityID string) (*domain.Community, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+	return uc.communityRepo.FindByID(ctx, id)
+}
+
+func (uc *OrganizationUseCase) ensureAnotherOwner(ctx context.Context, orgID domain.OrganizationID) error {
+	owners, err := uc.orgRepo.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOrganizationOwner
+	}
+	return nil
+}
+
+func toOrganizationOutput(org *domain.Organization, role domain.OrganizationRole) *OrganizationOutput {
+	return &OrganizationOutput{
+		ID:        org.ID().String(),
+		Slug:      org.Slug().String(),
+		Name:      org.Name(),
+		CreatedBy: org.CreatedBy().String(),
+		CreatedAt: org.CreatedAt(),
+		Role:      role.String(),
+	}
+}
+
+func toMemberOutput(m *domain.OrganizationMember) OrganizationMemberOutput {
+	return OrganizationMemberOutput{
+		UserID:   m.UserID().String(),
+		Role:     m.Role().String(),
+		JoinedAt: m.JoinedAt(),
+	}
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// defaultPruneBatchSize is how many events are deleted per statement.
+const defaultPruneBatchSize = 10000
+
+// ErrRetentionTooShort is returned when pruning would delete events momentum still reads.
+var ErrRetentionTooShort = fmt.Errorf("retention must be at least %s", domain.MaxMomentumTimeWindow)
+
+// PruneEventsUseCase deletes activity events older than a retention period.
+type PruneEventsUseCase struct {
+	eventRepo domain.ActivityEventRepository
+	clock     domain.Clock
+	logger    *logging.Logger
+}
+
+// NewPruneEventsUseCase creates a new PruneEventsUseCase.
+func NewPruneEventsUseCase(eventRepo domain.ActivityEventRepository, logger *logging.Logger) *PruneEventsUseCase {
+	return &PruneEventsUseCase{
+		eventRepo: eventRepo,
+		clock:     domain.SystemClock,
+		logger:    logger.WithComponent("prune_events"),
+	}
+}
+
+// PruneEventsInput contains the retention policy to apply.
+type PruneEventsInput struct {
+	// OlderThan is the retention period; events created before now-OlderThan are deleted.
+	// must cover the longest momentum window so scores don't change.
+	OlderThan time.Duration
+
+	// BatchSize is how many rows each DELETE removes, 0 for the default.
+	BatchSize int
+}
+
+// PruneEventsOutput contains the result of a prune run.
+type PruneEventsOutput struct {
+	Cutoff  time.Time
+	Deleted int64
+}
+
+// Execute deletes events older than the retention period in batches.
+// stops early if the context is cancelled, reporting what was deleted so far.
+func (uc *PruneEventsUseCase) Execute(ctx context.Context, input PruneEventsInput) (*PruneEventsOutput, error) {
+	if input.OlderThan < domain.MaxMomentumTimeWindow {
+		return nil, ErrRetentionTooShort
+	}
+
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPruneBatchSize
+	}
+
+	output := &PruneEventsOutput{
+		Cutoff: uc.clock.Now().Add(-input.OlderThan),
+	}
+
+	for {
+		deleted, err := uc.eventRepo.DeleteBefore(ctx, output.Cutoff, batchSize)
+		output.Deleted += deleted
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return output, err
+			}
+			uc.logger.Error("event prune failed",
+				"cutoff", output.Cutoff,
+				"deleted", output.Deleted,
+				"error", err.Error(),
+			)
+			return output, fmt.Errorf("pruning events: %w", err)
+		}
+		if deleted < int64(batchSize) {
+			break
+		}
+	}
+
+	uc.logger.Info("events pruned",
+		"cutoff", output.Cutoff,
+		"deleted", output.Deleted,
+	)
+
+	return output, nil
+}
@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrQuotaExceeded is wrapped by QuotaExceededError.
+var ErrQuotaExceeded = errors.New("ingestion quota exceeded")
+
+// QuotaExceededError is returned when an event is rejected for being over quota.
+type QuotaExceededError struct {
+	Scope   domain.UsageScope
+	ID      string
+	Limit   int64
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s %s is over its daily quota of %d events", e.Scope, e.ID, e.Limit)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// UsageCounter counts ingested events per scope and UTC day.
+type UsageCounter interface {
+	// AddEvents adds delta to the day's count and returns the new total.
+	AddEvents(ctx context.Context, scope domain.UsageScope, id string, day time.Time, delta int64) (int64, error)
+
+	// DailyEvents returns the count for each day, 0 for days without events.
+	DailyEvents(ctx context.Context, scope domain.UsageScope, id string, days []time.Time) ([]int64, error)
+}
+
+// OrganizationResolver finds the organization a community belongs to.
+type OrganizationResolver interface {
+	OrganizationOf(ctx context.Context, id domain.CommunityID) (*domain.OrganizationID, error)
+}
+
+// QuotaPolicy holds the default daily quotas. 0 means unlimited.
+type QuotaPolicy struct {
+	CommunityDailyEvents    int64
+	OrganizationDailyEvents int64
+	Mode                    domain.QuotaMode
+}
+
+// QuotaDecision is the outcome of counting an event.
+type QuotaDecision struct {
+	// Degraded is true when the event is over quota but accepted at reduced weight.
+	Degraded bool
+}
+
+// QuotaEnforcer counts ingested events per community and organization,
+// and refuses or degrades events once a daily quota is used up.
+// counting fails open: if the counter is unavailable, events are accepted.
+type QuotaEnforcer struct {
+	counter    UsageCounter
+	overrides  domain.IngestionQuotaRepository
+	orgs       OrganizationResolver
+	policy     QuotaPolicy
+	clock      domain.Clock
+	logger     *logging.Logger
+	onExceeded func(scope domain.UsageScope, mode domain.QuotaMode)
+}
+
+// QuotaEnforcerOption configures a QuotaEnforcer at construction.
+type QuotaEnforcerOption func(*QuotaEnforcer)
+
+// WithQuotaOverrides enables per-community and per-organization quotas.
+func WithQuotaOverrides(repo domain.IngestionQuotaRepository) QuotaEnforcerOption {
+	return func(q *QuotaEnforcer) {
+		q.overrides = repo
+	}
+}
+
+// WithOrganizationResolver counts events against the community's organization too.
+func WithOrganizationResolver(resolver OrganizationResolver) QuotaEnforcerOption {
+	return func(q *QuotaEnforcer) {
+		q.orgs = resolver
+	}
+}
+
+// WithQuotaExceededHook is called for every event over quota, e.g. to count it in metrics.
+func WithQuotaExceededHook(fn func(scope domain.UsageScope, mode domain.QuotaMode)) QuotaEnforcerOption {
+	return func(q *QuotaEnforcer) {
+		q.onExceeded = fn
+	}
+}
+
+// NewQuotaEnforcer creates a new QuotaEnforcer.
+func NewQuotaEnforcer(counter UsageCounter, policy QuotaPolicy, logger *logging.Logger, opts ...QuotaEnforcerOption) *QuotaEnforcer {
+	if policy.Mode == "" {
+		policy.Mode = domain.QuotaModeReject
+	}
+	q := &QuotaEnforcer{
+		counter: counter,
+		policy:  policy,
+		clock:   domain.SystemClock,
+		logger:  logger.WithComponent("quota"),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Mode returns what happens to events over quota.
+func (q *QuotaEnforcer) Mode() domain.QuotaMode {
+	return q.policy.Mode
+}
+
+type quotaScope struct {
+	scope domain.UsageScope
+	id    uuid.UUID
+}
+
+// Consume counts one event against the community and its organization.
+// in reject mode an event over either quota isn't counted and a *QuotaExceededError is returned.
+func (q *QuotaEnforcer) Consume(ctx context.Context, communityID domain.CommunityID) (QuotaDecision, error) {
+	log := q.logger.WithContext(ctx)
+	day := usageDay(q.clock.Now())
+
+	scopes := []quotaScope{{domain.UsageScopeCommunity, communityID.UUID()}}
+	if q.orgs != nil {
+		orgID, err := q.orgs.OrganizationOf(ctx, communityID)
+		if err != nil {
+			log.Warn("quota: organization lookup failed, counting community only",
+				"error", err.Error(),
+			)
+		} else if orgID != nil {
+			scopes = append(scopes, quotaScope{domain.UsageScopeOrganization, orgID.UUID()})
+		}
+	}
+
+	var (
+		counted  []quotaScope
+		exceeded *QuotaExceededError
+	)
+	for _, s := range scopes {
+		used, err := q.counter.AddEvents(ctx, s.scope, s.id.String(), day, 1)
+		if err != nil {
+			log.Warn("quota: usage counter failed, accepting event",
+				"scope", s.scope.String(),
+				"error", err.Error(),
+			)
+			continue
+		}
+		counted = append(counted, s)
+
+		if limit := q.Limit(ctx, s.scope, s.id); limit > 0 && used > limit && exceeded == nil {
+			exceeded = &QuotaExceededError{
+				Scope:   s.scope,
+				ID:      s.id.String(),
+				Limit:   limit,
+				ResetAt: day.Add(24 * time.Hour),
+			}
+		}
+	}
+
+	if exceeded == nil {
+		return QuotaDecision{}, nil
+	}
+
+	if q.onExceeded != nil {
+		q.onExceeded(exceeded.Scope, q.policy.Mode)
+	}
+
+	if q.policy.Mode == domain.QuotaModeDegrade {
+		return QuotaDecision{Degraded: true}, nil
+	}
+
+	// rejected events don't count as usage
+	for _, s := range counted {
+		if _, err := q.counter.AddEvents(ctx, s.scope, s.id.String(), day, -1); err != nil {
+			log.Warn("quota: failed to uncount rejected event",
+				"scope", s.scope.String(),
+				"error", err.Error(),
+			)
+		}
+	}
+	return QuotaDecision{}, exceeded
+}
+
+// Limit returns the daily quota for a scope: its override if it has one, otherwise the default.
+// 0 means unlimited.
+func (q *QuotaEnforcer) Limit(ctx context.Context, scope domain.UsageScope, id uuid.UUID) int64 {
+	if q.overrides != nil {
+		override, err := q.overrides.Find(ctx, scope, id)
+		switch {
+		case err == nil:
+			return override.DailyEvents()
+		case !errors.Is(err, domain.ErrNotFound):
+			q.logger.WithContext(ctx).Warn("quota: override lookup failed, using default",
+				"scope", scope.String(),
+				"error", err.Error(),
+			)
+		}
+	}
+
+	if scope == domain.UsageScopeOrganization {
+		return q.policy.OrganizationDailyEvents
+	}
+	return q.policy.CommunityDailyEvents
+}
+
+// usageDay truncates t to the start of its UTC day, the unit usage is counted in.
+func usageDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
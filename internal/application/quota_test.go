@@ -0,0 +1,101 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// fakeUsageCounter keeps counts in a map keyed by scope and id; days are ignored.
+type fakeUsageCounter map[string]int64
+
+func (f fakeUsageCounter) AddEvents(_ context.Context, scope domain.UsageScope, id string, _ time.Time, delta int64) (int64, error) {
+	f[scope.String()+id] += delta
+	return f[scope.String()+id], nil
+}
+
+func (f fakeUsageCounter) DailyEvents(_ context.Context, scope domain.UsageScope, id string, days []time.Time) ([]int64, error) {
+	counts := make([]int64, len(days))
+	counts[0] = f[scope.String()+id]
+	return counts, nil
+}
+
+type fixedOrganization struct{ id domain.OrganizationID }
+
+func (f fixedOrganization) OrganizationOf(context.Context, domain.CommunityID) (*domain.OrganizationID, error) {
+	return &f.id, nil
+}
+
+func TestQuotaEnforcer_Reject(t *testing.T) {
+	counter := fakeUsageCounter{}
+	orgID := domain.NewOrganizationID()
+	q := NewQuotaEnforcer(counter, QuotaPolicy{CommunityDailyEvents: 5, OrganizationDailyEvents: 2}, logging.New(),
+		WithOrganizationResolver(fixedOrganization{orgID}),
+	)
+	q.clock = domain.FixedClock(time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC))
+	communityID := domain.NewCommunityID()
+
+	for i := 0; i < 2; i++ {
+		if _, err := q.Consume(context.Background(), communityID); err != nil {
+			t.Fatalf("event %d: unexpected error: %v", i, err)
+		}
+	}
+
+	_, err := q.Consume(context.Background(), communityID)
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected QuotaExceededError, got %v", err)
+	}
+	if quotaErr.Scope != domain.UsageScopeOrganization || quotaErr.Limit != 2 {
+		t.Errorf("unexpected error details: %+v", quotaErr)
+	}
+	if want := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC); !quotaErr.ResetAt.Equal(want) {
+		t.Errorf("reset at = %v, want %v", quotaErr.ResetAt, want)
+	}
+
+	// rejected events aren't counted
+	if got := counter["community"+communityID.String()]; got != 2 {
+		t.Errorf("community usage = %d, want 2", got)
+	}
+}
+
+func TestQuotaEnforcer_Degrade(t *testing.T) {
+	var hooked []domain.QuotaMode
+	q := NewQuotaEnforcer(fakeUsageCounter{}, QuotaPolicy{CommunityDailyEvents: 1, Mode: domain.QuotaModeDegrade}, logging.New(),
+		WithQuotaExceededHook(func(_ domain.UsageScope, mode domain.QuotaMode) {
+			hooked = append(hooked, mode)
+		}),
+	)
+	communityID := domain.NewCommunityID()
+
+	first, err := q.Consume(context.Background(), communityID)
+	if err != nil || first.Degraded {
+		t.Fatalf("first event: degraded=%v err=%v", first.Degraded, err)
+	}
+
+	second, err := q.Consume(context.Background(), communityID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !second.Degraded {
+		t.Error("expected the event over quota to be degraded")
+	}
+	if len(hooked) != 1 || hooked[0] != domain.QuotaModeDegrade {
+		t.Errorf("hook calls = %v", hooked)
+	}
+}
+
+func TestQuotaEnforcer_Unlimited(t *testing.T) {
+	q := NewQuotaEnforcer(fakeUsageCounter{}, QuotaPolicy{}, logging.New())
+	communityID := domain.NewCommunityID()
+
+	for i := 0; i < 100; i++ {
+		if decision, err := q.Consume(context.Background(), communityID); err != nil || decision.Degraded {
+			t.Fatalf("event %d: degraded=%v err=%v", i, decision.Degraded, err)
+		}
+	}
+}
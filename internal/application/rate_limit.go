@@ -0,0 +1,153 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// RateLimiter counts each caller's requests in fixed windows shared by every caller.
+// it never refuses a request: callers read their budget from the status and pace
+// themselves. counts are kept in memory, so each instance counts its own requests.
+type RateLimiter struct {
+	limit  int64
+	window time.Duration
+	clock  domain.Clock
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int64
+}
+
+// RateLimitStatus is a caller's budget after a request.
+type RateLimitStatus struct {
+	Limit     int64
+	Remaining int64     // 0 once the limit is reached
+	Reset     time.Time // when the window ends and the budget is full again
+	Exceeded  bool      // the request was over the limit
+}
+
+// NewRateLimiter allows limit requests per caller in each window.
+func NewRateLimiter(limit int64, window time.Duration, clock domain.Clock) *RateLimiter {
+	if clock == nil {
+		clock = domain.SystemClock
+	}
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		clock:  clock,
+		counts: make(map[string]int64),
+	}
+}
+
+// Take counts a request for a caller and returns its budget. a new window drops every
+// count, so memory stays bounded by the callers of one window.
+func (l *RateLimiter) Take(caller string) RateLimitStatus {
+	start := l.clock.Now().Truncate(l.window)
+
+	l.mu.Lock()
+	if !start.Equal(l.windowStart) {
+		l.windowStart = start
+		clear(l.counts)
+	}
+	l.counts[caller]++
+	count := l.counts[caller]
+	l.mu.Unlock()
+
+	return RateLimitStatus{
+		Limit:     l.limit,
+		Remaining: max(l.limit-count, 0),
+		Reset:     start.Add(l.window),
+		Exceeded:  count > l.limit,
+	}
+}
+
+// TokenBucketPolicy refills a caller's bucket at Rate tokens per second, up to Burst.
+// each request takes one token, so a caller averages Rate requests per second
+// after a burst of up to Burst.
+type TokenBucketPolicy struct {
+	Rate  float64
+	Burst int64
+}
+
+// TokenBucketStatus is a caller's bucket after a request.
+type TokenBucketStatus struct {
+	Allowed    bool
+	Remaining  int64         // whole tokens left
+	RetryAfter time.Duration // until the next token, 0 when allowed
+}
+
+// TokenBuckets takes a token from a caller's bucket for each request. implemented in
+// memory by MemoryTokenBuckets and in redis, shared by every instance, by the cache package.
+type TokenBuckets interface {
+	Take(ctx context.Context, key string, policy TokenBucketPolicy) (TokenBucketStatus, error)
+}
+
+// MemoryTokenBuckets keeps token buckets in process memory.
+// used when redis is disabled; each instance limits only the requests it serves.
+type MemoryTokenBuckets struct {
+	clock domain.Clock
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time // when the bucket is full again and can be dropped
+}
+
+// NewMemoryTokenBuckets creates in-memory token buckets.
+func NewMemoryTokenBuckets(clock domain.Clock) *MemoryTokenBuckets {
+	if clock == nil {
+		clock = domain.SystemClock
+	}
+	return &MemoryTokenBuckets{clock: clock, buckets: make(map[string]*tokenBucket)}
+}
+
+// Take refills the caller's bucket for the time since its last request and takes a token.
+// a caller without a bucket starts with a full one.
+func (m *MemoryTokenBuckets) Take(_ context.Context, key string, policy TokenBucketPolicy) (TokenBucketStatus, error) {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(policy.Burst), updated: now}
+		m.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updated).Seconds()
+	bucket.tokens = min(float64(policy.Burst), bucket.tokens+max(elapsed, 0)*policy.Rate)
+	bucket.updated = now
+
+	status := TokenBucketStatus{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		status.Allowed = true
+	} else {
+		status.RetryAfter = time.Duration((1 - bucket.tokens) / policy.Rate * float64(time.Second))
+	}
+	status.Remaining = int64(bucket.tokens)
+	bucket.fullAt = now.Add(time.Duration((float64(policy.Burst) - bucket.tokens) / policy.Rate * float64(time.Second)))
+	return status, nil
+}
+
+// Cleanup drops the buckets that refilled, which are the same as no bucket.
+// call this periodically to prevent memory growth.
+func (m *MemoryTokenBuckets) Cleanup() {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, bucket := range m.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
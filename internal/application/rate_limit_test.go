@@ -0,0 +1,101 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+func TestRateLimiter_Take(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 10, 0, time.UTC)
+	limiter := NewRateLimiter(2, time.Minute, domain.FixedClock(start))
+
+	first := limiter.Take("user:a")
+	if first.Remaining != 1 || first.Exceeded {
+		t.Errorf("first request: remaining %d, exceeded %v, want 1, false", first.Remaining, first.Exceeded)
+	}
+	if want := time.Date(2025, 3, 1, 12, 1, 0, 0, time.UTC); !first.Reset.Equal(want) {
+		t.Errorf("reset = %v, want %v", first.Reset, want)
+	}
+
+	limiter.Take("user:a")
+	over := limiter.Take("user:a")
+	if over.Remaining != 0 || !over.Exceeded {
+		t.Errorf("third request: remaining %d, exceeded %v, want 0, true", over.Remaining, over.Exceeded)
+	}
+
+	// callers are counted apart
+	if other := limiter.Take("user:b"); other.Remaining != 1 {
+		t.Errorf("other caller remaining = %d, want 1", other.Remaining)
+	}
+}
+
+func TestRateLimiter_NewWindowResets(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 59, 0, time.UTC)
+	limiter := NewRateLimiter(1, time.Minute, domain.FixedClock(start))
+	limiter.Take("user:a")
+	if !limiter.Take("user:a").Exceeded {
+		t.Fatal("expected the second request to exceed the limit")
+	}
+
+	limiter.clock = domain.FixedClock(start.Add(time.Second))
+	status := limiter.Take("user:a")
+	if status.Exceeded || status.Remaining != 0 {
+		t.Errorf("after reset: remaining %d, exceeded %v, want 0, false", status.Remaining, status.Exceeded)
+	}
+	if len(limiter.counts) != 1 {
+		t.Errorf("kept %d callers, want only the current window's", len(limiter.counts))
+	}
+}
+
+func TestMemoryTokenBuckets_Take(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	buckets := NewMemoryTokenBuckets(domain.FixedClock(start))
+	policy := TokenBucketPolicy{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	// a new caller has the whole burst
+	for i := 3; i > 0; i-- {
+		status, _ := buckets.Take(ctx, "events:user:a", policy)
+		if !status.Allowed || status.Remaining != int64(i-1) {
+			t.Fatalf("request %d: allowed %v, remaining %d", 4-i, status.Allowed, status.Remaining)
+		}
+	}
+
+	over, _ := buckets.Take(ctx, "events:user:a", policy)
+	if over.Allowed || over.RetryAfter != 500*time.Millisecond {
+		t.Errorf("over the burst: allowed %v, retry after %v, want false, 500ms", over.Allowed, over.RetryAfter)
+	}
+
+	// callers are limited apart
+	if other, _ := buckets.Take(ctx, "events:user:b", policy); !other.Allowed {
+		t.Error("expected another caller to be allowed")
+	}
+
+	// two tokens a second
+	buckets.clock = domain.FixedClock(start.Add(time.Second))
+	refilled, _ := buckets.Take(ctx, "events:user:a", policy)
+	if !refilled.Allowed || refilled.Remaining != 1 {
+		t.Errorf("after a second: allowed %v, remaining %d, want true, 1", refilled.Allowed, refilled.Remaining)
+	}
+}
+
+func TestMemoryTokenBuckets_Cleanup(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	buckets := NewMemoryTokenBuckets(domain.FixedClock(start))
+	policy := TokenBucketPolicy{Rate: 1, Burst: 10}
+
+	buckets.Take(context.Background(), "api:ip:10.0.0.1", policy)
+	buckets.Cleanup()
+	if len(buckets.buckets) != 1 {
+		t.Fatal("expected a bucket still refilling to be kept")
+	}
+
+	buckets.clock = domain.FixedClock(start.Add(time.Second))
+	buckets.Cleanup()
+	if len(buckets.buckets) != 0 {
+		t.Error("expected a full bucket to be dropped")
+	}
+}
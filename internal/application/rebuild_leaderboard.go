@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// defaultRebuildBatchSize is how many communities are read from postgres per page.
+const defaultRebuildBatchSize = 500
+
+// ErrLeaderboardRebuildInProgress is returned when another rebuild holds the lock.
+var ErrLeaderboardRebuildInProgress = errors.New("leaderboard rebuild already in progress")
+
+// LeaderboardRebuilder replaces the whole leaderboard cache at once.
+// scores are staged out of sight and swapped in on commit, so readers
+// keep the old leaderboard until the new one is complete.
+type LeaderboardRebuilder interface {
+	// BeginLeaderboardRebuild takes the rebuild lock, returns false if it's held.
+	BeginLeaderboardRebuild(ctx context.Context) (bool, error)
+	StageLeaderboardScores(ctx context.Context, scores map[string]float64) error
+	CommitLeaderboardRebuild(ctx context.Context) error
+	AbortLeaderboardRebuild(ctx context.Context) error
+}
+
+// RebuildLeaderboardUseCase truncates and repopulates the leaderboard cache from postgres.
+// postgres is the source of truth, so this is safe to run at any time,
+// e.g. after redis lost its data or drifted from postgres.
+type RebuildLeaderboardUseCase struct {
+	communityRepo domain.CommunityRepository
+	leaderboard   LeaderboardRebuilder
+	logger        *logging.Logger
+}
+
+// NewRebuildLeaderboardUseCase creates a new RebuildLeaderboardUseCase.
+// communityRepo should read from postgres, not through the leaderboard cache being rebuilt.
+func NewRebuildLeaderboardUseCase(
+	communityRepo domain.CommunityRepository,
+	leaderboard LeaderboardRebuilder,
+	logger *logging.Logger,
+) *RebuildLeaderboardUseCase {
+	return &RebuildLeaderboardUseCase{
+		communityRepo: communityRepo,
+		leaderboard:   leaderboard,
+		logger:        logger.WithComponent("rebuild_leaderboard"),
+	}
+}
+
+// RebuildLeaderboardInput controls the rebuild.
+type RebuildLeaderboardInput struct {
+	// BatchSize is how many communities are loaded and staged per page, 0 for the default.
+	BatchSize int
+}
+
+// RebuildLeaderboardOutput contains the result of a rebuild.
+type RebuildLeaderboardOutput struct {
+	Communities int
+	Batches     int
+	Duration    time.Duration
+}
+
+// Execute stages the current momentum of every active public community and swaps it in.
+// communities that are no longer active or public drop out of the leaderboard.
+// on error the live leaderboard is left untouched.
+// momentum updates landing mid-rebuild are overwritten by the swap and
+// corrected on the next momentum cycle.
+func (uc *RebuildLeaderboardUseCase) Execute(ctx context.Context, input RebuildLeaderboardInput) (*RebuildLeaderboardOutput, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRebuildBatchSize
+	}
+
+	acquired, err := uc.leaderboard.BeginLeaderboardRebuild(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting leaderboard rebuild: %w", err)
+	}
+	if !acquired {
+		return nil, ErrLeaderboardRebuildInProgress
+	}
+
+	start := time.Now()
+	output := &RebuildLeaderboardOutput{}
+
+	if err := uc.stage(ctx, batchSize, output); err != nil {
+		if abortErr := uc.leaderboard.AbortLeaderboardRebuild(context.WithoutCancel(ctx)); abortErr != nil {
+			uc.logger.Warn("failed to abort leaderboard rebuild", "error", abortErr.Error())
+		}
+		uc.logger.Error("leaderboard rebuild failed",
+			"staged", output.Communities,
+			"error", err.Error(),
+		)
+		return output, err
+	}
+
+	if err := uc.leaderboard.CommitLeaderboardRebuild(ctx); err != nil {
+		return output, fmt.Errorf("swapping leaderboard: %w", err)
+	}
+
+	output.Duration = time.Since(start)
+	uc.logger.Info("leaderboard rebuilt",
+		"communities", output.Communities,
+		"batches", output.Batches,
+		"duration_ms", output.Duration.Milliseconds(),
+	)
+	return output, nil
+}
+
+func (uc *RebuildLeaderboardUseCase) stage(ctx context.Context, batchSize int, output *RebuildLeaderboardOutput) error {
+	for offset := 0; ; offset += batchSize {
+		communities, err := uc.communityRepo.ListPublicByMomentum(ctx, batchSize, offset)
+		if err != nil {
+			return fmt.Errorf("listing communities: %w", err)
+		}
+
+		scores := make(map[string]float64, len(communities))
+		for _, community := range communities {
+			scores[community.ID().String()] = community.CurrentMomentum().Value()
+		}
+		if err := uc.leaderboard.StageLeaderboardScores(ctx, scores); err != nil {
+			return fmt.Errorf("staging leaderboard scores: %w", err)
+		}
+
+		output.Communities += len(communities)
+		output.Batches++
+
+		if len(communities) < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// defaultReplayBatchSize is how many events are written per SaveBatch call.
+const defaultReplayBatchSize = 5000
+
+// EventArchive is a set of archived event files (NDJSON, see archive.Record).
+type EventArchive interface {
+	// Files lists the archive files in chronological (lexical) order.
+	Files(ctx context.Context) ([]string, error)
+
+	// ReadFile streams the events of one file to fn.
+	ReadFile(ctx context.Context, name string, fn func(*domain.ActivityEvent) error) error
+}
+
+// ReplayEventsUseCase re-ingests archived events through SaveBatch.
+// events keep their original ids and timestamps, so momentum computed after a
+// replay matches what it was when the events first arrived.
+type ReplayEventsUseCase struct {
+	eventRepo domain.ActivityEventRepository
+	logger    *logging.Logger
+}
+
+// NewReplayEventsUseCase creates a new ReplayEventsUseCase.
+func NewReplayEventsUseCase(eventRepo domain.ActivityEventRepository, logger *logging.Logger) *ReplayEventsUseCase {
+	return &ReplayEventsUseCase{
+		eventRepo: eventRepo,
+		logger:    logger.WithComponent("replay_events"),
+	}
+}
+
+// ReplayEventsInput configures a replay run.
+type ReplayEventsInput struct {
+	Archive EventArchive
+
+	// StartAfter skips files up to and including this name, to resume an interrupted replay.
+	StartAfter string
+
+	// BatchSize is how many events each SaveBatch writes, 0 for the default.
+	BatchSize int
+
+	// DryRun reads and validates every file without writing.
+	DryRun bool
+}
+
+// ReplayEventsOutput reports what was replayed.
+type ReplayEventsOutput struct {
+	Files  int
+	Events int64
+
+	// LastFile is the last fully replayed file, pass it as StartAfter to resume.
+	LastFile string
+}
+
+// Execute replays every archive file after StartAfter in order.
+// a file is flushed completely before the next starts, so everything up to LastFile is written.
+// ids are preserved, so replaying events that are still in the database fails on the
+// primary key: replay into a fresh database, or resume after LastFile once any partial
+// batches of the failed file are cleaned up.
+func (uc *ReplayEventsUseCase) Execute(ctx context.Context, input ReplayEventsInput) (*ReplayEventsOutput, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultReplayBatchSize
+	}
+
+	files, err := input.Archive.Files(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing archive: %w", err)
+	}
+
+	output := &ReplayEventsOutput{}
+	batch := make([]*domain.ActivityEvent, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !input.DryRun {
+			if err := uc.eventRepo.SaveBatch(ctx, batch); err != nil {
+				return err
+			}
+		}
+		output.Events += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, file := range files {
+		if input.StartAfter != "" && file <= input.StartAfter {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return output, err
+		}
+
+		before := output.Events
+		err := input.Archive.ReadFile(ctx, file, func(event *domain.ActivityEvent) error {
+			batch = append(batch, event)
+			if len(batch) >= batchSize {
+				return flush()
+			}
+			return nil
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			uc.logger.Error("replay failed",
+				"file", file,
+				"resume_after", output.LastFile,
+				"error", err.Error(),
+			)
+			return output, fmt.Errorf("replaying %s: %w", file, err)
+		}
+
+		output.Files++
+		output.LastFile = file
+		uc.logger.Info("archive file replayed",
+			"file", file,
+			"events", output.Events-before,
+			"total_events", output.Events,
+			"dry_run", input.DryRun,
+		)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,364 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// seedEventBatchSize is how many events are written per SaveBatch call.
+const seedEventBatchSize = 5000
+
+// ErrAlreadySeeded is returned when the dataset for a seed is already in the database.
+var ErrAlreadySeeded = errors.New("dataset for this seed is already present")
+
+// SeedConfig describes the dataset to generate.
+// the same config always produces the same users, communities and events,
+// so CI runs and bug reports can refer to a dataset by its seed.
+type SeedConfig struct {
+	// Seed drives every random choice.
+	Seed uint64
+
+	Users       int
+	Communities int
+	Events      int
+
+	// Span is how far back events are spread from Anchor.
+	Span time.Duration
+
+	// Anchor is the "now" of the dataset. pin it to get identical timestamps across runs.
+	Anchor time.Time
+}
+
+// DefaultSeedConfig returns a small dataset anchored at the current hour.
+func DefaultSeedConfig() SeedConfig {
+	return SeedConfig{
+		Seed:        1,
+		Users:       200,
+		Communities: 10,
+		Events:      10000,
+		Span:        7 * 24 * time.Hour,
+		Anchor:      time.Now().UTC().Truncate(time.Hour),
+	}
+}
+
+// SeedDataset is a generated dataset, ready to persist.
+type SeedDataset struct {
+	Users       []*domain.User
+	Communities []*domain.Community
+	Events      []*domain.ActivityEvent
+}
+
+// seedEventMix is the share of each event type, roughly what a forum sees.
+var seedEventMix = []struct {
+	eventType domain.EventType
+	share     float64
+}{
+	{domain.EventTypeView, 0.58},
+	{domain.EventTypeReaction, 0.15},
+	{domain.EventTypeComment, 0.10},
+	{domain.EventTypePost, 0.06},
+	{domain.EventTypeJoin, 0.05},
+	{domain.EventTypeShare, 0.04},
+	{domain.EventTypeLeave, 0.02},
+}
+
+var (
+	seedAdjectives = []string{
+		"quiet", "brave", "lucky", "rapid", "sunny", "clever", "gentle", "wild",
+		"calm", "eager", "fuzzy", "jolly", "mellow", "nimble", "proud", "witty",
+	}
+	seedNouns = []string{
+		"otter", "falcon", "maple", "comet", "badger", "harbor", "pixel", "river",
+		"cedar", "lynx", "quartz", "sparrow", "tundra", "walrus", "ember", "fjord",
+	}
+	seedTopics = []string{
+		"golang", "rust", "photography", "gardening", "chess", "climbing", "baking",
+		"astronomy", "vinyl", "cycling", "woodworking", "retro-gaming", "coffee",
+		"birdwatching", "synths", "mechanical-keyboards", "running", "sci-fi",
+		"home-lab", "typography", "urban-sketching", "fermentation", "aquariums",
+		"bouldering", "film-cameras", "indie-games", "tea", "pottery", "linux", "jazz",
+	}
+)
+
+// GenerateSeedData builds a deterministic dataset through the domain constructors.
+//
+// community popularity follows a power law, so a handful dominate like real
+// leaderboards. activity peaks in the evening (UTC), and about one in ten
+// communities is "trending" with a burst in the last hour so spikes fire.
+func GenerateSeedData(cfg SeedConfig) (*SeedDataset, error) {
+	if cfg.Users <= 0 || cfg.Communities <= 0 || cfg.Events < 0 || cfg.Span <= 0 {
+		return nil, fmt.Errorf("seed config: users, communities and span must be positive: %w", domain.ErrInvalidInput)
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15))
+	newUUID := func() uuid.UUID {
+		var b [16]byte
+		for i := range b {
+			b[i] = byte(rng.UintN(256))
+		}
+		// stamp version 4 / RFC 4122 variant bits so ids look like any other uuid
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return uuid.UUID(b)
+	}
+
+	dataset := &SeedDataset{
+		Users:       make([]*domain.User, 0, cfg.Users),
+		Communities: make([]*domain.Community, 0, cfg.Communities),
+		Events:      make([]*domain.ActivityEvent, 0, cfg.Events),
+	}
+	origin := cfg.Anchor.Add(-cfg.Span)
+
+	// users
+	for i := 0; i < cfg.Users; i++ {
+		username, err := domain.NewUsername(fmt.Sprintf("%s_%s_%d",
+			seedAdjectives[rng.IntN(len(seedAdjectives))],
+			seedNouns[rng.IntN(len(seedNouns))],
+			i,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("seed user %d: %w", i, err)
+		}
+
+		createdAt := origin.Add(-time.Duration(rng.Int64N(int64(30 * 24 * time.Hour))))
+		dataset.Users = append(dataset.Users, domain.ReconstructUser(
+			domain.UserIDFromUUID(newUUID()),
+			newUUID().String(),
+			username,
+			username.String(),
+			"",
+			"",
+			createdAt,
+			createdAt,
+		))
+	}
+
+	// communities, with power-law popularity weights
+	popularity := make([]float64, cfg.Communities)
+	trending := make([]bool, cfg.Communities)
+	var totalPopularity float64
+
+	for i := 0; i < cfg.Communities; i++ {
+		topic := seedTopics[i%len(seedTopics)]
+		slugValue := topic
+		if round := i / len(seedTopics); round > 0 {
+			slugValue = fmt.Sprintf("%s-%d", topic, round+1)
+		}
+		slug, err := domain.NewSlug(slugValue)
+		if err != nil {
+			return nil, fmt.Errorf("seed community %d: %w", i, err)
+		}
+
+		creator := dataset.Users[rng.IntN(len(dataset.Users))]
+		createdAt := origin.Add(-time.Duration(rng.Int64N(int64(14 * 24 * time.Hour))))
+		community, err := domain.NewCommunity(domain.FixedClock(createdAt), slug, seedCommunityName(slugValue), creator.ID())
+		if err != nil {
+			return nil, fmt.Errorf("seed community %d: %w", i, err)
+		}
+
+		dataset.Communities = append(dataset.Communities, domain.ReconstructCommunity(
+			domain.CommunityIDFromUUID(newUUID()),
+			community.Slug(),
+			community.Name(),
+			fmt.Sprintf("a place to talk about %s", slugValue),
+			community.CreatorID(),
+			nil,
+			"",
+			true,
+			domain.VisibilityPublic,
+			domain.NewMomentum(0),
+			nil,
+			community.CreatedAt(),
+			community.UpdatedAt(),
+		))
+
+		popularity[i] = 1 / math.Pow(float64(i+1), 1.1)
+		totalPopularity += popularity[i]
+		trending[i] = rng.Float64() < 0.1
+	}
+
+	// cumulative distributions for weighted picks
+	communityCDF := cumulative(popularity, totalPopularity)
+	mixShares := make([]float64, len(seedEventMix))
+	for i, m := range seedEventMix {
+		mixShares[i] = m.share
+	}
+	mixCDF := cumulative(mixShares, 1)
+
+	// events
+	for i := 0; i < cfg.Events; i++ {
+		ci := pick(communityCDF, rng.Float64())
+		eventType := seedEventMix[pick(mixCDF, rng.Float64())].eventType
+
+		var createdAt time.Time
+		if trending[ci] && rng.Float64() < 0.3 {
+			createdAt = cfg.Anchor.Add(-time.Duration(rng.Int64N(int64(time.Hour))))
+		} else {
+			createdAt = seedTimestamp(rng, origin, cfg.Span)
+		}
+
+		// a third of views are anonymous, every other event has an author
+		var userID *domain.UserID
+		if eventType != domain.EventTypeView || rng.Float64() >= 0.33 {
+			id := dataset.Users[rng.IntN(len(dataset.Users))].ID()
+			userID = &id
+		}
+
+		event, err := domain.NewActivityEventWithDefaultWeight(domain.FixedClock(createdAt), dataset.Communities[ci].ID(), userID, eventType, nil)
+		if err != nil {
+			return nil, fmt.Errorf("seed event %d: %w", i, err)
+		}
+
+		dataset.Events = append(dataset.Events, domain.ReconstructActivityEvent(
+			domain.EventIDFromUUID(newUUID()),
+			event.CommunityID(),
+			event.UserID(),
+			event.EventType(),
+			event.Weight(),
+			map[string]any{"source": "seed"},
+			event.CreatedAt(),
+			event.CreatedAt(),
+		))
+	}
+
+	return dataset, nil
+}
+
+// seedTimestamp picks a time in [origin, origin+span) with a daily rhythm:
+// quiet around 06:00 UTC, busiest around 18:00 UTC.
+func seedTimestamp(rng *rand.Rand, origin time.Time, span time.Duration) time.Time {
+	for {
+		t := origin.Add(time.Duration(rng.Int64N(int64(span))))
+		hour := float64(t.Hour()) + float64(t.Minute())/60
+		acceptance := 0.55 + 0.45*math.Sin((hour-12)/24*2*math.Pi)
+		if rng.Float64() < acceptance {
+			return t
+		}
+	}
+}
+
+func seedCommunityName(slug string) string {
+	name := []byte(slug)
+	upper := true
+	for i, c := range name {
+		if c == '-' {
+			name[i] = ' '
+			upper = true
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			name[i] = c - 'a' + 'A'
+		}
+		upper = false
+	}
+	return string(name)
+}
+
+func cumulative(weights []float64, total float64) []float64 {
+	cdf := make([]float64, len(weights))
+	var acc float64
+	for i, w := range weights {
+		acc += w / total
+		cdf[i] = acc
+	}
+	return cdf
+}
+
+func pick(cdf []float64, r float64) int {
+	i := sort.SearchFloat64s(cdf, r)
+	if i >= len(cdf) {
+		return len(cdf) - 1
+	}
+	return i
+}
+
+// SeedUseCase generates a dataset and writes it through the repositories.
+type SeedUseCase struct {
+	userRepo      domain.UserRepository
+	communityRepo domain.CommunityRepository
+	eventRepo     domain.ActivityEventRepository
+	logger        *logging.Logger
+}
+
+// NewSeedUseCase creates a new SeedUseCase.
+func NewSeedUseCase(
+	userRepo domain.UserRepository,
+	communityRepo domain.CommunityRepository,
+	eventRepo domain.ActivityEventRepository,
+	logger *logging.Logger,
+) *SeedUseCase {
+	return &SeedUseCase{
+		userRepo:      userRepo,
+		communityRepo: communityRepo,
+		eventRepo:     eventRepo,
+		logger:        logger.WithComponent("seed"),
+	}
+}
+
+// SeedOutput reports what was written.
+type SeedOutput struct {
+	Users       int
+	Communities int
+	Events      int
+}
+
+// Execute generates the dataset for cfg and persists it.
+// ids are derived from the seed, so running the same seed twice returns ErrAlreadySeeded
+// instead of duplicating events.
+func (uc *SeedUseCase) Execute(ctx context.Context, cfg SeedConfig) (*SeedOutput, error) {
+	dataset, err := GenerateSeedData(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := uc.communityRepo.Exists(ctx, dataset.Communities[0].ID())
+	if err != nil {
+		return nil, fmt.Errorf("checking existing seed data: %w", err)
+	}
+	if exists {
+		return nil, ErrAlreadySeeded
+	}
+
+	for _, user := range dataset.Users {
+		if err := uc.userRepo.Save(ctx, user); err != nil {
+			return nil, fmt.Errorf("saving user %s: %w", user.Username().String(), err)
+		}
+	}
+
+	for _, community := range dataset.Communities {
+		if err := uc.communityRepo.Save(ctx, community); err != nil {
+			return nil, fmt.Errorf("saving community %s: %w", community.Slug().String(), err)
+		}
+	}
+
+	for start := 0; start < len(dataset.Events); start += seedEventBatchSize {
+		end := min(start+seedEventBatchSize, len(dataset.Events))
+		if err := uc.eventRepo.SaveBatch(ctx, dataset.Events[start:end]); err != nil {
+			return nil, fmt.Errorf("saving events %d-%d: %w", start, end, err)
+		}
+		uc.logger.Info("seed events written", "written", end, "total", len(dataset.Events))
+	}
+
+	uc.logger.Info("seed completed",
+		"seed", cfg.Seed,
+		"users", len(dataset.Users),
+		"communities", len(dataset.Communities),
+		"events", len(dataset.Events),
+	)
+
+	return &SeedOutput{
+		Users:       len(dataset.Users),
+		Communities: len(dataset.Communities),
+		Events:      len(dataset.Events),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+func TestGenerateSeedData_Deterministic(t *testing.T) {
+	cfg := SeedConfig{
+		Seed:        42,
+		Users:       20,
+		Communities: 35,
+		Events:      2000,
+		Span:        48 * time.Hour,
+		Anchor:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	a, err := GenerateSeedData(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := GenerateSeedData(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(a.Events) != cfg.Events || len(a.Communities) != cfg.Communities || len(a.Users) != cfg.Users {
+		t.Fatalf("unexpected sizes: %d users, %d communities, %d events", len(a.Users), len(a.Communities), len(a.Events))
+	}
+
+	for i := range a.Events {
+		ea, eb := a.Events[i], b.Events[i]
+		if ea.ID() != eb.ID() || ea.CommunityID() != eb.CommunityID() || !ea.CreatedAt().Equal(eb.CreatedAt()) {
+			t.Fatalf("event %d differs between runs with the same seed", i)
+		}
+	}
+
+	// slugs stay unique once topics wrap around
+	slugs := make(map[string]bool)
+	for _, c := range a.Communities {
+		if slugs[c.Slug().String()] {
+			t.Errorf("duplicate slug %s", c.Slug().String())
+		}
+		slugs[c.Slug().String()] = true
+	}
+
+	origin := cfg.Anchor.Add(-cfg.Span)
+	for _, e := range a.Events {
+		if e.CreatedAt().Before(origin) || !e.CreatedAt().Before(cfg.Anchor) {
+			t.Fatalf("event at %s outside [%s, %s)", e.CreatedAt(), origin, cfg.Anchor)
+		}
+	}
+
+	c, err := GenerateSeedData(SeedConfig{Seed: 43, Users: 20, Communities: 35, Events: 10, Span: cfg.Span, Anchor: cfg.Anchor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Communities[0].ID() == a.Communities[0].ID() {
+		t.Error("different seeds should produce different ids")
+	}
+}
+
+func TestGenerateSeedData_InvalidConfig(t *testing.T) {
+	_, err := GenerateSeedData(SeedConfig{Users: 0, Communities: 1, Span: time.Hour})
+	if err == nil {
+		t.Fatal("expected error for zero users")
+	}
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
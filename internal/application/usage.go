@@ -0,0 +1,226 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// MaxUsageDays is how far back usage can be reported.
+// matches how long usage counters are kept.
+const MaxUsageDays = 31
+
+// ErrUsageDaysOutOfRange is returned for a report longer than MaxUsageDays.
+var ErrUsageDaysOutOfRange = fmt.Errorf("days must be between 1 and %d", MaxUsageDays)
+
+// UsageUseCase reports ingestion usage and manages quota overrides.
+type UsageUseCase struct {
+	counter       UsageCounter
+	quotas        *QuotaEnforcer
+	quotaRepo     domain.IngestionQuotaRepository
+	communityRepo domain.CommunityRepository
+	orgRepo       domain.OrganizationRepository
+	userRepo      domain.UserRepository
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// NewUsageUseCase creates a new UsageUseCase.
+// quotas provides the limits shown next to usage; quotaRepo stores overrides.
+func NewUsageUseCase(
+	counter UsageCounter,
+	quotas *QuotaEnforcer,
+	quotaRepo domain.IngestionQuotaRepository,
+	communityRepo domain.CommunityRepository,
+	orgRepo domain.OrganizationRepository,
+	userRepo domain.UserRepository,
+	logger *logging.Logger,
+) *UsageUseCase {
+	return &UsageUseCase{
+		counter:       counter,
+		quotas:        quotas,
+		quotaRepo:     quotaRepo,
+		communityRepo: communityRepo,
+		orgRepo:       orgRepo,
+		userRepo:      userRepo,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("usage"),
+	}
+}
+
+// DailyUsage is the number of events ingested on one UTC day.
+type DailyUsage struct {
+	Date   time.Time
+	Events int64
+}
+
+// UsageOutput describes a scope's usage against its quota.
+type UsageOutput struct {
+	Scope domain.UsageScope
+	ID    string
+
+	// DailyQuota is the effective quota, 0 when unlimited.
+	DailyQuota int64
+	Mode       domain.QuotaMode
+
+	// Today is usage so far today; Remaining is nil when unlimited.
+	Today     int64
+	Remaining *int64
+	ResetAt   time.Time
+
+	// Days is newest first, starting with today.
+	Days []DailyUsage
+}
+
+// CommunityUsage reports a community's usage to its creator or an admin of its organization.
+func (uc *UsageUseCase) CommunityUsage(ctx context.Context, communityID, requesterExternalID string, days int) (*UsageOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	requester, err := uc.requester(ctx, requesterExternalID, ErrNotCommunityOwner)
+	if err != nil {
+		return nil, err
+	}
+	if requester.ID() != community.CreatorID() {
+		orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+		if err != nil {
+			return nil, err
+		}
+		if !orgAdmin {
+			return nil, ErrNotCommunityOwner
+		}
+	}
+
+	return uc.usage(ctx, domain.UsageScopeCommunity, id.UUID(), days)
+}
+
+// OrganizationUsage reports an organization's usage to any of its members.
+func (uc *UsageUseCase) OrganizationUsage(ctx context.Context, organizationID, requesterExternalID string, days int) (*UsageOutput, error) {
+	id, err := domain.ParseOrganizationID(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization id: %w", err)
+	}
+
+	requester, err := uc.requester(ctx, requesterExternalID, ErrNotOrganizationMember)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.orgRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := uc.orgRepo.FindMember(ctx, id, requester.ID()); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrNotOrganizationMember
+		}
+		return nil, fmt.Errorf("looking up organization membership: %w", err)
+	}
+
+	return uc.usage(ctx, domain.UsageScopeOrganization, id.UUID(), days)
+}
+
+// Usage reports any scope's usage. for admins, no ownership check.
+func (uc *UsageUseCase) Usage(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID, days int) (*UsageOutput, error) {
+	return uc.usage(ctx, scope, scopeID, days)
+}
+
+// SetQuota overrides the default daily quota for a scope. 0 makes it unlimited.
+func (uc *UsageUseCase) SetQuota(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID, dailyEvents int64) (*UsageOutput, error) {
+	quota, err := domain.NewIngestionQuota(uc.clock, scope, scopeID, dailyEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.quotaRepo.Save(ctx, quota); err != nil {
+		return nil, fmt.Errorf("saving quota: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("ingestion quota set",
+		"scope", scope.String(),
+		"scope_id", scopeID.String(),
+		"daily_events", dailyEvents,
+	)
+	return uc.usage(ctx, scope, scopeID, 1)
+}
+
+// ClearQuota removes a scope's override so it uses the default again.
+func (uc *UsageUseCase) ClearQuota(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID) error {
+	if err := uc.quotaRepo.Delete(ctx, scope, scopeID); err != nil {
+		return fmt.Errorf("clearing quota: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("ingestion quota cleared",
+		"scope", scope.String(),
+		"scope_id", scopeID.String(),
+	)
+	return nil
+}
+
+func (uc *UsageUseCase) usage(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID, days int) (*UsageOutput, error) {
+	if days < 1 || days > MaxUsageDays {
+		return nil, ErrUsageDaysOutOfRange
+	}
+
+	today := usageDay(uc.clock.Now())
+	dates := make([]time.Time, days)
+	for i := range dates {
+		dates[i] = today.AddDate(0, 0, -i)
+	}
+
+	counts, err := uc.counter.DailyEvents(ctx, scope, scopeID.String(), dates)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("usage lookup failed",
+			"scope", scope.String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("loading usage: %w", err)
+	}
+
+	out := &UsageOutput{
+		Scope:      scope,
+		ID:         scopeID.String(),
+		DailyQuota: uc.quotas.Limit(ctx, scope, scopeID),
+		Mode:       uc.quotas.Mode(),
+		Today:      counts[0],
+		ResetAt:    today.Add(24 * time.Hour),
+		Days:       make([]DailyUsage, days),
+	}
+	for i, date := range dates {
+		out.Days[i] = DailyUsage{Date: date, Events: counts[i]}
+	}
+	if out.DailyQuota > 0 {
+		remaining := max(out.DailyQuota-out.Today, 0)
+		out.Remaining = &remaining
+	}
+	return out, nil
+}
+
+// requester resolves the caller, answering denied when they have no profile.
+func (uc *UsageUseCase) requester(ctx context.Context, externalID string, denied error) (*domain.User, error) {
+	if externalID == "" {
+		return nil, denied
+	}
+	user, err := uc.userRepo.FindByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, denied
+		}
+		return nil, fmt.Errorf("looking up requester: %w", err)
+	}
+	return user, nil
+}
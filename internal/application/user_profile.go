@@ -0,0 +1,202 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrUsernameTaken is returned when signing up with a username someone else holds, in any casing.
+var ErrUsernameTaken = errors.New("username is already taken")
+
+// UserProfileUseCase lets users create, read and edit their own profile.
+type UserProfileUseCase struct {
+	userRepo domain.UserRepository
+	reserved domain.ReservedNames
+	clock    domain.Clock
+	logger   *logging.Logger
+}
+
+// UserProfileOption configures a UserProfileUseCase at construction.
+type UserProfileOption func(*UserProfileUseCase)
+
+// WithReservedSignupUsernames rejects usernames on the blocklist at signup,
+// on top of the built-in one NewUsername enforces.
+func WithReservedSignupUsernames(reserved domain.ReservedNames) UserProfileOption {
+	return func(uc *UserProfileUseCase) {
+		uc.reserved = reserved
+	}
+}
+
+// NewUserProfileUseCase creates a new UserProfileUseCase.
+func NewUserProfileUseCase(userRepo domain.UserRepository, logger *logging.Logger, opts ...UserProfileOption) *UserProfileUseCase {
+	uc := &UserProfileUseCase{
+		userRepo: userRepo,
+		clock:    domain.SystemClock,
+		logger:   logger.WithComponent("user_profile"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// BootstrapUserInput contains what's needed to create the requester's profile.
+type BootstrapUserInput struct {
+	// ExternalID is the subject of the validated JWT
+	ExternalID string
+
+	Username string
+
+	// DisplayName and AvatarURL are optional, usually taken from the auth provider's user metadata.
+	DisplayName string
+	AvatarURL   string
+}
+
+// BootstrapUserOutput is the requester's profile, and whether this call created it.
+type BootstrapUserOutput struct {
+	Profile *UserProfileOutput
+	Created bool
+}
+
+// Bootstrap creates the requester's profile after they sign up with the auth provider.
+// it's idempotent: when the requester already has a profile it's returned as is,
+// whatever username was asked for.
+func (uc *UserProfileUseCase) Bootstrap(ctx context.Context, input BootstrapUserInput) (*BootstrapUserOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
+	if input.ExternalID == "" {
+		return nil, domain.ErrUserExternalIDEmpty
+	}
+
+	existing, err := uc.findExisting(ctx, input.ExternalID)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	username, err := domain.NewUsername(input.Username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username: %w", err)
+	}
+	if uc.reserved.Contains(input.Username) {
+		return nil, domain.ErrNameReserved
+	}
+
+	user, err := domain.NewUser(uc.clock, input.ExternalID, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.UpdateProfile(input.DisplayName, input.AvatarURL, ""); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		if !errors.Is(err, domain.ErrAlreadyExists) {
+			log.Error("user bootstrap failed",
+				"error", err.Error(),
+			)
+			return nil, fmt.Errorf("creating user: %w", err)
+		}
+		// a concurrent call created the profile first, or the username is someone else's
+		existing, err := uc.findExisting(ctx, input.ExternalID)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+		return nil, ErrUsernameTaken
+	}
+
+	log.Info("user profile created",
+		"user_id", user.ID().String(),
+		"username", username.String(),
+	)
+	return &BootstrapUserOutput{Profile: toUserProfileOutput(user), Created: true}, nil
+}
+
+// findExisting returns the profile already held by the external id, or nil if there's none.
+func (uc *UserProfileUseCase) findExisting(ctx context.Context, externalID string) (*BootstrapUserOutput, error) {
+	user, err := uc.userRepo.FindByExternalID(ctx, externalID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("user profile lookup failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("looking up user: %w", err)
+	}
+	return &BootstrapUserOutput{Profile: toUserProfileOutput(user)}, nil
+}
+
+// UpdateUserProfileInput contains the profile fields to change.
+// nil fields are kept, empty strings clear them.
+type UpdateUserProfileInput struct {
+	// RequesterExternalID comes from the validated JWT
+	RequesterExternalID string
+
+	DisplayName *string
+	AvatarURL   *string
+	Bio         *string
+}
+
+// Me returns the requester's own profile.
+func (uc *UserProfileUseCase) Me(ctx context.Context, requesterExternalID string) (*UserProfileOutput, error) {
+	user, err := uc.findRequester(ctx, requesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserProfileOutput(user), nil
+}
+
+// UpdateMe changes the requester's display name, avatar or bio.
+// the username is fixed once taken, it can't be changed here.
+func (uc *UserProfileUseCase) UpdateMe(ctx context.Context, input UpdateUserProfileInput) (*UserProfileOutput, error) {
+	user, err := uc.findRequester(ctx, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName, avatarURL, bio := user.DisplayName(), user.AvatarURL(), user.Bio()
+	if input.DisplayName != nil {
+		displayName = *input.DisplayName
+	}
+	if input.AvatarURL != nil {
+		avatarURL = *input.AvatarURL
+	}
+	if input.Bio != nil {
+		bio = *input.Bio
+	}
+
+	if err := user.UpdateProfile(displayName, avatarURL, bio); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		uc.logger.WithContext(ctx).Error("user profile save failed",
+			"user_id", user.ID().String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving user profile: %w", err)
+	}
+
+	uc.logger.WithContext(ctx).Info("user profile updated",
+		"user_id", user.ID().String(),
+	)
+	return toUserProfileOutput(user), nil
+}
+
+func (uc *UserProfileUseCase) findRequester(ctx context.Context, requesterExternalID string) (*domain.User, error) {
+	user, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrCreatorNotFound
+		}
+		uc.logger.WithContext(ctx).Error("user profile lookup failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("looking up user: %w", err)
+	}
+	return user, nil
+}
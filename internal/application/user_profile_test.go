@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// memoryUsers keeps users by external id; usernames are unique ignoring case, like the database.
+type memoryUsers struct {
+	domain.UserRepository
+	byExternalID map[string]*domain.User
+}
+
+func (m *memoryUsers) FindByExternalID(_ context.Context, externalID string) (*domain.User, error) {
+	if user, ok := m.byExternalID[externalID]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *memoryUsers) FindByIDs(_ context.Context, ids []domain.UserID) ([]*domain.User, error) {
+	var found []*domain.User
+	for _, user := range m.byExternalID {
+		if slices.Contains(ids, user.ID()) {
+			found = append(found, user)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryUsers) Create(_ context.Context, user *domain.User) error {
+	for _, existing := range m.byExternalID {
+		if existing.ExternalID() == user.ExternalID() || strings.EqualFold(existing.Username().String(), user.Username().String()) {
+			return domain.ErrAlreadyExists
+		}
+	}
+	m.byExternalID[user.ExternalID()] = user
+	return nil
+}
+
+func TestUserProfileUseCase_BootstrapIsIdempotent(t *testing.T) {
+	users := &memoryUsers{byExternalID: map[string]*domain.User{}}
+	uc := NewUserProfileUseCase(users, logging.New())
+	ctx := context.Background()
+
+	first, err := uc.Bootstrap(ctx, BootstrapUserInput{ExternalID: "auth-1", Username: "Jane_Doe", DisplayName: "Jane"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !first.Created || first.Profile.Username != "Jane_Doe" || first.Profile.DisplayName != "Jane" {
+		t.Fatalf("unexpected first bootstrap: created=%v profile=%+v", first.Created, first.Profile)
+	}
+
+	// a repeat call keeps the profile, whatever username it asks for
+	again, err := uc.Bootstrap(ctx, BootstrapUserInput{ExternalID: "auth-1", Username: "someone_else"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Created || again.Profile.ID != first.Profile.ID || again.Profile.Username != "Jane_Doe" {
+		t.Errorf("expected the existing profile, got created=%v profile=%+v", again.Created, again.Profile)
+	}
+}
+
+func TestUserProfileUseCase_BootstrapRejectsUnusableUsernames(t *testing.T) {
+	users := &memoryUsers{byExternalID: map[string]*domain.User{}}
+	uc := NewUserProfileUseCase(users, logging.New(), WithReservedSignupUsernames(domain.NewReservedNames("pulse_team")))
+	ctx := context.Background()
+
+	if _, err := uc.Bootstrap(ctx, BootstrapUserInput{ExternalID: "auth-1", Username: "jane_doe"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		want     error
+	}{
+		{"taken in another casing", "JANE_DOE", ErrUsernameTaken},
+		{"invalid", "jane doe", domain.ErrUsernameInvalid},
+		{"reserved by config", "Pulse_Team", domain.ErrNameReserved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Bootstrap(ctx, BootstrapUserInput{ExternalID: "auth-2", Username: tt.username})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	if _, ok := users.byExternalID["auth-2"]; ok {
+		t.Error("expected no profile to be created for a rejected username")
+	}
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// UserLookupUseCase finds public profiles by username and checks whether a username is free.
+// usernames are matched ignoring case, see domain.Username.
+type UserLookupUseCase struct {
+	userRepo domain.UserRepository
+	reserved domain.ReservedNames
+	logger   *logging.Logger
+}
+
+// UserLookupOption configures a UserLookupUseCase at construction.
+type UserLookupOption func(*UserLookupUseCase)
+
+// WithReservedUsernames reports usernames on the blocklist as unavailable,
+// on top of the built-in one NewUsername enforces.
+func WithReservedUsernames(reserved domain.ReservedNames) UserLookupOption {
+	return func(uc *UserLookupUseCase) {
+		uc.reserved = reserved
+	}
+}
+
+// NewUserLookupUseCase creates a new UserLookupUseCase.
+func NewUserLookupUseCase(userRepo domain.UserRepository, logger *logging.Logger, opts ...UserLookupOption) *UserLookupUseCase {
+	uc := &UserLookupUseCase{
+		userRepo: userRepo,
+		logger:   logger.WithComponent("user_lookup"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// UserProfileOutput is the public part of a user's profile. the external id is left out.
+type UserProfileOutput struct {
+	ID          string
+	Username    string
+	DisplayName string
+	AvatarURL   string
+	Bio         string
+	CreatedAt   time.Time
+}
+
+// FindByUsername returns the profile of the user holding the username, in any casing.
+func (uc *UserLookupUseCase) FindByUsername(ctx context.Context, username string) (*UserProfileOutput, error) {
+	name, err := domain.ParseUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username: %w", err)
+	}
+
+	user, err := uc.userRepo.FindByUsername(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		uc.logger.WithContext(ctx).Error("username lookup failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("looking up user: %w", err)
+	}
+
+	return toUserProfileOutput(user), nil
+}
+
+func toUserProfileOutput(user *domain.User) *UserProfileOutput {
+	return &UserProfileOutput{
+		ID:          user.ID().String(),
+		Username:    user.Username().String(),
+		DisplayName: user.DisplayName(),
+		AvatarURL:   user.AvatarURL(),
+		Bio:         user.Bio(),
+		CreatedAt:   user.CreatedAt(),
+	}
+}
+
+// UsernameAvailabilityOutput tells a client whether a username can be taken.
+type UsernameAvailabilityOutput struct {
+	// Username is the username as checked, Normalized the form uniqueness is decided on.
+	Username   string
+	Normalized string
+	Available  bool
+
+	// Reason explains why an unavailable username can't be used: invalid, reserved or taken.
+	Reason string
+	// Detail is the validation error for an invalid username.
+	Detail string
+}
+
+// CheckUsername reports whether a username is valid and not held by anyone, in any casing.
+// an invalid username isn't an error here, signup forms show the reason next to the field.
+func (uc *UserLookupUseCase) CheckUsername(ctx context.Context, username string) (*UsernameAvailabilityOutput, error) {
+	output := &UsernameAvailabilityOutput{Username: username}
+
+	name, err := domain.NewUsername(username)
+	if errors.Is(err, domain.ErrNameReserved) || (err == nil && uc.reserved.Contains(username)) {
+		output.Reason = "reserved"
+		return output, nil
+	}
+	if err != nil {
+		output.Reason = "invalid"
+		output.Detail = err.Error()
+		return output, nil
+	}
+	output.Normalized = name.Normalized()
+
+	_, err = uc.userRepo.FindByUsername(ctx, name)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		output.Available = true
+	case err != nil:
+		uc.logger.WithContext(ctx).Error("username availability check failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("looking up user: %w", err)
+	default:
+		output.Reason = "taken"
+	}
+	return output, nil
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// WebhookAllowlistUseCase lets community owners restrict where their community's
+// webhooks may be delivered, and checks subscriptions against it.
+type WebhookAllowlistUseCase struct {
+	allowlists    domain.CommunityWebhookAllowlistRepository
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// NewWebhookAllowlistUseCase creates a new WebhookAllowlistUseCase.
+// orgRepo lets owners and admins of a community's organization manage its allowlist,
+// nil leaves it to the creator.
+func NewWebhookAllowlistUseCase(
+	allowlists domain.CommunityWebhookAllowlistRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	orgRepo domain.OrganizationRepository,
+	logger *logging.Logger,
+) *WebhookAllowlistUseCase {
+	return &WebhookAllowlistUseCase{
+		allowlists:    allowlists,
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("webhook_allowlist"),
+	}
+}
+
+// WebhookAllowlistOutput lists the domains a community's webhooks may be delivered to.
+type WebhookAllowlistOutput struct {
+	CommunityID string
+
+	// Domains is empty when the community delivers anywhere.
+	Domains []string
+}
+
+// UpdateWebhookAllowlistInput contains the domains to allow, replacing the previous ones.
+type UpdateWebhookAllowlistInput struct {
+	CommunityID string
+	Domains     []string
+
+	// RequesterExternalID comes from the validated JWT
+	RequesterExternalID string
+}
+
+// Get returns the allowed webhook domains of a community.
+// readable by anyone, so subscribers can tell where they may point their webhooks.
+func (uc *WebhookAllowlistUseCase) Get(ctx context.Context, communityID string) (*WebhookAllowlistOutput, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid community id: %w", err)
+	}
+	if _, err := uc.communityRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	allowlist, err := uc.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookAllowlistOutput(id, allowlist), nil
+}
+
+// Update replaces the allowed webhook domains of a community managed by the requester.
+// existing subscriptions to other domains are kept, but nothing is delivered to them.
+func (uc *WebhookAllowlistUseCase) Update(ctx context.Context, input UpdateWebhookAllowlistInput) (*WebhookAllowlistOutput, error) {
+	ctx = logging.ContextWithCommunityID(ctx, input.CommunityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := uc.authorize(ctx, input.CommunityID, input.RequesterExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowlist, err := domain.NewCommunityWebhookAllowlist(uc.clock, id, input.Domains)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.allowlists.Save(ctx, allowlist); err != nil {
+		log.Error("webhook allowlist save failed",
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("saving webhook allowlist: %w", err)
+	}
+
+	log.Info("webhook allowlist updated",
+		"domains", len(allowlist.Domains()),
+	)
+
+	return toWebhookAllowlistOutput(id, allowlist), nil
+}
+
+// Reset removes the allowlist of a community managed by the requester,
+// so its webhooks may be delivered anywhere again.
+func (uc *WebhookAllowlistUseCase) Reset(ctx context.Context, communityID, requesterExternalID string) error {
+	ctx = logging.ContextWithCommunityID(ctx, communityID)
+	log := uc.logger.WithContext(ctx)
+
+	id, err := uc.authorize(ctx, communityID, requesterExternalID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.allowlists.Delete(ctx, id); err != nil {
+		log.Error("webhook allowlist reset failed",
+			"error", err.Error(),
+		)
+		return fmt.Errorf("resetting webhook allowlist: %w", err)
+	}
+
+	log.Info("webhook allowlist reset")
+	return nil
+}
+
+// Check returns domain.ErrWebhookDomainNotAllowed when the community's allowlist doesn't
+// cover the target url. global subscriptions, with a zero community id, aren't checked
+// here, their deliveries are checked per community when they're dispatched.
+func (uc *WebhookAllowlistUseCase) Check(ctx context.Context, communityID domain.CommunityID, targetURL string) error {
+	if communityID.IsZero() {
+		return nil
+	}
+
+	allowlist, err := uc.find(ctx, communityID)
+	if err != nil {
+		return err
+	}
+	if !allowlist.AllowsURL(targetURL) {
+		return domain.ErrWebhookDomainNotAllowed
+	}
+	return nil
+}
+
+// find returns a community's allowlist, nil when it has none.
+func (uc *WebhookAllowlistUseCase) find(ctx context.Context, id domain.CommunityID) (*domain.CommunityWebhookAllowlist, error) {
+	allowlist, err := uc.allowlists.FindByCommunity(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("webhook allowlist lookup failed",
+			"community_id", id.String(),
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("loading webhook allowlist: %w", err)
+	}
+	return allowlist, nil
+}
+
+// authorize checks that the requester created the community,
+// or manages the organization it belongs to.
+func (uc *WebhookAllowlistUseCase) authorize(ctx context.Context, communityID, requesterExternalID string) (domain.CommunityID, error) {
+	id, err := domain.ParseCommunityID(communityID)
+	if err != nil {
+		return domain.CommunityID{}, fmt.Errorf("invalid community id: %w", err)
+	}
+
+	community, err := uc.communityRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.CommunityID{}, err
+	}
+
+	requester, err := uc.userRepo.FindByExternalID(ctx, requesterExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.CommunityID{}, ErrNotCommunityOwner
+		}
+		return domain.CommunityID{}, fmt.Errorf("looking up requester: %w", err)
+	}
+	if requester.ID() == community.CreatorID() {
+		return id, nil
+	}
+
+	orgAdmin, err := canManageCommunity(ctx, uc.orgRepo, community, requester.ID())
+	if err != nil {
+		return domain.CommunityID{}, err
+	}
+	if !orgAdmin {
+		uc.logger.WithContext(ctx).Info("webhook allowlist change rejected: not owner",
+			"requester_id", requester.ID().String(),
+		)
+		return domain.CommunityID{}, ErrNotCommunityOwner
+	}
+	return id, nil
+}
+
+func toWebhookAllowlistOutput(id domain.CommunityID, allowlist *domain.CommunityWebhookAllowlist) *WebhookAllowlistOutput {
+	out := &WebhookAllowlistOutput{CommunityID: id.String(), Domains: []string{}}
+	if allowlist != nil {
+		out.Domains = allowlist.Domains()
+	}
+	return out
+}
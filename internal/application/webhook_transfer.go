@@ -0,0 +1,431 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrExportScopeRequired is returned when an export names neither or both of a community and an organization.
+var ErrExportScopeRequired = errors.New("invalid export scope: set either community_id or organization_id")
+
+// ErrSubscriptionImportInvalid is returned when some subscriptions of an import don't validate.
+// nothing is imported then, the output says which ones failed and why.
+var ErrSubscriptionImportInvalid = errors.New("some subscriptions are invalid, nothing was imported")
+
+// WebhookTransferUseCase exports the webhook subscriptions of a community or organization
+// and imports them elsewhere, e.g. when moving between environments.
+// secrets never leave pulse: imported webhook subscriptions get new ones.
+// users are identified by their auth provider id, which is the same in every environment.
+type WebhookTransferUseCase struct {
+	subscriptions domain.WebhookSubscriptionRepository
+	communityRepo domain.CommunityRepository
+	userRepo      domain.UserRepository
+	orgRepo       domain.OrganizationRepository
+	uow           UnitOfWork
+	allowlist     *WebhookAllowlistUseCase
+	allowProxy    bool
+	clock         domain.Clock
+	logger        *logging.Logger
+}
+
+// WebhookTransferOption configures a WebhookTransferUseCase at construction.
+type WebhookTransferOption func(*WebhookTransferUseCase)
+
+// WithTransferAllowlist checks imported target urls against the community's allowed domains.
+func WithTransferAllowlist(allowlist *WebhookAllowlistUseCase) WebhookTransferOption {
+	return func(uc *WebhookTransferUseCase) {
+		uc.allowlist = allowlist
+	}
+}
+
+// WithTransferProxies accepts a proxy_url on imported subscriptions, which is refused otherwise.
+func WithTransferProxies(allowed bool) WebhookTransferOption {
+	return func(uc *WebhookTransferUseCase) {
+		uc.allowProxy = allowed
+	}
+}
+
+// NewWebhookTransferUseCase creates a new WebhookTransferUseCase.
+// uow saves an import in one transaction, so it's imported whole or not at all.
+func NewWebhookTransferUseCase(
+	subscriptions domain.WebhookSubscriptionRepository,
+	communityRepo domain.CommunityRepository,
+	userRepo domain.UserRepository,
+	orgRepo domain.OrganizationRepository,
+	uow UnitOfWork,
+	logger *logging.Logger,
+	opts ...WebhookTransferOption,
+) *WebhookTransferUseCase {
+	uc := &WebhookTransferUseCase{
+		subscriptions: subscriptions,
+		communityRepo: communityRepo,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		uow:           uow,
+		clock:         domain.SystemClock,
+		logger:        logger.WithComponent("webhook_transfer"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// PauseWindowSettings is a recurring window during which deliveries are held.
+// Day is empty for every day.
+type PauseWindowSettings struct {
+	Day      string
+	Start    string
+	End      string
+	Timezone string
+}
+
+// TransferredSubscription is a webhook subscription as exported and imported.
+// it has no secret, and exports leave out the proxy url, which can hold credentials.
+type TransferredSubscription struct {
+	// UserExternalID is the subscriber's auth provider id, unlike the user id it's kept across environments.
+	UserExternalID string
+
+	// CommunityID is empty for a global subscription.
+	CommunityID string
+
+	Channel        string
+	TargetURL      string
+	PayloadVersion string
+	DeliveryMode   string
+	ProxyURL       string
+	PauseWindows   []PauseWindowSettings
+	IsActive       bool
+}
+
+// ExportSubscriptionsInput names what to export: one community or one organization.
+type ExportSubscriptionsInput struct {
+	CommunityID    string
+	OrganizationID string
+}
+
+// Export returns every subscription to the community, or to the communities of the organization.
+func (uc *WebhookTransferUseCase) Export(ctx context.Context, input ExportSubscriptionsInput) ([]TransferredSubscription, error) {
+	if (input.CommunityID == "") == (input.OrganizationID == "") {
+		return nil, ErrExportScopeRequired
+	}
+
+	var (
+		subs []*domain.WebhookSubscription
+		err  error
+	)
+	if input.CommunityID != "" {
+		communityID, parseErr := domain.ParseCommunityID(input.CommunityID)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid community id: %w", parseErr)
+		}
+		if _, err := uc.communityRepo.FindByID(ctx, communityID); err != nil {
+			return nil, err
+		}
+		subs, err = uc.subscriptions.FindAllByCommunity(ctx, communityID)
+	} else {
+		orgID, parseErr := domain.ParseOrganizationID(input.OrganizationID)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid organization id: %w", parseErr)
+		}
+		if _, err := uc.orgRepo.FindByID(ctx, orgID); err != nil {
+			return nil, err
+		}
+		subs, err = uc.subscriptions.FindByOrganization(ctx, orgID)
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("subscription export failed",
+			"community_id", input.CommunityID,
+			"organization_id", input.OrganizationID,
+			"error", err.Error(),
+		)
+		return nil, fmt.Errorf("loading subscriptions: %w", err)
+	}
+
+	externalIDs, err := uc.externalIDs(ctx, subs)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("subscription export failed",
+			"community_id", input.CommunityID,
+			"organization_id", input.OrganizationID,
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+
+	exported := make([]TransferredSubscription, len(subs))
+	for i, sub := range subs {
+		exported[i] = toTransferredSubscription(sub, externalIDs[sub.UserID()])
+	}
+	return exported, nil
+}
+
+// externalIDs maps the subscribers of subs to their auth provider ids.
+func (uc *WebhookTransferUseCase) externalIDs(ctx context.Context, subs []*domain.WebhookSubscription) (map[domain.UserID]string, error) {
+	ids := make([]domain.UserID, 0, len(subs))
+	seen := make(map[domain.UserID]bool, len(subs))
+	for _, sub := range subs {
+		if !seen[sub.UserID()] {
+			seen[sub.UserID()] = true
+			ids = append(ids, sub.UserID())
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	users, err := uc.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading subscribers: %w", err)
+	}
+	externalIDs := make(map[domain.UserID]string, len(users))
+	for _, user := range users {
+		externalIDs[user.ID()] = user.ExternalID()
+	}
+	return externalIDs, nil
+}
+
+// ImportSubscriptionsInput contains the subscriptions to create or replace.
+type ImportSubscriptionsInput struct {
+	Subscriptions []TransferredSubscription
+
+	// DryRun validates every subscription without saving any.
+	DryRun bool
+}
+
+// Import actions.
+const (
+	ImportActionCreate  = "create"
+	ImportActionReplace = "replace"
+)
+
+// SubscriptionImportResult is what happened, or would happen, to one imported subscription.
+type SubscriptionImportResult struct {
+	// Index is the subscription's position in the input.
+	Index          int
+	UserExternalID string
+	CommunityID    string
+
+	// Action is create, or replace when the user already subscribes to the community.
+	// empty when Error is set.
+	Action string
+
+	// SubscriptionID and Secret are set once saved. the secret is new, and only
+	// webhook subscriptions have one. it isn't stored anywhere it can be read back.
+	SubscriptionID string
+	Secret         string
+
+	Error string
+}
+
+// ImportSubscriptionsOutput reports an import.
+type ImportSubscriptionsOutput struct {
+	DryRun   bool
+	Imported int
+	Results  []SubscriptionImportResult
+}
+
+// Valid reports whether every subscription passed validation.
+func (o *ImportSubscriptionsOutput) Valid() bool {
+	for _, r := range o.Results {
+		if r.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Import validates every subscription, then saves them all in one transaction unless one
+// is invalid or it's a dry run. a subscription replaces the user's existing one to the
+// same community and keeps its id.
+// returns ErrSubscriptionImportInvalid, with the output, when something didn't validate.
+func (uc *WebhookTransferUseCase) Import(ctx context.Context, input ImportSubscriptionsInput) (*ImportSubscriptionsOutput, error) {
+	log := uc.logger.WithContext(ctx)
+
+	output := &ImportSubscriptionsOutput{
+		DryRun:  input.DryRun,
+		Results: make([]SubscriptionImportResult, len(input.Subscriptions)),
+	}
+	built := make([]*domain.WebhookSubscription, len(input.Subscriptions))
+
+	// one subscription per user and community, as the table enforces
+	seen := make(map[string]int, len(input.Subscriptions))
+	for i, in := range input.Subscriptions {
+		result := &output.Results[i]
+		result.Index = i
+		result.UserExternalID = in.UserExternalID
+		result.CommunityID = in.CommunityID
+
+		key := in.UserExternalID + "/" + in.CommunityID
+		if first, ok := seen[key]; ok {
+			result.Error = fmt.Sprintf("duplicate of subscription %d, a user has one subscription per community", first)
+			continue
+		}
+		seen[key] = i
+
+		sub, action, err := uc.buildImported(ctx, in)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		built[i] = sub
+		result.Action = action
+	}
+
+	if !output.Valid() {
+		return output, ErrSubscriptionImportInvalid
+	}
+	if input.DryRun {
+		return output, nil
+	}
+
+	err := RunInTransaction(ctx, uc.uow, func(ctx context.Context) error {
+		for i, sub := range built {
+			if err := uc.subscriptions.Save(ctx, sub); err != nil {
+				return fmt.Errorf("saving subscription %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("subscription import failed",
+			"total", len(built),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+
+	for i, sub := range built {
+		output.Results[i].SubscriptionID = sub.ID().String()
+		output.Results[i].Secret = sub.Secret()
+	}
+	output.Imported = len(built)
+
+	log.Info("subscriptions imported",
+		"imported", output.Imported,
+	)
+	return output, nil
+}
+
+// buildImported validates an imported subscription and builds it with a new secret.
+func (uc *WebhookTransferUseCase) buildImported(ctx context.Context, in TransferredSubscription) (*domain.WebhookSubscription, string, error) {
+	// the subscriber must have signed up in this environment too
+	user, err := uc.userRepo.FindByExternalID(ctx, in.UserExternalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, "", errors.New("user not found")
+		}
+		return nil, "", fmt.Errorf("looking up user: %w", err)
+	}
+	userID := user.ID()
+
+	// no community means a global subscription, left as the zero id
+	var communityID domain.CommunityID
+	if in.CommunityID != "" {
+		communityID, err = domain.ParseCommunityID(in.CommunityID)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid community id: %w", err)
+		}
+		if _, err := uc.communityRepo.FindByID(ctx, communityID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, "", errors.New("community not found")
+			}
+			return nil, "", fmt.Errorf("looking up community: %w", err)
+		}
+	}
+
+	if uc.allowlist != nil {
+		if err := uc.allowlist.Check(ctx, communityID, in.TargetURL); err != nil {
+			return nil, "", err
+		}
+	}
+
+	channel, err := domain.ParseNotificationChannel(in.Channel)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// replacing keeps the existing id, the save updates that row
+	action := ImportActionCreate
+	subID, _ := domain.NewWebhookSubscriptionID(uuid.New().String())
+	existing, err := uc.subscriptions.ListByUser(ctx, userID, domain.WebhookSubscriptionFilter{CommunityID: &communityID, Limit: 1})
+	if err != nil {
+		return nil, "", fmt.Errorf("looking up existing subscription: %w", err)
+	}
+	if len(existing) > 0 {
+		action = ImportActionReplace
+		subID = existing[0].ID()
+	}
+
+	var sub *domain.WebhookSubscription
+	if channel.IsSigned() {
+		secret, err := domain.NewWebhookSecret()
+		if err != nil {
+			return nil, "", fmt.Errorf("generating secret: %w", err)
+		}
+		sub, err = domain.NewWebhookSubscription(uc.clock, subID, userID, communityID, in.TargetURL, secret, domain.WebhookPayloadVersion(in.PayloadVersion), domain.WebhookDeliveryMode(in.DeliveryMode))
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		sub, err = domain.NewChatSubscription(uc.clock, subID, userID, communityID, channel, in.TargetURL, domain.WebhookDeliveryMode(in.DeliveryMode))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if in.ProxyURL != "" {
+		if !uc.allowProxy {
+			return nil, "", errors.New("per-subscription proxies are disabled")
+		}
+		if err := sub.SetProxyURL(in.ProxyURL); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if len(in.PauseWindows) > 0 {
+		windows := make([]domain.DeliveryPauseWindow, len(in.PauseWindows))
+		for i, w := range in.PauseWindows {
+			windows[i], err = domain.ParseDeliveryPauseWindow(w.Day, w.Start, w.End, w.Timezone)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		if err := sub.SetPauseWindows(windows); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if !in.IsActive {
+		sub.Deactivate()
+	}
+	return sub, action, nil
+}
+
+func toTransferredSubscription(sub *domain.WebhookSubscription, userExternalID string) TransferredSubscription {
+	var communityID string
+	if !sub.IsGlobal() {
+		communityID = sub.CommunityID().String()
+	}
+
+	windows := make([]PauseWindowSettings, len(sub.PauseWindows()))
+	for i, w := range sub.PauseWindows() {
+		windows[i] = PauseWindowSettings{Day: w.Day(), Start: w.Start(), End: w.End(), Timezone: w.Timezone()}
+	}
+
+	return TransferredSubscription{
+		UserExternalID: userExternalID,
+		CommunityID:    communityID,
+		Channel:        sub.Channel().String(),
+		TargetURL:      sub.TargetURL(),
+		PayloadVersion: sub.PayloadVersion().String(),
+		DeliveryMode:   sub.DeliveryMode().String(),
+		PauseWindows:   windows,
+		IsActive:       sub.IsActive(),
+	}
+}
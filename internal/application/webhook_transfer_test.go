@@ -0,0 +1,160 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// memorySubscriptions keeps saved subscriptions in order; none exist before the test saves them.
+type memorySubscriptions struct {
+	domain.WebhookSubscriptionRepository
+	saved []*domain.WebhookSubscription
+}
+
+func (m *memorySubscriptions) ListByUser(context.Context, domain.UserID, domain.WebhookSubscriptionFilter) ([]*domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *memorySubscriptions) Save(_ context.Context, sub *domain.WebhookSubscription) error {
+	m.saved = append(m.saved, sub)
+	return nil
+}
+
+func (m *memorySubscriptions) FindAllByCommunity(context.Context, domain.CommunityID) ([]*domain.WebhookSubscription, error) {
+	return m.saved, nil
+}
+
+// knownCommunities finds only the communities it holds.
+type knownCommunities struct {
+	domain.CommunityRepository
+	ids map[domain.CommunityID]bool
+}
+
+func (k knownCommunities) FindByID(_ context.Context, id domain.CommunityID) (*domain.Community, error) {
+	if !k.ids[id] {
+		return nil, domain.ErrNotFound
+	}
+	return &domain.Community{}, nil
+}
+
+// signedUp returns users who signed up with the given external ids.
+func signedUp(t *testing.T, externalIDs ...string) *memoryUsers {
+	t.Helper()
+	users := &memoryUsers{byExternalID: map[string]*domain.User{}}
+	for i, externalID := range externalIDs {
+		username, err := domain.NewUsername(fmt.Sprintf("user_%d", i))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		user, err := domain.NewUser(domain.SystemClock, externalID, username)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		users.byExternalID[externalID] = user
+	}
+	return users
+}
+
+type noopUnitOfWork struct{}
+
+func (noopUnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+func (noopUnitOfWork) Commit(context.Context) error                       { return nil }
+func (noopUnitOfWork) Rollback(context.Context) error                     { return nil }
+
+func TestWebhookTransferUseCase_ImportProvisionsSecrets(t *testing.T) {
+	community := domain.NewCommunityID()
+	subs := &memorySubscriptions{}
+	users := signedUp(t, "auth-1", "auth-2")
+	uc := NewWebhookTransferUseCase(subs, knownCommunities{ids: map[domain.CommunityID]bool{community: true}}, users, nil, noopUnitOfWork{}, logging.New())
+
+	input := ImportSubscriptionsInput{Subscriptions: []TransferredSubscription{
+		{UserExternalID: "auth-1", CommunityID: community.String(), TargetURL: "https://example.com/hook", IsActive: true},
+		{UserExternalID: "auth-2", Channel: "slack", TargetURL: "https://hooks.slack.com/x", IsActive: false},
+	}}
+
+	dry, err := uc.Import(context.Background(), ImportSubscriptionsInput{Subscriptions: input.Subscriptions, DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subs.saved) != 0 || dry.Imported != 0 || dry.Results[0].Secret != "" {
+		t.Fatalf("expected a dry run to save nothing, saved %d", len(subs.saved))
+	}
+
+	output, err := uc.Import(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Imported != 2 || len(subs.saved) != 2 {
+		t.Fatalf("expected 2 imported, got %d (saved %d)", output.Imported, len(subs.saved))
+	}
+	if secret := output.Results[0].Secret; !strings.HasPrefix(secret, domain.WebhookSecretPrefix) || secret != subs.saved[0].Secret() {
+		t.Errorf("expected a new secret for the webhook subscription, got %q", secret)
+	}
+	if output.Results[1].Secret != "" {
+		t.Error("expected no secret for a chat subscription")
+	}
+	if subs.saved[0].UserID() != users.byExternalID["auth-1"].ID() {
+		t.Error("expected the subscription to belong to the user with that external id")
+	}
+	if subs.saved[1].IsActive() {
+		t.Error("expected the inactive subscription to stay inactive")
+	}
+	if output.Results[0].Action != ImportActionCreate {
+		t.Errorf("expected create, got %q", output.Results[0].Action)
+	}
+}
+
+func TestWebhookTransferUseCase_ImportRejectsInvalidBatch(t *testing.T) {
+	subs := &memorySubscriptions{}
+	uc := NewWebhookTransferUseCase(subs, knownCommunities{}, signedUp(t, "auth-1", "auth-2", "auth-3"), nil, noopUnitOfWork{}, logging.New())
+
+	output, err := uc.Import(context.Background(), ImportSubscriptionsInput{Subscriptions: []TransferredSubscription{
+		{UserExternalID: "auth-1", TargetURL: "https://example.com/a", IsActive: true},
+		{UserExternalID: "auth-1", TargetURL: "https://example.com/b", IsActive: true},
+		{UserExternalID: "auth-2", CommunityID: domain.NewCommunityID().String(), TargetURL: "https://example.com/c", IsActive: true},
+		{UserExternalID: "auth-3", TargetURL: "https://example.com/d", ProxyURL: "http://proxy:8080", IsActive: true},
+		{UserExternalID: "never-signed-up", TargetURL: "https://example.com/e", IsActive: true},
+	}})
+	if !errors.Is(err, ErrSubscriptionImportInvalid) {
+		t.Fatalf("expected ErrSubscriptionImportInvalid, got %v", err)
+	}
+	if len(subs.saved) != 0 || output.Imported != 0 {
+		t.Fatalf("expected nothing saved, saved %d", len(subs.saved))
+	}
+
+	if output.Results[0].Error != "" {
+		t.Errorf("expected the first subscription to validate, got %q", output.Results[0].Error)
+	}
+	for i, want := range []string{"duplicate", "community not found", "proxies are disabled", "user not found"} {
+		if got := output.Results[i+1].Error; !strings.Contains(got, want) {
+			t.Errorf("subscription %d: expected an error about %q, got %q", i+1, want, got)
+		}
+	}
+}
+
+func TestWebhookTransferUseCase_ExportKeysOnExternalIDs(t *testing.T) {
+	community := domain.NewCommunityID()
+	subs := &memorySubscriptions{}
+	users := signedUp(t, "auth-1")
+	uc := NewWebhookTransferUseCase(subs, knownCommunities{ids: map[domain.CommunityID]bool{community: true}}, users, nil, noopUnitOfWork{}, logging.New())
+
+	if _, err := uc.Import(context.Background(), ImportSubscriptionsInput{Subscriptions: []TransferredSubscription{
+		{UserExternalID: "auth-1", CommunityID: community.String(), TargetURL: "https://example.com/hook", IsActive: true},
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exported, err := uc.Export(context.Background(), ExportSubscriptionsInput{CommunityID: community.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exported) != 1 || exported[0].UserExternalID != "auth-1" {
+		t.Fatalf("expected the subscriber's external id, got %+v", exported)
+	}
+}
@@ -0,0 +1,87 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/cache"
+)
+
+// cachedCommunities is how many communities the existence cache benchmarks spread lookups over.
+const cachedCommunities = 1000
+
+// memoryCommunities serves FindByID from a map, which is only read once built.
+// the cache calls nothing else.
+type memoryCommunities struct {
+	domain.CommunityRepository
+	byID map[domain.CommunityID]*domain.Community
+}
+
+func (m memoryCommunities) FindByID(_ context.Context, id domain.CommunityID) (*domain.Community, error) {
+	community, ok := m.byID[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return community, nil
+}
+
+func newMemoryCommunities(b *testing.B) (memoryCommunities, []domain.CommunityID) {
+	b.Helper()
+
+	repo := memoryCommunities{byID: make(map[domain.CommunityID]*domain.Community, cachedCommunities)}
+	ids := make([]domain.CommunityID, 0, cachedCommunities)
+	for i := 0; i < cachedCommunities; i++ {
+		slug, err := domain.NewSlug(fmt.Sprintf("community-%d", i))
+		if err != nil {
+			b.Fatalf("slug: %v", err)
+		}
+		community, err := domain.NewCommunity(domain.SystemClock, slug, "Community", domain.NewUserID())
+		if err != nil {
+			b.Fatalf("new community: %v", err)
+		}
+		repo.byID[community.ID()] = community
+		ids = append(ids, community.ID())
+	}
+	return repo, ids
+}
+
+func BenchmarkCommunityExistsCache(b *testing.B) {
+	b.Run("hits", benchmarkCommunityExistsCache(0))
+	b.Run("invalidations", benchmarkCommunityExistsCache(100))
+}
+
+// benchmarkCommunityExistsCache checks communities from every P in parallel, the way
+// concurrent ingest requests do. with invalidateEvery set, one check in that many
+// invalidates its community first, so readers contend with writers and refills.
+func benchmarkCommunityExistsCache(invalidateEvery int) func(b *testing.B) {
+	return func(b *testing.B) {
+		repo, ids := newMemoryCommunities(b)
+		c := cache.NewCommunityExistsCache(repo, time.Hour)
+		ctx := context.Background()
+		for _, id := range ids {
+			if _, _, err := c.CheckActive(ctx, id); err != nil {
+				b.Fatalf("warming cache: %v", err)
+			}
+		}
+
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			rng := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+			for i := 0; pb.Next(); i++ {
+				id := ids[rng.IntN(len(ids))]
+				if invalidateEvery > 0 && i%invalidateEvery == 0 {
+					c.Invalidate(id)
+				}
+				exists, _, err := c.CheckActive(ctx, id)
+				if err != nil || !exists {
+					b.Errorf("check = %v, %v; want an existing community", exists, err)
+					return
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+// batchSizes spans single inserts up to a full ingestion worker flush.
+var batchSizes = []int{1, 10, 100, 1000}
+
+func BenchmarkSaveBatch(b *testing.B) {
+	for _, size := range batchSizes {
+		b.Run(fmt.Sprintf("size=%d", size), benchmarkSaveBatch(size))
+	}
+}
+
+// benchmarkSaveBatch inserts batches of size posts into one community, reporting
+// the cost per event alongside the cost per batch.
+func benchmarkSaveBatch(size int) func(b *testing.B) {
+	return func(b *testing.B) {
+		env := sharedEnv(b)
+		ctx := context.Background()
+		creator := env.CreateUser(b, uniqueName("batch"))
+		community := env.CreateCommunity(b, creator, uniqueName("batch"), "Batch")
+		repo := postgres.NewActivityEventRepository(env.Pool)
+
+		events := 0
+		for b.Loop() {
+			b.StopTimer()
+			batch := newEvents(b, community.ID(), size)
+			b.StartTimer()
+
+			if err := repo.SaveBatch(ctx, batch); err != nil {
+				b.Fatalf("save batch: %v", err)
+			}
+			events += size
+		}
+		b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(events), "ns/event")
+	}
+}
+
+// newEvents builds count posts for a community.
+func newEvents(b *testing.B, communityID domain.CommunityID, count int) []*domain.ActivityEvent {
+	b.Helper()
+
+	events := make([]*domain.ActivityEvent, count)
+	for i := range events {
+		event, err := domain.NewActivityEventWithDefaultWeight(domain.SystemClock, communityID, nil, domain.EventTypePost, nil)
+		if err != nil {
+			b.Fatalf("new event: %v", err)
+		}
+		events[i] = event
+	}
+	return events
+}
@@ -0,0 +1,58 @@
+// Package benchmarks measures the hot paths: batched event inserts, momentum over a
+// busy window, and the community existence cache. the database benchmarks start
+// postgres with testsupport on first use and are skipped without docker.
+//
+//	go test -run '^$' -bench . ./internal/benchmarks
+//	go test -run TestBenchmarkReport ./internal/benchmarks -args -bench.out=bench.json -bench.baseline=old.json
+package benchmarks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/testsupport"
+)
+
+var (
+	envOnce sync.Once
+	env     *testsupport.Env
+	envErr  error
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if env != nil {
+		env.Close()
+	}
+	os.Exit(code)
+}
+
+// sharedEnv returns the postgres env shared by every benchmark, started on first use
+// so runs without database benchmarks never need docker.
+func sharedEnv(b *testing.B) *testsupport.Env {
+	b.Helper()
+
+	envOnce.Do(func() {
+		env, envErr = testsupport.New(context.Background())
+	})
+	if errors.Is(envErr, testsupport.ErrNoDocker) {
+		b.Skip(envErr.Error())
+	}
+	if envErr != nil {
+		b.Fatal(envErr)
+	}
+	return env
+}
+
+var nameSeq atomic.Int64
+
+// uniqueName is a username and slug no other benchmark in the run uses, since they all
+// share one database.
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, nameSeq.Add(1))
+}
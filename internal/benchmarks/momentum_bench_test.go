@@ -0,0 +1,46 @@
+package benchmarks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+// momentumWindowEvents is how many events sit in the community's momentum window.
+const momentumWindowEvents = 10_000
+
+// BenchmarkCalculateMomentum recalculates one community with 10k events in its window,
+// without redis so only postgres is measured.
+func BenchmarkCalculateMomentum(b *testing.B) {
+	env := sharedEnv(b)
+	ctx := context.Background()
+	creator := env.CreateUser(b, uniqueName("momentum"))
+	community := env.CreateCommunity(b, creator, uniqueName("momentum"), "Momentum")
+
+	eventRepo := postgres.NewActivityEventRepository(env.Pool)
+	for seeded := 0; seeded < momentumWindowEvents; seeded += 1000 {
+		if err := eventRepo.SaveBatch(ctx, newEvents(b, community.ID(), 1000)); err != nil {
+			b.Fatalf("seeding events: %v", err)
+		}
+	}
+
+	momentum := application.NewCalculateMomentumUseCase(
+		eventRepo,
+		postgres.NewCommunityRepository(env.Pool),
+		application.DefaultMomentumConfig(),
+		env.Logger,
+	)
+	input := application.CalculateMomentumInput{CommunityID: community.ID().String()}
+
+	for b.Loop() {
+		output, err := momentum.Execute(ctx, input)
+		if err != nil {
+			b.Fatalf("calculate momentum: %v", err)
+		}
+		if output.EventCount != momentumWindowEvents {
+			b.Fatalf("counted %d events, want %d", output.EventCount, momentumWindowEvents)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+var (
+	benchOut       = flag.String("bench.out", "", "run the benchmarks and write their results as JSON to this file")
+	benchBaseline  = flag.String("bench.baseline", "", "fail when a benchmark is slower than in this earlier -bench.out file")
+	benchTolerance = flag.Float64("bench.tolerance", 0.2, "how much slower than the baseline a benchmark may get, 0.2 is 20%")
+)
+
+// benchReport is the -bench.out file.
+type benchReport struct {
+	GoVersion  string        `json:"go_version"`
+	GOOS       string        `json:"goos"`
+	GOARCH     string        `json:"goarch"`
+	CPUs       int           `json:"cpus"`
+	RecordedAt time.Time     `json:"recorded_at"`
+	Results    []benchResult `json:"results"`
+}
+
+type benchResult struct {
+	Name        string             `json:"name"`
+	Iterations  int                `json:"iterations"`
+	NsPerOp     int64              `json:"ns_per_op"`
+	BytesPerOp  int64              `json:"bytes_per_op"`
+	AllocsPerOp int64              `json:"allocs_per_op"`
+	Extra       map[string]float64 `json:"extra,omitempty"`
+}
+
+type namedBenchmark struct {
+	name string
+	fn   func(b *testing.B)
+}
+
+// reportedBenchmarks are the benchmarks in the report, named as go test -bench names them.
+// testing.Benchmark runs one function, so sub-benchmarks are listed one by one.
+func reportedBenchmarks() []namedBenchmark {
+	var benchmarks []namedBenchmark
+	for _, size := range batchSizes {
+		benchmarks = append(benchmarks, namedBenchmark{fmt.Sprintf("BenchmarkSaveBatch/size=%d", size), benchmarkSaveBatch(size)})
+	}
+	return append(benchmarks,
+		namedBenchmark{"BenchmarkCalculateMomentum", BenchmarkCalculateMomentum},
+		namedBenchmark{"BenchmarkCommunityExistsCache/hits", benchmarkCommunityExistsCache(0)},
+		namedBenchmark{"BenchmarkCommunityExistsCache/invalidations", benchmarkCommunityExistsCache(100)},
+	)
+}
+
+// TestBenchmarkReport runs the benchmarks for a machine-readable report, only when
+// -bench.out is set. with -bench.baseline it also fails on regressions.
+func TestBenchmarkReport(t *testing.T) {
+	if *benchOut == "" {
+		t.Skip("set -bench.out to run the benchmarks")
+	}
+
+	report := benchReport{
+		GoVersion:  runtime.Version(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		CPUs:       runtime.GOMAXPROCS(0),
+		RecordedAt: time.Now().UTC(),
+	}
+	for _, bm := range reportedBenchmarks() {
+		r := testing.Benchmark(bm.fn)
+		if r.N == 0 {
+			// skipped, the database benchmarks without docker
+			t.Logf("%s: skipped", bm.name)
+			continue
+		}
+		report.Results = append(report.Results, benchResult{
+			Name:        bm.name,
+			Iterations:  r.N,
+			NsPerOp:     r.NsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+			Extra:       r.Extra,
+		})
+		t.Logf("%s: %s", bm.name, r.String())
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		t.Fatalf("encoding report: %v", err)
+	}
+	if err := os.WriteFile(*benchOut, append(data, '\n'), 0o644); err != nil {
+		t.Fatalf("writing report: %v", err)
+	}
+
+	if *benchBaseline != "" {
+		compareBaseline(t, report)
+	}
+}
+
+// compareBaseline fails for every benchmark slower than in the baseline by more than
+// the tolerance. benchmarks missing from either side are left out.
+func compareBaseline(t *testing.T, report benchReport) {
+	t.Helper()
+
+	data, err := os.ReadFile(*benchBaseline)
+	if err != nil {
+		t.Fatalf("reading baseline: %v", err)
+	}
+	var baseline benchReport
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		t.Fatalf("decoding baseline: %v", err)
+	}
+
+	before := make(map[string]int64, len(baseline.Results))
+	for _, r := range baseline.Results {
+		before[r.Name] = r.NsPerOp
+	}
+	for _, r := range report.Results {
+		old, ok := before[r.Name]
+		if !ok || old <= 0 {
+			continue
+		}
+		if change := float64(r.NsPerOp-old) / float64(old); change > *benchTolerance {
+			t.Errorf("%s regressed %.0f%%: %d ns/op, was %d ns/op", r.Name, change*100, r.NsPerOp, old)
+		}
+	}
+}
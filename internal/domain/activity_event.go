@@ -15,16 +15,50 @@ type ActivityEvent struct {
 	eventType   EventType
 	weight      Weight
 	metadata    map[string]any
-	createdAt   time.Time
+	occurredAt  time.Time // when it happened, momentum windows are measured against this
+	createdAt   time.Time // when pulse received it
 }
 
 var (
 	ErrEventCommunityEmpty = errors.New("event must have a community id")
 	ErrEventTypeEmpty      = errors.New("event must have an event type")
+	ErrOccurredInFuture    = errors.New("occurred_at is further in the future than the allowed clock skew")
+	ErrOccurredTooLongAgo  = errors.New("occurred_at is older than the allowed event age")
 )
 
+// EventTimeBounds limits how far a client's occurred_at may be from when the event is received.
+type EventTimeBounds struct {
+	// MaxSkew is how far ahead of the server clock occurred_at may be, for clients with fast clocks.
+	MaxSkew time.Duration
+
+	// MaxAge is how far back occurred_at may be, for backfilled and delayed events.
+	MaxAge time.Duration
+}
+
+// DefaultEventTimeBounds returns sensible defaults.
+func DefaultEventTimeBounds() EventTimeBounds {
+	return EventTimeBounds{
+		MaxSkew: 5 * time.Minute,
+		MaxAge:  24 * time.Hour,
+	}
+}
+
+// Check validates an occurred_at against the time the event was received.
+func (b EventTimeBounds) Check(occurredAt, receivedAt time.Time) error {
+	if occurredAt.After(receivedAt.Add(b.MaxSkew)) {
+		return ErrOccurredInFuture
+	}
+	if occurredAt.Before(receivedAt.Add(-b.MaxAge)) {
+		return ErrOccurredTooLongAgo
+	}
+	return nil
+}
+
 // NewActivityEvent creates a new ActivityEvent with the required fields.
+// clock provides the creation timestamp, which is also when the event occurred
+// unless SetOccurredAt says otherwise.
 func NewActivityEvent(
+	clock Clock,
 	communityID CommunityID,
 	userID *UserID,
 	eventType EventType,
@@ -37,6 +71,9 @@ func NewActivityEvent(
 	if !eventType.IsValid() {
 		return nil, ErrEventTypeEmpty
 	}
+	if err := ValidateMetadata(metadata); err != nil {
+		return nil, err
+	}
 
 	// defensive copy of metadata to prevent external mutation
 	var metadataCopy map[string]any
@@ -47,6 +84,7 @@ func NewActivityEvent(
 		}
 	}
 
+	now := clockOrSystem(clock).Now()
 	return &ActivityEvent{
 		id:          NewEventID(),
 		communityID: communityID,
@@ -54,18 +92,26 @@ func NewActivityEvent(
 		eventType:   eventType,
 		weight:      weight,
 		metadata:    metadataCopy,
-		createdAt:   time.Now().UTC(),
+		occurredAt:  now,
+		createdAt:   now,
 	}, nil
 }
 
+// SetOccurredAt records when a client says the event happened, checked against EventTimeBounds first.
+// only meant for events that haven't been stored yet.
+func (e *ActivityEvent) SetOccurredAt(occurredAt time.Time) {
+	e.occurredAt = occurredAt.UTC()
+}
+
 // NewActivityEventWithDefaultWeight creates an event using the default weight for its type.
 func NewActivityEventWithDefaultWeight(
+	clock Clock,
 	communityID CommunityID,
 	userID *UserID,
 	eventType EventType,
 	metadata map[string]any,
 ) (*ActivityEvent, error) {
-	return NewActivityEvent(communityID, userID, eventType, eventType.DefaultWeight(), metadata)
+	return NewActivityEvent(clock, communityID, userID, eventType, eventType.DefaultWeight(), metadata)
 }
 
 // ReconstructActivityEvent recreates an ActivityEvent from stored data.
@@ -79,6 +125,7 @@ func ReconstructActivityEvent(
 	eventType EventType,
 	weight Weight,
 	metadata map[string]any,
+	occurredAt time.Time,
 	createdAt time.Time,
 ) *ActivityEvent {
 	return &ActivityEvent{
@@ -88,6 +135,7 @@ func ReconstructActivityEvent(
 		eventType:   eventType,
 		weight:      weight,
 		metadata:    metadata,
+		occurredAt:  occurredAt,
 		createdAt:   createdAt,
 	}
 }
@@ -119,20 +167,25 @@ func (e *ActivityEvent) Weight() Weight {
 
 // Metadata returns a copy of the event-specific metadata.
 // returns a defensive copy to prevent external mutation of internal state.
-func (e *ActivityEvent) Metadata() map[string]any {
+func (e *ActivityEvent) Metadata() Metadata {
 	if e.metadata == nil {
 		return nil
 	}
 	// shallow copy is sufficient for typical metadata (strings, numbers)
 	// deep copy would be needed for nested structures, but we don't use those
-	result := make(map[string]any, len(e.metadata))
+	result := make(Metadata, len(e.metadata))
 	for k, v := range e.metadata {
 		result[k] = v
 	}
 	return result
 }
 
-// CreatedAt returns when this event was created.
+// OccurredAt returns when this event happened, as reported by the client or else when it was received.
+func (e *ActivityEvent) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// CreatedAt returns when this event was received.
 func (e *ActivityEvent) CreatedAt() time.Time {
 	return e.createdAt
 }
@@ -155,6 +208,20 @@ func (e *ActivityEvent) MomentumContribution() float64 {
 	return -e.weight.Value()
 }
 
+// Region returns the region from the event's metadata, or the zero Region if it has none.
+// the ingest use case validates it, so an invalid one only comes from older data and is ignored.
+func (e *ActivityEvent) Region() Region {
+	s, err := Metadata(e.metadata).GetString(MetadataRegionKey)
+	if err != nil {
+		return Region{}
+	}
+	region, err := NewRegion(s)
+	if err != nil {
+		return Region{}
+	}
+	return region
+}
+
 // IsAnonymous returns true if this event has no associated user.
 func (e *ActivityEvent) IsAnonymous() bool {
 	return e.userID == nil
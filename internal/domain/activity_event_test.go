@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestNewActivityEvent_ValidInput(t *testing.T) {
@@ -11,7 +13,7 @@ func TestNewActivityEvent_ValidInput(t *testing.T) {
 	weight := DefaultEventWeight()
 	metadata := map[string]any{"source": "web"}
 
-	event, err := NewActivityEvent(communityID, &userID, eventType, weight, metadata)
+	event, err := NewActivityEvent(SystemClock, communityID, &userID, eventType, weight, metadata)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -30,7 +32,7 @@ func TestNewActivityEvent_ValidInput(t *testing.T) {
 func TestNewActivityEvent_EmptyCommunityID(t *testing.T) {
 	userID := NewUserID()
 
-	_, err := NewActivityEvent(CommunityID{}, &userID, EventTypeJoin, DefaultEventWeight(), nil)
+	_, err := NewActivityEvent(SystemClock, CommunityID{}, &userID, EventTypeJoin, DefaultEventWeight(), nil)
 
 	if err != ErrEventCommunityEmpty {
 		t.Errorf("expected ErrEventCommunityEmpty, got %v", err)
@@ -41,7 +43,7 @@ func TestNewActivityEvent_InvalidEventType(t *testing.T) {
 	communityID := NewCommunityID()
 	userID := NewUserID()
 
-	_, err := NewActivityEvent(communityID, &userID, EventType("invalid"), DefaultEventWeight(), nil)
+	_, err := NewActivityEvent(SystemClock, communityID, &userID, EventType("invalid"), DefaultEventWeight(), nil)
 
 	if err != ErrEventTypeEmpty {
 		t.Errorf("expected ErrEventTypeEmpty, got %v", err)
@@ -53,7 +55,7 @@ func TestActivityEvent_MetadataImmutability(t *testing.T) {
 	userID := NewUserID()
 	originalMetadata := map[string]any{"key": "original"}
 
-	event, err := NewActivityEvent(communityID, &userID, EventTypePost, DefaultEventWeight(), originalMetadata)
+	event, err := NewActivityEvent(SystemClock, communityID, &userID, EventTypePost, DefaultEventWeight(), originalMetadata)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -77,7 +79,7 @@ func TestActivityEvent_MetadataGetterReturnsDefensiveCopy(t *testing.T) {
 	userID := NewUserID()
 	metadata := map[string]any{"key": "value"}
 
-	event, err := NewActivityEvent(communityID, &userID, EventTypePost, DefaultEventWeight(), metadata)
+	event, err := NewActivityEvent(SystemClock, communityID, &userID, EventTypePost, DefaultEventWeight(), metadata)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -119,7 +121,7 @@ func TestActivityEvent_MomentumContribution(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error creating weight: %v", err)
 			}
-			event, err := NewActivityEvent(communityID, &userID, tt.eventType, weight, nil)
+			event, err := NewActivityEvent(SystemClock, communityID, &userID, tt.eventType, weight, nil)
 			if err != nil {
 				t.Fatalf("unexpected error creating event: %v", err)
 			}
@@ -139,7 +141,7 @@ func TestActivityEvent_MomentumContribution(t *testing.T) {
 func TestActivityEvent_AnonymousEvents(t *testing.T) {
 	communityID := NewCommunityID()
 
-	event, err := NewActivityEvent(communityID, nil, EventTypeView, DefaultEventWeight(), nil)
+	event, err := NewActivityEvent(SystemClock, communityID, nil, EventTypeView, DefaultEventWeight(), nil)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -151,3 +153,48 @@ func TestActivityEvent_AnonymousEvents(t *testing.T) {
 		t.Error("expected nil user id")
 	}
 }
+
+func TestEventTimeBounds_Check(t *testing.T) {
+	bounds := EventTimeBounds{MaxSkew: 5 * time.Minute, MaxAge: 24 * time.Hour}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		occurredAt time.Time
+		wantErr    error
+	}{
+		{"now", now, nil},
+		{"within skew", now.Add(5 * time.Minute), nil},
+		{"beyond skew", now.Add(5*time.Minute + time.Second), ErrOccurredInFuture},
+		{"within age", now.Add(-24 * time.Hour), nil},
+		{"beyond age", now.Add(-24*time.Hour - time.Second), ErrOccurredTooLongAgo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := bounds.Check(tt.occurredAt, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestActivityEvent_OccurredAt(t *testing.T) {
+	received := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	event, err := NewActivityEvent(FixedClock(received), NewCommunityID(), nil, EventTypeView, DefaultEventWeight(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !event.OccurredAt().Equal(received) {
+		t.Errorf("expected occurred_at to default to created_at, got %v", event.OccurredAt())
+	}
+
+	occurred := time.Date(2025, 6, 1, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	event.SetOccurredAt(occurred)
+	if !event.OccurredAt().Equal(occurred) || event.OccurredAt().Location() != time.UTC {
+		t.Errorf("expected occurred_at %v in UTC, got %v", occurred, event.OccurredAt())
+	}
+	if !event.CreatedAt().Equal(received) {
+		t.Errorf("expected created_at unchanged, got %v", event.CreatedAt())
+	}
+}
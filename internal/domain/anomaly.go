@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidAnomalyStatus = errors.New("anomaly status must be pending, confirmed or dismissed")
+	ErrAnomalyReviewed      = errors.New("anomaly flag was already reviewed")
+)
+
+// AnomalyStatus is where a flag is in review.
+type AnomalyStatus string
+
+const (
+	// AnomalyPending quarantines the flagged events until an admin reviews them.
+	AnomalyPending AnomalyStatus = "pending"
+	// AnomalyConfirmed keeps the events from the flagged period out of momentum for good.
+	AnomalyConfirmed AnomalyStatus = "confirmed"
+	// AnomalyDismissed releases the quarantine, the events count again.
+	AnomalyDismissed AnomalyStatus = "dismissed"
+)
+
+// ParseAnomalyStatus validates a status name.
+func ParseAnomalyStatus(s string) (AnomalyStatus, error) {
+	switch status := AnomalyStatus(s); status {
+	case AnomalyPending, AnomalyConfirmed, AnomalyDismissed:
+		return status, nil
+	default:
+		return "", ErrInvalidAnomalyStatus
+	}
+}
+
+// String returns the status name.
+func (s AnomalyStatus) String() string {
+	return string(s)
+}
+
+// AnomalyThresholds decides when an ingest rate deviates enough from its baseline to flag.
+type AnomalyThresholds struct {
+	// Ratio is how many times the expected events the window must reach (e.g., 10 = 10x).
+	Ratio float64
+
+	// MinEvents ignores windows with fewer events, so small communities aren't flagged for noise.
+	MinEvents int64
+}
+
+// DefaultAnomalyThresholds returns sensible defaults.
+func DefaultAnomalyThresholds() AnomalyThresholds {
+	return AnomalyThresholds{
+		Ratio:     10,
+		MinEvents: 200,
+	}
+}
+
+// IsAnomalous reports whether observed events are far above the expected ones.
+// expected below one event is treated as one, so a quiet baseline doesn't flag every burst.
+func (t AnomalyThresholds) IsAnomalous(observed int64, expected float64) bool {
+	if observed < t.MinEvents {
+		return false
+	}
+	if expected < 1 {
+		expected = 1
+	}
+	return float64(observed) >= t.Ratio*expected
+}
+
+// IngestRate is how many events a community, or one user in it, sent in the recent window
+// and in the baseline before it.
+type IngestRate struct {
+	CommunityID CommunityID
+	UserID      *UserID // nil for the whole community
+
+	// Observed is the events in the recent window.
+	Observed int64
+
+	// Baseline is the events in the baseline period before the window.
+	Baseline int64
+}
+
+// Expected is the events the window would have had at the baseline rate,
+// where the baseline period is windows times as long as the window.
+func (r IngestRate) Expected(windows float64) float64 {
+	if windows <= 0 {
+		return 0
+	}
+	return float64(r.Baseline) / windows
+}
+
+// AnomalyFlag marks a community, or a user in one, whose ingest rate looks like gaming.
+// while pending, the events sent since the flagged window started don't count toward momentum.
+type AnomalyFlag struct {
+	id          uuid.UUID
+	communityID CommunityID
+	userID      *UserID
+	status      AnomalyStatus
+	observed    int64
+	expected    float64
+	windowStart time.Time
+	detectedAt  time.Time
+	reviewedAt  *time.Time
+	reviewedBy  string
+}
+
+// NewAnomalyFlag flags a rate whose window started at windowStart.
+func NewAnomalyFlag(clock Clock, rate IngestRate, expected float64, windowStart time.Time) (*AnomalyFlag, error) {
+	if rate.CommunityID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if rate.UserID != nil && rate.UserID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+
+	return &AnomalyFlag{
+		id:          uuid.New(),
+		communityID: rate.CommunityID,
+		userID:      rate.UserID,
+		status:      AnomalyPending,
+		observed:    rate.Observed,
+		expected:    expected,
+		windowStart: windowStart,
+		detectedAt:  clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructAnomalyFlag rebuilds a flag from persistence.
+func ReconstructAnomalyFlag(
+	id uuid.UUID,
+	communityID CommunityID,
+	userID *UserID,
+	status AnomalyStatus,
+	observed int64,
+	expected float64,
+	windowStart time.Time,
+	detectedAt time.Time,
+	reviewedAt *time.Time,
+	reviewedBy string,
+) *AnomalyFlag {
+	return &AnomalyFlag{
+		id:          id,
+		communityID: communityID,
+		userID:      userID,
+		status:      status,
+		observed:    observed,
+		expected:    expected,
+		windowStart: windowStart,
+		detectedAt:  detectedAt,
+		reviewedAt:  reviewedAt,
+		reviewedBy:  reviewedBy,
+	}
+}
+
+// Getters
+
+func (f *AnomalyFlag) ID() uuid.UUID            { return f.id }
+func (f *AnomalyFlag) CommunityID() CommunityID { return f.communityID }
+func (f *AnomalyFlag) UserID() *UserID          { return f.userID }
+func (f *AnomalyFlag) Status() AnomalyStatus    { return f.status }
+func (f *AnomalyFlag) Observed() int64          { return f.observed }
+func (f *AnomalyFlag) Expected() float64        { return f.expected }
+func (f *AnomalyFlag) WindowStart() time.Time   { return f.windowStart }
+func (f *AnomalyFlag) DetectedAt() time.Time    { return f.detectedAt }
+func (f *AnomalyFlag) ReviewedAt() *time.Time   { return f.reviewedAt }
+func (f *AnomalyFlag) ReviewedBy() string       { return f.reviewedBy }
+
+// Ratio is how many times the expected events were observed.
+func (f *AnomalyFlag) Ratio() float64 {
+	expected := f.expected
+	if expected < 1 {
+		expected = 1
+	}
+	return float64(f.observed) / expected
+}
+
+// Confirm upholds the flag, keeping the quarantined events out of momentum.
+func (f *AnomalyFlag) Confirm(clock Clock, reviewer string) error {
+	return f.review(clock, AnomalyConfirmed, reviewer)
+}
+
+// Dismiss releases the flag, the quarantined events count again.
+func (f *AnomalyFlag) Dismiss(clock Clock, reviewer string) error {
+	return f.review(clock, AnomalyDismissed, reviewer)
+}
+
+func (f *AnomalyFlag) review(clock Clock, status AnomalyStatus, reviewer string) error {
+	if f.status != AnomalyPending {
+		return ErrAnomalyReviewed
+	}
+	now := clockOrSystem(clock).Now()
+	f.status = status
+	f.reviewedAt = &now
+	f.reviewedBy = reviewer
+	return nil
+}
+
+// AnomalyFlagRepository persists anomaly flags.
+type AnomalyFlagRepository interface {
+	// Save inserts or updates a flag.
+	Save(ctx context.Context, flag *AnomalyFlag) error
+
+	// FindByID returns a flag, or ErrNotFound.
+	FindByID(ctx context.Context, id uuid.UUID) (*AnomalyFlag, error)
+
+	// FindPending returns the pending flag for a community (userID nil) or a user in it, or ErrNotFound.
+	FindPending(ctx context.Context, communityID CommunityID, userID *UserID) (*AnomalyFlag, error)
+
+	// List returns flags with the given status, newest first.
+	List(ctx context.Context, status AnomalyStatus, limit, offset int) ([]*AnomalyFlag, error)
+
+	// SumQuarantinedWeights returns the momentum contribution of a community's events since
+	// that fall in a pending or confirmed quarantine, counting each event once.
+	SumQuarantinedWeights(ctx context.Context, communityID CommunityID, since time.Time) (float64, error)
+
+	// SumQuarantinedWeightsByRegion is SumQuarantinedWeights per region, for regional leaderboards.
+	SumQuarantinedWeightsByRegion(ctx context.Context, communityID CommunityID, since time.Time) (map[string]float64, error)
+}
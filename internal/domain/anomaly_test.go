@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnomalyThresholds_IsAnomalous(t *testing.T) {
+	thresholds := AnomalyThresholds{Ratio: 10, MinEvents: 100}
+
+	tests := []struct {
+		name     string
+		observed int64
+		expected float64
+		want     bool
+	}{
+		{"far above baseline", 1000, 50, true},
+		{"exactly ratio times", 500, 50, true},
+		{"below ratio", 499, 50, false},
+		{"too few events", 99, 0, false},
+		{"quiet baseline counts as one", 100, 0.1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := thresholds.IsAnomalous(tt.observed, tt.expected); got != tt.want {
+				t.Errorf("IsAnomalous(%d, %v) = %v, want %v", tt.observed, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngestRate_Expected(t *testing.T) {
+	rate := IngestRate{CommunityID: NewCommunityID(), Observed: 10, Baseline: 960}
+	if got := rate.Expected(96); got != 10 {
+		t.Errorf("expected 10 events per window, got %v", got)
+	}
+	if got := rate.Expected(0); got != 0 {
+		t.Errorf("expected 0 without windows, got %v", got)
+	}
+}
+
+func TestAnomalyFlag_Review(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(now)
+	userID := NewUserID()
+
+	flag, err := NewAnomalyFlag(clock, IngestRate{CommunityID: NewCommunityID(), UserID: &userID, Observed: 900}, 30, now.Add(-15*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flag.Status() != AnomalyPending {
+		t.Fatalf("expected a new flag to be pending, got %s", flag.Status())
+	}
+	if flag.Ratio() != 30 {
+		t.Errorf("expected ratio 30, got %v", flag.Ratio())
+	}
+
+	if err := flag.Dismiss(clock, "admin"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flag.Status() != AnomalyDismissed || flag.ReviewedAt() == nil || !flag.ReviewedAt().Equal(now) {
+		t.Errorf("expected a dismissed flag reviewed at %v, got %s at %v", now, flag.Status(), flag.ReviewedAt())
+	}
+	if err := flag.Confirm(clock, "admin"); !errors.Is(err, ErrAnomalyReviewed) {
+		t.Errorf("expected ErrAnomalyReviewed, got %v", err)
+	}
+}
+
+func TestNewAnomalyFlag_RequiresCommunity(t *testing.T) {
+	_, err := NewAnomalyFlag(SystemClock, IngestRate{}, 1, time.Now())
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, plaintext, err := NewAPIKey(SystemClock, NewUserID(), "  ingestion  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(plaintext, APIKeyPrefix) {
+		t.Errorf("expected key to start with %s, got %s", APIKeyPrefix, plaintext)
+	}
+	if key.Hash() != HashAPIKey(plaintext) {
+		t.Error("expected stored hash to match the plaintext key")
+	}
+	if strings.Contains(key.Hash(), plaintext) {
+		t.Error("hash must not contain the plaintext key")
+	}
+	if !strings.HasSuffix(plaintext, key.Hint()) {
+		t.Errorf("expected hint %s to be the key suffix", key.Hint())
+	}
+	if key.Name() != "ingestion" {
+		t.Errorf("expected trimmed name, got %q", key.Name())
+	}
+	if key.IsRevoked() {
+		t.Error("new key should not be revoked")
+	}
+}
+
+func TestNewAPIKey_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  UserID
+		keyName string
+		wantErr error
+	}{
+		{"missing user", UserID{}, "svc", ErrInvalidInput},
+		{"empty name", NewUserID(), "", ErrAPIKeyNameEmpty},
+		{"blank name", NewUserID(), "   ", ErrAPIKeyNameEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewAPIKey(SystemClock, tt.userID, tt.keyName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewOrganizationAPIKey(t *testing.T) {
+	orgID := NewOrganizationID()
+	issuer := NewUserID()
+
+	key, _, err := NewOrganizationAPIKey(SystemClock, orgID, issuer, "ci")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.OrganizationID() == nil || *key.OrganizationID() != orgID {
+		t.Errorf("expected key scoped to %s", orgID)
+	}
+	if key.UserID() != issuer {
+		t.Errorf("expected key to act as the issuer")
+	}
+
+	if _, _, err := NewOrganizationAPIKey(SystemClock, OrganizationID{}, issuer, "ci"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for missing organization, got %v", err)
+	}
+}
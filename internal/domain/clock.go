@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Clock tells the domain what time it is.
+// entities take one at construction so timestamps are deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the wall clock in UTC.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now().UTC() }
+
+// SystemClock is the real clock. use it everywhere outside tests.
+var SystemClock Clock = systemClock{}
+
+// FixedClock always returns the same instant. for tests.
+type FixedClock time.Time
+
+// Now returns the fixed instant.
+func (c FixedClock) Now() time.Time { return time.Time(c) }
+
+// clockOrSystem guards against a nil clock, falling back to the real one.
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
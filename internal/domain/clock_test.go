@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedClock_StampsEntities(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(now)
+
+	slug, _ := NewSlug("golang")
+	community, err := NewCommunity(clock, slug, "Golang", NewUserID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !community.CreatedAt().Equal(now) || !community.UpdatedAt().Equal(now) {
+		t.Errorf("expected timestamps %s, got created %s updated %s", now, community.CreatedAt(), community.UpdatedAt())
+	}
+
+	// mutators use the clock the entity was built with
+	community.UpdateMomentum(NewMomentum(5))
+	if got := community.MomentumUpdatedAt(); got == nil || !got.Equal(now) {
+		t.Errorf("expected momentum updated at %s, got %v", now, got)
+	}
+
+	event, err := NewActivityEvent(clock, community.ID(), nil, EventTypeView, DefaultEventWeight(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !event.CreatedAt().Equal(now) {
+		t.Errorf("expected event created at %s, got %s", now, event.CreatedAt())
+	}
+}
+
+func TestNilClock_FallsBackToSystemClock(t *testing.T) {
+	before := time.Now().UTC()
+	user, err := NewUser(nil, "ext-1", Username{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.CreatedAt().Before(before) {
+		t.Errorf("expected a current timestamp, got %s", user.CreatedAt())
+	}
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"cmp"
 	"errors"
+	"slices"
 	"time"
 )
 
@@ -13,22 +15,61 @@ type Community struct {
 	name              string
 	description       string
 	creatorID         UserID
+	organizationID    *OrganizationID
 	avatarURL         string
 	isActive          bool
+	visibility        CommunityVisibility
 	currentMomentum   Momentum
 	momentumUpdatedAt *time.Time
 	createdAt         time.Time
 	updatedAt         time.Time
+	clock             Clock
 }
 
 var (
 	ErrCommunityNameEmpty    = errors.New("community name cannot be empty")
 	ErrCommunityNameTooLong  = errors.New("community name must be at most 255 characters")
 	ErrCommunityCreatorEmpty = errors.New("community must have a creator")
+	ErrInvalidVisibility     = errors.New("visibility must be public, unlisted or private")
 )
 
+// CommunityVisibility controls who can find and see a community.
+type CommunityVisibility string
+
+const (
+	// VisibilityPublic communities are listed on leaderboards and open to everyone.
+	VisibilityPublic CommunityVisibility = "public"
+	// VisibilityUnlisted communities are open to anyone with the id or slug,
+	// but left off leaderboards.
+	VisibilityUnlisted CommunityVisibility = "unlisted"
+	// VisibilityPrivate communities are left off leaderboards, and only
+	// members can send or read their activity.
+	VisibilityPrivate CommunityVisibility = "private"
+)
+
+// ParseCommunityVisibility validates a visibility name.
+func ParseCommunityVisibility(s string) (CommunityVisibility, error) {
+	switch v := CommunityVisibility(s); v {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate:
+		return v, nil
+	default:
+		return "", ErrInvalidVisibility
+	}
+}
+
+// String returns the visibility name.
+func (v CommunityVisibility) String() string {
+	return string(v)
+}
+
+// IsListed reports whether communities with this visibility appear on leaderboards.
+func (v CommunityVisibility) IsListed() bool {
+	return v == VisibilityPublic
+}
+
 // NewCommunity creates a new Community with the required fields.
-func NewCommunity(slug Slug, name string, creatorID UserID) (*Community, error) {
+// clock stamps creation and every later change.
+func NewCommunity(clock Clock, slug Slug, name string, creatorID UserID) (*Community, error) {
 	if name == "" {
 		return nil, ErrCommunityNameEmpty
 	}
@@ -39,16 +80,19 @@ func NewCommunity(slug Slug, name string, creatorID UserID) (*Community, error)
 		return nil, ErrCommunityCreatorEmpty
 	}
 
-	now := time.Now().UTC()
+	clock = clockOrSystem(clock)
+	now := clock.Now()
 	return &Community{
 		id:              NewCommunityID(),
 		slug:            slug,
 		name:            name,
 		creatorID:       creatorID,
 		isActive:        true,
+		visibility:      VisibilityPublic,
 		currentMomentum: NewMomentum(0),
 		createdAt:       now,
 		updatedAt:       now,
+		clock:           clock,
 	}, nil
 }
 
@@ -60,8 +104,10 @@ func ReconstructCommunity(
 	name string,
 	description string,
 	creatorID UserID,
+	organizationID *OrganizationID,
 	avatarURL string,
 	isActive bool,
+	visibility CommunityVisibility,
 	currentMomentum Momentum,
 	momentumUpdatedAt *time.Time,
 	createdAt time.Time,
@@ -73,12 +119,15 @@ func ReconstructCommunity(
 		name:              name,
 		description:       description,
 		creatorID:         creatorID,
+		organizationID:    organizationID,
 		avatarURL:         avatarURL,
 		isActive:          isActive,
+		visibility:        visibility,
 		currentMomentum:   currentMomentum,
 		momentumUpdatedAt: momentumUpdatedAt,
 		createdAt:         createdAt,
 		updatedAt:         updatedAt,
+		clock:             SystemClock,
 	}
 }
 
@@ -107,6 +156,11 @@ func (c *Community) CreatorID() UserID {
 	return c.creatorID
 }
 
+// OrganizationID returns the organization the community belongs to, nil if it's standalone.
+func (c *Community) OrganizationID() *OrganizationID {
+	return c.organizationID
+}
+
 // AvatarURL returns the community's avatar URL.
 func (c *Community) AvatarURL() string {
 	return c.avatarURL
@@ -117,6 +171,16 @@ func (c *Community) IsActive() bool {
 	return c.isActive
 }
 
+// Visibility returns who can find and see the community.
+func (c *Community) Visibility() CommunityVisibility {
+	return c.visibility
+}
+
+// IsPrivate reports whether only members can see the community's activity.
+func (c *Community) IsPrivate() bool {
+	return c.visibility == VisibilityPrivate
+}
+
 // CurrentMomentum returns the precomputed momentum score.
 func (c *Community) CurrentMomentum() Momentum {
 	return c.currentMomentum
@@ -141,24 +205,41 @@ func (c *Community) UpdatedAt() time.Time {
 // this is called by the momentum calculation job.
 func (c *Community) UpdateMomentum(momentum Momentum) {
 	c.currentMomentum = momentum
-	now := time.Now().UTC()
+	now := c.clock.Now()
 	c.momentumUpdatedAt = &now
 	c.updatedAt = now
 }
 
+// MoveToOrganization puts the community under an organization, or takes it out with nil.
+func (c *Community) MoveToOrganization(organizationID *OrganizationID) {
+	c.organizationID = organizationID
+	c.updatedAt = c.clock.Now()
+}
+
+// SetVisibility changes who can find and see the community.
+func (c *Community) SetVisibility(visibility CommunityVisibility) error {
+	if _, err := ParseCommunityVisibility(string(visibility)); err != nil {
+		return err
+	}
+	c.visibility = visibility
+	c.updatedAt = c.clock.Now()
+	return nil
+}
+
 // Deactivate marks the community as inactive.
 func (c *Community) Deactivate() {
 	c.isActive = false
-	c.updatedAt = time.Now().UTC()
+	c.updatedAt = c.clock.Now()
 }
 
 // Activate marks the community as active.
 func (c *Community) Activate() {
 	c.isActive = true
-	c.updatedAt = time.Now().UTC()
+	c.updatedAt = c.clock.Now()
 }
 
 // UpdateDetails updates the community's descriptive fields.
+// the description is cleaned with DescriptionRules before it's stored.
 func (c *Community) UpdateDetails(name, description, avatarURL string) error {
 	if name == "" {
 		return ErrCommunityNameEmpty
@@ -166,10 +247,38 @@ func (c *Community) UpdateDetails(name, description, avatarURL string) error {
 	if len(name) > 255 {
 		return ErrCommunityNameTooLong
 	}
+	description, err := DescriptionRules.Clean(description)
+	if err != nil {
+		return err
+	}
 
 	c.name = name
 	c.description = description
 	c.avatarURL = avatarURL
-	c.updatedAt = time.Now().UTC()
+	c.updatedAt = c.clock.Now()
 	return nil
 }
+
+// CompareLeaderboardRank orders two communities on a leaderboard: higher momentum first,
+// then more members, then the older community, then by id so ties never flap between
+// reads. memberCounts comes from the community summaries, a missing count is zero.
+// postgres ORDER BY clauses ranking by momentum follow the same order.
+func CompareLeaderboardRank(a, b *Community, memberCounts map[CommunityID]int) int {
+	if c := cmp.Compare(b.currentMomentum.Value(), a.currentMomentum.Value()); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(memberCounts[b.id], memberCounts[a.id]); c != 0 {
+		return c
+	}
+	if c := a.createdAt.Compare(b.createdAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.id.String(), b.id.String())
+}
+
+// SortByLeaderboardRank sorts communities in place by CompareLeaderboardRank.
+func SortByLeaderboardRank(communities []*Community, memberCounts map[CommunityID]int) {
+	slices.SortFunc(communities, func(a, b *Community) int {
+		return CompareLeaderboardRank(a, b, memberCounts)
+	})
+}
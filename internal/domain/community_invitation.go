@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvitationTokenPrefix marks invitation tokens so they aren't mistaken for api keys.
+const InvitationTokenPrefix = "pinv_"
+
+const (
+	// DefaultInvitationTTL is how long an invitation is valid when no expiry is given.
+	DefaultInvitationTTL = 7 * 24 * time.Hour
+	// MinInvitationTTL and MaxInvitationTTL bound invitation expiry.
+	MinInvitationTTL = time.Hour
+	MaxInvitationTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvitationTTLOutOfRange = errors.New("invitation expiry must be between 1h and 720h")
+	ErrInvitationInvalid       = errors.New("invalid invitation token")
+	ErrInvitationExpired       = errors.New("invitation has expired")
+	ErrInvitationRevoked       = errors.New("invitation has been revoked")
+	ErrInvitationUsed          = errors.New("invitation has already been used")
+)
+
+// CommunityInvitation is a single-use token that makes whoever accepts it a member.
+// like api keys, only the sha256 of the token is stored.
+type CommunityInvitation struct {
+	id          uuid.UUID
+	communityID CommunityID
+	createdBy   UserID
+	tokenHash   string
+	hint        string
+	expiresAt   time.Time
+	createdAt   time.Time
+	revokedAt   *time.Time
+	acceptedBy  *UserID
+	acceptedAt  *time.Time
+	clock       Clock
+}
+
+// NewCommunityInvitation generates an invitation valid for ttl.
+// returns the entity and the plaintext token, which is never stored.
+func NewCommunityInvitation(clock Clock, communityID CommunityID, createdBy UserID, ttl time.Duration) (*CommunityInvitation, string, error) {
+	if communityID.IsZero() || createdBy.IsZero() {
+		return nil, "", ErrInvalidInput
+	}
+	if ttl < MinInvitationTTL || ttl > MaxInvitationTTL {
+		return nil, "", ErrInvitationTTLOutOfRange
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	plaintext := InvitationTokenPrefix + hex.EncodeToString(secret)
+
+	clock = clockOrSystem(clock)
+	now := clock.Now()
+	return &CommunityInvitation{
+		id:          uuid.New(),
+		communityID: communityID,
+		createdBy:   createdBy,
+		tokenHash:   HashAPIKey(plaintext),
+		hint:        plaintext[len(plaintext)-4:],
+		expiresAt:   now.Add(ttl),
+		createdAt:   now,
+		clock:       clock,
+	}, plaintext, nil
+}
+
+// ReconstructCommunityInvitation rebuilds an invitation from persistence.
+func ReconstructCommunityInvitation(
+	id uuid.UUID,
+	communityID CommunityID,
+	createdBy UserID,
+	tokenHash string,
+	hint string,
+	expiresAt time.Time,
+	createdAt time.Time,
+	revokedAt *time.Time,
+	acceptedBy *UserID,
+	acceptedAt *time.Time,
+) *CommunityInvitation {
+	return &CommunityInvitation{
+		id:          id,
+		communityID: communityID,
+		createdBy:   createdBy,
+		tokenHash:   tokenHash,
+		hint:        hint,
+		expiresAt:   expiresAt,
+		createdAt:   createdAt,
+		revokedAt:   revokedAt,
+		acceptedBy:  acceptedBy,
+		acceptedAt:  acceptedAt,
+		clock:       SystemClock,
+	}
+}
+
+// Getters
+
+func (i *CommunityInvitation) ID() uuid.UUID            { return i.id }
+func (i *CommunityInvitation) CommunityID() CommunityID { return i.communityID }
+func (i *CommunityInvitation) CreatedBy() UserID        { return i.createdBy }
+func (i *CommunityInvitation) TokenHash() string        { return i.tokenHash }
+func (i *CommunityInvitation) Hint() string             { return i.hint }
+func (i *CommunityInvitation) ExpiresAt() time.Time     { return i.expiresAt }
+func (i *CommunityInvitation) CreatedAt() time.Time     { return i.createdAt }
+func (i *CommunityInvitation) RevokedAt() *time.Time    { return i.revokedAt }
+func (i *CommunityInvitation) AcceptedBy() *UserID      { return i.acceptedBy }
+func (i *CommunityInvitation) AcceptedAt() *time.Time   { return i.acceptedAt }
+
+// Status is pending, accepted, revoked or expired.
+func (i *CommunityInvitation) Status() string {
+	switch {
+	case i.acceptedAt != nil:
+		return "accepted"
+	case i.revokedAt != nil:
+		return "revoked"
+	case !i.clock.Now().Before(i.expiresAt):
+		return "expired"
+	default:
+		return "pending"
+	}
+}
+
+// Accept marks the invitation used by userID.
+// the repository enforces single use again when storing, in case of a race.
+func (i *CommunityInvitation) Accept(userID UserID) error {
+	if userID.IsZero() {
+		return ErrInvalidInput
+	}
+	switch i.Status() {
+	case "accepted":
+		return ErrInvitationUsed
+	case "revoked":
+		return ErrInvitationRevoked
+	case "expired":
+		return ErrInvitationExpired
+	}
+
+	now := i.clock.Now()
+	i.acceptedBy = &userID
+	i.acceptedAt = &now
+	return nil
+}
+
+// Revoke stops a pending invitation from being accepted.
+// revoking an accepted invitation doesn't remove the member.
+func (i *CommunityInvitation) Revoke() error {
+	if i.acceptedAt != nil {
+		return ErrInvitationUsed
+	}
+	if i.revokedAt == nil {
+		now := i.clock.Now()
+		i.revokedAt = &now
+	}
+	return nil
+}
+
+// CommunityInvitationRepository defines persistence for invitations.
+type CommunityInvitationRepository interface {
+	Create(ctx context.Context, invitation *CommunityInvitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*CommunityInvitation, error)
+	FindByTokenHash(ctx context.Context, hash string) (*CommunityInvitation, error)
+	ListByCommunity(ctx context.Context, communityID CommunityID) ([]*CommunityInvitation, error)
+
+	// Revoke stores a revoked invitation.
+	Revoke(ctx context.Context, invitation *CommunityInvitation) error
+
+	// Accept stores an accepted invitation and adds the member in one transaction.
+	// returns ErrInvitationUsed if the invitation was accepted or revoked concurrently.
+	Accept(ctx context.Context, invitation *CommunityInvitation, member *CommunityMember) error
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommunityInvitation_Accept(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	inv, token, err := NewCommunityInvitation(FixedClock(now), NewCommunityID(), NewUserID(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(token, InvitationTokenPrefix) || inv.TokenHash() != HashAPIKey(token) {
+		t.Fatal("token and hash don't match")
+	}
+
+	if err := inv.Accept(NewUserID()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := inv.Accept(NewUserID()); !errors.Is(err, ErrInvitationUsed) {
+		t.Errorf("expected ErrInvitationUsed on second accept, got %v", err)
+	}
+	if err := inv.Revoke(); !errors.Is(err, ErrInvitationUsed) {
+		t.Errorf("expected ErrInvitationUsed on revoke, got %v", err)
+	}
+}
+
+func TestCommunityInvitation_ExpiredAndRevoked(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	expired, _, _ := NewCommunityInvitation(FixedClock(now), NewCommunityID(), NewUserID(), time.Hour)
+	expired.clock = FixedClock(now.Add(time.Hour))
+	if err := expired.Accept(NewUserID()); !errors.Is(err, ErrInvitationExpired) {
+		t.Errorf("expected ErrInvitationExpired, got %v", err)
+	}
+
+	revoked, _, _ := NewCommunityInvitation(FixedClock(now), NewCommunityID(), NewUserID(), time.Hour)
+	_ = revoked.Revoke()
+	if err := revoked.Accept(NewUserID()); !errors.Is(err, ErrInvitationRevoked) {
+		t.Errorf("expected ErrInvitationRevoked, got %v", err)
+	}
+
+	if _, _, err := NewCommunityInvitation(SystemClock, NewCommunityID(), NewUserID(), time.Minute); !errors.Is(err, ErrInvitationTTLOutOfRange) {
+		t.Errorf("expected ErrInvitationTTLOutOfRange, got %v", err)
+	}
+}
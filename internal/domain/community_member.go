@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCommunityRole = errors.New("community role must be member or moderator")
+
+// CommunityRole is what a member can do in a community.
+type CommunityRole string
+
+const (
+	CommunityRoleMember    CommunityRole = "member"
+	CommunityRoleModerator CommunityRole = "moderator"
+)
+
+// ParseCommunityRole validates a role name.
+func ParseCommunityRole(s string) (CommunityRole, error) {
+	switch role := CommunityRole(s); role {
+	case CommunityRoleMember, CommunityRoleModerator:
+		return role, nil
+	default:
+		return "", ErrInvalidCommunityRole
+	}
+}
+
+// String returns the role name.
+func (r CommunityRole) String() string {
+	return string(r)
+}
+
+// CommunityMember is a user who joined a community.
+// the creator manages the community without being a member row.
+type CommunityMember struct {
+	communityID CommunityID
+	userID      UserID
+	role        CommunityRole
+	joinedAt    time.Time
+}
+
+// NewCommunityMember creates a membership starting now, as a plain member.
+func NewCommunityMember(clock Clock, communityID CommunityID, userID UserID) (*CommunityMember, error) {
+	if communityID.IsZero() || userID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	return &CommunityMember{
+		communityID: communityID,
+		userID:      userID,
+		role:        CommunityRoleMember,
+		joinedAt:    clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructCommunityMember recreates a membership from stored data.
+func ReconstructCommunityMember(communityID CommunityID, userID UserID, role CommunityRole, joinedAt time.Time) *CommunityMember {
+	return &CommunityMember{
+		communityID: communityID,
+		userID:      userID,
+		role:        role,
+		joinedAt:    joinedAt,
+	}
+}
+
+// Getters
+
+func (m *CommunityMember) CommunityID() CommunityID { return m.communityID }
+func (m *CommunityMember) UserID() UserID           { return m.userID }
+func (m *CommunityMember) Role() CommunityRole      { return m.role }
+func (m *CommunityMember) JoinedAt() time.Time      { return m.joinedAt }
+
+// IsModerator reports whether the member can ban and mute others.
+func (m *CommunityMember) IsModerator() bool {
+	return m.role == CommunityRoleModerator
+}
+
+// SetRole changes what the member can do.
+func (m *CommunityMember) SetRole(role CommunityRole) error {
+	if _, err := ParseCommunityRole(string(role)); err != nil {
+		return err
+	}
+	m.role = role
+	return nil
+}
+
+// CommunityMemberRepository defines persistence for community memberships.
+type CommunityMemberRepository interface {
+	// FindMember returns a membership, or ErrNotFound.
+	FindMember(ctx context.Context, communityID CommunityID, userID UserID) (*CommunityMember, error)
+
+	// ListMembers returns members, longest-standing first.
+	ListMembers(ctx context.Context, communityID CommunityID, limit, offset int) ([]*CommunityMember, error)
+
+	// UpdateRole stores a member's role. returns ErrNotFound for non-members.
+	UpdateRole(ctx context.Context, member *CommunityMember) error
+
+	// RemoveMember deletes a membership. removing a non-member is not an error.
+	RemoveMember(ctx context.Context, communityID CommunityID, userID UserID) error
+}
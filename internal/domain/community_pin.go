@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPinnedCommunities is how many communities can be pinned to the top of the
+// leaderboard at the same time.
+const MaxPinnedCommunities = 3
+
+var (
+	ErrInvalidPinWindow = errors.New("invalid pin window: ends_at must be after starts_at and in the future")
+	ErrTooManyPins      = errors.New("too many pinned communities in this window, at most 3 at a time")
+	ErrPinOverlap       = errors.New("community is already pinned in this window")
+)
+
+// CommunityPin puts a community at the top of the global leaderboard for a time window,
+// above the communities ranked by momentum.
+type CommunityPin struct {
+	id          uuid.UUID
+	communityID CommunityID
+	startsAt    time.Time
+	endsAt      time.Time
+	sponsored   bool
+	createdAt   time.Time
+}
+
+// NewCommunityPin schedules a pin from startsAt until endsAt.
+// sponsored marks a paid placement, which clients should label as such.
+func NewCommunityPin(
+	clock Clock,
+	communityID CommunityID,
+	startsAt, endsAt time.Time,
+	sponsored bool,
+) (*CommunityPin, error) {
+	if communityID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	now := clockOrSystem(clock).Now()
+	if !endsAt.After(startsAt) || !endsAt.After(now) {
+		return nil, ErrInvalidPinWindow
+	}
+
+	return &CommunityPin{
+		id:          uuid.New(),
+		communityID: communityID,
+		startsAt:    startsAt,
+		endsAt:      endsAt,
+		sponsored:   sponsored,
+		createdAt:   now,
+	}, nil
+}
+
+// ReconstructCommunityPin rebuilds a pin from persistence.
+func ReconstructCommunityPin(
+	id uuid.UUID,
+	communityID CommunityID,
+	startsAt, endsAt time.Time,
+	sponsored bool,
+	createdAt time.Time,
+) *CommunityPin {
+	return &CommunityPin{
+		id:          id,
+		communityID: communityID,
+		startsAt:    startsAt,
+		endsAt:      endsAt,
+		sponsored:   sponsored,
+		createdAt:   createdAt,
+	}
+}
+
+// Getters
+
+func (p *CommunityPin) ID() uuid.UUID            { return p.id }
+func (p *CommunityPin) CommunityID() CommunityID { return p.communityID }
+func (p *CommunityPin) StartsAt() time.Time      { return p.startsAt }
+func (p *CommunityPin) EndsAt() time.Time        { return p.endsAt }
+func (p *CommunityPin) Sponsored() bool          { return p.sponsored }
+func (p *CommunityPin) CreatedAt() time.Time     { return p.createdAt }
+
+// IsActiveAt reports whether the community is pinned at t.
+func (p *CommunityPin) IsActiveAt(t time.Time) bool {
+	return !t.Before(p.startsAt) && t.Before(p.endsAt)
+}
+
+// CheckPinCapacity returns ErrTooManyPins if adding pin to the scheduled pins would put
+// more than MaxPinnedCommunities on the leaderboard at any moment, and ErrPinOverlap if
+// the community would be pinned twice at once.
+func CheckPinCapacity(scheduled []*CommunityPin, pin *CommunityPin) error {
+	type boundary struct {
+		at    time.Time
+		delta int
+	}
+	var boundaries []boundary
+	for _, other := range scheduled {
+		if !other.startsAt.Before(pin.endsAt) || !pin.startsAt.Before(other.endsAt) {
+			continue
+		}
+		if other.communityID == pin.communityID {
+			return ErrPinOverlap
+		}
+		start := other.startsAt
+		if start.Before(pin.startsAt) {
+			start = pin.startsAt
+		}
+		boundaries = append(boundaries, boundary{start, 1}, boundary{other.endsAt, -1})
+	}
+
+	// ends sort before starts at the same instant, windows are half-open
+	sort.Slice(boundaries, func(i, j int) bool {
+		if boundaries[i].at.Equal(boundaries[j].at) {
+			return boundaries[i].delta < boundaries[j].delta
+		}
+		return boundaries[i].at.Before(boundaries[j].at)
+	})
+
+	pinned := 1
+	for _, b := range boundaries {
+		if pinned += b.delta; pinned > MaxPinnedCommunities {
+			return ErrTooManyPins
+		}
+	}
+	return nil
+}
+
+// CommunityPinRepository defines persistence for leaderboard pins.
+type CommunityPinRepository interface {
+	Save(ctx context.Context, pin *CommunityPin) error
+
+	// Delete removes a pin, returns ErrNotFound if there's none with the id.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListEndingAfter returns the pins still active or scheduled at t, by start time.
+	ListEndingAfter(ctx context.Context, t time.Time) ([]*CommunityPin, error)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewCommunityPin(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(now)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantErr    error
+	}{
+		{"starting now", now, now.Add(time.Hour), nil},
+		{"scheduled", now.Add(time.Hour), now.Add(2 * time.Hour), nil},
+		{"already running", now.Add(-time.Hour), now.Add(time.Hour), nil},
+		{"ends before it starts", now.Add(time.Hour), now, ErrInvalidPinWindow},
+		{"empty window", now.Add(time.Hour), now.Add(time.Hour), ErrInvalidPinWindow},
+		{"already over", now.Add(-2 * time.Hour), now.Add(-time.Hour), ErrInvalidPinWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommunityPin(clock, NewCommunityID(), tt.start, tt.end, false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCommunityPin_IsActiveAt(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pin := ReconstructCommunityPin([16]byte{1}, NewCommunityID(), start, start.Add(time.Hour), true, start)
+
+	if pin.IsActiveAt(start.Add(-time.Second)) {
+		t.Error("expected pin inactive before its start")
+	}
+	if !pin.IsActiveAt(start) {
+		t.Error("expected pin active at its start")
+	}
+	if pin.IsActiveAt(start.Add(time.Hour)) {
+		t.Error("expected pin inactive at its end")
+	}
+}
+
+func TestCheckPinCapacity(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	pin := func(communityID CommunityID, from, to int) *CommunityPin {
+		return ReconstructCommunityPin([16]byte{}, communityID, at(from), at(to), false, day)
+	}
+	community := NewCommunityID()
+
+	tests := []struct {
+		name      string
+		scheduled []*CommunityPin
+		pin       *CommunityPin
+		wantErr   error
+	}{
+		{"nothing scheduled", nil, pin(community, 0, 24), nil},
+		{
+			"full at the same time",
+			[]*CommunityPin{pin(NewCommunityID(), 0, 24), pin(NewCommunityID(), 0, 24), pin(NewCommunityID(), 10, 12)},
+			pin(community, 11, 13),
+			ErrTooManyPins,
+		},
+		{
+			"full windows don't overlap each other",
+			[]*CommunityPin{pin(NewCommunityID(), 0, 24), pin(NewCommunityID(), 0, 24), pin(NewCommunityID(), 8, 10), pin(NewCommunityID(), 14, 16)},
+			pin(community, 10, 14),
+			nil,
+		},
+		{
+			"back to back",
+			[]*CommunityPin{pin(NewCommunityID(), 0, 24), pin(NewCommunityID(), 0, 24), pin(NewCommunityID(), 0, 10)},
+			pin(community, 10, 20),
+			nil,
+		},
+		{"same community twice", []*CommunityPin{pin(community, 0, 12)}, pin(community, 6, 18), ErrPinOverlap},
+		{"same community later", []*CommunityPin{pin(community, 0, 12)}, pin(community, 12, 18), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckPinCapacity(tt.scheduled, tt.pin); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
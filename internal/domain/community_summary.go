@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CommunitySummaryWindow is the period a summary counts recent events over.
+const CommunitySummaryWindow = 24 * time.Hour
+
+// CommunitySummary is the listing data of a community that would otherwise be computed
+// per request: its member count and recent activity. summaries are rolled up in the
+// background, so they lag by up to the rollup interval.
+type CommunitySummary struct {
+	communityID    CommunityID
+	memberCount    int
+	eventsLastDay  int64
+	lastActivityAt *time.Time
+	refreshedAt    time.Time
+}
+
+// ReconstructCommunitySummary rebuilds a summary from persistence.
+func ReconstructCommunitySummary(
+	communityID CommunityID,
+	memberCount int,
+	eventsLastDay int64,
+	lastActivityAt *time.Time,
+	refreshedAt time.Time,
+) *CommunitySummary {
+	return &CommunitySummary{
+		communityID:    communityID,
+		memberCount:    memberCount,
+		eventsLastDay:  eventsLastDay,
+		lastActivityAt: lastActivityAt,
+		refreshedAt:    refreshedAt,
+	}
+}
+
+// CommunityID returns the summarized community.
+func (s *CommunitySummary) CommunityID() CommunityID {
+	return s.communityID
+}
+
+// MemberCount returns how many users joined the community, not counting its creator.
+func (s *CommunitySummary) MemberCount() int {
+	return s.memberCount
+}
+
+// EventsLastDay returns how many events occurred in the CommunitySummaryWindow before the rollup.
+func (s *CommunitySummary) EventsLastDay() int64 {
+	return s.eventsLastDay
+}
+
+// LastActivityAt returns when the latest event seen by a rollup occurred, nil if none was.
+func (s *CommunitySummary) LastActivityAt() *time.Time {
+	return s.lastActivityAt
+}
+
+// RefreshedAt returns when the summary was rolled up.
+func (s *CommunitySummary) RefreshedAt() time.Time {
+	return s.refreshedAt
+}
+
+// CommunitySummaryRepository defines persistence for community summaries.
+type CommunitySummaryRepository interface {
+	// Refresh rolls up the summary of every community as of now, in one statement,
+	// and returns how many summaries it wrote.
+	Refresh(ctx context.Context, now time.Time) (int64, error)
+
+	// FindByCommunityIDs returns the summaries of the communities that have one.
+	FindByCommunityIDs(ctx context.Context, ids []CommunityID) (map[CommunityID]*CommunitySummary, error)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewCommunity_DefaultsToPublic(t *testing.T) {
+	slug, _ := NewSlug("golang")
+
+	community, err := NewCommunity(SystemClock, slug, "Go", NewUserID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if community.Visibility() != VisibilityPublic {
+		t.Errorf("expected public, got %s", community.Visibility())
+	}
+	if community.IsPrivate() {
+		t.Error("expected a new community not to be private")
+	}
+}
+
+func TestCommunity_SetVisibility(t *testing.T) {
+	slug, _ := NewSlug("golang")
+	community, _ := NewCommunity(SystemClock, slug, "Go", NewUserID())
+
+	if err := community.SetVisibility(VisibilityPrivate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !community.IsPrivate() {
+		t.Error("expected the community to be private")
+	}
+
+	if err := community.SetVisibility("hidden"); !errors.Is(err, ErrInvalidVisibility) {
+		t.Errorf("expected ErrInvalidVisibility, got %v", err)
+	}
+	if community.Visibility() != VisibilityPrivate {
+		t.Errorf("expected an invalid visibility to be ignored, got %s", community.Visibility())
+	}
+}
+
+func TestCommunityVisibility_IsListed(t *testing.T) {
+	tests := []struct {
+		visibility CommunityVisibility
+		want       bool
+	}{
+		{VisibilityPublic, true},
+		{VisibilityUnlisted, false},
+		{VisibilityPrivate, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.visibility.IsListed(); got != tt.want {
+			t.Errorf("%s.IsListed() = %v, want %v", tt.visibility, got, tt.want)
+		}
+	}
+}
+
+func TestSortByLeaderboardRank(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	community := func(name string, momentum float64, age time.Duration) *Community {
+		slug, _ := NewSlug(name)
+		c, _ := NewCommunity(FixedClock(created.Add(-age)), slug, name, NewUserID())
+		c.UpdateMomentum(NewMomentum(momentum))
+		return c
+	}
+
+	hot := community("hot", 50, 0)
+	popular := community("popular", 20, 0)
+	older := community("older", 20, time.Hour)
+	newer := community("newer", 20, 0)
+	quiet := community("quiet", 5, 0)
+	twin := community("twin", 20, 0)
+
+	members := map[CommunityID]int{popular.ID(): 30, older.ID(): 10, newer.ID(): 10, twin.ID(): 10}
+
+	communities := []*Community{quiet, newer, twin, older, popular, hot}
+	SortByLeaderboardRank(communities, members)
+
+	// newer and twin tie on everything, their ids decide
+	first, second := newer, twin
+	if twin.ID().String() < newer.ID().String() {
+		first, second = twin, newer
+	}
+	want := []*Community{hot, popular, older, first, second, quiet}
+	for i, c := range communities {
+		if c != want[i] {
+			t.Errorf("position %d: got %s, want %s", i, c.Name(), want[i].Name())
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// domain event names, also used on the wire between instances.
+const (
+	EventCommunityCreated     = "community.created"
+	EventCommunityDeactivated = "community.deactivated"
+	EventMomentumCalculated   = "momentum.calculated"
+	EventSpikeDetected        = "momentum.spike_detected"
+)
+
+// DomainEvent is something that happened in the domain, published by use cases for
+// whoever needs to react to it. not to be confused with ActivityEvent, which users send.
+type DomainEvent interface {
+	EventName() string
+}
+
+// CommunityCreated is published once a new community is saved.
+type CommunityCreated struct {
+	CommunityID CommunityID
+	Slug        string
+	Visibility  CommunityVisibility
+	CreatedAt   time.Time
+}
+
+// CommunityDeactivated is published once a community is deactivated by its creator.
+type CommunityDeactivated struct {
+	CommunityID   CommunityID
+	DeactivatedAt time.Time
+}
+
+// MomentumCalculated is published when a community's momentum is stored, recalculated or decayed.
+type MomentumCalculated struct {
+	CommunityID  CommunityID
+	OldMomentum  float64
+	NewMomentum  float64
+	Decayed      bool
+	CalculatedAt time.Time
+}
+
+// SpikeDetected is published for a momentum spike to notify, after the spike cooldown.
+type SpikeDetected struct {
+	Spike MomentumSpike
+}
+
+func (CommunityCreated) EventName() string     { return EventCommunityCreated }
+func (CommunityDeactivated) EventName() string { return EventCommunityDeactivated }
+func (MomentumCalculated) EventName() string   { return EventMomentumCalculated }
+func (SpikeDetected) EventName() string        { return EventSpikeDetected }
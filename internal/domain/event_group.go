@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// MaxEventGroupSize is the most events one group can hold. groups are for the few
+	// events of a single client action, not bulk imports.
+	MaxEventGroupSize = 10
+
+	// IdempotencyKeyTTL is how long an idempotency key is remembered. a retry after
+	// that is saved again.
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key: must be 1 to 255 printable ASCII characters")
+	ErrEventGroupSize        = errors.New("invalid event group: must have 1 to 10 events")
+
+	// ErrDuplicateEventGroup is returned when an idempotency key was already used.
+	ErrDuplicateEventGroup = errors.New("event group already ingested with this idempotency key")
+)
+
+// IdempotencyKey identifies a client request, so a retry isn't saved twice.
+type IdempotencyKey struct {
+	value string
+}
+
+// NewIdempotencyKey validates a client-chosen idempotency key, usually a uuid.
+func NewIdempotencyKey(s string) (IdempotencyKey, error) {
+	if s == "" || len(s) > maxIdempotencyKeyLength {
+		return IdempotencyKey{}, ErrInvalidIdempotencyKey
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x21 || s[i] > 0x7e {
+			return IdempotencyKey{}, ErrInvalidIdempotencyKey
+		}
+	}
+	return IdempotencyKey{value: s}, nil
+}
+
+// String returns the key.
+func (k IdempotencyKey) String() string {
+	return k.value
+}
+
+// EventGroupRepository remembers the idempotency keys of saved event groups.
+// keys are scoped to who sent the group, like "user:<id>".
+type EventGroupRepository interface {
+	// FindGroup returns the ids of the events saved under the key since the given time.
+	// returns ErrNotFound if the key wasn't used since then.
+	FindGroup(ctx context.Context, scope string, key IdempotencyKey, since time.Time) ([]EventID, error)
+
+	// SaveGroup records the key for the events, meant to run in the transaction saving them.
+	// if the key was used since the given time, the ids saved then are returned with
+	// ErrDuplicateEventGroup.
+	SaveGroup(ctx context.Context, scope string, key IdempotencyKey, ids []EventID, since time.Time) ([]EventID, error)
+}
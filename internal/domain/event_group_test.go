@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"uuid", "0b6e8f4e-3c1a-4f7e-9a59-2b1f6d0c8e11", nil},
+		{"any printable ascii", "checkout#42/retry:1", nil},
+		{"longest", strings.Repeat("k", 255), nil},
+		{"empty", "", ErrInvalidIdempotencyKey},
+		{"too long", strings.Repeat("k", 256), ErrInvalidIdempotencyKey},
+		{"space", "two words", ErrInvalidIdempotencyKey},
+		{"non ascii", "clé", ErrInvalidIdempotencyKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewIdempotencyKey(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && key.String() != tt.input {
+				t.Errorf("expected %q, got %q", tt.input, key.String())
+			}
+		})
+	}
+}
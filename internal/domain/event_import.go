@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxEventImportErrors bounds how many rejected rows an import records, so a file
+// full of bad rows doesn't grow its record without limit. every rejection is counted.
+const MaxEventImportErrors = 20
+
+// EventImportFormat is the file format of a bulk event import.
+type EventImportFormat string
+
+const (
+	// ImportFormatCSV is a header row followed by one event per row.
+	ImportFormatCSV EventImportFormat = "csv"
+
+	// ImportFormatNDJSON is one JSON object per line.
+	ImportFormatNDJSON EventImportFormat = "ndjson"
+)
+
+var ErrInvalidEventImportFormat = errors.New("import format must be csv or ndjson")
+
+// ParseEventImportFormat validates an import format.
+func ParseEventImportFormat(s string) (EventImportFormat, error) {
+	switch f := EventImportFormat(s); f {
+	case ImportFormatCSV, ImportFormatNDJSON:
+		return f, nil
+	default:
+		return "", ErrInvalidEventImportFormat
+	}
+}
+
+// String returns the format name.
+func (f EventImportFormat) String() string {
+	return string(f)
+}
+
+// EventImportStatus is where an import is in its lifecycle.
+type EventImportStatus string
+
+const (
+	ImportQueued    EventImportStatus = "queued"
+	ImportRunning   EventImportStatus = "running"
+	ImportCompleted EventImportStatus = "completed"
+	ImportFailed    EventImportStatus = "failed"
+)
+
+// String returns the status name.
+func (s EventImportStatus) String() string {
+	return string(s)
+}
+
+// IsFinished reports whether the import stopped, successfully or not.
+func (s EventImportStatus) IsFinished() bool {
+	return s == ImportCompleted || s == ImportFailed
+}
+
+// EventImportRowError is a row an import skipped, and why.
+type EventImportRowError struct {
+	// Line is the 1-based line of the row in the file, the CSV header being line 1.
+	Line   int
+	Reason string
+}
+
+// EventImport tracks a bulk import of historical events into one community, so the
+// uploader can follow its progress. rows are validated one by one: bad rows are skipped
+// and counted, the rest are imported.
+type EventImport struct {
+	id          uuid.UUID
+	communityID CommunityID
+	requestedBy UserID
+	format      EventImportFormat
+	status      EventImportStatus
+	imported    int64
+	rejected    int64
+	rowErrors   []EventImportRowError
+	err         string
+	createdAt   time.Time
+	updatedAt   time.Time
+	finishedAt  *time.Time
+}
+
+// NewEventImport creates a queued import.
+func NewEventImport(clock Clock, communityID CommunityID, requestedBy UserID, format EventImportFormat) (*EventImport, error) {
+	if communityID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if _, err := ParseEventImportFormat(format.String()); err != nil {
+		return nil, err
+	}
+	now := clockOrSystem(clock).Now()
+	return &EventImport{
+		id:          uuid.New(),
+		communityID: communityID,
+		requestedBy: requestedBy,
+		format:      format,
+		status:      ImportQueued,
+		createdAt:   now,
+		updatedAt:   now,
+	}, nil
+}
+
+// ReconstructEventImport rebuilds an import from persistence.
+func ReconstructEventImport(
+	id uuid.UUID,
+	communityID CommunityID,
+	requestedBy UserID,
+	format EventImportFormat,
+	status EventImportStatus,
+	imported, rejected int64,
+	rowErrors []EventImportRowError,
+	err string,
+	createdAt, updatedAt time.Time,
+	finishedAt *time.Time,
+) *EventImport {
+	return &EventImport{
+		id:          id,
+		communityID: communityID,
+		requestedBy: requestedBy,
+		format:      format,
+		status:      status,
+		imported:    imported,
+		rejected:    rejected,
+		rowErrors:   rowErrors,
+		err:         err,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
+		finishedAt:  finishedAt,
+	}
+}
+
+func (i *EventImport) ID() uuid.UUID                    { return i.id }
+func (i *EventImport) CommunityID() CommunityID         { return i.communityID }
+func (i *EventImport) RequestedBy() UserID              { return i.requestedBy }
+func (i *EventImport) Format() EventImportFormat        { return i.format }
+func (i *EventImport) Status() EventImportStatus        { return i.status }
+func (i *EventImport) Imported() int64                  { return i.imported }
+func (i *EventImport) Rejected() int64                  { return i.rejected }
+func (i *EventImport) RowErrors() []EventImportRowError { return i.rowErrors }
+func (i *EventImport) CreatedAt() time.Time             { return i.createdAt }
+func (i *EventImport) UpdatedAt() time.Time             { return i.updatedAt }
+func (i *EventImport) FinishedAt() *time.Time           { return i.finishedAt }
+
+// Err returns why the import failed as a whole, empty unless it failed.
+func (i *EventImport) Err() string { return i.err }
+
+// Start marks a queued import as running.
+func (i *EventImport) Start(clock Clock) {
+	i.status = ImportRunning
+	i.updatedAt = clockOrSystem(clock).Now()
+}
+
+// AddImported counts events written to the community.
+func (i *EventImport) AddImported(clock Clock, n int) {
+	i.imported += int64(n)
+	i.updatedAt = clockOrSystem(clock).Now()
+}
+
+// Reject counts a skipped row, keeping the reason of the first MaxEventImportErrors.
+func (i *EventImport) Reject(line int, cause error) {
+	i.rejected++
+	if len(i.rowErrors) < MaxEventImportErrors {
+		i.rowErrors = append(i.rowErrors, EventImportRowError{Line: line, Reason: cause.Error()})
+	}
+}
+
+// Finish ends the import: completed when cause is nil, failed otherwise.
+// events imported before a failure stay imported.
+func (i *EventImport) Finish(clock Clock, cause error) {
+	now := clockOrSystem(clock).Now()
+	i.status = ImportCompleted
+	if cause != nil {
+		i.status = ImportFailed
+		i.err = cause.Error()
+	}
+	i.updatedAt = now
+	i.finishedAt = &now
+}
+
+// EventImportRepository persists bulk import records.
+type EventImportRepository interface {
+	// Save persists an import (insert or update).
+	Save(ctx context.Context, imp *EventImport) error
+
+	// FindByID returns an import, ErrNotFound if there's none.
+	FindByID(ctx context.Context, id uuid.UUID) (*EventImport, error)
+}
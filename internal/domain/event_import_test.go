@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEventImport_Lifecycle(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	imp, err := NewEventImport(FixedClock(start), NewCommunityID(), NewUserID(), ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("NewEventImport: %v", err)
+	}
+	if imp.Status() != ImportQueued {
+		t.Fatalf("status = %s, want queued", imp.Status())
+	}
+
+	imp.Start(FixedClock(start))
+	imp.AddImported(FixedClock(start), 500)
+	for line := 2; line < 2+MaxEventImportErrors+5; line++ {
+		imp.Reject(line, fmt.Errorf("bad row"))
+	}
+
+	end := start.Add(time.Minute)
+	imp.Finish(FixedClock(end), nil)
+
+	if imp.Status() != ImportCompleted || !imp.Status().IsFinished() {
+		t.Errorf("status = %s, want completed", imp.Status())
+	}
+	if imp.Imported() != 500 || imp.Rejected() != MaxEventImportErrors+5 {
+		t.Errorf("imported %d, rejected %d, want 500, %d", imp.Imported(), imp.Rejected(), MaxEventImportErrors+5)
+	}
+	if len(imp.RowErrors()) != MaxEventImportErrors {
+		t.Errorf("kept %d row errors, want %d", len(imp.RowErrors()), MaxEventImportErrors)
+	}
+	if imp.FinishedAt() == nil || !imp.FinishedAt().Equal(end) {
+		t.Errorf("finished at %v, want %v", imp.FinishedAt(), end)
+	}
+}
+
+func TestEventImport_FinishWithError(t *testing.T) {
+	imp, _ := NewEventImport(SystemClock, NewCommunityID(), NewUserID(), ImportFormatNDJSON)
+	imp.Finish(SystemClock, errors.New("storage unavailable"))
+
+	if imp.Status() != ImportFailed || imp.Err() != "storage unavailable" {
+		t.Errorf("status %s, err %q, want failed with the cause", imp.Status(), imp.Err())
+	}
+}
+
+func TestParseEventImportFormat(t *testing.T) {
+	if _, err := ParseEventImportFormat("xlsx"); !errors.Is(err, ErrInvalidEventImportFormat) {
+		t.Errorf("expected ErrInvalidEventImportFormat, got %v", err)
+	}
+	if f, err := ParseEventImportFormat("ndjson"); err != nil || f != ImportFormatNDJSON {
+		t.Errorf("ParseEventImportFormat(ndjson) = %q, %v", f, err)
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"slices"
+)
 
 // EventType represents the type of activity event.
 // defined as enum to enforce valid values at compile time.
@@ -18,6 +21,17 @@ const (
 
 var ErrInvalidEventType = errors.New("invalid event type")
 
+// eventTypes lists every valid event type in a stable order.
+var eventTypes = []EventType{
+	EventTypeView,
+	EventTypeJoin,
+	EventTypeLeave,
+	EventTypePost,
+	EventTypeComment,
+	EventTypeReaction,
+	EventTypeShare,
+}
+
 // validEventTypes for quick lookup.
 var validEventTypes = map[EventType]bool{
 	EventTypeView:     true,
@@ -29,6 +43,11 @@ var validEventTypes = map[EventType]bool{
 	EventTypeShare:    true,
 }
 
+// EventTypes returns every valid event type, in a stable order.
+func EventTypes() []EventType {
+	return slices.Clone(eventTypes)
+}
+
 // ParseEventType validates and returns an EventType from a string.
 func ParseEventType(s string) (EventType, error) {
 	et := EventType(s)
@@ -83,3 +102,24 @@ func (e EventType) DefaultWeight() Weight {
 func (e EventType) IsPositiveSignal() bool {
 	return e != EventTypeLeave
 }
+
+// MetadataField describes an event metadata key Pulse reads.
+// events can carry any other keys too, they're stored as-is.
+type MetadataField struct {
+	Key         string
+	Type        string // JSON type
+	Pattern     string // regular expression the value must match, empty for any
+	Description string
+}
+
+// MetadataFields returns the metadata keys Pulse reads, the same for every event type.
+func MetadataFields() []MetadataField {
+	return []MetadataField{
+		{
+			Key:         MetadataRegionKey,
+			Type:        "string",
+			Pattern:     RegionPattern,
+			Description: "where the event came from, counted toward that region's leaderboard; lowercased",
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+)
+
+var ErrNoEventWeights = errors.New("at least one event weight is required")
+
+// CommunityEventWeights overrides the default weight of event types in one community.
+// events sent without a weight get the community's weight for their type, falling back
+// to EventType.DefaultWeight for the types it doesn't override.
+type CommunityEventWeights struct {
+	communityID CommunityID
+	weights     map[EventType]Weight
+	updatedAt   time.Time
+}
+
+// NewCommunityEventWeights creates validated weight overrides for a community.
+// every event type must be valid and every weight within MinWeight and MaxWeight.
+func NewCommunityEventWeights(clock Clock, communityID CommunityID, weights map[EventType]float64) (*CommunityEventWeights, error) {
+	if communityID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if len(weights) == 0 {
+		return nil, ErrNoEventWeights
+	}
+
+	validated := make(map[EventType]Weight, len(weights))
+	for et, v := range weights {
+		if !et.IsValid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidEventType, et)
+		}
+		w, err := NewWeight(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", et, err)
+		}
+		validated[et] = w
+	}
+
+	return &CommunityEventWeights{
+		communityID: communityID,
+		weights:     validated,
+		updatedAt:   clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructCommunityEventWeights rebuilds weight overrides from persistence.
+// bypasses validation for trusted data from database.
+func ReconstructCommunityEventWeights(communityID CommunityID, weights map[EventType]Weight, updatedAt time.Time) *CommunityEventWeights {
+	return &CommunityEventWeights{
+		communityID: communityID,
+		weights:     weights,
+		updatedAt:   updatedAt,
+	}
+}
+
+func (w *CommunityEventWeights) CommunityID() CommunityID { return w.communityID }
+func (w *CommunityEventWeights) UpdatedAt() time.Time     { return w.updatedAt }
+
+// Weights returns the overridden weights by event type.
+func (w *CommunityEventWeights) Weights() map[EventType]Weight {
+	return maps.Clone(w.weights)
+}
+
+// WeightFor returns the weight of an event type in the community: its override,
+// or the type's default weight.
+func (w *CommunityEventWeights) WeightFor(et EventType) Weight {
+	if w != nil {
+		if weight, ok := w.weights[et]; ok {
+			return weight
+		}
+	}
+	return et.DefaultWeight()
+}
+
+// Overrides reports whether the community overrides the weight of an event type.
+func (w *CommunityEventWeights) Overrides(et EventType) bool {
+	if w == nil {
+		return false
+	}
+	_, ok := w.weights[et]
+	return ok
+}
+
+// CommunityEventWeightsRepository defines persistence for per-community event weights.
+type CommunityEventWeightsRepository interface {
+	// FindByCommunity retrieves the weight overrides for a community.
+	// returns ErrNotFound if the community uses the default weights.
+	FindByCommunity(ctx context.Context, communityID CommunityID) (*CommunityEventWeights, error)
+
+	// Save replaces a community's overrides.
+	Save(ctx context.Context, weights *CommunityEventWeights) error
+
+	// Delete removes the overrides, reverting the community to the default weights.
+	Delete(ctx context.Context, communityID CommunityID) error
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCommunityEventWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[EventType]float64
+		wantErr error
+	}{
+		{"valid", map[EventType]float64{EventTypePost: 8, EventTypeView: 0.1}, nil},
+		{"empty", map[EventType]float64{}, ErrNoEventWeights},
+		{"unknown type", map[EventType]float64{"upvote": 2}, ErrInvalidEventType},
+		{"too heavy", map[EventType]float64{EventTypePost: 11}, ErrWeightOutOfRange},
+		{"too light", map[EventType]float64{EventTypeView: 0}, ErrWeightOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommunityEventWeights(SystemClock, NewCommunityID(), tt.weights)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCommunityEventWeights_WeightFor(t *testing.T) {
+	weights, err := NewCommunityEventWeights(SystemClock, NewCommunityID(), map[EventType]float64{EventTypePost: 8})
+	if err != nil {
+		t.Fatalf("NewCommunityEventWeights: %v", err)
+	}
+
+	if got := weights.WeightFor(EventTypePost).Value(); got != 8 {
+		t.Errorf("post weight = %v, want the override 8", got)
+	}
+	if got, want := weights.WeightFor(EventTypeShare), EventTypeShare.DefaultWeight(); got != want {
+		t.Errorf("share weight = %v, want the default %v", got.Value(), want.Value())
+	}
+	if !weights.Overrides(EventTypePost) || weights.Overrides(EventTypeShare) {
+		t.Error("expected only post to be overridden")
+	}
+
+	// no overrides at all falls back to the defaults
+	var none *CommunityEventWeights
+	if got, want := none.WeightFor(EventTypeJoin), EventTypeJoin.DefaultWeight(); got != want {
+		t.Errorf("join weight without overrides = %v, want %v", got.Value(), want.Value())
+	}
+}
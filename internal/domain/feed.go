@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// FeedReason is why a community is in a discovery feed.
+type FeedReason string
+
+const (
+	// FeedReasonMover is a community whose activity is growing fastest.
+	FeedReasonMover FeedReason = "mover"
+
+	// FeedReasonNew is a recently created community.
+	FeedReasonNew FeedReason = "new"
+
+	// FeedReasonParticipating is a community the user has been active in.
+	FeedReasonParticipating FeedReason = "participating"
+)
+
+// String returns the reason name.
+func (r FeedReason) String() string {
+	return string(r)
+}
+
+// CommunityVelocity compares a community's weighted activity in the recent window
+// with the window of the same length before it.
+type CommunityVelocity struct {
+	CommunityID CommunityID
+	Recent      float64
+	Previous    float64
+}
+
+// Delta is how much the weighted activity grew, negative when it fell.
+func (v CommunityVelocity) Delta() float64 {
+	return v.Recent - v.Previous
+}
+
+// CommunityParticipation is a user's recent activity in one community.
+type CommunityParticipation struct {
+	CommunityID  CommunityID
+	Events       int64
+	LastActiveAt time.Time
+}
+
+// FeedCandidate is one reason for a community to be in the feed, with its score.
+type FeedCandidate struct {
+	CommunityID CommunityID
+	Reason      FeedReason
+	Score       float64
+}
+
+// FeedEntry is a community in the feed with every reason it's there.
+type FeedEntry struct {
+	CommunityID CommunityID
+	Reasons     []FeedReason
+	Score       float64
+}
+
+// FeedDecay is the weight left of a signal age old: 1 now, halving every halfLife.
+func FeedDecay(age, halfLife time.Duration) float64 {
+	if age <= 0 || halfLife <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// MergeFeed combines candidates into feed entries, highest score first.
+// a community found for several reasons gets the sum of their scores,
+// so one that's new, growing and familiar ranks above one that's only one of those.
+// candidates without a positive score are dropped; limit caps the entries, 0 for all.
+func MergeFeed(candidates []FeedCandidate, limit int) []FeedEntry {
+	index := make(map[CommunityID]int, len(candidates))
+	entries := make([]FeedEntry, 0, len(candidates))
+
+	for _, c := range candidates {
+		if c.Score <= 0 {
+			continue
+		}
+		i, ok := index[c.CommunityID]
+		if !ok {
+			index[c.CommunityID] = len(entries)
+			entries = append(entries, FeedEntry{CommunityID: c.CommunityID})
+			i = len(entries) - 1
+		}
+		entries[i].Score += c.Score
+		entries[i].Reasons = append(entries[i].Reasons, c.Reason)
+	}
+
+	// ties keep candidate order, so the feed is stable between refreshes
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Score > entries[b].Score
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFeedDecay(t *testing.T) {
+	tests := []struct {
+		name string
+		age  time.Duration
+		want float64
+	}{
+		{"now", 0, 1},
+		{"one half-life", 6 * time.Hour, 0.5},
+		{"two half-lives", 12 * time.Hour, 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FeedDecay(tt.age, 6*time.Hour); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMergeFeed(t *testing.T) {
+	a, b, c := NewCommunityID(), NewCommunityID(), NewCommunityID()
+
+	entries := MergeFeed([]FeedCandidate{
+		{CommunityID: a, Reason: FeedReasonMover, Score: 5},
+		{CommunityID: b, Reason: FeedReasonNew, Score: 4},
+		{CommunityID: b, Reason: FeedReasonParticipating, Score: 3},
+		{CommunityID: c, Reason: FeedReasonMover, Score: 0},
+	}, 0)
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].CommunityID != b || entries[0].Score != 7 || len(entries[0].Reasons) != 2 {
+		t.Errorf("expected b first with both reasons summed, got %+v", entries[0])
+	}
+	if entries[1].CommunityID != a {
+		t.Errorf("expected a second, got %+v", entries[1])
+	}
+
+	if limited := MergeFeed([]FeedCandidate{
+		{CommunityID: a, Reason: FeedReasonMover, Score: 1},
+		{CommunityID: b, Reason: FeedReasonMover, Score: 2},
+	}, 1); len(limited) != 1 || limited[0].CommunityID != b {
+		t.Errorf("expected only b, got %+v", limited)
+	}
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobKind is the kind of long-running work a job tracks.
+type JobKind string
+
+const (
+	// JobKindEventImport tracks a bulk event import. the job shares the import's id.
+	JobKindEventImport JobKind = "event_import"
+)
+
+var ErrInvalidJobKind = errors.New("invalid job kind")
+
+// String returns the kind name.
+func (k JobKind) String() string {
+	return string(k)
+}
+
+// JobStatus is where a job is in its lifecycle.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// String returns the status name.
+func (s JobStatus) String() string {
+	return string(s)
+}
+
+// IsFinished reports whether the job stopped, successfully or not.
+func (s JobStatus) IsFinished() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// Job is the status of long-running work done in the background, like an import, so
+// whoever started it can follow it through one endpoint whatever the work is.
+// the work keeps its own detailed record; a job only has what every kind shares.
+type Job struct {
+	id         uuid.UUID
+	kind       JobKind
+	ownerID    *UserID
+	status     JobStatus
+	done       int64
+	total      int64
+	err        string
+	createdAt  time.Time
+	updatedAt  time.Time
+	startedAt  *time.Time
+	finishedAt *time.Time
+}
+
+// NewJob creates a queued job. id is the id of the work it tracks, so the work's
+// caller already knows it. ownerID is nil for work nobody in particular started.
+func NewJob(clock Clock, id uuid.UUID, kind JobKind, ownerID *UserID) (*Job, error) {
+	if id == uuid.Nil {
+		return nil, ErrInvalidInput
+	}
+	if kind == "" {
+		return nil, ErrInvalidJobKind
+	}
+	now := clockOrSystem(clock).Now()
+	return &Job{
+		id:        id,
+		kind:      kind,
+		ownerID:   ownerID,
+		status:    JobQueued,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// ReconstructJob rebuilds a job from persistence.
+func ReconstructJob(
+	id uuid.UUID,
+	kind JobKind,
+	ownerID *UserID,
+	status JobStatus,
+	done, total int64,
+	err string,
+	createdAt, updatedAt time.Time,
+	startedAt, finishedAt *time.Time,
+) *Job {
+	return &Job{
+		id:         id,
+		kind:       kind,
+		ownerID:    ownerID,
+		status:     status,
+		done:       done,
+		total:      total,
+		err:        err,
+		createdAt:  createdAt,
+		updatedAt:  updatedAt,
+		startedAt:  startedAt,
+		finishedAt: finishedAt,
+	}
+}
+
+func (j *Job) ID() uuid.UUID          { return j.id }
+func (j *Job) Kind() JobKind          { return j.kind }
+func (j *Job) OwnerID() *UserID       { return j.ownerID }
+func (j *Job) Status() JobStatus      { return j.status }
+func (j *Job) CreatedAt() time.Time   { return j.createdAt }
+func (j *Job) UpdatedAt() time.Time   { return j.updatedAt }
+func (j *Job) StartedAt() *time.Time  { return j.startedAt }
+func (j *Job) FinishedAt() *time.Time { return j.finishedAt }
+
+// Done returns how many items the job has processed.
+func (j *Job) Done() int64 { return j.done }
+
+// Total returns how many items the job will process, 0 when unknown, like for a streamed file.
+func (j *Job) Total() int64 { return j.total }
+
+// Err returns why the job failed, empty unless it failed.
+func (j *Job) Err() string { return j.err }
+
+// Percent returns the share of the job done, from 0 to 100. nil while the total is unknown.
+func (j *Job) Percent() *float64 {
+	if j.status == JobSucceeded {
+		p := 100.0
+		return &p
+	}
+	if j.total <= 0 {
+		return nil
+	}
+	p := min(float64(j.done)/float64(j.total)*100, 100)
+	return &p
+}
+
+// IsOwnedBy reports whether the user started the job.
+func (j *Job) IsOwnedBy(userID UserID) bool {
+	return j.ownerID != nil && *j.ownerID == userID
+}
+
+// Start marks a queued job as running.
+func (j *Job) Start(clock Clock) {
+	now := clockOrSystem(clock).Now()
+	j.status = JobRunning
+	j.startedAt = &now
+	j.updatedAt = now
+}
+
+// Progress records how much of the job is done. total is 0 when unknown.
+func (j *Job) Progress(clock Clock, done, total int64) {
+	j.done = done
+	j.total = total
+	j.updatedAt = clockOrSystem(clock).Now()
+}
+
+// Finish ends the job: succeeded when cause is nil, failed otherwise.
+func (j *Job) Finish(clock Clock, cause error) {
+	now := clockOrSystem(clock).Now()
+	j.status = JobSucceeded
+	if cause != nil {
+		j.status = JobFailed
+		j.err = cause.Error()
+	}
+	j.updatedAt = now
+	j.finishedAt = &now
+}
+
+// JobRepository persists job statuses.
+type JobRepository interface {
+	// Save persists a job (insert or update).
+	Save(ctx context.Context, job *Job) error
+
+	// FindByID returns a job, ErrNotFound if there's none.
+	FindByID(ctx context.Context, id uuid.UUID) (*Job, error)
+}
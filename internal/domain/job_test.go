@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestJob_Lifecycle(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	owner := NewUserID()
+	job, err := NewJob(FixedClock(start), uuid.New(), JobKindEventImport, &owner)
+	if err != nil {
+		t.Fatalf("NewJob: %v", err)
+	}
+	if job.Status() != JobQueued || job.Percent() != nil {
+		t.Fatalf("status %s, percent %v, want queued with no percent", job.Status(), job.Percent())
+	}
+
+	job.Start(FixedClock(start))
+	job.Progress(FixedClock(start), 250, 1000)
+	if p := job.Percent(); p == nil || *p != 25 {
+		t.Errorf("percent = %v, want 25", p)
+	}
+	job.Progress(FixedClock(start), 1200, 0)
+	if p := job.Percent(); p != nil {
+		t.Errorf("percent = %v, want nil with an unknown total", *p)
+	}
+
+	end := start.Add(time.Minute)
+	job.Finish(FixedClock(end), nil)
+	if job.Status() != JobSucceeded || !job.Status().IsFinished() {
+		t.Errorf("status = %s, want succeeded", job.Status())
+	}
+	if p := job.Percent(); p == nil || *p != 100 {
+		t.Errorf("percent = %v, want 100 once succeeded", p)
+	}
+	if job.StartedAt() == nil || job.FinishedAt() == nil || !job.FinishedAt().Equal(end) {
+		t.Errorf("started %v, finished %v, want both set", job.StartedAt(), job.FinishedAt())
+	}
+	if !job.IsOwnedBy(owner) || job.IsOwnedBy(NewUserID()) {
+		t.Error("expected only the owner to own the job")
+	}
+}
+
+func TestJob_FinishWithError(t *testing.T) {
+	job, _ := NewJob(SystemClock, uuid.New(), JobKindEventImport, nil)
+	job.Finish(SystemClock, errors.New("storage unavailable"))
+
+	if job.Status() != JobFailed || job.Err() != "storage unavailable" {
+		t.Errorf("status %s, err %q, want failed with the cause", job.Status(), job.Err())
+	}
+	if job.IsOwnedBy(NewUserID()) {
+		t.Error("a job without an owner isn't owned by anyone")
+	}
+}
+
+func TestNewJob_Validation(t *testing.T) {
+	if _, err := NewJob(SystemClock, uuid.Nil, JobKindEventImport, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for a nil id, got %v", err)
+	}
+	if _, err := NewJob(SystemClock, uuid.New(), "", nil); !errors.Is(err, ErrInvalidJobKind) {
+		t.Errorf("expected ErrInvalidJobKind, got %v", err)
+	}
+}
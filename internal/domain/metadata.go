@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxMetadataKeys is how many top-level keys an event's metadata can have.
+	MaxMetadataKeys = 32
+
+	// MaxMetadataBytes is how large an event's metadata can be, serialized as JSON.
+	MaxMetadataBytes = 4096
+)
+
+var (
+	ErrMetadataTooManyKeys = errors.New("invalid metadata: at most 32 keys")
+	ErrMetadataTooLarge    = errors.New("invalid metadata: at most 4096 bytes as JSON")
+	ErrMetadataInvalidText = errors.New("invalid metadata: keys and strings must be UTF-8 text without control characters")
+
+	// ErrMetadataKeyMissing and ErrMetadataWrongType are returned by the Metadata getters.
+	ErrMetadataKeyMissing = errors.New("metadata key not found")
+	ErrMetadataWrongType  = errors.New("metadata value has the wrong type")
+)
+
+// Metadata is the free-form data an event carries, as decoded from JSON.
+type Metadata map[string]any
+
+// ValidateMetadata checks metadata against the key and size limits, and rejects keys and
+// strings that aren't text: invalid UTF-8, or control characters other than tabs and
+// newlines in values. nested objects and arrays are checked too.
+func ValidateMetadata(m map[string]any) error {
+	if len(m) > MaxMetadataKeys {
+		return ErrMetadataTooManyKeys
+	}
+	if err := checkMetadataValue(m); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		// unencodable values, like channels or NaN, can't be stored either
+		return ErrMetadataInvalidText
+	}
+	if len(data) > MaxMetadataBytes {
+		return ErrMetadataTooLarge
+	}
+	return nil
+}
+
+func checkMetadataValue(v any) error {
+	switch v := v.(type) {
+	case string:
+		if !isMetadataText(v, true) {
+			return ErrMetadataInvalidText
+		}
+	case map[string]any:
+		for k, nested := range v {
+			if !isMetadataText(k, false) {
+				return ErrMetadataInvalidText
+			}
+			if err := checkMetadataValue(nested); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, nested := range v {
+			if err := checkMetadataValue(nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isMetadataText reports whether s is valid UTF-8 without control characters.
+// values may contain tabs and newlines, keys may not.
+func isMetadataText(s string, value bool) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if value && (r == '\t' || r == '\n' || r == '\r') {
+			continue
+		}
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetString returns a string value.
+func (m Metadata) GetString(key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", ErrMetadataKeyMissing
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", ErrMetadataWrongType
+	}
+	return s, nil
+}
+
+// GetInt returns a whole number value. JSON numbers decode as float64, so any float
+// without a fractional part in int64 range is accepted.
+func (m Metadata) GetInt(key string) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, ErrMetadataKeyMissing
+	}
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, ErrMetadataWrongType
+		}
+		return int64(n), nil
+	case json.Number:
+		i, err := n.Int64()
+		if err != nil {
+			return 0, ErrMetadataWrongType
+		}
+		return i, nil
+	default:
+		return 0, ErrMetadataWrongType
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	tooManyKeys := make(map[string]any, MaxMetadataKeys+1)
+	for i := 0; i <= MaxMetadataKeys; i++ {
+		tooManyKeys[strings.Repeat("k", i+1)] = i
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]any
+		wantErr  error
+	}{
+		{"nil", nil, nil},
+		{"plain values", map[string]any{"source": "web", "count": 3.0, "ok": true}, nil},
+		{"multiline text", map[string]any{"body": "line one\n\tline two\r\n"}, nil},
+		{"unicode text", map[string]any{"título": "¡hola! 👋"}, nil},
+		{"too many keys", tooManyKeys, ErrMetadataTooManyKeys},
+		{"too large", map[string]any{"body": strings.Repeat("a", MaxMetadataBytes)}, ErrMetadataTooLarge},
+		{"invalid utf-8", map[string]any{"body": "\xff\xfe"}, ErrMetadataInvalidText},
+		{"control character", map[string]any{"body": "null\x00byte"}, ErrMetadataInvalidText},
+		{"newline in key", map[string]any{"a\nb": "x"}, ErrMetadataInvalidText},
+		{"nested object", map[string]any{"ref": map[string]any{"id": "\x1b[31m"}}, ErrMetadataInvalidText},
+		{"nested array", map[string]any{"tags": []any{"go", "\x07"}}, ErrMetadataInvalidText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMetadata(tt.metadata)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewActivityEvent_RejectsInvalidMetadata(t *testing.T) {
+	metadata := map[string]any{"body": "bad\x00"}
+	_, err := NewActivityEvent(SystemClock, NewCommunityID(), nil, EventTypePost, DefaultEventWeight(), metadata)
+	if !errors.Is(err, ErrMetadataInvalidText) {
+		t.Errorf("expected ErrMetadataInvalidText, got %v", err)
+	}
+}
+
+func TestMetadata_Getters(t *testing.T) {
+	var decoded Metadata
+	if err := json.Unmarshal([]byte(`{"source":"web","count":42,"ratio":1.5,"big":1e30}`), &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if s, err := decoded.GetString("source"); err != nil || s != "web" {
+		t.Errorf("GetString(source) = %q, %v", s, err)
+	}
+	if _, err := decoded.GetString("count"); !errors.Is(err, ErrMetadataWrongType) {
+		t.Errorf("GetString(count) error = %v, want ErrMetadataWrongType", err)
+	}
+	if _, err := decoded.GetString("missing"); !errors.Is(err, ErrMetadataKeyMissing) {
+		t.Errorf("GetString(missing) error = %v, want ErrMetadataKeyMissing", err)
+	}
+
+	if n, err := decoded.GetInt("count"); err != nil || n != 42 {
+		t.Errorf("GetInt(count) = %d, %v", n, err)
+	}
+	for _, key := range []string{"ratio", "big", "source"} {
+		if _, err := decoded.GetInt(key); !errors.Is(err, ErrMetadataWrongType) {
+			t.Errorf("GetInt(%s) error = %v, want ErrMetadataWrongType", key, err)
+		}
+	}
+	if n, err := (Metadata{"n": int64(-7)}).GetInt("n"); err != nil || n != -7 {
+		t.Errorf("GetInt(int64) = %d, %v", n, err)
+	}
+}
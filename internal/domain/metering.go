@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMeterMetric     = errors.New("metric must be events_ingested, webhooks_delivered or api_calls")
+	ErrInvalidMeterSubject    = errors.New("subject must be community, organization or user")
+	ErrMeteringQuantity       = errors.New("metering quantity must be positive")
+	ErrMeteringPeriodInvalid  = errors.New("metering period must end after it starts")
+	ErrMeteringSubjectIDEmpty = errors.New("metering subject id is required")
+)
+
+// MeterMetric is a billable quantity.
+type MeterMetric string
+
+const (
+	MeterEventsIngested    MeterMetric = "events_ingested"
+	MeterWebhooksDelivered MeterMetric = "webhooks_delivered"
+	MeterAPICalls          MeterMetric = "api_calls"
+)
+
+// ParseMeterMetric validates a metric name.
+func ParseMeterMetric(s string) (MeterMetric, error) {
+	switch metric := MeterMetric(s); metric {
+	case MeterEventsIngested, MeterWebhooksDelivered, MeterAPICalls:
+		return metric, nil
+	default:
+		return "", ErrInvalidMeterMetric
+	}
+}
+
+// String returns the metric name.
+func (m MeterMetric) String() string {
+	return string(m)
+}
+
+// MeterSubject is who a metered quantity is billed to.
+type MeterSubject string
+
+const (
+	MeterSubjectCommunity    MeterSubject = "community"
+	MeterSubjectOrganization MeterSubject = "organization"
+	// MeterSubjectUser is keyed by the user's external (auth provider) id.
+	MeterSubjectUser MeterSubject = "user"
+)
+
+// ParseMeterSubject validates a subject type.
+func ParseMeterSubject(s string) (MeterSubject, error) {
+	switch subject := MeterSubject(s); subject {
+	case MeterSubjectCommunity, MeterSubjectOrganization, MeterSubjectUser:
+		return subject, nil
+	default:
+		return "", ErrInvalidMeterSubject
+	}
+}
+
+// String returns the subject type.
+func (s MeterSubject) String() string {
+	return string(s)
+}
+
+// MeteringRecord is the quantity of one metric used by one subject over a period.
+// records are append-only; billing sums them over whatever range it invoices.
+type MeteringRecord struct {
+	id          uuid.UUID
+	subject     MeterSubject
+	subjectID   string
+	metric      MeterMetric
+	quantity    int64
+	periodStart time.Time
+	periodEnd   time.Time
+	createdAt   time.Time
+}
+
+// NewMeteringRecord creates a metering record for the period [periodStart, periodEnd).
+func NewMeteringRecord(clock Clock, subject MeterSubject, subjectID string, metric MeterMetric, quantity int64, periodStart, periodEnd time.Time) (*MeteringRecord, error) {
+	if _, err := ParseMeterSubject(string(subject)); err != nil {
+		return nil, err
+	}
+	if subjectID == "" {
+		return nil, ErrMeteringSubjectIDEmpty
+	}
+	if _, err := ParseMeterMetric(string(metric)); err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, ErrMeteringQuantity
+	}
+	if !periodEnd.After(periodStart) {
+		return nil, ErrMeteringPeriodInvalid
+	}
+
+	return &MeteringRecord{
+		id:          uuid.New(),
+		subject:     subject,
+		subjectID:   subjectID,
+		metric:      metric,
+		quantity:    quantity,
+		periodStart: periodStart,
+		periodEnd:   periodEnd,
+		createdAt:   clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructMeteringRecord recreates a metering record from stored data.
+func ReconstructMeteringRecord(
+	id uuid.UUID,
+	subject MeterSubject,
+	subjectID string,
+	metric MeterMetric,
+	quantity int64,
+	periodStart, periodEnd, createdAt time.Time,
+) *MeteringRecord {
+	return &MeteringRecord{
+		id:          id,
+		subject:     subject,
+		subjectID:   subjectID,
+		metric:      metric,
+		quantity:    quantity,
+		periodStart: periodStart,
+		periodEnd:   periodEnd,
+		createdAt:   createdAt,
+	}
+}
+
+// Getters
+
+func (r *MeteringRecord) ID() uuid.UUID          { return r.id }
+func (r *MeteringRecord) Subject() MeterSubject  { return r.subject }
+func (r *MeteringRecord) SubjectID() string      { return r.subjectID }
+func (r *MeteringRecord) Metric() MeterMetric    { return r.metric }
+func (r *MeteringRecord) Quantity() int64        { return r.quantity }
+func (r *MeteringRecord) PeriodStart() time.Time { return r.periodStart }
+func (r *MeteringRecord) PeriodEnd() time.Time   { return r.periodEnd }
+func (r *MeteringRecord) CreatedAt() time.Time   { return r.createdAt }
+
+// MeteringRepository defines persistence for metering records.
+type MeteringRepository interface {
+	// SaveBatch inserts records in a single round trip.
+	SaveBatch(ctx context.Context, records []*MeteringRecord) error
+
+	// ListByPeriod returns records whose period starts in [from, to), oldest first.
+	ListByPeriod(ctx context.Context, from, to time.Time) ([]*MeteringRecord, error)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewMeteringRecord(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	record, err := NewMeteringRecord(SystemClock, MeterSubjectCommunity, "c1", MeterEventsIngested, 42, start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Quantity() != 42 || !record.PeriodEnd().Equal(end) {
+		t.Errorf("unexpected record: quantity=%d end=%v", record.Quantity(), record.PeriodEnd())
+	}
+
+	tests := []struct {
+		name     string
+		subject  MeterSubject
+		id       string
+		metric   MeterMetric
+		quantity int64
+		end      time.Time
+		want     error
+	}{
+		{"unknown subject", "team", "c1", MeterAPICalls, 1, end, ErrInvalidMeterSubject},
+		{"empty subject id", MeterSubjectUser, "", MeterAPICalls, 1, end, ErrMeteringSubjectIDEmpty},
+		{"unknown metric", MeterSubjectUser, "u1", "storage", 1, end, ErrInvalidMeterMetric},
+		{"zero quantity", MeterSubjectUser, "u1", MeterAPICalls, 0, end, ErrMeteringQuantity},
+		{"empty period", MeterSubjectUser, "u1", MeterAPICalls, 1, start, ErrMeteringPeriodInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMeteringRecord(SystemClock, tt.subject, tt.id, tt.metric, tt.quantity, start, tt.end)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
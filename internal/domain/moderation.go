@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxModerationReasonLength bounds the reason stored with a sanction.
+const MaxModerationReasonLength = 500
+
+var (
+	ErrInvalidSanctionKind     = errors.New("sanction must be ban or mute")
+	ErrModerationReasonTooLong = errors.New("reason must be at most 500 characters")
+	ErrSanctionDurationInvalid = errors.New("sanction duration must not be negative")
+)
+
+// SanctionKind is what a moderator did to a user in a community.
+type SanctionKind string
+
+const (
+	// SanctionBan rejects the user's events and removes their membership.
+	SanctionBan SanctionKind = "ban"
+	// SanctionMute keeps accepting the user's events but drops them,
+	// so they add nothing to momentum.
+	SanctionMute SanctionKind = "mute"
+)
+
+// ParseSanctionKind validates a sanction name.
+func ParseSanctionKind(s string) (SanctionKind, error) {
+	switch kind := SanctionKind(s); kind {
+	case SanctionBan, SanctionMute:
+		return kind, nil
+	default:
+		return "", ErrInvalidSanctionKind
+	}
+}
+
+// String returns the sanction name.
+func (k SanctionKind) String() string {
+	return string(k)
+}
+
+// CommunitySanction bans or mutes a user in one community, until it expires or is lifted.
+type CommunitySanction struct {
+	communityID CommunityID
+	userID      UserID
+	kind        SanctionKind
+	reason      string
+	issuedBy    UserID
+	createdAt   time.Time
+	expiresAt   *time.Time
+}
+
+// NewCommunitySanction sanctions a user for duration, or until lifted when duration is 0.
+func NewCommunitySanction(
+	clock Clock,
+	communityID CommunityID,
+	userID UserID,
+	kind SanctionKind,
+	reason string,
+	issuedBy UserID,
+	duration time.Duration,
+) (*CommunitySanction, error) {
+	if communityID.IsZero() || userID.IsZero() || issuedBy.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if _, err := ParseSanctionKind(string(kind)); err != nil {
+		return nil, err
+	}
+	if len(reason) > MaxModerationReasonLength {
+		return nil, ErrModerationReasonTooLong
+	}
+	if duration < 0 {
+		return nil, ErrSanctionDurationInvalid
+	}
+
+	now := clockOrSystem(clock).Now()
+	s := &CommunitySanction{
+		communityID: communityID,
+		userID:      userID,
+		kind:        kind,
+		reason:      reason,
+		issuedBy:    issuedBy,
+		createdAt:   now,
+	}
+	if duration > 0 {
+		expiresAt := now.Add(duration)
+		s.expiresAt = &expiresAt
+	}
+	return s, nil
+}
+
+// ReconstructCommunitySanction rebuilds a sanction from persistence.
+func ReconstructCommunitySanction(
+	communityID CommunityID,
+	userID UserID,
+	kind SanctionKind,
+	reason string,
+	issuedBy UserID,
+	createdAt time.Time,
+	expiresAt *time.Time,
+) *CommunitySanction {
+	return &CommunitySanction{
+		communityID: communityID,
+		userID:      userID,
+		kind:        kind,
+		reason:      reason,
+		issuedBy:    issuedBy,
+		createdAt:   createdAt,
+		expiresAt:   expiresAt,
+	}
+}
+
+// Getters
+
+func (s *CommunitySanction) CommunityID() CommunityID { return s.communityID }
+func (s *CommunitySanction) UserID() UserID           { return s.userID }
+func (s *CommunitySanction) Kind() SanctionKind       { return s.kind }
+func (s *CommunitySanction) Reason() string           { return s.reason }
+func (s *CommunitySanction) IssuedBy() UserID         { return s.issuedBy }
+func (s *CommunitySanction) CreatedAt() time.Time     { return s.createdAt }
+func (s *CommunitySanction) ExpiresAt() *time.Time    { return s.expiresAt }
+
+// IsActiveAt reports whether the sanction still applies at t.
+func (s *CommunitySanction) IsActiveAt(t time.Time) bool {
+	return s.expiresAt == nil || t.Before(*s.expiresAt)
+}
+
+// ModerationAction is an entry type in the moderation audit log.
+type ModerationAction string
+
+const (
+	ModerationBan             ModerationAction = "ban"
+	ModerationUnban           ModerationAction = "unban"
+	ModerationMute            ModerationAction = "mute"
+	ModerationUnmute          ModerationAction = "unmute"
+	ModerationAddModerator    ModerationAction = "add_moderator"
+	ModerationRemoveModerator ModerationAction = "remove_moderator"
+)
+
+// String returns the action name.
+func (a ModerationAction) String() string {
+	return string(a)
+}
+
+// ModerationLogEntry records one moderation action, kept after the sanction is lifted.
+type ModerationLogEntry struct {
+	id          uuid.UUID
+	communityID CommunityID
+	actorID     UserID
+	targetID    UserID
+	action      ModerationAction
+	reason      string
+	expiresAt   *time.Time
+	createdAt   time.Time
+}
+
+// NewModerationLogEntry records an action taken now.
+func NewModerationLogEntry(
+	clock Clock,
+	communityID CommunityID,
+	actorID UserID,
+	targetID UserID,
+	action ModerationAction,
+	reason string,
+	expiresAt *time.Time,
+) *ModerationLogEntry {
+	return &ModerationLogEntry{
+		id:          uuid.New(),
+		communityID: communityID,
+		actorID:     actorID,
+		targetID:    targetID,
+		action:      action,
+		reason:      reason,
+		expiresAt:   expiresAt,
+		createdAt:   clockOrSystem(clock).Now(),
+	}
+}
+
+// ReconstructModerationLogEntry rebuilds a log entry from persistence.
+func ReconstructModerationLogEntry(
+	id uuid.UUID,
+	communityID CommunityID,
+	actorID UserID,
+	targetID UserID,
+	action ModerationAction,
+	reason string,
+	expiresAt *time.Time,
+	createdAt time.Time,
+) *ModerationLogEntry {
+	return &ModerationLogEntry{
+		id:          id,
+		communityID: communityID,
+		actorID:     actorID,
+		targetID:    targetID,
+		action:      action,
+		reason:      reason,
+		expiresAt:   expiresAt,
+		createdAt:   createdAt,
+	}
+}
+
+// Getters
+
+func (e *ModerationLogEntry) ID() uuid.UUID            { return e.id }
+func (e *ModerationLogEntry) CommunityID() CommunityID { return e.communityID }
+func (e *ModerationLogEntry) ActorID() UserID          { return e.actorID }
+func (e *ModerationLogEntry) TargetID() UserID         { return e.targetID }
+func (e *ModerationLogEntry) Action() ModerationAction { return e.action }
+func (e *ModerationLogEntry) Reason() string           { return e.reason }
+func (e *ModerationLogEntry) ExpiresAt() *time.Time    { return e.expiresAt }
+func (e *ModerationLogEntry) CreatedAt() time.Time     { return e.createdAt }
+
+// ModerationRepository persists sanctions and the moderation audit log.
+// every change to sanctions is stored together with its log entry.
+type ModerationRepository interface {
+	// Apply stores a sanction, replacing one of the same kind, and logs it.
+	// a ban also removes the user's membership.
+	Apply(ctx context.Context, sanction *CommunitySanction, entry *ModerationLogEntry) error
+
+	// Lift removes a sanction and logs it. returns ErrNotFound if there was none.
+	Lift(ctx context.Context, communityID CommunityID, userID UserID, kind SanctionKind, entry *ModerationLogEntry) error
+
+	// FindActive returns a user's unexpired sanctions in a community.
+	FindActive(ctx context.Context, communityID CommunityID, userID UserID) ([]*CommunitySanction, error)
+
+	// ListActive returns a community's unexpired sanctions, newest first.
+	ListActive(ctx context.Context, communityID CommunityID) ([]*CommunitySanction, error)
+
+	// Log appends an entry for actions that don't change sanctions.
+	Log(ctx context.Context, entry *ModerationLogEntry) error
+
+	// ListLog returns a community's moderation log, newest first.
+	ListLog(ctx context.Context, communityID CommunityID, limit, offset int) ([]*ModerationLogEntry, error)
+}
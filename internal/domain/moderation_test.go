@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewCommunitySanction(t *testing.T) {
+	communityID, userID, moderatorID := NewCommunityID(), NewUserID(), NewUserID()
+
+	tests := []struct {
+		name     string
+		kind     SanctionKind
+		reason   string
+		duration time.Duration
+		wantErr  error
+	}{
+		{"permanent ban", SanctionBan, "spam", 0, nil},
+		{"timed mute", SanctionMute, "", time.Hour, nil},
+		{"unknown kind", "kick", "", 0, ErrInvalidSanctionKind},
+		{"reason too long", SanctionBan, strings.Repeat("x", MaxModerationReasonLength+1), 0, ErrModerationReasonTooLong},
+		{"negative duration", SanctionMute, "", -time.Minute, ErrSanctionDurationInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommunitySanction(SystemClock, communityID, userID, tt.kind, tt.reason, moderatorID, tt.duration)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCommunitySanction_IsActiveAt(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(now)
+
+	mute, err := NewCommunitySanction(clock, NewCommunityID(), NewUserID(), SanctionMute, "", NewUserID(), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mute.IsActiveAt(now.Add(59 * time.Minute)) {
+		t.Error("expected the mute to be active before it expires")
+	}
+	if mute.IsActiveAt(now.Add(time.Hour)) {
+		t.Error("expected the mute to have expired")
+	}
+
+	ban, _ := NewCommunitySanction(clock, NewCommunityID(), NewUserID(), SanctionBan, "", NewUserID(), 0)
+	if ban.ExpiresAt() != nil {
+		t.Error("expected a ban without duration not to expire")
+	}
+	if !ban.IsActiveAt(now.AddDate(10, 0, 0)) {
+		t.Error("expected a ban without duration to stay active")
+	}
+}
+
+func TestParseCommunityRole(t *testing.T) {
+	if role, err := ParseCommunityRole("moderator"); err != nil || role != CommunityRoleModerator {
+		t.Errorf("expected moderator, got %q, %v", role, err)
+	}
+	if _, err := ParseCommunityRole("owner"); !errors.Is(err, ErrInvalidCommunityRole) {
+		t.Errorf("expected ErrInvalidCommunityRole, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // MomentumInput represents the input data for momentum calculation.
 // all data is provided upfront - no side effects or time acquisition inside.
@@ -126,3 +129,32 @@ func CalculateMomentum(input *MomentumInput) MomentumResult {
 func SimpleMomentum(weightedSum, decayFactor float64) Momentum {
 	return NewMomentum(weightedSum * decayFactor)
 }
+
+// MinDecayedMomentum is the score under which decayed momentum drops to zero, so a
+// community without activity eventually leaves the leaderboard instead of fading forever.
+const MinDecayedMomentum = 0.01
+
+// DecayMomentum fades a score analytically for the time since it was calculated, for
+// communities without new events: momentum * e^(-λ·elapsed), where λ = ln 2 / halfLife.
+// a zero halfLife or elapsed time leaves the score as is.
+func DecayMomentum(m Momentum, elapsed, halfLife time.Duration) Momentum {
+	if halfLife <= 0 || elapsed <= 0 {
+		return m
+	}
+	lambda := math.Ln2 / halfLife.Seconds()
+	decayed := m.Value() * math.Exp(-lambda*elapsed.Seconds())
+	if decayed < MinDecayedMomentum {
+		return NewMomentum(0)
+	}
+	return NewMomentum(decayed)
+}
+
+// MomentumPercentile is the share of ranked communities with momentum at or below
+// a community's, from 0 to 100 with one decimal. the top community is at 100.
+// returns 0 for an empty ranking.
+func MomentumPercentile(atOrBelow, ranked int64) float64 {
+	if ranked <= 0 {
+		return 0
+	}
+	return math.Round(float64(atOrBelow)/float64(ranked)*1000) / 10
+}
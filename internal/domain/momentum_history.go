@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MomentumHistoryRetention is how long calculated momentum is kept for history charts.
+// it covers the longest history window.
+const MomentumHistoryRetention = 30 * 24 * time.Hour
+
+var ErrInvalidHistoryWindow = errors.New("invalid history window: must be 1h, 6h, 24h, 7d or 30d")
+
+// MomentumHistoryWindow is how far back a momentum history goes. each window has a fixed
+// bucket size, so a chart gets a few dozen points whichever window it asks for.
+type MomentumHistoryWindow string
+
+const (
+	HistoryWindowHour      MomentumHistoryWindow = "1h"
+	HistoryWindowSixHours  MomentumHistoryWindow = "6h"
+	HistoryWindowDay       MomentumHistoryWindow = "24h"
+	HistoryWindowWeek      MomentumHistoryWindow = "7d"
+	HistoryWindowThirtyDay MomentumHistoryWindow = "30d"
+)
+
+// DefaultHistoryWindow is the window used when none is given.
+const DefaultHistoryWindow = HistoryWindowDay
+
+var historyWindows = map[MomentumHistoryWindow]struct{ duration, bucket time.Duration }{
+	HistoryWindowHour:      {time.Hour, 5 * time.Minute},
+	HistoryWindowSixHours:  {6 * time.Hour, 15 * time.Minute},
+	HistoryWindowDay:       {24 * time.Hour, time.Hour},
+	HistoryWindowWeek:      {7 * 24 * time.Hour, 6 * time.Hour},
+	HistoryWindowThirtyDay: {30 * 24 * time.Hour, 24 * time.Hour},
+}
+
+// ParseMomentumHistoryWindow validates a window, an empty one is DefaultHistoryWindow.
+func ParseMomentumHistoryWindow(s string) (MomentumHistoryWindow, error) {
+	if s == "" {
+		return DefaultHistoryWindow, nil
+	}
+	w := MomentumHistoryWindow(s)
+	if _, ok := historyWindows[w]; !ok {
+		return "", ErrInvalidHistoryWindow
+	}
+	return w, nil
+}
+
+// Duration returns how far back the window goes.
+func (w MomentumHistoryWindow) Duration() time.Duration {
+	return historyWindows[w].duration
+}
+
+// Bucket returns how much time each point of the window covers.
+func (w MomentumHistoryWindow) Bucket() time.Duration {
+	return historyWindows[w].bucket
+}
+
+// String returns the window name.
+func (w MomentumHistoryWindow) String() string {
+	return string(w)
+}
+
+// MomentumBucket summarizes the momentum calculated for a community within one bucket.
+type MomentumBucket struct {
+	Start   time.Time
+	Average float64
+	Min     float64
+	Max     float64
+	Last    float64 // the latest calculation in the bucket
+	Samples int
+}
+
+// MomentumHistoryRepository persists every calculated momentum score.
+type MomentumHistoryRepository interface {
+	// Record stores a community's momentum as calculated at a time.
+	Record(ctx context.Context, communityID CommunityID, momentum Momentum, at time.Time) error
+
+	// Buckets returns a community's momentum since a time, grouped into buckets aligned
+	// to multiples of the bucket size, oldest first. buckets without a calculation are left out.
+	Buckets(ctx context.Context, communityID CommunityID, since time.Time, bucket time.Duration) ([]MomentumBucket, error)
+
+	// DeleteBefore removes the scores calculated before t, returning how many.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
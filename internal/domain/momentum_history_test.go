@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseMomentumHistoryWindow(t *testing.T) {
+	tests := []struct {
+		input      string
+		want       MomentumHistoryWindow
+		wantBucket time.Duration
+		wantErr    error
+	}{
+		{"", HistoryWindowDay, time.Hour, nil},
+		{"1h", HistoryWindowHour, 5 * time.Minute, nil},
+		{"6h", HistoryWindowSixHours, 15 * time.Minute, nil},
+		{"24h", HistoryWindowDay, time.Hour, nil},
+		{"7d", HistoryWindowWeek, 6 * time.Hour, nil},
+		{"30d", HistoryWindowThirtyDay, 24 * time.Hour, nil},
+		{"1d", "", 0, ErrInvalidHistoryWindow},
+		{"90d", "", 0, ErrInvalidHistoryWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMomentumHistoryWindow(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if got.Bucket() != tt.wantBucket {
+				t.Errorf("expected bucket %v, got %v", tt.wantBucket, got.Bucket())
+			}
+		})
+	}
+}
+
+func TestMomentumHistoryRetention_CoversLongestWindow(t *testing.T) {
+	for w := range historyWindows {
+		if w.Duration() > MomentumHistoryRetention {
+			t.Errorf("window %s is longer than the retention", w)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MomentumRunRetention is how long momentum run records are kept.
+const MomentumRunRetention = 30 * 24 * time.Hour
+
+// MaxMomentumRunFailures bounds the failures kept per run, so a cycle where every community
+// fails doesn't produce a record as big as the community list.
+const MaxMomentumRunFailures = 20
+
+var ErrInvalidMomentumRunTrigger = errors.New("momentum run trigger must be schedule, admin, api or cli")
+
+// MomentumRunTrigger is what started a momentum cycle.
+type MomentumRunTrigger string
+
+const (
+	MomentumRunSchedule MomentumRunTrigger = "schedule" // the worker's interval
+	MomentumRunAdmin    MomentumRunTrigger = "admin"    // the admin run endpoint
+	MomentumRunAPI      MomentumRunTrigger = "api"      // the calculate-all endpoint
+	MomentumRunCLI      MomentumRunTrigger = "cli"      // pulsectl recalc
+)
+
+// ParseMomentumRunTrigger validates a trigger name.
+func ParseMomentumRunTrigger(s string) (MomentumRunTrigger, error) {
+	switch trigger := MomentumRunTrigger(s); trigger {
+	case MomentumRunSchedule, MomentumRunAdmin, MomentumRunAPI, MomentumRunCLI:
+		return trigger, nil
+	default:
+		return "", ErrInvalidMomentumRunTrigger
+	}
+}
+
+// String returns the trigger name.
+func (t MomentumRunTrigger) String() string {
+	return string(t)
+}
+
+// MomentumFailure is why one community failed in a momentum cycle.
+type MomentumFailure struct {
+	CommunityID CommunityID
+	Reason      string
+
+	// Transient is set when the next cycle will likely succeed, like after a timeout.
+	// permanent failures, like a missing community, repeat until someone steps in.
+	Transient bool
+}
+
+// MomentumRun records one momentum cycle over every community, so failed cycles can be
+// looked up after the fact instead of only logged.
+type MomentumRun struct {
+	id          uuid.UUID
+	triggeredBy MomentumRunTrigger
+	startedAt   time.Time
+	finishedAt  time.Time
+	processed   int
+	succeeded   int
+	failed      int
+	failures    []MomentumFailure
+	err         string
+}
+
+// StartMomentumRun begins the record of a cycle started now.
+func StartMomentumRun(clock Clock, triggeredBy MomentumRunTrigger) (*MomentumRun, error) {
+	if _, err := ParseMomentumRunTrigger(string(triggeredBy)); err != nil {
+		return nil, err
+	}
+	return &MomentumRun{
+		id:          uuid.New(),
+		triggeredBy: triggeredBy,
+		startedAt:   clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructMomentumRun rebuilds a run from persistence.
+func ReconstructMomentumRun(
+	id uuid.UUID,
+	triggeredBy MomentumRunTrigger,
+	startedAt, finishedAt time.Time,
+	processed, succeeded, failed int,
+	failures []MomentumFailure,
+	err string,
+) *MomentumRun {
+	return &MomentumRun{
+		id:          id,
+		triggeredBy: triggeredBy,
+		startedAt:   startedAt,
+		finishedAt:  finishedAt,
+		processed:   processed,
+		succeeded:   succeeded,
+		failed:      failed,
+		failures:    failures,
+		err:         err,
+	}
+}
+
+// Finish records how the cycle went, keeping the first MaxMomentumRunFailures failures.
+// cause is set when the whole cycle failed, like when communities couldn't be listed.
+func (r *MomentumRun) Finish(clock Clock, processed, succeeded, failed int, failures []MomentumFailure, cause error) {
+	r.finishedAt = clockOrSystem(clock).Now()
+	r.processed = processed
+	r.succeeded = succeeded
+	r.failed = failed
+	if len(failures) > MaxMomentumRunFailures {
+		failures = failures[:MaxMomentumRunFailures]
+	}
+	r.failures = failures
+	if cause != nil {
+		r.err = cause.Error()
+	}
+}
+
+func (r *MomentumRun) ID() uuid.UUID                   { return r.id }
+func (r *MomentumRun) TriggeredBy() MomentumRunTrigger { return r.triggeredBy }
+func (r *MomentumRun) StartedAt() time.Time            { return r.startedAt }
+func (r *MomentumRun) FinishedAt() time.Time           { return r.finishedAt }
+func (r *MomentumRun) Processed() int                  { return r.processed }
+func (r *MomentumRun) Succeeded() int                  { return r.succeeded }
+func (r *MomentumRun) Failed() int                     { return r.failed }
+
+// Failures returns why single communities failed, at most MaxMomentumRunFailures of them.
+// Failed counts every failure.
+func (r *MomentumRun) Failures() []MomentumFailure { return r.failures }
+
+// Err returns why the whole cycle failed, empty when it ran.
+func (r *MomentumRun) Err() string { return r.err }
+
+// Duration returns how long the cycle took.
+func (r *MomentumRun) Duration() time.Duration {
+	return r.finishedAt.Sub(r.startedAt)
+}
+
+// MomentumRunRepository persists the momentum run records.
+type MomentumRunRepository interface {
+	Save(ctx context.Context, run *MomentumRun) error
+
+	// List returns runs, newest first.
+	List(ctx context.Context, limit, offset int) ([]*MomentumRun, error)
+
+	// DeleteBefore removes the runs started before t, returning how many.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartMomentumRun(t *testing.T) {
+	tests := []struct {
+		trigger MomentumRunTrigger
+		wantErr error
+	}{
+		{MomentumRunSchedule, nil},
+		{MomentumRunAdmin, nil},
+		{MomentumRunAPI, nil},
+		{MomentumRunCLI, nil},
+		{"", ErrInvalidMomentumRunTrigger},
+		{"cron", ErrInvalidMomentumRunTrigger},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.trigger), func(t *testing.T) {
+			_, err := StartMomentumRun(SystemClock, tt.trigger)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMomentumRun_Finish(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	run, err := StartMomentumRun(FixedClock(start), MomentumRunSchedule)
+	if err != nil {
+		t.Fatalf("StartMomentumRun: %v", err)
+	}
+
+	run.Finish(FixedClock(start.Add(1500*time.Millisecond)), 10, 8, 2, nil, nil)
+	if run.Duration() != 1500*time.Millisecond {
+		t.Errorf("duration = %s, want 1.5s", run.Duration())
+	}
+	if run.Processed() != 10 || run.Succeeded() != 8 || run.Failed() != 2 {
+		t.Errorf("counts = %d/%d/%d, want 10/8/2", run.Processed(), run.Succeeded(), run.Failed())
+	}
+	if run.Err() != "" {
+		t.Errorf("err = %q, want empty", run.Err())
+	}
+
+	run.Finish(FixedClock(start.Add(time.Second)), 0, 0, 0, nil, errors.New("listing communities: timeout"))
+	if run.Err() != "listing communities: timeout" {
+		t.Errorf("err = %q, want the cause", run.Err())
+	}
+}
+
+func TestMomentumRun_FinishBoundsFailures(t *testing.T) {
+	run, err := StartMomentumRun(SystemClock, MomentumRunSchedule)
+	if err != nil {
+		t.Fatalf("StartMomentumRun: %v", err)
+	}
+
+	failures := make([]MomentumFailure, MaxMomentumRunFailures+5)
+	for i := range failures {
+		failures[i] = MomentumFailure{CommunityID: NewCommunityID(), Reason: "counting events: timeout", Transient: true}
+	}
+	run.Finish(SystemClock, len(failures), 0, len(failures), failures, nil)
+
+	if got := len(run.Failures()); got != MaxMomentumRunFailures {
+		t.Errorf("kept %d failures, want %d", got, MaxMomentumRunFailures)
+	}
+	if run.Failed() != len(failures) {
+		t.Errorf("failed = %d, want every failure counted", run.Failed())
+	}
+	if run.Failures()[0].CommunityID != failures[0].CommunityID {
+		t.Error("expected the first failures to be kept")
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// bounds for per-community momentum overrides.
+// keeps owners from picking windows the event queries can't serve efficiently.
+const (
+	MinMomentumTimeWindow = 5 * time.Minute
+	MaxMomentumTimeWindow = 7 * 24 * time.Hour
+)
+
+var (
+	ErrMomentumTimeWindowOutOfRange = errors.New("time window must be between 5m and 168h")
+	ErrMomentumTimeWindowPrecision  = errors.New("time window must be a whole number of seconds")
+	ErrDecayFactorOutOfRange        = errors.New("decay factor must be greater than 0 and at most 1")
+)
+
+// CommunityMomentumSettings holds optional per-community overrides for momentum calculation.
+// a nil field means "use the global default".
+type CommunityMomentumSettings struct {
+	communityID CommunityID
+	timeWindow  *time.Duration
+	decayFactor *float64
+	updatedAt   time.Time
+}
+
+// NewCommunityMomentumSettings creates validated momentum overrides for a community.
+// pass nil for any value that should fall back to the global default.
+func NewCommunityMomentumSettings(
+	clock Clock,
+	communityID CommunityID,
+	timeWindow *time.Duration,
+	decayFactor *float64,
+) (*CommunityMomentumSettings, error) {
+	if communityID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if timeWindow != nil && (*timeWindow < MinMomentumTimeWindow || *timeWindow > MaxMomentumTimeWindow) {
+		return nil, ErrMomentumTimeWindowOutOfRange
+	}
+	// stored in seconds, a fraction would be dropped on save
+	if timeWindow != nil && *timeWindow%time.Second != 0 {
+		return nil, ErrMomentumTimeWindowPrecision
+	}
+	if decayFactor != nil && (*decayFactor <= 0 || *decayFactor > 1) {
+		return nil, ErrDecayFactorOutOfRange
+	}
+
+	return &CommunityMomentumSettings{
+		communityID: communityID,
+		timeWindow:  timeWindow,
+		decayFactor: decayFactor,
+		updatedAt:   clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructCommunityMomentumSettings rebuilds settings from persistence.
+// bypasses validation for trusted data from database.
+func ReconstructCommunityMomentumSettings(
+	communityID CommunityID,
+	timeWindow *time.Duration,
+	decayFactor *float64,
+	updatedAt time.Time,
+) *CommunityMomentumSettings {
+	return &CommunityMomentumSettings{
+		communityID: communityID,
+		timeWindow:  timeWindow,
+		decayFactor: decayFactor,
+		updatedAt:   updatedAt,
+	}
+}
+
+// Getters
+
+func (s *CommunityMomentumSettings) CommunityID() CommunityID   { return s.communityID }
+func (s *CommunityMomentumSettings) TimeWindow() *time.Duration { return s.timeWindow }
+func (s *CommunityMomentumSettings) DecayFactor() *float64      { return s.decayFactor }
+func (s *CommunityMomentumSettings) UpdatedAt() time.Time       { return s.updatedAt }
+
+// CommunityMomentumSettingsRepository defines persistence for per-community momentum overrides.
+type CommunityMomentumSettingsRepository interface {
+	// FindByCommunity retrieves the overrides for a community.
+	// returns ErrNotFound if the community uses the global defaults.
+	FindByCommunity(ctx context.Context, communityID CommunityID) (*CommunityMomentumSettings, error)
+
+	// Save persists overrides (insert or update).
+	Save(ctx context.Context, settings *CommunityMomentumSettings) error
+
+	// Delete removes overrides, reverting the community to global defaults.
+	Delete(ctx context.Context, communityID CommunityID) error
+}
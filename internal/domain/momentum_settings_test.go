@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewCommunityMomentumSettings(t *testing.T) {
+	duration := func(d time.Duration) *time.Duration { return &d }
+	float := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name        string
+		timeWindow  *time.Duration
+		decayFactor *float64
+		wantErr     error
+	}{
+		{"no overrides", nil, nil, nil},
+		{"valid window", duration(30 * time.Minute), nil, nil},
+		{"minimum window", duration(MinMomentumTimeWindow), nil, nil},
+		{"maximum window", duration(MaxMomentumTimeWindow), nil, nil},
+		{"window too short", duration(time.Minute), nil, ErrMomentumTimeWindowOutOfRange},
+		{"window too long", duration(8 * 24 * time.Hour), nil, ErrMomentumTimeWindowOutOfRange},
+		{"window with fractional seconds", duration(30*time.Minute + 500*time.Millisecond), nil, ErrMomentumTimeWindowPrecision},
+		{"valid decay", nil, float(0.5), nil},
+		{"no decay", nil, float(1.0), nil},
+		{"zero decay", nil, float(0), ErrDecayFactorOutOfRange},
+		{"decay above one", nil, float(1.5), ErrDecayFactorOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommunityMomentumSettings(SystemClock, NewCommunityID(), tt.timeWindow, tt.decayFactor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewCommunityMomentumSettings_ZeroCommunity(t *testing.T) {
+	_, err := NewCommunityMomentumSettings(SystemClock, CommunityID{}, nil, nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
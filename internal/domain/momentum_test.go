@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -171,3 +172,51 @@ func TestSimpleMomentum(t *testing.T) {
 		})
 	}
 }
+
+func TestDecayMomentum(t *testing.T) {
+	tests := []struct {
+		name     string
+		momentum float64
+		elapsed  time.Duration
+		halfLife time.Duration
+		expected float64
+	}{
+		{"one_half_life", 80, 30 * time.Minute, 30 * time.Minute, 40},
+		{"two_half_lives", 80, time.Hour, 30 * time.Minute, 20},
+		{"no_time_passed", 80, 0, 30 * time.Minute, 80},
+		{"disabled", 80, time.Hour, 0, 80},
+		{"fades_to_zero", 0.015, time.Hour, 30 * time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DecayMomentum(NewMomentum(tt.momentum), tt.elapsed, tt.halfLife)
+			if math.Abs(result.Value()-tt.expected) > 1e-9 {
+				t.Errorf("expected %f, got %f", tt.expected, result.Value())
+			}
+		})
+	}
+}
+
+func TestMomentumPercentile(t *testing.T) {
+	tests := []struct {
+		name      string
+		atOrBelow int64
+		ranked    int64
+		expected  float64
+	}{
+		{"top", 50, 50, 100},
+		{"bottom", 1, 50, 2},
+		{"middle", 25, 50, 50},
+		{"rounded", 1, 3, 33.3},
+		{"empty", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MomentumPercentile(tt.atOrBelow, tt.ranked); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
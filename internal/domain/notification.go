@@ -2,19 +2,204 @@ package domain
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
 	"time"
 )
 
+// WebhookPayloadVersion is the payload format a subscription receives.
+// new versions are added alongside the old ones, so existing receivers keep working.
+type WebhookPayloadVersion string
+
+const (
+	// WebhookPayloadV1 is the original flat JSON payload.
+	WebhookPayloadV1 WebhookPayloadVersion = "v1"
+
+	// DefaultWebhookPayloadVersion is used when a subscription doesn't ask for one.
+	DefaultWebhookPayloadVersion = WebhookPayloadV1
+)
+
+var ErrInvalidWebhookPayloadVersion = errors.New("unsupported webhook payload version")
+
+// ParseWebhookPayloadVersion validates a payload version, empty means the default.
+func ParseWebhookPayloadVersion(s string) (WebhookPayloadVersion, error) {
+	switch v := WebhookPayloadVersion(s); v {
+	case "":
+		return DefaultWebhookPayloadVersion, nil
+	case WebhookPayloadV1:
+		return v, nil
+	default:
+		return "", ErrInvalidWebhookPayloadVersion
+	}
+}
+
+// String returns the version name.
+func (v WebhookPayloadVersion) String() string {
+	return string(v)
+}
+
+// WebhookDeliveryMode is how a subscription receives momentum spikes.
+type WebhookDeliveryMode string
+
+const (
+	// WebhookDeliveryImmediate sends one webhook per spike as soon as it's detected.
+	WebhookDeliveryImmediate WebhookDeliveryMode = "immediate"
+
+	// WebhookDeliveryDigest batches the spikes of a window into one webhook.
+	WebhookDeliveryDigest WebhookDeliveryMode = "digest"
+
+	// DefaultWebhookDeliveryMode is used when a subscription doesn't ask for one.
+	DefaultWebhookDeliveryMode = WebhookDeliveryImmediate
+)
+
+var ErrInvalidWebhookDeliveryMode = errors.New("unsupported webhook delivery mode")
+
+// ParseWebhookDeliveryMode validates a delivery mode, empty means the default.
+func ParseWebhookDeliveryMode(s string) (WebhookDeliveryMode, error) {
+	switch m := WebhookDeliveryMode(s); m {
+	case "":
+		return DefaultWebhookDeliveryMode, nil
+	case WebhookDeliveryImmediate, WebhookDeliveryDigest:
+		return m, nil
+	default:
+		return "", ErrInvalidWebhookDeliveryMode
+	}
+}
+
+// String returns the mode name.
+func (m WebhookDeliveryMode) String() string {
+	return string(m)
+}
+
+// NotificationChannel is where a subscription's notifications are delivered.
+type NotificationChannel string
+
+const (
+	// ChannelWebhook posts signed JSON payloads to the subscriber's endpoint.
+	ChannelWebhook NotificationChannel = "webhook"
+
+	// ChannelSlack and ChannelDiscord post a readable message to an incoming webhook url.
+	// they aren't signed, the url itself is the secret.
+	ChannelSlack   NotificationChannel = "slack"
+	ChannelDiscord NotificationChannel = "discord"
+
+	// DefaultNotificationChannel is used when a subscription doesn't ask for one.
+	DefaultNotificationChannel = ChannelWebhook
+)
+
+var ErrInvalidNotificationChannel = errors.New("unsupported notification channel")
+
+// ParseNotificationChannel validates a channel, empty means the default.
+func ParseNotificationChannel(s string) (NotificationChannel, error) {
+	switch c := NotificationChannel(s); c {
+	case "":
+		return DefaultNotificationChannel, nil
+	case ChannelWebhook, ChannelSlack, ChannelDiscord:
+		return c, nil
+	default:
+		return "", ErrInvalidNotificationChannel
+	}
+}
+
+// String returns the channel name.
+func (c NotificationChannel) String() string {
+	return string(c)
+}
+
+// IsSigned reports whether deliveries on the channel carry an X-Pulse-Signature.
+func (c NotificationChannel) IsSigned() bool {
+	return c == ChannelWebhook
+}
+
+// WebhookSubscriptionOrder is how a user's subscriptions are sorted.
+type WebhookSubscriptionOrder string
+
+const (
+	// SubscriptionsNewestFirst sorts by creation, latest first.
+	SubscriptionsNewestFirst WebhookSubscriptionOrder = "newest"
+
+	// SubscriptionsOldestFirst sorts by creation, earliest first.
+	SubscriptionsOldestFirst WebhookSubscriptionOrder = "oldest"
+
+	// SubscriptionsRecentlyUpdated sorts by last change, latest first.
+	SubscriptionsRecentlyUpdated WebhookSubscriptionOrder = "updated"
+)
+
+var ErrInvalidWebhookSubscriptionOrder = errors.New("subscription order must be newest, oldest or updated")
+
+// ParseWebhookSubscriptionOrder validates an order, empty means newest first.
+func ParseWebhookSubscriptionOrder(s string) (WebhookSubscriptionOrder, error) {
+	switch o := WebhookSubscriptionOrder(s); o {
+	case "":
+		return SubscriptionsNewestFirst, nil
+	case SubscriptionsNewestFirst, SubscriptionsOldestFirst, SubscriptionsRecentlyUpdated:
+		return o, nil
+	default:
+		return "", ErrInvalidWebhookSubscriptionOrder
+	}
+}
+
+// WebhookSubscriptionFilter narrows and pages a user's subscriptions.
+type WebhookSubscriptionFilter struct {
+	// CommunityID keeps the subscriptions to one community when set.
+	// a zero id keeps only the global subscriptions.
+	CommunityID *CommunityID
+
+	// IsActive keeps only active or only inactive subscriptions when set.
+	IsActive *bool
+
+	Order  WebhookSubscriptionOrder
+	Limit  int
+	Offset int
+}
+
+var ErrInvalidWebhookProxy = errors.New("webhook proxy must be an http, https or socks5 url with a host")
+
+// ParseWebhookProxy validates a proxy url for webhook deliveries.
+func ParseWebhookProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, ErrInvalidWebhookProxy
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	default:
+		return nil, ErrInvalidWebhookProxy
+	}
+}
+
+// WebhookSecretPrefix marks secrets pulse generates for subscriptions.
+const WebhookSecretPrefix = "whsec_"
+
+// NewWebhookSecret generates a random signing secret for a webhook subscription.
+func NewWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return WebhookSecretPrefix + hex.EncodeToString(secret), nil
+}
+
 // WebhookSubscription represents a user's subscription to community momentum notifications.
+// a global subscription has a zero community id and gets spikes from every public community.
 type WebhookSubscription struct {
-	id          WebhookSubscriptionID
-	userID      UserID
-	communityID CommunityID
-	targetURL   string
-	secret      string
-	isActive    bool
-	createdAt   time.Time
-	updatedAt   time.Time
+	id           WebhookSubscriptionID
+	userID       UserID
+	communityID  CommunityID
+	targetURL    string
+	secret       string
+	version      WebhookPayloadVersion
+	mode         WebhookDeliveryMode
+	channel      NotificationChannel
+	proxyURL     string // empty uses the server's proxy settings
+	pauseWindows []DeliveryPauseWindow
+	isActive     bool
+	createdAt    time.Time
+	updatedAt    time.Time
+	clock        Clock
 }
 
 // WebhookSubscriptionID uniquely identifies a webhook subscription.
@@ -36,12 +221,16 @@ func (id WebhookSubscriptionID) String() string {
 }
 
 // NewWebhookSubscription creates a new webhook subscription.
+// pass a zero communityID for a global subscription.
 func NewWebhookSubscription(
+	clock Clock,
 	id WebhookSubscriptionID,
 	userID UserID,
 	communityID CommunityID,
 	targetURL string,
 	secret string,
+	version WebhookPayloadVersion,
+	mode WebhookDeliveryMode,
 ) (*WebhookSubscription, error) {
 	if targetURL == "" {
 		return nil, ErrInvalidInput
@@ -49,17 +238,73 @@ func NewWebhookSubscription(
 	if secret == "" {
 		return nil, ErrInvalidInput
 	}
+	version, err := ParseWebhookPayloadVersion(version.String())
+	if err != nil {
+		return nil, err
+	}
+	mode, err = ParseWebhookDeliveryMode(mode.String())
+	if err != nil {
+		return nil, err
+	}
 
-	now := time.Now().UTC()
+	clock = clockOrSystem(clock)
+	now := clock.Now()
 	return &WebhookSubscription{
 		id:          id,
 		userID:      userID,
 		communityID: communityID,
 		targetURL:   targetURL,
 		secret:      secret,
+		version:     version,
+		mode:        mode,
+		channel:     ChannelWebhook,
+		isActive:    true,
+		createdAt:   now,
+		updatedAt:   now,
+		clock:       clock,
+	}, nil
+}
+
+// NewChatSubscription creates a subscription posting messages to a slack or discord
+// incoming webhook. chat messages aren't signed, so there's no secret or payload version.
+func NewChatSubscription(
+	clock Clock,
+	id WebhookSubscriptionID,
+	userID UserID,
+	communityID CommunityID,
+	channel NotificationChannel,
+	targetURL string,
+	mode WebhookDeliveryMode,
+) (*WebhookSubscription, error) {
+	if targetURL == "" {
+		return nil, ErrInvalidInput
+	}
+	channel, err := ParseNotificationChannel(channel.String())
+	if err != nil {
+		return nil, err
+	}
+	if channel.IsSigned() {
+		return nil, ErrInvalidNotificationChannel
+	}
+	mode, err = ParseWebhookDeliveryMode(mode.String())
+	if err != nil {
+		return nil, err
+	}
+
+	clock = clockOrSystem(clock)
+	now := clock.Now()
+	return &WebhookSubscription{
+		id:          id,
+		userID:      userID,
+		communityID: communityID,
+		targetURL:   targetURL,
+		version:     DefaultWebhookPayloadVersion,
+		mode:        mode,
+		channel:     channel,
 		isActive:    true,
 		createdAt:   now,
 		updatedAt:   now,
+		clock:       clock,
 	}, nil
 }
 
@@ -71,43 +316,77 @@ func ReconstructWebhookSubscription(
 	communityID CommunityID,
 	targetURL string,
 	secret string,
+	version WebhookPayloadVersion,
+	mode WebhookDeliveryMode,
+	channel NotificationChannel,
+	proxyURL string,
+	pauseWindows []DeliveryPauseWindow,
 	isActive bool,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *WebhookSubscription {
 	return &WebhookSubscription{
-		id:          id,
-		userID:      userID,
-		communityID: communityID,
-		targetURL:   targetURL,
-		secret:      secret,
-		isActive:    isActive,
-		createdAt:   createdAt,
-		updatedAt:   updatedAt,
+		id:           id,
+		userID:       userID,
+		communityID:  communityID,
+		targetURL:    targetURL,
+		secret:       secret,
+		version:      version,
+		mode:         mode,
+		channel:      channel,
+		proxyURL:     proxyURL,
+		pauseWindows: pauseWindows,
+		isActive:     isActive,
+		createdAt:    createdAt,
+		updatedAt:    updatedAt,
+		clock:        SystemClock,
 	}
 }
 
 // Getters
 
-func (s *WebhookSubscription) ID() WebhookSubscriptionID { return s.id }
-func (s *WebhookSubscription) UserID() UserID            { return s.userID }
-func (s *WebhookSubscription) CommunityID() CommunityID  { return s.communityID }
-func (s *WebhookSubscription) TargetURL() string         { return s.targetURL }
-func (s *WebhookSubscription) Secret() string            { return s.secret }
-func (s *WebhookSubscription) IsActive() bool            { return s.isActive }
-func (s *WebhookSubscription) CreatedAt() time.Time      { return s.createdAt }
-func (s *WebhookSubscription) UpdatedAt() time.Time      { return s.updatedAt }
+func (s *WebhookSubscription) ID() WebhookSubscriptionID             { return s.id }
+func (s *WebhookSubscription) UserID() UserID                        { return s.userID }
+func (s *WebhookSubscription) CommunityID() CommunityID              { return s.communityID }
+func (s *WebhookSubscription) TargetURL() string                     { return s.targetURL }
+func (s *WebhookSubscription) Secret() string                        { return s.secret }
+func (s *WebhookSubscription) PayloadVersion() WebhookPayloadVersion { return s.version }
+func (s *WebhookSubscription) DeliveryMode() WebhookDeliveryMode     { return s.mode }
+func (s *WebhookSubscription) Channel() NotificationChannel          { return s.channel }
+func (s *WebhookSubscription) ProxyURL() string                      { return s.proxyURL }
+func (s *WebhookSubscription) PauseWindows() []DeliveryPauseWindow   { return s.pauseWindows }
+func (s *WebhookSubscription) IsActive() bool                        { return s.isActive }
+func (s *WebhookSubscription) CreatedAt() time.Time                  { return s.createdAt }
+func (s *WebhookSubscription) UpdatedAt() time.Time                  { return s.updatedAt }
+
+// IsGlobal reports whether the subscription covers every public community rather than one.
+func (s *WebhookSubscription) IsGlobal() bool {
+	return s.communityID.IsZero()
+}
+
+// SetProxyURL routes the subscription's deliveries through its own proxy, empty to clear it.
+// the webhook worker ignores it unless per-subscription proxies are enabled.
+func (s *WebhookSubscription) SetProxyURL(raw string) error {
+	if raw != "" {
+		if _, err := ParseWebhookProxy(raw); err != nil {
+			return err
+		}
+	}
+	s.proxyURL = raw
+	s.updatedAt = s.clock.Now()
+	return nil
+}
 
 // Deactivate disables the subscription without deleting it.
 func (s *WebhookSubscription) Deactivate() {
 	s.isActive = false
-	s.updatedAt = time.Now().UTC()
+	s.updatedAt = s.clock.Now()
 }
 
 // Activate enables a previously deactivated subscription.
 func (s *WebhookSubscription) Activate() {
 	s.isActive = true
-	s.updatedAt = time.Now().UTC()
+	s.updatedAt = s.clock.Now()
 }
 
 // WebhookSubscriptionRepository defines persistence for webhook subscriptions.
@@ -115,12 +394,26 @@ type WebhookSubscriptionRepository interface {
 	// Save persists a webhook subscription (insert or update).
 	Save(ctx context.Context, sub *WebhookSubscription) error
 
-	// FindByCommunity retrieves all active subscriptions for a community.
+	// FindByCommunity retrieves all active subscriptions for a community,
+	// plus the global ones when the community is public.
 	FindByCommunity(ctx context.Context, communityID CommunityID) ([]*WebhookSubscription, error)
 
+	// FindByID retrieves a subscription, ErrNotFound if it doesn't exist.
+	FindByID(ctx context.Context, id WebhookSubscriptionID) (*WebhookSubscription, error)
+
 	// FindByUser retrieves all subscriptions for a user.
 	FindByUser(ctx context.Context, userID UserID) ([]*WebhookSubscription, error)
 
+	// ListByUser returns one page of a user's subscriptions matching the filter.
+	ListByUser(ctx context.Context, userID UserID, filter WebhookSubscriptionFilter) ([]*WebhookSubscription, error)
+
+	// FindAllByCommunity retrieves every subscription to a community, active or not.
+	// global subscriptions aren't included.
+	FindAllByCommunity(ctx context.Context, communityID CommunityID) ([]*WebhookSubscription, error)
+
+	// FindByOrganization retrieves every subscription to the communities of an organization, active or not.
+	FindByOrganization(ctx context.Context, orgID OrganizationID) ([]*WebhookSubscription, error)
+
 	// Delete removes a subscription.
 	Delete(ctx context.Context, id WebhookSubscriptionID) error
 }
@@ -135,6 +428,15 @@ type MomentumSpike struct {
 	Timestamp     time.Time
 }
 
+// LeaderboardPosition places a community on the leaderboard around a momentum change.
+// ranks are 1-based, highest momentum first.
+type LeaderboardPosition struct {
+	// PreviousRank is where the previous momentum would rank among the current scores.
+	PreviousRank int64
+	NewRank      int64
+	Size         int64
+}
+
 // NotificationService defines the interface for sending momentum notifications.
 // implementations handle the actual delivery mechanism (webhooks, etc).
 type NotificationService interface {
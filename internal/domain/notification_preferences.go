@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxMutedCommunities caps how many communities one user can mute.
+const MaxMutedCommunities = 500
+
+var (
+	ErrInvalidMaxNotificationsPerHour = errors.New("max notifications per hour must be at least 1")
+	ErrTooManyMutedCommunities        = errors.New("at most 500 communities can be muted")
+	ErrInvalidQuietHours              = errors.New("quiet hours need different start and end times as HH:MM")
+	ErrInvalidTimezone                = errors.New("timezone must be an IANA name, e.g. Europe/Madrid")
+)
+
+// QuietHours is a daily span, in the user's timezone, during which no notifications are sent.
+// the span may cross midnight, e.g. 22:00 to 07:00.
+type QuietHours struct {
+	start    int // minutes after midnight, inclusive
+	end      int // minutes after midnight, exclusive
+	location *time.Location
+}
+
+// ParseQuietHours validates quiet hours given as HH:MM times and an IANA timezone.
+// an empty timezone means UTC.
+func ParseQuietHours(start, end, timezone string) (QuietHours, error) {
+	startMinute, err := parseClockMinute(start)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	endMinute, err := parseClockMinute(end)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	if startMinute == endMinute {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return QuietHours{}, ErrInvalidTimezone
+	}
+
+	return QuietHours{start: startMinute, end: endMinute, location: location}, nil
+}
+
+// ReconstructQuietHours rebuilds quiet hours from persistence.
+// an unknown timezone falls back to UTC rather than dropping the preference.
+func ReconstructQuietHours(startMinute, endMinute int, timezone string) QuietHours {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return QuietHours{start: startMinute, end: endMinute, location: location}
+}
+
+func parseClockMinute(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, ErrInvalidQuietHours
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// StartMinute and EndMinute are minutes after midnight.
+func (q QuietHours) StartMinute() int { return q.start }
+func (q QuietHours) EndMinute() int   { return q.end }
+
+// Start returns the start time as HH:MM.
+func (q QuietHours) Start() string { return formatClockMinute(q.start) }
+
+// End returns the end time as HH:MM.
+func (q QuietHours) End() string { return formatClockMinute(q.end) }
+
+// Timezone returns the IANA timezone name.
+func (q QuietHours) Timezone() string { return q.location.String() }
+
+func formatClockMinute(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
+
+// Contains reports whether t falls within the quiet hours.
+func (q QuietHours) Contains(t time.Time) bool {
+	local := t.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	// crosses midnight
+	return minute >= q.start || minute < q.end
+}
+
+// NotificationPreferences limits which webhook notifications reach a user's subscriptions.
+// users without preferences get every notification.
+type NotificationPreferences struct {
+	userID     UserID
+	maxPerHour int // 0 is unlimited
+	muted      []CommunityID
+	quietHours *QuietHours
+	updatedAt  time.Time
+}
+
+// NewNotificationPreferences creates validated preferences for a user.
+// maxPerHour 0 means unlimited, nil quietHours means none.
+// duplicate muted communities are dropped.
+func NewNotificationPreferences(
+	clock Clock,
+	userID UserID,
+	maxPerHour int,
+	muted []CommunityID,
+	quietHours *QuietHours,
+) (*NotificationPreferences, error) {
+	if maxPerHour < 0 {
+		return nil, ErrInvalidMaxNotificationsPerHour
+	}
+
+	seen := make(map[CommunityID]struct{}, len(muted))
+	unique := make([]CommunityID, 0, len(muted))
+	for _, id := range muted {
+		if id.IsZero() {
+			return nil, ErrInvalidInput
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	if len(unique) > MaxMutedCommunities {
+		return nil, ErrTooManyMutedCommunities
+	}
+
+	return &NotificationPreferences{
+		userID:     userID,
+		maxPerHour: maxPerHour,
+		muted:      unique,
+		quietHours: quietHours,
+		updatedAt:  clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructNotificationPreferences rebuilds preferences from persistence.
+// bypasses validation for trusted data from database.
+func ReconstructNotificationPreferences(
+	userID UserID,
+	maxPerHour int,
+	muted []CommunityID,
+	quietHours *QuietHours,
+	updatedAt time.Time,
+) *NotificationPreferences {
+	return &NotificationPreferences{
+		userID:     userID,
+		maxPerHour: maxPerHour,
+		muted:      muted,
+		quietHours: quietHours,
+		updatedAt:  updatedAt,
+	}
+}
+
+// Getters
+
+func (p *NotificationPreferences) UserID() UserID                  { return p.userID }
+func (p *NotificationPreferences) MaxPerHour() int                 { return p.maxPerHour }
+func (p *NotificationPreferences) MutedCommunities() []CommunityID { return p.muted }
+func (p *NotificationPreferences) QuietHours() *QuietHours         { return p.quietHours }
+func (p *NotificationPreferences) UpdatedAt() time.Time            { return p.updatedAt }
+
+// IsMuted reports whether the user muted the community.
+func (p *NotificationPreferences) IsMuted(communityID CommunityID) bool {
+	for _, id := range p.muted {
+		if id == communityID {
+			return true
+		}
+	}
+	return false
+}
+
+// InQuietHours reports whether t falls within the user's quiet hours.
+func (p *NotificationPreferences) InQuietHours(t time.Time) bool {
+	return p.quietHours != nil && p.quietHours.Contains(t)
+}
+
+// NotificationPreferencesRepository defines persistence for notification preferences.
+type NotificationPreferencesRepository interface {
+	// FindByUser retrieves a user's preferences.
+	// returns ErrNotFound if the user never set any.
+	FindByUser(ctx context.Context, userID UserID) (*NotificationPreferences, error)
+
+	// Save persists preferences (insert or update).
+	Save(ctx context.Context, prefs *NotificationPreferences) error
+
+	// Delete removes a user's preferences, so they get every notification again.
+	Delete(ctx context.Context, userID UserID) error
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		timezone string
+		wantErr  error
+	}{
+		{"same day", "13:00", "14:30", "UTC", nil},
+		{"across midnight", "22:00", "07:00", "Europe/Madrid", nil},
+		{"empty timezone is utc", "22:00", "07:00", "", nil},
+		{"same start and end", "08:00", "08:00", "UTC", ErrInvalidQuietHours},
+		{"bad start", "25:00", "07:00", "UTC", ErrInvalidQuietHours},
+		{"bad end", "22:00", "7pm", "UTC", ErrInvalidQuietHours},
+		{"unknown timezone", "22:00", "07:00", "Mars/Olympus", ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuietHours(tt.start, tt.end, tt.timezone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	overnight, err := ParseQuietHours("22:00", "07:00", "America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	afternoon, err := ParseQuietHours("13:00", "14:30", "UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		at    time.Time
+		want  bool
+	}{
+		// new york is utc-4 in june
+		{"late evening local", overnight, time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC), true},
+		{"early morning local", overnight, time.Date(2025, 6, 1, 10, 59, 0, 0, time.UTC), true},
+		{"end is exclusive", overnight, time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), false},
+		{"start is inclusive", overnight, time.Date(2025, 6, 2, 2, 0, 0, 0, time.UTC), true},
+		{"midday local", overnight, time.Date(2025, 6, 1, 16, 0, 0, 0, time.UTC), false},
+		{"inside same day span", afternoon, time.Date(2025, 6, 1, 14, 0, 0, 0, time.UTC), true},
+		{"after same day span", afternoon, time.Date(2025, 6, 1, 14, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(tt.at); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Format(t *testing.T) {
+	q := ReconstructQuietHours(22*60+5, 7*60, "Europe/Madrid")
+	if q.Start() != "22:05" || q.End() != "07:00" || q.Timezone() != "Europe/Madrid" {
+		t.Errorf("got %s-%s %s", q.Start(), q.End(), q.Timezone())
+	}
+}
+
+func TestNewNotificationPreferences(t *testing.T) {
+	a, b := NewCommunityID(), NewCommunityID()
+
+	prefs, err := NewNotificationPreferences(SystemClock, NewUserID(), 10, []CommunityID{a, b, a}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prefs.MutedCommunities()) != 2 {
+		t.Errorf("expected duplicates dropped, got %d muted", len(prefs.MutedCommunities()))
+	}
+	if !prefs.IsMuted(a) || prefs.IsMuted(NewCommunityID()) {
+		t.Error("IsMuted doesn't match the muted communities")
+	}
+	if prefs.InQuietHours(time.Now()) {
+		t.Error("expected no quiet hours")
+	}
+
+	if _, err := NewNotificationPreferences(SystemClock, NewUserID(), -1, nil, nil); !errors.Is(err, ErrInvalidMaxNotificationsPerHour) {
+		t.Errorf("expected ErrInvalidMaxNotificationsPerHour, got %v", err)
+	}
+	if _, err := NewNotificationPreferences(SystemClock, NewUserID(), 0, []CommunityID{{}}, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+
+	many := make([]CommunityID, MaxMutedCommunities+1)
+	for i := range many {
+		many[i] = NewCommunityID()
+	}
+	if _, err := NewNotificationPreferences(SystemClock, NewUserID(), 0, many, nil); !errors.Is(err, ErrTooManyMutedCommunities) {
+		t.Errorf("expected ErrTooManyMutedCommunities, got %v", err)
+	}
+}
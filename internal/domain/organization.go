@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrOrganizationNameEmpty   = errors.New("organization name cannot be empty")
+	ErrOrganizationNameTooLong = errors.New("organization name must be at most 255 characters")
+	ErrOrganizationOwnerEmpty  = errors.New("organization must have an owner")
+	ErrInvalidOrganizationRole = errors.New("role must be one of owner, admin, member")
+	ErrLastOrganizationOwner   = errors.New("organization must keep at least one owner")
+)
+
+// Organization groups communities under one umbrella with shared administration.
+// communities keep their creator; org owners and admins can manage them too.
+type Organization struct {
+	id        OrganizationID
+	slug      Slug
+	name      string
+	createdBy UserID
+	createdAt time.Time
+	updatedAt time.Time
+	clock     Clock
+}
+
+// NewOrganization creates a new Organization.
+// the creator should be added as its first owner.
+func NewOrganization(clock Clock, slug Slug, name string, createdBy UserID) (*Organization, error) {
+	if err := validateOrganizationName(name); err != nil {
+		return nil, err
+	}
+	if createdBy.IsZero() {
+		return nil, ErrOrganizationOwnerEmpty
+	}
+
+	clock = clockOrSystem(clock)
+	now := clock.Now()
+	return &Organization{
+		id:        NewOrganizationID(),
+		slug:      slug,
+		name:      name,
+		createdBy: createdBy,
+		createdAt: now,
+		updatedAt: now,
+		clock:     clock,
+	}, nil
+}
+
+// ReconstructOrganization recreates an Organization from stored data.
+func ReconstructOrganization(
+	id OrganizationID,
+	slug Slug,
+	name string,
+	createdBy UserID,
+	createdAt time.Time,
+	updatedAt time.Time,
+) *Organization {
+	return &Organization{
+		id:        id,
+		slug:      slug,
+		name:      name,
+		createdBy: createdBy,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+		clock:     SystemClock,
+	}
+}
+
+func validateOrganizationName(name string) error {
+	if name == "" {
+		return ErrOrganizationNameEmpty
+	}
+	if len(name) > 255 {
+		return ErrOrganizationNameTooLong
+	}
+	return nil
+}
+
+// Getters
+
+func (o *Organization) ID() OrganizationID   { return o.id }
+func (o *Organization) Slug() Slug           { return o.slug }
+func (o *Organization) Name() string         { return o.name }
+func (o *Organization) CreatedBy() UserID    { return o.createdBy }
+func (o *Organization) CreatedAt() time.Time { return o.createdAt }
+func (o *Organization) UpdatedAt() time.Time { return o.updatedAt }
+
+// Rename changes the organization's display name.
+func (o *Organization) Rename(name string) error {
+	if err := validateOrganizationName(name); err != nil {
+		return err
+	}
+	o.name = name
+	o.updatedAt = o.clock.Now()
+	return nil
+}
+
+// OrganizationRole is what a member may do in an organization.
+type OrganizationRole string
+
+const (
+	// OrganizationRoleOwner can do everything, including managing other owners.
+	OrganizationRoleOwner OrganizationRole = "owner"
+	// OrganizationRoleAdmin manages members, communities and api keys.
+	OrganizationRoleAdmin OrganizationRole = "admin"
+	// OrganizationRoleMember can see the organization and its leaderboard.
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+// ParseOrganizationRole validates a role name.
+func ParseOrganizationRole(s string) (OrganizationRole, error) {
+	switch role := OrganizationRole(s); role {
+	case OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleMember:
+		return role, nil
+	default:
+		return "", ErrInvalidOrganizationRole
+	}
+}
+
+// String returns the role name.
+func (r OrganizationRole) String() string {
+	return string(r)
+}
+
+// CanManage reports whether the role may administer the organization and its communities.
+func (r OrganizationRole) CanManage() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// CanGrant reports whether the role may give another member the target role.
+// only owners can create or demote owners.
+func (r OrganizationRole) CanGrant(target OrganizationRole) bool {
+	if target == OrganizationRoleOwner {
+		return r == OrganizationRoleOwner
+	}
+	return r.CanManage()
+}
+
+// OrganizationMember is a user's membership in an organization.
+type OrganizationMember struct {
+	organizationID OrganizationID
+	userID         UserID
+	role           OrganizationRole
+	joinedAt       time.Time
+}
+
+// NewOrganizationMember creates a membership with the given role.
+func NewOrganizationMember(clock Clock, organizationID OrganizationID, userID UserID, role OrganizationRole) (*OrganizationMember, error) {
+	if organizationID.IsZero() || userID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if _, err := ParseOrganizationRole(string(role)); err != nil {
+		return nil, err
+	}
+
+	return &OrganizationMember{
+		organizationID: organizationID,
+		userID:         userID,
+		role:           role,
+		joinedAt:       clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructOrganizationMember recreates a membership from stored data.
+func ReconstructOrganizationMember(
+	organizationID OrganizationID,
+	userID UserID,
+	role OrganizationRole,
+	joinedAt time.Time,
+) *OrganizationMember {
+	return &OrganizationMember{
+		organizationID: organizationID,
+		userID:         userID,
+		role:           role,
+		joinedAt:       joinedAt,
+	}
+}
+
+// Getters
+
+func (m *OrganizationMember) OrganizationID() OrganizationID { return m.organizationID }
+func (m *OrganizationMember) UserID() UserID                 { return m.userID }
+func (m *OrganizationMember) Role() OrganizationRole         { return m.role }
+func (m *OrganizationMember) JoinedAt() time.Time            { return m.joinedAt }
+
+// ChangeRole sets a new role for the member.
+func (m *OrganizationMember) ChangeRole(role OrganizationRole) error {
+	if _, err := ParseOrganizationRole(string(role)); err != nil {
+		return err
+	}
+	m.role = role
+	return nil
+}
+
+// OrganizationRepository defines persistence for organizations and their members.
+type OrganizationRepository interface {
+	// FindByID retrieves an organization by its ID.
+	FindByID(ctx context.Context, id OrganizationID) (*Organization, error)
+
+	// FindBySlug retrieves an organization by its slug.
+	FindBySlug(ctx context.Context, slug Slug) (*Organization, error)
+
+	// Create persists a new organization together with its first owner.
+	Create(ctx context.Context, org *Organization, owner *OrganizationMember) error
+
+	// Save updates an existing organization.
+	Save(ctx context.Context, org *Organization) error
+
+	// FindMember retrieves a user's membership, or ErrNotFound if they aren't a member.
+	FindMember(ctx context.Context, orgID OrganizationID, userID UserID) (*OrganizationMember, error)
+
+	// ListMembers returns all members, owners first.
+	ListMembers(ctx context.Context, orgID OrganizationID) ([]*OrganizationMember, error)
+
+	// SaveMember inserts a membership or updates its role.
+	SaveMember(ctx context.Context, member *OrganizationMember) error
+
+	// RemoveMember deletes a membership.
+	RemoveMember(ctx context.Context, orgID OrganizationID, userID UserID) error
+
+	// CountOwners counts members with the owner role.
+	CountOwners(ctx context.Context, orgID OrganizationID) (int, error)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewOrganization(t *testing.T) {
+	slug, _ := NewSlug("acme")
+
+	org, err := NewOrganization(SystemClock, slug, "Acme", NewUserID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if org.ID().IsZero() {
+		t.Error("expected an id")
+	}
+
+	if _, err := NewOrganization(SystemClock, slug, "", NewUserID()); !errors.Is(err, ErrOrganizationNameEmpty) {
+		t.Errorf("expected ErrOrganizationNameEmpty, got %v", err)
+	}
+	if _, err := NewOrganization(SystemClock, slug, "Acme", UserID{}); !errors.Is(err, ErrOrganizationOwnerEmpty) {
+		t.Errorf("expected ErrOrganizationOwnerEmpty, got %v", err)
+	}
+}
+
+func TestOrganizationRole_CanGrant(t *testing.T) {
+	tests := []struct {
+		role   OrganizationRole
+		target OrganizationRole
+		want   bool
+	}{
+		{OrganizationRoleOwner, OrganizationRoleOwner, true},
+		{OrganizationRoleOwner, OrganizationRoleMember, true},
+		{OrganizationRoleAdmin, OrganizationRoleAdmin, true},
+		{OrganizationRoleAdmin, OrganizationRoleOwner, false},
+		{OrganizationRoleMember, OrganizationRoleMember, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.CanGrant(tt.target); got != tt.want {
+			t.Errorf("%s.CanGrant(%s) = %v, want %v", tt.role, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestNewOrganizationMember_InvalidRole(t *testing.T) {
+	_, err := NewOrganizationMember(SystemClock, NewOrganizationID(), NewUserID(), "superuser")
+	if !errors.Is(err, ErrInvalidOrganizationRole) {
+		t.Errorf("expected ErrInvalidOrganizationRole, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidUsageScope = errors.New("scope must be community or organization")
+	ErrInvalidQuotaMode  = errors.New("quota mode must be reject or degrade")
+	ErrQuotaNegative     = errors.New("daily event quota must not be negative")
+)
+
+// UsageScope is what usage is counted and limited against.
+type UsageScope string
+
+const (
+	UsageScopeCommunity    UsageScope = "community"
+	UsageScopeOrganization UsageScope = "organization"
+)
+
+// ParseUsageScope validates a scope name.
+func ParseUsageScope(s string) (UsageScope, error) {
+	switch scope := UsageScope(s); scope {
+	case UsageScopeCommunity, UsageScopeOrganization:
+		return scope, nil
+	default:
+		return "", ErrInvalidUsageScope
+	}
+}
+
+// String returns the scope name.
+func (s UsageScope) String() string {
+	return string(s)
+}
+
+// QuotaMode is what happens to events over quota.
+type QuotaMode string
+
+const (
+	// QuotaModeReject refuses events over quota with 429.
+	QuotaModeReject QuotaMode = "reject"
+	// QuotaModeDegrade stores events over quota with the minimum weight,
+	// so they're kept but barely move momentum.
+	QuotaModeDegrade QuotaMode = "degrade"
+)
+
+// ParseQuotaMode validates a quota mode.
+func ParseQuotaMode(s string) (QuotaMode, error) {
+	switch mode := QuotaMode(s); mode {
+	case QuotaModeReject, QuotaModeDegrade:
+		return mode, nil
+	default:
+		return "", ErrInvalidQuotaMode
+	}
+}
+
+// String returns the mode name.
+func (m QuotaMode) String() string {
+	return string(m)
+}
+
+// IngestionQuota overrides the default daily event quota for one community or organization.
+type IngestionQuota struct {
+	scope       UsageScope
+	scopeID     uuid.UUID
+	dailyEvents int64
+	updatedAt   time.Time
+}
+
+// NewIngestionQuota creates a quota override. dailyEvents of 0 means unlimited.
+func NewIngestionQuota(clock Clock, scope UsageScope, scopeID uuid.UUID, dailyEvents int64) (*IngestionQuota, error) {
+	if _, err := ParseUsageScope(string(scope)); err != nil {
+		return nil, err
+	}
+	if scopeID == uuid.Nil {
+		return nil, ErrInvalidInput
+	}
+	if dailyEvents < 0 {
+		return nil, ErrQuotaNegative
+	}
+
+	return &IngestionQuota{
+		scope:       scope,
+		scopeID:     scopeID,
+		dailyEvents: dailyEvents,
+		updatedAt:   clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructIngestionQuota recreates a quota override from stored data.
+func ReconstructIngestionQuota(scope UsageScope, scopeID uuid.UUID, dailyEvents int64, updatedAt time.Time) *IngestionQuota {
+	return &IngestionQuota{
+		scope:       scope,
+		scopeID:     scopeID,
+		dailyEvents: dailyEvents,
+		updatedAt:   updatedAt,
+	}
+}
+
+// Getters
+
+func (q *IngestionQuota) Scope() UsageScope    { return q.scope }
+func (q *IngestionQuota) ScopeID() uuid.UUID   { return q.scopeID }
+func (q *IngestionQuota) DailyEvents() int64   { return q.dailyEvents }
+func (q *IngestionQuota) UpdatedAt() time.Time { return q.updatedAt }
+
+// IngestionQuotaRepository defines persistence for quota overrides.
+type IngestionQuotaRepository interface {
+	// Find returns the override for a scope, or ErrNotFound if it uses the default.
+	Find(ctx context.Context, scope UsageScope, scopeID uuid.UUID) (*IngestionQuota, error)
+
+	// Save inserts or replaces an override.
+	Save(ctx context.Context, quota *IngestionQuota) error
+
+	// Delete removes an override, returning the scope to the default.
+	Delete(ctx context.Context, scope UsageScope, scopeID uuid.UUID) error
+}
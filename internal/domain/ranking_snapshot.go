@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RankingSnapshotSize is how many communities from the top of the leaderboard a snapshot keeps.
+// a community ranked lower is absent from the snapshot, like one that isn't listed at all.
+const RankingSnapshotSize = 100
+
+// RankingSnapshotRetention is how long ranking snapshots are kept, like momentum runs.
+const RankingSnapshotRetention = MomentumRunRetention
+
+// RankedCommunity is a community's place on the leaderboard when a snapshot was taken.
+type RankedCommunity struct {
+	CommunityID CommunityID
+	Rank        int // 1-based
+	Momentum    float64
+}
+
+// RankingSnapshot is the top of the public leaderboard at the end of a momentum cycle,
+// kept to tell how the leaderboard changed since.
+type RankingSnapshot struct {
+	id      uuid.UUID
+	takenAt time.Time
+	entries []RankedCommunity
+}
+
+// NewRankingSnapshot records the leaderboard as it is now, keeping the first
+// RankingSnapshotSize entries. id is the momentum run's, so a cycle finds its snapshot.
+func NewRankingSnapshot(clock Clock, id uuid.UUID, entries []RankedCommunity) (*RankingSnapshot, error) {
+	if id == uuid.Nil {
+		return nil, ErrInvalidInput
+	}
+	if len(entries) > RankingSnapshotSize {
+		entries = entries[:RankingSnapshotSize]
+	}
+	return &RankingSnapshot{
+		id:      id,
+		takenAt: clockOrSystem(clock).Now(),
+		entries: slices.Clone(entries),
+	}, nil
+}
+
+// ReconstructRankingSnapshot rebuilds a snapshot from persistence.
+func ReconstructRankingSnapshot(id uuid.UUID, takenAt time.Time, entries []RankedCommunity) *RankingSnapshot {
+	return &RankingSnapshot{
+		id:      id,
+		takenAt: takenAt,
+		entries: entries,
+	}
+}
+
+func (s *RankingSnapshot) ID() uuid.UUID              { return s.id }
+func (s *RankingSnapshot) TakenAt() time.Time         { return s.takenAt }
+func (s *RankingSnapshot) Entries() []RankedCommunity { return slices.Clone(s.entries) }
+
+// RankingChangeKind is how a community's place changed between two snapshots.
+type RankingChangeKind string
+
+const (
+	RankingEntered RankingChangeKind = "entered" // absent before, ranked now
+	RankingLeft    RankingChangeKind = "left"    // ranked before, absent now
+	RankingMoved   RankingChangeKind = "moved"   // ranked in both, at another rank
+)
+
+// String returns the kind name.
+func (k RankingChangeKind) String() string {
+	return string(k)
+}
+
+// RankingChange is one community's change between two snapshots.
+// ranks and momentum are zero on the side where the community is absent.
+type RankingChange struct {
+	CommunityID      CommunityID
+	Kind             RankingChangeKind
+	PreviousRank     int
+	Rank             int
+	PreviousMomentum float64
+	Momentum         float64
+}
+
+// Movement returns how many places the community climbed, negative when it dropped.
+// zero unless it moved.
+func (c RankingChange) Movement() int {
+	if c.Kind != RankingMoved {
+		return 0
+	}
+	return c.PreviousRank - c.Rank
+}
+
+// ChangesSince compares the snapshot with an earlier one. a community moved when its rank
+// changed by more than minMove places. entered and moved communities come first, by their
+// rank now, then the ones that left, by their rank before.
+func (s *RankingSnapshot) ChangesSince(earlier *RankingSnapshot, minMove int) []RankingChange {
+	before := make(map[CommunityID]RankedCommunity, len(earlier.entries))
+	for _, e := range earlier.entries {
+		before[e.CommunityID] = e
+	}
+
+	changes := []RankingChange{}
+	for _, e := range s.entries {
+		prev, ok := before[e.CommunityID]
+		delete(before, e.CommunityID)
+		if !ok {
+			changes = append(changes, RankingChange{
+				CommunityID: e.CommunityID,
+				Kind:        RankingEntered,
+				Rank:        e.Rank,
+				Momentum:    e.Momentum,
+			})
+			continue
+		}
+		if moved := prev.Rank - e.Rank; moved > minMove || -moved > minMove {
+			changes = append(changes, RankingChange{
+				CommunityID:      e.CommunityID,
+				Kind:             RankingMoved,
+				PreviousRank:     prev.Rank,
+				Rank:             e.Rank,
+				PreviousMomentum: prev.Momentum,
+				Momentum:         e.Momentum,
+			})
+		}
+	}
+
+	var left []RankingChange
+	for _, prev := range before {
+		left = append(left, RankingChange{
+			CommunityID:      prev.CommunityID,
+			Kind:             RankingLeft,
+			PreviousRank:     prev.Rank,
+			PreviousMomentum: prev.Momentum,
+		})
+	}
+	slices.SortFunc(left, func(a, b RankingChange) int { return a.PreviousRank - b.PreviousRank })
+	return append(changes, left...)
+}
+
+// RankingSnapshotRepository persists ranking snapshots.
+type RankingSnapshotRepository interface {
+	Save(ctx context.Context, snapshot *RankingSnapshot) error
+
+	// FindByID returns the snapshot of a momentum run, ErrNotFound if there's none.
+	FindByID(ctx context.Context, id uuid.UUID) (*RankingSnapshot, error)
+
+	// LatestAt returns the last snapshot taken at or before t, ErrNotFound if there's none.
+	LatestAt(ctx context.Context, t time.Time) (*RankingSnapshot, error)
+
+	// DeleteBefore removes the snapshots taken before t, returning how many.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRankingSnapshot_ChangesSince(t *testing.T) {
+	a, b, c, d, e := NewCommunityID(), NewCommunityID(), NewCommunityID(), NewCommunityID(), NewCommunityID()
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	earlier, _ := NewRankingSnapshot(FixedClock(start), uuid.New(), []RankedCommunity{
+		{CommunityID: a, Rank: 1, Momentum: 90},
+		{CommunityID: b, Rank: 2, Momentum: 80},
+		{CommunityID: c, Rank: 3, Momentum: 70},
+		{CommunityID: d, Rank: 4, Momentum: 60},
+	})
+	later, _ := NewRankingSnapshot(FixedClock(start.Add(time.Hour)), uuid.New(), []RankedCommunity{
+		{CommunityID: d, Rank: 1, Momentum: 120}, // up 3
+		{CommunityID: e, Rank: 2, Momentum: 100}, // new
+		{CommunityID: a, Rank: 3, Momentum: 85},  // down 2
+		{CommunityID: b, Rank: 4, Momentum: 75},  // down 2
+	})
+
+	tests := []struct {
+		name    string
+		minMove int
+		want    []RankingChange
+	}{
+		{
+			name:    "every move",
+			minMove: 0,
+			want: []RankingChange{
+				{CommunityID: d, Kind: RankingMoved, PreviousRank: 4, Rank: 1, PreviousMomentum: 60, Momentum: 120},
+				{CommunityID: e, Kind: RankingEntered, Rank: 2, Momentum: 100},
+				{CommunityID: a, Kind: RankingMoved, PreviousRank: 1, Rank: 3, PreviousMomentum: 90, Momentum: 85},
+				{CommunityID: b, Kind: RankingMoved, PreviousRank: 2, Rank: 4, PreviousMomentum: 80, Momentum: 75},
+				{CommunityID: c, Kind: RankingLeft, PreviousRank: 3, PreviousMomentum: 70},
+			},
+		},
+		{
+			name:    "moves of more than two places",
+			minMove: 2,
+			want: []RankingChange{
+				{CommunityID: d, Kind: RankingMoved, PreviousRank: 4, Rank: 1, PreviousMomentum: 60, Momentum: 120},
+				{CommunityID: e, Kind: RankingEntered, Rank: 2, Momentum: 100},
+				{CommunityID: c, Kind: RankingLeft, PreviousRank: 3, PreviousMomentum: 70},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := later.ChangesSince(earlier, tt.minMove)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d changes, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("change %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if moved := later.ChangesSince(earlier, 0)[0].Movement(); moved != 3 {
+		t.Errorf("movement = %d, want 3", moved)
+	}
+}
+
+func TestNewRankingSnapshot(t *testing.T) {
+	if _, err := NewRankingSnapshot(SystemClock, uuid.Nil, nil); err != ErrInvalidInput {
+		t.Errorf("expected ErrInvalidInput for a nil id, got %v", err)
+	}
+
+	entries := make([]RankedCommunity, RankingSnapshotSize+10)
+	for i := range entries {
+		entries[i] = RankedCommunity{CommunityID: NewCommunityID(), Rank: i + 1}
+	}
+	snapshot, err := NewRankingSnapshot(SystemClock, uuid.New(), entries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(snapshot.Entries()); got != RankingSnapshotSize {
+		t.Errorf("kept %d entries, want %d", got, RankingSnapshotSize)
+	}
+
+	var none RankingSnapshot
+	if changes := snapshot.ChangesSince(&none, 0); len(changes) != RankingSnapshotSize {
+		t.Errorf("got %d changes against an empty snapshot, want every entry entered", len(changes))
+	}
+}
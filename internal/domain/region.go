@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// MetadataRegionKey is the event metadata key a region is carried under.
+const MetadataRegionKey = "region"
+
+// RegionPattern matches the regions NewRegion accepts, before they're lowercased.
+const RegionPattern = "^[A-Za-z0-9-]{2,32}$"
+
+// Region is where an event came from, used for regional leaderboards.
+// lowercase letters, numbers and hyphens, 2-32 chars (e.g. "eu", "us-east").
+type Region struct {
+	value string
+}
+
+var (
+	ErrRegionTooShort = errors.New("region must be at least 2 characters")
+	ErrRegionTooLong  = errors.New("region must be at most 32 characters")
+	ErrRegionInvalid  = errors.New("region must contain only letters, numbers, and hyphens")
+)
+
+// NewRegion creates a Region from a string, lowercasing it.
+func NewRegion(s string) (Region, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return Region{}, ErrRegionTooShort
+	}
+	if len(s) > 32 {
+		return Region{}, ErrRegionTooLong
+	}
+
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return Region{}, ErrRegionInvalid
+		}
+	}
+
+	return Region{value: s}, nil
+}
+
+// String returns the string representation of the Region.
+func (r Region) String() string {
+	return r.value
+}
+
+// IsZero returns true for an event without a region.
+func (r Region) IsZero() bool {
+	return r.value == ""
+}
+
+// RegionalScore is a community's momentum from the events of one region.
+type RegionalScore struct {
+	CommunityID CommunityID
+	Momentum    float64
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"short code", "eu", "eu", nil},
+		{"lowercased", " US-East ", "us-east", nil},
+		{"too short", "e", "", ErrRegionTooShort},
+		{"too long", "a-region-name-well-over-the-limit", "", ErrRegionTooLong},
+		{"invalid characters", "eu_west", "", ErrRegionInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, err := NewRegion(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if region.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, region.String())
+			}
+		})
+	}
+}
+
+func TestActivityEvent_Region(t *testing.T) {
+	event, err := NewActivityEvent(SystemClock, NewCommunityID(), nil, EventTypeView, DefaultEventWeight(), map[string]any{
+		MetadataRegionKey: "eu",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Region().String() != "eu" {
+		t.Errorf("expected region eu, got %q", event.Region().String())
+	}
+
+	event, _ = NewActivityEvent(SystemClock, NewCommunityID(), nil, EventTypeView, DefaultEventWeight(), map[string]any{
+		MetadataRegionKey: 42,
+	})
+	if !event.Region().IsZero() {
+		t.Errorf("expected no region for a non-string value, got %q", event.Region().String())
+	}
+}
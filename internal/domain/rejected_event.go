@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidValidationMode = errors.New("validation mode must be strict or deferred")
+	ErrInvalidRejectReason   = errors.New("unknown reject reason")
+)
+
+// ValidationMode is when ingested events are checked against the database.
+type ValidationMode string
+
+const (
+	// ValidationStrict checks that the community and user exist before accepting an event.
+	ValidationStrict ValidationMode = "strict"
+	// ValidationDeferred accepts events that are well formed and leaves the existence
+	// checks to the ingestion worker, which quarantines the events that fail them.
+	ValidationDeferred ValidationMode = "deferred"
+)
+
+// ParseValidationMode validates a validation mode.
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch mode := ValidationMode(s); mode {
+	case ValidationStrict, ValidationDeferred:
+		return mode, nil
+	default:
+		return "", ErrInvalidValidationMode
+	}
+}
+
+// String returns the mode name.
+func (m ValidationMode) String() string {
+	return string(m)
+}
+
+// RejectReason is why the ingestion worker refused an event the api accepted.
+type RejectReason string
+
+const (
+	RejectCommunityNotFound RejectReason = "community_not_found"
+	RejectCommunityInactive RejectReason = "community_inactive"
+	RejectUserNotFound      RejectReason = "user_not_found"
+
+	// RejectStale is an event that waited in the ingestion buffer past its deadline. the client
+	// has likely given up and retried it, so storing it as well would count it twice.
+	RejectStale RejectReason = "stale"
+)
+
+// ParseRejectReason validates a reject reason.
+func ParseRejectReason(s string) (RejectReason, error) {
+	switch reason := RejectReason(s); reason {
+	case RejectCommunityNotFound, RejectCommunityInactive, RejectUserNotFound, RejectStale:
+		return reason, nil
+	default:
+		return "", ErrInvalidRejectReason
+	}
+}
+
+// String returns the reason name.
+func (r RejectReason) String() string {
+	return string(r)
+}
+
+// RejectedEvent is a quarantined event that failed deferred validation or went stale.
+// the event is kept as received, so it can be inspected or replayed once fixed.
+type RejectedEvent struct {
+	event      *ActivityEvent
+	reason     RejectReason
+	rejectedAt time.Time
+}
+
+// NewRejectedEvent quarantines an event for the given reason.
+func NewRejectedEvent(clock Clock, event *ActivityEvent, reason RejectReason) *RejectedEvent {
+	return &RejectedEvent{
+		event:      event,
+		reason:     reason,
+		rejectedAt: clockOrSystem(clock).Now(),
+	}
+}
+
+// ReconstructRejectedEvent recreates a RejectedEvent from stored data.
+func ReconstructRejectedEvent(event *ActivityEvent, reason RejectReason, rejectedAt time.Time) *RejectedEvent {
+	return &RejectedEvent{
+		event:      event,
+		reason:     reason,
+		rejectedAt: rejectedAt,
+	}
+}
+
+// Getters
+
+func (r *RejectedEvent) Event() *ActivityEvent { return r.event }
+func (r *RejectedEvent) Reason() RejectReason  { return r.reason }
+func (r *RejectedEvent) RejectedAt() time.Time { return r.rejectedAt }
+
+// RejectedEventRepository persists the ingestion quarantine.
+type RejectedEventRepository interface {
+	// SaveBatch stores rejected events in a single round trip.
+	SaveBatch(ctx context.Context, rejected []*RejectedEvent) error
+
+	// List returns rejected events, newest first.
+	List(ctx context.Context, limit, offset int) ([]*RejectedEvent, error)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseValidationMode(t *testing.T) {
+	for _, s := range []string{"strict", "deferred"} {
+		if mode, err := ParseValidationMode(s); err != nil || mode.String() != s {
+			t.Errorf("ParseValidationMode(%q) = %q, %v", s, mode, err)
+		}
+	}
+	if _, err := ParseValidationMode("lazy"); !errors.Is(err, ErrInvalidValidationMode) {
+		t.Errorf("expected ErrInvalidValidationMode, got %v", err)
+	}
+}
+
+func TestParseRejectReason(t *testing.T) {
+	for _, s := range []string{"community_not_found", "community_inactive", "user_not_found", "stale"} {
+		if reason, err := ParseRejectReason(s); err != nil || reason.String() != s {
+			t.Errorf("ParseRejectReason(%q) = %q, %v", s, reason, err)
+		}
+	}
+	if _, err := ParseRejectReason("spam"); !errors.Is(err, ErrInvalidRejectReason) {
+		t.Errorf("expected ErrInvalidRejectReason, got %v", err)
+	}
+}
+
+func TestNewRejectedEvent(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event, err := NewActivityEventWithDefaultWeight(FixedClock(now.Add(-time.Second)), NewCommunityID(), nil, EventTypeView, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rejected := NewRejectedEvent(FixedClock(now), event, RejectCommunityNotFound)
+	if rejected.Event() != event {
+		t.Error("expected the rejected event to keep the original event")
+	}
+	if rejected.Reason() != RejectCommunityNotFound {
+		t.Errorf("expected reason community_not_found, got %s", rejected.Reason())
+	}
+	if !rejected.RejectedAt().Equal(now) {
+		t.Errorf("expected rejected at %v, got %v", now, rejected.RejectedAt())
+	}
+}
@@ -11,17 +11,29 @@ type UserRepository interface {
 	// FindByID retrieves a user by their internal ID.
 	FindByID(ctx context.Context, id UserID) (*User, error)
 
+	// FindByIDs retrieves multiple users by their internal IDs.
+	// maintains the order of the input IDs, missing users are skipped.
+	FindByIDs(ctx context.Context, ids []UserID) ([]*User, error)
+
 	// FindByExternalID retrieves a user by their external auth provider ID.
 	FindByExternalID(ctx context.Context, externalID string) (*User, error)
 
-	// FindByUsername retrieves a user by their username.
+	// FindByUsername retrieves a user by their username, ignoring case.
 	FindByUsername(ctx context.Context, username Username) (*User, error)
 
 	// Save persists a user (insert or update).
 	Save(ctx context.Context, user *User) error
 
+	// Create inserts a new user.
+	// returns ErrAlreadyExists if the external id or the username, in any casing, is taken.
+	Create(ctx context.Context, user *User) error
+
 	// Exists checks if a user with the given ID exists.
 	Exists(ctx context.Context, id UserID) (bool, error)
+
+	// ExistsBatch checks which of the given users exist, in one round trip.
+	// every input ID is a key of the result.
+	ExistsBatch(ctx context.Context, ids []UserID) (map[UserID]bool, error)
 }
 
 // CommunityRepository defines the interface for community persistence.
@@ -42,10 +54,18 @@ type CommunityRepository interface {
 	// Exists checks if a community with the given ID exists.
 	Exists(ctx context.Context, id CommunityID) (bool, error)
 
-	// ListByMomentum returns active communities ordered by momentum.
+	// ListByMomentum returns active communities ordered by momentum, whatever their visibility.
 	// limit controls max results, offset for pagination.
 	ListByMomentum(ctx context.Context, limit, offset int) ([]*Community, error)
 
+	// ListPublicByMomentum returns active public communities ordered by momentum.
+	// this is the public leaderboard; unlisted and private communities are left out.
+	ListPublicByMomentum(ctx context.Context, limit, offset int) ([]*Community, error)
+
+	// ListByOrganization returns an organization's active communities ordered by momentum.
+	// private communities are left out.
+	ListByOrganization(ctx context.Context, orgID OrganizationID, limit, offset int) ([]*Community, error)
+
 	// UpdateMomentum updates just the momentum fields for a community.
 	// more efficient than full save for background jobs.
 	UpdateMomentum(ctx context.Context, id CommunityID, momentum Momentum) error
@@ -61,9 +81,10 @@ type ActivityEventRepository interface {
 	// more efficient than individual saves for bulk operations.
 	SaveBatch(ctx context.Context, events []*ActivityEvent) error
 
-	// FindByCommunity retrieves events for a community within a time window.
-	// ordered by created_at descending (newest first).
-	FindByCommunity(ctx context.Context, communityID CommunityID, since time.Time, limit int) ([]*ActivityEvent, error)
+	// FindByCommunity retrieves events for a community that occurred since the given time,
+	// restricted to the given event types, or of any type when types is empty.
+	// ordered by occurred_at descending (newest first).
+	FindByCommunity(ctx context.Context, communityID CommunityID, since time.Time, types []EventType, limit int) ([]*ActivityEvent, error)
 
 	// FindByUser retrieves events generated by a user.
 	// ordered by created_at descending (newest first).
@@ -75,4 +96,8 @@ type ActivityEventRepository interface {
 	// SumWeightsByCommunity calculates the total weighted momentum contribution
 	// for a community within a time window.
 	SumWeightsByCommunity(ctx context.Context, communityID CommunityID, since time.Time) (float64, error)
+
+	// DeleteBefore removes up to limit events created before the cutoff.
+	// returns how many were deleted, so callers can loop until it returns 0.
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
 }
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNameReserved is returned for a slug or username on the reserved list.
+var ErrNameReserved = errors.New("name is reserved")
+
+// defaultReservedNames can never be claimed as a slug or username: names that read like
+// pulse itself or its routes, and a short profanity list. operators add their own on top.
+var defaultReservedNames = []string{
+	// pulse and its routes
+	"admin", "administrator", "api", "app", "auth", "docs", "health", "help", "login", "logout",
+	"me", "metrics", "moderator", "official", "pulse", "ready", "root", "security", "settings",
+	"signin", "signup", "staff", "status", "statusz", "support", "system", "www",
+	// profanity
+	"asshole", "bastard", "bitch", "cunt", "fuck", "motherfucker", "nigger", "shit", "slut", "whore",
+}
+
+var defaultReserved = NewReservedNames(defaultReservedNames...)
+
+// ReservedNames is a blocklist of slugs and usernames.
+// names are compared ignoring case, hyphens and underscores, so "Ad_Min" is "admin".
+type ReservedNames struct {
+	names map[string]struct{}
+}
+
+// NewReservedNames creates a blocklist from the given names. blank names are ignored.
+func NewReservedNames(names ...string) ReservedNames {
+	r := ReservedNames{names: make(map[string]struct{}, len(names))}
+	for _, name := range names {
+		if key := reservedKey(name); key != "" {
+			r.names[key] = struct{}{}
+		}
+	}
+	return r
+}
+
+// DefaultReservedNames returns the built-in blocklist that NewSlug and NewUsername enforce.
+func DefaultReservedNames() ReservedNames {
+	return NewReservedNames(defaultReservedNames...)
+}
+
+// With returns a blocklist holding these names and the extra ones.
+func (r ReservedNames) With(extra ...string) ReservedNames {
+	merged := NewReservedNames(extra...)
+	for key := range r.names {
+		merged.names[key] = struct{}{}
+	}
+	return merged
+}
+
+// Contains reports whether the name is reserved.
+func (r ReservedNames) Contains(name string) bool {
+	_, ok := r.names[reservedKey(name)]
+	return ok
+}
+
+// Len returns the number of reserved names.
+func (r ReservedNames) Len() int {
+	return len(r.names)
+}
+
+// reservedKey is the form names are compared in.
+func reservedKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("-", "", "_", "").Replace(name)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReservedNames_Contains(t *testing.T) {
+	reserved := NewReservedNames("admin", " Billing ", "")
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"admin", true},
+		{"ADMIN", true},
+		{"ad-min", true},
+		{"Ad_Min", true},
+		{"billing", true},
+		{"admins", false},
+		{"my-admin", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reserved.Contains(tt.name); got != tt.want {
+				t.Errorf("Contains(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if reserved.Len() != 2 {
+		t.Errorf("expected blank names ignored, got %d names", reserved.Len())
+	}
+}
+
+func TestReservedNames_With(t *testing.T) {
+	base := NewReservedNames("admin")
+	merged := base.With("acme")
+
+	if !merged.Contains("admin") || !merged.Contains("acme") {
+		t.Error("expected both the base and extra names")
+	}
+	if base.Contains("acme") {
+		t.Error("expected the base list unchanged")
+	}
+}
+
+func TestNewSlugAndUsername_RejectReserved(t *testing.T) {
+	if _, err := NewSlug("api"); !errors.Is(err, ErrNameReserved) {
+		t.Errorf("expected ErrNameReserved for slug, got %v", err)
+	}
+	if _, err := NewUsername("Admin"); !errors.Is(err, ErrNameReserved) {
+		t.Errorf("expected ErrNameReserved for username, got %v", err)
+	}
+	if _, err := ParseUsername("Admin"); err != nil {
+		t.Errorf("expected lookups to accept reserved usernames, got %v", err)
+	}
+	if _, err := NewSlug("api-fans"); err != nil {
+		t.Errorf("expected names containing a reserved word to pass, got %v", err)
+	}
+}
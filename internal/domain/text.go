@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrDescriptionTooLong = errors.New("description must be at most 2000 characters")
+	ErrBioTooLong         = errors.New("bio must be at most 500 characters")
+	ErrDisplayNameTooLong = errors.New("display name must be at most 100 characters")
+)
+
+// TextRules says how a free-text field is cleaned before it's stored.
+// frontends render these fields, so what's stored must be safe to render as is.
+type TextRules struct {
+	// MaxLength is the most characters the cleaned text may have, 0 for no limit.
+	MaxLength int
+
+	// Markdown strips raw HTML and unsafe link targets, for fields rendered as markdown.
+	// markdown syntax itself is kept.
+	Markdown bool
+
+	// ErrTooLong is returned when the cleaned text is over MaxLength.
+	ErrTooLong error
+}
+
+// DescriptionRules apply to community descriptions.
+var DescriptionRules = TextRules{MaxLength: 2000, Markdown: true, ErrTooLong: ErrDescriptionTooLong}
+
+// BioRules apply to user bios.
+var BioRules = TextRules{MaxLength: 500, Markdown: true, ErrTooLong: ErrBioTooLong}
+
+// DisplayNameRules apply to user display names, shown as plain text.
+var DisplayNameRules = TextRules{MaxLength: 100, ErrTooLong: ErrDisplayNameTooLong}
+
+var (
+	// html tags and comments, "a < b" isn't one
+	htmlTagPattern     = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^>]*>`)
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*$`)
+
+	// markdown link and image targets: [text](target) and [ref]: target
+	inlineLinkPattern    = regexp.MustCompile(`\]\(\s*<?([^)\s>]*)`)
+	referenceLinkPattern = regexp.MustCompile(`(?m)^(\s*\[[^\]]+\]:\s*)<?(\S+?)>?(\s|$)`)
+)
+
+// Clean returns the text with invalid UTF-8 and control characters removed, line endings
+// normalized and surrounding whitespace trimmed, then markup stripped when the rules say so.
+func (r TextRules) Clean(s string) (string, error) {
+	s = strings.ToValidUTF8(s, "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.Map(func(c rune) rune {
+		if c == '\n' || c == '\t' {
+			return c
+		}
+		// also drops bidi overrides and zero-width characters used to disguise text
+		if unicode.IsControl(c) || unicode.Is(unicode.Cf, c) {
+			return -1
+		}
+		return c
+	}, s)
+
+	if r.Markdown {
+		s = sanitizeMarkdown(s)
+	}
+	s = strings.TrimSpace(s)
+
+	if r.MaxLength > 0 && utf8.RuneCountInString(s) > r.MaxLength {
+		return "", r.ErrTooLong
+	}
+	return s, nil
+}
+
+// sanitizeMarkdown removes raw HTML, which markdown renderers pass through, and
+// replaces link targets with a scheme that runs code, like javascript:, with "#".
+func sanitizeMarkdown(s string) string {
+	// repeat until stable, so removing one tag can't assemble another
+	for {
+		cleaned := htmlTagPattern.ReplaceAllString(s, "")
+		cleaned = htmlCommentPattern.ReplaceAllString(cleaned, "")
+		if cleaned == s {
+			break
+		}
+		s = cleaned
+	}
+
+	s = inlineLinkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		target := inlineLinkPattern.FindStringSubmatch(m)[1]
+		if isSafeLinkTarget(target) {
+			return m
+		}
+		return strings.Replace(m, target, "#", 1)
+	})
+	s = referenceLinkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := referenceLinkPattern.FindStringSubmatch(m)
+		if isSafeLinkTarget(parts[2]) {
+			return m
+		}
+		return parts[1] + "#" + parts[3]
+	})
+	return s
+}
+
+// isSafeLinkTarget allows relative links, anchors, http(s) and mailto.
+func isSafeLinkTarget(target string) bool {
+	// only what comes before a path, query or fragment can be a scheme
+	prefix := target
+	if end := strings.IndexAny(target, "/?#"); end >= 0 {
+		prefix = target[:end]
+	}
+	// renderers decode entities in link targets, javascript&#58; is javascript:
+	if strings.ContainsRune(prefix, '&') {
+		return false
+	}
+	colon := strings.IndexByte(prefix, ':')
+	if colon < 0 {
+		return true
+	}
+	switch strings.ToLower(prefix[:colon]) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
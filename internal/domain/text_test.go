@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextRules_Clean(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "Gophers talking Go", "Gophers talking Go"},
+		{"trims", "  hello \n", "hello"},
+		{"keeps newlines and tabs", "line one\r\nline\ttwo", "line one\nline\ttwo"},
+		{"strips control characters", "bell\a and null\x00", "bell and null"},
+		{"strips bidi overrides", "abc‮def", "abcdef"},
+		{"strips invalid utf-8", "ok\xffok", "okok"},
+		{"strips script tags", "hi<script>alert(1)</script>", "hialert(1)"},
+		{"strips attributes", `<img src=x onerror="alert(1)">pic`, "pic"},
+		{"strips comments", "a<!-- hidden -->b<!-- open", "ab"},
+		{"keeps comparisons", "a < b and c > d", "a < b and c > d"},
+		{"nested tags", "<scr<script>ipt>alert(1)", "ipt>alert(1)"},
+		{"keeps markdown", "**bold** and [docs](https://go.dev)", "**bold** and [docs](https://go.dev)"},
+		{"keeps relative links", "[faq](/faq#top) [x](page?q=a:b)", "[faq](/faq#top) [x](page?q=a:b)"},
+		{"neutralizes javascript links", "[click](javascript:alert(1))", "[click](#))"},
+		{"neutralizes uppercase schemes", "![img](JavaScript:alert(1))", "![img](#))"},
+		{"neutralizes entity schemes", "[x](javascript&#58;alert(1))", "[x](#))"},
+		{"neutralizes data links", "[x](data:text/html;base64,PHN2Zz4=)", "[x](#)"},
+		{"neutralizes reference links", "[x]: javascript:alert(1)", "[x]: #"},
+		{"keeps mailto", "[mail](mailto:team@example.com)", "[mail](mailto:team@example.com)"},
+	}
+
+	rules := TextRules{Markdown: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rules.Clean(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Clean(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextRules_CleanPlainKeepsMarkup(t *testing.T) {
+	got, err := TextRules{}.Clean("<b>bold</b>\x00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "<b>bold</b>" {
+		t.Errorf("expected markup kept without the markdown rule, got %q", got)
+	}
+}
+
+func TestTextRules_MaxLength(t *testing.T) {
+	// counted in characters after cleaning, not bytes
+	if _, err := BioRules.Clean(strings.Repeat("é", 500)); err != nil {
+		t.Errorf("expected 500 characters to fit, got %v", err)
+	}
+	if _, err := BioRules.Clean(strings.Repeat("a", 500) + "<b></b>"); err != nil {
+		t.Errorf("expected stripped markup not to count, got %v", err)
+	}
+	if _, err := BioRules.Clean(strings.Repeat("a", 501)); !errors.Is(err, ErrBioTooLong) {
+		t.Errorf("expected ErrBioTooLong, got %v", err)
+	}
+	if _, err := DescriptionRules.Clean(strings.Repeat("a", 2001)); !errors.Is(err, ErrDescriptionTooLong) {
+		t.Errorf("expected ErrDescriptionTooLong, got %v", err)
+	}
+	if _, err := DisplayNameRules.Clean(strings.Repeat("a", 101)); !errors.Is(err, ErrDisplayNameTooLong) {
+		t.Errorf("expected ErrDisplayNameTooLong, got %v", err)
+	}
+}
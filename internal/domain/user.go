@@ -16,6 +16,7 @@ type User struct {
 	bio         string
 	createdAt   time.Time
 	updatedAt   time.Time
+	clock       Clock
 }
 
 var (
@@ -23,18 +24,21 @@ var (
 )
 
 // NewUser creates a new User with the required fields.
-func NewUser(externalID string, username Username) (*User, error) {
+// clock stamps creation and every later change.
+func NewUser(clock Clock, externalID string, username Username) (*User, error) {
 	if externalID == "" {
 		return nil, ErrUserExternalIDEmpty
 	}
 
-	now := time.Now().UTC()
+	clock = clockOrSystem(clock)
+	now := clock.Now()
 	return &User{
 		id:         NewUserID(),
 		externalID: externalID,
 		username:   username,
 		createdAt:  now,
 		updatedAt:  now,
+		clock:      clock,
 	}, nil
 }
 
@@ -59,6 +63,7 @@ func ReconstructUser(
 		bio:         bio,
 		createdAt:   createdAt,
 		updatedAt:   updatedAt,
+		clock:       SystemClock,
 	}
 }
 
@@ -103,9 +108,20 @@ func (u *User) UpdatedAt() time.Time {
 }
 
 // UpdateProfile updates the user's profile fields.
-func (u *User) UpdateProfile(displayName, avatarURL, bio string) {
+// the display name is cleaned with DisplayNameRules and the bio with BioRules before they're stored.
+func (u *User) UpdateProfile(displayName, avatarURL, bio string) error {
+	displayName, err := DisplayNameRules.Clean(displayName)
+	if err != nil {
+		return err
+	}
+	bio, err = BioRules.Clean(bio)
+	if err != nil {
+		return err
+	}
+
 	u.displayName = displayName
 	u.avatarURL = avatarURL
 	u.bio = bio
-	u.updatedAt = time.Now().UTC()
+	u.updatedAt = u.clock.Now()
+	return nil
 }
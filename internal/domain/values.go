@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -86,6 +87,45 @@ func (id CommunityID) IsZero() bool {
 	return id.value == uuid.Nil
 }
 
+// OrganizationID represents a unique identifier for an organization.
+type OrganizationID struct {
+	value uuid.UUID
+}
+
+// NewOrganizationID creates a new random OrganizationID.
+func NewOrganizationID() OrganizationID {
+	return OrganizationID{value: uuid.New()}
+}
+
+// ParseOrganizationID parses a string into an OrganizationID.
+func ParseOrganizationID(s string) (OrganizationID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return OrganizationID{}, fmt.Errorf("invalid organization id: %w", err)
+	}
+	return OrganizationID{value: id}, nil
+}
+
+// OrganizationIDFromUUID creates an OrganizationID from an existing uuid.
+func OrganizationIDFromUUID(id uuid.UUID) OrganizationID {
+	return OrganizationID{value: id}
+}
+
+// String returns the string representation of the OrganizationID.
+func (id OrganizationID) String() string {
+	return id.value.String()
+}
+
+// UUID returns the underlying uuid value.
+func (id OrganizationID) UUID() uuid.UUID {
+	return id.value
+}
+
+// IsZero returns true if the OrganizationID is not set.
+func (id OrganizationID) IsZero() bool {
+	return id.value == uuid.Nil
+}
+
 // EventID represents a unique identifier for an activity event.
 type EventID struct {
 	value uuid.UUID
@@ -139,6 +179,7 @@ var (
 )
 
 // NewSlug creates a new Slug from a string, validating the format.
+// names on the built-in reserved list are rejected.
 func NewSlug(s string) (Slug, error) {
 	if s == "" {
 		return Slug{}, ErrSlugEmpty
@@ -156,6 +197,10 @@ func NewSlug(s string) (Slug, error) {
 		}
 	}
 
+	if defaultReserved.Contains(s) {
+		return Slug{}, ErrNameReserved
+	}
+
 	return Slug{value: s}, nil
 }
 
@@ -172,6 +217,7 @@ func (s Slug) String() string {
 
 // Username represents a validated username.
 // must be 3-50 chars, alphanumeric with underscores.
+// usernames are unique regardless of case, the casing a user picked is kept for display.
 type Username struct {
 	value string
 }
@@ -184,7 +230,21 @@ var (
 )
 
 // NewUsername creates a new Username from a string, validating the format.
+// names on the built-in reserved list are rejected, use ParseUsername to look up existing users.
 func NewUsername(s string) (Username, error) {
+	username, err := ParseUsername(s)
+	if err != nil {
+		return Username{}, err
+	}
+	if defaultReserved.Contains(s) {
+		return Username{}, ErrNameReserved
+	}
+	return username, nil
+}
+
+// ParseUsername validates a username's format only. for finding users who already hold
+// a name, which may have been reserved since they got it.
+func ParseUsername(s string) (Username, error) {
 	if s == "" {
 		return Username{}, ErrUsernameEmpty
 	}
@@ -215,6 +275,16 @@ func (u Username) String() string {
 	return u.value
 }
 
+// Normalized returns the lowercase form usernames are compared and looked up by.
+func (u Username) Normalized() string {
+	return strings.ToLower(u.value)
+}
+
+// Equal reports whether two usernames are the same handle, ignoring case.
+func (u Username) Equal(other Username) bool {
+	return u.Normalized() == other.Normalized()
+}
+
 // Momentum represents a momentum score value.
 // always non-negative, represents rate of activity change.
 type Momentum struct {
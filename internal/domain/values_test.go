@@ -121,6 +121,18 @@ func TestEventType_IsPositiveSignal(t *testing.T) {
 	}
 }
 
+func TestEventTypes(t *testing.T) {
+	types := EventTypes()
+	if len(types) != len(validEventTypes) {
+		t.Fatalf("expected %d event types, got %d", len(validEventTypes), len(types))
+	}
+	for _, et := range types {
+		if !et.IsValid() {
+			t.Errorf("listed event type %q is not valid", et)
+		}
+	}
+}
+
 func TestMomentum_ClampedToZero(t *testing.T) {
 	tests := []struct {
 		input    float64
@@ -215,3 +227,24 @@ func TestUsername_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestUsername_CaseInsensitive(t *testing.T) {
+	mixed, err := NewUsername("John_Doe")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lower, err := NewUsername("john_doe")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mixed.String() != "John_Doe" {
+		t.Errorf("expected casing kept for display, got %q", mixed.String())
+	}
+	if mixed.Normalized() != "john_doe" {
+		t.Errorf("expected normalized john_doe, got %q", mixed.Normalized())
+	}
+	if !mixed.Equal(lower) {
+		t.Error("expected usernames differing only in case to be equal")
+	}
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxWebhookAllowedDomains caps the domains one community can allow.
+const MaxWebhookAllowedDomains = 50
+
+var (
+	ErrNoWebhookDomains        = errors.New("at least one allowed domain is required")
+	ErrTooManyWebhookDomains   = errors.New("at most 50 allowed domains")
+	ErrInvalidWebhookDomain    = errors.New("allowed domains must be a host name like example.com or *.example.com")
+	ErrWebhookDomainNotAllowed = errors.New("webhook target domain is not allowed by the community")
+)
+
+// CommunityWebhookAllowlist restricts where a community's webhooks may be delivered.
+// a domain like example.com allows that host only, *.example.com allows its subdomains
+// but not example.com itself. communities without an allowlist deliver anywhere.
+type CommunityWebhookAllowlist struct {
+	communityID CommunityID
+	domains     []string
+	updatedAt   time.Time
+}
+
+// NewCommunityWebhookAllowlist creates a validated allowlist for a community.
+// domains are lowercased and deduplicated.
+func NewCommunityWebhookAllowlist(clock Clock, communityID CommunityID, domains []string) (*CommunityWebhookAllowlist, error) {
+	if communityID.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	if len(domains) == 0 {
+		return nil, ErrNoWebhookDomains
+	}
+
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d, err := normalizeWebhookDomain(d)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(normalized, d) {
+			normalized = append(normalized, d)
+		}
+	}
+	if len(normalized) > MaxWebhookAllowedDomains {
+		return nil, ErrTooManyWebhookDomains
+	}
+
+	return &CommunityWebhookAllowlist{
+		communityID: communityID,
+		domains:     normalized,
+		updatedAt:   clockOrSystem(clock).Now(),
+	}, nil
+}
+
+// ReconstructCommunityWebhookAllowlist rebuilds an allowlist from persistence.
+// bypasses validation for trusted data from database.
+func ReconstructCommunityWebhookAllowlist(communityID CommunityID, domains []string, updatedAt time.Time) *CommunityWebhookAllowlist {
+	return &CommunityWebhookAllowlist{
+		communityID: communityID,
+		domains:     domains,
+		updatedAt:   updatedAt,
+	}
+}
+
+func (a *CommunityWebhookAllowlist) CommunityID() CommunityID { return a.communityID }
+func (a *CommunityWebhookAllowlist) UpdatedAt() time.Time     { return a.updatedAt }
+
+// Domains returns the allowed domains.
+func (a *CommunityWebhookAllowlist) Domains() []string {
+	return slices.Clone(a.domains)
+}
+
+// Allows reports whether a host may receive the community's webhooks.
+// a nil allowlist allows every host.
+func (a *CommunityWebhookAllowlist) Allows(host string) bool {
+	if a == nil {
+		return true
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return false
+	}
+	for _, d := range a.domains {
+		if suffix, ok := strings.CutPrefix(d, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == d {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsURL reports whether a target url's host may receive the community's webhooks.
+// urls that don't parse are never allowed.
+func (a *CommunityWebhookAllowlist) AllowsURL(rawURL string) bool {
+	if a == nil {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return a.Allows(u.Hostname())
+}
+
+// normalizeWebhookDomain lowercases a domain and checks it's a host name of at least
+// two labels, optionally under a leading wildcard.
+func normalizeWebhookDomain(raw string) (string, error) {
+	d := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+	host, _ := strings.CutPrefix(d, "*.")
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		// *.com would allow half the internet, and bare names only resolve internally
+		return "", fmt.Errorf("%w: %q", ErrInvalidWebhookDomain, raw)
+	}
+	for _, label := range labels {
+		if !validDomainLabel(label) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidWebhookDomain, raw)
+		}
+	}
+	return d, nil
+}
+
+// validDomainLabel reports whether s is a DNS label: letters, digits and inner hyphens.
+func validDomainLabel(s string) bool {
+	if s == "" || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// CommunityWebhookAllowlistRepository defines persistence for webhook allowlists.
+type CommunityWebhookAllowlistRepository interface {
+	// FindByCommunity retrieves the allowlist for a community.
+	// returns ErrNotFound if the community delivers anywhere.
+	FindByCommunity(ctx context.Context, communityID CommunityID) (*CommunityWebhookAllowlist, error)
+
+	// Save persists an allowlist (insert or update), replacing the previous one.
+	Save(ctx context.Context, allowlist *CommunityWebhookAllowlist) error
+
+	// Delete removes an allowlist, letting the community deliver anywhere again.
+	Delete(ctx context.Context, communityID CommunityID) error
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewCommunityWebhookAllowlist(t *testing.T) {
+	communityID := NewCommunityID()
+
+	tests := []struct {
+		name    string
+		domains []string
+		want    []string
+		wantErr error
+	}{
+		{"exact and wildcard", []string{"Hooks.MyCompany.com", "*.mycompany.com"}, []string{"hooks.mycompany.com", "*.mycompany.com"}, nil},
+		{"duplicates and trailing dot", []string{"example.com", "example.com."}, []string{"example.com"}, nil},
+		{"empty", nil, nil, ErrNoWebhookDomains},
+		{"single label", []string{"localhost"}, nil, ErrInvalidWebhookDomain},
+		{"wildcard over a tld", []string{"*.com"}, nil, ErrInvalidWebhookDomain},
+		{"url instead of host", []string{"https://example.com"}, nil, ErrInvalidWebhookDomain},
+		{"port", []string{"example.com:8443"}, nil, ErrInvalidWebhookDomain},
+		{"inner wildcard", []string{"hooks.*.example.com"}, nil, ErrInvalidWebhookDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowlist, err := NewCommunityWebhookAllowlist(SystemClock, communityID, tt.domains)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := allowlist.Domains(); !slices.Equal(got, tt.want) {
+				t.Errorf("domains = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCommunityWebhookAllowlist_TooMany(t *testing.T) {
+	domains := make([]string, MaxWebhookAllowedDomains+1)
+	for i := range domains {
+		domains[i] = string(rune('a'+i%26)) + string(rune('a'+i/26)) + ".example.com"
+	}
+	if _, err := NewCommunityWebhookAllowlist(SystemClock, NewCommunityID(), domains); !errors.Is(err, ErrTooManyWebhookDomains) {
+		t.Errorf("expected ErrTooManyWebhookDomains, got %v", err)
+	}
+}
+
+func TestCommunityWebhookAllowlist_AllowsURL(t *testing.T) {
+	allowlist, err := NewCommunityWebhookAllowlist(SystemClock, NewCommunityID(), []string{"hooks.partner.io", "*.mycompany.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://hooks.partner.io/pulse", true},
+		{"https://HOOKS.partner.io:8443/pulse", true},
+		{"https://api.mycompany.com/webhooks", true},
+		{"https://a.b.mycompany.com/webhooks", true},
+		{"https://mycompany.com/webhooks", false},
+		{"https://evilmycompany.com/webhooks", false},
+		{"https://mycompany.com.evil.io/webhooks", false},
+		{"https://partner.io/pulse", false},
+		{"://broken", false},
+	}
+
+	for _, tt := range tests {
+		if got := allowlist.AllowsURL(tt.url); got != tt.want {
+			t.Errorf("AllowsURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+
+	var none *CommunityWebhookAllowlist
+	if !none.AllowsURL("https://anywhere.example/") {
+		t.Error("a community without an allowlist should deliver anywhere")
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDeliveryRetention is how long delivery attempts are kept.
+const WebhookDeliveryRetention = 30 * 24 * time.Hour
+
+// WebhookDelivery records one attempt to deliver a notification to a subscription,
+// so its owner can tell whether the subscriber received it.
+type WebhookDelivery struct {
+	id             uuid.UUID
+	subscriptionID WebhookSubscriptionID
+	event          string
+	statusCode     int
+	latency        time.Duration
+	err            string
+	attemptedAt    time.Time
+}
+
+// NewWebhookDelivery records an attempt that ended now. id is the X-Pulse-Delivery-ID
+// the subscriber saw, uuid.Nil for a new one. statusCode is 0 when no response came back,
+// cause is nil when the subscriber accepted the notification.
+func NewWebhookDelivery(
+	clock Clock,
+	id uuid.UUID,
+	subscriptionID WebhookSubscriptionID,
+	event string,
+	statusCode int,
+	latency time.Duration,
+	cause error,
+) *WebhookDelivery {
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	d := &WebhookDelivery{
+		id:             id,
+		subscriptionID: subscriptionID,
+		event:          event,
+		statusCode:     statusCode,
+		latency:        latency,
+		attemptedAt:    clockOrSystem(clock).Now(),
+	}
+	if cause != nil {
+		d.err = cause.Error()
+	}
+	return d
+}
+
+// ReconstructWebhookDelivery rebuilds a delivery attempt from persistence.
+func ReconstructWebhookDelivery(
+	id uuid.UUID,
+	subscriptionID WebhookSubscriptionID,
+	event string,
+	statusCode int,
+	latency time.Duration,
+	err string,
+	attemptedAt time.Time,
+) *WebhookDelivery {
+	return &WebhookDelivery{
+		id:             id,
+		subscriptionID: subscriptionID,
+		event:          event,
+		statusCode:     statusCode,
+		latency:        latency,
+		err:            err,
+		attemptedAt:    attemptedAt,
+	}
+}
+
+func (d *WebhookDelivery) ID() uuid.UUID                         { return d.id }
+func (d *WebhookDelivery) SubscriptionID() WebhookSubscriptionID { return d.subscriptionID }
+func (d *WebhookDelivery) Event() string                         { return d.event }
+func (d *WebhookDelivery) StatusCode() int                       { return d.statusCode }
+func (d *WebhookDelivery) Latency() time.Duration                { return d.latency }
+func (d *WebhookDelivery) AttemptedAt() time.Time                { return d.attemptedAt }
+
+// Err returns why the delivery failed, empty when it succeeded.
+func (d *WebhookDelivery) Err() string { return d.err }
+
+// Succeeded reports whether the subscriber accepted the notification.
+func (d *WebhookDelivery) Succeeded() bool { return d.err == "" }
+
+// WebhookDeliveryRepository persists delivery attempts.
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, delivery *WebhookDelivery) error
+
+	// ListBySubscription returns a subscription's attempts, newest first.
+	ListBySubscription(ctx context.Context, subscriptionID WebhookSubscriptionID, limit, offset int) ([]*WebhookDelivery, error)
+
+	// DeleteBefore removes the attempts made before t, returning how many.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPauseWindows caps how many pause windows one subscription can declare.
+const MaxPauseWindows = 14
+
+// MaxPauseWindowLength caps a single pause window, so a subscription can't hold its
+// deliveries for most of the day.
+const MaxPauseWindowLength = 12 * time.Hour
+
+var (
+	ErrInvalidPauseWindow  = errors.New("pause windows need different start and end times as HH:MM, at most 12 hours apart, and a weekday or none")
+	ErrTooManyPauseWindows = errors.New("at most 14 pause windows per subscription")
+)
+
+// DeliveryPauseWindow is a recurring span, in the subscriber's timezone, during which the
+// subscription's deliveries are held and sent once it ends, e.g. every Sunday 02:00 to 03:00.
+// the span may cross midnight, it then ends on the next day.
+type DeliveryPauseWindow struct {
+	weekday  *time.Weekday // nil repeats the window every day
+	start    int           // minutes after midnight, inclusive
+	end      int           // minutes after midnight, exclusive
+	location *time.Location
+}
+
+// ParseDeliveryPauseWindow validates a pause window given as a weekday name, HH:MM times
+// and an IANA timezone. an empty day repeats the window daily, an empty timezone means UTC.
+func ParseDeliveryPauseWindow(day, start, end, timezone string) (DeliveryPauseWindow, error) {
+	weekday, err := parseWeekday(day)
+	if err != nil {
+		return DeliveryPauseWindow{}, err
+	}
+	startMinute, err := parseClockMinute(start)
+	if err != nil {
+		return DeliveryPauseWindow{}, ErrInvalidPauseWindow
+	}
+	endMinute, err := parseClockMinute(end)
+	if err != nil {
+		return DeliveryPauseWindow{}, ErrInvalidPauseWindow
+	}
+	if startMinute == endMinute {
+		return DeliveryPauseWindow{}, ErrInvalidPauseWindow
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return DeliveryPauseWindow{}, ErrInvalidTimezone
+	}
+
+	w := DeliveryPauseWindow{weekday: weekday, start: startMinute, end: endMinute, location: location}
+	if w.length() > MaxPauseWindowLength {
+		return DeliveryPauseWindow{}, ErrInvalidPauseWindow
+	}
+	return w, nil
+}
+
+// ReconstructDeliveryPauseWindow rebuilds a pause window from persistence.
+// an unknown day repeats daily and an unknown timezone falls back to UTC, rather than
+// dropping the window.
+func ReconstructDeliveryPauseWindow(day string, startMinute, endMinute int, timezone string) DeliveryPauseWindow {
+	weekday, _ := parseWeekday(day)
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return DeliveryPauseWindow{weekday: weekday, start: startMinute, end: endMinute, location: location}
+}
+
+// parseWeekday parses an english weekday name, nil for an empty one.
+func parseWeekday(day string) (*time.Weekday, error) {
+	if day == "" {
+		return nil, nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(day, d.String()) {
+			return &d, nil
+		}
+	}
+	return nil, ErrInvalidPauseWindow
+}
+
+// Day returns the lowercase weekday name the window starts on, empty when it's daily.
+func (w DeliveryPauseWindow) Day() string {
+	if w.weekday == nil {
+		return ""
+	}
+	return strings.ToLower(w.weekday.String())
+}
+
+// StartMinute and EndMinute are minutes after midnight.
+func (w DeliveryPauseWindow) StartMinute() int { return w.start }
+func (w DeliveryPauseWindow) EndMinute() int   { return w.end }
+
+// Start returns the start time as HH:MM.
+func (w DeliveryPauseWindow) Start() string { return formatClockMinute(w.start) }
+
+// End returns the end time as HH:MM.
+func (w DeliveryPauseWindow) End() string { return formatClockMinute(w.end) }
+
+// Timezone returns the IANA timezone name.
+func (w DeliveryPauseWindow) Timezone() string { return w.location.String() }
+
+func (w DeliveryPauseWindow) length() time.Duration {
+	minutes := w.end - w.start
+	if minutes < 0 {
+		minutes += 24 * 60
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// EndAfter returns when the window ends if t falls within it.
+func (w DeliveryPauseWindow) EndAfter(t time.Time) (time.Time, bool) {
+	local := t.In(w.location)
+
+	// a window containing t started today or, when it crosses midnight, yesterday
+	for _, day := range []time.Time{local, local.AddDate(0, 0, -1)} {
+		if w.weekday != nil && day.Weekday() != *w.weekday {
+			continue
+		}
+		y, m, d := day.Date()
+		start := time.Date(y, m, d, w.start/60, w.start%60, 0, 0, w.location)
+		end := time.Date(y, m, d, w.end/60, w.end%60, 0, 0, w.location)
+		if w.end < w.start {
+			end = time.Date(y, m, d+1, w.end/60, w.end%60, 0, 0, w.location)
+		}
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetPauseWindows replaces the subscription's pause windows, none to deliver at any time.
+func (s *WebhookSubscription) SetPauseWindows(windows []DeliveryPauseWindow) error {
+	if len(windows) > MaxPauseWindows {
+		return ErrTooManyPauseWindows
+	}
+	s.pauseWindows = append([]DeliveryPauseWindow(nil), windows...)
+	s.updatedAt = s.clock.Now()
+	return nil
+}
+
+// PausedUntil returns when deliveries may resume if t falls within one of the
+// subscription's pause windows. back-to-back or overlapping windows are joined.
+func (s *WebhookSubscription) PausedUntil(t time.Time) (time.Time, bool) {
+	until, paused := t, false
+	// each pass can only extend the pause by another window
+	for range len(s.pauseWindows) {
+		extended := false
+		for _, w := range s.pauseWindows {
+			if end, ok := w.EndAfter(until); ok && end.After(until) {
+				until, paused, extended = end, true, true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return until, paused
+}
+
+// WebhookOutboxEntry is a notification held for a paused subscription,
+// delivered once its pause window ends.
+type WebhookOutboxEntry struct {
+	id             uuid.UUID
+	subscriptionID WebhookSubscriptionID
+	communityID    CommunityID
+	event          string
+	payload        []byte
+	createdAt      time.Time
+	deliverAfter   time.Time
+}
+
+// NewWebhookOutboxEntry holds a notification until deliverAfter.
+// payload is the notification as JSON, restored by event name when it's delivered.
+func NewWebhookOutboxEntry(
+	clock Clock,
+	subscriptionID WebhookSubscriptionID,
+	communityID CommunityID,
+	event string,
+	payload []byte,
+	deliverAfter time.Time,
+) (*WebhookOutboxEntry, error) {
+	if event == "" || len(payload) == 0 {
+		return nil, ErrInvalidInput
+	}
+	return &WebhookOutboxEntry{
+		id:             uuid.New(),
+		subscriptionID: subscriptionID,
+		communityID:    communityID,
+		event:          event,
+		payload:        payload,
+		createdAt:      clockOrSystem(clock).Now(),
+		deliverAfter:   deliverAfter,
+	}, nil
+}
+
+// ReconstructWebhookOutboxEntry rebuilds an outbox entry from persistence.
+func ReconstructWebhookOutboxEntry(
+	id uuid.UUID,
+	subscriptionID WebhookSubscriptionID,
+	communityID CommunityID,
+	event string,
+	payload []byte,
+	createdAt time.Time,
+	deliverAfter time.Time,
+) *WebhookOutboxEntry {
+	return &WebhookOutboxEntry{
+		id:             id,
+		subscriptionID: subscriptionID,
+		communityID:    communityID,
+		event:          event,
+		payload:        payload,
+		createdAt:      createdAt,
+		deliverAfter:   deliverAfter,
+	}
+}
+
+func (e *WebhookOutboxEntry) ID() uuid.UUID                         { return e.id }
+func (e *WebhookOutboxEntry) SubscriptionID() WebhookSubscriptionID { return e.subscriptionID }
+func (e *WebhookOutboxEntry) CommunityID() CommunityID              { return e.communityID }
+func (e *WebhookOutboxEntry) Event() string                         { return e.event }
+func (e *WebhookOutboxEntry) Payload() []byte                       { return e.payload }
+func (e *WebhookOutboxEntry) CreatedAt() time.Time                  { return e.createdAt }
+func (e *WebhookOutboxEntry) DeliverAfter() time.Time               { return e.deliverAfter }
+
+// WebhookOutboxRepository persists notifications held for paused subscriptions.
+type WebhookOutboxRepository interface {
+	Add(ctx context.Context, entry *WebhookOutboxEntry) error
+
+	// ClaimDue returns up to limit entries due at now, and pushes them back by lease so
+	// other instances skip them until they're delivered or the lease runs out.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookOutboxEntry, error)
+
+	// Reschedule holds an entry until deliverAfter.
+	Reschedule(ctx context.Context, id uuid.UUID, deliverAfter time.Time) error
+
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDeliveryPauseWindow(t *testing.T) {
+	tests := []struct {
+		name                      string
+		day, start, end, timezone string
+		wantErr                   error
+	}{
+		{"weekly", "Sunday", "02:00", "03:00", "UTC", nil},
+		{"daily across midnight", "", "23:30", "00:30", "Europe/Madrid", nil},
+		{"empty timezone", "monday", "02:00", "03:00", "", nil},
+		{"same start and end", "sunday", "02:00", "02:00", "UTC", ErrInvalidPauseWindow},
+		{"bad time", "sunday", "2am", "03:00", "UTC", ErrInvalidPauseWindow},
+		{"unknown day", "someday", "02:00", "03:00", "UTC", ErrInvalidPauseWindow},
+		{"too long", "", "08:00", "21:00", "UTC", ErrInvalidPauseWindow},
+		{"unknown timezone", "sunday", "02:00", "03:00", "Mars/Olympus", ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDeliveryPauseWindow(tt.day, tt.start, tt.end, tt.timezone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscription_PausedUntil(t *testing.T) {
+	sunday, _ := ParseDeliveryPauseWindow("sunday", "02:00", "03:00", "UTC")
+	lateSaturday, _ := ParseDeliveryPauseWindow("saturday", "23:00", "02:00", "UTC")
+	daily, _ := ParseDeliveryPauseWindow("", "12:00", "12:30", "Europe/Madrid")
+
+	id, _ := NewWebhookSubscriptionID("sub-1")
+	sub, err := NewWebhookSubscription(SystemClock, id, NewUserID(), NewCommunityID(), "https://example.com/hook", "secret", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sub.SetPauseWindows([]DeliveryPauseWindow{sunday, lateSaturday, daily}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2025-03-02 is a sunday; madrid is UTC+1 in march
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		wantPaused bool
+		want       time.Time
+	}{
+		{"inside the weekly window", at(2, 2, 30), true, at(2, 3, 0)},
+		{"end is exclusive", at(2, 3, 0), false, time.Time{}},
+		{"window crossing midnight joins the next one", at(1, 23, 15), true, at(2, 3, 0)},
+		{"after midnight, in yesterday's window", at(2, 1, 0), true, at(2, 3, 0)},
+		{"weekly window on another day", at(3, 2, 30), false, time.Time{}},
+		{"daily window in its timezone", at(4, 11, 10), true, at(4, 11, 30)},
+		{"outside every window", at(4, 12, 10), false, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, paused := sub.PausedUntil(tt.t)
+			if paused != tt.wantPaused {
+				t.Fatalf("paused = %v, want %v", paused, tt.wantPaused)
+			}
+			if paused && !got.Equal(tt.want) {
+				t.Errorf("paused until %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	tooMany := make([]DeliveryPauseWindow, MaxPauseWindows+1)
+	if err := sub.SetPauseWindows(tooMany); !errors.Is(err, ErrTooManyPauseWindows) {
+		t.Errorf("expected ErrTooManyPauseWindows, got %v", err)
+	}
+}
@@ -0,0 +1,599 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/cache"
+	"github.com/joacominatel/pulse/internal/infrastructure/worker"
+)
+
+// CommunityCache is the in-memory cache of community existence, state and organization
+// that ingestion checks against. implemented by cache.CommunityExistsCache.
+type CommunityCache interface {
+	Stats() cache.CommunityExistsStats
+	Invalidate(id domain.CommunityID) bool
+	Flush() int
+}
+
+// MomentumWorker is the background momentum recalculation of this instance.
+// implemented by worker.MomentumWorker.
+type MomentumWorker interface {
+	Pause() bool
+	Resume() bool
+	Trigger()
+	Status() worker.MomentumWorkerStatus
+}
+
+// AdminHandler handles operator endpoints.
+// every route requires an admin token (service_role or app_metadata role "admin").
+type AdminHandler struct {
+	rebuildLeaderboard *application.RebuildLeaderboardUseCase
+	anomalies          *application.AnomalyUseCase
+	rejectedEvents     domain.RejectedEventRepository
+	communityCache     CommunityCache
+	pins               *application.CommunityPinUseCase
+	momentumWorker     MomentumWorker
+	standby            StandbySwitch
+	momentumRuns       domain.MomentumRunRepository
+}
+
+// NewAdminHandler creates a new AdminHandler.
+// rebuildLeaderboard may be nil when redis is disabled.
+func NewAdminHandler(
+	rebuildLeaderboard *application.RebuildLeaderboardUseCase,
+	anomalies *application.AnomalyUseCase,
+	rejectedEvents domain.RejectedEventRepository,
+	communityCache CommunityCache,
+	pins *application.CommunityPinUseCase,
+	momentumWorker MomentumWorker,
+	standby StandbySwitch,
+	momentumRuns domain.MomentumRunRepository,
+) *AdminHandler {
+	return &AdminHandler{
+		rebuildLeaderboard: rebuildLeaderboard,
+		anomalies:          anomalies,
+		rejectedEvents:     rejectedEvents,
+		communityCache:     communityCache,
+		pins:               pins,
+		momentumWorker:     momentumWorker,
+		standby:            standby,
+		momentumRuns:       momentumRuns,
+	}
+}
+
+// RegisterRoutes registers the admin routes on the given group.
+func (h *AdminHandler) RegisterRoutes(g *echo.Group) {
+	admin := g.Group("/admin", RequireAdmin())
+	admin.POST("/leaderboard/rebuild", h.RebuildLeaderboard)
+	admin.GET("/anomalies", h.ListAnomalies)
+	admin.POST("/anomalies/:id/confirm", h.reviewAnomaly(true))
+	admin.POST("/anomalies/:id/dismiss", h.reviewAnomaly(false))
+	admin.GET("/rejected-events", h.ListRejectedEvents)
+	admin.GET("/caches", h.GetCaches)
+	admin.DELETE("/caches", h.FlushCaches)
+	if h.pins != nil {
+		admin.GET("/pins", h.ListPins)
+		admin.POST("/pins", h.PinCommunity)
+		admin.DELETE("/pins/:id", h.UnpinCommunity)
+	}
+	if h.momentumWorker != nil {
+		admin.GET("/momentum-worker", h.GetMomentumWorker)
+		admin.POST("/momentum-worker/pause", h.PauseMomentumWorker)
+		admin.POST("/momentum-worker/resume", h.ResumeMomentumWorker)
+		admin.POST("/momentum-worker/run", h.RunMomentumWorker)
+	}
+	if h.momentumRuns != nil {
+		admin.GET("/momentum/runs", h.ListMomentumRuns)
+	}
+	if h.standby != nil {
+		admin.GET("/standby", h.GetStandby)
+		admin.PUT("/standby", h.SetStandby)
+	}
+}
+
+// rebuildLeaderboardResponse reports the result of a leaderboard rebuild.
+type rebuildLeaderboardResponse struct {
+	Communities int   `json:"communities"`
+	Batches     int   `json:"batches"`
+	DurationMs  int64 `json:"duration_ms"`
+}
+
+// RebuildLeaderboard truncates and repopulates the redis leaderboard from postgres.
+// POST /api/v1/admin/leaderboard/rebuild
+func (h *AdminHandler) RebuildLeaderboard(c echo.Context) error {
+	if h.rebuildLeaderboard == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "leaderboard cache is disabled")
+	}
+
+	output, err := h.rebuildLeaderboard.Execute(c.Request().Context(), application.RebuildLeaderboardInput{})
+	if err != nil {
+		if errors.Is(err, application.ErrLeaderboardRebuildInProgress) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "leaderboard rebuild failed")
+	}
+
+	return c.JSON(http.StatusOK, rebuildLeaderboardResponse{
+		Communities: output.Communities,
+		Batches:     output.Batches,
+		DurationMs:  output.Duration.Milliseconds(),
+	})
+}
+
+type anomalyResponse struct {
+	ID             string     `json:"id"`
+	CommunityID    string     `json:"community_id"`
+	UserID         *string    `json:"user_id,omitempty"`
+	Status         string     `json:"status"`
+	ObservedEvents int64      `json:"observed_events"`
+	ExpectedEvents float64    `json:"expected_events"`
+	Ratio          float64    `json:"ratio"`
+	WindowStart    time.Time  `json:"window_start"`
+	DetectedAt     time.Time  `json:"detected_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+}
+
+type listAnomaliesResponse struct {
+	Anomalies []anomalyResponse `json:"anomalies"`
+	Count     int               `json:"count"`
+}
+
+// ListAnomalies returns anomaly flags, newest first. pending flags are the review queue.
+// GET /api/v1/admin/anomalies?status=pending&limit=50&offset=0
+func (h *AdminHandler) ListAnomalies(c echo.Context) error {
+	if h.anomalies == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "anomaly detection is disabled")
+	}
+
+	status := domain.AnomalyPending
+	if s := c.QueryParam("status"); s != "" {
+		parsed, err := domain.ParseAnomalyStatus(s)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		status = parsed
+	}
+
+	limit := 50
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	flags, err := h.anomalies.List(c.Request().Context(), status, limit, offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "listing anomalies failed")
+	}
+
+	resp := listAnomaliesResponse{
+		Anomalies: make([]anomalyResponse, len(flags)),
+		Count:     len(flags),
+	}
+	for i, f := range flags {
+		resp.Anomalies[i] = toAnomalyResponse(f)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// reviewAnomaly confirms or dismisses a pending flag.
+// POST /api/v1/admin/anomalies/:id/confirm
+// POST /api/v1/admin/anomalies/:id/dismiss
+func (h *AdminHandler) reviewAnomaly(confirm bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if h.anomalies == nil {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "anomaly detection is disabled")
+		}
+
+		// service_role tokens have no subject
+		reviewer := GetUserExternalID(c)
+		if reviewer == "" {
+			reviewer = "service_role"
+		}
+
+		output, err := h.anomalies.Review(c.Request().Context(), c.Param("id"), confirm, reviewer)
+		if err != nil {
+			if errors.Is(err, domain.ErrAnomalyReviewed) {
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			}
+			return mapDomainError(err)
+		}
+
+		return c.JSON(http.StatusOK, toAnomalyResponse(*output))
+	}
+}
+
+func toAnomalyResponse(f application.AnomalyFlagOutput) anomalyResponse {
+	return anomalyResponse{
+		ID:             f.ID,
+		CommunityID:    f.CommunityID,
+		UserID:         f.UserID,
+		Status:         f.Status.String(),
+		ObservedEvents: f.Observed,
+		ExpectedEvents: f.Expected,
+		Ratio:          f.Ratio,
+		WindowStart:    f.WindowStart,
+		DetectedAt:     f.DetectedAt,
+		ReviewedAt:     f.ReviewedAt,
+		ReviewedBy:     f.ReviewedBy,
+	}
+}
+
+type rejectedEventResponse struct {
+	ID          string         `json:"id"`
+	CommunityID string         `json:"community_id"`
+	UserID      *string        `json:"user_id,omitempty"`
+	EventType   string         `json:"event_type"`
+	Weight      float64        `json:"weight"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Reason      string         `json:"reason"`
+	RejectedAt  time.Time      `json:"rejected_at"`
+}
+
+type listRejectedEventsResponse struct {
+	Events []rejectedEventResponse `json:"events"`
+	Count  int                     `json:"count"`
+}
+
+// ListRejectedEvents returns the events quarantined by deferred validation, newest first.
+// GET /api/v1/admin/rejected-events?limit=50&offset=0
+func (h *AdminHandler) ListRejectedEvents(c echo.Context) error {
+	limit := 50
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	rejected, err := h.rejectedEvents.List(c.Request().Context(), limit, offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "listing rejected events failed")
+	}
+
+	resp := listRejectedEventsResponse{
+		Events: make([]rejectedEventResponse, len(rejected)),
+		Count:  len(rejected),
+	}
+	for i, r := range rejected {
+		event := r.Event()
+		var userID *string
+		if event.UserID() != nil {
+			id := event.UserID().String()
+			userID = &id
+		}
+		resp.Events[i] = rejectedEventResponse{
+			ID:          event.ID().String(),
+			CommunityID: event.CommunityID().String(),
+			UserID:      userID,
+			EventType:   event.EventType().String(),
+			Weight:      event.Weight().Value(),
+			Metadata:    event.Metadata(),
+			OccurredAt:  event.OccurredAt(),
+			CreatedAt:   event.CreatedAt(),
+			Reason:      r.Reason().String(),
+			RejectedAt:  r.RejectedAt(),
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// communityCacheResponse summarizes the community cache. expired entries are
+// still held until the next cleanup, but are never served.
+type communityCacheResponse struct {
+	Entries    int     `json:"entries"`
+	Active     int     `json:"active"`
+	Inactive   int     `json:"inactive"`
+	Missing    int     `json:"missing"`
+	Expired    int     `json:"expired"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+}
+
+type cachesResponse struct {
+	CommunityExists communityCacheResponse `json:"community_exists"`
+}
+
+// GetCaches summarizes the in-memory caches of this instance.
+// GET /api/v1/admin/caches
+func (h *AdminHandler) GetCaches(c echo.Context) error {
+	if h.communityCache == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "community cache is disabled")
+	}
+
+	stats := h.communityCache.Stats()
+	return c.JSON(http.StatusOK, cachesResponse{
+		CommunityExists: communityCacheResponse{
+			Entries:    stats.Entries,
+			Active:     stats.Active,
+			Inactive:   stats.Inactive,
+			Missing:    stats.Missing,
+			Expired:    stats.Expired,
+			TTLSeconds: stats.TTL.Seconds(),
+		},
+	})
+}
+
+type flushCachesResponse struct {
+	Removed int `json:"removed"`
+}
+
+// FlushCaches drops one community from the community cache, or every entry without
+// community_id, so a change made behind pulse's back applies right away instead of after
+// the ttl. caches are per instance: call it on every instance.
+// DELETE /api/v1/admin/caches?community_id=
+func (h *AdminHandler) FlushCaches(c echo.Context) error {
+	if h.communityCache == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "community cache is disabled")
+	}
+
+	if s := c.QueryParam("community_id"); s != "" {
+		id, err := domain.ParseCommunityID(s)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		removed := 0
+		if h.communityCache.Invalidate(id) {
+			removed = 1
+		}
+		return c.JSON(http.StatusOK, flushCachesResponse{Removed: removed})
+	}
+
+	return c.JSON(http.StatusOK, flushCachesResponse{Removed: h.communityCache.Flush()})
+}
+
+// PinCommunityRequest is the request body for pinning a community to the leaderboard.
+type PinCommunityRequest struct {
+	CommunityID string     `json:"community_id" validate:"required,uuid"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      time.Time  `json:"ends_at" validate:"required"`
+	Sponsored   bool       `json:"sponsored"`
+}
+
+type pinResponse struct {
+	ID          string    `json:"id"`
+	CommunityID string    `json:"community_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Sponsored   bool      `json:"sponsored"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type listPinsResponse struct {
+	Pins []pinResponse `json:"pins"`
+}
+
+// ListPins returns the active and scheduled leaderboard pins, by start time.
+// GET /api/v1/admin/pins
+func (h *AdminHandler) ListPins(c echo.Context) error {
+	pins, err := h.pins.List(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "listing pins failed")
+	}
+
+	resp := listPinsResponse{Pins: make([]pinResponse, len(pins))}
+	for i, pin := range pins {
+		resp.Pins[i] = toPinResponse(pin)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PinCommunity puts a community above the global leaderboard from starts_at, or now,
+// until ends_at.
+// POST /api/v1/admin/pins
+func (h *AdminHandler) PinCommunity(c echo.Context) error {
+	var req PinCommunityRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	pin, err := h.pins.Pin(c.Request().Context(), application.PinCommunityInput{
+		CommunityID: req.CommunityID,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Sponsored:   req.Sponsored,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrTooManyPins) || errors.Is(err, domain.ErrPinOverlap) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return mapDomainError(err)
+	}
+
+	return c.JSON(http.StatusCreated, toPinResponse(pin))
+}
+
+// UnpinCommunity removes a pin, ending it early or cancelling it.
+// DELETE /api/v1/admin/pins/:id
+func (h *AdminHandler) UnpinCommunity(c echo.Context) error {
+	if err := h.pins.Unpin(c.Request().Context(), c.Param("id")); err != nil {
+		return mapDomainError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toPinResponse(pin *domain.CommunityPin) pinResponse {
+	return pinResponse{
+		ID:          pin.ID().String(),
+		CommunityID: pin.CommunityID().String(),
+		StartsAt:    pin.StartsAt(),
+		EndsAt:      pin.EndsAt(),
+		Sponsored:   pin.Sponsored(),
+		CreatedAt:   pin.CreatedAt(),
+	}
+}
+
+type momentumWorkerResponse struct {
+	Paused          bool       `json:"paused"`
+	PausedAt        *time.Time `json:"paused_at,omitempty"`
+	Running         bool       `json:"running"`
+	IntervalSeconds int64      `json:"interval_seconds"`
+	LastStartedAt   *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt  *time.Time `json:"last_finished_at,omitempty"`
+
+	// Triggered is set when the request queued a cycle.
+	Triggered bool `json:"triggered,omitempty"`
+}
+
+func (h *AdminHandler) momentumWorkerJSON(c echo.Context, status int, triggered bool) error {
+	s := h.momentumWorker.Status()
+	return c.JSON(status, momentumWorkerResponse{
+		Paused:          s.Paused,
+		PausedAt:        s.PausedAt,
+		Running:         s.Running,
+		IntervalSeconds: int64(s.Interval.Seconds()),
+		LastStartedAt:   s.LastStartedAt,
+		LastFinishedAt:  s.LastFinishedAt,
+		Triggered:       triggered,
+	})
+}
+
+// GetMomentumWorker reports whether this instance's momentum worker is paused or mid-cycle.
+// GET /api/v1/admin/momentum-worker
+func (h *AdminHandler) GetMomentumWorker(c echo.Context) error {
+	return h.momentumWorkerJSON(c, http.StatusOK, false)
+}
+
+// PauseMomentumWorker stops the scheduled momentum cycles of this instance until resumed.
+// a cycle in progress finishes.
+// POST /api/v1/admin/momentum-worker/pause
+func (h *AdminHandler) PauseMomentumWorker(c echo.Context) error {
+	h.momentumWorker.Pause()
+	return h.momentumWorkerJSON(c, http.StatusOK, false)
+}
+
+// ResumeMomentumWorker runs the scheduled momentum cycles again, from the next tick.
+// POST /api/v1/admin/momentum-worker/resume
+func (h *AdminHandler) ResumeMomentumWorker(c echo.Context) error {
+	h.momentumWorker.Resume()
+	return h.momentumWorkerJSON(c, http.StatusOK, false)
+}
+
+// RunMomentumWorker queues a momentum cycle now, paused or not, and returns without waiting for it.
+// POST /api/v1/admin/momentum-worker/run
+func (h *AdminHandler) RunMomentumWorker(c echo.Context) error {
+	if h.standby != nil && h.standby.Standby() {
+		return echo.NewHTTPError(http.StatusConflict, "instance is a standby, run the cycle on the active instance")
+	}
+	h.momentumWorker.Trigger()
+	return h.momentumWorkerJSON(c, http.StatusAccepted, true)
+}
+
+type momentumRunResponse struct {
+	ID          string    `json:"id"`
+	TriggeredBy string    `json:"triggered_by"`
+	StartedAt   time.Time `json:"started_at"`
+	DurationMS  int64     `json:"duration_ms"`
+	Processed   int       `json:"processed"`
+	Succeeded   int       `json:"succeeded"`
+	Failed      int       `json:"failed"`
+	Error       string    `json:"error,omitempty"`
+
+	Failures []MomentumFailureResponse `json:"failures,omitempty"`
+}
+
+type listMomentumRunsResponse struct {
+	Runs  []momentumRunResponse `json:"runs"`
+	Count int                   `json:"count"`
+}
+
+// ListMomentumRuns returns the recorded momentum cycles of every instance, newest first.
+// GET /api/v1/admin/momentum/runs?limit=50&offset=0
+func (h *AdminHandler) ListMomentumRuns(c echo.Context) error {
+	limit := 50
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	runs, err := h.momentumRuns.List(c.Request().Context(), limit, offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "listing momentum runs failed")
+	}
+
+	resp := listMomentumRunsResponse{
+		Runs:  make([]momentumRunResponse, len(runs)),
+		Count: len(runs),
+	}
+	for i, run := range runs {
+		resp.Runs[i] = momentumRunResponse{
+			ID:          run.ID().String(),
+			TriggeredBy: run.TriggeredBy().String(),
+			StartedAt:   run.StartedAt(),
+			DurationMS:  run.Duration().Milliseconds(),
+			Processed:   run.Processed(),
+			Succeeded:   run.Succeeded(),
+			Failed:      run.Failed(),
+			Error:       run.Err(),
+			Failures:    toMomentumFailureResponses(run.Failures()),
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type standbyRequest struct {
+	Standby *bool `json:"standby"`
+}
+
+type standbyResponse struct {
+	Standby bool      `json:"standby"`
+	Since   time.Time `json:"since"`
+
+	// Changed is set when the request switched the mode.
+	Changed bool `json:"changed,omitempty"`
+}
+
+func (h *AdminHandler) standbyJSON(c echo.Context, changed bool) error {
+	return c.JSON(http.StatusOK, standbyResponse{
+		Standby: h.standby.Standby(),
+		Since:   h.standby.Since(),
+		Changed: changed,
+	})
+}
+
+// GetStandby reports whether this instance is a standby.
+// GET /api/v1/admin/standby
+func (h *AdminHandler) GetStandby(c echo.Context) error {
+	return h.standbyJSON(c, false)
+}
+
+// SetStandby switches this instance between active and standby. a standby serves reads,
+// skips the momentum, anomaly and leaderboard resync workers and rejects writes.
+// PUT /api/v1/admin/standby
+func (h *AdminHandler) SetStandby(c echo.Context) error {
+	var req standbyRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if req.Standby == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "standby is required")
+	}
+	return h.standbyJSON(c, h.standby.Set(*req.Standby))
+}
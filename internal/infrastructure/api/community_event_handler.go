@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityEventHandler handles listing the recent activity events of a community.
+type CommunityEventHandler struct {
+	useCase *application.CommunityEventsUseCase
+}
+
+// NewCommunityEventHandler creates a new CommunityEventHandler.
+func NewCommunityEventHandler(useCase *application.CommunityEventsUseCase) *CommunityEventHandler {
+	return &CommunityEventHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the community event routes on the given group.
+// listing requires the community owner or an admin.
+func (h *CommunityEventHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/communities/:id/events", h.List)
+}
+
+type communityEventResponse struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id,omitempty"` // omitted for anonymous events
+	EventType  string         `json:"event_type"`
+	Weight     float64        `json:"weight"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type communityEventsResponse struct {
+	CommunityID string                   `json:"community_id"`
+	Since       time.Time                `json:"since"`
+	Count       int                      `json:"count"`
+	Events      []communityEventResponse `json:"events"`
+}
+
+// List returns the recent activity events of a community, newest first.
+// @Summary List a community's recent events
+// @Description Events that occurred since the given time (RFC 3339, or a duration like 1h meaning that long ago, 24h by default), optionally of the given types.
+// @Tags events
+// @Produce json
+// @Param id path string true "Community ID"
+// @Param since query string false "RFC 3339 timestamp or duration, e.g. 2h"
+// @Param type query string false "Comma-separated event types, e.g. post,comment"
+// @Param limit query int false "Max events (default 50, max 100)"
+// @Success 200 {object} communityEventsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/communities/{id}/events [get]
+// @Security BearerAuth
+func (h *CommunityEventHandler) List(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	admin := false
+	if claims := GetClaims(c); claims != nil {
+		admin = claims.IsAdmin()
+	}
+
+	since, err := parseSince(c.QueryParam("since"), time.Now())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "since must be an RFC 3339 timestamp or a duration like 1h")
+	}
+
+	var types []string
+	for _, param := range c.QueryParams()["type"] {
+		for _, t := range strings.Split(param, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+
+	limit := 50
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	output, err := h.useCase.List(c.Request().Context(), application.ListCommunityEventsInput{
+		CommunityID:         c.Param("id"),
+		Since:               since,
+		Types:               types,
+		Limit:               limit,
+		RequesterExternalID: userExternalID,
+		Admin:               admin,
+	})
+	if err != nil {
+		return mapCommunityEventError(err)
+	}
+
+	resp := communityEventsResponse{
+		CommunityID: output.CommunityID,
+		Since:       output.Since,
+		Count:       len(output.Events),
+		Events:      make([]communityEventResponse, len(output.Events)),
+	}
+	for i, e := range output.Events {
+		resp.Events[i] = communityEventResponse{
+			ID:         e.ID,
+			UserID:     e.UserID,
+			EventType:  e.EventType,
+			Weight:     e.Weight,
+			Metadata:   e.Metadata,
+			OccurredAt: e.OccurredAt,
+			CreatedAt:  e.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// parseSince reads an RFC 3339 timestamp, or a duration counted back from now.
+// empty returns the zero time, leaving the default to the use case.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// mapCommunityEventError converts use case errors to HTTP errors
+func mapCommunityEventError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can list its events")
+	case errors.Is(err, domain.ErrInvalidEventType):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -12,20 +14,40 @@ import (
 	"github.com/joacominatel/pulse/internal/domain"
 )
 
+// CommunitySummaries looks up the rolled-up listing data of communities.
+// implemented by application.CommunitySummaryUseCase.
+type CommunitySummaries interface {
+	ForCommunities(ctx context.Context, communities []*domain.Community) map[domain.CommunityID]*domain.CommunitySummary
+}
+
 // CommunityHandler handles community-related HTTP endpoints.
 type CommunityHandler struct {
 	repo                   domain.CommunityRepository
 	createCommunityUseCase *application.CreateCommunityUseCase
+	statsUseCase           *application.CommunityStatsUseCase
+	visibilityUseCase      *application.CommunityVisibilityUseCase
+	detailsUseCase         *application.CommunityDetailsUseCase
+	summaries              CommunitySummaries
 }
 
 // NewCommunityHandler creates a new CommunityHandler.
+// stats, visibility, update and deactivate routes are only registered when their use case is set.
+// without summaries, listings leave out member counts and recent activity.
 func NewCommunityHandler(
 	repo domain.CommunityRepository,
 	createCommunityUseCase *application.CreateCommunityUseCase,
+	statsUseCase *application.CommunityStatsUseCase,
+	visibilityUseCase *application.CommunityVisibilityUseCase,
+	detailsUseCase *application.CommunityDetailsUseCase,
+	summaries CommunitySummaries,
 ) *CommunityHandler {
 	return &CommunityHandler{
 		repo:                   repo,
 		createCommunityUseCase: createCommunityUseCase,
+		statsUseCase:           statsUseCase,
+		visibilityUseCase:      visibilityUseCase,
+		detailsUseCase:         detailsUseCase,
+		summaries:              summaries,
 	}
 }
 
@@ -33,6 +55,19 @@ func NewCommunityHandler(
 func (h *CommunityHandler) RegisterRoutes(g *echo.Group) {
 	g.GET("/communities", h.ListByMomentum)
 	g.POST("/communities", h.Create)
+	g.GET("/communities/check-slug", h.CheckSlug)
+
+	if h.statsUseCase != nil {
+		g.GET("/communities/:id/stats", h.Stats)
+		g.GET("/communities/:id/momentum/history", h.MomentumHistory)
+	}
+	if h.visibilityUseCase != nil {
+		g.PUT("/communities/:id/visibility", h.UpdateVisibility)
+	}
+	if h.detailsUseCase != nil {
+		g.PATCH("/communities/:id", h.Update)
+		g.DELETE("/communities/:id", h.Deactivate)
+	}
 }
 
 // communityResponse is the API representation of a community.
@@ -42,11 +77,18 @@ type communityResponse struct {
 	Name              string    `json:"name"`
 	Description       string    `json:"description,omitempty"`
 	CreatorID         string    `json:"creator_id"`
+	OrganizationID    *string   `json:"organization_id,omitempty"`
 	AvatarURL         string    `json:"avatar_url,omitempty"`
 	IsActive          bool      `json:"is_active"`
+	Visibility        string    `json:"visibility"`
 	CurrentMomentum   float64   `json:"current_momentum"`
 	MomentumUpdatedAt *string   `json:"momentum_updated_at,omitempty"`
 	CreatedAt         time.Time `json:"created_at"`
+
+	// from the summary rollup, only in listings and left out until the community is rolled up
+	MemberCount    *int       `json:"member_count,omitempty"`
+	EventsLastDay  *int64     `json:"events_last_24h,omitempty"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
 }
 
 // listCommunitiesResponse is the API response for listing communities.
@@ -58,17 +100,97 @@ type listCommunitiesResponse struct {
 
 // createCommunityRequest is the API request for creating a community.
 type createCommunityRequest struct {
-	Slug        string `json:"slug"`
-	Name        string `json:"name"`
+	Slug        string `json:"slug" validate:"required,min=3,max=100"`
+	Name        string `json:"name" validate:"required,max=255"`
 	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty" validate:"omitempty,oneof=public unlisted private"`
+}
+
+// updateVisibilityRequest is the API request for changing a community's visibility.
+type updateVisibilityRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=public unlisted private"`
+}
+
+// updateCommunityRequest is the API request for editing a community. omitted fields are
+// left as they are, an empty avatar_url removes the avatar.
+type updateCommunityRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,max=255"`
+	Description *string `json:"description,omitempty"`
+	AvatarURL   *string `json:"avatar_url,omitempty" validate:"omitempty,max=2048"`
+}
+
+// communityStatsResponse is the API response for a community's stats.
+type communityStatsResponse struct {
+	CommunityID       string  `json:"community_id"`
+	Visibility        string  `json:"visibility"`
+	CurrentMomentum   float64 `json:"current_momentum"`
+	MomentumUpdatedAt *string `json:"momentum_updated_at,omitempty"`
+	EventsLastDay     int64   `json:"events_last_24h"`
+	EventsLastWeek    int64   `json:"events_last_7d"`
+
+	// share of listed communities at or below current_momentum, left out without redis
+	MomentumPercentile *float64 `json:"momentum_percentile,omitempty"`
+
+	// approximate, left out without redis
+	UniqueUsersLastDay  *int64 `json:"unique_users_last_24h,omitempty"`
+	UniqueUsersLastWeek *int64 `json:"unique_users_last_7d,omitempty"`
+}
+
+// momentumHistoryResponse is the API response for a community's momentum history.
+type momentumHistoryResponse struct {
+	CommunityID   string                  `json:"community_id"`
+	Window        string                  `json:"window"`
+	BucketSeconds int64                   `json:"bucket_seconds"`
+	Since         string                  `json:"since"`
+	Points        []momentumPointResponse `json:"points"`
+}
+
+// momentumPointResponse is one bucket of a momentum history.
+type momentumPointResponse struct {
+	Start   string  `json:"start"`
+	Average float64 `json:"average"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Last    float64 `json:"last"`
+	Samples int     `json:"samples"`
 }
 
 // createCommunityResponse is the API response for creating a community.
 type createCommunityResponse struct {
-	ID        string `json:"id"`
+	ID         string `json:"id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	CreatorID  string `json:"creator_id"`
+	Visibility string `json:"visibility"`
+}
+
+// slugAvailabilityResponse is the API response for a slug check.
+type slugAvailabilityResponse struct {
 	Slug      string `json:"slug"`
-	Name      string `json:"name"`
-	CreatorID string `json:"creator_id"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"` // invalid or taken
+	Detail    string `json:"detail,omitempty"`
+}
+
+// CheckSlug reports whether a slug is valid and not used by another community.
+// GET /api/v1/communities/check-slug?slug=
+func (h *CommunityHandler) CheckSlug(c echo.Context) error {
+	slug := c.QueryParam("slug")
+	if slug == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "slug is required")
+	}
+
+	output, err := h.createCommunityUseCase.CheckSlug(c.Request().Context(), slug)
+	if err != nil {
+		return mapDomainError(err)
+	}
+
+	return c.JSON(http.StatusOK, slugAvailabilityResponse{
+		Slug:      output.Slug,
+		Available: output.Available,
+		Reason:    output.Reason,
+		Detail:    output.Detail,
+	})
 }
 
 // Create creates a new community.
@@ -83,16 +205,8 @@ func (h *CommunityHandler) Create(c echo.Context) error {
 
 	// parse request body
 	var req createCommunityRequest
-	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
-	}
-
-	// validate required fields
-	if req.Slug == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "slug is required")
-	}
-	if req.Name == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
 	// execute use case
@@ -100,6 +214,7 @@ func (h *CommunityHandler) Create(c echo.Context) error {
 		Slug:              req.Slug,
 		Name:              req.Name,
 		Description:       req.Description,
+		Visibility:        req.Visibility,
 		CreatorExternalID: creatorExternalID,
 	})
 
@@ -108,10 +223,11 @@ func (h *CommunityHandler) Create(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusCreated, createCommunityResponse{
-		ID:        output.CommunityID,
-		Slug:      output.Slug,
-		Name:      output.Name,
-		CreatorID: output.CreatorID,
+		ID:         output.CommunityID,
+		Slug:       output.Slug,
+		Name:       output.Name,
+		CreatorID:  output.CreatorID,
+		Visibility: output.Visibility,
 	})
 }
 
@@ -130,16 +246,23 @@ func mapCreateCommunityError(err error) *echo.HTTPError {
 		return echo.NewHTTPError(http.StatusBadRequest, "slug must be at most 100 characters")
 	case errors.Is(err, domain.ErrSlugInvalid):
 		return echo.NewHTTPError(http.StatusBadRequest, "slug must contain only lowercase letters, numbers, and hyphens")
+	case errors.Is(err, domain.ErrNameReserved):
+		return echo.NewHTTPError(http.StatusBadRequest, "slug is reserved")
+	case errors.Is(err, domain.ErrDescriptionTooLong):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	case errors.Is(err, domain.ErrCommunityNameEmpty):
 		return echo.NewHTTPError(http.StatusBadRequest, "name cannot be empty")
 	case errors.Is(err, domain.ErrCommunityNameTooLong):
 		return echo.NewHTTPError(http.StatusBadRequest, "name must be at most 255 characters")
+	case errors.Is(err, domain.ErrInvalidVisibility):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	default:
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create community")
 	}
 }
 
-// ListByMomentum returns communities ranked by current momentum.
+// ListByMomentum returns public communities ranked by current momentum.
+// unlisted and private communities are never listed.
 // GET /api/v1/communities?limit=20&offset=0
 func (h *CommunityHandler) ListByMomentum(c echo.Context) error {
 	// parse pagination params with defaults
@@ -158,22 +281,198 @@ func (h *CommunityHandler) ListByMomentum(c echo.Context) error {
 		}
 	}
 
-	communities, err := h.repo.ListByMomentum(c.Request().Context(), limit, offset)
+	communities, err := h.repo.ListPublicByMomentum(c.Request().Context(), limit, offset)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch communities")
 	}
 
-	response := listCommunitiesResponse{
+	return c.JSON(http.StatusOK, toCommunityListResponse(c.Request().Context(), h.summaries, communities, limit, offset))
+}
+
+// Stats returns a community's momentum and recent activity.
+// GET /api/v1/communities/:id/stats
+// private communities only answer their members
+func (h *CommunityHandler) Stats(c echo.Context) error {
+	output, err := h.statsUseCase.Execute(c.Request().Context(), c.Param("id"), GetUserExternalID(c))
+	if err != nil {
+		return mapCommunityAccessError(err)
+	}
+
+	resp := communityStatsResponse{
+		CommunityID:         output.CommunityID,
+		Visibility:          output.Visibility.String(),
+		CurrentMomentum:     output.Momentum,
+		EventsLastDay:       output.EventsLastDay,
+		EventsLastWeek:      output.EventsLastWeek,
+		MomentumPercentile:  output.MomentumPercentile,
+		UniqueUsersLastDay:  output.UniqueUsersLastDay,
+		UniqueUsersLastWeek: output.UniqueUsersLastWeek,
+	}
+	if t := output.MomentumUpdatedAt; t != nil {
+		formatted := t.Format(time.RFC3339)
+		resp.MomentumUpdatedAt = &formatted
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// MomentumHistory returns a community's momentum over a window, bucketed for charting.
+// GET /api/v1/communities/:id/momentum/history?window=24h
+// window is one of 1h, 6h, 24h (default), 7d or 30d. buckets without a calculation are left out.
+func (h *CommunityHandler) MomentumHistory(c echo.Context) error {
+	output, err := h.statsUseCase.History(c.Request().Context(), c.Param("id"), c.QueryParam("window"), GetUserExternalID(c))
+	if err != nil {
+		if errors.Is(err, application.ErrMomentumHistoryDisabled) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return mapCommunityAccessError(err)
+	}
+
+	resp := momentumHistoryResponse{
+		CommunityID:   output.CommunityID,
+		Window:        output.Window.String(),
+		BucketSeconds: int64(output.Window.Bucket().Seconds()),
+		Since:         output.Since.UTC().Format(time.RFC3339),
+		Points:        make([]momentumPointResponse, 0, len(output.Buckets)),
+	}
+	for _, b := range output.Buckets {
+		resp.Points = append(resp.Points, momentumPointResponse{
+			Start:   b.Start.UTC().Format(time.RFC3339),
+			Average: b.Average,
+			Min:     b.Min,
+			Max:     b.Max,
+			Last:    b.Last,
+			Samples: b.Samples,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateVisibility changes who can find and see a community.
+// PUT /api/v1/communities/:id/visibility
+// requires the community creator or an admin of its organization
+func (h *CommunityHandler) UpdateVisibility(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateVisibilityRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	community, err := h.visibilityUseCase.Update(c.Request().Context(), c.Param("id"), req.Visibility, userExternalID)
+	if err != nil {
+		return mapCommunityAccessError(err)
+	}
+	return c.JSON(http.StatusOK, toCommunityResponse(community))
+}
+
+// Update edits a community's name, description or avatar.
+// PATCH /api/v1/communities/:id
+// requires the community creator
+func (h *CommunityHandler) Update(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateCommunityRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+	if req.AvatarURL != nil && *req.AvatarURL != "" && !isHTTPURL(*req.AvatarURL) {
+		return echo.NewHTTPError(http.StatusBadRequest, "avatar_url must be an http or https url")
+	}
+
+	community, err := h.detailsUseCase.Update(c.Request().Context(), application.UpdateCommunityInput{
+		CommunityID:         c.Param("id"),
+		Name:                req.Name,
+		Description:         req.Description,
+		AvatarURL:           req.AvatarURL,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapCommunityDetailsError(err)
+	}
+	return c.JSON(http.StatusOK, toCommunityResponse(community))
+}
+
+// Deactivate stops a community from accepting events and takes it off the leaderboard.
+// DELETE /api/v1/communities/:id
+// requires the community creator
+func (h *CommunityHandler) Deactivate(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if _, err := h.detailsUseCase.Deactivate(c.Request().Context(), c.Param("id"), userExternalID); err != nil {
+		return mapCommunityDetailsError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// mapCommunityDetailsError converts community update errors to HTTP errors
+func mapCommunityDetailsError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community creator can change it")
+	case errors.Is(err, domain.ErrCommunityNameEmpty):
+		return echo.NewHTTPError(http.StatusBadRequest, "name cannot be empty")
+	case errors.Is(err, domain.ErrCommunityNameTooLong):
+		return echo.NewHTTPError(http.StatusBadRequest, "name must be at most 255 characters")
+	case errors.Is(err, domain.ErrDescriptionTooLong):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+// isHTTPURL reports whether s is an absolute http or https url.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// mapCommunityAccessError converts visibility and membership errors to HTTP errors
+func mapCommunityAccessError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrCommunityPrivate):
+		return echo.NewHTTPError(http.StatusForbidden, "community is private - members only")
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can change its visibility")
+	case errors.Is(err, domain.ErrInvalidVisibility):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+// toCommunityListResponse converts a page of communities, adding their summaries when
+// summaries is set.
+func toCommunityListResponse(ctx context.Context, summaries CommunitySummaries, communities []*domain.Community, limit, offset int) listCommunitiesResponse {
+	var bySummary map[domain.CommunityID]*domain.CommunitySummary
+	if summaries != nil {
+		bySummary = summaries.ForCommunities(ctx, communities)
+	}
+
+	resp := listCommunitiesResponse{
 		Communities: make([]communityResponse, 0, len(communities)),
 		Limit:       limit,
 		Offset:      offset,
 	}
-
 	for _, comm := range communities {
-		response.Communities = append(response.Communities, toCommunityResponse(comm))
+		item := toCommunityResponse(comm)
+		if summary, ok := bySummary[comm.ID()]; ok {
+			memberCount, eventsLastDay := summary.MemberCount(), summary.EventsLastDay()
+			item.MemberCount = &memberCount
+			item.EventsLastDay = &eventsLastDay
+			item.LastActivityAt = summary.LastActivityAt()
+		}
+		resp.Communities = append(resp.Communities, item)
 	}
-
-	return c.JSON(http.StatusOK, response)
+	return resp
 }
 
 // toCommunityResponse converts a domain community to API response.
@@ -186,10 +485,16 @@ func toCommunityResponse(c *domain.Community) communityResponse {
 		CreatorID:       c.CreatorID().String(),
 		AvatarURL:       c.AvatarURL(),
 		IsActive:        c.IsActive(),
+		Visibility:      c.Visibility().String(),
 		CurrentMomentum: c.CurrentMomentum().Value(),
 		CreatedAt:       c.CreatedAt(),
 	}
 
+	if orgID := c.OrganizationID(); orgID != nil {
+		id := orgID.String()
+		resp.OrganizationID = &id
+	}
+
 	if t := c.MomentumUpdatedAt(); t != nil {
 		formatted := t.Format(time.RFC3339)
 		resp.MomentumUpdatedAt = &formatted
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed dashboard/*
+var dashboardFiles embed.FS
+
+// dashboardCSP keeps the dashboard to its own scripts and the same origin's endpoints,
+// so nothing injected into the page can send the admin token elsewhere.
+const dashboardCSP = "default-src 'none'; script-src 'self'; style-src 'self'; connect-src 'self'; img-src 'self'; form-action 'none'; frame-ancestors 'none'; base-uri 'none'"
+
+// RegisterDashboardRoutes serves the admin dashboard at /admin, a single page built into
+// the binary. the page holds no data: it asks for an admin token and reads every panel
+// from the admin, leaderboard, /statusz and /metrics endpoints, which do the gating.
+func RegisterDashboardRoutes(e *echo.Echo) {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // the embedded directory is always there
+	}
+	assets := http.FileServer(http.FS(files))
+
+	g := e.Group("/admin", dashboardHeaders)
+	g.GET("", func(c echo.Context) error {
+		return c.Redirect(http.StatusMovedPermanently, "/admin/")
+	})
+	g.GET("/*", echo.WrapHandler(http.StripPrefix("/admin", assets)))
+}
+
+// dashboardHeaders sets the security and caching headers of every dashboard file.
+// no-store keeps a new release's page from running against an old script.
+func dashboardHeaders(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h := c.Response().Header()
+		h.Set("Content-Security-Policy", dashboardCSP)
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("Cache-Control", "no-store")
+		return next(c)
+	}
+}
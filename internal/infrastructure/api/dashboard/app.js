@@ -0,0 +1,217 @@
+// Pulse admin dashboard. every panel reads an existing JSON endpoint with the admin
+// token kept in sessionStorage; the page itself holds no data.
+"use strict";
+
+const TOKEN_KEY = "pulse_admin_token";
+const REFRESH_MS = 5000;
+
+let timer = null;
+let lastIngested = null; // {total, at} of the previous /metrics read
+
+const $ = (id) => document.getElementById(id);
+
+class AuthError extends Error {}
+
+async function api(path) {
+  const res = await fetch(path, {
+    headers: { Authorization: "Bearer " + sessionStorage.getItem(TOKEN_KEY) },
+    cache: "no-store",
+  });
+  if (res.status === 401 || res.status === 403) {
+    throw new AuthError(res.status === 403 ? "this token isn't an admin token" : "the token was rejected");
+  }
+  if (!res.ok) {
+    return null; // the feature is off on this instance, or failed; the panel says so
+  }
+  return res;
+}
+
+async function json(path) {
+  const res = await api(path);
+  return res ? res.json() : null;
+}
+
+// sumMetric adds up every series of a counter or gauge in prometheus text format.
+function sumMetric(text, name) {
+  let sum = null;
+  for (const line of text.split("\n")) {
+    if (line.startsWith(name + " ") || line.startsWith(name + "{")) {
+      sum = (sum || 0) + Number(line.slice(line.lastIndexOf(" ") + 1));
+    }
+  }
+  return sum;
+}
+
+function fmt(n, digits = 0) {
+  return n == null ? "-" : n.toLocaleString(undefined, { maximumFractionDigits: digits });
+}
+
+function when(t) {
+  return t ? new Date(t).toLocaleString() : "-";
+}
+
+function fill(tbody, rows, columns) {
+  tbody.replaceChildren();
+  if (!rows || rows.length === 0) {
+    const tr = tbody.insertRow();
+    const td = tr.insertCell();
+    td.colSpan = columns;
+    td.className = "empty";
+    td.textContent = rows ? "nothing yet" : "unavailable";
+    return;
+  }
+  for (const cells of rows) {
+    const tr = tbody.insertRow();
+    for (const cell of cells) {
+      const td = tr.insertCell();
+      if (cell && typeof cell === "object") {
+        td.textContent = cell.text;
+        td.className = cell.className;
+      } else {
+        td.textContent = cell;
+      }
+    }
+  }
+}
+
+async function loadIngestion() {
+  const res = await api("/metrics");
+  if (!res) {
+    for (const id of ["ingest-rate", "ingest-total", "ingest-buffer", "spikes-total"]) {
+      $(id).textContent = "metrics disabled";
+    }
+    return;
+  }
+  const text = await res.text();
+  const total = sumMetric(text, "pulse_events_ingested_total");
+  const now = Date.now();
+  if (lastIngested && total != null && total >= lastIngested.total) {
+    $("ingest-rate").textContent = fmt((total - lastIngested.total) / ((now - lastIngested.at) / 1000), 1);
+  }
+  lastIngested = total == null ? null : { total, at: now };
+
+  $("ingest-total").textContent = fmt(total);
+  const size = sumMetric(text, "pulse_buffer_size");
+  const capacity = sumMetric(text, "pulse_buffer_capacity");
+  $("ingest-buffer").textContent = size == null ? "-" : fmt(size) + " / " + fmt(capacity);
+  $("spikes-total").textContent = fmt(sumMetric(text, "pulse_momentum_spikes_total"));
+}
+
+async function loadWorkers() {
+  const [worker, status] = await Promise.all([
+    json("/api/v1/admin/momentum-worker"),
+    json("/statusz"),
+  ]);
+
+  if (worker) {
+    $("momentum-worker").textContent =
+      (worker.paused ? "paused" : worker.running ? "running a cycle" : "idle") +
+      ", every " + worker.interval_seconds + "s";
+    $("momentum-last").textContent = when(worker.last_finished_at);
+  } else {
+    $("momentum-worker").textContent = "unavailable";
+    $("momentum-last").textContent = "-";
+  }
+
+  fill($("components"), status && status.components.map((c) => [
+    c.name,
+    c.kind,
+    { text: c.up ? "up" : "down", className: c.up ? "up" : "down" },
+    fmt(c.uptime_percent, 1) + "%",
+    when(c.last_beat),
+  ]), 5);
+}
+
+async function loadRuns() {
+  const data = await json("/api/v1/admin/momentum/runs?limit=10");
+  fill($("runs"), data && data.runs.map((r) => [
+    when(r.started_at),
+    r.triggered_by,
+    fmt(r.duration_ms) + " ms",
+    fmt(r.processed),
+    { text: r.error || fmt(r.failed), className: r.error || r.failed ? "down" : "" },
+  ]), 5);
+}
+
+async function loadLeaderboard() {
+  const data = await json("/api/v1/leaderboard?limit=20");
+  fill($("leaderboard"), data && data.entries.map((e) => [
+    e.pinned ? "pinned" : e.rank,
+    e.community.name,
+    fmt(e.momentum, 2),
+  ]), 3);
+}
+
+async function loadSpikes() {
+  const since = new Date(Date.now() - 60 * 60 * 1000).toISOString();
+  const [changes, anomalies] = await Promise.all([
+    json("/api/v1/leaderboard/changes?positions=2&since=" + encodeURIComponent(since)),
+    json("/api/v1/admin/anomalies?status=pending&limit=20"),
+  ]);
+
+  const movers = changes && changes.changes
+    .filter((c) => c.change === "entered" || c.movement > 0)
+    .map((c) => [
+      c.community.name,
+      c.change === "entered" ? "entered" : "up " + c.movement,
+      c.rank,
+      fmt(c.previous_momentum, 2) + " → " + fmt(c.momentum, 2),
+    ]);
+  fill($("movers"), movers, 4);
+
+  fill($("anomalies"), anomalies && anomalies.anomalies.map((a) => [
+    when(a.detected_at),
+    a.community_id,
+    fmt(a.observed_events),
+    fmt(a.expected_events, 1),
+    fmt(a.ratio, 1) + "×",
+  ]), 5);
+}
+
+async function refresh() {
+  try {
+    await Promise.all([loadIngestion(), loadWorkers(), loadRuns(), loadLeaderboard(), loadSpikes()]);
+    $("updated").textContent = "updated " + new Date().toLocaleTimeString();
+  } catch (err) {
+    if (err instanceof AuthError) {
+      signOut(err.message);
+      return;
+    }
+    $("updated").textContent = "refresh failed: " + err.message;
+  }
+}
+
+function signIn() {
+  $("sign-in").hidden = true;
+  $("dashboard").hidden = false;
+  $("sign-out").hidden = false;
+  lastIngested = null;
+  refresh();
+  timer = setInterval(refresh, REFRESH_MS);
+}
+
+function signOut(reason) {
+  clearInterval(timer);
+  sessionStorage.removeItem(TOKEN_KEY);
+  $("dashboard").hidden = true;
+  $("sign-out").hidden = true;
+  $("sign-in").hidden = false;
+  $("sign-in-error").textContent = reason || "";
+  $("updated").textContent = "";
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  $("sign-in").addEventListener("submit", (e) => {
+    e.preventDefault();
+    sessionStorage.setItem(TOKEN_KEY, $("token").value.trim());
+    $("token").value = "";
+    signIn();
+  });
+  $("sign-out").addEventListener("click", () => signOut());
+
+  if (sessionStorage.getItem(TOKEN_KEY)) {
+    signIn();
+  } else {
+    signOut();
+  }
+});
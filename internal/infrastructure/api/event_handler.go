@@ -1,7 +1,11 @@
 package api
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -12,26 +16,35 @@ import (
 // EventHandler handles activity event related HTTP requests.
 type EventHandler struct {
 	ingestUseCase *application.IngestEventUseCase
+	groupUseCase  *application.IngestEventGroupUseCase
 }
 
 // NewEventHandler creates a new EventHandler.
-func NewEventHandler(ingestUseCase *application.IngestEventUseCase) *EventHandler {
+// groupUseCase is optional, event groups aren't served without it.
+func NewEventHandler(ingestUseCase *application.IngestEventUseCase, groupUseCase *application.IngestEventGroupUseCase) *EventHandler {
 	return &EventHandler{
 		ingestUseCase: ingestUseCase,
+		groupUseCase:  groupUseCase,
 	}
 }
 
 // RegisterRoutes registers the event routes on the given group.
 func (h *EventHandler) RegisterRoutes(g *echo.Group) {
 	g.POST("/events", h.IngestEvent)
+	if h.groupUseCase != nil {
+		g.POST("/events/groups", h.IngestEventGroup)
+	}
 }
 
 // IngestEventRequest is the request body for ingesting an activity event.
 type IngestEventRequest struct {
-	CommunityID string         `json:"community_id" validate:"required"`
-	EventType   string         `json:"event_type" validate:"required"`
-	Weight      *float64       `json:"weight,omitempty"`
+	CommunityID string         `json:"community_id" validate:"required,uuid"`
+	EventType   string         `json:"event_type" validate:"required,max=50"`
+	Weight      *float64       `json:"weight,omitempty" validate:"omitempty,gte=0"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
+	// OccurredAt is when the event happened (RFC 3339), for delayed or backfilled events.
+	// defaults to when it's received.
+	OccurredAt *time.Time `json:"occurred_at,omitempty"`
 }
 
 // IngestEventResponse is the response for a successfully ingested event.
@@ -41,6 +54,13 @@ type IngestEventResponse struct {
 	EventType   string  `json:"event_type"`
 	Weight      float64 `json:"weight"`
 	Accepted    bool    `json:"accepted"`
+	Degraded    bool    `json:"degraded,omitempty"`
+	Muted       bool    `json:"muted,omitempty"`
+	// Persisted is only set with ack=persisted, false when the ack timeout passed first.
+	Persisted *bool `json:"persisted,omitempty"`
+	// QueueDepth and QueueCapacity describe the buffer after this event, left out in sync mode.
+	QueueDepth    *int `json:"queue_depth,omitempty"`
+	QueueCapacity *int `json:"queue_capacity,omitempty"`
 }
 
 // IngestEvent handles POST /api/v1/events
@@ -52,24 +72,33 @@ type IngestEventResponse struct {
 // @Accept json
 // @Produce json
 // @Param body body IngestEventRequest true "Event data"
+// @Param ack query string false "queued (default) or persisted, also read from X-Pulse-Ack"
+// @Header all {string} X-Pulse-Accepted-Mode "sync or async"
+// @Header all {integer} X-Pulse-Queue-Depth "events waiting in the buffer, async mode only"
 // @Success 201 {object} IngestEventResponse
+// @Success 202 {object} IngestEventResponse "user is muted and the event dropped, or ack=persisted timed out"
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse "buffer full, or ack=persisted and saving failed"
 // @Router /api/v1/events [post]
 func (h *EventHandler) IngestEvent(c echo.Context) error {
 	var req IngestEventRequest
-	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	// validate required fields
-	if req.CommunityID == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "community_id is required")
+	ackParam := c.QueryParam("ack")
+	if ackParam == "" {
+		ackParam = c.Request().Header.Get(HeaderAck)
 	}
-	if req.EventType == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "event_type is required")
+	ack, err := application.ParseAckLevel(ackParam)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	// get user from context (optional for events - some can be anonymous)
@@ -81,29 +110,233 @@ func (h *EventHandler) IngestEvent(c echo.Context) error {
 
 	// execute use case
 	output, err := h.ingestUseCase.Execute(c.Request().Context(), application.IngestEventInput{
-		CommunityID: req.CommunityID,
-		UserID:      userIDPtr,
-		EventType:   req.EventType,
-		Weight:      req.Weight,
-		Metadata:    req.Metadata,
+		CommunityID:    req.CommunityID,
+		UserID:         userIDPtr,
+		EventType:      req.EventType,
+		Weight:         req.Weight,
+		Metadata:       req.Metadata,
+		OccurredAt:     req.OccurredAt,
+		Region:         c.Request().Header.Get(HeaderRegion),
+		OrganizationID: GetOrganizationScope(c),
+		Ack:            ack,
 	})
 
+	// set on errors too, a producer seeing the buffer fill can back off before it's full
+	depth, capacity := h.queueHeaders(c)
+
 	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		switch {
+		case errors.Is(err, application.ErrEventNotPersisted):
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "event was not persisted, try again")
+		case errors.Is(err, application.ErrEventQuarantined):
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
 		return mapDomainError(err)
 	}
 
-	return c.JSON(http.StatusCreated, IngestEventResponse{
-		EventID:     output.EventID,
-		CommunityID: output.CommunityID,
-		EventType:   output.EventType,
-		Weight:      output.Weight,
-		Accepted:    output.Accepted,
+	if output.Degraded {
+		c.Response().Header().Set(HeaderQuota, "exceeded")
+	}
+
+	// muted users get a 202 so clients don't retry, but the event is not stored
+	if output.Muted {
+		return c.JSON(http.StatusAccepted, IngestEventResponse{
+			CommunityID:   output.CommunityID,
+			EventType:     output.EventType,
+			Accepted:      false,
+			Muted:         true,
+			QueueDepth:    depth,
+			QueueCapacity: capacity,
+		})
+	}
+
+	resp := IngestEventResponse{
+		EventID:       output.EventID,
+		CommunityID:   output.CommunityID,
+		EventType:     output.EventType,
+		Weight:        output.Weight,
+		Accepted:      output.Accepted,
+		Degraded:      output.Degraded,
+		QueueDepth:    depth,
+		QueueCapacity: capacity,
+	}
+	if ack == application.AckPersisted {
+		resp.Persisted = &output.Persisted
+		// still queued: the client can't tell yet whether the event will be saved
+		if !output.Persisted {
+			return c.JSON(http.StatusAccepted, resp)
+		}
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// queueHeaders sets the accepted mode and queue depth headers and returns the depth and
+// capacity for the response body, nil in sync mode.
+func (h *EventHandler) queueHeaders(c echo.Context) (depth, capacity *int) {
+	header := c.Response().Header()
+	if !h.ingestUseCase.Async() {
+		header.Set(HeaderAcceptedMode, "sync")
+		return nil, nil
+	}
+	d, cp := h.ingestUseCase.QueueDepth()
+	header.Set(HeaderAcceptedMode, "async")
+	header.Set(HeaderQueueDepth, strconv.Itoa(d))
+	return &d, &cp
+}
+
+// IngestEventGroupRequest is the request body for ingesting the events of one client action.
+type IngestEventGroupRequest struct {
+	Events []IngestEventGroupItem `json:"events" validate:"required,min=1,max=10,dive"`
+}
+
+// IngestEventGroupItem is one event of a group, the user and region are the request's.
+type IngestEventGroupItem struct {
+	CommunityID string         `json:"community_id" validate:"required,uuid"`
+	EventType   string         `json:"event_type" validate:"required,max=50"`
+	Weight      *float64       `json:"weight,omitempty" validate:"omitempty,gte=0"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	OccurredAt  *time.Time     `json:"occurred_at,omitempty"`
+}
+
+// IngestEventGroupResponse is the response for an ingested event group.
+type IngestEventGroupResponse struct {
+	// Events are the saved events, without the muted ones. for a duplicate only event_id is set.
+	Events    []IngestEventResponse `json:"events"`
+	Muted     int                   `json:"muted,omitempty"`
+	Duplicate bool                  `json:"duplicate,omitempty"`
+}
+
+// IngestEventGroup handles POST /api/v1/events/groups
+// ingests the events of one client action all or nothing.
+//
+// @Summary Ingest event group
+// @Description Saves up to 10 events atomically, a retry with the same Idempotency-Key returns the saved events
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string true "Unique per client action, remembered for 24 hours"
+// @Param body body IngestEventGroupRequest true "Events"
+// @Success 201 {object} IngestEventGroupResponse
+// @Success 200 {object} IngestEventGroupResponse "already ingested with this key"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/events/groups [post]
+func (h *EventHandler) IngestEventGroup(c echo.Context) error {
+	var req IngestEventGroupRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	key := c.Request().Header.Get(HeaderIdempotencyKey)
+	if key == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, HeaderIdempotencyKey+" header is required")
+	}
+
+	userID := GetUserExternalID(c)
+	var userIDPtr *string
+	if userID != "" {
+		userIDPtr = &userID
+	}
+
+	items := make([]application.EventGroupItem, len(req.Events))
+	for i, event := range req.Events {
+		items[i] = application.EventGroupItem{
+			CommunityID: event.CommunityID,
+			EventType:   event.EventType,
+			Weight:      event.Weight,
+			Metadata:    event.Metadata,
+			OccurredAt:  event.OccurredAt,
+		}
+	}
+
+	output, err := h.groupUseCase.Execute(c.Request().Context(), application.IngestEventGroupInput{
+		IdempotencyKey: key,
+		UserID:         userIDPtr,
+		Region:         c.Request().Header.Get(HeaderRegion),
+		OrganizationID: GetOrganizationScope(c),
+		Events:         items,
 	})
+	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		return mapDomainError(err)
+	}
+
+	resp := IngestEventGroupResponse{
+		Events:    make([]IngestEventResponse, len(output.Events)),
+		Muted:     output.Muted,
+		Duplicate: output.Duplicate,
+	}
+	for i, event := range output.Events {
+		resp.Events[i] = IngestEventResponse{
+			EventID:     event.EventID,
+			CommunityID: event.CommunityID,
+			EventType:   event.EventType,
+			Weight:      event.Weight,
+			Accepted:    event.Accepted,
+			Degraded:    event.Degraded,
+		}
+		if event.Degraded {
+			c.Response().Header().Set(HeaderQuota, "exceeded")
+		}
+	}
+
+	if output.Duplicate {
+		return c.JSON(http.StatusOK, resp)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// HeaderIdempotencyKey identifies the client action of an event group.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// HeaderRegion sets the region of events whose metadata doesn't have one.
+const HeaderRegion = "X-Pulse-Region"
+
+// HeaderAck asks for an ack level like the ack query parameter, which takes precedence.
+const HeaderAck = "X-Pulse-Ack"
+
+// HeaderAcceptedMode is sync when an event was saved before the response, async when it was queued.
+const HeaderAcceptedMode = "X-Pulse-Accepted-Mode"
+
+// HeaderQueueDepth is how many events wait in the ingestion buffer, set in async mode.
+const HeaderQueueDepth = "X-Pulse-Queue-Depth"
+
+// HeaderQuota is set to "exceeded" when an event was accepted over quota at reduced weight.
+const HeaderQuota = "X-Pulse-Quota"
+
+// quotaExceeded answers 429, with Retry-After pointing at the next UTC midnight when the quota resets.
+func quotaExceeded(c echo.Context, err *application.QuotaExceededError) error {
+	retryAfter := int64(math.Ceil(time.Until(err.ResetAt).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Response().Header().Set(echo.HeaderRetryAfter, strconv.FormatInt(retryAfter, 10))
+	c.Response().Header().Set(HeaderQuota, "exceeded")
+	return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
 }
 
 // mapDomainError maps domain/application errors to HTTP errors.
 func mapDomainError(err error) error {
 	switch {
+	case errors.Is(err, application.ErrCommunityNotInOrganization):
+		return echo.NewHTTPError(http.StatusForbidden, "api key is scoped to another organization")
+	case errors.Is(err, application.ErrCommunityPrivate):
+		return echo.NewHTTPError(http.StatusForbidden, "community is private - members only")
+	case errors.Is(err, application.ErrUserBanned):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrNameReserved):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	case isNotFoundError(err):
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	case isValidationError(err):
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// EventImportQueue hands uploaded files to the import worker, which deletes them once imported.
+// implemented by worker.EventImportWorker.
+type EventImportQueue interface {
+	Enqueue(imp *domain.EventImport, path string) error
+}
+
+// EventImportHandler handles bulk event imports from file uploads.
+type EventImportHandler struct {
+	useCase       *application.EventImportUseCase
+	queue         EventImportQueue
+	maxBytes      int64
+	uploadTimeout time.Duration
+}
+
+// NewEventImportHandler creates a new EventImportHandler.
+// maxBytes bounds an upload, uploadTimeout replaces the server read timeout for it.
+func NewEventImportHandler(useCase *application.EventImportUseCase, queue EventImportQueue, maxBytes int64, uploadTimeout time.Duration) *EventImportHandler {
+	return &EventImportHandler{
+		useCase:       useCase,
+		queue:         queue,
+		maxBytes:      maxBytes,
+		uploadTimeout: uploadTimeout,
+	}
+}
+
+// RegisterRoutes registers the import routes on the given group.
+// all routes require authentication.
+func (h *EventImportHandler) RegisterRoutes(g *echo.Group) {
+	g.POST("/events/import", h.Import)
+	g.GET("/events/import/:id", h.Get)
+}
+
+// eventImportResponse is the progress of a bulk import.
+type eventImportResponse struct {
+	ID          string                   `json:"id"`
+	CommunityID string                   `json:"community_id"`
+	Format      string                   `json:"format"`
+	Status      string                   `json:"status"`
+	Imported    int64                    `json:"imported"`
+	Rejected    int64                    `json:"rejected"`
+	Errors      []importRowErrorResponse `json:"errors"`
+	Error       string                   `json:"error,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	FinishedAt  *time.Time               `json:"finished_at,omitempty"`
+}
+
+// importRowErrorResponse is a skipped row, the first 20 are listed.
+type importRowErrorResponse struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// Import handles POST /api/v1/events/import
+// takes a multipart form with community_id, an optional format (csv or ndjson, else taken
+// from the file name) and the file, in that order, so the upload can be checked before
+// it's read. the file is spooled to disk and imported in the background.
+//
+// @Summary Import historical events
+// @Description Uploads a CSV or NDJSON file of events into a community managed by the caller
+// @Tags events
+// @Accept multipart/form-data
+// @Produce json
+// @Param community_id formData string true "Community to import into"
+// @Param format formData string false "csv or ndjson, taken from the file name when omitted"
+// @Param file formData file true "Events, one per row or line"
+// @Success 202 {object} eventImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse "import queue full"
+// @Router /api/v1/events/import [post]
+// @Security BearerAuth
+func (h *EventImportHandler) Import(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	// uploads take longer than regular requests, best effort when the connection can't tell
+	_ = http.NewResponseController(c.Response()).SetReadDeadline(time.Now().Add(h.uploadTimeout))
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, h.maxBytes)
+
+	reader, err := c.Request().MultipartReader()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "multipart/form-data body required")
+	}
+
+	fields := make(map[string]string)
+	var file *multipart.Part
+	for file == nil {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+		}
+		if err != nil {
+			return uploadError(err)
+		}
+		if part.FormName() == "file" {
+			file = part
+			break
+		}
+		value, err := io.ReadAll(io.LimitReader(part, 1024))
+		if err != nil {
+			return uploadError(err)
+		}
+		fields[part.FormName()] = strings.TrimSpace(string(value))
+	}
+
+	format := fields["format"]
+	if format == "" {
+		format = importFormatFromName(file.FileName())
+	}
+
+	imp, err := h.useCase.Create(c.Request().Context(), application.CreateEventImportInput{
+		CommunityID:         fields["community_id"],
+		Format:              format,
+		RequesterExternalID: userExternalID,
+		OrganizationID:      GetOrganizationScope(c),
+	})
+	if err != nil {
+		return mapEventImportError(err)
+	}
+
+	path, err := spoolUpload(file)
+	if err != nil {
+		_ = h.useCase.Fail(c.Request().Context(), imp, fmt.Errorf("upload failed: %w", err))
+		return uploadError(err)
+	}
+
+	if err := h.queue.Enqueue(imp, path); err != nil {
+		_ = os.Remove(path)
+		_ = h.useCase.Fail(c.Request().Context(), imp, err)
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+
+	return c.JSON(http.StatusAccepted, toEventImportResponse(imp))
+}
+
+// Get handles GET /api/v1/events/import/:id
+// returns the progress of an import started by the caller.
+//
+// @Summary Get an event import
+// @Tags events
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} eventImportResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/events/import/{id} [get]
+// @Security BearerAuth
+func (h *EventImportHandler) Get(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	imp, err := h.useCase.Get(c.Request().Context(), c.Param("id"), userExternalID)
+	if err != nil {
+		return mapEventImportError(err)
+	}
+	return c.JSON(http.StatusOK, toEventImportResponse(imp))
+}
+
+// spoolUpload copies an uploaded file to a temporary file and returns its path.
+func spoolUpload(part io.Reader) (string, error) {
+	f, err := os.CreateTemp("", "pulse-import-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, part); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// importFormatFromName guesses an import's format from its file extension.
+func importFormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return domain.ImportFormatCSV.String()
+	case ".ndjson", ".jsonl":
+		return domain.ImportFormatNDJSON.String()
+	default:
+		return ""
+	}
+}
+
+// uploadError converts a failed read of the request body to an HTTP error.
+func uploadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("upload is larger than %d bytes", tooLarge.Limit))
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, "failed to read upload")
+}
+
+// mapEventImportError converts use case errors to HTTP errors
+func mapEventImportError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can import events")
+	case errors.Is(err, domain.ErrInvalidEventImportFormat):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toEventImportResponse(imp *domain.EventImport) eventImportResponse {
+	resp := eventImportResponse{
+		ID:          imp.ID().String(),
+		CommunityID: imp.CommunityID().String(),
+		Format:      imp.Format().String(),
+		Status:      imp.Status().String(),
+		Imported:    imp.Imported(),
+		Rejected:    imp.Rejected(),
+		Errors:      make([]importRowErrorResponse, len(imp.RowErrors())),
+		Error:       imp.Err(),
+		CreatedAt:   imp.CreatedAt(),
+		UpdatedAt:   imp.UpdatedAt(),
+		FinishedAt:  imp.FinishedAt(),
+	}
+	for i, e := range imp.RowErrors() {
+		resp.Errors[i] = importRowErrorResponse{Line: e.Line, Reason: e.Reason}
+	}
+	return resp
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// EventTypeHandler lists the event types the ingest endpoint accepts,
+// so SDKs and UIs don't have to hardcode them.
+type EventTypeHandler struct{}
+
+// NewEventTypeHandler creates a new EventTypeHandler.
+func NewEventTypeHandler() *EventTypeHandler {
+	return &EventTypeHandler{}
+}
+
+// RegisterRoutes registers the event type routes on the given group.
+func (h *EventTypeHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/event-types", h.List)
+}
+
+type eventTypeResponse struct {
+	Name           string         `json:"name"`
+	DefaultWeight  float64        `json:"default_weight"`
+	Sign           string         `json:"sign"` // positive or negative momentum signal
+	MetadataSchema map[string]any `json:"metadata_schema"`
+}
+
+type listEventTypesResponse struct {
+	EventTypes []eventTypeResponse `json:"event_types"`
+	Count      int                 `json:"count"`
+}
+
+// List returns every event type with its default weight, sign and metadata JSON schema.
+// GET /api/v1/event-types
+func (h *EventTypeHandler) List(c echo.Context) error {
+	schema := metadataSchema(domain.MetadataFields())
+	types := domain.EventTypes()
+
+	resp := listEventTypesResponse{
+		EventTypes: make([]eventTypeResponse, len(types)),
+		Count:      len(types),
+	}
+	for i, et := range types {
+		sign := "positive"
+		if !et.IsPositiveSignal() {
+			sign = "negative"
+		}
+		resp.EventTypes[i] = eventTypeResponse{
+			Name:           et.String(),
+			DefaultWeight:  et.DefaultWeight().Value(),
+			Sign:           sign,
+			MetadataSchema: schema,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// metadataSchema builds the JSON schema of event metadata. keys Pulse doesn't read are allowed.
+func metadataSchema(fields []domain.MetadataField) map[string]any {
+	properties := make(map[string]any, len(fields))
+	for _, f := range fields {
+		prop := map[string]any{
+			"type":        f.Type,
+			"description": f.Description,
+		}
+		if f.Pattern != "" {
+			prop["pattern"] = f.Pattern
+		}
+		properties[f.Key] = prop
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": true,
+	}
+}
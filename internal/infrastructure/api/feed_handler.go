@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+)
+
+// FeedHandler handles the "hot now" discovery feed.
+type FeedHandler struct {
+	useCase *application.FeedUseCase
+}
+
+// NewFeedHandler creates a new FeedHandler.
+func NewFeedHandler(useCase *application.FeedUseCase) *FeedHandler {
+	return &FeedHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the feed routes on the given group.
+func (h *FeedHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/feed", h.Feed)
+}
+
+type feedItemResponse struct {
+	Community communityResponse `json:"community"`
+	Reasons   []string          `json:"reasons"` // mover, new, participating
+	Score     float64           `json:"score"`
+}
+
+type feedResponse struct {
+	Items []feedItemResponse `json:"items"`
+	Count int                `json:"count"`
+}
+
+// Feed mixes top movers, new communities and the caller's own communities, best first.
+// anonymous callers get the same feed without their own communities.
+// GET /api/v1/feed?limit=20
+func (h *FeedHandler) Feed(c echo.Context) error {
+	limit := 20
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	items, err := h.useCase.Execute(c.Request().Context(), GetUserExternalID(c), limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to build feed")
+	}
+
+	resp := feedResponse{
+		Items: make([]feedItemResponse, len(items)),
+		Count: len(items),
+	}
+	for i, item := range items {
+		reasons := make([]string, len(item.Reasons))
+		for j, r := range item.Reasons {
+			reasons[j] = r.String()
+		}
+		resp.Items[i] = feedItemResponse{
+			Community: toCommunityResponse(item.Community),
+			Reasons:   reasons,
+			Score:     item.Score,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -2,8 +2,11 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/health"
 )
 
 // HealthResponse is the response for health check endpoints.
@@ -39,3 +42,64 @@ func readyHandler(c echo.Context) error {
 		Service: "pulse",
 	})
 }
+
+// statusResponse is the data behind a status page, summarized from the health history.
+type statusResponse struct {
+	Status     string              `json:"status"` // ok or degraded
+	StartedAt  time.Time           `json:"started_at"`
+	Since      time.Time           `json:"since"`
+	Samples    int                 `json:"samples"`
+	Components []componentResponse `json:"components"`
+}
+
+type componentResponse struct {
+	Name          string           `json:"name"`
+	Kind          string           `json:"kind"` // check or heartbeat
+	Up            bool             `json:"up"`
+	UptimePercent float64          `json:"uptime_percent"`
+	LatencyMs     *latencyResponse `json:"latency_ms,omitempty"`
+	LastBeat      *time.Time       `json:"last_beat,omitempty"`
+}
+
+type latencyResponse struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// RegisterStatusRoute registers GET /statusz, the health history of the monitor.
+// public like /health, it reveals component names and latencies but no data.
+func RegisterStatusRoute(e *echo.Echo, monitor *health.Monitor) {
+	e.GET("/statusz", func(c echo.Context) error {
+		status := monitor.Status()
+		resp := statusResponse{
+			Status:     status.Status,
+			StartedAt:  status.StartedAt,
+			Since:      status.Since,
+			Samples:    status.Samples,
+			Components: make([]componentResponse, len(status.Components)),
+		}
+		for i, comp := range status.Components {
+			resp.Components[i] = componentResponse{
+				Name:          comp.Name,
+				Kind:          comp.Kind,
+				Up:            comp.Up,
+				UptimePercent: comp.Uptime,
+				LastBeat:      comp.LastBeat,
+			}
+			if comp.LatencyP50 != nil {
+				resp.Components[i].LatencyMs = &latencyResponse{
+					P50: milliseconds(*comp.LatencyP50),
+					P95: milliseconds(*comp.LatencyP95),
+					P99: milliseconds(*comp.LatencyP99),
+				}
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
+	})
+}
+
+// milliseconds converts a duration to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
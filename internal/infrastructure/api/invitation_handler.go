@@ -0,0 +1,254 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// InvitationHandler handles community invitation and membership endpoints.
+type InvitationHandler struct {
+	useCase *application.InvitationUseCase
+}
+
+// NewInvitationHandler creates a new InvitationHandler.
+func NewInvitationHandler(useCase *application.InvitationUseCase) *InvitationHandler {
+	return &InvitationHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the invitation routes on the given group.
+// every route requires authentication.
+func (h *InvitationHandler) RegisterRoutes(g *echo.Group) {
+	g.POST("/communities/:id/invitations", h.Create)
+	g.GET("/communities/:id/invitations", h.List)
+	g.DELETE("/communities/:id/invitations/:invitation_id", h.Revoke)
+	g.GET("/communities/:id/members", h.ListMembers)
+
+	// the token goes in the body, so it doesn't end up in access logs
+	g.POST("/invitations/accept", h.Accept)
+}
+
+// createInvitationRequest is the request body for creating an invitation.
+type createInvitationRequest struct {
+	// ExpiresIn is a Go duration string between 1h and 720h, 168h when omitted.
+	ExpiresIn string `json:"expires_in" validate:"omitempty,duration"`
+}
+
+// acceptInvitationRequest is the request body for accepting an invitation.
+type acceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type invitationResponse struct {
+	ID          string     `json:"id"`
+	CommunityID string     `json:"community_id"`
+	CreatedBy   string     `json:"created_by"`
+	Hint        string     `json:"hint"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AcceptedBy  *string    `json:"accepted_by,omitempty"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+}
+
+// createInvitationResponse includes the token, which is never shown again.
+type createInvitationResponse struct {
+	invitationResponse
+	Token string `json:"token"`
+}
+
+type listInvitationsResponse struct {
+	Invitations []invitationResponse `json:"invitations"`
+	Count       int                  `json:"count"`
+}
+
+type acceptInvitationResponse struct {
+	CommunityID string    `json:"community_id"`
+	UserID      string    `json:"user_id"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+type communityMemberResponse struct {
+	UserID   string    `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type listCommunityMembersResponse struct {
+	Members []communityMemberResponse `json:"members"`
+	Count   int                       `json:"count"`
+}
+
+// Create issues a single-use invitation.
+// POST /api/v1/communities/:id/invitations
+// requires the community creator or an admin of its organization
+func (h *InvitationHandler) Create(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req createInvitationRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		// already checked by the duration rule
+		ttl, _ = time.ParseDuration(req.ExpiresIn)
+	}
+
+	output, err := h.useCase.Create(c.Request().Context(), application.CreateInvitationInput{
+		CommunityID:         c.Param("id"),
+		TTL:                 ttl,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapInvitationError(err)
+	}
+
+	return c.JSON(http.StatusCreated, createInvitationResponse{
+		invitationResponse: toInvitationResponse(output.InvitationOutput),
+		Token:              output.Token,
+	})
+}
+
+// List returns the community's invitations.
+// GET /api/v1/communities/:id/invitations
+func (h *InvitationHandler) List(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	invitations, err := h.useCase.List(c.Request().Context(), c.Param("id"), userExternalID)
+	if err != nil {
+		return mapInvitationError(err)
+	}
+
+	resp := listInvitationsResponse{
+		Invitations: make([]invitationResponse, len(invitations)),
+		Count:       len(invitations),
+	}
+	for i, inv := range invitations {
+		resp.Invitations[i] = toInvitationResponse(inv)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Revoke stops a pending invitation from being accepted.
+// DELETE /api/v1/communities/:id/invitations/:invitation_id
+func (h *InvitationHandler) Revoke(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.Revoke(c.Request().Context(), c.Param("id"), c.Param("invitation_id"), userExternalID); err != nil {
+		return mapInvitationError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Accept joins the community the invitation is for.
+// POST /api/v1/invitations/accept
+func (h *InvitationHandler) Accept(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req acceptInvitationRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.useCase.Accept(c.Request().Context(), req.Token, userExternalID)
+	if err != nil {
+		return mapInvitationError(err)
+	}
+
+	return c.JSON(http.StatusOK, acceptInvitationResponse{
+		CommunityID: output.CommunityID,
+		UserID:      output.UserID,
+		JoinedAt:    output.JoinedAt,
+	})
+}
+
+// ListMembers returns the community's members.
+// GET /api/v1/communities/:id/members?limit=50&offset=0
+func (h *InvitationHandler) ListMembers(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	limit := 50
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	members, err := h.useCase.ListMembers(c.Request().Context(), c.Param("id"), userExternalID, limit, offset)
+	if err != nil {
+		return mapInvitationError(err)
+	}
+
+	resp := listCommunityMembersResponse{
+		Members: make([]communityMemberResponse, len(members)),
+		Count:   len(members),
+	}
+	for i, m := range members {
+		resp.Members[i] = communityMemberResponse{UserID: m.UserID, JoinedAt: m.JoinedAt}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// mapInvitationError converts use case errors to HTTP errors
+func mapInvitationError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can manage invitations")
+	case errors.Is(err, application.ErrCreatorNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "user profile not found - please complete signup first")
+	case errors.Is(err, domain.ErrInvitationInvalid):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvitationExpired),
+		errors.Is(err, domain.ErrInvitationRevoked),
+		errors.Is(err, domain.ErrInvitationUsed):
+		return echo.NewHTTPError(http.StatusGone, err.Error())
+	case errors.Is(err, application.ErrAlreadyCommunityMember):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvitationTTLOutOfRange):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toInvitationResponse(inv application.InvitationOutput) invitationResponse {
+	return invitationResponse{
+		ID:          inv.ID,
+		CommunityID: inv.CommunityID,
+		CreatedBy:   inv.CreatedBy,
+		Hint:        inv.Hint,
+		Status:      inv.Status,
+		ExpiresAt:   inv.ExpiresAt,
+		CreatedAt:   inv.CreatedAt,
+		AcceptedBy:  inv.AcceptedBy,
+		AcceptedAt:  inv.AcceptedAt,
+	}
+}
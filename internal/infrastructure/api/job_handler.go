@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// JobHandler reports the status of background jobs.
+type JobHandler struct {
+	useCase *application.JobUseCase
+}
+
+// NewJobHandler creates a new JobHandler.
+func NewJobHandler(useCase *application.JobUseCase) *JobHandler {
+	return &JobHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the job routes on the given group.
+// all routes require authentication.
+func (h *JobHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/jobs/:id", h.Get)
+}
+
+// jobResponse is the status of a background job.
+type jobResponse struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Status     string            `json:"status"`
+	Progress   jobProgress       `json:"progress"`
+	Error      string            `json:"error,omitempty"`
+	Links      map[string]string `json:"links"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// jobProgress is how far along a job is. total and percent are left out while unknown.
+type jobProgress struct {
+	Done    int64    `json:"done"`
+	Total   *int64   `json:"total,omitempty"`
+	Percent *float64 `json:"percent,omitempty"`
+}
+
+// Get handles GET /api/v1/jobs/:id
+// returns a job started by the caller, or any job for admins.
+//
+// @Summary Get a background job
+// @Description Status and progress of long-running work, with a link to its result
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/jobs/{id} [get]
+// @Security BearerAuth
+func (h *JobHandler) Get(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	admin := false
+	if claims := GetClaims(c); claims != nil {
+		admin = claims.IsAdmin()
+	}
+
+	job, err := h.useCase.Get(c.Request().Context(), c.Param("id"), userExternalID, admin)
+	if err != nil {
+		return mapDomainError(err)
+	}
+	return c.JSON(http.StatusOK, toJobResponse(job))
+}
+
+func toJobResponse(job *domain.Job) jobResponse {
+	resp := jobResponse{
+		ID:     job.ID().String(),
+		Kind:   job.Kind().String(),
+		Status: job.Status().String(),
+		Progress: jobProgress{
+			Done:    job.Done(),
+			Percent: job.Percent(),
+		},
+		Error:      job.Err(),
+		Links:      jobLinks(job),
+		CreatedAt:  job.CreatedAt(),
+		UpdatedAt:  job.UpdatedAt(),
+		StartedAt:  job.StartedAt(),
+		FinishedAt: job.FinishedAt(),
+	}
+	if total := job.Total(); total > 0 {
+		resp.Progress.Total = &total
+	}
+	return resp
+}
+
+// jobLinks points to the job itself and to the detailed record of the work it tracks.
+func jobLinks(job *domain.Job) map[string]string {
+	links := map[string]string{
+		"self": "/api/v1/jobs/" + job.ID().String(),
+	}
+	switch job.Kind() {
+	case domain.JobKindEventImport:
+		links["result"] = "/api/v1/events/import/" + job.ID().String()
+	}
+	return links
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+)
+
+// LeaderboardHandler handles the global and regional leaderboards.
+type LeaderboardHandler struct {
+	useCase *application.LeaderboardUseCase
+}
+
+// NewLeaderboardHandler creates a new LeaderboardHandler.
+func NewLeaderboardHandler(useCase *application.LeaderboardUseCase) *LeaderboardHandler {
+	return &LeaderboardHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the leaderboard routes on the given group.
+func (h *LeaderboardHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/leaderboard", h.Leaderboard)
+	g.GET("/leaderboard/changes", h.Changes)
+}
+
+type leaderboardEntryResponse struct {
+	// Rank is left out for pinned entries, which come before the ranked ones on the first page.
+	Rank      int               `json:"rank,omitempty"`
+	Momentum  float64           `json:"momentum"`
+	Pinned    bool              `json:"pinned,omitempty"`
+	Sponsored bool              `json:"sponsored,omitempty"`
+	Community communityResponse `json:"community"`
+}
+
+type leaderboardResponse struct {
+	Region  string                     `json:"region,omitempty"`
+	Entries []leaderboardEntryResponse `json:"entries"`
+	Limit   int                        `json:"limit"`
+	Offset  int                        `json:"offset"`
+}
+
+// leaderboardSnapshotResponse identifies one side of a leaderboard comparison.
+type leaderboardSnapshotResponse struct {
+	// RunID is the momentum cycle the leaderboard was taken after.
+	RunID   string    `json:"run_id"`
+	TakenAt time.Time `json:"taken_at"`
+}
+
+type leaderboardChangeResponse struct {
+	// Change is entered, left or moved.
+	Change string `json:"change"`
+	// PreviousRank is left out for entered communities, Rank for the ones that left.
+	PreviousRank     int     `json:"previous_rank,omitempty"`
+	Rank             int     `json:"rank,omitempty"`
+	Movement         int     `json:"movement"` // places climbed, negative when dropped
+	PreviousMomentum float64 `json:"previous_momentum"`
+	Momentum         float64 `json:"momentum"`
+
+	Community communityResponse `json:"community"`
+}
+
+type leaderboardChangesResponse struct {
+	From      leaderboardSnapshotResponse `json:"from"`
+	To        leaderboardSnapshotResponse `json:"to"`
+	Positions int                         `json:"positions"`
+	Changes   []leaderboardChangeResponse `json:"changes"`
+}
+
+// Leaderboard ranks public communities by momentum, within a region when one is given.
+// regional momentum only counts the events sent from that region. the first page of the
+// global leaderboard starts with the communities pinned by admins.
+// GET /api/v1/leaderboard?region=eu&limit=20&offset=0
+func (h *LeaderboardHandler) Leaderboard(c echo.Context) error {
+	limit := 20
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	region := c.QueryParam("region")
+	entries, err := h.useCase.Execute(c.Request().Context(), region, limit, offset)
+	if err != nil {
+		if errors.Is(err, application.ErrRegionalLeaderboardsDisabled) {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+		}
+		if isValidationError(err) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch leaderboard")
+	}
+
+	resp := leaderboardResponse{
+		Region:  region,
+		Entries: make([]leaderboardEntryResponse, len(entries)),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for i, e := range entries {
+		resp.Entries[i] = leaderboardEntryResponse{
+			Rank:      e.Rank,
+			Momentum:  e.Momentum,
+			Pinned:    e.Pinned,
+			Sponsored: e.Sponsored,
+			Community: toCommunityResponse(e.Community),
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Changes lists the communities that entered or left the top of the global leaderboard,
+// or moved by more than positions places, between a past momentum cycle and the latest one.
+// since is a momentum run id, or an RFC 3339 time for the last cycle before it.
+// GET /api/v1/leaderboard/changes?since=2025-03-01T12:00:00Z&positions=3
+func (h *LeaderboardHandler) Changes(c echo.Context) error {
+	since := c.QueryParam("since")
+	if since == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "since is required, a momentum run id or an RFC 3339 time")
+	}
+	positions := 0
+	if p := c.QueryParam("positions"); p != "" {
+		parsed, err := strconv.Atoi(p)
+		if err != nil || parsed < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "positions must be zero or more")
+		}
+		positions = parsed
+	}
+
+	output, err := h.useCase.Changes(c.Request().Context(), since, positions)
+	if err != nil {
+		if errors.Is(err, application.ErrLeaderboardChangesDisabled) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return mapDomainError(err)
+	}
+
+	resp := leaderboardChangesResponse{
+		From:      leaderboardSnapshotResponse{RunID: output.From.ID().String(), TakenAt: output.From.TakenAt()},
+		To:        leaderboardSnapshotResponse{RunID: output.To.ID().String(), TakenAt: output.To.TakenAt()},
+		Positions: positions,
+		Changes:   make([]leaderboardChangeResponse, len(output.Changes)),
+	}
+	for i, change := range output.Changes {
+		resp.Changes[i] = leaderboardChangeResponse{
+			Change:           change.Kind.String(),
+			PreviousRank:     change.PreviousRank,
+			Rank:             change.Rank,
+			Movement:         change.Movement(),
+			PreviousMomentum: change.PreviousMomentum,
+			Momentum:         change.Momentum,
+			Community:        toCommunityResponse(change.Community),
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor systemd passes with socket activation.
+const listenFDsStart = 3
+
+// errReusePortUnsupported is returned when SO_REUSEPORT is asked for on a platform without it.
+var errReusePortUnsupported = errors.New("SO_REUSEPORT is not supported on this platform")
+
+// listen returns the socket the server accepts on and where it came from: the one systemd
+// passed with socket activation when there is one, otherwise a new one on addr, which other
+// processes can bind too when reusePort is set.
+func listen(addr string, reusePort bool) (net.Listener, string, error) {
+	ln, err := activationListener()
+	if err != nil {
+		return nil, "", err
+	}
+	if ln != nil {
+		return ln, "systemd", nil
+	}
+
+	lc := net.ListenConfig{}
+	source := "port"
+	if reusePort {
+		lc.Control = reusePortControl
+		source = "port (SO_REUSEPORT)"
+	}
+	ln, err = lc.Listen(context.Background(), "tcp", addr)
+	if err != nil {
+		return nil, "", fmt.Errorf("listening on %s: %w", addr, err)
+	}
+	return ln, source, nil
+}
+
+// activationListener returns the first socket passed by systemd socket activation, or nil
+// without one. the variables are cleared so processes pulse starts don't take the socket.
+func activationListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	f := os.NewFile(listenFDsStart, "systemd-socket")
+	defer func() { _ = f.Close() }() // FileListener dups the descriptor
+
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("using socket from systemd: %w", err)
+	}
+	return ln, nil
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/auth"
+	"github.com/joacominatel/pulse/internal/infrastructure/eventbus"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+const (
+	// liveHeartbeatInterval is how often an idle live feed is pinged, so proxies keep it open,
+	// and how often its token's expiry is checked.
+	liveHeartbeatInterval = 30 * time.Second
+
+	// liveWriteTimeout is how long a live feed client has to take a message.
+	liveWriteTimeout = 10 * time.Second
+
+	// liveMaxMessageBytes bounds the messages a live feed reads, only its auth message matters.
+	liveMaxMessageBytes = 4096
+)
+
+// LiveHub hands live feeds the momentum updates and spikes of a community.
+// implemented by eventbus.Hub.
+type LiveHub interface {
+	Subscribe(communityID domain.CommunityID) *eventbus.HubSubscription
+}
+
+// LiveHandler streams a community's momentum over a websocket.
+type LiveHandler struct {
+	stats     *application.CommunityStatsUseCase
+	hub       LiveHub
+	validator *auth.JWTValidator
+	logger    *logging.Logger
+}
+
+// NewLiveHandler creates a new LiveHandler.
+func NewLiveHandler(stats *application.CommunityStatsUseCase, hub LiveHub, validator *auth.JWTValidator, logger *logging.Logger) *LiveHandler {
+	return &LiveHandler{
+		stats:     stats,
+		hub:       hub,
+		validator: validator,
+		logger:    logger.WithComponent("live_handler"),
+	}
+}
+
+// RegisterRoutes registers the live feed route on the given group.
+// browsers can't set headers on a websocket, so it also takes the token as access_token.
+func (h *LiveHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/communities/:id/live", h.Live, StreamAuthMiddleware(h.validator))
+}
+
+// liveSnapshotMessage is the first message of a feed, the momentum it starts from.
+type liveSnapshotMessage struct {
+	Type              string     `json:"type"`
+	CommunityID       string     `json:"community_id"`
+	Momentum          float64    `json:"momentum"`
+	MomentumUpdatedAt *time.Time `json:"momentum_updated_at"`
+}
+
+// liveMomentumMessage is sent each time the community's momentum is stored.
+type liveMomentumMessage struct {
+	Type         string    `json:"type"`
+	CommunityID  string    `json:"community_id"`
+	OldMomentum  float64   `json:"old_momentum"`
+	NewMomentum  float64   `json:"new_momentum"`
+	Decayed      bool      `json:"decayed"`
+	CalculatedAt time.Time `json:"calculated_at"`
+}
+
+// liveSpikeMessage is sent for each spike notified, after the spike cooldown.
+type liveSpikeMessage struct {
+	Type          string    `json:"type"`
+	CommunityID   string    `json:"community_id"`
+	OldMomentum   float64   `json:"old_momentum"`
+	NewMomentum   float64   `json:"new_momentum"`
+	PercentChange float64   `json:"percent_change"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// liveErrorMessage is the last message of a feed the server closes.
+type liveErrorMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// Live handles GET /api/v1/communities/:id/live
+// upgrades to a websocket that sends the community's current momentum, then every update
+// and spike as they happen. private communities only stream to members: a connection
+// without a token has StreamAuthTimeout to send {"type": "auth", "token": "<jwt>"}.
+//
+// @Summary Follow a community's momentum live
+// @Description WebSocket. Messages are JSON with a type: snapshot, momentum, spike or error
+// @Tags communities
+// @Param id path string true "Community ID"
+// @Param access_token query string false "JWT, for clients that can't set headers"
+// @Success 101 "Switching Protocols"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/communities/{id}/live [get]
+func (h *LiveHandler) Live(c echo.Context) error {
+	if !c.IsWebSocket() {
+		return echo.NewHTTPError(http.StatusBadRequest, "websocket upgrade required")
+	}
+
+	communityID := c.Param("id")
+	userExternalID := GetUserExternalID(c)
+	snapshot, err := h.stats.Live(c.Request().Context(), communityID, userExternalID)
+	// anonymous connections to private communities may still authenticate once upgraded
+	awaitAuth := errors.Is(err, application.ErrCommunityPrivate) && userExternalID == ""
+	if err != nil && !awaitAuth {
+		return mapDomainError(err)
+	}
+
+	var session *StreamSession
+	if claims := GetClaims(c); claims != nil {
+		session = NewStreamSession(claims, 1)
+	}
+
+	server := websocket.Server{
+		// tokens never come from cookies, so any origin may connect
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ctx := logging.ContextWithCommunityID(c.Request().Context(), communityID)
+			// the server's read and write timeouts still apply to the hijacked connection
+			_ = ws.SetDeadline(time.Time{})
+			ws.MaxPayloadBytes = liveMaxMessageBytes
+
+			if awaitAuth {
+				snapshot, session, err = h.authenticate(ctx, ws, communityID)
+				if err != nil {
+					h.send(ws, liveErrorMessage{Type: "error", Error: err.Error()})
+					return
+				}
+			}
+			h.stream(ctx, ws, snapshot, session)
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// authenticate reads the auth message of a connection to a private community
+// and checks its user is a member.
+func (h *LiveHandler) authenticate(ctx context.Context, ws *websocket.Conn, communityID string) (*application.LiveMomentumOutput, *StreamSession, error) {
+	_ = ws.SetReadDeadline(time.Now().Add(StreamAuthTimeout))
+	var msg []byte
+	if err := websocket.Message.Receive(ws, &msg); err != nil {
+		return nil, nil, ErrStreamAuthMessage
+	}
+	_ = ws.SetReadDeadline(time.Time{})
+
+	claims, err := AuthenticateStreamMessage(h.validator, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, err := h.stats.Live(ctx, communityID, claims.UserID())
+	if errors.Is(err, application.ErrCommunityPrivate) {
+		return nil, nil, errors.New("community is private - members only")
+	}
+	if err != nil {
+		return nil, nil, errors.New("community unavailable")
+	}
+	return snapshot, NewStreamSession(claims, 1), nil
+}
+
+// stream sends the snapshot, then the community's events until the client leaves,
+// its token expires, or the hub ends the subscription.
+func (h *LiveHandler) stream(ctx context.Context, ws *websocket.Conn, snapshot *application.LiveMomentumOutput, session *StreamSession) {
+	log := h.logger.WithContext(ctx)
+
+	sub := h.hub.Subscribe(snapshot.CommunityID)
+	defer sub.Close()
+	if session != nil {
+		_ = session.Subscribe(snapshot.CommunityID.String())
+	}
+
+	if !h.send(ws, liveSnapshotMessage{
+		Type:              "snapshot",
+		CommunityID:       snapshot.CommunityID.String(),
+		Momentum:          snapshot.Momentum,
+		MomentumUpdatedAt: snapshot.MomentumUpdatedAt,
+	}) {
+		return
+	}
+
+	// clients don't send anything once authenticated, reads only notice them leaving
+	left := make(chan struct{})
+	go func() {
+		defer close(left)
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	heartbeat := time.NewTicker(liveHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-left:
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				reason := "server shutting down, reconnect"
+				if sub.Dropped() {
+					reason = "connection too slow for the feed, reconnect"
+					log.Info("live feed dropped a slow client")
+				}
+				h.send(ws, liveErrorMessage{Type: "error", Error: reason})
+				return
+			}
+			if msg := toLiveMessage(event); msg != nil && !h.send(ws, msg) {
+				return
+			}
+		case <-heartbeat.C:
+			if session != nil && session.Expired(time.Now()) {
+				h.send(ws, liveErrorMessage{Type: "error", Error: "token expired, reconnect with a fresh one"})
+				return
+			}
+			if !h.ping(ws) {
+				return
+			}
+		}
+	}
+}
+
+// send writes a JSON message, false once the client can't be written to.
+func (h *LiveHandler) send(ws *websocket.Conn, msg any) bool {
+	_ = ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	return websocket.JSON.Send(ws, msg) == nil
+}
+
+// ping writes a ping frame, answered by the client's websocket library.
+func (h *LiveHandler) ping(ws *websocket.Conn) bool {
+	_ = ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	ws.PayloadType = websocket.PingFrame
+	_, err := ws.Write(nil)
+	return err == nil
+}
+
+// toLiveMessage converts a hub event to its message, nil for events feeds don't carry.
+func toLiveMessage(event domain.DomainEvent) any {
+	switch e := event.(type) {
+	case domain.MomentumCalculated:
+		return liveMomentumMessage{
+			Type:         "momentum",
+			CommunityID:  e.CommunityID.String(),
+			OldMomentum:  e.OldMomentum,
+			NewMomentum:  e.NewMomentum,
+			Decayed:      e.Decayed,
+			CalculatedAt: e.CalculatedAt,
+		}
+	case domain.SpikeDetected:
+		return liveSpikeMessage{
+			Type:          "spike",
+			CommunityID:   e.Spike.CommunityID.String(),
+			OldMomentum:   e.Spike.OldMomentum,
+			NewMomentum:   e.Spike.NewMomentum,
+			PercentChange: e.Spike.PercentChange,
+			Timestamp:     e.Spike.Timestamp,
+		}
+	default:
+		return nil
+	}
+}
@@ -1,11 +1,20 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/auth"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 )
 
 // contextKey is a custom type for context keys to avoid collisions.
@@ -17,13 +26,27 @@ const (
 
 	// ClaimsContextKey is the context key for the full JWT claims.
 	ClaimsContextKey contextKey = "jwt_claims"
+
+	// OrganizationScopeContextKey holds the organization id of an organization api key.
+	OrganizationScopeContextKey contextKey = "organization_scope"
+
+	// APIKeyHeader carries an api key for service-to-service calls.
+	APIKeyHeader = "X-API-Key"
 )
 
+// APIKeyAuthenticator resolves an api key to the user it acts for.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (*application.APIKeyPrincipal, error)
+}
+
 // AuthConfig holds authentication middleware configuration.
 type AuthConfig struct {
 	// JWTValidator is the validator for supabase JWT tokens.
 	JWTValidator *auth.JWTValidator
 
+	// APIKeys validates X-API-Key headers. optional, api keys are rejected when nil.
+	APIKeys APIKeyAuthenticator
+
 	// Skipper defines a function to skip auth for certain routes.
 	Skipper func(c echo.Context) bool
 }
@@ -44,6 +67,14 @@ func AuthMiddleware(config AuthConfig) echo.MiddlewareFunc {
 				return next(c)
 			}
 
+			// api keys take precedence over bearer tokens
+			if key := c.Request().Header.Get(APIKeyHeader); key != "" {
+				if err := authenticateAPIKey(c, config.APIKeys, key); err != nil {
+					return echo.NewHTTPError(401, "invalid api key")
+				}
+				return next(c)
+			}
+
 			// extract and validate JWT
 			claims, err := validateRequest(c, config.JWTValidator)
 			if err != nil {
@@ -64,6 +95,12 @@ func AuthMiddleware(config AuthConfig) echo.MiddlewareFunc {
 func OptionalAuthMiddleware(config AuthConfig) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			// api keys take precedence over bearer tokens
+			if key := c.Request().Header.Get(APIKeyHeader); key != "" {
+				_ = authenticateAPIKey(c, config.APIKeys, key)
+				return next(c)
+			}
+
 			// try to validate JWT if present
 			claims, err := validateRequest(c, config.JWTValidator)
 			if err == nil && claims != nil {
@@ -88,6 +125,26 @@ func validateRequest(c echo.Context, validator *auth.JWTValidator) (*auth.Supaba
 	return validator.ValidateToken(token)
 }
 
+// authenticateAPIKey resolves the key and stores its owner in context.
+// api key requests carry no JWT claims, only the user external id
+// and, for organization keys, the organization they're limited to.
+func authenticateAPIKey(c echo.Context, authenticator APIKeyAuthenticator, key string) error {
+	if authenticator == nil {
+		return domain.ErrAPIKeyInvalid
+	}
+
+	principal, err := authenticator.Authenticate(c.Request().Context(), key)
+	if err != nil {
+		return err
+	}
+
+	c.Set(string(UserContextKey), principal.User.ExternalID())
+	if principal.OrganizationID != nil {
+		c.Set(string(OrganizationScopeContextKey), principal.OrganizationID.String())
+	}
+	return nil
+}
+
 // mapAuthError converts auth errors to appropriate HTTP errors
 func mapAuthError(err error) *echo.HTTPError {
 	switch {
@@ -128,6 +185,60 @@ func GetClaims(c echo.Context) *auth.SupabaseClaims {
 	return nil
 }
 
+// GetOrganizationScope returns the organization an organization api key is limited to.
+// returns empty string for user keys and JWTs.
+func GetOrganizationScope(c echo.Context) string {
+	if val := c.Get(string(OrganizationScopeContextKey)); val != nil {
+		if orgID, ok := val.(string); ok {
+			return orgID
+		}
+	}
+	return ""
+}
+
+// OrganizationScopeMiddleware keeps organization api keys inside their organization.
+// they can read anything public, ingest and import events (the use cases check the community),
+// and use their own organization's routes; every other write is refused.
+// must run after the auth middleware.
+func OrganizationScopeMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scope := GetOrganizationScope(c)
+			if scope == "" {
+				return next(c)
+			}
+
+			switch path := c.Path(); {
+			case c.Request().Method == http.MethodGet && !strings.Contains(path, "/organizations/"):
+				return next(c)
+			case path == "/api/v1/events", path == "/api/v1/events/import":
+				return next(c)
+			case strings.HasPrefix(path, "/api/v1/organizations/:id") && c.Param("id") == scope:
+				return next(c)
+			default:
+				return echo.NewHTTPError(http.StatusForbidden, "organization api keys can only ingest events and manage their organization")
+			}
+		}
+	}
+}
+
+// RequireAdmin rejects requests that aren't from an admin token.
+// must run after the auth middleware. api keys act as a regular user and are never admin.
+func RequireAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := GetClaims(c)
+			if claims == nil {
+				return echo.NewHTTPError(401, "authentication required")
+			}
+			if !claims.IsAdmin() {
+				return echo.NewHTTPError(403, "admin access required")
+			}
+			return next(c)
+		}
+	}
+}
+
 // PublicRoutesSkipper returns a skipper function that skips auth for public routes.
 func PublicRoutesSkipper(publicPaths ...string) func(echo.Context) bool {
 	pathSet := make(map[string]bool)
@@ -139,3 +250,218 @@ func PublicRoutesSkipper(publicPaths ...string) func(echo.Context) bool {
 		return pathSet[c.Path()]
 	}
 }
+
+// RequestContextMiddleware stores request_id, user_id and community_id in the
+// request context so loggers derived with WithContext include them.
+// must run after the auth middleware to see the user.
+func RequestContextMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			if requestID := c.Response().Header().Get(echo.HeaderXRequestID); requestID != "" {
+				ctx = logging.ContextWithRequestID(ctx, requestID)
+			}
+			if userID := GetUserExternalID(c); userID != "" {
+				ctx = logging.ContextWithUserID(ctx, userID)
+			}
+			// :id is the community on every /communities/:id/... route
+			if strings.Contains(c.Path(), "/communities/:id") {
+				ctx = logging.ContextWithCommunityID(ctx, c.Param("id"))
+			}
+
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// UsageMeter records billable usage. implemented by application.Meter.
+type UsageMeter interface {
+	Add(subject domain.MeterSubject, subjectID string, metric domain.MeterMetric, n int64)
+}
+
+// MeteringMiddleware counts authenticated api calls for billing.
+// calls with an organization api key are billed to the organization, others to the user.
+// anonymous calls aren't metered.
+func MeteringMiddleware(meter UsageMeter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			if orgID := GetOrganizationScope(c); orgID != "" {
+				meter.Add(domain.MeterSubjectOrganization, orgID, domain.MeterAPICalls, 1)
+			} else if userID := GetUserExternalID(c); userID != "" {
+				meter.Add(domain.MeterSubjectUser, userID, domain.MeterAPICalls, 1)
+			}
+
+			return err
+		}
+	}
+}
+
+// RequestRateLimiter counts a caller's requests. implemented by application.RateLimiter.
+type RequestRateLimiter interface {
+	Take(caller string) application.RateLimitStatus
+}
+
+const (
+	// HeaderRateLimitLimit is how many requests a caller may make per window.
+	HeaderRateLimitLimit = "X-RateLimit-Limit"
+
+	// HeaderRateLimitRemaining is how many requests are left in the current window.
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+
+	// HeaderRateLimitReset is when the window ends, in unix seconds.
+	HeaderRateLimitReset = "X-RateLimit-Reset"
+)
+
+// RateLimitHeadersMiddleware tells authenticated callers their request budget, so clients
+// can pace themselves. requests over the budget are still served. organization api keys
+// share their organization's budget, others have their user's. anonymous calls aren't counted.
+// must run after the auth middleware.
+func RateLimitHeadersMiddleware(limiter RequestRateLimiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var caller string
+			if orgID := GetOrganizationScope(c); orgID != "" {
+				caller = "organization:" + orgID
+			} else if userID := GetUserExternalID(c); userID != "" {
+				caller = "user:" + userID
+			} else {
+				return next(c)
+			}
+
+			status := limiter.Take(caller)
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.FormatInt(status.Limit, 10))
+			h.Set(HeaderRateLimitRemaining, strconv.FormatInt(status.Remaining, 10))
+			h.Set(HeaderRateLimitReset, strconv.FormatInt(status.Reset.Unix(), 10))
+			return next(c)
+		}
+	}
+}
+
+// Rate limit groups of the /api/v1 routes.
+const (
+	RateLimitGroupEvents = "events" // /api/v1/events and everything under it
+	RateLimitGroupAPI    = "api"    // every other route
+)
+
+// RateLimitRule limits the routes under a path prefix with a token bucket per caller.
+type RateLimitRule struct {
+	// Group names the routes in metrics, keeps their buckets apart from other groups',
+	// and picks their policy from RateLimitPolicies.
+	Group string
+
+	// Prefix matches the registered route path, like /api/v1/events. empty matches every route.
+	Prefix string
+}
+
+// RateLimitPolicies holds the token bucket policy of each route group. the middleware reads
+// them on every request, so Set applies new policies from the next request on, e.g. after a
+// config reload. a group without a policy, or with a zero rate, is unlimited.
+type RateLimitPolicies struct {
+	current atomic.Pointer[map[string]application.TokenBucketPolicy]
+}
+
+// NewRateLimitPolicies creates the policies, by group.
+func NewRateLimitPolicies(policies map[string]application.TokenBucketPolicy) *RateLimitPolicies {
+	p := &RateLimitPolicies{}
+	p.Set(policies)
+	return p
+}
+
+// Set replaces every group's policy at once.
+func (p *RateLimitPolicies) Set(policies map[string]application.TokenBucketPolicy) {
+	p.current.Store(&policies)
+}
+
+// Policy returns the group's policy.
+func (p *RateLimitPolicies) Policy(group string) application.TokenBucketPolicy {
+	return (*p.current.Load())[group]
+}
+
+// RateLimitRecorder counts rate limited requests. implemented by metrics.Metrics.
+type RateLimitRecorder interface {
+	RecordRateLimit(group, result string)
+}
+
+// RateLimitConfig configures the enforced rate limits.
+type RateLimitConfig struct {
+	Buckets  application.TokenBuckets
+	Policies *RateLimitPolicies
+
+	// Rules are checked in order, the first one matching the route applies.
+	// routes without a matching rule aren't limited.
+	Rules []RateLimitRule
+
+	// TrustForwardedFor limits anonymous callers by the X-Forwarded-For client ip.
+	// only safe behind a proxy that sets the header.
+	TrustForwardedFor bool
+
+	// Recorder is optional.
+	Recorder RateLimitRecorder
+}
+
+// RateLimitMiddleware refuses requests over the caller's token bucket with 429 and a
+// Retry-After header. organization api keys share their organization's bucket, other
+// authenticated callers have their user's, and anonymous ones their client ip's.
+// a failed bucket lookup lets the request through. must run after the auth middleware.
+func RateLimitMiddleware(config RateLimitConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rule, ok := matchRateLimitRule(config.Rules, c.Path())
+			if !ok {
+				return next(c)
+			}
+			policy := config.Policies.Policy(rule.Group)
+			if policy.Rate <= 0 {
+				return next(c)
+			}
+
+			var caller string
+			if orgID := GetOrganizationScope(c); orgID != "" {
+				caller = "organization:" + orgID
+			} else if userID := GetUserExternalID(c); userID != "" {
+				caller = "user:" + userID
+			} else if config.TrustForwardedFor {
+				caller = "ip:" + c.RealIP()
+			} else {
+				caller = "ip:" + echo.ExtractIPDirect()(c.Request())
+			}
+
+			status, err := config.Buckets.Take(c.Request().Context(), rule.Group+":"+caller, policy)
+			if err != nil {
+				recordRateLimit(config.Recorder, rule.Group, "error")
+				return next(c)
+			}
+			if !status.Allowed {
+				recordRateLimit(config.Recorder, rule.Group, "limited")
+				// whole seconds, rounded up so a retry at Retry-After finds a token
+				retryAfter := int64((status.RetryAfter + time.Second - 1) / time.Second)
+				c.Response().Header().Set("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+			}
+
+			recordRateLimit(config.Recorder, rule.Group, "allowed")
+			return next(c)
+		}
+	}
+}
+
+// matchRateLimitRule returns the first rule whose prefix matches the route path.
+func matchRateLimitRule(rules []RateLimitRule, path string) (RateLimitRule, bool) {
+	for _, rule := range rules {
+		if strings.HasPrefix(path, rule.Prefix) {
+			return rule, true
+		}
+	}
+	return RateLimitRule{}, false
+}
+
+func recordRateLimit(recorder RateLimitRecorder, group, result string) {
+	if recorder != nil {
+		recorder.RecordRateLimit(group, result)
+	}
+}
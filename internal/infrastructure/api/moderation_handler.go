@@ -0,0 +1,245 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// ModerationHandler handles bans, mutes, moderators and the moderation log.
+type ModerationHandler struct {
+	useCase *application.ModerationUseCase
+}
+
+// NewModerationHandler creates a new ModerationHandler.
+func NewModerationHandler(useCase *application.ModerationUseCase) *ModerationHandler {
+	return &ModerationHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the moderation routes on the given group.
+// every route requires authentication.
+func (h *ModerationHandler) RegisterRoutes(g *echo.Group) {
+	g.POST("/communities/:id/bans", h.sanction(domain.SanctionBan))
+	g.DELETE("/communities/:id/bans/:user_id", h.lift(domain.SanctionBan))
+	g.POST("/communities/:id/mutes", h.sanction(domain.SanctionMute))
+	g.DELETE("/communities/:id/mutes/:user_id", h.lift(domain.SanctionMute))
+	g.GET("/communities/:id/sanctions", h.ListSanctions)
+	g.GET("/communities/:id/moderation/log", h.AuditLog)
+	g.PUT("/communities/:id/moderators/:user_id", h.setModerator(true))
+	g.DELETE("/communities/:id/moderators/:user_id", h.setModerator(false))
+}
+
+// sanctionRequest is the request body for banning or muting a user.
+type sanctionRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Reason string `json:"reason" validate:"max=500"`
+	// Duration is a Go duration string, the sanction lasts until lifted when omitted.
+	Duration string `json:"duration" validate:"omitempty,duration"`
+}
+
+type sanctionResponse struct {
+	CommunityID string     `json:"community_id"`
+	UserID      string     `json:"user_id"`
+	Kind        string     `json:"kind"`
+	Reason      string     `json:"reason,omitempty"`
+	IssuedBy    string     `json:"issued_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type listSanctionsResponse struct {
+	Sanctions []sanctionResponse `json:"sanctions"`
+	Count     int                `json:"count"`
+}
+
+type moderationLogEntryResponse struct {
+	ID        string     `json:"id"`
+	ActorID   string     `json:"actor_id"`
+	TargetID  string     `json:"target_id"`
+	Action    string     `json:"action"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type moderationLogResponse struct {
+	Entries []moderationLogEntryResponse `json:"entries"`
+	Count   int                          `json:"count"`
+}
+
+// sanction bans or mutes a user.
+// POST /api/v1/communities/:id/bans
+// POST /api/v1/communities/:id/mutes
+// requires an owner or moderator of the community
+func (h *ModerationHandler) sanction(kind domain.SanctionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userExternalID := GetUserExternalID(c)
+		if userExternalID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+
+		var req sanctionRequest
+		if err := bindAndValidate(c, &req); err != nil {
+			return err
+		}
+
+		var duration time.Duration
+		if req.Duration != "" {
+			// already checked by the duration rule
+			duration, _ = time.ParseDuration(req.Duration)
+		}
+
+		output, err := h.useCase.Sanction(c.Request().Context(), application.SanctionInput{
+			CommunityID:         c.Param("id"),
+			TargetUserID:        req.UserID,
+			Kind:                kind,
+			Reason:              req.Reason,
+			Duration:            duration,
+			RequesterExternalID: userExternalID,
+		})
+		if err != nil {
+			return mapModerationError(err)
+		}
+
+		return c.JSON(http.StatusCreated, toSanctionResponse(*output))
+	}
+}
+
+// lift removes a ban or mute.
+// DELETE /api/v1/communities/:id/bans/:user_id
+// DELETE /api/v1/communities/:id/mutes/:user_id
+func (h *ModerationHandler) lift(kind domain.SanctionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userExternalID := GetUserExternalID(c)
+		if userExternalID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+
+		err := h.useCase.Lift(c.Request().Context(), c.Param("id"), c.Param("user_id"), kind, userExternalID)
+		if err != nil {
+			return mapModerationError(err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// setModerator promotes a member to moderator, or demotes them.
+// PUT /api/v1/communities/:id/moderators/:user_id
+// DELETE /api/v1/communities/:id/moderators/:user_id
+// requires the community creator or an admin of its organization
+func (h *ModerationHandler) setModerator(moderator bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userExternalID := GetUserExternalID(c)
+		if userExternalID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+
+		err := h.useCase.SetModerator(c.Request().Context(), c.Param("id"), c.Param("user_id"), moderator, userExternalID)
+		if err != nil {
+			return mapModerationError(err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListSanctions returns the community's active bans and mutes.
+// GET /api/v1/communities/:id/sanctions
+func (h *ModerationHandler) ListSanctions(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	sanctions, err := h.useCase.ListSanctions(c.Request().Context(), c.Param("id"), userExternalID)
+	if err != nil {
+		return mapModerationError(err)
+	}
+
+	resp := listSanctionsResponse{
+		Sanctions: make([]sanctionResponse, len(sanctions)),
+		Count:     len(sanctions),
+	}
+	for i, s := range sanctions {
+		resp.Sanctions[i] = toSanctionResponse(s)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// AuditLog returns the community's moderation history, newest first.
+// GET /api/v1/communities/:id/moderation/log?limit=50&offset=0
+func (h *ModerationHandler) AuditLog(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	limit := 50
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	entries, err := h.useCase.AuditLog(c.Request().Context(), c.Param("id"), userExternalID, limit, offset)
+	if err != nil {
+		return mapModerationError(err)
+	}
+
+	resp := moderationLogResponse{
+		Entries: make([]moderationLogEntryResponse, len(entries)),
+		Count:   len(entries),
+	}
+	for i, e := range entries {
+		resp.Entries[i] = moderationLogEntryResponse{
+			ID:        e.ID,
+			ActorID:   e.ActorID,
+			TargetID:  e.TargetID,
+			Action:    e.Action.String(),
+			Reason:    e.Reason,
+			ExpiresAt: e.ExpiresAt,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// mapModerationError converts use case errors to HTTP errors
+func mapModerationError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityModerator):
+		return echo.NewHTTPError(http.StatusForbidden, "only community owners and moderators can moderate")
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can appoint moderators")
+	case errors.Is(err, application.ErrCannotModerateUser):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrModerationReasonTooLong),
+		errors.Is(err, domain.ErrSanctionDurationInvalid):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toSanctionResponse(s application.SanctionOutput) sanctionResponse {
+	return sanctionResponse{
+		CommunityID: s.CommunityID,
+		UserID:      s.UserID,
+		Kind:        s.Kind.String(),
+		Reason:      s.Reason,
+		IssuedBy:    s.IssuedBy,
+		CreatedAt:   s.CreatedAt,
+		ExpiresAt:   s.ExpiresAt,
+	}
+}
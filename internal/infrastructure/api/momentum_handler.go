@@ -2,10 +2,12 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
 )
 
 // MomentumHandler handles momentum calculation related HTTP requests.
@@ -28,28 +30,98 @@ func (h *MomentumHandler) RegisterRoutes(g *echo.Group) {
 
 // CalculateMomentumResponse is the response for momentum calculation.
 type CalculateMomentumResponse struct {
-	CommunityID string  `json:"community_id"`
-	OldMomentum float64 `json:"old_momentum"`
-	NewMomentum float64 `json:"new_momentum"`
-	EventCount  int64   `json:"event_count"`
-	TimeWindow  string  `json:"time_window"`
-	WasUpdated  bool    `json:"was_updated"`
+	CommunityID string         `json:"community_id"`
+	OldMomentum float64        `json:"old_momentum"`
+	NewMomentum float64        `json:"new_momentum"`
+	EventCount  int64          `json:"event_count"`
+	TimeWindow  string         `json:"time_window"`
+	DecayFactor float64        `json:"decay_factor"`
+	WasUpdated  bool           `json:"was_updated"`
+	DryRun      bool           `json:"dry_run"`
+	Spike       *SpikeResponse `json:"spike,omitempty"`
+	// SpikeSuppressed means the spike wasn't notified, the community is within its spike cooldown.
+	SpikeSuppressed bool `json:"spike_suppressed,omitempty"`
+	// Percentile is the share of listed communities at or below new_momentum, 0 to 100.
+	// only on single community calculations with the leaderboard cache enabled.
+	Percentile *float64 `json:"percentile,omitempty"`
+}
+
+// SpikeResponse describes a spike that fired, or would fire in a dry run.
+type SpikeResponse struct {
+	PercentChange float64 `json:"percent_change"`
 }
 
 // CalculateAllMomentumRequest is the request body for batch momentum calculation.
 type CalculateAllMomentumRequest struct {
-	Limit int `json:"limit,omitempty"`
+	Limit  int  `json:"limit,omitempty" validate:"gte=0"`
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // CalculateAllMomentumResponse is the response for batch momentum calculation.
+// results are only included for dry runs.
 type CalculateAllMomentumResponse struct {
-	Processed int `json:"processed"`
-	Succeeded int `json:"succeeded"`
-	Failed    int `json:"failed"`
+	Processed int                         `json:"processed"`
+	Succeeded int                         `json:"succeeded"`
+	Failed    int                         `json:"failed"`
+	DryRun    bool                        `json:"dry_run"`
+	Results   []CalculateMomentumResponse `json:"results,omitempty"`
+
+	// Failures lists why communities failed, the first 20 of them.
+	Failures []MomentumFailureResponse `json:"failures,omitempty"`
+}
+
+// MomentumFailureResponse is why one community failed in a batch calculation.
+// transient failures will likely pass on the next cycle, permanent ones need a look.
+type MomentumFailureResponse struct {
+	CommunityID string `json:"community_id"`
+	Reason      string `json:"reason"`
+	Transient   bool   `json:"transient"`
+}
+
+func toMomentumFailureResponses(failures []domain.MomentumFailure) []MomentumFailureResponse {
+	if len(failures) == 0 {
+		return nil
+	}
+	resp := make([]MomentumFailureResponse, len(failures))
+	for i, f := range failures {
+		resp[i] = MomentumFailureResponse{
+			CommunityID: f.CommunityID.String(),
+			Reason:      f.Reason,
+			Transient:   f.Transient,
+		}
+	}
+	return resp
+}
+
+func toCalculateMomentumResponse(output *application.CalculateMomentumOutput) CalculateMomentumResponse {
+	resp := CalculateMomentumResponse{
+		CommunityID: output.CommunityID,
+		OldMomentum: output.OldMomentum,
+		NewMomentum: output.NewMomentum,
+		EventCount:  output.EventCount,
+		TimeWindow:  output.TimeWindow.String(),
+		DecayFactor: output.DecayFactor,
+		WasUpdated:  output.WasUpdated,
+		DryRun:      output.DryRun,
+		Percentile:  output.Percentile,
+
+		SpikeSuppressed: output.SpikeSuppressed,
+	}
+	if output.Spike != nil {
+		resp.Spike = &SpikeResponse{PercentChange: output.Spike.PercentChange}
+	}
+	return resp
+}
+
+// isDryRun reads the dry_run query parameter.
+func isDryRun(c echo.Context) bool {
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+	return dryRun
 }
 
 // CalculateMomentum handles POST /api/v1/communities/:id/momentum/calculate
 // calculates and updates momentum for a single community.
+// with ?dry_run=true the score is returned without being stored or notified.
 //
 // @Summary Calculate community momentum
 // @Description Triggers momentum recalculation for a specific community
@@ -57,6 +129,7 @@ type CalculateAllMomentumResponse struct {
 // @Accept json
 // @Produce json
 // @Param id path string true "Community ID"
+// @Param dry_run query bool false "Compute without writing or sending webhooks"
 // @Success 200 {object} CalculateMomentumResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -70,24 +143,20 @@ func (h *MomentumHandler) CalculateMomentum(c echo.Context) error {
 
 	output, err := h.calculateUseCase.Execute(c.Request().Context(), application.CalculateMomentumInput{
 		CommunityID: communityID,
+		DryRun:      isDryRun(c),
+		Percentile:  true,
 	})
 
 	if err != nil {
 		return mapDomainError(err)
 	}
 
-	return c.JSON(http.StatusOK, CalculateMomentumResponse{
-		CommunityID: output.CommunityID,
-		OldMomentum: output.OldMomentum,
-		NewMomentum: output.NewMomentum,
-		EventCount:  output.EventCount,
-		TimeWindow:  output.TimeWindow.String(),
-		WasUpdated:  output.WasUpdated,
-	})
+	return c.JSON(http.StatusOK, toCalculateMomentumResponse(output))
 }
 
 // CalculateAllMomentum handles POST /api/v1/momentum/calculate-all
 // calculates momentum for all active communities (batch operation).
+// dry runs (?dry_run=true or "dry_run": true) return every score without storing it.
 //
 // @Summary Calculate all community momentum
 // @Description Triggers momentum recalculation for all active communities
@@ -95,6 +164,7 @@ func (h *MomentumHandler) CalculateMomentum(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param body body CalculateAllMomentumRequest false "Batch options"
+// @Param dry_run query bool false "Compute without writing or sending webhooks"
 // @Success 200 {object} CalculateAllMomentumResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/momentum/calculate-all [post]
@@ -104,18 +174,30 @@ func (h *MomentumHandler) CalculateAllMomentum(c echo.Context) error {
 		// bind errors are fine here, we have defaults
 		req = CalculateAllMomentumRequest{}
 	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
 
 	output, err := h.calculateUseCase.ExecuteAll(c.Request().Context(), application.CalculateAllInput{
-		Limit: req.Limit,
+		Limit:       req.Limit,
+		DryRun:      req.DryRun || isDryRun(c),
+		TriggeredBy: domain.MomentumRunAPI,
 	})
 
 	if err != nil {
 		return mapDomainError(err)
 	}
 
-	return c.JSON(http.StatusOK, CalculateAllMomentumResponse{
+	resp := CalculateAllMomentumResponse{
 		Processed: output.Processed,
 		Succeeded: output.Succeeded,
 		Failed:    output.Failed,
-	})
+		DryRun:    req.DryRun || isDryRun(c),
+		Failures:  toMomentumFailureResponses(output.Failures),
+	}
+	for _, result := range output.Results {
+		resp.Results = append(resp.Results, toCalculateMomentumResponse(result))
+	}
+
+	return c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// MomentumSettingsHandler handles per-community momentum override endpoints.
+type MomentumSettingsHandler struct {
+	useCase *application.MomentumSettingsUseCase
+}
+
+// NewMomentumSettingsHandler creates a new MomentumSettingsHandler.
+func NewMomentumSettingsHandler(useCase *application.MomentumSettingsUseCase) *MomentumSettingsHandler {
+	return &MomentumSettingsHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the momentum settings routes on the given group.
+// reads are public, writes require the community owner.
+func (h *MomentumSettingsHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/communities/:id/momentum/settings", h.Get)
+	g.PUT("/communities/:id/momentum/settings", h.Update)
+	g.DELETE("/communities/:id/momentum/settings", h.Reset)
+	g.GET("/communities/:id/weights", h.Weights)
+	g.PUT("/communities/:id/weights", h.UpdateWeights)
+	g.DELETE("/communities/:id/weights", h.ResetWeights)
+}
+
+// updateMomentumSettingsRequest is the request body for changing momentum overrides.
+// omitted or null fields fall back to the global default.
+type updateMomentumSettingsRequest struct {
+	// TimeWindow is a Go duration string, e.g. "30m" or "6h".
+	TimeWindow  *string  `json:"time_window" validate:"omitempty,duration"`
+	DecayFactor *float64 `json:"decay_factor" validate:"omitempty,gt=0,lte=1"`
+}
+
+// updateEventWeightsRequest is the request body for overriding event weights,
+// e.g. {"weights": {"post": 8, "view": 0.2}}. event types left out keep their default weight.
+type updateEventWeightsRequest struct {
+	Weights map[string]float64 `json:"weights" validate:"required,min=1"`
+}
+
+// momentumSettingsResponse shows the overrides alongside the config they produce.
+type momentumSettingsResponse struct {
+	CommunityID string                    `json:"community_id"`
+	Overrides   momentumOverridesResponse `json:"overrides"`
+	Effective   effectiveMomentumResponse `json:"effective"`
+}
+
+type momentumOverridesResponse struct {
+	TimeWindow  *string  `json:"time_window"`
+	DecayFactor *float64 `json:"decay_factor"`
+}
+
+type effectiveMomentumResponse struct {
+	TimeWindow  string  `json:"time_window"`
+	DecayFactor float64 `json:"decay_factor"`
+}
+
+type eventWeightResponse struct {
+	EventType  string  `json:"event_type"`
+	Configured float64 `json:"configured"`
+	Overridden bool    `json:"overridden"`
+	Effective  float64 `json:"effective"`
+}
+
+type eventWeightsResponse struct {
+	CommunityID string                `json:"community_id"`
+	DecayFactor float64               `json:"decay_factor"`
+	Weights     []eventWeightResponse `json:"weights"`
+}
+
+// Get returns the momentum settings for a community.
+// GET /api/v1/communities/:id/momentum/settings
+func (h *MomentumSettingsHandler) Get(c echo.Context) error {
+	output, err := h.useCase.Get(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return mapMomentumSettingsError(err)
+	}
+	return c.JSON(http.StatusOK, toMomentumSettingsResponse(output))
+}
+
+// Weights returns the configured and effective weight of every event type for a community.
+// GET /api/v1/communities/:id/weights
+func (h *MomentumSettingsHandler) Weights(c echo.Context) error {
+	output, err := h.useCase.Weights(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return mapMomentumSettingsError(err)
+	}
+	return c.JSON(http.StatusOK, toEventWeightsResponse(output))
+}
+
+// UpdateWeights replaces the event weight overrides for a community.
+// PUT /api/v1/communities/:id/weights
+// requires authentication as the community creator
+func (h *MomentumSettingsHandler) UpdateWeights(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateEventWeightsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.useCase.UpdateWeights(c.Request().Context(), application.UpdateEventWeightsInput{
+		CommunityID:         c.Param("id"),
+		Weights:             req.Weights,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapMomentumSettingsError(err)
+	}
+
+	return c.JSON(http.StatusOK, toEventWeightsResponse(output))
+}
+
+// ResetWeights removes the event weight overrides for a community.
+// DELETE /api/v1/communities/:id/weights
+// requires authentication as the community creator
+func (h *MomentumSettingsHandler) ResetWeights(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.ResetWeights(c.Request().Context(), c.Param("id"), userExternalID); err != nil {
+		return mapMomentumSettingsError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Update replaces the momentum overrides for a community.
+// PUT /api/v1/communities/:id/momentum/settings
+// requires authentication as the community creator
+func (h *MomentumSettingsHandler) Update(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateMomentumSettingsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	var timeWindow *time.Duration
+	if req.TimeWindow != nil {
+		// already checked by the duration rule
+		parsed, _ := time.ParseDuration(*req.TimeWindow)
+		timeWindow = &parsed
+	}
+
+	output, err := h.useCase.Update(c.Request().Context(), application.UpdateMomentumSettingsInput{
+		CommunityID:         c.Param("id"),
+		TimeWindow:          timeWindow,
+		DecayFactor:         req.DecayFactor,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapMomentumSettingsError(err)
+	}
+
+	return c.JSON(http.StatusOK, toMomentumSettingsResponse(output))
+}
+
+// Reset removes all momentum overrides for a community.
+// DELETE /api/v1/communities/:id/momentum/settings
+// requires authentication as the community creator
+func (h *MomentumSettingsHandler) Reset(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.Reset(c.Request().Context(), c.Param("id"), userExternalID); err != nil {
+		return mapMomentumSettingsError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// mapMomentumSettingsError converts use case errors to HTTP errors
+func mapMomentumSettingsError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can change momentum settings")
+	case errors.Is(err, application.ErrEventWeightOverridesDisabled):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrMomentumTimeWindowOutOfRange),
+		errors.Is(err, domain.ErrMomentumTimeWindowPrecision),
+		errors.Is(err, domain.ErrDecayFactorOutOfRange),
+		errors.Is(err, domain.ErrWeightOutOfRange),
+		errors.Is(err, domain.ErrInvalidEventType),
+		errors.Is(err, domain.ErrNoEventWeights):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toEventWeightsResponse(output *application.EventWeightsOutput) eventWeightsResponse {
+	resp := eventWeightsResponse{
+		CommunityID: output.CommunityID,
+		DecayFactor: output.DecayFactor,
+		Weights:     make([]eventWeightResponse, len(output.Weights)),
+	}
+	for i, w := range output.Weights {
+		resp.Weights[i] = eventWeightResponse{
+			EventType:  w.EventType.String(),
+			Configured: w.Configured,
+			Overridden: w.Overridden,
+			Effective:  w.Effective,
+		}
+	}
+	return resp
+}
+
+func toMomentumSettingsResponse(output *application.MomentumSettingsOutput) momentumSettingsResponse {
+	resp := momentumSettingsResponse{
+		CommunityID: output.CommunityID,
+		Overrides: momentumOverridesResponse{
+			DecayFactor: output.DecayFactor,
+		},
+		Effective: effectiveMomentumResponse{
+			TimeWindow:  output.Effective.TimeWindow.String(),
+			DecayFactor: output.Effective.DecayFactor,
+		},
+	}
+	if output.TimeWindow != nil {
+		tw := output.TimeWindow.String()
+		resp.Overrides.TimeWindow = &tw
+	}
+	return resp
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// OrganizationHandler handles organization endpoints.
+// everything requires authentication; the use case checks the requester's role.
+type OrganizationHandler struct {
+	useCase   *application.OrganizationUseCase
+	summaries CommunitySummaries
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler.
+// without summaries, the organization leaderboard leaves out member counts and recent activity.
+func NewOrganizationHandler(useCase *application.OrganizationUseCase, summaries CommunitySummaries) *OrganizationHandler {
+	return &OrganizationHandler{useCase: useCase, summaries: summaries}
+}
+
+// RegisterRoutes registers the organization routes on the given group.
+func (h *OrganizationHandler) RegisterRoutes(g *echo.Group) {
+	g.POST("/organizations", h.Create)
+	g.GET("/organizations/:id", h.Get)
+	g.GET("/organizations/:id/members", h.ListMembers)
+	g.POST("/organizations/:id/members", h.AddMember)
+	g.PUT("/organizations/:id/members/:user_id", h.UpdateMember)
+	g.DELETE("/organizations/:id/members/:user_id", h.RemoveMember)
+	g.POST("/organizations/:id/communities", h.AttachCommunity)
+	g.DELETE("/organizations/:id/communities/:community_id", h.DetachCommunity)
+	g.GET("/organizations/:id/leaderboard", h.Leaderboard)
+	g.POST("/organizations/:id/api-keys", h.CreateAPIKey)
+}
+
+type createOrganizationRequest struct {
+	Slug string `json:"slug" validate:"required,min=3,max=100"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type organizationResponse struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	Role      string    `json:"role"`
+}
+
+type addMemberRequest struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type updateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type memberResponse struct {
+	UserID   string    `json:"user_id"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type listMembersResponse struct {
+	Members []memberResponse `json:"members"`
+	Count   int              `json:"count"`
+}
+
+type attachCommunityRequest struct {
+	CommunityID string `json:"community_id" validate:"required,uuid"`
+}
+
+type createOrganizationAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type organizationAPIKeyResponse struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id"`
+	Key            string `json:"key"`
+}
+
+// Create creates an organization owned by the requester.
+// POST /api/v1/organizations
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req createOrganizationRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.useCase.Create(c.Request().Context(), application.CreateOrganizationInput{
+		Slug:                req.Slug,
+		Name:                req.Name,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.JSON(http.StatusCreated, toOrganizationResponse(output))
+}
+
+// Get returns an organization the requester belongs to.
+// GET /api/v1/organizations/:id
+func (h *OrganizationHandler) Get(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	output, err := h.useCase.Get(c.Request().Context(), c.Param("id"), userExternalID)
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.JSON(http.StatusOK, toOrganizationResponse(output))
+}
+
+// ListMembers lists the organization's members.
+// GET /api/v1/organizations/:id/members
+func (h *OrganizationHandler) ListMembers(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	members, err := h.useCase.ListMembers(c.Request().Context(), c.Param("id"), userExternalID)
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	resp := listMembersResponse{
+		Members: make([]memberResponse, 0, len(members)),
+		Count:   len(members),
+	}
+	for _, m := range members {
+		resp.Members = append(resp.Members, toMemberResponse(m))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// AddMember adds a user to the organization.
+// POST /api/v1/organizations/:id/members
+// requires owner or admin; only owners can add owners
+func (h *OrganizationHandler) AddMember(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req addMemberRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	member, err := h.useCase.AddMember(c.Request().Context(), application.AddMemberInput{
+		OrganizationID:      c.Param("id"),
+		Username:            req.Username,
+		Role:                req.Role,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.JSON(http.StatusCreated, toMemberResponse(*member))
+}
+
+// UpdateMember changes a member's role.
+// PUT /api/v1/organizations/:id/members/:user_id
+func (h *OrganizationHandler) UpdateMember(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateMemberRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	member, err := h.useCase.UpdateMemberRole(c.Request().Context(), application.UpdateMemberRoleInput{
+		OrganizationID:      c.Param("id"),
+		UserID:              c.Param("user_id"),
+		Role:                req.Role,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.JSON(http.StatusOK, toMemberResponse(*member))
+}
+
+// RemoveMember removes a member, or lets a member leave.
+// DELETE /api/v1/organizations/:id/members/:user_id
+func (h *OrganizationHandler) RemoveMember(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.RemoveMember(c.Request().Context(), c.Param("id"), c.Param("user_id"), userExternalID); err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AttachCommunity moves one of the requester's communities into the organization.
+// POST /api/v1/organizations/:id/communities
+func (h *OrganizationHandler) AttachCommunity(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req attachCommunityRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.useCase.AttachCommunity(c.Request().Context(), c.Param("id"), req.CommunityID, userExternalID); err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DetachCommunity makes an organization community standalone again.
+// DELETE /api/v1/organizations/:id/communities/:community_id
+func (h *OrganizationHandler) DetachCommunity(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.DetachCommunity(c.Request().Context(), c.Param("id"), c.Param("community_id"), userExternalID); err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Leaderboard ranks the organization's communities by momentum.
+// GET /api/v1/organizations/:id/leaderboard?limit=20&offset=0
+func (h *OrganizationHandler) Leaderboard(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	limit := 20
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	communities, err := h.useCase.Leaderboard(c.Request().Context(), c.Param("id"), userExternalID, limit, offset)
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.JSON(http.StatusOK, toCommunityListResponse(c.Request().Context(), h.summaries, communities, limit, offset))
+}
+
+// CreateAPIKey issues an api key limited to the organization.
+// POST /api/v1/organizations/:id/api-keys
+// the key is returned once and acts as the requester within the organization
+func (h *OrganizationHandler) CreateAPIKey(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req createOrganizationAPIKeyRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.useCase.CreateAPIKey(c.Request().Context(), c.Param("id"), req.Name, userExternalID)
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	return c.JSON(http.StatusCreated, organizationAPIKeyResponse{
+		ID:             output.ID,
+		Name:           output.Name,
+		OrganizationID: output.OrganizationID,
+		Key:            output.Key,
+	})
+}
+
+// mapOrganizationError converts use case errors to HTTP errors
+func mapOrganizationError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrOrganizationSlugTaken):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, application.ErrOrganizationMemberExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, application.ErrCommunityInOtherOrganization):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, application.ErrOrganizationPermissionDenied),
+		errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, application.ErrCreatorNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "user profile not found - please complete signup first")
+	case errors.Is(err, domain.ErrLastOrganizationOwner):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvalidOrganizationRole),
+		errors.Is(err, domain.ErrOrganizationNameEmpty),
+		errors.Is(err, domain.ErrOrganizationNameTooLong),
+		errors.Is(err, domain.ErrAPIKeyNameEmpty),
+		errors.Is(err, application.ErrCommunityNotInOrganization):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toOrganizationResponse(o *application.OrganizationOutput) organizationResponse {
+	return organizationResponse{
+		ID:        o.ID,
+		Slug:      o.Slug,
+		Name:      o.Name,
+		CreatedBy: o.CreatedBy,
+		CreatedAt: o.CreatedAt,
+		Role:      o.Role,
+	}
+}
+
+func toMemberResponse(m application.OrganizationMemberOutput) memberResponse {
+	return memberResponse{
+		UserID:   m.UserID,
+		Role:     m.Role,
+		JoinedAt: m.JoinedAt,
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// PreferencesHandler handles the authenticated user's notification preferences.
+type PreferencesHandler struct {
+	useCase *application.NotificationPreferencesUseCase
+}
+
+// NewPreferencesHandler creates a new PreferencesHandler.
+func NewPreferencesHandler(useCase *application.NotificationPreferencesUseCase) *PreferencesHandler {
+	return &PreferencesHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the preferences routes on the given group.
+// all routes require authentication and act on the caller's own preferences.
+func (h *PreferencesHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/me/preferences", h.Get)
+	g.PUT("/me/preferences", h.Update)
+	g.DELETE("/me/preferences", h.Reset)
+}
+
+// updatePreferencesRequest is the request body for replacing notification preferences.
+// omitted fields are cleared.
+type updatePreferencesRequest struct {
+	// MaxNotificationsPerHour caps deliveries per rolling hour, 0 or omitted is unlimited.
+	MaxNotificationsPerHour int                `json:"max_notifications_per_hour" validate:"gte=0"`
+	MutedCommunityIDs       []string           `json:"muted_community_ids" validate:"max=500,dive,uuid"`
+	QuietHours              *quietHoursRequest `json:"quiet_hours"`
+}
+
+type quietHoursRequest struct {
+	// Start and End are HH:MM in the timezone, End may be before Start to cross midnight.
+	Start    string `json:"start" validate:"required"`
+	End      string `json:"end" validate:"required"`
+	Timezone string `json:"timezone" validate:"required"`
+}
+
+// preferencesResponse is the API representation of notification preferences.
+type preferencesResponse struct {
+	MaxNotificationsPerHour int                 `json:"max_notifications_per_hour"`
+	MutedCommunityIDs       []string            `json:"muted_community_ids"`
+	QuietHours              *quietHoursResponse `json:"quiet_hours"`
+	UpdatedAt               *time.Time          `json:"updated_at,omitempty"` // left out until preferences are set
+}
+
+type quietHoursResponse struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// Get returns the caller's notification preferences.
+// GET /api/v1/me/preferences
+func (h *PreferencesHandler) Get(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	output, err := h.useCase.Get(c.Request().Context(), userExternalID)
+	if err != nil {
+		return mapPreferencesError(err)
+	}
+	return c.JSON(http.StatusOK, toPreferencesResponse(output))
+}
+
+// Update replaces the caller's notification preferences.
+// PUT /api/v1/me/preferences
+func (h *PreferencesHandler) Update(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updatePreferencesRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	input := application.UpdateNotificationPreferencesInput{
+		RequesterExternalID: userExternalID,
+		MaxPerHour:          req.MaxNotificationsPerHour,
+		MutedCommunityIDs:   req.MutedCommunityIDs,
+	}
+	if q := req.QuietHours; q != nil {
+		input.QuietHours = &application.QuietHoursSettings{
+			Start:    q.Start,
+			End:      q.End,
+			Timezone: q.Timezone,
+		}
+	}
+
+	output, err := h.useCase.Update(c.Request().Context(), input)
+	if err != nil {
+		return mapPreferencesError(err)
+	}
+	return c.JSON(http.StatusOK, toPreferencesResponse(output))
+}
+
+// Reset removes the caller's notification preferences, so every notification is sent again.
+// DELETE /api/v1/me/preferences
+func (h *PreferencesHandler) Reset(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.Reset(c.Request().Context(), userExternalID); err != nil {
+		return mapPreferencesError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// mapPreferencesError converts use case errors to HTTP errors
+func mapPreferencesError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMaxNotificationsPerHour),
+		errors.Is(err, domain.ErrTooManyMutedCommunities),
+		errors.Is(err, domain.ErrInvalidQuietHours),
+		errors.Is(err, domain.ErrInvalidTimezone):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toPreferencesResponse(output *application.NotificationPreferencesOutput) preferencesResponse {
+	resp := preferencesResponse{
+		MaxNotificationsPerHour: output.MaxPerHour,
+		MutedCommunityIDs:       output.MutedCommunityIDs,
+		UpdatedAt:               output.UpdatedAt,
+	}
+	if q := output.QuietHours; q != nil {
+		resp.QuietHours = &quietHoursResponse{
+			Start:    q.Start,
+			End:      q.End,
+			Timezone: q.Timezone,
+		}
+	}
+	return resp
+}
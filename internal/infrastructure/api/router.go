@@ -1,12 +1,15 @@
 package api
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/joacominatel/pulse/internal/application"
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/auth"
+	"github.com/joacominatel/pulse/internal/infrastructure/health"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 	"github.com/joacominatel/pulse/internal/infrastructure/metrics"
 )
@@ -14,11 +17,52 @@ import (
 // RouterConfig holds dependencies for route registration.
 type RouterConfig struct {
 	IngestEventUseCase       *application.IngestEventUseCase
+	IngestEventGroupUseCase  *application.IngestEventGroupUseCase
 	CalculateMomentumUseCase *application.CalculateMomentumUseCase
 	CreateCommunityUseCase   *application.CreateCommunityUseCase
+	MomentumSettingsUseCase  *application.MomentumSettingsUseCase
+	CommunityEventsUseCase   *application.CommunityEventsUseCase
+	RebuildLeaderboard       *application.RebuildLeaderboardUseCase
+	AnomalyUseCase           *application.AnomalyUseCase
+	LeaderboardUseCase       *application.LeaderboardUseCase
+	CommunityPinUseCase      *application.CommunityPinUseCase
+	FeedUseCase              *application.FeedUseCase
+	OrganizationUseCase      *application.OrganizationUseCase
+	UsageUseCase             *application.UsageUseCase
+	InvitationUseCase        *application.InvitationUseCase
+	CommunityStatsUseCase    *application.CommunityStatsUseCase
+	CommunityVisibility      *application.CommunityVisibilityUseCase
+	CommunityDetails         *application.CommunityDetailsUseCase
+	CommunitySummaries       CommunitySummaries
+	ModerationUseCase        *application.ModerationUseCase
+	NotificationPreferences  *application.NotificationPreferencesUseCase
+	UserLookupUseCase        *application.UserLookupUseCase
+	UserProfileUseCase       *application.UserProfileUseCase
+	EventImportUseCase       *application.EventImportUseCase
+	LiveHub                  LiveHub
+	JobUseCase               *application.JobUseCase
+	WebhookAllowlistUseCase  *application.WebhookAllowlistUseCase
+	WebhookTransferUseCase   *application.WebhookTransferUseCase
+	EventImportQueue         EventImportQueue
+	EventImportMaxBytes      int64
+	EventImportUploadTimeout time.Duration
+	Meter                    UsageMeter
+	RateLimiter              RequestRateLimiter
+	RateLimitBuckets         application.TokenBuckets
+	RateLimitPolicies        *RateLimitPolicies // by group, RateLimitGroupEvents and RateLimitGroupAPI
+	TrustForwardedFor        bool
 	CommunityRepo            domain.CommunityRepository
 	WebhookSubscriptionRepo  domain.WebhookSubscriptionRepository
+	WebhookDeliveryRepo      domain.WebhookDeliveryRepository
+	RejectedEventRepo        domain.RejectedEventRepository
+	MomentumRunRepo          domain.MomentumRunRepository
+	CommunityCache           CommunityCache
+	MomentumWorker           MomentumWorker
+	Standby                  StandbySwitch
+	AllowSubscriptionProxy   bool
+	HealthMonitor            *health.Monitor
 	JWTValidator             *auth.JWTValidator
+	APIKeyAuthenticator      APIKeyAuthenticator
 	Logger                   *logging.Logger
 	Metrics                  *metrics.Metrics
 }
@@ -36,12 +80,19 @@ func RegisterRoutes(e *echo.Echo, config *RouterConfig) {
 			},
 		)))
 
-		// apply metrics middleware to all routes
-		e.Use(metrics.Middleware(config.Metrics))
+		// before routing and every other middleware, so all requests are measured
+		// with the status the client actually got
+		e.Pre(metrics.Middleware(config.Metrics))
 	}
 
 	// health endpoints (no auth required)
 	RegisterHealthRoutes(e)
+	if config.HealthMonitor != nil {
+		RegisterStatusRoute(e, config.HealthMonitor)
+	}
+
+	// admin dashboard (static, its data comes from the admin routes below)
+	RegisterDashboardRoutes(e)
 
 	// api v1 group with auth
 	v1 := e.Group("/api/v1")
@@ -49,6 +100,7 @@ func RegisterRoutes(e *echo.Echo, config *RouterConfig) {
 	// configure auth middleware with public routes skipper
 	authConfig := AuthConfig{
 		JWTValidator: config.JWTValidator,
+		APIKeys:      config.APIKeyAuthenticator,
 		Skipper: PublicRoutesSkipper(
 			"/health",
 			"/ready",
@@ -59,33 +111,170 @@ func RegisterRoutes(e *echo.Echo, config *RouterConfig) {
 	// individual handlers decide what to do with the user context
 	v1.Use(OptionalAuthMiddleware(authConfig))
 
+	// request-scoped log fields for handlers and use cases
+	v1.Use(RequestContextMiddleware())
+
+	// organization api keys stay inside their organization
+	v1.Use(OrganizationScopeMiddleware())
+
+	// request budget headers for authenticated callers, before the enforced limits so a 429 has them too
+	if config.RateLimiter != nil {
+		v1.Use(RateLimitHeadersMiddleware(config.RateLimiter))
+	}
+
+	// enforced limits per route group, before metering so refused calls aren't billed.
+	// installed even when every group is unlimited, a config reload may limit them later
+	if config.RateLimitBuckets != nil && config.RateLimitPolicies != nil {
+		rateLimit := RateLimitConfig{
+			Buckets:  config.RateLimitBuckets,
+			Policies: config.RateLimitPolicies,
+			Rules: []RateLimitRule{
+				{Group: RateLimitGroupEvents, Prefix: "/api/v1/events"},
+				{Group: RateLimitGroupAPI, Prefix: ""},
+			},
+			TrustForwardedFor: config.TrustForwardedFor,
+		}
+		if config.Metrics != nil {
+			rateLimit.Recorder = config.Metrics
+		}
+		v1.Use(RateLimitMiddleware(rateLimit))
+	}
+
+	// count authenticated calls for billing
+	if config.Meter != nil {
+		v1.Use(MeteringMiddleware(config.Meter))
+	}
+
 	// register domain handlers
 	if config.IngestEventUseCase != nil {
-		eventHandler := NewEventHandler(config.IngestEventUseCase)
+		eventHandler := NewEventHandler(config.IngestEventUseCase, config.IngestEventGroupUseCase)
 		eventHandler.RegisterRoutes(v1)
 	}
 
+	if config.EventImportUseCase != nil && config.EventImportQueue != nil {
+		importHandler := NewEventImportHandler(config.EventImportUseCase, config.EventImportQueue, config.EventImportMaxBytes, config.EventImportUploadTimeout)
+		importHandler.RegisterRoutes(v1)
+	}
+
+	if config.JobUseCase != nil {
+		jobHandler := NewJobHandler(config.JobUseCase)
+		jobHandler.RegisterRoutes(v1)
+	}
+
+	eventTypeHandler := NewEventTypeHandler()
+	eventTypeHandler.RegisterRoutes(v1)
+
 	if config.CalculateMomentumUseCase != nil {
 		momentumHandler := NewMomentumHandler(config.CalculateMomentumUseCase)
 		momentumHandler.RegisterRoutes(v1)
 	}
 
+	if config.MomentumSettingsUseCase != nil {
+		momentumSettingsHandler := NewMomentumSettingsHandler(config.MomentumSettingsUseCase)
+		momentumSettingsHandler.RegisterRoutes(v1)
+	}
+
+	if config.CommunityEventsUseCase != nil {
+		communityEventHandler := NewCommunityEventHandler(config.CommunityEventsUseCase)
+		communityEventHandler.RegisterRoutes(v1)
+	}
+
 	if config.CommunityRepo != nil {
-		communityHandler := NewCommunityHandler(config.CommunityRepo, config.CreateCommunityUseCase)
+		communityHandler := NewCommunityHandler(
+			config.CommunityRepo,
+			config.CreateCommunityUseCase,
+			config.CommunityStatsUseCase,
+			config.CommunityVisibility,
+			config.CommunityDetails,
+			config.CommunitySummaries,
+		)
 		communityHandler.RegisterRoutes(v1)
 	}
 
+	if config.CommunityStatsUseCase != nil && config.LiveHub != nil {
+		liveHandler := NewLiveHandler(config.CommunityStatsUseCase, config.LiveHub, config.JWTValidator, config.Logger)
+		liveHandler.RegisterRoutes(v1)
+	}
+
+	if config.LeaderboardUseCase != nil {
+		leaderboardHandler := NewLeaderboardHandler(config.LeaderboardUseCase)
+		leaderboardHandler.RegisterRoutes(v1)
+	}
+
+	if config.FeedUseCase != nil {
+		feedHandler := NewFeedHandler(config.FeedUseCase)
+		feedHandler.RegisterRoutes(v1)
+	}
+
 	// subscription routes (protected - require auth)
 	if config.WebhookSubscriptionRepo != nil {
-		subscriptionHandler := NewSubscriptionHandler(config.WebhookSubscriptionRepo)
+		subscriptionHandler := NewSubscriptionHandler(config.WebhookSubscriptionRepo, config.WebhookDeliveryRepo, config.WebhookAllowlistUseCase, config.AllowSubscriptionProxy)
 		subscriptionHandler.RegisterRoutes(v1)
 	}
 
+	if config.WebhookAllowlistUseCase != nil {
+		allowlistHandler := NewWebhookAllowlistHandler(config.WebhookAllowlistUseCase)
+		allowlistHandler.RegisterRoutes(v1)
+	}
+
+	if config.WebhookTransferUseCase != nil {
+		transferHandler := NewWebhookTransferHandler(config.WebhookTransferUseCase)
+		transferHandler.RegisterRoutes(v1)
+	}
+
+	if config.NotificationPreferences != nil {
+		preferencesHandler := NewPreferencesHandler(config.NotificationPreferences)
+		preferencesHandler.RegisterRoutes(v1)
+	}
+
+	if config.UserLookupUseCase != nil {
+		userHandler := NewUserHandler(config.UserLookupUseCase, config.UserProfileUseCase)
+		userHandler.RegisterRoutes(v1)
+	}
+
+	if config.OrganizationUseCase != nil {
+		organizationHandler := NewOrganizationHandler(config.OrganizationUseCase, config.CommunitySummaries)
+		organizationHandler.RegisterRoutes(v1)
+	}
+
+	if config.InvitationUseCase != nil {
+		invitationHandler := NewInvitationHandler(config.InvitationUseCase)
+		invitationHandler.RegisterRoutes(v1)
+	}
+
+	if config.ModerationUseCase != nil {
+		moderationHandler := NewModerationHandler(config.ModerationUseCase)
+		moderationHandler.RegisterRoutes(v1)
+	}
+
+	if config.UsageUseCase != nil {
+		usageHandler := NewUsageHandler(config.UsageUseCase)
+		usageHandler.RegisterRoutes(v1)
+	}
+
+	// admin routes (require an admin token)
+	adminHandler := NewAdminHandler(
+		config.RebuildLeaderboard,
+		config.AnomalyUseCase,
+		config.RejectedEventRepo,
+		config.CommunityCache,
+		config.CommunityPinUseCase,
+		config.MomentumWorker,
+		config.Standby,
+		config.MomentumRunRepo,
+	)
+	adminHandler.RegisterRoutes(v1)
+
 	metricsEnabled := config.Metrics != nil
+	healthEndpoints := []string{"/health", "/ready"}
+	if config.HealthMonitor != nil {
+		healthEndpoints = append(healthEndpoints, "/statusz")
+	}
 	config.Logger.Info("api routes registered",
 		"version", "v1",
-		"health_endpoints", []string{"/health", "/ready"},
+		"health_endpoints", healthEndpoints,
 		"metrics_enabled", metricsEnabled,
 		"api_prefix", "/api/v1",
+		"admin_dashboard", "/admin/",
 	)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -17,7 +19,16 @@ type ServerConfig struct {
 	Port            string
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
 	ShutdownTimeout time.Duration
+
+	// DrainDelay is how long the server keeps serving after Drain with /ready failing,
+	// so load balancers stop sending it requests before it stops accepting them.
+	DrainDelay time.Duration
+
+	// ReusePort sets SO_REUSEPORT on the socket, so the next process can take over the
+	// port while this one drains. ignored under systemd socket activation.
+	ReusePort bool
 }
 
 // DefaultServerConfig returns sensible defaults.
@@ -26,15 +37,26 @@ func DefaultServerConfig() ServerConfig {
 		Port:            ":8080",
 		ReadTimeout:     15 * time.Second,
 		WriteTimeout:    15 * time.Second,
+		IdleTimeout:     60 * time.Second,
 		ShutdownTimeout: 10 * time.Second,
 	}
 }
 
+// StandbySwitch switches this instance between active and warm standby.
+// implemented by standby.Switch.
+type StandbySwitch interface {
+	Standby() bool
+	Since() time.Time
+	Set(standby bool) bool
+}
+
 // Server wraps the Echo instance and provides lifecycle management.
 type Server struct {
-	echo   *echo.Echo
-	config ServerConfig
-	logger *logging.Logger
+	echo     *echo.Echo
+	config   ServerConfig
+	logger   *logging.Logger
+	draining atomic.Bool
+	standby  StandbySwitch
 }
 
 // NewServer creates a new HTTP server with Echo.
@@ -43,6 +65,16 @@ func NewServer(config ServerConfig, logger *logging.Logger) *Server {
 	e.HideBanner = true
 	e.HidePort = true
 
+	s := &Server{
+		echo:   e,
+		config: config,
+		logger: logger.WithComponent("http_server"),
+	}
+
+	// ahead of routing, so a draining server fails readiness whatever routes are registered
+	e.Pre(s.drainMiddleware())
+	e.Pre(s.standbyMiddleware())
+
 	// configure base middleware
 	e.Use(middleware.Recover())
 	e.Use(middleware.RequestID())
@@ -55,14 +87,19 @@ func NewServer(config ServerConfig, logger *logging.Logger) *Server {
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
 	}))
 
+	// request bodies are checked against their validate tags
+	e.Validator = newRequestValidator()
+
 	// custom error handler
 	e.HTTPErrorHandler = customErrorHandler(logger)
 
-	return &Server{
-		echo:   e,
-		config: config,
-		logger: logger.WithComponent("http_server"),
-	}
+	return s
+}
+
+// WithStandby rejects writes while the instance is a standby, see standbyMiddleware.
+func (s *Server) WithStandby(sw StandbySwitch) *Server {
+	s.standby = sw
+	return s
 }
 
 // Echo returns the underlying Echo instance for route registration.
@@ -73,28 +110,106 @@ func (s *Server) Echo() *echo.Echo {
 // Start begins listening for HTTP requests.
 // blocks until the server is stopped.
 func (s *Server) Start() error {
+	ln, source, err := listen(s.config.Port, s.config.ReusePort)
+	if err != nil {
+		return err
+	}
+
 	s.logger.Info("http server starting",
-		"port", s.config.Port,
+		"address", ln.Addr().String(),
+		"listener", source,
 		"read_timeout", s.config.ReadTimeout.String(),
 		"write_timeout", s.config.WriteTimeout.String(),
+		"idle_timeout", s.config.IdleTimeout.String(),
 	)
 
 	server := &http.Server{
 		Addr:         s.config.Port,
 		ReadTimeout:  s.config.ReadTimeout,
 		WriteTimeout: s.config.WriteTimeout,
+		IdleTimeout:  s.config.IdleTimeout,
 	}
 
+	s.echo.Listener = ln
 	if err := s.echo.StartServer(server); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 	return nil
 }
 
-// Shutdown gracefully stops the server.
+// Drain fails readiness and closes keep-alive connections after their next response,
+// then waits out the drain delay while requests keep being served. call it before
+// Shutdown, so a deploy moves traffic to other instances instead of dropping it.
+func (s *Server) Drain(ctx context.Context) {
+	if !s.draining.CompareAndSwap(false, true) || s.config.DrainDelay <= 0 {
+		return
+	}
+
+	s.logger.Info("http server draining", "delay", s.config.DrainDelay.String())
+	select {
+	case <-ctx.Done():
+	case <-time.After(s.config.DrainDelay):
+	}
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests until ctx is done.
+// connections still open then are closed.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.draining.Store(true)
 	s.logger.Info("http server shutting down")
-	return s.echo.Shutdown(ctx)
+	if err := s.echo.Shutdown(ctx); err != nil {
+		_ = s.echo.Close()
+		return err
+	}
+	return nil
+}
+
+// drainMiddleware answers /ready with 503 once the server drains, and asks clients
+// to reconnect, which a load balancer sends to an instance that isn't draining.
+func (s *Server) drainMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !s.draining.Load() {
+				return next(c)
+			}
+			c.Response().Header().Set(echo.HeaderConnection, "close")
+			if c.Request().URL.Path == "/ready" {
+				return c.JSON(http.StatusServiceUnavailable, HealthResponse{
+					Status:  "draining",
+					Service: "pulse",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// standbyMiddleware answers /ready with a standby status and rejects writes while the
+// instance is a standby, so a load balancer can keep sending it reads. admin routes stay
+// open, that's where the standby is made active.
+func (s *Server) standbyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if s.standby == nil || !s.standby.Standby() {
+				return next(c)
+			}
+			req := c.Request()
+			if req.URL.Path == "/ready" {
+				return c.JSON(http.StatusOK, HealthResponse{
+					Status:  "standby",
+					Service: "pulse",
+				})
+			}
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+			if strings.HasPrefix(req.URL.Path, "/api/v1/admin/") {
+				return next(c)
+			}
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "instance is a standby, send writes to the active instance")
+		}
+	}
 }
 
 // requestLogger creates a middleware that logs requests using our structured logger.
@@ -112,7 +227,7 @@ func requestLogger(logger *logging.Logger) echo.MiddlewareFunc {
 			if v.Error != nil {
 				l.Warn("request error",
 					"method", v.Method,
-					"uri", v.URI,
+					"uri", redactStreamToken(v.URI),
 					"status", v.Status,
 					"latency_ms", v.Latency.Milliseconds(),
 					"error", v.Error.Error(),
@@ -121,7 +236,7 @@ func requestLogger(logger *logging.Logger) echo.MiddlewareFunc {
 			} else {
 				l.Info("request",
 					"method", v.Method,
-					"uri", v.URI,
+					"uri", redactStreamToken(v.URI),
 					"status", v.Status,
 					"latency_ms", v.Latency.Milliseconds(),
 					"request_id", c.Response().Header().Get(echo.HeaderXRequestID),
@@ -141,6 +256,23 @@ func customErrorHandler(logger *logging.Logger) echo.HTTPErrorHandler {
 			return
 		}
 
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			if c.Request().Method == http.MethodHead {
+				err = c.NoContent(http.StatusUnprocessableEntity)
+			} else {
+				err = c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+					Error:   http.StatusText(http.StatusUnprocessableEntity),
+					Message: "validation failed",
+					Fields:  ve.Fields,
+				})
+			}
+			if err != nil {
+				l.Error("failed to send error response", "error", err.Error())
+			}
+			return
+		}
+
 		var he *echo.HTTPError
 		if errors.As(err, &he) {
 			if he.Internal != nil {
@@ -185,4 +317,6 @@ func customErrorHandler(logger *logging.Logger) echo.HTTPErrorHandler {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message any    `json:"message"`
+	// Fields lists each invalid field on 422 responses.
+	Fields []FieldError `json:"fields,omitempty"`
 }
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/auth"
+)
+
+const (
+	// StreamTokenParam carries the JWT on streaming routes.
+	// browsers can't set Authorization on a WebSocket, and EventSource can't set headers at all.
+	StreamTokenParam = "access_token"
+
+	// StreamAuthMessageType is the type of the message a connection authenticates with
+	// when it didn't send a token in the url.
+	StreamAuthMessageType = "auth"
+
+	// StreamAuthTimeout is how long a connection has to send its auth message.
+	StreamAuthTimeout = 10 * time.Second
+
+	// DefaultStreamSubscriptionLimit is how many communities one connection can follow.
+	DefaultStreamSubscriptionLimit = 20
+)
+
+var (
+	ErrStreamAuthMessage       = errors.New("first stream message must be an auth message")
+	ErrStreamSubscriptionLimit = errors.New("stream subscription limit reached")
+)
+
+// StreamAuthMiddleware authenticates streaming routes from the access_token query parameter
+// when the request didn't authenticate with a header. must run after OptionalAuthMiddleware.
+// a bad token is rejected; a missing one is let through, so the handler can upgrade the
+// connection and wait for an auth message with AuthenticateStreamMessage.
+func StreamAuthMiddleware(validator *auth.JWTValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if GetUserExternalID(c) != "" || GetClaims(c) != nil {
+				return next(c)
+			}
+
+			token := c.QueryParam(StreamTokenParam)
+			if token == "" {
+				return next(c)
+			}
+			if validator == nil {
+				return mapAuthError(auth.ErrMissingToken)
+			}
+
+			claims, err := validator.ValidateToken(token)
+			if err != nil {
+				return mapAuthError(err)
+			}
+
+			c.Set(string(UserContextKey), claims.UserID())
+			c.Set(string(ClaimsContextKey), claims)
+			return next(c)
+		}
+	}
+}
+
+// streamAuthMessage is the first message of a connection that didn't authenticate in the url.
+type streamAuthMessage struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+}
+
+// AuthenticateStreamMessage validates the token in a connection's first message:
+//
+//	{"type": "auth", "token": "<jwt>"}
+//
+// anything else is ErrStreamAuthMessage, and the connection should be closed.
+func AuthenticateStreamMessage(validator *auth.JWTValidator, msg []byte) (*auth.SupabaseClaims, error) {
+	var m streamAuthMessage
+	if err := json.Unmarshal(msg, &m); err != nil || m.Type != StreamAuthMessageType {
+		return nil, ErrStreamAuthMessage
+	}
+	if validator == nil {
+		return nil, auth.ErrMissingToken
+	}
+	return validator.ValidateToken(m.Token)
+}
+
+// StreamSession is one authenticated streaming connection and the communities it follows.
+// safe for concurrent use by the connection's reader and writer.
+type StreamSession struct {
+	claims *auth.SupabaseClaims
+	limit  int
+
+	mu   sync.Mutex
+	subs map[string]struct{}
+}
+
+// NewStreamSession creates a session for an authenticated connection.
+// limit caps the communities it can follow, DefaultStreamSubscriptionLimit when not positive.
+func NewStreamSession(claims *auth.SupabaseClaims, limit int) *StreamSession {
+	if limit <= 0 {
+		limit = DefaultStreamSubscriptionLimit
+	}
+	return &StreamSession{
+		claims: claims,
+		limit:  limit,
+		subs:   make(map[string]struct{}),
+	}
+}
+
+// UserID returns the external id of the connection's user.
+func (s *StreamSession) UserID() string {
+	return s.claims.UserID()
+}
+
+// Expired reports whether the connection's token has expired.
+// long-lived connections are closed then, and the client reconnects with a fresh token.
+func (s *StreamSession) Expired(now time.Time) bool {
+	return s.claims.ExpiresAt != nil && !now.Before(s.claims.ExpiresAt.Time)
+}
+
+// Subscribe follows a community. following one twice is a no-op,
+// and a new one past the limit is ErrStreamSubscriptionLimit.
+func (s *StreamSession) Subscribe(communityID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[communityID]; ok {
+		return nil
+	}
+	if len(s.subs) >= s.limit {
+		return ErrStreamSubscriptionLimit
+	}
+	s.subs[communityID] = struct{}{}
+	return nil
+}
+
+// Unsubscribe stops following a community.
+func (s *StreamSession) Unsubscribe(communityID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subs, communityID)
+}
+
+// Subscriptions returns the followed communities, sorted.
+func (s *StreamSession) Subscriptions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.subs))
+	for id := range s.subs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// redactStreamToken hides the access_token query parameter so tokens don't end up in request logs.
+func redactStreamToken(uri string) string {
+	if !strings.Contains(uri, StreamTokenParam+"=") {
+		return uri
+	}
+
+	u, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return uri
+	}
+	q := u.Query()
+	q.Set(StreamTokenParam, redactedValue)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// redactedValue replaces secrets in logs.
+const redactedValue = "[REDACTED]"
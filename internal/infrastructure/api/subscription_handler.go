@@ -1,24 +1,34 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
+	"github.com/joacominatel/pulse/internal/application"
 	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/worker"
 )
 
 // SubscriptionHandler handles webhook subscription HTTP endpoints.
 type SubscriptionHandler struct {
-	repo domain.WebhookSubscriptionRepository
+	repo       domain.WebhookSubscriptionRepository
+	deliveries domain.WebhookDeliveryRepository
+	allowlist  *application.WebhookAllowlistUseCase
+	allowProxy bool
 }
 
 // NewSubscriptionHandler creates a new SubscriptionHandler.
-func NewSubscriptionHandler(repo domain.WebhookSubscriptionRepository) *SubscriptionHandler {
-	return &SubscriptionHandler{repo: repo}
+// allowProxy accepts a per-subscription proxy_url, which is refused otherwise.
+// the deliveries route is only registered when deliveries is set, and target urls are
+// only checked against the community's allowed domains when allowlist is set.
+func NewSubscriptionHandler(repo domain.WebhookSubscriptionRepository, deliveries domain.WebhookDeliveryRepository, allowlist *application.WebhookAllowlistUseCase, allowProxy bool) *SubscriptionHandler {
+	return &SubscriptionHandler{repo: repo, deliveries: deliveries, allowlist: allowlist, allowProxy: allowProxy}
 }
 
 // RegisterRoutes registers subscription routes on the given group.
@@ -28,6 +38,11 @@ func (h *SubscriptionHandler) RegisterRoutes(g *echo.Group) {
 	subs.POST("", h.Create)
 	subs.GET("", h.List)
 	subs.DELETE("/:id", h.Delete)
+	subs.PUT("/:id/pause-windows", h.UpdatePauseWindows)
+	subs.GET("/:id/signature-example", h.SignatureExample)
+	if h.deliveries != nil {
+		subs.GET("/:id/deliveries", h.Deliveries)
+	}
 }
 
 // --- Request/Response DTOs ---
@@ -36,36 +51,120 @@ func (h *SubscriptionHandler) RegisterRoutes(g *echo.Group) {
 // @Description Request body for creating a webhook subscription.
 type createSubscriptionRequest struct {
 	// CommunityID is the UUID of the community to subscribe to.
-	CommunityID string `json:"community_id"`
-	// TargetURL is the webhook endpoint that will receive notifications.
-	TargetURL string `json:"target_url"`
-	// Secret is used for HMAC-SHA256 signature verification.
+	// omit it to get spikes from every public community.
+	CommunityID string `json:"community_id,omitempty" validate:"omitempty,uuid"`
+	// Channel is webhook (signed JSON, the default), slack or discord.
+	Channel string `json:"channel,omitempty" validate:"omitempty,oneof=webhook slack discord"`
+	// TargetURL is the webhook endpoint that will receive notifications,
+	// or the incoming webhook url for slack and discord.
+	TargetURL string `json:"target_url" validate:"required,http_url"`
+	// Secret is used for HMAC-SHA256 signature verification, required for the webhook channel.
 	Secret string `json:"secret"`
+	// PayloadVersion is the payload format to receive, v1 when omitted.
+	PayloadVersion string `json:"payload_version,omitempty" validate:"omitempty,oneof=v1"`
+	// DeliveryMode is immediate (one call per spike, the default) or digest (one call per window).
+	DeliveryMode string `json:"delivery_mode,omitempty" validate:"omitempty,oneof=immediate digest"`
+	// ProxyURL routes deliveries through this proxy, only accepted when the server allows it.
+	ProxyURL string `json:"proxy_url,omitempty" validate:"omitempty,url"`
+	// PauseWindows are recurring maintenance windows during which deliveries are held,
+	// then sent once the window ends.
+	PauseWindows []pauseWindowRequest `json:"pause_windows,omitempty" validate:"max=14,dive"`
+}
+
+// pauseWindowRequest is a recurring window during which deliveries are held,
+// e.g. {"day": "sunday", "start": "02:00", "end": "03:00", "timezone": "UTC"}.
+type pauseWindowRequest struct {
+	// Day is the weekday the window starts on, omit it for every day.
+	Day string `json:"day,omitempty" validate:"omitempty,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	// Start and End are HH:MM in the timezone, End may be before Start to cross midnight.
+	Start    string `json:"start" validate:"required"`
+	End      string `json:"end" validate:"required"`
+	Timezone string `json:"timezone,omitempty"` // UTC when omitted
+}
+
+// updatePauseWindowsRequest replaces a subscription's pause windows, empty to remove them.
+type updatePauseWindowsRequest struct {
+	PauseWindows []pauseWindowRequest `json:"pause_windows" validate:"max=14,dive"`
+}
+
+type pauseWindowResponse struct {
+	Day      string `json:"day,omitempty"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
 }
 
 // subscriptionResponse is the API representation of a webhook subscription.
 // @Description Webhook subscription details.
 type subscriptionResponse struct {
-	ID          string    `json:"id"`
-	CommunityID string    `json:"community_id"`
-	TargetURL   string    `json:"target_url"`
-	IsActive    bool      `json:"is_active"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             string                `json:"id"`
+	CommunityID    *string               `json:"community_id"` // null for global subscriptions
+	Channel        string                `json:"channel"`
+	TargetURL      string                `json:"target_url"`
+	PayloadVersion string                `json:"payload_version"`
+	DeliveryMode   string                `json:"delivery_mode"`
+	ProxyURL       string                `json:"proxy_url,omitempty"` // password redacted
+	PauseWindows   []pauseWindowResponse `json:"pause_windows"`
+	IsActive       bool                  `json:"is_active"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
 }
 
 // listSubscriptionsResponse is the response for listing subscriptions.
-// @Description List of webhook subscriptions for the authenticated user.
+// @Description A page of webhook subscriptions for the authenticated user.
 type listSubscriptionsResponse struct {
 	Subscriptions []subscriptionResponse `json:"subscriptions"`
 	Count         int                    `json:"count"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+}
+
+// signatureExampleResponse shows how a delivery to the subscription is signed.
+// @Description A sample delivery and the steps to verify its signature.
+type signatureExampleResponse struct {
+	SubscriptionID string `json:"subscription_id"`
+	PayloadVersion string `json:"payload_version"`
+	// Payload is the raw request body, byte for byte.
+	Payload string `json:"payload"`
+	// Timestamp is the X-Pulse-Timestamp header, unix seconds.
+	Timestamp string `json:"timestamp"`
+	// StringToSign is "<timestamp>.<payload>", what the HMAC covers.
+	StringToSign string `json:"string_to_sign"`
+	// Signature is the X-Pulse-Signature header.
+	Signature string `json:"signature"`
+	// TestSecret is true when the signature used the test_secret query parameter.
+	TestSecret bool              `json:"test_secret"`
+	Headers    map[string]string `json:"headers"`
+}
+
+// deliveryResponse is one delivery attempt to a subscription.
+// @Description A webhook delivery attempt.
+type deliveryResponse struct {
+	// ID is the X-Pulse-Delivery-ID header of signed webhooks.
+	ID    string `json:"id"`
+	Event string `json:"event"`
+	// StatusCode is the subscriber's response status, 0 when no response came back.
+	StatusCode  int       `json:"status_code"`
+	LatencyMS   int64     `json:"latency_ms"`
+	Succeeded   bool      `json:"succeeded"`
+	Error       string    `json:"error,omitempty"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// listDeliveriesResponse is the response for listing a subscription's delivery attempts.
+// @Description Delivery attempts to a webhook subscription, newest first.
+type listDeliveriesResponse struct {
+	Deliveries []deliveryResponse `json:"deliveries"`
+	Count      int                `json:"count"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
 }
 
 // --- Handlers ---
 
 // Create creates a new webhook subscription.
 // @Summary Create a webhook subscription
-// @Description Subscribe to momentum spike notifications for a community.
+// @Description Subscribe to momentum spike notifications for a community, or for every public one without community_id.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
@@ -73,7 +172,9 @@ type listSubscriptionsResponse struct {
 // @Success 201 {object} subscriptionResponse
 // @Failure 400 {object} echo.HTTPError "Invalid request"
 // @Failure 401 {object} echo.HTTPError "Unauthorized"
+// @Failure 403 {object} echo.HTTPError "Target domain not allowed by the community"
 // @Failure 409 {object} echo.HTTPError "Subscription already exists"
+// @Failure 422 {object} ErrorResponse "Invalid fields"
 // @Router /api/v1/subscriptions [post]
 // @Security BearerAuth
 func (h *SubscriptionHandler) Create(c echo.Context) error {
@@ -85,25 +186,8 @@ func (h *SubscriptionHandler) Create(c echo.Context) error {
 
 	// parse request body
 	var req createSubscriptionRequest
-	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
-	}
-
-	// validate required fields
-	if req.CommunityID == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "community_id is required")
-	}
-	if req.TargetURL == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "target_url is required")
-	}
-	if req.Secret == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "secret is required")
-	}
-
-	// validate target_url is a valid URL with http/https scheme
-	parsedURL, err := url.Parse(req.TargetURL)
-	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "target_url must be a valid HTTP or HTTPS URL")
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
 	// parse domain IDs
@@ -112,9 +196,23 @@ func (h *SubscriptionHandler) Create(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id format")
 	}
 
-	communityID, err := domain.ParseCommunityID(req.CommunityID)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid community_id format")
+	// no community means a global subscription, left as the zero id
+	var communityID domain.CommunityID
+	if req.CommunityID != "" {
+		communityID, err = domain.ParseCommunityID(req.CommunityID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid community_id format")
+		}
+	}
+
+	// the community may only allow some domains to receive its analytics
+	if h.allowlist != nil {
+		if err := h.allowlist.Check(c.Request().Context(), communityID, req.TargetURL); err != nil {
+			if errors.Is(err, domain.ErrWebhookDomainNotAllowed) {
+				return echo.NewHTTPError(http.StatusForbidden, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to check the community's allowed domains")
+		}
 	}
 
 	// generate subscription ID
@@ -123,11 +221,40 @@ func (h *SubscriptionHandler) Create(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to generate subscription id")
 	}
 
+	// already checked by the oneof rule
+	channel, _ := domain.ParseNotificationChannel(req.Channel)
+
 	// create domain entity
-	subscription, err := domain.NewWebhookSubscription(subID, userID, communityID, req.TargetURL, req.Secret)
+	var subscription *domain.WebhookSubscription
+	if channel.IsSigned() {
+		if req.Secret == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "secret is required for webhook subscriptions")
+		}
+		subscription, err = domain.NewWebhookSubscription(domain.SystemClock, subID, userID, communityID, req.TargetURL, req.Secret, domain.WebhookPayloadVersion(req.PayloadVersion), domain.WebhookDeliveryMode(req.DeliveryMode))
+	} else {
+		subscription, err = domain.NewChatSubscription(domain.SystemClock, subID, userID, communityID, channel, req.TargetURL, domain.WebhookDeliveryMode(req.DeliveryMode))
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid subscription data")
 	}
+	if req.ProxyURL != "" {
+		if !h.allowProxy {
+			return echo.NewHTTPError(http.StatusBadRequest, "per-subscription proxies are disabled")
+		}
+		if err := subscription.SetProxyURL(req.ProxyURL); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if len(req.PauseWindows) > 0 {
+		windows, err := parsePauseWindows(req.PauseWindows)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := subscription.SetPauseWindows(windows); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
 
 	// persist
 	if err := h.repo.Save(c.Request().Context(), subscription); err != nil {
@@ -135,22 +262,21 @@ func (h *SubscriptionHandler) Create(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to save subscription")
 	}
 
-	return c.JSON(http.StatusCreated, subscriptionResponse{
-		ID:          subscription.ID().String(),
-		CommunityID: subscription.CommunityID().String(),
-		TargetURL:   subscription.TargetURL(),
-		IsActive:    subscription.IsActive(),
-		CreatedAt:   subscription.CreatedAt(),
-		UpdatedAt:   subscription.UpdatedAt(),
-	})
+	return c.JSON(http.StatusCreated, toSubscriptionResponse(subscription))
 }
 
-// List returns all subscriptions for the authenticated user.
+// List returns a page of the authenticated user's subscriptions.
 // @Summary List webhook subscriptions
-// @Description Get all webhook subscriptions for the authenticated user.
+// @Description Get the webhook subscriptions of the authenticated user, optionally filtered by community and state.
 // @Tags subscriptions
 // @Produce json
+// @Param community_id query string false "Only subscriptions to this community, or global for the global ones"
+// @Param is_active query bool false "Only active or only inactive subscriptions"
+// @Param order query string false "newest (default), oldest or updated"
+// @Param limit query int false "Page size, 1 to 100" default(50)
+// @Param offset query int false "Subscriptions to skip" default(0)
 // @Success 200 {object} listSubscriptionsResponse
+// @Failure 400 {object} echo.HTTPError "Invalid filter"
 // @Failure 401 {object} echo.HTTPError "Unauthorized"
 // @Router /api/v1/subscriptions [get]
 // @Security BearerAuth
@@ -166,8 +292,42 @@ func (h *SubscriptionHandler) List(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id format")
 	}
 
-	// fetch from repository
-	subs, err := h.repo.FindByUser(c.Request().Context(), userID)
+	filter := domain.WebhookSubscriptionFilter{Limit: 50}
+	switch s := c.QueryParam("community_id"); s {
+	case "":
+	case "global":
+		// the zero id selects global subscriptions
+		filter.CommunityID = &domain.CommunityID{}
+	default:
+		communityID, err := domain.ParseCommunityID(s)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid community_id format")
+		}
+		filter.CommunityID = &communityID
+	}
+	if s := c.QueryParam("is_active"); s != "" {
+		active, err := strconv.ParseBool(s)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "is_active must be true or false")
+		}
+		filter.IsActive = &active
+	}
+	filter.Order, err = domain.ParseWebhookSubscriptionOrder(c.QueryParam("order"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			filter.Limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			filter.Offset = parsed
+		}
+	}
+
+	subs, err := h.repo.ListByUser(c.Request().Context(), userID, filter)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch subscriptions")
 	}
@@ -176,17 +336,12 @@ func (h *SubscriptionHandler) List(c echo.Context) error {
 	response := listSubscriptionsResponse{
 		Subscriptions: make([]subscriptionResponse, 0, len(subs)),
 		Count:         len(subs),
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
 	}
 
 	for _, sub := range subs {
-		response.Subscriptions = append(response.Subscriptions, subscriptionResponse{
-			ID:          sub.ID().String(),
-			CommunityID: sub.CommunityID().String(),
-			TargetURL:   sub.TargetURL(),
-			IsActive:    sub.IsActive(),
-			CreatedAt:   sub.CreatedAt(),
-			UpdatedAt:   sub.UpdatedAt(),
-		})
+		response.Subscriptions = append(response.Subscriptions, toSubscriptionResponse(sub))
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -221,41 +376,271 @@ func (h *SubscriptionHandler) Delete(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid subscription id format")
 	}
 
+	// authorization check: verify the subscription belongs to this user
+	if _, err := h.findOwned(c, userExternalID, subID); err != nil {
+		return err
+	}
+
+	// delete
+	if err := h.repo.Delete(c.Request().Context(), subID); err != nil {
+		if err == domain.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete subscription")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdatePauseWindows replaces the maintenance windows of a subscription.
+// @Summary Set a subscription's pause windows
+// @Description Deliveries during a pause window are held and sent once it ends, instead of failing against a receiver that's down for maintenance. An empty list removes the windows.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body updatePauseWindowsRequest true "Pause windows"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} echo.HTTPError "Invalid pause windows"
+// @Failure 401 {object} echo.HTTPError "Unauthorized"
+// @Failure 404 {object} echo.HTTPError "Subscription not found"
+// @Failure 422 {object} ErrorResponse "Invalid fields"
+// @Router /api/v1/subscriptions/{id}/pause-windows [put]
+// @Security BearerAuth
+func (h *SubscriptionHandler) UpdatePauseWindows(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	subID, err := domain.NewWebhookSubscriptionID(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid subscription id format")
+	}
+
+	var req updatePauseWindowsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	sub, err := h.findOwned(c, userExternalID, subID)
+	if err != nil {
+		return err
+	}
+
+	windows, err := parsePauseWindows(req.PauseWindows)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := sub.SetPauseWindows(windows); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.repo.Save(c.Request().Context(), sub); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to save subscription")
+	}
+
+	return c.JSON(http.StatusOK, toSubscriptionResponse(sub))
+}
+
+// SignatureExample signs a sample payload the way deliveries to the subscription are signed.
+// @Summary Show a signed sample delivery
+// @Description Returns a sample payload, the string to sign and the signature, using the stored secret or test_secret.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param test_secret query string false "Sign with this secret instead of the stored one"
+// @Success 200 {object} signatureExampleResponse
+// @Failure 400 {object} echo.HTTPError "Not a webhook subscription"
+// @Failure 401 {object} echo.HTTPError "Unauthorized"
+// @Failure 404 {object} echo.HTTPError "Subscription not found"
+// @Router /api/v1/subscriptions/{id}/signature-example [get]
+// @Security BearerAuth
+func (h *SubscriptionHandler) SignatureExample(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	subID, err := domain.NewWebhookSubscriptionID(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid subscription id format")
+	}
+
+	sub, err := h.findOwned(c, userExternalID, subID)
+	if err != nil {
+		return err
+	}
+	if !sub.Channel().IsSigned() {
+		return echo.NewHTTPError(http.StatusBadRequest, "only webhook subscriptions are signed")
+	}
+
+	now := time.Now()
+	payload, err := worker.SamplePayload(sub, now)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to render sample payload")
+	}
+
+	// the stored secret is never echoed back, only used to sign
+	secret := sub.Secret()
+	testSecret := c.QueryParam("test_secret")
+	if testSecret != "" {
+		secret = testSecret
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := worker.SignPayload(timestamp, payload, secret)
+
+	return c.JSON(http.StatusOK, signatureExampleResponse{
+		SubscriptionID: sub.ID().String(),
+		PayloadVersion: sub.PayloadVersion().String(),
+		Payload:        string(payload),
+		Timestamp:      timestamp,
+		StringToSign:   worker.StringToSign(timestamp, payload),
+		Signature:      signature,
+		TestSecret:     testSecret != "",
+		Headers: map[string]string{
+			"Content-Type":            "application/json",
+			"X-Pulse-Signature":       signature,
+			"X-Pulse-Timestamp":       timestamp,
+			"X-Pulse-Event":           "momentum_spike",
+			"X-Pulse-Payload-Version": sub.PayloadVersion().String(),
+		},
+	})
+}
+
+// Deliveries lists the delivery attempts to a subscription, newest first.
+// @Summary List webhook deliveries
+// @Description Delivery attempts to a subscription from the last 30 days, with the response status, latency and error. Only the owner can list them.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param limit query int false "Max attempts (default 50, max 100)"
+// @Param offset query int false "Attempts to skip"
+// @Success 200 {object} listDeliveriesResponse
+// @Failure 400 {object} echo.HTTPError "Invalid request"
+// @Failure 401 {object} echo.HTTPError "Unauthorized"
+// @Failure 404 {object} echo.HTTPError "Subscription not found"
+// @Router /api/v1/subscriptions/{id}/deliveries [get]
+// @Security BearerAuth
+func (h *SubscriptionHandler) Deliveries(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	subID, err := domain.NewWebhookSubscriptionID(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid subscription id format")
+	}
+	if _, err := h.findOwned(c, userExternalID, subID); err != nil {
+		return err
+	}
+
+	limit := 50
+	offset := 0
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	deliveries, err := h.deliveries.ListBySubscription(c.Request().Context(), subID, limit, offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch deliveries")
+	}
+
+	resp := listDeliveriesResponse{
+		Deliveries: make([]deliveryResponse, len(deliveries)),
+		Count:      len(deliveries),
+		Limit:      limit,
+		Offset:     offset,
+	}
+	for i, d := range deliveries {
+		resp.Deliveries[i] = deliveryResponse{
+			ID:          d.ID().String(),
+			Event:       d.Event(),
+			StatusCode:  d.StatusCode(),
+			LatencyMS:   d.Latency().Milliseconds(),
+			Succeeded:   d.Succeeded(),
+			Error:       d.Err(),
+			AttemptedAt: d.AttemptedAt(),
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// findOwned returns the caller's subscription, or a 404 echo error when it doesn't exist
+// or belongs to another user, so other users' subscriptions don't leak.
+func (h *SubscriptionHandler) findOwned(c echo.Context, userExternalID string, id domain.WebhookSubscriptionID) (*domain.WebhookSubscription, error) {
 	userID, err := domain.ParseUserID(userExternalID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id format")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid user id format")
 	}
 
-	// authorization check: verify the subscription belongs to this user
-	// we need to fetch all user's subscriptions and check if this id is in there
-	// this is a workaround since FindByID isn't in the interface
-	subs, err := h.repo.FindByUser(c.Request().Context(), userID)
+	// ids are uuids, anything else can't exist
+	if _, err := uuid.Parse(id.String()); err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	}
+
+	sub, err := h.repo.FindByID(c.Request().Context(), id)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && sub.UserID() != userID) {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to verify ownership")
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to verify ownership")
 	}
+	return sub, nil
+}
 
-	// check if the subscription belongs to this user
-	found := false
-	for _, sub := range subs {
-		if sub.ID().String() == subID.String() {
-			found = true
-			break
+// parsePauseWindows validates the requested pause windows.
+func parsePauseWindows(req []pauseWindowRequest) ([]domain.DeliveryPauseWindow, error) {
+	windows := make([]domain.DeliveryPauseWindow, len(req))
+	for i, w := range req {
+		window, err := domain.ParseDeliveryPauseWindow(w.Day, w.Start, w.End, w.Timezone)
+		if err != nil {
+			return nil, err
 		}
+		windows[i] = window
 	}
+	return windows, nil
+}
 
-	if !found {
-		// either doesn't exist or belongs to another user
-		// return 404 to avoid leaking info about other users' subscriptions
-		return echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+func toSubscriptionResponse(sub *domain.WebhookSubscription) subscriptionResponse {
+	var communityID *string
+	if !sub.IsGlobal() {
+		id := sub.CommunityID().String()
+		communityID = &id
 	}
 
-	// delete
-	if err := h.repo.Delete(c.Request().Context(), subID); err != nil {
-		if err == domain.ErrNotFound {
-			return echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	var proxyURL string
+	if sub.ProxyURL() != "" {
+		if u, err := url.Parse(sub.ProxyURL()); err == nil {
+			proxyURL = u.Redacted()
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete subscription")
 	}
 
-	return c.NoContent(http.StatusNoContent)
+	pauseWindows := make([]pauseWindowResponse, len(sub.PauseWindows()))
+	for i, w := range sub.PauseWindows() {
+		pauseWindows[i] = pauseWindowResponse{Day: w.Day(), Start: w.Start(), End: w.End(), Timezone: w.Timezone()}
+	}
+
+	return subscriptionResponse{
+		ID:             sub.ID().String(),
+		CommunityID:    communityID,
+		Channel:        sub.Channel().String(),
+		TargetURL:      sub.TargetURL(),
+		PayloadVersion: sub.PayloadVersion().String(),
+		DeliveryMode:   sub.DeliveryMode().String(),
+		ProxyURL:       proxyURL,
+		PauseWindows:   pauseWindows,
+		IsActive:       sub.IsActive(),
+		CreatedAt:      sub.CreatedAt(),
+		UpdatedAt:      sub.UpdatedAt(),
+	}
 }
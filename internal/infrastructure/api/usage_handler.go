@@ -0,0 +1,215 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// defaultUsageDays is how many days a usage report covers without ?days.
+const defaultUsageDays = 7
+
+// UsageHandler handles ingestion usage and quota endpoints.
+type UsageHandler struct {
+	useCase *application.UsageUseCase
+}
+
+// NewUsageHandler creates a new UsageHandler.
+func NewUsageHandler(useCase *application.UsageUseCase) *UsageHandler {
+	return &UsageHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the usage routes on the given group.
+// tenants read their own usage; quotas are changed by admins.
+func (h *UsageHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/communities/:id/usage", h.CommunityUsage)
+	g.GET("/organizations/:id/usage", h.OrganizationUsage)
+
+	admin := g.Group("/admin", RequireAdmin())
+	admin.GET("/quotas/:scope/:id", h.GetQuota)
+	admin.PUT("/quotas/:scope/:id", h.SetQuota)
+	admin.DELETE("/quotas/:scope/:id", h.ClearQuota)
+}
+
+// setQuotaRequest is the request body for overriding a daily quota.
+type setQuotaRequest struct {
+	// DailyEvents is the number of events accepted per UTC day, 0 for unlimited.
+	DailyEvents *int64 `json:"daily_events" validate:"required,gte=0"`
+}
+
+type usageResponse struct {
+	Scope      string             `json:"scope"`
+	ID         string             `json:"id"`
+	DailyQuota int64              `json:"daily_quota"`
+	Mode       string             `json:"mode"`
+	Today      int64              `json:"today"`
+	Remaining  *int64             `json:"remaining"`
+	ResetAt    time.Time          `json:"reset_at"`
+	Days       []dailyUsageResult `json:"days"`
+}
+
+type dailyUsageResult struct {
+	Date   string `json:"date"`
+	Events int64  `json:"events"`
+}
+
+// CommunityUsage returns a community's daily event usage.
+// GET /api/v1/communities/:id/usage?days=7
+// requires the community creator or an admin of its organization
+func (h *UsageHandler) CommunityUsage(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	days, err := usageDays(c)
+	if err != nil {
+		return err
+	}
+
+	output, err := h.useCase.CommunityUsage(c.Request().Context(), c.Param("id"), userExternalID, days)
+	if err != nil {
+		return mapUsageError(err)
+	}
+	return c.JSON(http.StatusOK, toUsageResponse(output))
+}
+
+// OrganizationUsage returns an organization's daily event usage, across all its communities.
+// GET /api/v1/organizations/:id/usage?days=7
+// requires membership of the organization
+func (h *UsageHandler) OrganizationUsage(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	days, err := usageDays(c)
+	if err != nil {
+		return err
+	}
+
+	output, err := h.useCase.OrganizationUsage(c.Request().Context(), c.Param("id"), userExternalID, days)
+	if err != nil {
+		return mapUsageError(err)
+	}
+	return c.JSON(http.StatusOK, toUsageResponse(output))
+}
+
+// GetQuota returns any scope's usage and effective quota.
+// GET /api/v1/admin/quotas/:scope/:id
+func (h *UsageHandler) GetQuota(c echo.Context) error {
+	scope, id, err := quotaTarget(c)
+	if err != nil {
+		return err
+	}
+
+	days, err := usageDays(c)
+	if err != nil {
+		return err
+	}
+
+	output, err := h.useCase.Usage(c.Request().Context(), scope, id, days)
+	if err != nil {
+		return mapUsageError(err)
+	}
+	return c.JSON(http.StatusOK, toUsageResponse(output))
+}
+
+// SetQuota overrides the default daily quota for a scope.
+// PUT /api/v1/admin/quotas/:scope/:id
+func (h *UsageHandler) SetQuota(c echo.Context) error {
+	scope, id, err := quotaTarget(c)
+	if err != nil {
+		return err
+	}
+
+	var req setQuotaRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.useCase.SetQuota(c.Request().Context(), scope, id, *req.DailyEvents)
+	if err != nil {
+		return mapUsageError(err)
+	}
+	return c.JSON(http.StatusOK, toUsageResponse(output))
+}
+
+// ClearQuota returns a scope to the default daily quota.
+// DELETE /api/v1/admin/quotas/:scope/:id
+func (h *UsageHandler) ClearQuota(c echo.Context) error {
+	scope, id, err := quotaTarget(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.useCase.ClearQuota(c.Request().Context(), scope, id); err != nil {
+		return mapUsageError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func usageDays(c echo.Context) (int, error) {
+	d := c.QueryParam("days")
+	if d == "" {
+		return defaultUsageDays, nil
+	}
+	days, err := strconv.Atoi(d)
+	if err != nil || days < 1 || days > application.MaxUsageDays {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, application.ErrUsageDaysOutOfRange.Error())
+	}
+	return days, nil
+}
+
+func quotaTarget(c echo.Context) (domain.UsageScope, uuid.UUID, error) {
+	scope, err := domain.ParseUsageScope(c.Param("scope"))
+	if err != nil {
+		return "", uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return "", uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+	return scope, id, nil
+}
+
+// mapUsageError converts use case errors to HTTP errors
+func mapUsageError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner),
+		errors.Is(err, application.ErrNotOrganizationMember):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, application.ErrUsageDaysOutOfRange),
+		errors.Is(err, domain.ErrQuotaNegative):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toUsageResponse(output *application.UsageOutput) usageResponse {
+	resp := usageResponse{
+		Scope:      output.Scope.String(),
+		ID:         output.ID,
+		DailyQuota: output.DailyQuota,
+		Mode:       output.Mode.String(),
+		Today:      output.Today,
+		Remaining:  output.Remaining,
+		ResetAt:    output.ResetAt,
+		Days:       make([]dailyUsageResult, len(output.Days)),
+	}
+	for i, d := range output.Days {
+		resp.Days[i] = dailyUsageResult{
+			Date:   d.Date.Format(time.DateOnly),
+			Events: d.Events,
+		}
+	}
+	return resp
+}
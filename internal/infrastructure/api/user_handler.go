@@ -0,0 +1,229 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// UserHandler handles user lookups and the caller's own profile.
+type UserHandler struct {
+	useCase  *application.UserLookupUseCase
+	profiles *application.UserProfileUseCase
+}
+
+// NewUserHandler creates a new UserHandler.
+// profiles may be nil, the /users/me routes are left out then.
+func NewUserHandler(useCase *application.UserLookupUseCase, profiles *application.UserProfileUseCase) *UserHandler {
+	return &UserHandler{useCase: useCase, profiles: profiles}
+}
+
+// RegisterRoutes registers the user routes on the given group.
+// lookups are public, clients check handles before the user has signed up.
+// /users/me can't clash with a username, those are at least 3 characters.
+func (h *UserHandler) RegisterRoutes(g *echo.Group) {
+	if h.profiles != nil {
+		g.POST("/users/bootstrap", h.Bootstrap)
+		g.GET("/users/me", h.GetMe)
+		g.PATCH("/users/me", h.UpdateMe)
+	}
+	g.GET("/users/by-username/:username", h.GetByUsername)
+	g.GET("/users/check-username", h.CheckUsername)
+	g.GET("/users/:username", h.GetByUsername)
+}
+
+// bootstrapUserRequest is the request body for creating the caller's profile.
+// display_name and avatar_url default to the auth provider's user metadata.
+type bootstrapUserRequest struct {
+	Username    string `json:"username" validate:"required"`
+	DisplayName string `json:"display_name" validate:"omitempty,max=100"`
+	AvatarURL   string `json:"avatar_url" validate:"omitempty,http_url,max=2048"`
+}
+
+// updateProfileRequest is the request body for editing the caller's profile.
+// omitted fields are kept, empty strings clear them.
+type updateProfileRequest struct {
+	DisplayName *string `json:"display_name" validate:"omitempty,max=100"`
+	AvatarURL   *string `json:"avatar_url" validate:"omitempty,http_url,max=2048"`
+	Bio         *string `json:"bio"` // cleaned and length-checked by the domain
+}
+
+// userProfileResponse is the API representation of a user's public profile.
+type userProfileResponse struct {
+	ID          string    `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name,omitempty"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	Bio         string    `json:"bio,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// usernameAvailabilityResponse is the API representation of a username check.
+type usernameAvailabilityResponse struct {
+	Username   string `json:"username"`
+	Normalized string `json:"normalized,omitempty"`
+	Available  bool   `json:"available"`
+	Reason     string `json:"reason,omitempty"` // invalid or taken
+	Detail     string `json:"detail,omitempty"`
+}
+
+// Bootstrap creates the caller's profile from their JWT, the first call after signup.
+// repeat calls return the existing profile with 200 instead of 201.
+// POST /api/v1/users/bootstrap
+func (h *UserHandler) Bootstrap(c echo.Context) error {
+	// a profile belongs to an auth provider account, api keys and service tokens have one already or none at all
+	claims := GetClaims(c)
+	if claims == nil || claims.UserID() == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "a user token is required")
+	}
+
+	var req bootstrapUserRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	input := application.BootstrapUserInput{
+		ExternalID:  claims.UserID(),
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		AvatarURL:   req.AvatarURL,
+	}
+	if input.DisplayName == "" {
+		input.DisplayName = metadataString(claims.UserMetadata, "full_name", "name")
+		if utf8.RuneCountInString(input.DisplayName) > 100 {
+			input.DisplayName = ""
+		}
+	}
+	if input.AvatarURL == "" {
+		input.AvatarURL = metadataString(claims.UserMetadata, "avatar_url", "picture")
+	}
+
+	output, err := h.profiles.Bootstrap(c.Request().Context(), input)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	status := http.StatusOK
+	if output.Created {
+		status = http.StatusCreated
+	}
+	return c.JSON(status, toUserProfileResponse(output.Profile))
+}
+
+// metadataString returns the first of the keys set to a non-empty string in supabase user metadata.
+// oauth providers fill in different keys for the same thing.
+func metadataString(metadata map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, _ := metadata[key].(string); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetMe returns the caller's own profile.
+// GET /api/v1/users/me
+func (h *UserHandler) GetMe(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	output, err := h.profiles.Me(c.Request().Context(), userExternalID)
+	if err != nil {
+		return mapUserError(err)
+	}
+	return c.JSON(http.StatusOK, toUserProfileResponse(output))
+}
+
+// UpdateMe changes the caller's display name, avatar or bio.
+// PATCH /api/v1/users/me
+func (h *UserHandler) UpdateMe(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateProfileRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.profiles.UpdateMe(c.Request().Context(), application.UpdateUserProfileInput{
+		RequesterExternalID: userExternalID,
+		DisplayName:         req.DisplayName,
+		AvatarURL:           req.AvatarURL,
+		Bio:                 req.Bio,
+	})
+	if err != nil {
+		return mapUserError(err)
+	}
+	return c.JSON(http.StatusOK, toUserProfileResponse(output))
+}
+
+// GetByUsername returns a user's public profile, matching the username in any casing.
+// GET /api/v1/users/:username
+// GET /api/v1/users/by-username/:username
+func (h *UserHandler) GetByUsername(c echo.Context) error {
+	output, err := h.useCase.FindByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return mapUserError(err)
+	}
+	return c.JSON(http.StatusOK, toUserProfileResponse(output))
+}
+
+// CheckUsername reports whether a username is valid and free, in any casing.
+// GET /api/v1/users/check-username?u=
+func (h *UserHandler) CheckUsername(c echo.Context) error {
+	username := c.QueryParam("u")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "u is required")
+	}
+
+	output, err := h.useCase.CheckUsername(c.Request().Context(), username)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	return c.JSON(http.StatusOK, usernameAvailabilityResponse{
+		Username:   output.Username,
+		Normalized: output.Normalized,
+		Available:  output.Available,
+		Reason:     output.Reason,
+		Detail:     output.Detail,
+	})
+}
+
+// mapUserError converts use case errors to HTTP errors
+func mapUserError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	case errors.Is(err, application.ErrCreatorNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "user profile not found - please complete signup first")
+	case errors.Is(err, application.ErrUsernameTaken):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrDisplayNameTooLong),
+		errors.Is(err, domain.ErrBioTooLong):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toUserProfileResponse(output *application.UserProfileOutput) userProfileResponse {
+	return userProfileResponse{
+		ID:          output.ID,
+		Username:    output.Username,
+		DisplayName: output.DisplayName,
+		AvatarURL:   output.AvatarURL,
+		Bio:         output.Bio,
+		CreatedAt:   output.CreatedAt,
+	}
+}
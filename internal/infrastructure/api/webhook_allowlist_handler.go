@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// WebhookAllowlistHandler handles the per-community webhook allowlist endpoints.
+type WebhookAllowlistHandler struct {
+	useCase *application.WebhookAllowlistUseCase
+}
+
+// NewWebhookAllowlistHandler creates a new WebhookAllowlistHandler.
+func NewWebhookAllowlistHandler(useCase *application.WebhookAllowlistUseCase) *WebhookAllowlistHandler {
+	return &WebhookAllowlistHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the webhook allowlist routes on the given group.
+// reads are public, writes require the community owner.
+func (h *WebhookAllowlistHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/communities/:id/webhooks/allowlist", h.Get)
+	g.PUT("/communities/:id/webhooks/allowlist", h.Update)
+	g.DELETE("/communities/:id/webhooks/allowlist", h.Reset)
+}
+
+// updateWebhookAllowlistRequest is the request body for restricting webhook domains,
+// e.g. {"domains": ["hooks.partner.io", "*.mycompany.com"]}.
+type updateWebhookAllowlistRequest struct {
+	Domains []string `json:"domains" validate:"required,min=1"`
+}
+
+// webhookAllowlistResponse lists the domains a community's webhooks may be delivered to.
+type webhookAllowlistResponse struct {
+	CommunityID string `json:"community_id"`
+	// Domains is empty when webhooks may be delivered anywhere.
+	Domains []string `json:"domains"`
+}
+
+// Get returns the allowed webhook domains of a community.
+// @Summary Get a community's webhook allowlist
+// @Description Domains subscriptions to the community may deliver to, empty when any domain is allowed.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Community ID"
+// @Success 200 {object} webhookAllowlistResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/communities/{id}/webhooks/allowlist [get]
+func (h *WebhookAllowlistHandler) Get(c echo.Context) error {
+	output, err := h.useCase.Get(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return mapWebhookAllowlistError(err)
+	}
+	return c.JSON(http.StatusOK, toWebhookAllowlistResponse(output))
+}
+
+// Update replaces the allowed webhook domains of a community.
+// @Summary Restrict a community's webhook domains
+// @Description Only deliver the community's webhooks to these domains. *.example.com allows the subdomains of example.com.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Community ID"
+// @Param request body updateWebhookAllowlistRequest true "Allowed domains"
+// @Success 200 {object} webhookAllowlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/communities/{id}/webhooks/allowlist [put]
+// @Security BearerAuth
+func (h *WebhookAllowlistHandler) Update(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req updateWebhookAllowlistRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	output, err := h.useCase.Update(c.Request().Context(), application.UpdateWebhookAllowlistInput{
+		CommunityID:         c.Param("id"),
+		Domains:             req.Domains,
+		RequesterExternalID: userExternalID,
+	})
+	if err != nil {
+		return mapWebhookAllowlistError(err)
+	}
+
+	return c.JSON(http.StatusOK, toWebhookAllowlistResponse(output))
+}
+
+// Reset removes a community's webhook allowlist, allowing any domain again.
+// @Summary Remove a community's webhook allowlist
+// @Tags subscriptions
+// @Param id path string true "Community ID"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/communities/{id}/webhooks/allowlist [delete]
+// @Security BearerAuth
+func (h *WebhookAllowlistHandler) Reset(c echo.Context) error {
+	userExternalID := GetUserExternalID(c)
+	if userExternalID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	if err := h.useCase.Reset(c.Request().Context(), c.Param("id"), userExternalID); err != nil {
+		return mapWebhookAllowlistError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// mapWebhookAllowlistError converts use case errors to HTTP errors
+func mapWebhookAllowlistError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrNotCommunityOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the community owner can change its webhook allowlist")
+	case errors.Is(err, domain.ErrNoWebhookDomains),
+		errors.Is(err, domain.ErrTooManyWebhookDomains),
+		errors.Is(err, domain.ErrInvalidWebhookDomain):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toWebhookAllowlistResponse(output *application.WebhookAllowlistOutput) webhookAllowlistResponse {
+	return webhookAllowlistResponse{
+		CommunityID: output.CommunityID,
+		Domains:     output.Domains,
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// WebhookTransferHandler handles exporting and importing webhook subscriptions in bulk.
+// every route requires an admin token.
+type WebhookTransferHandler struct {
+	useCase *application.WebhookTransferUseCase
+}
+
+// NewWebhookTransferHandler creates a new WebhookTransferHandler.
+func NewWebhookTransferHandler(useCase *application.WebhookTransferUseCase) *WebhookTransferHandler {
+	return &WebhookTransferHandler{useCase: useCase}
+}
+
+// RegisterRoutes registers the transfer routes on the given group.
+func (h *WebhookTransferHandler) RegisterRoutes(g *echo.Group) {
+	admin := g.Group("/admin", RequireAdmin())
+	admin.GET("/subscriptions/export", h.Export)
+	admin.POST("/subscriptions/import", h.Import)
+}
+
+// transferredSubscription is a subscription in an export, and in an import request.
+// exports never carry secrets or proxy urls.
+type transferredSubscription struct {
+	// UserExternalID is the subscriber's auth provider id, the same in every environment.
+	UserExternalID string `json:"user_external_id" validate:"required,max=255"`
+	// CommunityID is omitted for a global subscription.
+	CommunityID    string               `json:"community_id,omitempty" validate:"omitempty,uuid"`
+	Channel        string               `json:"channel" validate:"omitempty,oneof=webhook slack discord"`
+	TargetURL      string               `json:"target_url" validate:"required,http_url"`
+	PayloadVersion string               `json:"payload_version,omitempty" validate:"omitempty,oneof=v1"`
+	DeliveryMode   string               `json:"delivery_mode,omitempty" validate:"omitempty,oneof=immediate digest"`
+	ProxyURL       string               `json:"proxy_url,omitempty" validate:"omitempty,url"`
+	PauseWindows   []pauseWindowRequest `json:"pause_windows" validate:"max=14,dive"`
+	IsActive       *bool                `json:"is_active,omitempty"` // true when omitted
+}
+
+type exportSubscriptionsResponse struct {
+	CommunityID    string                    `json:"community_id,omitempty"`
+	OrganizationID string                    `json:"organization_id,omitempty"`
+	ExportedAt     time.Time                 `json:"exported_at"`
+	Count          int                       `json:"count"`
+	Subscriptions  []transferredSubscription `json:"subscriptions"`
+}
+
+// importSubscriptionsRequest takes the body of an export as is, up to 1000 subscriptions.
+type importSubscriptionsRequest struct {
+	Subscriptions []transferredSubscription `json:"subscriptions" validate:"required,max=1000,dive"`
+}
+
+type importResultResponse struct {
+	Index          int    `json:"index"`
+	UserExternalID string `json:"user_external_id"`
+	CommunityID    string `json:"community_id,omitempty"`
+	Action         string `json:"action,omitempty"` // create or replace
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	// Secret is the new signing secret of a webhook subscription, shown once.
+	Secret string `json:"secret,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type importSubscriptionsResponse struct {
+	DryRun   bool                   `json:"dry_run"`
+	Valid    bool                   `json:"valid"`
+	Imported int                    `json:"imported"`
+	Results  []importResultResponse `json:"results"`
+}
+
+// Export returns every subscription to a community, or to an organization's communities.
+// GET /api/v1/admin/subscriptions/export?community_id=|organization_id=
+func (h *WebhookTransferHandler) Export(c echo.Context) error {
+	input := application.ExportSubscriptionsInput{
+		CommunityID:    c.QueryParam("community_id"),
+		OrganizationID: c.QueryParam("organization_id"),
+	}
+
+	subs, err := h.useCase.Export(c.Request().Context(), input)
+	if err != nil {
+		return mapWebhookTransferError(err)
+	}
+
+	resp := exportSubscriptionsResponse{
+		CommunityID:    input.CommunityID,
+		OrganizationID: input.OrganizationID,
+		ExportedAt:     time.Now().UTC(),
+		Count:          len(subs),
+		Subscriptions:  make([]transferredSubscription, len(subs)),
+	}
+	for i, sub := range subs {
+		resp.Subscriptions[i] = toTransferredSubscriptionResponse(sub)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Import creates or replaces subscriptions from an export, with new secrets.
+// they're saved in one transaction. nothing is saved when one of them is invalid,
+// the 422 response says which and why.
+// POST /api/v1/admin/subscriptions/import?dry_run=true
+func (h *WebhookTransferHandler) Import(c echo.Context) error {
+	dryRun := false
+	if s := c.QueryParam("dry_run"); s != "" {
+		parsed, err := strconv.ParseBool(s)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "dry_run must be true or false")
+		}
+		dryRun = parsed
+	}
+
+	var req importSubscriptionsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	input := application.ImportSubscriptionsInput{
+		Subscriptions: make([]application.TransferredSubscription, len(req.Subscriptions)),
+		DryRun:        dryRun,
+	}
+	for i, sub := range req.Subscriptions {
+		input.Subscriptions[i] = fromTransferredSubscriptionRequest(sub)
+	}
+
+	output, err := h.useCase.Import(c.Request().Context(), input)
+	if err != nil && !errors.Is(err, application.ErrSubscriptionImportInvalid) {
+		return echo.NewHTTPError(http.StatusInternalServerError, "subscription import failed")
+	}
+
+	resp := importSubscriptionsResponse{
+		DryRun:   output.DryRun,
+		Valid:    output.Valid(),
+		Imported: output.Imported,
+		Results:  make([]importResultResponse, len(output.Results)),
+	}
+	for i, r := range output.Results {
+		resp.Results[i] = importResultResponse{
+			Index:          r.Index,
+			UserExternalID: r.UserExternalID,
+			CommunityID:    r.CommunityID,
+			Action:         r.Action,
+			SubscriptionID: r.SubscriptionID,
+			Secret:         r.Secret,
+			Error:          r.Error,
+		}
+	}
+
+	status := http.StatusOK
+	if !resp.Valid {
+		status = http.StatusUnprocessableEntity
+	}
+	return c.JSON(status, resp)
+}
+
+// mapWebhookTransferError converts use case errors to HTTP errors
+func mapWebhookTransferError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrExportScopeRequired):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "community or organization not found")
+	default:
+		return mapDomainError(err)
+	}
+}
+
+func toTransferredSubscriptionResponse(sub application.TransferredSubscription) transferredSubscription {
+	windows := make([]pauseWindowRequest, len(sub.PauseWindows))
+	for i, w := range sub.PauseWindows {
+		windows[i] = pauseWindowRequest{Day: w.Day, Start: w.Start, End: w.End, Timezone: w.Timezone}
+	}
+	active := sub.IsActive
+	return transferredSubscription{
+		UserExternalID: sub.UserExternalID,
+		CommunityID:    sub.CommunityID,
+		Channel:        sub.Channel,
+		TargetURL:      sub.TargetURL,
+		PayloadVersion: sub.PayloadVersion,
+		DeliveryMode:   sub.DeliveryMode,
+		PauseWindows:   windows,
+		IsActive:       &active,
+	}
+}
+
+func fromTransferredSubscriptionRequest(req transferredSubscription) application.TransferredSubscription {
+	windows := make([]application.PauseWindowSettings, len(req.PauseWindows))
+	for i, w := range req.PauseWindows {
+		windows[i] = application.PauseWindowSettings{Day: w.Day, Start: w.Start, End: w.End, Timezone: w.Timezone}
+	}
+	return application.TransferredSubscription{
+		UserExternalID: req.UserExternalID,
+		CommunityID:    req.CommunityID,
+		Channel:        req.Channel,
+		TargetURL:      req.TargetURL,
+		PayloadVersion: req.PayloadVersion,
+		DeliveryMode:   req.DeliveryMode,
+		ProxyURL:       req.ProxyURL,
+		PauseWindows:   windows,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+	}
+}
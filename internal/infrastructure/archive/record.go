@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// Record is one archived activity event, one JSON object per line (NDJSON).
+// this is the archive format contract: exporters write it, replay reads it.
+type Record struct {
+	ID          string         `json:"id"`
+	CommunityID string         `json:"community_id"`
+	UserID      *string        `json:"user_id,omitempty"`
+	EventType   string         `json:"event_type"`
+	Weight      float64        `json:"weight"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+
+	// OccurredAt is left out when it's the same as CreatedAt, and in archives written before it existed.
+	OccurredAt *time.Time `json:"occurred_at,omitempty"`
+}
+
+// RecordFromEvent converts a domain event to its archive representation.
+func RecordFromEvent(event *domain.ActivityEvent) Record {
+	record := Record{
+		ID:          event.ID().String(),
+		CommunityID: event.CommunityID().String(),
+		EventType:   event.EventType().String(),
+		Weight:      event.Weight().Value(),
+		Metadata:    event.Metadata(),
+		CreatedAt:   event.CreatedAt(),
+	}
+	if event.UserID() != nil {
+		userID := event.UserID().String()
+		record.UserID = &userID
+	}
+	if occurredAt := event.OccurredAt(); !occurredAt.Equal(event.CreatedAt()) {
+		record.OccurredAt = &occurredAt
+	}
+	return record
+}
+
+// ToEvent validates the record and rebuilds the domain event.
+// the original id and timestamp are kept so replays are faithful.
+func (r Record) ToEvent() (*domain.ActivityEvent, error) {
+	id, err := domain.ParseEventID(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	communityID, err := domain.ParseCommunityID(r.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var userID *domain.UserID
+	if r.UserID != nil {
+		parsed, err := domain.ParseUserID(*r.UserID)
+		if err != nil {
+			return nil, err
+		}
+		userID = &parsed
+	}
+
+	eventType, err := domain.ParseEventType(r.EventType)
+	if err != nil {
+		return nil, err
+	}
+
+	weight, err := domain.NewWeight(r.Weight)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("missing created_at")
+	}
+
+	occurredAt := r.CreatedAt
+	if r.OccurredAt != nil {
+		occurredAt = *r.OccurredAt
+	}
+
+	return domain.ReconstructActivityEvent(
+		id,
+		communityID,
+		userID,
+		eventType,
+		weight,
+		r.Metadata,
+		occurredAt.UTC(),
+		r.CreatedAt.UTC(),
+	), nil
+}
+
+// ReadEvents decodes NDJSON records from r and calls fn for each event.
+// a malformed line aborts the read with its line number, so a bad archive
+// is noticed instead of silently replayed in part.
+func ReadEvents(r io.Reader, fn func(*domain.ActivityEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	// metadata can make lines long, allow up to 1MB per record
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		event, err := record.ToEvent()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,162 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// archive files are NDJSON, optionally gzipped
+var archiveExtensions = []string{".ndjson", ".jsonl", ".ndjson.gz", ".jsonl.gz"}
+
+// Source is a location holding archived event files.
+// implements application.EventArchive.
+type Source struct {
+	lister func(ctx context.Context) ([]string, error)
+	opener func(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// Open resolves an archive uri to a Source.
+// supports s3://bucket/prefix/ and local files or directories.
+// s3 credentials and region come from the standard AWS environment/config chain.
+func Open(ctx context.Context, uri string) (*Source, error) {
+	if rest, ok := strings.CutPrefix(uri, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid s3 uri %q: missing bucket", uri)
+		}
+		return openS3(ctx, bucket, prefix)
+	}
+	return openLocal(uri)
+}
+
+// Files lists archive files in lexical order.
+// exporters name files by time, so lexical order is chronological.
+func (s *Source) Files(ctx context.Context) ([]string, error) {
+	files, err := s.lister(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadFile streams the events of one archive file to fn.
+func (s *Source) ReadFile(ctx context.Context, name string, fn func(*domain.ActivityEvent) error) error {
+	rc, err := s.opener(ctx, name)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", name, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	if err := ReadEvents(r, fn); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func openLocal(path string) (*Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	open := func(_ context.Context, name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+
+	if !info.IsDir() {
+		return &Source{
+			lister: func(context.Context) ([]string, error) { return []string{path}, nil },
+			opener: open,
+		}, nil
+	}
+
+	return &Source{
+		lister: func(context.Context) ([]string, error) {
+			var files []string
+			err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && isArchiveFile(p) {
+					files = append(files, p)
+				}
+				return nil
+			})
+			return files, err
+		},
+		opener: open,
+	}, nil
+}
+
+func openS3(ctx context.Context, bucket, prefix string) (*Source, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading aws config: %w", err)
+	}
+	client := s3.NewFromConfig(cfg)
+
+	return &Source{
+		lister: func(ctx context.Context) ([]string, error) {
+			var keys []string
+			paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+				Bucket: aws.String(bucket),
+				Prefix: aws.String(prefix),
+			})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("listing s3://%s/%s: %w", bucket, prefix, err)
+				}
+				for _, obj := range page.Contents {
+					if key := aws.ToString(obj.Key); isArchiveFile(key) {
+						keys = append(keys, key)
+					}
+				}
+			}
+			return keys, nil
+		},
+		opener: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			out, err := client.GetObject(ctx, &s3.GetObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(key),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return out.Body, nil
+		},
+	}, nil
+}
+
+func isArchiveFile(name string) bool {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -55,6 +55,22 @@ func (c *SupabaseClaims) IsAuthenticated() bool {
 	return c.Role == "authenticated"
 }
 
+// IsServiceRole returns true for supabase service_role keys.
+// these are server-side credentials with full access and no user behind them.
+func (c *SupabaseClaims) IsServiceRole() bool {
+	return c.Role == "service_role"
+}
+
+// IsAdmin returns true for the service role, or a user whose app_metadata has role "admin".
+// app_metadata can only be set server-side, so users can't grant it to themselves.
+func (c *SupabaseClaims) IsAdmin() bool {
+	if c.IsServiceRole() {
+		return true
+	}
+	role, _ := c.AppMetadata["role"].(string)
+	return role == "admin"
+}
+
 // JWTValidator validates supabase auth tokens
 type JWTValidator struct {
 	secret []byte
@@ -120,7 +136,8 @@ func (v *JWTValidator) ValidateToken(tokenString string) (*SupabaseClaims, error
 	}
 
 	// validate essential claims
-	if claims.Subject == "" {
+	// service_role keys are not issued to a user, so they carry no subject
+	if claims.Subject == "" && !claims.IsServiceRole() {
 		return nil, fmt.Errorf("%w: missing subject claim", ErrInvalidClaims)
 	}
 
@@ -0,0 +1,94 @@
+package billing
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+var csvHeader = []string{"id", "subject", "subject_id", "metric", "quantity", "period_start", "period_end"}
+
+// CSVExporter appends metering records to one CSV file per UTC day,
+// named metering-YYYY-MM-DD.csv after the period start.
+// meant for spreadsheets or a billing job that picks up finished days.
+type CSVExporter struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewCSVExporter creates an exporter writing into dir, creating it if needed.
+func NewCSVExporter(dir string) (*CSVExporter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating metering export dir: %w", err)
+	}
+	return &CSVExporter{dir: dir}, nil
+}
+
+// Name identifies the exporter in logs.
+func (e *CSVExporter) Name() string {
+	return "csv"
+}
+
+// Export appends records to their day's file, writing the header to new files.
+func (e *CSVExporter) Export(_ context.Context, records []*domain.MeteringRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	byDay := make(map[string][]*domain.MeteringRecord)
+	for _, r := range records {
+		day := r.PeriodStart().UTC().Format(time.DateOnly)
+		byDay[day] = append(byDay[day], r)
+	}
+
+	var errs []error
+	for day, recs := range byDay {
+		if err := e.appendFile(filepath.Join(e.dir, "metering-"+day+".csv"), recs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *CSVExporter) appendFile(path string, records []*domain.MeteringRecord) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		_ = w.Write(csvHeader)
+	}
+	for _, r := range records {
+		_ = w.Write([]string{
+			r.ID().String(),
+			r.Subject().String(),
+			r.SubjectID(),
+			r.Metric().String(),
+			strconv.FormatInt(r.Quantity(), 10),
+			r.PeriodStart().UTC().Format(time.RFC3339),
+			r.PeriodEnd().UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,159 @@
+package billing
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// stripeMeterEventsURL is the Stripe billing meter events endpoint.
+const stripeMeterEventsURL = "https://api.stripe.com/v1/billing/meter_events"
+
+// StripeConfig holds configuration for the Stripe exporter.
+type StripeConfig struct {
+	// APIKey is a Stripe secret or restricted key with meter event write access.
+	APIKey string
+
+	// EventPrefix is prepended to the metric to build the meter event name,
+	// e.g. "pulse_" sends events_ingested as "pulse_events_ingested".
+	EventPrefix string
+
+	// Customers maps "subject:subject_id" to a Stripe customer id.
+	// records for subjects without a customer are skipped.
+	Customers map[string]string
+
+	// Timeout is the max time to wait for each request.
+	Timeout time.Duration
+}
+
+// StripeExporter reports metering records to Stripe as billing meter events.
+// each record is sent with its id as the event identifier, so Stripe drops re-exports.
+type StripeExporter struct {
+	config     StripeConfig
+	endpoint   string
+	httpClient *http.Client
+	logger     *logging.Logger
+}
+
+// NewStripeExporter creates a new Stripe exporter.
+func NewStripeExporter(config StripeConfig, logger *logging.Logger) *StripeExporter {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	return &StripeExporter{
+		config:     config,
+		endpoint:   stripeMeterEventsURL,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		logger:     logger.WithComponent("stripe_exporter"),
+	}
+}
+
+// Name identifies the exporter in logs.
+func (e *StripeExporter) Name() string {
+	return "stripe"
+}
+
+// Export sends one meter event per record whose subject has a Stripe customer.
+func (e *StripeExporter) Export(ctx context.Context, records []*domain.MeteringRecord) error {
+	var (
+		sent, skipped int
+		errs          []error
+	)
+	for _, r := range records {
+		customer, ok := e.config.Customers[CustomerKey(r.Subject(), r.SubjectID())]
+		if !ok {
+			skipped++
+			continue
+		}
+		if err := e.send(ctx, r, customer); err != nil {
+			errs = append(errs, fmt.Errorf("record %s: %w", r.ID(), err))
+			continue
+		}
+		sent++
+	}
+
+	e.logger.WithContext(ctx).Debug("stripe meter events sent",
+		"sent", sent,
+		"skipped", skipped,
+		"failed", len(errs),
+	)
+	return errors.Join(errs...)
+}
+
+func (e *StripeExporter) send(ctx context.Context, r *domain.MeteringRecord, customer string) error {
+	form := url.Values{}
+	form.Set("event_name", e.config.EventPrefix+r.Metric().String())
+	form.Set("identifier", r.ID().String())
+	// stripe attributes the usage to the event timestamp, so use the end of the period
+	form.Set("timestamp", strconv.FormatInt(r.PeriodEnd().Unix(), 10))
+	form.Set("payload[stripe_customer_id]", customer)
+	form.Set("payload[value]", strconv.FormatInt(r.Quantity(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("stripe returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// CustomerKey is the key for a subject in StripeConfig.Customers.
+func CustomerKey(subject domain.MeterSubject, subjectID string) string {
+	return subject.String() + ":" + subjectID
+}
+
+// LoadStripeCustomers reads the subject to customer mapping from a CSV file
+// with rows of subject,subject_id,stripe_customer_id. lines starting with # are ignored.
+func LoadStripeCustomers(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening stripe customers: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = 3
+	r.TrimLeadingSpace = true
+
+	customers := make(map[string]string)
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading stripe customers: %w", err)
+		}
+		subject, err := domain.ParseMeterSubject(row[0])
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("stripe customers line %d: %w", line, err)
+		}
+		customers[CustomerKey(subject, row[1])] = row[2]
+	}
+	return customers, nil
+}
@@ -2,17 +2,20 @@ package cache
 
 import (
 	"context"
+	"errors"
 
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 )
 
 // CommunityRepositoryWithCache wraps a CommunityRepository and adds Redis caching.
-// uses redis for the hot path (ListByMomentum) and falls back to postgres on errors.
+// uses redis for the hot path (ListPublicByMomentum) and falls back to postgres on errors.
 type CommunityRepositoryWithCache struct {
-	repo   domain.CommunityRepository
-	redis  *RedisClient
-	logger *logging.Logger
+	repo      domain.CommunityRepository
+	summaries domain.CommunitySummaryRepository
+	redis     *RedisClient
+	logger    *logging.Logger
+	onEmpty   func()
 }
 
 // NewCommunityRepositoryWithCache creates a cached community repository.
@@ -29,6 +32,20 @@ func NewCommunityRepositoryWithCache(
 	}
 }
 
+// OnEmptyLeaderboard calls fn whenever a read finds the leaderboard empty, which
+// may mean redis lost its data. fn must not block the read, e.g. a resync worker's Kick.
+func (r *CommunityRepositoryWithCache) OnEmptyLeaderboard(fn func()) *CommunityRepositoryWithCache {
+	r.onEmpty = fn
+	return r
+}
+
+// WithSummaries breaks momentum ties on the cached leaderboard by member count, like
+// postgres does. without it, tied communities are ordered by age alone.
+func (r *CommunityRepositoryWithCache) WithSummaries(summaries domain.CommunitySummaryRepository) *CommunityRepositoryWithCache {
+	r.summaries = summaries
+	return r
+}
+
 // FindByID delegates directly to the underlying repository.
 // single entity lookups don't benefit much from caching here.
 func (r *CommunityRepositoryWithCache) FindByID(ctx context.Context, id domain.CommunityID) (*domain.Community, error) {
@@ -55,36 +72,52 @@ func (r *CommunityRepositoryWithCache) Exists(ctx context.Context, id domain.Com
 	return r.repo.Exists(ctx, id)
 }
 
+// ListByOrganization delegates directly to the underlying repository.
+// the redis leaderboard is global, so organization leaderboards come from postgres.
+func (r *CommunityRepositoryWithCache) ListByOrganization(ctx context.Context, orgID domain.OrganizationID, limit, offset int) ([]*domain.Community, error) {
+	return r.repo.ListByOrganization(ctx, orgID, limit, offset)
+}
+
 // UpdateMomentum delegates directly to the underlying repository.
 // redis sync is handled by the use case, not here.
 func (r *CommunityRepositoryWithCache) UpdateMomentum(ctx context.Context, id domain.CommunityID, momentum domain.Momentum) error {
 	return r.repo.UpdateMomentum(ctx, id, momentum)
 }
 
-// ListByMomentum returns active communities ordered by momentum.
-// tries redis first for sub-millisecond response, falls back to postgres on error.
+// ListByMomentum delegates directly to the underlying repository.
+// the redis leaderboard only holds public communities, batch jobs need all of them.
 func (r *CommunityRepositoryWithCache) ListByMomentum(ctx context.Context, limit, offset int) ([]*domain.Community, error) {
+	return r.repo.ListByMomentum(ctx, limit, offset)
+}
+
+// ListPublicByMomentum returns active public communities ordered by momentum.
+// tries redis first for sub-millisecond response, falls back to postgres on error.
+func (r *CommunityRepositoryWithCache) ListPublicByMomentum(ctx context.Context, limit, offset int) ([]*domain.Community, error) {
 	// if redis is not configured, go straight to postgres
 	if r.redis == nil {
-		return r.repo.ListByMomentum(ctx, limit, offset)
+		return r.repo.ListPublicByMomentum(ctx, limit, offset)
 	}
 
-	// try to get community IDs from redis leaderboard
-	communityIDs, err := r.redis.GetTopCommunities(ctx, int64(limit), int64(offset))
+	// try to get community IDs from redis leaderboard, with the ties around the page
+	communityIDs, skip, err := r.redis.GetTopCommunitiesWithTies(ctx, int64(limit), int64(offset))
 	if err != nil {
+		if errors.Is(err, ErrRedisEmpty) && r.onEmpty != nil {
+			r.onEmpty()
+		}
 		// redis failed or empty - fall back to postgres
 		r.logger.Debug("leaderboard cache miss, falling back to postgres",
 			"limit", limit,
 			"offset", offset,
 			"reason", err.Error(),
 		)
-		return r.repo.ListByMomentum(ctx, limit, offset)
+		return r.repo.ListPublicByMomentum(ctx, limit, offset)
 	}
 
 	r.logger.Debug("leaderboard cache hit",
 		"limit", limit,
 		"offset", offset,
 		"cached_count", len(communityIDs),
+		"tied_before", skip,
 	)
 
 	// convert string IDs to domain IDs
@@ -105,26 +138,129 @@ func (r *CommunityRepositoryWithCache) ListByMomentum(ctx context.Context, limit
 	if len(ids) == 0 {
 		// all IDs were invalid? fall back to postgres
 		r.logger.Warn("all leaderboard cache entries invalid, falling back to postgres")
-		return r.repo.ListByMomentum(ctx, limit, offset)
+		return r.repo.ListPublicByMomentum(ctx, limit, offset)
 	}
 
 	// fetch full community details from postgres
-	// FindByIDs preserves the order from redis (momentum descending)
 	communities, err := r.repo.FindByIDs(ctx, ids)
 	if err != nil {
 		// postgres failed after redis success - this is a real error
 		return nil, err
 	}
 
-	if len(communities) != len(ids) {
+	// a community made private or deactivated since its last score is stale too
+	if len(communities) != len(ids) || !allListed(communities) {
 		r.logger.Warn("leaderboard cache returned stale community ids, falling back to postgres",
 			"expected", len(ids),
 			"received", len(communities),
 			"limit", limit,
 			"offset", offset,
 		)
-		return r.repo.ListByMomentum(ctx, limit, offset)
+		return r.repo.ListPublicByMomentum(ctx, limit, offset)
+	}
+
+	return r.cutPage(ctx, communities, int(skip), limit), nil
+}
+
+// cutPage sorts the communities read around a page, ties included, and returns the page.
+func (r *CommunityRepositoryWithCache) cutPage(ctx context.Context, communities []*domain.Community, skip, limit int) []*domain.Community {
+	r.sortByRank(ctx, communities)
+	start := min(skip, len(communities))
+	return communities[start:min(start+limit, len(communities))]
+}
+
+// LeaderboardRank returns a community's 0-based rank on the cached leaderboard, with ties
+// ordered by domain.CompareLeaderboardRank like ListPublicByMomentum.
+// returns ErrRedisEmpty if the community isn't on the leaderboard.
+func (r *CommunityRepositoryWithCache) LeaderboardRank(ctx context.Context, communityID string) (int64, error) {
+	if r.redis == nil {
+		return 0, ErrRedisNotConnected
+	}
+
+	above, tied, err := r.redis.GetCommunityTies(ctx, communityID)
+	if err != nil {
+		return 0, err
+	}
+	if len(tied) == 1 {
+		return above, nil
+	}
+
+	ids := make([]domain.CommunityID, 0, len(tied))
+	for _, idStr := range tied {
+		if id, err := domain.ParseCommunityID(idStr); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	communities, err := r.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	r.sortByRank(ctx, communities)
+	for i, c := range communities {
+		if c.ID().String() == communityID {
+			return above + int64(i), nil
+		}
+	}
+	return 0, domain.ErrNotFound
+}
+
+// LeaderboardPosition returns a community's rank now and the rank its previous momentum
+// would have among the current scores, so spike notifications can show the move.
+// the current rank places ties like LeaderboardRank, so it matches the listing.
+// returns ErrRedisEmpty if the community isn't on the leaderboard, like a private one.
+func (r *CommunityRepositoryWithCache) LeaderboardPosition(ctx context.Context, communityID string, previousMomentum float64) (domain.LeaderboardPosition, error) {
+	if r.redis == nil {
+		return domain.LeaderboardPosition{}, ErrRedisNotConnected
 	}
 
-	return communities, nil
+	previous, size, err := r.redis.PreviousLeaderboardRank(ctx, communityID, previousMomentum)
+	if err != nil {
+		return domain.LeaderboardPosition{}, err
+	}
+	rank, err := r.LeaderboardRank(ctx, communityID)
+	if err != nil {
+		return domain.LeaderboardPosition{}, err
+	}
+
+	return domain.LeaderboardPosition{
+		PreviousRank: previous,
+		NewRank:      rank + 1,
+		Size:         size,
+	}, nil
+}
+
+// sortByRank sorts communities by domain.CompareLeaderboardRank, with the member counts of
+// the summaries when there are any.
+func (r *CommunityRepositoryWithCache) sortByRank(ctx context.Context, communities []*domain.Community) {
+	var members map[domain.CommunityID]int
+	if r.summaries != nil {
+		ids := make([]domain.CommunityID, len(communities))
+		for i, c := range communities {
+			ids[i] = c.ID()
+		}
+		summaries, err := r.summaries.FindByCommunityIDs(ctx, ids)
+		if err != nil {
+			// the page is still ranked by momentum, only ties may differ from postgres
+			r.logger.Warn("member counts unavailable for leaderboard ties",
+				"error", err.Error(),
+			)
+		}
+		members = make(map[domain.CommunityID]int, len(summaries))
+		for id, s := range summaries {
+			members[id] = s.MemberCount()
+		}
+	}
+
+	domain.SortByLeaderboardRank(communities, members)
+}
+
+// allListed reports whether every community belongs on the public leaderboard.
+func allListed(communities []*domain.Community) bool {
+	for _, c := range communities {
+		if !c.IsActive() || !c.Visibility().IsListed() {
+			return false
+		}
+	}
+	return true
 }
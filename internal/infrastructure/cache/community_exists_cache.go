@@ -19,9 +19,11 @@ type CommunityExistsCache struct {
 }
 
 type communityEntry struct {
-	exists    bool
-	isActive  bool
-	expiresAt time.Time
+	exists         bool
+	isActive       bool
+	organizationID *domain.OrganizationID
+	visibility     domain.CommunityVisibility
+	expiresAt      time.Time
 }
 
 // NewCommunityExistsCache creates a new community existence cache.
@@ -37,6 +39,35 @@ func NewCommunityExistsCache(repo domain.CommunityRepository, ttl time.Duration)
 // returns (exists, isActive, error).
 // uses cache if available, otherwise queries the database.
 func (c *CommunityExistsCache) CheckActive(ctx context.Context, id domain.CommunityID) (exists, isActive bool, err error) {
+	entry, err := c.lookup(ctx, id)
+	if err != nil {
+		return false, false, err
+	}
+	return entry.exists, entry.isActive, nil
+}
+
+// OrganizationOf returns the organization a community belongs to, or nil if it's standalone or missing.
+func (c *CommunityExistsCache) OrganizationOf(ctx context.Context, id domain.CommunityID) (*domain.OrganizationID, error) {
+	entry, err := c.lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return entry.organizationID, nil
+}
+
+// VisibilityOf returns a community's visibility, public for missing communities.
+func (c *CommunityExistsCache) VisibilityOf(ctx context.Context, id domain.CommunityID) (domain.CommunityVisibility, error) {
+	entry, err := c.lookup(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !entry.exists {
+		return domain.VisibilityPublic, nil
+	}
+	return entry.visibility, nil
+}
+
+func (c *CommunityExistsCache) lookup(ctx context.Context, id domain.CommunityID) (*communityEntry, error) {
 	idStr := id.String()
 
 	// fast path: check cache
@@ -44,7 +75,7 @@ func (c *CommunityExistsCache) CheckActive(ctx context.Context, id domain.Commun
 	entry, ok := c.entries[idStr]
 	if ok && time.Now().Before(entry.expiresAt) {
 		c.mu.RUnlock()
-		return entry.exists, entry.isActive, nil
+		return entry, nil
 	}
 	c.mu.RUnlock()
 
@@ -53,36 +84,114 @@ func (c *CommunityExistsCache) CheckActive(ctx context.Context, id domain.Commun
 	if err != nil {
 		if err == domain.ErrNotFound {
 			// cache negative result
-			c.mu.Lock()
-			c.entries[idStr] = &communityEntry{
+			entry = &communityEntry{
 				exists:    false,
 				isActive:  false,
 				expiresAt: time.Now().Add(c.ttl),
 			}
+			c.mu.Lock()
+			c.entries[idStr] = entry
 			c.mu.Unlock()
-			return false, false, nil
+			return entry, nil
 		}
-		return false, false, err
+		return nil, err
 	}
 
 	// cache positive result
-	c.mu.Lock()
-	c.entries[idStr] = &communityEntry{
-		exists:    true,
-		isActive:  community.IsActive(),
-		expiresAt: time.Now().Add(c.ttl),
+	entry = &communityEntry{
+		exists:         true,
+		isActive:       community.IsActive(),
+		organizationID: community.OrganizationID(),
+		visibility:     community.Visibility(),
+		expiresAt:      time.Now().Add(c.ttl),
 	}
+	c.mu.Lock()
+	c.entries[idStr] = entry
 	c.mu.Unlock()
 
-	return true, community.IsActive(), nil
+	return entry, nil
+}
+
+// Warm caches a community that was just saved, so ingestion accepts its events right away
+// without a database round trip. replaces a negative entry cached before it existed.
+func (c *CommunityExistsCache) Warm(community *domain.Community) {
+	entry := &communityEntry{
+		exists:         true,
+		isActive:       community.IsActive(),
+		organizationID: community.OrganizationID(),
+		visibility:     community.Visibility(),
+		expiresAt:      time.Now().Add(c.ttl),
+	}
+	c.mu.Lock()
+	c.entries[community.ID().String()] = entry
+	c.mu.Unlock()
 }
 
-// Invalidate removes a community from the cache.
+// Invalidate removes a community from the cache, reporting whether it was cached.
 // call this when a community is created or its status changes.
-func (c *CommunityExistsCache) Invalidate(id domain.CommunityID) {
+func (c *CommunityExistsCache) Invalidate(id domain.CommunityID) bool {
 	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[id.String()]
 	delete(c.entries, id.String())
-	c.mu.Unlock()
+	return ok
+}
+
+// HandleCommunityCreated drops a new community from the cache, in case an event for it
+// was rejected just before and cached as missing. subscribed to the event bus for every instance.
+func (c *CommunityExistsCache) HandleCommunityCreated(_ context.Context, event domain.DomainEvent) {
+	if e, ok := event.(domain.CommunityCreated); ok {
+		c.Invalidate(e.CommunityID)
+	}
+}
+
+// HandleCommunityDeactivated drops a deactivated community from the cache, so ingestion
+// stops accepting its events right away. subscribed to the event bus for every instance.
+func (c *CommunityExistsCache) HandleCommunityDeactivated(_ context.Context, event domain.DomainEvent) {
+	if e, ok := event.(domain.CommunityDeactivated); ok {
+		c.Invalidate(e.CommunityID)
+	}
+}
+
+// Flush removes every entry and returns how many there were.
+func (c *CommunityExistsCache) Flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.entries)
+	c.entries = make(map[string]*communityEntry)
+	return n
+}
+
+// CommunityExistsStats summarizes the entries of a CommunityExistsCache.
+type CommunityExistsStats struct {
+	Entries  int
+	Active   int
+	Inactive int
+	Missing  int // cached lookups of communities that don't exist
+	Expired  int // left for the next Cleanup
+	TTL      time.Duration
+}
+
+// Stats counts the cached entries by state.
+func (c *CommunityExistsCache) Stats() CommunityExistsStats {
+	now := time.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := CommunityExistsStats{Entries: len(c.entries), TTL: c.ttl}
+	for _, entry := range c.entries {
+		switch {
+		case now.After(entry.expiresAt):
+			stats.Expired++
+		case !entry.exists:
+			stats.Missing++
+		case entry.isActive:
+			stats.Active++
+		default:
+			stats.Inactive++
+		}
+	}
+	return stats
 }
 
 // Size returns the current number of cached entries.
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityPinCache is an in-memory TTL cache in front of the community pin repository.
+// every first leaderboard page reads the pins, and there are only ever a few of them,
+// so the whole schedule is cached at once. writes through this cache drop it; other
+// instances pick up the change once it expires.
+type CommunityPinCache struct {
+	repo domain.CommunityPinRepository
+	ttl  time.Duration
+
+	mu        sync.RWMutex
+	pins      []*domain.CommunityPin
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// NewCommunityPinCache creates a new community pin cache.
+func NewCommunityPinCache(repo domain.CommunityPinRepository, ttl time.Duration) *CommunityPinCache {
+	return &CommunityPinCache{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// ListEndingAfter returns the pins still active or scheduled at t, using the cache when fresh.
+func (c *CommunityPinCache) ListEndingAfter(ctx context.Context, t time.Time) ([]*domain.CommunityPin, error) {
+	now := time.Now()
+
+	// fast path: the cached schedule holds every pin ending after it was fetched
+	c.mu.RLock()
+	if now.Before(c.expiresAt) && !t.Before(c.fetchedAt) {
+		pins := make([]*domain.CommunityPin, 0, len(c.pins))
+		for _, pin := range c.pins {
+			if pin.EndsAt().After(t) {
+				pins = append(pins, pin)
+			}
+		}
+		c.mu.RUnlock()
+		return pins, nil
+	}
+	c.mu.RUnlock()
+
+	// slow path: query database
+	pins, err := c.repo.ListEndingAfter(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.pins = pins
+	c.fetchedAt = t
+	c.expiresAt = now.Add(c.ttl)
+	c.mu.Unlock()
+
+	return pins, nil
+}
+
+// Save persists a pin and drops the cached schedule.
+func (c *CommunityPinCache) Save(ctx context.Context, pin *domain.CommunityPin) error {
+	if err := c.repo.Save(ctx, pin); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// Delete removes a pin and drops the cached schedule.
+func (c *CommunityPinCache) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := c.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// Invalidate drops the cached schedule.
+func (c *CommunityPinCache) Invalidate() {
+	c.mu.Lock()
+	c.expiresAt = time.Time{}
+	c.mu.Unlock()
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+const (
+	// contributorsBucket is the span of one unique contributor HyperLogLog.
+	// windows are counted by merging the buckets they cover.
+	contributorsBucket = time.Hour
+
+	// contributorsRetention is how long buckets are kept, enough for the 7 day stats window.
+	contributorsRetention = 8 * 24 * time.Hour
+)
+
+// ContributorsKey returns the HyperLogLog key of a community's contributors in the hour starting at bucket.
+func ContributorsKey(communityID string, bucket time.Time) string {
+	return "pulse:contributors:" + communityID + ":" + bucket.UTC().Format("2006010215")
+}
+
+// TrackContributors adds the users behind saved events to their community's hourly HyperLogLog.
+// anonymous events are skipped. one pipelined round trip per batch.
+func (r *RedisClient) TrackContributors(ctx context.Context, events []*domain.ActivityEvent) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	users := make(map[string][]any)
+	for _, e := range events {
+		if e.UserID() == nil {
+			continue
+		}
+		key := ContributorsKey(e.CommunityID().String(), e.CreatedAt().Truncate(contributorsBucket))
+		users[key] = append(users[key], e.UserID().String())
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for key, ids := range users {
+		pipe.PFAdd(ctx, key, ids...)
+		pipe.Expire(ctx, key, contributorsRetention)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("pfadd contributors failed: %w", err)
+	}
+	return nil
+}
+
+// UniqueContributors approximates how many distinct users sent events to a community
+// from since until now, to the hour. the standard error is under 1%.
+func (r *RedisClient) UniqueContributors(ctx context.Context, communityID domain.CommunityID, since, now time.Time) (int64, error) {
+	if r.client == nil {
+		return 0, ErrRedisNotConnected
+	}
+
+	var keys []string
+	for bucket := since.Truncate(contributorsBucket); !bucket.After(now); bucket = bucket.Add(contributorsBucket) {
+		keys = append(keys, ContributorsKey(communityID.String(), bucket))
+	}
+
+	count, err := r.client.PFCount(ctx, keys...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("pfcount contributors failed: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// EventWeightsCache is an in-memory TTL cache in front of the event weights repository.
+// ingestion looks up the weights of the community of every event sent without a weight,
+// and almost no community has overrides, so negative results are cached too.
+// writes through this cache invalidate the local entry; other instances
+// pick up the change once their entry expires.
+type EventWeightsCache struct {
+	entries map[string]*eventWeightsEntry
+	mu      sync.RWMutex
+	ttl     time.Duration
+	repo    domain.CommunityEventWeightsRepository
+}
+
+type eventWeightsEntry struct {
+	weights   *domain.CommunityEventWeights // nil means no overrides
+	expiresAt time.Time
+}
+
+// NewEventWeightsCache creates a new event weights cache.
+func NewEventWeightsCache(repo domain.CommunityEventWeightsRepository, ttl time.Duration) *EventWeightsCache {
+	return &EventWeightsCache{
+		entries: make(map[string]*eventWeightsEntry),
+		ttl:     ttl,
+		repo:    repo,
+	}
+}
+
+// FindByCommunity returns the overrides for a community, using the cache when fresh.
+// returns domain.ErrNotFound if the community has no overrides.
+func (c *EventWeightsCache) FindByCommunity(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityEventWeights, error) {
+	idStr := communityID.String()
+
+	// fast path: check cache
+	c.mu.RLock()
+	entry, ok := c.entries[idStr]
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.mu.RUnlock()
+		if entry.weights == nil {
+			return nil, domain.ErrNotFound
+		}
+		return entry.weights, nil
+	}
+	c.mu.RUnlock()
+
+	// slow path: query database
+	weights, err := c.repo.FindByCommunity(ctx, communityID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[idStr] = &eventWeightsEntry{
+		weights:   weights,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return weights, err
+}
+
+// Save persists overrides and drops the cached entry.
+func (c *EventWeightsCache) Save(ctx context.Context, weights *domain.CommunityEventWeights) error {
+	if err := c.repo.Save(ctx, weights); err != nil {
+		return err
+	}
+	c.Invalidate(weights.CommunityID())
+	return nil
+}
+
+// Delete removes overrides and drops the cached entry.
+func (c *EventWeightsCache) Delete(ctx context.Context, communityID domain.CommunityID) error {
+	if err := c.repo.Delete(ctx, communityID); err != nil {
+		return err
+	}
+	c.Invalidate(communityID)
+	return nil
+}
+
+// Invalidate removes a community from the cache.
+func (c *EventWeightsCache) Invalidate(communityID domain.CommunityID) {
+	c.mu.Lock()
+	delete(c.entries, communityID.String())
+	c.mu.Unlock()
+}
+
+// Cleanup removes expired entries.
+// call this periodically to prevent memory growth.
+func (c *EventWeightsCache) Cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// FeedKey returns the key a user's discovery feed is cached under.
+func FeedKey(key string) string {
+	return "pulse:feed:" + key
+}
+
+// feedEntry is the cached form of a domain.FeedEntry.
+type feedEntry struct {
+	CommunityID string              `json:"community_id"`
+	Reasons     []domain.FeedReason `json:"reasons"`
+	Score       float64             `json:"score"`
+}
+
+// GetFeed returns a cached feed, with ok false on a miss.
+func (r *RedisClient) GetFeed(ctx context.Context, key string) ([]domain.FeedEntry, bool, error) {
+	if r.client == nil {
+		return nil, false, ErrRedisNotConnected
+	}
+
+	raw, err := r.client.Get(ctx, FeedKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get feed failed: %w", err)
+	}
+
+	var cached []feedEntry
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return nil, false, fmt.Errorf("decoding feed: %w", err)
+	}
+
+	entries := make([]domain.FeedEntry, 0, len(cached))
+	for _, e := range cached {
+		id, err := domain.ParseCommunityID(e.CommunityID)
+		if err != nil {
+			return nil, false, fmt.Errorf("decoding feed: %w", err)
+		}
+		entries = append(entries, domain.FeedEntry{
+			CommunityID: id,
+			Reasons:     e.Reasons,
+			Score:       e.Score,
+		})
+	}
+	return entries, true, nil
+}
+
+// SetFeed caches a feed for ttl.
+func (r *RedisClient) SetFeed(ctx context.Context, key string, entries []domain.FeedEntry, ttl time.Duration) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	cached := make([]feedEntry, len(entries))
+	for i, e := range entries {
+		cached[i] = feedEntry{
+			CommunityID: e.CommunityID.String(),
+			Reasons:     e.Reasons,
+			Score:       e.Score,
+		}
+	}
+
+	raw, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("encoding feed: %w", err)
+	}
+	if err := r.client.Set(ctx, FeedKey(key), raw, ttl).Err(); err != nil {
+		return fmt.Errorf("set feed failed: %w", err)
+	}
+	return nil
+}
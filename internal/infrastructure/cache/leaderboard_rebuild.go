@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// leaderboardStagingKey receives scores during a rebuild before being swapped in.
+	leaderboardStagingKey = LeaderboardKey + ":rebuild"
+
+	// leaderboardRebuildLockKey keeps two rebuilds (say the CLI and the admin endpoint) from interleaving.
+	leaderboardRebuildLockKey = LeaderboardKey + ":rebuild:lock"
+
+	// leaderboardBuiltKey is set by every rebuild. redis losing its data takes it along,
+	// which is how a restarted or flushed redis is told apart from an empty leaderboard.
+	leaderboardBuiltKey = LeaderboardKey + ":built"
+
+	// leaderboardRebuildLockTTL bounds how long a crashed rebuild blocks the next one.
+	leaderboardRebuildLockTTL = 10 * time.Minute
+)
+
+// BeginLeaderboardRebuild takes the rebuild lock and clears any leftover staging set.
+// returns false if another rebuild holds the lock.
+func (r *RedisClient) BeginLeaderboardRebuild(ctx context.Context) (bool, error) {
+	if r.client == nil {
+		return false, ErrRedisNotConnected
+	}
+
+	acquired, err := r.client.SetNX(ctx, leaderboardRebuildLockKey, time.Now().UTC().Format(time.RFC3339), leaderboardRebuildLockTTL).Result()
+	if err != nil {
+		return false, fmt.Errorf("setnx failed: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	if err := r.client.Del(ctx, leaderboardStagingKey).Err(); err != nil {
+		_ = r.client.Del(ctx, leaderboardRebuildLockKey).Err()
+		return false, fmt.Errorf("clearing staging set: %w", err)
+	}
+
+	return true, nil
+}
+
+// StageLeaderboardScores adds a batch of scores to the staging set in one round trip.
+func (r *RedisClient) StageLeaderboardScores(ctx context.Context, scores map[string]float64) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(scores))
+	for communityID, momentum := range scores {
+		members = append(members, redis.Z{Score: momentum, Member: communityID})
+	}
+
+	if err := r.client.ZAdd(ctx, leaderboardStagingKey, members...).Err(); err != nil {
+		return fmt.Errorf("zadd staging failed: %w", err)
+	}
+	return nil
+}
+
+// CommitLeaderboardRebuild swaps the staging set in as the live leaderboard and releases the lock.
+// the swap is a single RENAME, so readers never see a half-built leaderboard.
+// communities dropped from postgres disappear with the old set.
+// also marks the leaderboard as built, see LeaderboardBuilt.
+func (r *RedisClient) CommitLeaderboardRebuild(ctx context.Context) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+	defer func() { _ = r.client.Del(context.WithoutCancel(ctx), leaderboardRebuildLockKey).Err() }()
+
+	staged, err := r.client.Exists(ctx, leaderboardStagingKey).Result()
+	if err != nil {
+		return fmt.Errorf("exists failed: %w", err)
+	}
+
+	// no active communities: an empty sorted set doesn't exist in redis, so just truncate
+	if staged == 0 {
+		if err := r.client.Del(ctx, LeaderboardKey).Err(); err != nil {
+			return fmt.Errorf("del failed: %w", err)
+		}
+		return r.markLeaderboardBuilt(ctx)
+	}
+
+	if err := r.client.Rename(ctx, leaderboardStagingKey, LeaderboardKey).Err(); err != nil {
+		return fmt.Errorf("rename failed: %w", err)
+	}
+
+	r.logger.Debug("leaderboard swapped in")
+	return r.markLeaderboardBuilt(ctx)
+}
+
+func (r *RedisClient) markLeaderboardBuilt(ctx context.Context) error {
+	if err := r.client.Set(ctx, leaderboardBuiltKey, time.Now().UTC().Format(time.RFC3339), 0).Err(); err != nil {
+		return fmt.Errorf("set failed: %w", err)
+	}
+	return nil
+}
+
+// LeaderboardBuilt reports whether the leaderboard was rebuilt since redis last lost its data.
+// false means scores written since are all redis has, so the leaderboard needs a rebuild.
+func (r *RedisClient) LeaderboardBuilt(ctx context.Context) (bool, error) {
+	if r.client == nil {
+		return false, ErrRedisNotConnected
+	}
+
+	n, err := r.client.Exists(ctx, leaderboardBuiltKey).Result()
+	if err != nil {
+		return false, fmt.Errorf("exists failed: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AbortLeaderboardRebuild drops the staging set and releases the lock, leaving the live leaderboard as it was.
+func (r *RedisClient) AbortLeaderboardRebuild(ctx context.Context) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	if err := r.client.Del(ctx, leaderboardStagingKey, leaderboardRebuildLockKey).Err(); err != nil {
+		return fmt.Errorf("del failed: %w", err)
+	}
+	return nil
+}
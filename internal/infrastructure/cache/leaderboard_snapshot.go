@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// leaderboardSnapshotKey receives a momentum cycle's scores before being swapped in.
+	leaderboardSnapshotKey = LeaderboardKey + ":cycle"
+
+	// leaderboardSnapshotLockKey is held while a cycle builds its snapshot, by one instance at a time.
+	// writes outside the cycle also go to the snapshot while it's held, see UpdateLeaderboardScore.
+	leaderboardSnapshotLockKey = LeaderboardKey + ":cycle:lock"
+
+	// leaderboardSnapshotLockTTL bounds how long a crashed cycle blocks the next one.
+	leaderboardSnapshotLockTTL = 10 * time.Minute
+)
+
+// ErrSnapshotLockLost is returned when committing a snapshot whose lock expired, and may have
+// been taken by another cycle since. the snapshot isn't swapped in.
+var ErrSnapshotLockLost = errors.New("leaderboard snapshot lock lost")
+
+// swapSnapshotIfOwner swaps the snapshot in as the live leaderboard and releases the lock, if
+// the lock still holds the cycle's token. an empty snapshot empties the live leaderboard.
+// KEYS: snapshot, live, lock. ARGV: token. returns 0 when the lock isn't the cycle's.
+var swapSnapshotIfOwner = redis.NewScript(`
+if redis.call('GET', KEYS[3]) ~= ARGV[1] then
+	return 0
+end
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('RENAME', KEYS[1], KEYS[2])
+else
+	redis.call('DEL', KEYS[2])
+end
+redis.call('DEL', KEYS[3])
+return 1
+`)
+
+// dropSnapshotIfOwner drops the snapshot and releases the lock, if the lock still holds the
+// cycle's token. KEYS: snapshot, lock. ARGV: token. returns 0 when the lock isn't the cycle's.
+var dropSnapshotIfOwner = redis.NewScript(`
+if redis.call('GET', KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1], KEYS[2])
+return 1
+`)
+
+// zaddDuringSnapshot sets a score on the live leaderboard, and on the snapshot while a cycle builds one,
+// so the swap doesn't undo it. KEYS: live, snapshot, lock. ARGV: score, member.
+var zaddDuringSnapshot = redis.NewScript(`
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+if redis.call('EXISTS', KEYS[3]) == 1 then
+	redis.call('ZADD', KEYS[2], ARGV[1], ARGV[2])
+end
+return 1
+`)
+
+// zremDuringSnapshot is the removal counterpart of zaddDuringSnapshot. KEYS: live, snapshot. ARGV: member.
+var zremDuringSnapshot = redis.NewScript(`
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+return 1
+`)
+
+// BeginLeaderboardSnapshot takes the cycle lock and copies the live leaderboard into the snapshot,
+// so communities the cycle doesn't reach keep their scores. returns the token that commits or
+// aborts the snapshot, empty if another cycle holds the lock.
+func (r *RedisClient) BeginLeaderboardSnapshot(ctx context.Context) (string, error) {
+	if r.client == nil {
+		return "", ErrRedisNotConnected
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return "", err
+	}
+	acquired, err := r.client.SetNX(ctx, leaderboardSnapshotLockKey, token, leaderboardSnapshotLockTTL).Result()
+	if err != nil {
+		return "", fmt.Errorf("setnx failed: %w", err)
+	}
+	if !acquired {
+		return "", nil
+	}
+
+	// ZUNIONSTORE of a single key is a copy, and replaces any snapshot left by a crashed cycle
+	if err := r.client.ZUnionStore(ctx, leaderboardSnapshotKey, &redis.ZStore{Keys: []string{LeaderboardKey}}).Err(); err != nil {
+		_ = dropSnapshotIfOwner.Run(ctx, r.client, []string{leaderboardSnapshotKey, leaderboardSnapshotLockKey}, token).Err()
+		return "", fmt.Errorf("copying leaderboard: %w", err)
+	}
+
+	return token, nil
+}
+
+// newLockToken returns a random value identifying who holds a lock.
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// StageLeaderboardScore sets a community's score in the snapshot.
+func (r *RedisClient) StageLeaderboardScore(ctx context.Context, communityID string, momentum float64) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	if err := r.client.ZAdd(ctx, leaderboardSnapshotKey, redis.Z{Score: momentum, Member: communityID}).Err(); err != nil {
+		return fmt.Errorf("zadd snapshot failed: %w", err)
+	}
+	return nil
+}
+
+// UnstageLeaderboardScore drops a community from the snapshot. regional leaderboards aren't
+// snapshotted, so it's removed from them right away.
+func (r *RedisClient) UnstageLeaderboardScore(ctx context.Context, communityID string) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	if err := r.client.ZRem(ctx, leaderboardSnapshotKey, communityID).Err(); err != nil {
+		return fmt.Errorf("zrem snapshot failed: %w", err)
+	}
+	return r.removeFromRegionalLeaderboards(ctx, communityID)
+}
+
+// CommitLeaderboardSnapshot swaps the snapshot in as the live leaderboard and releases the lock.
+// like CommitLeaderboardRebuild, the swap is a single RENAME, and it only happens while the lock
+// still holds token: a cycle that outlived the lock's TTL gets ErrSnapshotLockLost instead of
+// swapping in over another cycle's snapshot.
+func (r *RedisClient) CommitLeaderboardSnapshot(ctx context.Context, token string) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	swapped, err := swapSnapshotIfOwner.Run(ctx, r.client, []string{leaderboardSnapshotKey, LeaderboardKey, leaderboardSnapshotLockKey}, token).Int()
+	if err != nil {
+		return fmt.Errorf("swapping snapshot failed: %w", err)
+	}
+	if swapped == 0 {
+		return ErrSnapshotLockLost
+	}
+
+	r.logger.Debug("leaderboard snapshot swapped in")
+	return nil
+}
+
+// AbortLeaderboardSnapshot drops the snapshot and releases the lock, leaving the live leaderboard as it was.
+// it does nothing once the lock no longer holds token, the snapshot is then another cycle's.
+func (r *RedisClient) AbortLeaderboardSnapshot(ctx context.Context, token string) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	if err := dropSnapshotIfOwner.Run(ctx, r.client, []string{leaderboardSnapshotKey, leaderboardSnapshotLockKey}, token).Err(); err != nil {
+		return fmt.Errorf("dropping snapshot failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// ModerationCache is an in-memory TTL cache in front of the moderation repository.
+// sanctions are checked on every authenticated event and almost nobody has one,
+// so empty results are cached too. only FindActive is cached.
+type ModerationCache struct {
+	entries map[string]*moderationEntry
+	mu      sync.RWMutex
+	ttl     time.Duration
+	repo    domain.ModerationRepository
+}
+
+type moderationEntry struct {
+	sanctions []*domain.CommunitySanction
+	expiresAt time.Time
+}
+
+// NewModerationCache creates a new sanction cache.
+func NewModerationCache(repo domain.ModerationRepository, ttl time.Duration) *ModerationCache {
+	return &ModerationCache{
+		entries: make(map[string]*moderationEntry),
+		ttl:     ttl,
+		repo:    repo,
+	}
+}
+
+func moderationCacheKey(communityID domain.CommunityID, userID domain.UserID) string {
+	return communityID.String() + ":" + userID.String()
+}
+
+// FindActive returns a user's sanctions in a community, using the cache when fresh.
+// callers should still check expiry, a cached sanction may have run out since.
+func (c *ModerationCache) FindActive(ctx context.Context, communityID domain.CommunityID, userID domain.UserID) ([]*domain.CommunitySanction, error) {
+	key := moderationCacheKey(communityID, userID)
+
+	// fast path: check cache
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.mu.RUnlock()
+		return entry.sanctions, nil
+	}
+	c.mu.RUnlock()
+
+	// slow path: query database
+	sanctions, err := c.repo.FindActive(ctx, communityID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &moderationEntry{
+		sanctions: sanctions,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return sanctions, nil
+}
+
+// Apply stores a sanction and drops the cached entry.
+func (c *ModerationCache) Apply(ctx context.Context, sanction *domain.CommunitySanction, entry *domain.ModerationLogEntry) error {
+	if err := c.repo.Apply(ctx, sanction, entry); err != nil {
+		return err
+	}
+	c.invalidate(sanction.CommunityID(), sanction.UserID())
+	return nil
+}
+
+// Lift removes a sanction and drops the cached entry.
+func (c *ModerationCache) Lift(ctx context.Context, communityID domain.CommunityID, userID domain.UserID, kind domain.SanctionKind, entry *domain.ModerationLogEntry) error {
+	if err := c.repo.Lift(ctx, communityID, userID, kind, entry); err != nil {
+		return err
+	}
+	c.invalidate(communityID, userID)
+	return nil
+}
+
+// ListActive delegates directly to the underlying repository.
+func (c *ModerationCache) ListActive(ctx context.Context, communityID domain.CommunityID) ([]*domain.CommunitySanction, error) {
+	return c.repo.ListActive(ctx, communityID)
+}
+
+// Log delegates directly to the underlying repository.
+func (c *ModerationCache) Log(ctx context.Context, entry *domain.ModerationLogEntry) error {
+	return c.repo.Log(ctx, entry)
+}
+
+// ListLog delegates directly to the underlying repository.
+func (c *ModerationCache) ListLog(ctx context.Context, communityID domain.CommunityID, limit, offset int) ([]*domain.ModerationLogEntry, error) {
+	return c.repo.ListLog(ctx, communityID, limit, offset)
+}
+
+func (c *ModerationCache) invalidate(communityID domain.CommunityID, userID domain.UserID) {
+	c.mu.Lock()
+	delete(c.entries, moderationCacheKey(communityID, userID))
+	c.mu.Unlock()
+}
+
+// Cleanup removes expired entries.
+// call this periodically to prevent memory growth.
+func (c *ModerationCache) Cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// MomentumSettingsCache is an in-memory TTL cache in front of the momentum settings repository.
+// the momentum worker looks up settings for every community on every cycle,
+// and almost none of them have overrides, so negative results are cached too.
+// writes through this cache invalidate the local entry; other instances
+// pick up the change once their entry expires.
+type MomentumSettingsCache struct {
+	entries map[string]*momentumSettingsEntry
+	mu      sync.RWMutex
+	ttl     time.Duration
+	repo    domain.CommunityMomentumSettingsRepository
+}
+
+type momentumSettingsEntry struct {
+	settings  *domain.CommunityMomentumSettings // nil means no overrides
+	expiresAt time.Time
+}
+
+// NewMomentumSettingsCache creates a new momentum settings cache.
+func NewMomentumSettingsCache(repo domain.CommunityMomentumSettingsRepository, ttl time.Duration) *MomentumSettingsCache {
+	return &MomentumSettingsCache{
+		entries: make(map[string]*momentumSettingsEntry),
+		ttl:     ttl,
+		repo:    repo,
+	}
+}
+
+// FindByCommunity returns the overrides for a community, using the cache when fresh.
+// returns domain.ErrNotFound if the community has no overrides.
+func (c *MomentumSettingsCache) FindByCommunity(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityMomentumSettings, error) {
+	idStr := communityID.String()
+
+	// fast path: check cache
+	c.mu.RLock()
+	entry, ok := c.entries[idStr]
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.mu.RUnlock()
+		if entry.settings == nil {
+			return nil, domain.ErrNotFound
+		}
+		return entry.settings, nil
+	}
+	c.mu.RUnlock()
+
+	// slow path: query database
+	settings, err := c.repo.FindByCommunity(ctx, communityID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[idStr] = &momentumSettingsEntry{
+		settings:  settings,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return settings, err
+}
+
+// Save persists overrides and drops the cached entry.
+func (c *MomentumSettingsCache) Save(ctx context.Context, settings *domain.CommunityMomentumSettings) error {
+	if err := c.repo.Save(ctx, settings); err != nil {
+		return err
+	}
+	c.Invalidate(settings.CommunityID())
+	return nil
+}
+
+// Delete removes overrides and drops the cached entry.
+func (c *MomentumSettingsCache) Delete(ctx context.Context, communityID domain.CommunityID) error {
+	if err := c.repo.Delete(ctx, communityID); err != nil {
+		return err
+	}
+	c.Invalidate(communityID)
+	return nil
+}
+
+// Invalidate removes a community from the cache.
+func (c *MomentumSettingsCache) Invalidate(communityID domain.CommunityID) {
+	c.mu.Lock()
+	delete(c.entries, communityID.String())
+	c.mu.Unlock()
+}
+
+// Cleanup removes expired entries.
+// call this periodically to prevent memory growth.
+func (c *MomentumSettingsCache) Cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
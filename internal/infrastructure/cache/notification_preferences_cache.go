@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// NotificationPreferencesCache is an in-memory TTL cache in front of the notification preferences repository.
+// the webhook worker looks up the preferences of every subscriber on every notification,
+// and most users never set any, so negative results are cached too.
+// writes through this cache invalidate the local entry; other instances
+// pick up the change once their entry expires.
+type NotificationPreferencesCache struct {
+	entries map[string]*notificationPreferencesEntry
+	mu      sync.RWMutex
+	ttl     time.Duration
+	repo    domain.NotificationPreferencesRepository
+}
+
+type notificationPreferencesEntry struct {
+	prefs     *domain.NotificationPreferences // nil means no preferences
+	expiresAt time.Time
+}
+
+// NewNotificationPreferencesCache creates a new notification preferences cache.
+func NewNotificationPreferencesCache(repo domain.NotificationPreferencesRepository, ttl time.Duration) *NotificationPreferencesCache {
+	return &NotificationPreferencesCache{
+		entries: make(map[string]*notificationPreferencesEntry),
+		ttl:     ttl,
+		repo:    repo,
+	}
+}
+
+// FindByUser returns a user's preferences, using the cache when fresh.
+// returns domain.ErrNotFound if the user has no preferences.
+func (c *NotificationPreferencesCache) FindByUser(ctx context.Context, userID domain.UserID) (*domain.NotificationPreferences, error) {
+	idStr := userID.String()
+
+	// fast path: check cache
+	c.mu.RLock()
+	entry, ok := c.entries[idStr]
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.mu.RUnlock()
+		if entry.prefs == nil {
+			return nil, domain.ErrNotFound
+		}
+		return entry.prefs, nil
+	}
+	c.mu.RUnlock()
+
+	// slow path: query database
+	prefs, err := c.repo.FindByUser(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[idStr] = &notificationPreferencesEntry{
+		prefs:     prefs,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return prefs, err
+}
+
+// Save persists preferences and drops the cached entry.
+func (c *NotificationPreferencesCache) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	if err := c.repo.Save(ctx, prefs); err != nil {
+		return err
+	}
+	c.Invalidate(prefs.UserID())
+	return nil
+}
+
+// Delete removes preferences and drops the cached entry.
+func (c *NotificationPreferencesCache) Delete(ctx context.Context, userID domain.UserID) error {
+	if err := c.repo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	c.Invalidate(userID)
+	return nil
+}
+
+// Invalidate removes a user from the cache.
+func (c *NotificationPreferencesCache) Invalidate(userID domain.UserID) {
+	c.mu.Lock()
+	delete(c.entries, userID.String())
+	c.mu.Unlock()
+}
+
+// Cleanup removes expired entries.
+// call this periodically to prevent memory growth.
+func (c *NotificationPreferencesCache) Cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
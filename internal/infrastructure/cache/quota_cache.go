@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// IngestionQuotaCache is an in-memory TTL cache in front of the quota override repository.
+// quotas are checked on every ingested event and almost no tenant has an override,
+// so negative results are cached too.
+type IngestionQuotaCache struct {
+	entries map[string]*ingestionQuotaEntry
+	mu      sync.RWMutex
+	ttl     time.Duration
+	repo    domain.IngestionQuotaRepository
+}
+
+type ingestionQuotaEntry struct {
+	quota     *domain.IngestionQuota // nil means no override
+	expiresAt time.Time
+}
+
+// NewIngestionQuotaCache creates a new quota override cache.
+func NewIngestionQuotaCache(repo domain.IngestionQuotaRepository, ttl time.Duration) *IngestionQuotaCache {
+	return &IngestionQuotaCache{
+		entries: make(map[string]*ingestionQuotaEntry),
+		ttl:     ttl,
+		repo:    repo,
+	}
+}
+
+func quotaCacheKey(scope domain.UsageScope, scopeID uuid.UUID) string {
+	return scope.String() + ":" + scopeID.String()
+}
+
+// Find returns the override for a scope, using the cache when fresh.
+// returns domain.ErrNotFound if the scope uses the default.
+func (c *IngestionQuotaCache) Find(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID) (*domain.IngestionQuota, error) {
+	key := quotaCacheKey(scope, scopeID)
+
+	// fast path: check cache
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.mu.RUnlock()
+		if entry.quota == nil {
+			return nil, domain.ErrNotFound
+		}
+		return entry.quota, nil
+	}
+	c.mu.RUnlock()
+
+	// slow path: query database
+	quota, err := c.repo.Find(ctx, scope, scopeID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &ingestionQuotaEntry{
+		quota:     quota,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return quota, err
+}
+
+// Save persists an override and drops the cached entry.
+func (c *IngestionQuotaCache) Save(ctx context.Context, quota *domain.IngestionQuota) error {
+	if err := c.repo.Save(ctx, quota); err != nil {
+		return err
+	}
+	c.invalidate(quota.Scope(), quota.ScopeID())
+	return nil
+}
+
+// Delete removes an override and drops the cached entry.
+func (c *IngestionQuotaCache) Delete(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID) error {
+	if err := c.repo.Delete(ctx, scope, scopeID); err != nil {
+		return err
+	}
+	c.invalidate(scope, scopeID)
+	return nil
+}
+
+func (c *IngestionQuotaCache) invalidate(scope domain.UsageScope, scopeID uuid.UUID) {
+	c.mu.Lock()
+	delete(c.entries, quotaCacheKey(scope, scopeID))
+	c.mu.Unlock()
+}
+
+// Cleanup removes expired entries.
+// call this periodically to prevent memory growth.
+func (c *IngestionQuotaCache) Cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
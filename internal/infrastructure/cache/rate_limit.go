@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+func rateLimitKey(key string) string {
+	return "pulse:ratelimit:" + key
+}
+
+// takeToken refills a token bucket for the time since its last request and takes a token,
+// using the redis clock so every instance agrees. the hash expires once the bucket would
+// be full again, which is the same as no bucket.
+// KEYS: bucket. ARGV: rate per second, burst. returns allowed, whole tokens left, retry after in ms.
+var takeToken = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, math.floor(tokens), retry}
+`)
+
+// RedisTokenBuckets keeps token buckets in redis, so a caller's limit is shared by every
+// instance. implements application.TokenBuckets.
+type RedisTokenBuckets struct {
+	client   *redis.Client
+	fallback application.TokenBuckets
+	logger   *logging.Logger
+
+	// lastWarned is when falling back was last logged, in unix seconds, so an outage
+	// logs once a minute rather than once per request.
+	lastWarned atomic.Int64
+}
+
+// NewRedisTokenBuckets creates token buckets backed by redis. while redis can't be reached,
+// requests are limited by fallback instead, per instance, rather than let through or refused.
+func NewRedisTokenBuckets(rc *RedisClient, fallback application.TokenBuckets, logger *logging.Logger) *RedisTokenBuckets {
+	return &RedisTokenBuckets{
+		client:   rc.Client(),
+		fallback: fallback,
+		logger:   logger.WithComponent("rate_limit"),
+	}
+}
+
+// Take takes a token from the caller's bucket.
+func (r *RedisTokenBuckets) Take(ctx context.Context, key string, policy application.TokenBucketPolicy) (application.TokenBucketStatus, error) {
+	result, err := takeToken.Run(ctx, r.client, []string{rateLimitKey(key)}, policy.Rate, policy.Burst).Int64Slice()
+	if err == nil && len(result) != 3 {
+		err = fmt.Errorf("unexpected reply %v", result)
+	}
+	if err != nil {
+		if now, last := time.Now().Unix(), r.lastWarned.Load(); now-last >= 60 && r.lastWarned.CompareAndSwap(last, now) {
+			r.logger.Warn("redis rate limit unavailable, limiting per instance",
+				"error", err.Error(),
+			)
+		}
+		return r.fallback.Take(ctx, key, policy)
+	}
+
+	return application.TokenBucketStatus{
+		Allowed:    result[0] == 1,
+		Remaining:  result[1],
+		RetryAfter: time.Duration(result[2]) * time.Millisecond,
+	}, nil
+}
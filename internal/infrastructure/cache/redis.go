@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 )
 
@@ -23,8 +25,13 @@ const (
 var (
 	ErrRedisNotConnected = errors.New("redis not connected")
 	ErrRedisEmpty        = errors.New("redis leaderboard is empty")
+	ErrTiesTooWide       = errors.New("too many leaderboard ties around the page")
 )
 
+// maxTieWindow bounds how many communities GetTopCommunitiesWithTies returns.
+// a page inside a larger tie, like the communities with no momentum at all, is read from postgres.
+const maxTieWindow = 500
+
 // RedisConfig holds configuration for Redis connection.
 type RedisConfig struct {
 	URL string
@@ -100,16 +107,17 @@ func (r *RedisClient) Client() *redis.Client {
 }
 
 // UpdateLeaderboardScore updates the momentum score for a community.
-// uses ZADD to upsert the score in the sorted set.
+// uses ZADD to upsert the score in the sorted set, and in the snapshot of a running
+// momentum cycle, see BeginLeaderboardSnapshot.
 func (r *RedisClient) UpdateLeaderboardScore(ctx context.Context, communityID string, momentum float64) error {
 	if r.client == nil {
 		return ErrRedisNotConnected
 	}
 
-	err := r.client.ZAdd(ctx, LeaderboardKey, redis.Z{
-		Score:  momentum,
-		Member: communityID,
-	}).Err()
+	err := zaddDuringSnapshot.Run(ctx, r.client,
+		[]string{LeaderboardKey, leaderboardSnapshotKey, leaderboardSnapshotLockKey},
+		momentum, communityID,
+	).Err()
 
 	if err != nil {
 		r.logger.Error("failed to update leaderboard",
@@ -184,38 +192,111 @@ func (r *RedisClient) GetTopCommunitiesWithScores(ctx context.Context, limit, of
 	return results, nil
 }
 
-// RemoveFromLeaderboard removes a community from the leaderboard.
+// GetTopCommunitiesWithTies returns the community IDs of a leaderboard page, widened to
+// whole tie groups at either end, and where the page starts within them. redis orders equal
+// scores by member, not by domain.CompareLeaderboardRank, so the caller sorts the window
+// and cuts the page out of it. returns ErrTiesTooWide past maxTieWindow communities.
+func (r *RedisClient) GetTopCommunitiesWithTies(ctx context.Context, limit, offset int64) ([]string, int64, error) {
+	page, err := r.GetTopCommunitiesWithScores(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// the tie group of the first score starts after every higher score,
+	// the one of the last score ends after every score at or above it
+	first := strconv.FormatFloat(page[0].Score, 'f', -1, 64)
+	last := strconv.FormatFloat(page[len(page)-1].Score, 'f', -1, 64)
+	pipe := r.client.Pipeline()
+	aboveCmd := pipe.ZCount(ctx, LeaderboardKey, "("+first, "+inf")
+	throughCmd := pipe.ZCount(ctx, LeaderboardKey, last, "+inf")
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, fmt.Errorf("leaderboard ties failed: %w", err)
+	}
+
+	start, end := aboveCmd.Val(), throughCmd.Val()
+	if start == offset && end == offset+int64(len(page)) {
+		members := make([]string, len(page))
+		for i, z := range page {
+			members[i], _ = z.Member.(string)
+		}
+		return members, 0, nil
+	}
+	// scores changed between the two reads
+	if start > offset || end < offset+int64(len(page)) {
+		return nil, 0, errors.New("leaderboard changed while reading ties")
+	}
+	if end-start > maxTieWindow {
+		return nil, 0, ErrTiesTooWide
+	}
+
+	members, err := r.client.ZRevRange(ctx, LeaderboardKey, start, end-1).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("zrevrange failed: %w", err)
+	}
+	return members, offset - start, nil
+}
+
+// RemoveFromLeaderboard removes a community from the global and regional leaderboards.
 // useful when a community is deactivated.
 func (r *RedisClient) RemoveFromLeaderboard(ctx context.Context, communityID string) error {
 	if r.client == nil {
 		return ErrRedisNotConnected
 	}
 
-	err := r.client.ZRem(ctx, LeaderboardKey, communityID).Err()
+	err := zremDuringSnapshot.Run(ctx, r.client, []string{LeaderboardKey, leaderboardSnapshotKey}, communityID).Err()
 	if err != nil {
 		return fmt.Errorf("zrem failed: %w", err)
 	}
 
+	if err := r.removeFromRegionalLeaderboards(ctx, communityID); err != nil {
+		return err
+	}
+
 	r.logger.Debug("removed from leaderboard", "community_id", communityID)
 	return nil
 }
 
-// GetCommunityRank returns the rank of a community (0-based, highest momentum = 0).
-// returns -1 if community is not in the leaderboard.
-func (r *RedisClient) GetCommunityRank(ctx context.Context, communityID string) (int64, error) {
+// communityTies reads a community's score, how many communities score higher, and every community
+// sharing its score, itself included, in one step. KEYS: leaderboard. ARGV: member, max tied.
+// returns nil when the community isn't on the leaderboard.
+var communityTies = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score then
+	return nil
+end
+local above = redis.call('ZCOUNT', KEYS[1], '(' .. score, '+inf')
+local tied = redis.call('ZRANGEBYSCORE', KEYS[1], score, score, 'LIMIT', 0, tonumber(ARGV[2]) + 1)
+return {above, tied}
+`)
+
+// GetCommunityTies returns how many communities score higher than the given one, and the
+// communities tied with it, itself included. like GetTopCommunitiesWithTies, redis doesn't order
+// ties by domain.CompareLeaderboardRank, so the caller sorts them to place the community.
+// returns ErrRedisEmpty if the community isn't on the leaderboard, and ErrTiesTooWide past
+// maxTieWindow tied communities.
+func (r *RedisClient) GetCommunityTies(ctx context.Context, communityID string) (int64, []string, error) {
 	if r.client == nil {
-		return -1, ErrRedisNotConnected
+		return 0, nil, ErrRedisNotConnected
 	}
 
-	rank, err := r.client.ZRevRank(ctx, LeaderboardKey, communityID).Result()
-	if err == redis.Nil {
-		return -1, nil
+	res, err := communityTies.Run(ctx, r.client, []string{LeaderboardKey}, communityID, maxTieWindow).Slice()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil, ErrRedisEmpty
 	}
 	if err != nil {
-		return -1, fmt.Errorf("zrevrank failed: %w", err)
+		return 0, nil, fmt.Errorf("community ties failed: %w", err)
 	}
 
-	return rank, nil
+	above, _ := res[0].(int64)
+	members, _ := res[1].([]any)
+	if len(members) > maxTieWindow {
+		return 0, nil, ErrTiesTooWide
+	}
+	tied := make([]string, len(members))
+	for i, m := range members {
+		tied[i], _ = m.(string)
+	}
+	return above, tied, nil
 }
 
 // LeaderboardSize returns the number of communities in the leaderboard.
@@ -232,6 +313,28 @@ func (r *RedisClient) LeaderboardSize(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// MomentumPercentile returns where a momentum score sits among the leaderboard,
+// as the share of listed communities at or below it. scoring by momentum rather than
+// rank works for dry runs and unlisted communities, which aren't on the leaderboard.
+// returns ErrRedisEmpty if the leaderboard is empty.
+func (r *RedisClient) MomentumPercentile(ctx context.Context, momentum float64) (float64, error) {
+	if r.client == nil {
+		return 0, ErrRedisNotConnected
+	}
+
+	pipe := r.client.Pipeline()
+	belowCmd := pipe.ZCount(ctx, LeaderboardKey, "-inf", strconv.FormatFloat(momentum, 'f', -1, 64))
+	sizeCmd := pipe.ZCard(ctx, LeaderboardKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("momentum percentile failed: %w", err)
+	}
+
+	if sizeCmd.Val() == 0 {
+		return 0, ErrRedisEmpty
+	}
+	return domain.MomentumPercentile(belowCmd.Val(), sizeCmd.Val()), nil
+}
+
 // HealthCheck verifies Redis is responding.
 func (r *RedisClient) HealthCheck(ctx context.Context) error {
 	if r.client == nil {
@@ -240,3 +343,31 @@ func (r *RedisClient) HealthCheck(ctx context.Context) error {
 
 	return r.client.Ping(ctx).Err()
 }
+
+// PreviousLeaderboardRank returns the rank a community's previous momentum would have among
+// the current scores, and the leaderboard's size, so spike notifications can show the move.
+// the community's own rank is placed among its ties by CommunityRepositoryWithCache.
+// returns ErrRedisEmpty if the community isn't on the leaderboard, like a private one.
+func (r *RedisClient) PreviousLeaderboardRank(ctx context.Context, communityID string, previousMomentum float64) (int64, int64, error) {
+	if r.client == nil {
+		return 0, 0, ErrRedisNotConnected
+	}
+
+	pipe := r.client.Pipeline()
+	scoreCmd := pipe.ZScore(ctx, LeaderboardKey, communityID)
+	sizeCmd := pipe.ZCard(ctx, LeaderboardKey)
+	aboveCmd := pipe.ZCount(ctx, LeaderboardKey, "("+strconv.FormatFloat(previousMomentum, 'f', -1, 64), "+inf")
+	if _, err := pipe.Exec(ctx); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, 0, ErrRedisEmpty
+		}
+		return 0, 0, fmt.Errorf("leaderboard position failed: %w", err)
+	}
+
+	// the community itself counts as above its previous momentum when it went up
+	above := aboveCmd.Val()
+	if scoreCmd.Val() > previousMomentum {
+		above--
+	}
+	return above + 1, sizeCmd.Val(), nil
+}
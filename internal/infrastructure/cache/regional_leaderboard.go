@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// leaderboardRegionsKey is the set of regions that have a leaderboard,
+// so a community can be taken off the regions it no longer has events in.
+const leaderboardRegionsKey = LeaderboardKey + ":regions"
+
+// RegionalLeaderboardKey returns the sorted set key for a region's momentum rankings.
+func RegionalLeaderboardKey(region string) string {
+	return LeaderboardKey + ":region:" + region
+}
+
+// UpdateRegionalScores sets a community's momentum in each region it has events in,
+// and removes it from every other regional leaderboard, in one round trip after the region lookup.
+func (r *RedisClient) UpdateRegionalScores(ctx context.Context, communityID string, scores map[string]float64) error {
+	if r.client == nil {
+		return ErrRedisNotConnected
+	}
+
+	if len(scores) > 0 {
+		regions := make([]any, 0, len(scores))
+		for region := range scores {
+			regions = append(regions, region)
+		}
+		if err := r.client.SAdd(ctx, leaderboardRegionsKey, regions...).Err(); err != nil {
+			return fmt.Errorf("sadd regions failed: %w", err)
+		}
+	}
+
+	regions, err := r.client.SMembers(ctx, leaderboardRegionsKey).Result()
+	if err != nil {
+		return fmt.Errorf("smembers regions failed: %w", err)
+	}
+
+	pipe := r.client.Pipeline()
+	for _, region := range regions {
+		if momentum, ok := scores[region]; ok {
+			pipe.ZAdd(ctx, RegionalLeaderboardKey(region), redis.Z{Score: momentum, Member: communityID})
+		} else {
+			pipe.ZRem(ctx, RegionalLeaderboardKey(region), communityID)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("updating regional leaderboards failed: %w", err)
+	}
+
+	r.logger.Debug("regional leaderboards updated",
+		"community_id", communityID,
+		"regions", len(scores),
+	)
+	return nil
+}
+
+// removeFromRegionalLeaderboards takes a community off every regional leaderboard.
+func (r *RedisClient) removeFromRegionalLeaderboards(ctx context.Context, communityID string) error {
+	return r.UpdateRegionalScores(ctx, communityID, nil)
+}
+
+// GetTopCommunitiesInRegion returns a region's top N communities with their regional momentum.
+// a region without events has no leaderboard, so it returns an empty slice rather than ErrRedisEmpty:
+// there is no postgres ranking to fall back to.
+func (r *RedisClient) GetTopCommunitiesInRegion(ctx context.Context, region string, limit, offset int) ([]domain.RegionalScore, error) {
+	if r.client == nil {
+		return nil, ErrRedisNotConnected
+	}
+
+	start := int64(offset)
+	stop := int64(offset + limit - 1)
+
+	results, err := r.client.ZRevRangeWithScores(ctx, RegionalLeaderboardKey(region), start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("zrevrangewithscores failed: %w", err)
+	}
+	scores := make([]domain.RegionalScore, 0, len(results))
+	for _, z := range results {
+		member, _ := z.Member.(string)
+		id, err := domain.ParseCommunityID(member)
+		if err != nil {
+			// corrupted data in redis? log and skip
+			r.logger.Warn("invalid community id in regional leaderboard",
+				"region", region,
+				"id", member,
+			)
+			continue
+		}
+		scores = append(scores, domain.RegionalScore{CommunityID: id, Momentum: z.Score})
+	}
+	return scores, nil
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+func spikeCooldownKey(communityID string) string {
+	return "pulse:spike_cooldown:" + communityID
+}
+
+// AcquireSpikeCooldown starts a community's spike cooldown unless one is running.
+// the key holds the notified momentum and expires with the cooldown, so a single
+// SET NX decides between instances which one notifies.
+func (r *RedisClient) AcquireSpikeCooldown(ctx context.Context, communityID string, momentum float64, cooldown time.Duration) (bool, error) {
+	if r.client == nil {
+		return false, ErrRedisNotConnected
+	}
+
+	acquired, err := r.client.SetNX(ctx, spikeCooldownKey(communityID), strconv.FormatFloat(momentum, 'f', -1, 64), cooldown).Result()
+	if err != nil {
+		return false, fmt.Errorf("setnx failed: %w", err)
+	}
+	return acquired, nil
+}
+
+// MemorySpikeCooldown keeps spike cooldowns in process memory.
+// used when redis is disabled; cooldowns are per instance and reset on restart.
+type MemorySpikeCooldown struct {
+	until map[string]time.Time
+	mu    sync.Mutex
+}
+
+// NewMemorySpikeCooldown creates an in-memory spike cooldown store.
+func NewMemorySpikeCooldown() *MemorySpikeCooldown {
+	return &MemorySpikeCooldown{until: make(map[string]time.Time)}
+}
+
+// AcquireSpikeCooldown starts a community's spike cooldown unless one is running.
+func (m *MemorySpikeCooldown) AcquireSpikeCooldown(_ context.Context, communityID string, _ float64, cooldown time.Duration) (bool, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Before(m.until[communityID]) {
+		return false, nil
+	}
+	m.until[communityID] = now.Add(cooldown)
+	return true, nil
+}
+
+// Cleanup drops expired cooldowns.
+// call this periodically to prevent memory growth.
+func (m *MemorySpikeCooldown) Cleanup() {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, until := range m.until {
+		if !now.Before(until) {
+			delete(m.until, id)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// usageRetention is how long daily usage counters are kept.
+// long enough to report a full month back.
+const usageRetention = 40 * 24 * time.Hour
+
+func usageKey(scope domain.UsageScope, id string, day time.Time) string {
+	return fmt.Sprintf("pulse:usage:%s:%s:%s", scope, id, day.UTC().Format("20060102"))
+}
+
+// RedisUsageCounter counts daily usage in redis, shared by every instance.
+// one key per scope and day, expired after usageRetention.
+type RedisUsageCounter struct {
+	client *redis.Client
+}
+
+// NewRedisUsageCounter creates a usage counter backed by redis.
+func NewRedisUsageCounter(rc *RedisClient) *RedisUsageCounter {
+	return &RedisUsageCounter{client: rc.Client()}
+}
+
+// AddEvents adds delta to the day's count and returns the new total.
+func (r *RedisUsageCounter) AddEvents(ctx context.Context, scope domain.UsageScope, id string, day time.Time, delta int64) (int64, error) {
+	key := usageKey(scope, id, day)
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, key, delta)
+	pipe.Expire(ctx, key, usageRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("incrby failed: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// DailyEvents returns the count for each day, 0 for days without events.
+func (r *RedisUsageCounter) DailyEvents(ctx context.Context, scope domain.UsageScope, id string, days []time.Time) ([]int64, error) {
+	if len(days) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(days))
+	for i, day := range days {
+		keys[i] = usageKey(scope, id, day)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("mget failed: %w", err)
+	}
+
+	counts := make([]int64, len(days))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing usage counter %s: %w", keys[i], err)
+		}
+		counts[i] = n
+	}
+	return counts, nil
+}
+
+// MemoryUsageCounter counts daily usage in process memory.
+// used when redis is disabled; each instance only sees its own traffic,
+// so quotas are per instance and usage resets on restart.
+type MemoryUsageCounter struct {
+	counts map[string]int64
+	mu     sync.Mutex
+}
+
+// NewMemoryUsageCounter creates an in-memory usage counter.
+func NewMemoryUsageCounter() *MemoryUsageCounter {
+	return &MemoryUsageCounter{counts: make(map[string]int64)}
+}
+
+// AddEvents adds delta to the day's count and returns the new total.
+func (m *MemoryUsageCounter) AddEvents(_ context.Context, scope domain.UsageScope, id string, day time.Time, delta int64) (int64, error) {
+	key := usageKey(scope, id, day)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[key] += delta
+	return m.counts[key], nil
+}
+
+// DailyEvents returns the count for each day, 0 for days without events.
+func (m *MemoryUsageCounter) DailyEvents(_ context.Context, scope domain.UsageScope, id string, days []time.Time) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make([]int64, len(days))
+	for i, day := range days {
+		counts[i] = m.counts[usageKey(scope, id, day)]
+	}
+	return counts, nil
+}
+
+// Cleanup drops counters older than usageRetention.
+// call this periodically to prevent memory growth.
+func (m *MemoryUsageCounter) Cleanup() {
+	cutoff := time.Now().UTC().Add(-usageRetention).Format("20060102")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.counts {
+		// keys end with the yyyymmdd day, which sorts as a string
+		if key[len(key)-8:] < cutoff {
+			delete(m.counts, key)
+		}
+	}
+}
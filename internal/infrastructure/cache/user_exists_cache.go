@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// UserExistsCache is an in-memory cache for user existence checks.
+// avoids a database round trip for every authenticated event.
+// users are never deleted, so only users that exist are cached; a user
+// created right after a miss is found on the next event.
+type UserExistsCache struct {
+	entries map[string]time.Time // user id to expiry
+	mu      sync.RWMutex
+	ttl     time.Duration
+	repo    domain.UserRepository
+}
+
+// NewUserExistsCache creates a new user existence cache.
+func NewUserExistsCache(repo domain.UserRepository, ttl time.Duration) *UserExistsCache {
+	return &UserExistsCache{
+		entries: make(map[string]time.Time),
+		ttl:     ttl,
+		repo:    repo,
+	}
+}
+
+// Exists checks if a user exists, using the cache when fresh.
+func (c *UserExistsCache) Exists(ctx context.Context, id domain.UserID) (bool, error) {
+	idStr := id.String()
+
+	// fast path: check cache
+	c.mu.RLock()
+	expiresAt, ok := c.entries[idStr]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(expiresAt) {
+		return true, nil
+	}
+
+	// slow path: query database
+	exists, err := c.repo.Exists(ctx, id)
+	if err != nil || !exists {
+		return exists, err
+	}
+
+	c.mu.Lock()
+	c.entries[idStr] = time.Now().Add(c.ttl)
+	c.mu.Unlock()
+
+	return true, nil
+}
+
+// ExistsBatch checks which of the users exist, querying the database once for the ones not cached.
+func (c *UserExistsCache) ExistsBatch(ctx context.Context, ids []domain.UserID) (map[domain.UserID]bool, error) {
+	result := make(map[domain.UserID]bool, len(ids))
+	var misses []domain.UserID
+
+	now := time.Now()
+	c.mu.RLock()
+	for _, id := range ids {
+		if expiresAt, ok := c.entries[id.String()]; ok && now.Before(expiresAt) {
+			result[id] = true
+			continue
+		}
+		misses = append(misses, id)
+	}
+	c.mu.RUnlock()
+
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	found, err := c.repo.ExistsBatch(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+	c.mu.Lock()
+	for _, id := range misses {
+		result[id] = found[id]
+		if found[id] {
+			c.entries[id.String()] = expiresAt
+		}
+	}
+	c.mu.Unlock()
+
+	return result, nil
+}
+
+// Size returns the current number of cached entries.
+func (c *UserExistsCache) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Cleanup removes expired entries.
+// call this periodically to prevent memory growth.
+func (c *UserExistsCache) Cleanup() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, expiresAt := range c.entries {
+		if now.After(expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
@@ -3,40 +3,349 @@ package config
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"github.com/joacominatel/pulse/internal/domain"
 )
 
 // Config holds all configuration for the application.
-// loaded from environment variables, no magic defaults for required fields.
+// loaded from an optional config file, then environment variables.
+// no magic defaults for required fields.
 type Config struct {
-	Database DatabaseConfig
-	Auth     AuthConfig
-	Redis    RedisConfig
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	Auth      AuthConfig      `yaml:"auth" toml:"auth"`
+	Redis     RedisConfig     `yaml:"redis" toml:"redis"`
+	Log       LogConfig       `yaml:"log" toml:"log"`
+	Momentum  MomentumConfig  `yaml:"momentum" toml:"momentum"`
+	Ingest    IngestConfig    `yaml:"ingest" toml:"ingest"`
+	Quota     QuotaConfig     `yaml:"quota" toml:"quota"`
+	Metering  MeteringConfig  `yaml:"metering" toml:"metering"`
+	Anomaly   AnomalyConfig   `yaml:"anomaly" toml:"anomaly"`
+	Summary   SummaryConfig   `yaml:"summary" toml:"summary"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Webhook   WebhookConfig   `yaml:"webhook" toml:"webhook"`
+	Import    ImportConfig    `yaml:"import" toml:"import"`
+	Kafka     KafkaConfig     `yaml:"kafka" toml:"kafka"`
+	Metrics   MetricsConfig   `yaml:"metrics" toml:"metrics"`
+	Names     NamesConfig     `yaml:"names" toml:"names"`
+	Startup   StartupConfig   `yaml:"startup" toml:"startup"`
+
+	// Standby starts the instance as a warm standby: it serves reads and health checks,
+	// but takes no writes and runs no background work until made active through the admin api.
+	Standby bool `yaml:"standby" toml:"standby"`
+}
+
+// LogConfig contains logging parameters.
+// reloadable at runtime with SIGHUP.
+type LogConfig struct {
+	// Level is the minimum log level: debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+}
+
+// MomentumConfig contains momentum worker and spike detection parameters.
+// reloadable at runtime with SIGHUP.
+type MomentumConfig struct {
+	// Interval is how often the background worker recalculates momentum.
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+
+	// StartPaused starts the background worker paused, until resumed through the admin api.
+	// only read at startup.
+	StartPaused bool `yaml:"start_paused" toml:"start_paused"`
+
+	// SpikeAbsoluteThreshold is the minimum momentum value for a spike.
+	SpikeAbsoluteThreshold float64 `yaml:"spike_absolute_threshold" toml:"spike_absolute_threshold"`
+
+	// SpikeGrowthPercentage is the minimum growth rate for a spike (0.20 = 20%).
+	SpikeGrowthPercentage float64 `yaml:"spike_growth_percentage" toml:"spike_growth_percentage"`
+
+	// DecayHalfLife is how fast the momentum of a community without new events fades
+	// between cycles, 0 to recalculate it like any other. needs a restart.
+	DecayHalfLife time.Duration `yaml:"decay_half_life" toml:"decay_half_life"`
+
+	// RetryAttempts is how many times a cycle retries a community that failed transiently,
+	// like on a database timeout, before moving on. 0 disables retries. needs a restart.
+	RetryAttempts int `yaml:"retry_attempts" toml:"retry_attempts"`
+
+	// RetryBackoff is the wait before the first retry, doubled after each. needs a restart.
+	RetryBackoff time.Duration `yaml:"retry_backoff" toml:"retry_backoff"`
+
+	// StaleAfterCycles is how many cycles in a row a community may fail before it counts
+	// as stale in the metrics, 0 never counts it. needs a restart.
+	StaleAfterCycles int `yaml:"stale_after_cycles" toml:"stale_after_cycles"`
+}
+
+// IngestConfig contains event ingestion parameters.
+type IngestConfig struct {
+	// Validation is when events are checked against the database: strict (before accepting)
+	// or deferred (by the ingestion worker, quarantining the events that fail).
+	Validation string `yaml:"validation" toml:"validation"`
+
+	// MaxClockSkew is how far ahead of the server clock an event's occurred_at may be.
+	MaxClockSkew time.Duration `yaml:"max_clock_skew" toml:"max_clock_skew"`
+
+	// MaxEventAge is how far back an event's occurred_at may be, for delayed and backfilled events.
+	MaxEventAge time.Duration `yaml:"max_event_age" toml:"max_event_age"`
+
+	// MaxQueueAge is how long an accepted event may wait in the ingestion buffer before it's
+	// quarantined as stale instead of saved, 0 for no limit. keep it above client timeouts.
+	MaxQueueAge time.Duration `yaml:"max_queue_age" toml:"max_queue_age"`
+
+	// AckTimeout is how long a request with ack=persisted waits for its event to be saved.
+	AckTimeout time.Duration `yaml:"ack_timeout" toml:"ack_timeout"`
+}
+
+// EventTimeBounds returns the bounds on client event timestamps.
+func (c IngestConfig) EventTimeBounds() domain.EventTimeBounds {
+	return domain.EventTimeBounds{
+		MaxSkew: c.MaxClockSkew,
+		MaxAge:  c.MaxEventAge,
+	}
+}
+
+// QuotaConfig contains the default daily ingestion quotas.
+// individual communities and organizations can be overridden through the admin api.
+type QuotaConfig struct {
+	// CommunityDailyEvents is the events a community can ingest per UTC day, 0 for unlimited.
+	CommunityDailyEvents int64 `yaml:"community_daily_events" toml:"community_daily_events"`
+
+	// OrganizationDailyEvents is the events all of an organization's communities can ingest per UTC day, 0 for unlimited.
+	OrganizationDailyEvents int64 `yaml:"organization_daily_events" toml:"organization_daily_events"`
+
+	// Mode is what happens to events over quota: reject (429) or degrade (stored at minimum weight).
+	Mode string `yaml:"mode" toml:"mode"`
+}
+
+// MeteringConfig contains billing metering parameters.
+// metering records are always written to the database; exports are optional.
+type MeteringConfig struct {
+	// Interval is how often usage is flushed into metering records.
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+
+	// CSVDir enables the CSV export into one file per day in this directory.
+	CSVDir string `yaml:"csv_dir" toml:"csv_dir"`
+
+	// StripeAPIKey enables the Stripe meter events export.
+	StripeAPIKey string `yaml:"stripe_api_key" toml:"stripe_api_key"`
+
+	// StripeEventPrefix is prepended to metric names to build meter event names.
+	StripeEventPrefix string `yaml:"stripe_event_prefix" toml:"stripe_event_prefix"`
+
+	// StripeCustomers is a CSV file mapping subject,subject_id to a Stripe customer id.
+	StripeCustomers string `yaml:"stripe_customers" toml:"stripe_customers"`
+}
+
+// SummaryConfig contains the community summary rollup parameters.
+type SummaryConfig struct {
+	// Interval is how often member counts and recent activity are rolled up for community
+	// listings, 0 disables the rollup and listings go without them.
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+}
+
+// RateLimitConfig contains the request budget reported to authenticated callers,
+// and the limits enforced per route group.
+type RateLimitConfig struct {
+	// Requests is how many requests a caller may make per window, reported in the
+	// X-RateLimit headers. requests over it are still served. 0 disables the headers.
+	Requests int64 `yaml:"requests" toml:"requests"`
+
+	// Window is how long a budget lasts.
+	Window time.Duration `yaml:"window" toml:"window"`
+
+	// Events limits the /events routes, refusing requests over it with 429.
+	Events RouteRateLimit `yaml:"events" toml:"events"`
+
+	// API limits every other /api/v1 route the same way.
+	API RouteRateLimit `yaml:"api" toml:"api"`
+
+	// TrustForwardedFor limits anonymous callers by the client ip in X-Forwarded-For
+	// rather than the connection's. only enable it behind a proxy that sets the header,
+	// anyone can send it otherwise.
+	TrustForwardedFor bool `yaml:"trust_forwarded_for" toml:"trust_forwarded_for"`
+}
+
+// RouteRateLimit is a token bucket per caller: Rate requests per second on average,
+// in bursts of up to Burst.
+type RouteRateLimit struct {
+	// Rate is 0 to leave the routes unlimited.
+	Rate  float64 `yaml:"rate" toml:"rate"`
+	Burst int64   `yaml:"burst" toml:"burst"`
+}
+
+// Enabled reports whether the limit is enforced.
+func (l RouteRateLimit) Enabled() bool {
+	return l.Rate > 0
+}
+
+func (l RouteRateLimit) validate(group string) error {
+	if l.Rate < 0 {
+		return fmt.Errorf("rate limit config: %s rate must not be negative", group)
+	}
+	if l.Enabled() && l.Burst < 1 {
+		return fmt.Errorf("rate limit config: %s burst must be at least 1", group)
+	}
+	return nil
+}
+
+// AnomalyConfig contains suspicious activity detection parameters.
+type AnomalyConfig struct {
+	// Interval is how often ingest rates are checked, 0 disables detection.
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+
+	// Window is the recent period whose ingest rate is checked.
+	Window time.Duration `yaml:"window" toml:"window"`
+
+	// Baseline is the period before the window the rate is compared to.
+	Baseline time.Duration `yaml:"baseline" toml:"baseline"`
+
+	// Ratio is how many times the baseline rate a window must reach to be flagged.
+	Ratio float64 `yaml:"ratio" toml:"ratio"`
+
+	// MinEvents is the fewest events in a window that can be flagged.
+	MinEvents int64 `yaml:"min_events" toml:"min_events"`
+}
+
+// WebhookConfig contains webhook delivery parameters.
+type WebhookConfig struct {
+	// DigestWindow is how often digest subscriptions receive the spikes held since the last digest.
+	DigestWindow time.Duration `yaml:"digest_window" toml:"digest_window"`
+
+	// Proxy is the egress proxy for webhook deliveries. empty falls back to HTTP_PROXY and HTTPS_PROXY.
+	Proxy string `yaml:"proxy" toml:"proxy"`
+
+	// AllowSubscriptionProxy lets each subscription set its own proxy_url. off by default.
+	AllowSubscriptionProxy bool `yaml:"allow_subscription_proxy" toml:"allow_subscription_proxy"`
+
+	// SpikeCooldown is how long after notifying a community's spike further spikes
+	// from it are suppressed. 0 notifies every spike.
+	SpikeCooldown time.Duration `yaml:"spike_cooldown" toml:"spike_cooldown"`
+}
+
+// ImportConfig contains bulk event import parameters.
+type ImportConfig struct {
+	// MaxUploadBytes is the largest file an import accepts.
+	MaxUploadBytes int64 `yaml:"max_upload_bytes" toml:"max_upload_bytes"`
+
+	// UploadTimeout is how long an upload may take, replacing the server read timeout for it.
+	UploadTimeout time.Duration `yaml:"upload_timeout" toml:"upload_timeout"`
+
+	// QueueSize is how many uploaded imports may wait for the one running. 0 disables imports.
+	QueueSize int `yaml:"queue_size" toml:"queue_size"`
+}
+
+// KafkaConfig contains the kafka source of activity events.
+// without brokers, nothing is consumed.
+type KafkaConfig struct {
+	// Brokers are the kafka bootstrap servers, host:port.
+	Brokers []string `yaml:"brokers" toml:"brokers"`
+
+	// Topic is where activity events are read from, one JSON event per message.
+	Topic string `yaml:"topic" toml:"topic"`
+
+	// GroupID is the consumer group, whose offsets say where consumption resumes.
+	GroupID string `yaml:"group_id" toml:"group_id"`
+
+	// BatchSize is how many messages are ingested before their offsets are committed.
+	BatchSize int `yaml:"batch_size" toml:"batch_size"`
+
+	// BatchWait is how long a batch waits to fill before it's ingested anyway.
+	BatchWait time.Duration `yaml:"batch_wait" toml:"batch_wait"`
+}
+
+// Enabled reports whether events are consumed from kafka.
+func (c KafkaConfig) Enabled() bool {
+	return len(c.Brokers) > 0
+}
+
+// MetricsConfig contains prometheus metrics parameters.
+type MetricsConfig struct {
+	// TopCommunities is how many of the busiest communities get their own ingest counter,
+	// the rest are counted as "other". 0 disables per-community counters.
+	TopCommunities int `yaml:"top_communities" toml:"top_communities"`
+
+	// CommunityMomentum is how many of the top public communities get their momentum
+	// exported as a gauge after every cycle. 0 disables the gauge.
+	CommunityMomentum int `yaml:"community_momentum" toml:"community_momentum"`
+}
+
+// NamesConfig contains the rules for community and organization slugs and usernames.
+type NamesConfig struct {
+	// Reserved are names nobody can claim, on top of the built-in list.
+	Reserved []string `yaml:"reserved" toml:"reserved"`
+}
+
+// ReservedNames returns the built-in blocklist with the configured names added.
+func (c NamesConfig) ReservedNames() domain.ReservedNames {
+	return domain.DefaultReservedNames().With(c.Reserved...)
+}
+
+// StartupConfig contains how long pulse waits for postgres and redis on boot.
+// in docker compose or kubernetes they're often still starting when pulse does.
+type StartupConfig struct {
+	// RetryInitial is the wait after the first failed connection attempt, doubled after each one.
+	RetryInitial time.Duration `yaml:"retry_initial" toml:"retry_initial"`
+
+	// RetryMax caps the wait between attempts.
+	RetryMax time.Duration `yaml:"retry_max" toml:"retry_max"`
+
+	// MaxWait is how long a dependency is retried before pulse gives up on it, 0 tries once.
+	// pulse exits without postgres and runs without redis.
+	MaxWait time.Duration `yaml:"max_wait" toml:"max_wait"`
+}
+
+// ServerConfig contains HTTP server parameters.
+type ServerConfig struct {
+	// Port is the port to listen on, without the leading colon.
+	Port string `yaml:"port" toml:"port"`
+
+	// ReadTimeout bounds reading a whole request, body included.
+	ReadTimeout time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+
+	// WriteTimeout bounds handling a request and writing its response.
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+
+	// IdleTimeout is how long a keep-alive connection waits for its next request.
+	IdleTimeout time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+
+	// ShutdownTimeout is how long in-flight requests get to finish on shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+
+	// DrainDelay is how long pulse keeps serving with /ready failing before it shuts down,
+	// for load balancers to stop routing to it. 0 shuts down right away.
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay"`
+
+	// ReusePort lets a new process listen on the port while the old one drains.
+	ReusePort bool `yaml:"reuse_port" toml:"reuse_port"`
 }
 
 // RedisConfig contains Redis connection parameters.
 // optional - if URL is empty, Redis caching is disabled.
 type RedisConfig struct {
-	URL string
+	URL string `yaml:"url" toml:"url"`
 }
 
 // DatabaseConfig contains database connection parameters.
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-	SSLMode  string
-	Schema   string
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`
+	SSLMode  string `yaml:"ssl_mode" toml:"ssl_mode"`
+	Schema   string `yaml:"schema" toml:"schema"`
 }
 
 // AuthConfig contains authentication configuration.
 type AuthConfig struct {
 	// JWTSecret is the supabase jwt secret for token validation
-	JWTSecret string
+	JWTSecret string `yaml:"jwt_secret" toml:"jwt_secret"`
 }
 
 // ConnectionString returns the postgres connection string.
@@ -53,79 +362,617 @@ func (c *DatabaseConfig) ConnectionString() string {
 	)
 }
 
-// Load reads configuration from environment variables.
+// ConfigPathEnv is the environment variable pointing at an optional config file.
+const ConfigPathEnv = "PULSE_CONFIG"
+
+// Load reads configuration from an optional config file and environment variables.
+// precedence, highest first: environment variables, config file, defaults.
+// path may be empty, in which case only the environment is used.
 // loads .env file if present, but doesn't fail if it's missing.
-func Load() (*Config, error) {
+func Load(path string) (*Config, error) {
+	cfg, err := LoadUnvalidated(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadUnvalidated reads config like Load but skips the required-field checks.
+// used by diagnostics that want to report every problem instead of stopping at the first.
+func LoadUnvalidated(path string) (*Config, error) {
 	// try to load .env file, ignore error if it doesn't exist
 	_ = godotenv.Load()
 
-	dbConfig, err := loadDatabaseConfig()
-	if err != nil {
-		return nil, fmt.Errorf("database config: %w", err)
+	cfg := defaults()
+
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
 	}
 
-	authConfig, err := loadAuthConfig()
-	if err != nil {
-		return nil, fmt.Errorf("auth config: %w", err)
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
 	}
 
-	redisConfig := loadRedisConfig()
+	return cfg, nil
+}
 
+// defaults returns the configuration used when neither file nor env set a value.
+func defaults() *Config {
 	return &Config{
-		Database: dbConfig,
-		Auth:     authConfig,
-		Redis:    redisConfig,
-	}, nil
+		Server: ServerConfig{
+			Port:            "8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:    "localhost",
+			Port:    "5432",
+			SSLMode: "require",
+			Schema:  "pulse",
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Momentum: MomentumConfig{
+			Interval:               5 * time.Minute,
+			SpikeAbsoluteThreshold: domain.DefaultSpikeThresholds().AbsoluteThreshold,
+			SpikeGrowthPercentage:  domain.DefaultSpikeThresholds().GrowthPercentage,
+			DecayHalfLife:          30 * time.Minute,
+			RetryAttempts:          2,
+			RetryBackoff:           500 * time.Millisecond,
+			StaleAfterCycles:       3,
+		},
+		Ingest: IngestConfig{
+			Validation:   string(domain.ValidationStrict),
+			MaxClockSkew: domain.DefaultEventTimeBounds().MaxSkew,
+			MaxEventAge:  domain.DefaultEventTimeBounds().MaxAge,
+			MaxQueueAge:  30 * time.Second,
+			AckTimeout:   5 * time.Second,
+		},
+		Quota: QuotaConfig{
+			Mode: string(domain.QuotaModeReject),
+		},
+		Metering: MeteringConfig{
+			Interval:          time.Hour,
+			StripeEventPrefix: "pulse_",
+		},
+		Anomaly: AnomalyConfig{
+			Interval:  5 * time.Minute,
+			Window:    15 * time.Minute,
+			Baseline:  24 * time.Hour,
+			Ratio:     domain.DefaultAnomalyThresholds().Ratio,
+			MinEvents: domain.DefaultAnomalyThresholds().MinEvents,
+		},
+		Summary: SummaryConfig{
+			Interval: time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			Requests: 600,
+			Window:   time.Minute,
+			Events:   RouteRateLimit{Rate: 100, Burst: 200},
+		},
+		Webhook: WebhookConfig{
+			DigestWindow:  15 * time.Minute,
+			SpikeCooldown: 30 * time.Minute,
+		},
+		Import: ImportConfig{
+			MaxUploadBytes: 1 << 30,
+			UploadTimeout:  10 * time.Minute,
+			QueueSize:      4,
+		},
+		Kafka: KafkaConfig{
+			GroupID:   "pulse",
+			BatchSize: 500,
+			BatchWait: time.Second,
+		},
+		Metrics: MetricsConfig{
+			TopCommunities: 20,
+		},
+		Startup: StartupConfig{
+			RetryInitial: time.Second,
+			RetryMax:     15 * time.Second,
+			MaxWait:      time.Minute,
+		},
+	}
 }
 
-func loadAuthConfig() (AuthConfig, error) {
-	config := AuthConfig{
-		JWTSecret: os.Getenv("SUPABASE_JWT_SECRET"),
+// applyEnv overrides config values with any environment variables that are set.
+// returns an error if a typed variable can't be parsed.
+func applyEnv(cfg *Config) error {
+	overrideString(&cfg.Server.Port, "PORT")
+
+	overrideString(&cfg.Database.Host, "DB_HOST")
+	overrideString(&cfg.Database.Port, "DB_PORT")
+	overrideString(&cfg.Database.User, "DB_USER")
+	overrideString(&cfg.Database.Password, "DB_PASSWORD")
+	overrideString(&cfg.Database.Name, "DB_NAME")
+	overrideString(&cfg.Database.SSLMode, "DB_SSL_MODE")
+	overrideString(&cfg.Database.Schema, "DB_SCHEMA")
+
+	overrideString(&cfg.Auth.JWTSecret, "SUPABASE_JWT_SECRET")
+
+	// redis is optional - if URL is empty, redis caching is disabled.
+	overrideString(&cfg.Redis.URL, "REDIS_URL")
+
+	overrideString(&cfg.Log.Level, "PULSE_LOG_LEVEL")
+	overrideString(&cfg.Ingest.Validation, "PULSE_INGEST_VALIDATION")
+	overrideString(&cfg.Quota.Mode, "PULSE_QUOTA_MODE")
+
+	overrideString(&cfg.Webhook.Proxy, "PULSE_WEBHOOK_PROXY")
+	overrideList(&cfg.Names.Reserved, "PULSE_RESERVED_NAMES")
+	overrideList(&cfg.Kafka.Brokers, "PULSE_KAFKA_BROKERS")
+	overrideString(&cfg.Kafka.Topic, "PULSE_KAFKA_TOPIC")
+	overrideString(&cfg.Kafka.GroupID, "PULSE_KAFKA_GROUP_ID")
+
+	overrideString(&cfg.Metering.CSVDir, "PULSE_METERING_CSV_DIR")
+	overrideString(&cfg.Metering.StripeAPIKey, "PULSE_METERING_STRIPE_API_KEY")
+	overrideString(&cfg.Metering.StripeEventPrefix, "PULSE_METERING_STRIPE_EVENT_PREFIX")
+	overrideString(&cfg.Metering.StripeCustomers, "PULSE_METERING_STRIPE_CUSTOMERS")
+
+	return errors.Join(
+		overrideDuration(&cfg.Server.ReadTimeout, "PULSE_SERVER_READ_TIMEOUT"),
+		overrideDuration(&cfg.Server.WriteTimeout, "PULSE_SERVER_WRITE_TIMEOUT"),
+		overrideDuration(&cfg.Server.IdleTimeout, "PULSE_SERVER_IDLE_TIMEOUT"),
+		overrideDuration(&cfg.Server.ShutdownTimeout, "PULSE_SERVER_SHUTDOWN_TIMEOUT"),
+		overrideDuration(&cfg.Server.DrainDelay, "PULSE_SERVER_DRAIN_DELAY"),
+		overrideBool(&cfg.Server.ReusePort, "PULSE_SERVER_REUSE_PORT"),
+		overrideBool(&cfg.Standby, "PULSE_STANDBY"),
+		overrideDuration(&cfg.Momentum.Interval, "PULSE_MOMENTUM_INTERVAL"),
+		overrideBool(&cfg.Momentum.StartPaused, "PULSE_MOMENTUM_START_PAUSED"),
+		overrideDuration(&cfg.Ingest.MaxClockSkew, "PULSE_INGEST_MAX_CLOCK_SKEW"),
+		overrideDuration(&cfg.Ingest.MaxEventAge, "PULSE_INGEST_MAX_EVENT_AGE"),
+		overrideDuration(&cfg.Ingest.MaxQueueAge, "PULSE_INGEST_MAX_QUEUE_AGE"),
+		overrideDuration(&cfg.Ingest.AckTimeout, "PULSE_INGEST_ACK_TIMEOUT"),
+		overrideFloat(&cfg.Momentum.SpikeAbsoluteThreshold, "PULSE_SPIKE_ABSOLUTE_THRESHOLD"),
+		overrideFloat(&cfg.Momentum.SpikeGrowthPercentage, "PULSE_SPIKE_GROWTH_PERCENTAGE"),
+		overrideDuration(&cfg.Momentum.DecayHalfLife, "PULSE_MOMENTUM_DECAY_HALF_LIFE"),
+		overrideInt(&cfg.Momentum.RetryAttempts, "PULSE_MOMENTUM_RETRY_ATTEMPTS"),
+		overrideDuration(&cfg.Momentum.RetryBackoff, "PULSE_MOMENTUM_RETRY_BACKOFF"),
+		overrideInt(&cfg.Momentum.StaleAfterCycles, "PULSE_MOMENTUM_STALE_AFTER_CYCLES"),
+		overrideInt64(&cfg.Quota.CommunityDailyEvents, "PULSE_QUOTA_COMMUNITY_DAILY_EVENTS"),
+		overrideInt64(&cfg.Quota.OrganizationDailyEvents, "PULSE_QUOTA_ORGANIZATION_DAILY_EVENTS"),
+		overrideDuration(&cfg.Metering.Interval, "PULSE_METERING_INTERVAL"),
+		overrideDuration(&cfg.Anomaly.Interval, "PULSE_ANOMALY_INTERVAL"),
+		overrideDuration(&cfg.Anomaly.Window, "PULSE_ANOMALY_WINDOW"),
+		overrideDuration(&cfg.Anomaly.Baseline, "PULSE_ANOMALY_BASELINE"),
+		overrideFloat(&cfg.Anomaly.Ratio, "PULSE_ANOMALY_RATIO"),
+		overrideInt64(&cfg.Anomaly.MinEvents, "PULSE_ANOMALY_MIN_EVENTS"),
+		overrideDuration(&cfg.Summary.Interval, "PULSE_SUMMARY_INTERVAL"),
+		overrideInt64(&cfg.RateLimit.Requests, "PULSE_RATE_LIMIT_REQUESTS"),
+		overrideDuration(&cfg.RateLimit.Window, "PULSE_RATE_LIMIT_WINDOW"),
+		overrideFloat(&cfg.RateLimit.Events.Rate, "PULSE_RATE_LIMIT_EVENTS_RATE"),
+		overrideInt64(&cfg.RateLimit.Events.Burst, "PULSE_RATE_LIMIT_EVENTS_BURST"),
+		overrideFloat(&cfg.RateLimit.API.Rate, "PULSE_RATE_LIMIT_API_RATE"),
+		overrideInt64(&cfg.RateLimit.API.Burst, "PULSE_RATE_LIMIT_API_BURST"),
+		overrideBool(&cfg.RateLimit.TrustForwardedFor, "PULSE_RATE_LIMIT_TRUST_FORWARDED_FOR"),
+		overrideDuration(&cfg.Webhook.DigestWindow, "PULSE_WEBHOOK_DIGEST_WINDOW"),
+		overrideBool(&cfg.Webhook.AllowSubscriptionProxy, "PULSE_WEBHOOK_ALLOW_SUBSCRIPTION_PROXY"),
+		overrideDuration(&cfg.Webhook.SpikeCooldown, "PULSE_WEBHOOK_SPIKE_COOLDOWN"),
+		overrideInt64(&cfg.Import.MaxUploadBytes, "PULSE_IMPORT_MAX_UPLOAD_BYTES"),
+		overrideDuration(&cfg.Import.UploadTimeout, "PULSE_IMPORT_UPLOAD_TIMEOUT"),
+		overrideInt(&cfg.Import.QueueSize, "PULSE_IMPORT_QUEUE_SIZE"),
+		overrideInt(&cfg.Kafka.BatchSize, "PULSE_KAFKA_BATCH_SIZE"),
+		overrideDuration(&cfg.Kafka.BatchWait, "PULSE_KAFKA_BATCH_WAIT"),
+		overrideInt(&cfg.Metrics.TopCommunities, "PULSE_METRICS_TOP_COMMUNITIES"),
+		overrideInt(&cfg.Metrics.CommunityMomentum, "PULSE_METRICS_COMMUNITY_MOMENTUM"),
+		overrideDuration(&cfg.Startup.RetryInitial, "PULSE_STARTUP_RETRY_INITIAL"),
+		overrideDuration(&cfg.Startup.RetryMax, "PULSE_STARTUP_RETRY_MAX"),
+		overrideDuration(&cfg.Startup.MaxWait, "PULSE_STARTUP_MAX_WAIT"),
+	)
+}
+
+// Validate checks that all required fields are set.
+func (c *Config) Validate() error {
+	if c.Database.User == "" {
+		return errors.New("database config: DB_USER is required")
+	}
+	if c.Database.Password == "" {
+		return errors.New("database config: DB_PASSWORD is required")
+	}
+	if c.Database.Name == "" {
+		return errors.New("database config: DB_NAME is required")
+	}
+	if c.Auth.JWTSecret == "" {
+		return errors.New("auth config: SUPABASE_JWT_SECRET is required")
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		return errors.New("server config: timeouts must not be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		return errors.New("server config: shutdown timeout must be positive")
+	}
+	if c.Server.DrainDelay < 0 {
+		return errors.New("server config: drain delay must not be negative")
+	}
+	if _, err := domain.ParseValidationMode(c.Ingest.Validation); err != nil {
+		return fmt.Errorf("ingest config: invalid validation mode %q", c.Ingest.Validation)
+	}
+	if c.Ingest.MaxClockSkew < 0 || c.Ingest.MaxEventAge < 0 || c.Ingest.MaxQueueAge < 0 {
+		return errors.New("ingest config: max clock skew, max event age and max queue age must not be negative")
+	}
+	if c.Ingest.AckTimeout <= 0 {
+		return errors.New("ingest config: ack timeout must be positive")
+	}
+	if _, err := domain.ParseQuotaMode(c.Quota.Mode); err != nil {
+		return fmt.Errorf("quota config: invalid mode %q", c.Quota.Mode)
+	}
+	if c.Quota.CommunityDailyEvents < 0 || c.Quota.OrganizationDailyEvents < 0 {
+		return errors.New("quota config: daily events must not be negative")
+	}
+	if c.Metering.Interval <= 0 {
+		return errors.New("metering config: interval must be positive")
+	}
+	if c.Metering.StripeAPIKey != "" && c.Metering.StripeCustomers == "" {
+		return errors.New("metering config: PULSE_METERING_STRIPE_CUSTOMERS is required with a stripe api key")
+	}
+	if err := c.Anomaly.validate(); err != nil {
+		return err
+	}
+	if c.Summary.Interval < 0 {
+		return errors.New("summary config: interval must not be negative")
+	}
+	if c.RateLimit.Requests < 0 {
+		return errors.New("rate limit config: requests must not be negative")
+	}
+	if c.RateLimit.Requests > 0 && c.RateLimit.Window <= 0 {
+		return errors.New("rate limit config: window must be positive")
+	}
+	if err := c.RateLimit.Events.validate("events"); err != nil {
+		return err
+	}
+	if err := c.RateLimit.API.validate("api"); err != nil {
+		return err
+	}
+	if c.Webhook.DigestWindow <= 0 {
+		return errors.New("webhook config: digest window must be positive")
+	}
+	if c.Webhook.SpikeCooldown < 0 {
+		return errors.New("webhook config: spike cooldown must not be negative")
+	}
+	if c.Webhook.Proxy != "" {
+		if _, err := domain.ParseWebhookProxy(c.Webhook.Proxy); err != nil {
+			return fmt.Errorf("webhook config: %w", err)
+		}
+	}
+	if c.Import.QueueSize < 0 {
+		return errors.New("import config: queue size must not be negative")
+	}
+	if c.Import.QueueSize > 0 && (c.Import.MaxUploadBytes <= 0 || c.Import.UploadTimeout <= 0) {
+		return errors.New("import config: max upload bytes and upload timeout must be positive")
+	}
+	if c.Kafka.Enabled() {
+		if c.Kafka.Topic == "" || c.Kafka.GroupID == "" {
+			return errors.New("kafka config: PULSE_KAFKA_TOPIC and PULSE_KAFKA_GROUP_ID are required with brokers")
+		}
+		if c.Kafka.BatchSize <= 0 || c.Kafka.BatchWait <= 0 {
+			return errors.New("kafka config: batch size and batch wait must be positive")
+		}
+	}
+	if c.Metrics.TopCommunities < 0 {
+		return errors.New("metrics config: top communities must not be negative")
+	}
+	if c.Metrics.CommunityMomentum < 0 || c.Metrics.CommunityMomentum > 1000 {
+		return errors.New("metrics config: community momentum must be between 0 and 1000")
+	}
+	if c.Startup.MaxWait < 0 {
+		return errors.New("startup config: max wait must not be negative")
+	}
+	if c.Startup.MaxWait > 0 && (c.Startup.RetryInitial <= 0 || c.Startup.RetryMax < c.Startup.RetryInitial) {
+		return errors.New("startup config: retry initial must be positive and retry max at least retry initial")
+	}
+	return c.validateRuntime()
+}
+
+// validateRuntime checks the settings that can be reloaded with SIGHUP.
+func (c *Config) validateRuntime() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		return fmt.Errorf("log config: invalid level %q", c.Log.Level)
+	}
+	if c.Momentum.Interval <= 0 {
+		return errors.New("momentum config: interval must be positive")
+	}
+	if c.Momentum.SpikeAbsoluteThreshold < 0 {
+		return errors.New("momentum config: spike absolute threshold must not be negative")
+	}
+	if c.Momentum.SpikeGrowthPercentage < 0 {
+		return errors.New("momentum config: spike growth percentage must not be negative")
+	}
+	if c.Momentum.DecayHalfLife < 0 {
+		return errors.New("momentum config: decay half life must not be negative")
+	}
+	if c.Momentum.RetryAttempts < 0 {
+		return errors.New("momentum config: retry attempts must not be negative")
+	}
+	if c.Momentum.RetryBackoff < 0 {
+		return errors.New("momentum config: retry backoff must not be negative")
+	}
+	if c.Momentum.StaleAfterCycles < 0 {
+		return errors.New("momentum config: stale after cycles must not be negative")
+	}
+	return nil
+}
+
+// validate checks the anomaly settings, which only matter when detection is enabled.
+func (c *AnomalyConfig) validate() error {
+	if c.Interval < 0 {
+		return errors.New("anomaly config: interval must not be negative")
+	}
+	if c.Interval == 0 {
+		return nil
+	}
+	if c.Window <= 0 {
+		return errors.New("anomaly config: window must be positive")
+	}
+	if c.Baseline < c.Window {
+		return errors.New("anomaly config: baseline must be at least the window")
 	}
+	if c.Ratio <= 1 {
+		return errors.New("anomaly config: ratio must be greater than 1")
+	}
+	if c.MinEvents < 0 {
+		return errors.New("anomaly config: min events must not be negative")
+	}
+	return nil
+}
 
-	if config.JWTSecret == "" {
-		return config, errors.New("SUPABASE_JWT_SECRET is required")
+// Thresholds returns the configured anomaly thresholds as a domain value.
+func (c *AnomalyConfig) Thresholds() domain.AnomalyThresholds {
+	return domain.AnomalyThresholds{
+		Ratio:     c.Ratio,
+		MinEvents: c.MinEvents,
 	}
+}
 
-	return config, nil
+// SpikeThresholds returns the configured spike thresholds as a domain value.
+func (c *MomentumConfig) SpikeThresholds() domain.MomentumSpikeThresholds {
+	return domain.MomentumSpikeThresholds{
+		AbsoluteThreshold: c.SpikeAbsoluteThreshold,
+		GrowthPercentage:  c.SpikeGrowthPercentage,
+	}
 }
 
-func loadDatabaseConfig() (DatabaseConfig, error) {
-	config := DatabaseConfig{
-		Host:     getEnvOrDefault("DB_HOST", "localhost"),
-		Port:     getEnvOrDefault("DB_PORT", "5432"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Name:     os.Getenv("DB_NAME"),
-		SSLMode:  getEnvOrDefault("DB_SSL_MODE", "require"),
-		Schema:   getEnvOrDefault("DB_SCHEMA", "pulse"),
+// overrideString replaces target with the env value if the variable is set.
+func overrideString(target *string, key string) {
+	if value := os.Getenv(key); value != "" {
+		*target = value
 	}
+}
 
-	// required fields must be set
-	if config.User == "" {
-		return config, errors.New("DB_USER is required")
+// overrideList replaces target with the comma-separated env value if the variable is set.
+func overrideList(target *[]string, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
 	}
-	if config.Password == "" {
-		return config, errors.New("DB_PASSWORD is required")
+	*target = list
+}
+
+// overrideDuration replaces target with the parsed env value if the variable is set.
+func overrideDuration(target *time.Duration, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
 	}
-	if config.Name == "" {
-		return config, errors.New("DB_NAME is required")
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid duration %q", key, value)
 	}
+	*target = parsed
+	return nil
+}
 
-	return config, nil
+// overrideFloat replaces target with the parsed env value if the variable is set.
+func overrideFloat(target *float64, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%s: invalid number %q", key, value)
+	}
+	*target = parsed
+	return nil
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// overrideBool replaces target with the parsed env value if the variable is set.
+func overrideBool(target *bool, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
 	}
-	return defaultValue
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid boolean %q", key, value)
+	}
+	*target = parsed
+	return nil
 }
 
-// loadRedisConfig loads optional Redis configuration.
-// not required - if URL is empty, redis caching is disabled.
-func loadRedisConfig() RedisConfig {
-	return RedisConfig{
-		URL: os.Getenv("REDIS_URL"),
+// overrideInt replaces target with the parsed env value if the variable is set.
+func overrideInt(target *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid integer %q", key, value)
+	}
+	*target = parsed
+	return nil
+}
+
+// overrideInt64 replaces target with the parsed env value if the variable is set.
+func overrideInt64(target *int64, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s: invalid integer %q", key, value)
+	}
+	*target = parsed
+	return nil
+}
+
+// redacted replaces secret values in log output.
+const redacted = "[REDACTED]"
+
+// LogValue implements slog.LogValuer so the config can be logged safely.
+// secrets are never written to logs, only whether they are set.
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("server_port", c.Server.Port),
+		slog.Bool("standby", c.Standby),
+		slog.Any("database", c.Database),
+		slog.Any("auth", c.Auth),
+		slog.Any("redis", c.Redis),
+		slog.String("log_level", c.Log.Level),
+		slog.Group("momentum",
+			slog.String("interval", c.Momentum.Interval.String()),
+			slog.Bool("start_paused", c.Momentum.StartPaused),
+			slog.Float64("spike_absolute_threshold", c.Momentum.SpikeAbsoluteThreshold),
+			slog.Float64("spike_growth_percentage", c.Momentum.SpikeGrowthPercentage),
+			slog.String("decay_half_life", c.Momentum.DecayHalfLife.String()),
+			slog.Int("retry_attempts", c.Momentum.RetryAttempts),
+			slog.String("retry_backoff", c.Momentum.RetryBackoff.String()),
+			slog.Int("stale_after_cycles", c.Momentum.StaleAfterCycles),
+		),
+		slog.Group("ingest",
+			slog.String("validation", c.Ingest.Validation),
+			slog.String("max_clock_skew", c.Ingest.MaxClockSkew.String()),
+			slog.String("max_event_age", c.Ingest.MaxEventAge.String()),
+			slog.String("max_queue_age", c.Ingest.MaxQueueAge.String()),
+			slog.String("ack_timeout", c.Ingest.AckTimeout.String()),
+		),
+		slog.Group("quota",
+			slog.Int64("community_daily_events", c.Quota.CommunityDailyEvents),
+			slog.Int64("organization_daily_events", c.Quota.OrganizationDailyEvents),
+			slog.String("mode", c.Quota.Mode),
+		),
+		slog.Any("metering", c.Metering),
+		slog.Group("anomaly",
+			slog.String("interval", c.Anomaly.Interval.String()),
+			slog.String("window", c.Anomaly.Window.String()),
+			slog.String("baseline", c.Anomaly.Baseline.String()),
+			slog.Float64("ratio", c.Anomaly.Ratio),
+			slog.Int64("min_events", c.Anomaly.MinEvents),
+		),
+		slog.Group("summary",
+			slog.String("interval", c.Summary.Interval.String()),
+		),
+		slog.Group("rate_limit",
+			slog.Int64("requests", c.RateLimit.Requests),
+			slog.String("window", c.RateLimit.Window.String()),
+			slog.Float64("events_rate", c.RateLimit.Events.Rate),
+			slog.Int64("events_burst", c.RateLimit.Events.Burst),
+			slog.Float64("api_rate", c.RateLimit.API.Rate),
+			slog.Int64("api_burst", c.RateLimit.API.Burst),
+			slog.Bool("trust_forwarded_for", c.RateLimit.TrustForwardedFor),
+		),
+		slog.Group("webhook",
+			slog.String("digest_window", c.Webhook.DigestWindow.String()),
+			slog.String("proxy", redactURL(c.Webhook.Proxy)),
+			slog.Bool("allow_subscription_proxy", c.Webhook.AllowSubscriptionProxy),
+			slog.String("spike_cooldown", c.Webhook.SpikeCooldown.String()),
+		),
+		slog.Group("import",
+			slog.Int64("max_upload_bytes", c.Import.MaxUploadBytes),
+			slog.String("upload_timeout", c.Import.UploadTimeout.String()),
+			slog.Int("queue_size", c.Import.QueueSize),
+		),
+		slog.Group("kafka",
+			slog.Any("brokers", c.Kafka.Brokers),
+			slog.String("topic", c.Kafka.Topic),
+			slog.String("group_id", c.Kafka.GroupID),
+			slog.Int("batch_size", c.Kafka.BatchSize),
+			slog.String("batch_wait", c.Kafka.BatchWait.String()),
+		),
+		slog.Group("metrics",
+			slog.Int("top_communities", c.Metrics.TopCommunities),
+			slog.Int("community_momentum", c.Metrics.CommunityMomentum),
+		),
+		slog.Group("names",
+			slog.Int("reserved", len(c.Names.Reserved)),
+		),
+		slog.Group("startup",
+			slog.String("retry_initial", c.Startup.RetryInitial.String()),
+			slog.String("retry_max", c.Startup.RetryMax.String()),
+			slog.String("max_wait", c.Startup.MaxWait.String()),
+		),
+	)
+}
+
+// LogValue implements slog.LogValuer with the password redacted.
+func (c DatabaseConfig) LogValue() slog.Value {
+	password := ""
+	if c.Password != "" {
+		password = redacted
+	}
+	return slog.GroupValue(
+		slog.String("host", c.Host),
+		slog.String("port", c.Port),
+		slog.String("user", c.User),
+		slog.String("password", password),
+		slog.String("name", c.Name),
+		slog.String("ssl_mode", c.SSLMode),
+		slog.String("schema", c.Schema),
+	)
+}
+
+// LogValue implements slog.LogValuer, reporting only whether the stripe key is present.
+func (c MeteringConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("interval", c.Interval.String()),
+		slog.String("csv_dir", c.CSVDir),
+		slog.Bool("stripe_enabled", c.StripeAPIKey != ""),
+		slog.String("stripe_event_prefix", c.StripeEventPrefix),
+		slog.String("stripe_customers", c.StripeCustomers),
+	)
+}
+
+// LogValue implements slog.LogValuer, reporting only whether the secret is present.
+func (c AuthConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("jwt_secret_set", c.JWTSecret != ""),
+	)
+}
+
+// LogValue implements slog.LogValuer with any credentials in the url redacted.
+func (c RedisConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("enabled", c.URL != ""),
+		slog.String("url", redactURL(c.URL)),
+	)
+}
+
+// redactURL masks the password component of a connection url.
+// unparseable urls are fully redacted rather than risk leaking credentials.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
 	}
+	return u.Redacted()
 }
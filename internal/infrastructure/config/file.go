@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedConfigFormat is returned for config files that aren't YAML or TOML.
+var ErrUnsupportedConfigFormat = errors.New("unsupported config file format, use .yaml, .yml or .toml")
+
+// loadFile decodes a YAML or TOML config file on top of cfg.
+// unknown keys are rejected so typos don't silently fall back to defaults.
+func loadFile(path string, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return loadYAML(path, cfg)
+	case ".toml":
+		return loadTOML(path, cfg)
+	default:
+		return ErrUnsupportedConfigFormat
+	}
+}
+
+func loadYAML(path string, cfg *Config) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("opening: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil {
+		// an empty file is valid, it just doesn't override anything
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("decoding yaml: %w", err)
+	}
+	return nil
+}
+
+func loadTOML(path string, cfg *Config) error {
+	meta, err := toml.DecodeFile(filepath.Clean(path), cfg)
+	if err != nil {
+		return fmt.Errorf("decoding toml: %w", err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
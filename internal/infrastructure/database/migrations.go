@@ -40,12 +40,13 @@ func NewMigrator(conn *Connection, logger *logging.Logger) *Migrator {
 }
 
 // Run applies all pending migrations.
-func (m *Migrator) Run(ctx context.Context) error {
+// returns the number of migrations applied by this run.
+func (m *Migrator) Run(ctx context.Context) (int, error) {
 	m.logger.MigrationStarted()
 
 	migrations, err := m.loadMigrations()
 	if err != nil {
-		return fmt.Errorf("loading migrations: %w", err)
+		return 0, fmt.Errorf("loading migrations: %w", err)
 	}
 
 	appliedCount := 0
@@ -53,7 +54,7 @@ func (m *Migrator) Run(ctx context.Context) error {
 		applied, err := m.applyMigration(ctx, migration)
 		if err != nil {
 			m.logger.MigrationFailed(migration.Version, migration.Description, err)
-			return fmt.Errorf("applying migration %s: %w", migration.Version, err)
+			return appliedCount, fmt.Errorf("applying migration %s: %w", migration.Version, err)
 		}
 		if applied {
 			appliedCount++
@@ -61,7 +62,12 @@ func (m *Migrator) Run(ctx context.Context) error {
 	}
 
 	m.logger.MigrationCompleted(appliedCount)
-	return nil
+	return appliedCount, nil
+}
+
+// EmbeddedMigrations returns every migration compiled into the binary, ordered by version.
+func (m *Migrator) EmbeddedMigrations() ([]Migration, error) {
+	return m.loadMigrations()
 }
 
 // loadMigrations reads all migration files from the embedded filesystem.
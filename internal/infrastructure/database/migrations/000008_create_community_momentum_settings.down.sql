@@ -0,0 +1,4 @@
+-- migration: 000008_create_community_momentum_settings.down.sql
+-- drops the community_momentum_settings table
+
+DROP TABLE IF EXISTS pulse.community_momentum_settings;
@@ -0,0 +1,18 @@
+-- migration: 000008_create_community_momentum_settings.up.sql
+-- creates the community_momentum_settings table for per-community momentum overrides
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.community_momentum_settings (
+    community_id UUID PRIMARY KEY REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    time_window_seconds INTEGER,
+    decay_factor DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    -- same bounds as the domain validation: 5m to 7d, (0, 1]
+    CONSTRAINT valid_time_window CHECK (time_window_seconds IS NULL OR time_window_seconds BETWEEN 300 AND 604800),
+    CONSTRAINT valid_decay_factor CHECK (decay_factor IS NULL OR (decay_factor > 0 AND decay_factor <= 1))
+);
+
+COMMENT ON TABLE pulse.community_momentum_settings IS 'optional per-community overrides of the global momentum config';
+COMMENT ON COLUMN pulse.community_momentum_settings.time_window_seconds IS 'sliding window override, null uses the global default';
+COMMENT ON COLUMN pulse.community_momentum_settings.decay_factor IS 'decay factor override, null uses the global default';
@@ -0,0 +1,4 @@
+-- migration: 000009_create_api_keys.down.sql
+-- drops the api_keys table
+
+DROP TABLE IF EXISTS pulse.api_keys;
@@ -0,0 +1,21 @@
+-- migration: 000009_create_api_keys.up.sql
+-- creates the api_keys table for long-lived service credentials
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    key_hint CHAR(4) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE pulse.api_keys IS 'api keys acting on behalf of a user, created with pulsectl';
+COMMENT ON COLUMN pulse.api_keys.key_hash IS 'hex sha256 of the plaintext key, the plaintext is never stored';
+COMMENT ON COLUMN pulse.api_keys.key_hint IS 'last 4 characters of the key, for identifying it in listings';
+
+-- index for listing a user's keys
+CREATE INDEX IF NOT EXISTS idx_api_keys_user
+    ON pulse.api_keys(user_id);
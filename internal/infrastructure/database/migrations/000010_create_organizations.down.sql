@@ -0,0 +1,10 @@
+-- migration: 000010_create_organizations.down.sql
+-- drops organizations and the columns pointing at them
+
+ALTER TABLE pulse.api_keys DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS pulse.idx_communities_organization_momentum;
+ALTER TABLE pulse.communities DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS pulse.organization_members;
+DROP TABLE IF EXISTS pulse.organizations;
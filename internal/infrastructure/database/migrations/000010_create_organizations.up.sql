@@ -0,0 +1,45 @@
+-- migration: 000010_create_organizations.up.sql
+-- creates organizations and their members, and lets communities and api keys belong to one
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_by UUID NOT NULL REFERENCES pulse.users_profile(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.organizations IS 'workspaces owning many communities with shared administration';
+
+CREATE TABLE IF NOT EXISTS pulse.organization_members (
+    organization_id UUID NOT NULL REFERENCES pulse.organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT valid_organization_role CHECK (role IN ('owner', 'admin', 'member'))
+);
+
+COMMENT ON COLUMN pulse.organization_members.role IS 'owner, admin (manages members, communities, keys) or member (read only)';
+
+-- index for listing a user's organizations
+CREATE INDEX IF NOT EXISTS idx_organization_members_user
+    ON pulse.organization_members(user_id);
+
+ALTER TABLE pulse.communities
+    ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES pulse.organizations(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN pulse.communities.organization_id IS 'owning organization, null for standalone communities';
+
+-- index for organization leaderboards
+CREATE INDEX IF NOT EXISTS idx_communities_organization_momentum
+    ON pulse.communities(organization_id, current_momentum DESC)
+    WHERE is_active = true AND organization_id IS NOT NULL;
+
+ALTER TABLE pulse.api_keys
+    ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES pulse.organizations(id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN pulse.api_keys.organization_id IS 'set for organization keys, which only reach that organization''s communities';
@@ -0,0 +1,4 @@
+-- migration: 000011_create_ingestion_quotas.down.sql
+-- drops the ingestion_quotas table
+
+DROP TABLE IF EXISTS pulse.ingestion_quotas;
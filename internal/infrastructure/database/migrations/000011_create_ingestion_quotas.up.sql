@@ -0,0 +1,18 @@
+-- migration: 000011_create_ingestion_quotas.up.sql
+-- creates the ingestion_quotas table for per-community and per-organization quota overrides
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.ingestion_quotas (
+    scope VARCHAR(20) NOT NULL,
+    scope_id UUID NOT NULL,
+    daily_events BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (scope, scope_id),
+    CONSTRAINT valid_quota_scope CHECK (scope IN ('community', 'organization')),
+    CONSTRAINT valid_daily_events CHECK (daily_events >= 0)
+);
+
+COMMENT ON TABLE pulse.ingestion_quotas IS 'daily event quota overrides; scopes without a row use the configured default';
+COMMENT ON COLUMN pulse.ingestion_quotas.scope_id IS 'community or organization id, depending on scope';
+COMMENT ON COLUMN pulse.ingestion_quotas.daily_events IS 'events accepted per UTC day, 0 means unlimited';
@@ -0,0 +1,4 @@
+-- migration: 000012_create_metering_records.down.sql
+-- drops the metering_records table
+
+DROP TABLE IF EXISTS pulse.metering_records;
@@ -0,0 +1,30 @@
+-- migration: 000012_create_metering_records.up.sql
+-- creates the metering_records table, billable usage per subject and period
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.metering_records (
+    id UUID PRIMARY KEY,
+    subject VARCHAR(20) NOT NULL,
+    subject_id VARCHAR(255) NOT NULL,
+    metric VARCHAR(30) NOT NULL,
+    quantity BIGINT NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT valid_metering_subject CHECK (subject IN ('community', 'organization', 'user')),
+    CONSTRAINT valid_metering_metric CHECK (metric IN ('events_ingested', 'webhooks_delivered', 'api_calls')),
+    CONSTRAINT positive_quantity CHECK (quantity > 0),
+    CONSTRAINT valid_period CHECK (period_end > period_start)
+);
+
+COMMENT ON TABLE pulse.metering_records IS 'append-only billable usage, one row per subject, metric and flush period';
+COMMENT ON COLUMN pulse.metering_records.subject_id IS 'community or organization id, or the external auth id for users';
+
+-- index for billing exports by period
+CREATE INDEX IF NOT EXISTS idx_metering_records_period
+    ON pulse.metering_records(period_start);
+
+-- index for invoicing one subject
+CREATE INDEX IF NOT EXISTS idx_metering_records_subject
+    ON pulse.metering_records(subject, subject_id, period_start);
@@ -0,0 +1,5 @@
+-- migration: 000013_create_community_invitations.down.sql
+-- drops community invitations and members
+
+DROP TABLE IF EXISTS pulse.community_invitations;
+DROP TABLE IF EXISTS pulse.community_members;
@@ -0,0 +1,35 @@
+-- migration: 000013_create_community_invitations.up.sql
+-- creates community members and the invitations that add them
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.community_members (
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (community_id, user_id)
+);
+
+COMMENT ON TABLE pulse.community_members IS 'users who joined a community, currently through invitations';
+
+-- index for listing a user's communities
+CREATE INDEX IF NOT EXISTS idx_community_members_user
+    ON pulse.community_members(user_id);
+
+CREATE TABLE IF NOT EXISTS pulse.community_invitations (
+    id UUID PRIMARY KEY,
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    hint VARCHAR(4) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ,
+    accepted_by UUID REFERENCES pulse.users_profile(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE pulse.community_invitations IS 'single-use invitation tokens; only the sha256 of the token is stored';
+
+CREATE INDEX IF NOT EXISTS idx_community_invitations_community
+    ON pulse.community_invitations(community_id, created_at DESC);
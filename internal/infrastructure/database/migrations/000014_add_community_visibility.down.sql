@@ -0,0 +1,5 @@
+-- migration: 000014_add_community_visibility.down.sql
+-- drops community visibility, every community is public again
+
+DROP INDEX IF EXISTS pulse.idx_communities_public_momentum;
+ALTER TABLE pulse.communities DROP COLUMN IF EXISTS visibility;
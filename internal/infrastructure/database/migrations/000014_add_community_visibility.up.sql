@@ -0,0 +1,14 @@
+-- migration: 000014_add_community_visibility.up.sql
+-- adds community visibility: public, unlisted or private
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.communities
+    ADD COLUMN IF NOT EXISTS visibility VARCHAR(10) NOT NULL DEFAULT 'public'
+        CONSTRAINT valid_community_visibility CHECK (visibility IN ('public', 'unlisted', 'private'));
+
+COMMENT ON COLUMN pulse.communities.visibility IS 'public (on leaderboards), unlisted (reachable by id or slug) or private (members only)';
+
+-- index for the public leaderboard fallback
+CREATE INDEX IF NOT EXISTS idx_communities_public_momentum
+    ON pulse.communities(current_momentum DESC)
+    WHERE is_active = true AND visibility = 'public';
@@ -0,0 +1,6 @@
+-- migration: 000015_create_community_moderation.down.sql
+-- drops moderation tables and community member roles
+
+DROP TABLE IF EXISTS pulse.moderation_log;
+DROP TABLE IF EXISTS pulse.community_sanctions;
+ALTER TABLE pulse.community_members DROP COLUMN IF EXISTS role;
@@ -0,0 +1,40 @@
+-- migration: 000015_create_community_moderation.up.sql
+-- adds community moderators, bans and mutes, and the moderation audit log
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.community_members
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member'
+        CONSTRAINT valid_community_role CHECK (role IN ('member', 'moderator'));
+
+COMMENT ON COLUMN pulse.community_members.role IS 'member, or moderator (bans and mutes other members)';
+
+CREATE TABLE IF NOT EXISTS pulse.community_sanctions (
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    issued_by UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ,
+
+    PRIMARY KEY (community_id, user_id, kind),
+    CONSTRAINT valid_sanction_kind CHECK (kind IN ('ban', 'mute'))
+);
+
+COMMENT ON TABLE pulse.community_sanctions IS 'bans reject a user''s events in a community, mutes drop them; null expires_at lasts until lifted';
+
+CREATE TABLE IF NOT EXISTS pulse.moderation_log (
+    id UUID PRIMARY KEY,
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES pulse.users_profile(id),
+    target_id UUID NOT NULL REFERENCES pulse.users_profile(id),
+    action VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.moderation_log IS 'append-only audit trail of bans, mutes and moderator changes';
+
+CREATE INDEX IF NOT EXISTS idx_moderation_log_community
+    ON pulse.moderation_log(community_id, created_at DESC);
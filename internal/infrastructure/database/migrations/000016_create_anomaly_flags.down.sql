@@ -0,0 +1,4 @@
+-- migration: 000016_create_anomaly_flags.down.sql
+-- drops anomaly flags
+
+DROP TABLE IF EXISTS pulse.anomaly_flags;
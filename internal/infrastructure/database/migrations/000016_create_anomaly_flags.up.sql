@@ -0,0 +1,34 @@
+-- migration: 000016_create_anomaly_flags.up.sql
+-- creates anomaly flags for communities and users whose ingest rate looks like gaming
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.anomaly_flags (
+    id UUID PRIMARY KEY,
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'confirmed', 'dismissed')),
+    observed_events BIGINT NOT NULL,
+    expected_events DOUBLE PRECISION NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewed_at TIMESTAMPTZ,
+    reviewed_by TEXT NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE pulse.anomaly_flags IS 'suspicious ingest rates; pending and confirmed flags keep events out of momentum';
+COMMENT ON COLUMN pulse.anomaly_flags.user_id IS 'null when the whole community is flagged';
+
+-- at most one pending flag per community, and per user in a community
+CREATE UNIQUE INDEX IF NOT EXISTS idx_anomaly_flags_pending
+    ON pulse.anomaly_flags(community_id, COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid))
+    WHERE status = 'pending';
+
+-- index for the momentum worker, which reads a community's quarantines every cycle
+CREATE INDEX IF NOT EXISTS idx_anomaly_flags_quarantine
+    ON pulse.anomaly_flags(community_id, window_start)
+    WHERE status <> 'dismissed';
+
+-- index for the admin review queue
+CREATE INDEX IF NOT EXISTS idx_anomaly_flags_status
+    ON pulse.anomaly_flags(status, detected_at DESC);
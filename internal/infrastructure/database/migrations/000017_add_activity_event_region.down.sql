@@ -0,0 +1,5 @@
+-- migration: 000017_add_activity_event_region.down.sql
+-- drops the event region column, the region stays in the metadata
+
+DROP INDEX IF EXISTS pulse.idx_activity_events_community_region_time;
+ALTER TABLE pulse.activity_events DROP COLUMN IF EXISTS region;
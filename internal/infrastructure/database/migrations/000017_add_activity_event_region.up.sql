@@ -0,0 +1,14 @@
+-- migration: 000017_add_activity_event_region.up.sql
+-- adds the region events carry in their metadata as a column, for regional leaderboards
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.activity_events
+    ADD COLUMN IF NOT EXISTS region VARCHAR(32)
+        GENERATED ALWAYS AS (lower(left(metadata->>'region', 32))) STORED;
+
+COMMENT ON COLUMN pulse.activity_events.region IS 'metadata region, null for events without one';
+
+-- index for per-region momentum sums
+CREATE INDEX IF NOT EXISTS idx_activity_events_community_region_time
+    ON pulse.activity_events(community_id, region, created_at DESC)
+    WHERE region IS NOT NULL;
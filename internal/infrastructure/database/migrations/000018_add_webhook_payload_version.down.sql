@@ -0,0 +1,4 @@
+-- migration: 000018_add_webhook_payload_version.down.sql
+-- drops the webhook payload version, every subscription gets v1 again
+
+ALTER TABLE pulse.webhook_subscriptions DROP COLUMN IF EXISTS payload_version;
@@ -0,0 +1,8 @@
+-- migration: 000018_add_webhook_payload_version.up.sql
+-- adds the payload format each webhook subscription receives
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS payload_version VARCHAR(8) NOT NULL DEFAULT 'v1';
+
+COMMENT ON COLUMN pulse.webhook_subscriptions.payload_version IS 'payload format sent to the target, existing subscriptions stay on v1';
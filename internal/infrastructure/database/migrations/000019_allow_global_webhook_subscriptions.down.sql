@@ -0,0 +1,11 @@
+-- migration: 000019_allow_global_webhook_subscriptions.down.sql
+-- removes global webhook subscriptions, community_id is required again
+
+DROP INDEX IF EXISTS pulse.idx_webhook_subscriptions_global;
+DELETE FROM pulse.webhook_subscriptions WHERE community_id IS NULL;
+
+ALTER TABLE pulse.webhook_subscriptions DROP CONSTRAINT IF EXISTS unique_user_community_subscription;
+ALTER TABLE pulse.webhook_subscriptions
+    ADD CONSTRAINT unique_user_community_subscription UNIQUE (user_id, community_id);
+
+ALTER TABLE pulse.webhook_subscriptions ALTER COLUMN community_id SET NOT NULL;
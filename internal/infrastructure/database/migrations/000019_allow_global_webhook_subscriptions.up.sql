@@ -0,0 +1,17 @@
+-- migration: 000019_allow_global_webhook_subscriptions.up.sql
+-- lets a webhook subscription leave community_id null to get spikes from every public community
+-- idempotent: drops and recreates the constraint it changes
+
+ALTER TABLE pulse.webhook_subscriptions ALTER COLUMN community_id DROP NOT NULL;
+
+COMMENT ON COLUMN pulse.webhook_subscriptions.community_id IS 'null for a global subscription to every public community';
+
+-- one global subscription per user too, so the upsert in Save still applies
+ALTER TABLE pulse.webhook_subscriptions DROP CONSTRAINT IF EXISTS unique_user_community_subscription;
+ALTER TABLE pulse.webhook_subscriptions
+    ADD CONSTRAINT unique_user_community_subscription UNIQUE NULLS NOT DISTINCT (user_id, community_id);
+
+-- index for the global subscriptions every public spike is sent to
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_global
+    ON pulse.webhook_subscriptions(user_id)
+    WHERE community_id IS NULL AND is_active = true;
@@ -0,0 +1,4 @@
+-- migration: 000020_add_webhook_delivery_mode.down.sql
+-- drops the webhook delivery mode, every subscription gets immediate spikes again
+
+ALTER TABLE pulse.webhook_subscriptions DROP COLUMN IF EXISTS delivery_mode;
@@ -0,0 +1,8 @@
+-- migration: 000020_add_webhook_delivery_mode.up.sql
+-- adds how each webhook subscription receives spikes: one call each, or a digest per window
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS delivery_mode VARCHAR(16) NOT NULL DEFAULT 'immediate';
+
+COMMENT ON COLUMN pulse.webhook_subscriptions.delivery_mode IS 'immediate sends every spike, digest batches the spikes of a window into one call';
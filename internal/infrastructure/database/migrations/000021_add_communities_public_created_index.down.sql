@@ -0,0 +1,4 @@
+-- migration: 000021_add_communities_public_created_index.down.sql
+-- drops the new communities index
+
+DROP INDEX IF EXISTS pulse.idx_communities_public_created;
@@ -0,0 +1,7 @@
+-- migration: 000021_add_communities_public_created_index.up.sql
+-- index for the new communities in the discovery feed
+-- idempotent: uses IF NOT EXISTS
+
+CREATE INDEX IF NOT EXISTS idx_communities_public_created
+    ON pulse.communities(created_at DESC)
+    WHERE is_active = true AND visibility = 'public';
@@ -0,0 +1,4 @@
+-- migration: 000022_create_rejected_events.down.sql
+-- drops the ingestion quarantine
+
+DROP TABLE IF EXISTS pulse.rejected_events;
@@ -0,0 +1,24 @@
+-- migration: 000022_create_rejected_events.up.sql
+-- creates the quarantine for events accepted in deferred validation mode that failed the worker's checks
+-- idempotent: uses IF NOT EXISTS
+
+-- no foreign keys: rejected events usually point at communities or users that don't exist
+CREATE TABLE IF NOT EXISTS pulse.rejected_events (
+    id UUID PRIMARY KEY,
+    community_id UUID NOT NULL,
+    user_id UUID,
+    event_type pulse.activity_event_type NOT NULL,
+    weight NUMERIC(5, 2) NOT NULL,
+    metadata JSONB,
+    created_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(32) NOT NULL
+        CHECK (reason IN ('community_not_found', 'community_inactive', 'user_not_found')),
+    rejected_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.rejected_events IS 'events accepted without existence checks that the ingestion worker refused to store';
+COMMENT ON COLUMN pulse.rejected_events.created_at IS 'when the event was accepted, as it would have been stored';
+
+-- index for the admin listing, newest first
+CREATE INDEX IF NOT EXISTS idx_rejected_events_rejected_at
+    ON pulse.rejected_events(rejected_at DESC);
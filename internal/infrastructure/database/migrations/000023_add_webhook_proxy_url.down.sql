@@ -0,0 +1,4 @@
+-- migration: 000023_add_webhook_proxy_url.down.sql
+-- drops per-subscription webhook proxies
+
+ALTER TABLE pulse.webhook_subscriptions DROP COLUMN IF EXISTS proxy_url;
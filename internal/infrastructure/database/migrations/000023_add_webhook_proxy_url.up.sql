@@ -0,0 +1,8 @@
+-- migration: 000023_add_webhook_proxy_url.up.sql
+-- adds an optional per-subscription egress proxy for webhook deliveries
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS proxy_url TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN pulse.webhook_subscriptions.proxy_url IS 'empty uses the server proxy; only honored when per-subscription proxies are enabled';
@@ -0,0 +1,4 @@
+-- migration: 000024_create_notification_preferences.down.sql
+-- drops the notification_preferences table
+
+DROP TABLE IF EXISTS pulse.notification_preferences;
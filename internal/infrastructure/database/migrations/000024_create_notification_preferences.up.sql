@@ -0,0 +1,30 @@
+-- migration: 000024_create_notification_preferences.up.sql
+-- creates the notification_preferences table consulted before webhook deliveries
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    max_per_hour INTEGER NOT NULL DEFAULT 0,
+    muted_community_ids UUID[] NOT NULL DEFAULT '{}',
+    quiet_start_minute SMALLINT,
+    quiet_end_minute SMALLINT,
+    quiet_timezone TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    -- same bounds as the domain validation
+    CONSTRAINT valid_max_per_hour CHECK (max_per_hour >= 0),
+    CONSTRAINT valid_muted_count CHECK (cardinality(muted_community_ids) <= 500),
+    CONSTRAINT valid_quiet_hours CHECK (
+        (quiet_start_minute IS NULL AND quiet_end_minute IS NULL AND quiet_timezone IS NULL)
+        OR (quiet_start_minute BETWEEN 0 AND 1439
+            AND quiet_end_minute BETWEEN 0 AND 1439
+            AND quiet_start_minute <> quiet_end_minute
+            AND quiet_timezone IS NOT NULL)
+    )
+);
+
+COMMENT ON TABLE pulse.notification_preferences IS 'per-user limits on webhook notifications, users without a row get every notification';
+COMMENT ON COLUMN pulse.notification_preferences.max_per_hour IS 'deliveries allowed per rolling hour, 0 is unlimited';
+COMMENT ON COLUMN pulse.notification_preferences.muted_community_ids IS 'communities whose notifications are never sent to this user';
+COMMENT ON COLUMN pulse.notification_preferences.quiet_start_minute IS 'start of quiet hours in minutes after midnight, in quiet_timezone';
+COMMENT ON COLUMN pulse.notification_preferences.quiet_end_minute IS 'end of quiet hours in minutes after midnight, exclusive; may be before the start to cross midnight';
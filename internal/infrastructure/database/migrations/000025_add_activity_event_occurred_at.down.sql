@@ -0,0 +1,9 @@
+-- migration: 000025_add_activity_event_occurred_at.down.sql
+-- drops client event timestamps, windows go back to created_at
+
+ALTER TABLE pulse.rejected_events DROP COLUMN IF EXISTS occurred_at;
+
+DROP INDEX IF EXISTS pulse.idx_activity_events_community_region_occurred;
+DROP INDEX IF EXISTS pulse.idx_activity_events_occurred_at;
+DROP INDEX IF EXISTS pulse.idx_activity_events_community_occurred;
+ALTER TABLE pulse.activity_events DROP COLUMN IF EXISTS occurred_at;
@@ -0,0 +1,30 @@
+-- migration: 000025_add_activity_event_occurred_at.up.sql
+-- adds when an event happened, as reported by the client, next to when it was received
+-- momentum windows are measured against occurred_at from now on
+-- idempotent: uses IF NOT EXISTS
+
+-- existing events happened when they were received
+ALTER TABLE pulse.activity_events ADD COLUMN IF NOT EXISTS occurred_at TIMESTAMPTZ;
+UPDATE pulse.activity_events SET occurred_at = created_at WHERE occurred_at IS NULL;
+ALTER TABLE pulse.activity_events
+    ALTER COLUMN occurred_at SET DEFAULT now(),
+    ALTER COLUMN occurred_at SET NOT NULL;
+
+COMMENT ON COLUMN pulse.activity_events.occurred_at IS 'when the event happened, created_at unless the client sent a timestamp';
+COMMENT ON COLUMN pulse.activity_events.created_at IS 'when pulse received the event';
+
+-- indexes for the windowed queries, which now filter on occurred_at
+CREATE INDEX IF NOT EXISTS idx_activity_events_community_occurred
+    ON pulse.activity_events(community_id, occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_activity_events_occurred_at
+    ON pulse.activity_events(occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_activity_events_community_region_occurred
+    ON pulse.activity_events(community_id, region, occurred_at DESC)
+    WHERE region IS NOT NULL;
+
+-- quarantined events keep it too
+ALTER TABLE pulse.rejected_events ADD COLUMN IF NOT EXISTS occurred_at TIMESTAMPTZ;
+UPDATE pulse.rejected_events SET occurred_at = created_at WHERE occurred_at IS NULL;
+ALTER TABLE pulse.rejected_events ALTER COLUMN occurred_at SET NOT NULL;
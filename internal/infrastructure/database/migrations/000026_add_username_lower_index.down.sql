@@ -0,0 +1,4 @@
+-- migration: 000026_add_username_lower_index.down.sql
+-- usernames go back to case-sensitive uniqueness, renamed duplicates keep their suffix
+
+DROP INDEX IF EXISTS pulse.idx_users_profile_username_lower;
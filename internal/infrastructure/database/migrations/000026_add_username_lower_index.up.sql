@@ -0,0 +1,17 @@
+-- migration: 000026_add_username_lower_index.up.sql
+-- makes usernames unique regardless of case, lookups go through lower(username)
+-- idempotent: uses IF NOT EXISTS
+
+-- older profiles could differ only in case; the oldest keeps its handle,
+-- the rest get an id suffix like the signup trigger uses on conflicts
+UPDATE pulse.users_profile AS u
+SET username = left(u.username, 41) || '_' || left(u.id::text, 8),
+    updated_at = now()
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY lower(username) ORDER BY created_at, id) AS rank
+    FROM pulse.users_profile
+) AS dup
+WHERE u.id = dup.id AND dup.rank > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_profile_username_lower
+    ON pulse.users_profile(lower(username));
@@ -0,0 +1,11 @@
+-- migration: 000027_add_stale_reject_reason.down.sql
+-- drops the stale events from the quarantine, the old constraint doesn't allow them
+
+DELETE FROM pulse.rejected_events WHERE reason = 'stale';
+
+ALTER TABLE pulse.rejected_events DROP CONSTRAINT IF EXISTS rejected_events_reason_check;
+ALTER TABLE pulse.rejected_events
+    ADD CONSTRAINT rejected_events_reason_check
+        CHECK (reason IN ('community_not_found', 'community_inactive', 'user_not_found'));
+
+COMMENT ON TABLE pulse.rejected_events IS 'events accepted without existence checks that the ingestion worker refused to store';
@@ -0,0 +1,9 @@
+-- migration: 000027_add_stale_reject_reason.up.sql
+-- the ingestion worker quarantines events that waited in its buffer past the staleness bound
+
+ALTER TABLE pulse.rejected_events DROP CONSTRAINT IF EXISTS rejected_events_reason_check;
+ALTER TABLE pulse.rejected_events
+    ADD CONSTRAINT rejected_events_reason_check
+        CHECK (reason IN ('community_not_found', 'community_inactive', 'user_not_found', 'stale'));
+
+COMMENT ON TABLE pulse.rejected_events IS 'events accepted by the api that the ingestion worker refused to store';
@@ -0,0 +1,4 @@
+-- migration: 000028_create_event_groups.down.sql
+-- drops the event group idempotency keys
+
+DROP TABLE IF EXISTS pulse.event_groups;
@@ -0,0 +1,15 @@
+-- migration: 000028_create_event_groups.up.sql
+-- creates the idempotency keys of event groups, saved in the same transaction as their events
+-- idempotent: uses IF NOT EXISTS
+
+-- no foreign keys: the events may be pruned before the key is reused
+CREATE TABLE IF NOT EXISTS pulse.event_groups (
+    scope VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    event_ids UUID[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+COMMENT ON TABLE pulse.event_groups IS 'idempotency keys of event groups, a retry with the same key returns the saved events';
+COMMENT ON COLUMN pulse.event_groups.scope IS 'who sent the group, keys only collide within a scope';
@@ -0,0 +1,4 @@
+-- migration: 000029_create_community_pins.down.sql
+-- drops the leaderboard pins
+
+DROP TABLE IF EXISTS pulse.community_pins;
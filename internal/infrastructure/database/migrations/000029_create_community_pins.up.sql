@@ -0,0 +1,20 @@
+-- migration: 000029_create_community_pins.up.sql
+-- creates the scheduled pins that put communities at the top of the global leaderboard
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.community_pins (
+    id UUID PRIMARY KEY,
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    sponsored BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at)
+);
+
+COMMENT ON TABLE pulse.community_pins IS 'communities pinned above the momentum ranking of the global leaderboard for a time window';
+COMMENT ON COLUMN pulse.community_pins.sponsored IS 'paid placement, labeled as such by clients';
+
+-- index for the current and scheduled pins
+CREATE INDEX IF NOT EXISTS idx_community_pins_ends_at
+    ON pulse.community_pins(ends_at);
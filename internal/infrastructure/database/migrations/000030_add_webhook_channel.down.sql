@@ -0,0 +1,4 @@
+-- migration: 000030_add_webhook_channel.down.sql
+-- drops the subscription channel, every subscription is a signed webhook again
+
+ALTER TABLE pulse.webhook_subscriptions DROP COLUMN IF EXISTS channel;
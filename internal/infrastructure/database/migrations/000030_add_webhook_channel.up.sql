@@ -0,0 +1,8 @@
+-- migration: 000030_add_webhook_channel.up.sql
+-- adds where each subscription is delivered: a signed webhook, or a slack or discord incoming webhook
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS channel VARCHAR(16) NOT NULL DEFAULT 'webhook';
+
+COMMENT ON COLUMN pulse.webhook_subscriptions.channel IS 'webhook sends signed JSON, slack and discord post a message to the incoming webhook in target_url';
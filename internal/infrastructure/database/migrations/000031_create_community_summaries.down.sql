@@ -0,0 +1,4 @@
+-- migration: 000031_create_community_summaries.down.sql
+-- drops the community summaries
+
+DROP TABLE IF EXISTS pulse.community_summaries;
@@ -0,0 +1,15 @@
+-- migration: 000031_create_community_summaries.up.sql
+-- creates the rolled-up listing data of communities, so listings don't count members and events per request
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.community_summaries (
+    community_id UUID PRIMARY KEY REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    events_last_24h BIGINT NOT NULL DEFAULT 0,
+    last_activity_at TIMESTAMPTZ,
+    refreshed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.community_summaries IS 'member counts and recent activity per community, rewritten by the summary rollup';
+COMMENT ON COLUMN pulse.community_summaries.member_count IS 'community_members rows, the creator is not counted';
+COMMENT ON COLUMN pulse.community_summaries.last_activity_at IS 'latest occurred_at seen by a rollup, null until the community has an event in a rolled-up window';
@@ -0,0 +1,4 @@
+-- migration: 000032_create_momentum_runs.down.sql
+-- drops the momentum run audit trail
+
+DROP TABLE IF EXISTS pulse.momentum_runs;
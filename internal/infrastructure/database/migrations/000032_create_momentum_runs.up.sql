@@ -0,0 +1,21 @@
+-- migration: 000032_create_momentum_runs.up.sql
+-- creates the audit trail of momentum cycles, which were only logged before
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.momentum_runs (
+    id UUID PRIMARY KEY,
+    triggered_by TEXT NOT NULL CHECK (triggered_by IN ('schedule', 'admin', 'api', 'cli')),
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    processed INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE pulse.momentum_runs IS 'one row per momentum cycle over every community, kept for 30 days';
+COMMENT ON COLUMN pulse.momentum_runs.error IS 'why the whole cycle failed, empty when it ran; failed counts single communities';
+
+-- index for listing the latest runs and pruning old ones
+CREATE INDEX IF NOT EXISTS idx_momentum_runs_started_at
+    ON pulse.momentum_runs(started_at DESC);
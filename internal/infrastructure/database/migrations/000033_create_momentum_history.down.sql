@@ -0,0 +1,4 @@
+-- migration: 000033_create_momentum_history.down.sql
+-- drops the momentum history
+
+DROP TABLE IF EXISTS pulse.momentum_history;
@@ -0,0 +1,17 @@
+-- migration: 000033_create_momentum_history.up.sql
+-- creates the time series of calculated momentum, for history charts
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.momentum_history (
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    calculated_at TIMESTAMPTZ NOT NULL,
+    momentum DOUBLE PRECISION NOT NULL,
+
+    PRIMARY KEY (community_id, calculated_at)
+);
+
+COMMENT ON TABLE pulse.momentum_history IS 'every calculated or decayed momentum score, kept for 30 days';
+
+-- index for pruning old scores
+CREATE INDEX IF NOT EXISTS idx_momentum_history_calculated_at
+    ON pulse.momentum_history(calculated_at);
@@ -0,0 +1,4 @@
+-- migration: 000034_add_momentum_run_failures.down.sql
+-- drops the failure details of momentum runs, keeping their counts
+
+ALTER TABLE pulse.momentum_runs DROP COLUMN IF EXISTS failures;
@@ -0,0 +1,8 @@
+-- migration: 000034_add_momentum_run_failures.up.sql
+-- adds why single communities failed to the momentum run records, which only counted them
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.momentum_runs
+    ADD COLUMN IF NOT EXISTS failures JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN pulse.momentum_runs.failures IS 'first 20 failed communities as {community_id, reason, transient}; failed counts all of them';
@@ -0,0 +1,4 @@
+-- migration: 000035_create_webhook_deliveries.down.sql
+-- drops the webhook delivery log
+
+DROP TABLE IF EXISTS pulse.webhook_deliveries;
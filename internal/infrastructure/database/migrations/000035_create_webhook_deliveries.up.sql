@@ -0,0 +1,26 @@
+-- migration: 000035_create_webhook_deliveries.up.sql
+-- creates the log of webhook delivery attempts, so subscription owners can see what was received
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES pulse.webhook_subscriptions(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.webhook_deliveries IS 'one row per delivery attempt, kept for 30 days';
+COMMENT ON COLUMN pulse.webhook_deliveries.id IS 'the X-Pulse-Delivery-ID header of signed webhooks';
+COMMENT ON COLUMN pulse.webhook_deliveries.status_code IS 'subscriber response status, 0 when no response came back';
+COMMENT ON COLUMN pulse.webhook_deliveries.error IS 'why the attempt failed, empty when the subscriber accepted it';
+
+-- index for a subscription's history, newest first
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON pulse.webhook_deliveries(subscription_id, attempted_at DESC);
+
+-- index for pruning old attempts
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_attempted_at
+    ON pulse.webhook_deliveries(attempted_at);
@@ -0,0 +1,4 @@
+-- migration: 000036_create_community_event_weights.down.sql
+-- drops the per-community event weights
+
+DROP TABLE IF EXISTS pulse.community_event_weights;
@@ -0,0 +1,12 @@
+-- migration: 000036_create_community_event_weights.up.sql
+-- creates per-community overrides of the default event weights
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.community_event_weights (
+    community_id UUID PRIMARY KEY REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    weights JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.community_event_weights IS 'weights given to events ingested without one, communities without a row use the defaults';
+COMMENT ON COLUMN pulse.community_event_weights.weights IS 'weight by event type, types left out keep their default weight';
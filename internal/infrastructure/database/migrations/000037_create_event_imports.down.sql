@@ -0,0 +1,4 @@
+-- migration: 000037_create_event_imports.down.sql
+-- drops the bulk event import records
+
+DROP TABLE IF EXISTS pulse.event_imports;
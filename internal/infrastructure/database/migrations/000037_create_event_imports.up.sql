@@ -0,0 +1,22 @@
+-- migration: 000037_create_event_imports.up.sql
+-- creates the progress records of bulk event imports
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.event_imports (
+    id UUID PRIMARY KEY,
+    community_id UUID NOT NULL REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    format TEXT NOT NULL CHECK (format IN ('csv', 'ndjson')),
+    status TEXT NOT NULL CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    imported BIGINT NOT NULL DEFAULT 0,
+    rejected BIGINT NOT NULL DEFAULT 0,
+    row_errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE pulse.event_imports IS 'one row per bulk upload of historical events, updated as batches are written';
+COMMENT ON COLUMN pulse.event_imports.row_errors IS 'line and reason of the first 20 skipped rows, rejected counts all of them';
+COMMENT ON COLUMN pulse.event_imports.error IS 'why the whole import failed, empty unless status is failed';
@@ -0,0 +1,4 @@
+-- migration: 000038_create_jobs.down.sql
+-- drops the background job status records
+
+DROP TABLE IF EXISTS pulse.jobs;
@@ -0,0 +1,23 @@
+-- migration: 000038_create_jobs.up.sql
+-- creates the status records of long-running background work
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.jobs (
+    id UUID PRIMARY KEY,
+    kind TEXT NOT NULL,
+    owner_id UUID REFERENCES pulse.users_profile(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    done BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_owner ON pulse.jobs (owner_id, created_at DESC);
+
+COMMENT ON TABLE pulse.jobs IS 'one row per piece of background work, shares the id of the work''s own record';
+COMMENT ON COLUMN pulse.jobs.owner_id IS 'who started the job, null for work nobody in particular started';
+COMMENT ON COLUMN pulse.jobs.total IS 'items the job will process, 0 while unknown';
@@ -0,0 +1,4 @@
+-- migration: 000039_create_community_webhook_allowlists.down.sql
+-- drops the per-community webhook allowlists
+
+DROP TABLE IF EXISTS pulse.community_webhook_allowlists;
@@ -0,0 +1,12 @@
+-- migration: 000039_create_community_webhook_allowlists.up.sql
+-- creates per-community allowlists of webhook target domains
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.community_webhook_allowlists (
+    community_id UUID PRIMARY KEY REFERENCES pulse.communities(id) ON DELETE CASCADE,
+    domains TEXT[] NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE pulse.community_webhook_allowlists IS 'domains a community''s webhooks may be delivered to, communities without a row deliver anywhere';
+COMMENT ON COLUMN pulse.community_webhook_allowlists.domains IS 'lowercase host names, *.example.com allows the subdomains of example.com';
@@ -0,0 +1,4 @@
+-- migration: 000040_create_ranking_snapshots.down.sql
+-- drops the leaderboard snapshots
+
+DROP TABLE IF EXISTS pulse.ranking_snapshots;
@@ -0,0 +1,17 @@
+-- migration: 000040_create_ranking_snapshots.up.sql
+-- creates the top of the public leaderboard as it stood after each momentum cycle
+-- idempotent: uses IF NOT EXISTS
+
+CREATE TABLE IF NOT EXISTS pulse.ranking_snapshots (
+    id UUID PRIMARY KEY,
+    taken_at TIMESTAMPTZ NOT NULL,
+    entries JSONB NOT NULL
+);
+
+COMMENT ON TABLE pulse.ranking_snapshots IS 'the leaderboard after each momentum cycle, kept for 30 days to tell how it changed';
+COMMENT ON COLUMN pulse.ranking_snapshots.id IS 'the momentum run the snapshot was taken after';
+COMMENT ON COLUMN pulse.ranking_snapshots.entries IS 'community_id, rank and momentum of the top 100 listed communities';
+
+-- index for finding the snapshot at a time and pruning old ones
+CREATE INDEX IF NOT EXISTS idx_ranking_snapshots_taken_at
+    ON pulse.ranking_snapshots(taken_at);
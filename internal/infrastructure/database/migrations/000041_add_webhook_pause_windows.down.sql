@@ -0,0 +1,6 @@
+-- migration: 000041_add_webhook_pause_windows.down.sql
+-- drops webhook pause windows and their outbox
+
+DROP TABLE IF EXISTS pulse.webhook_outbox;
+
+ALTER TABLE pulse.webhook_subscriptions DROP COLUMN IF EXISTS pause_windows;
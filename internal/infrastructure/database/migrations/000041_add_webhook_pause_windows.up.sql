@@ -0,0 +1,22 @@
+-- migration: 000041_add_webhook_pause_windows.up.sql
+-- adds subscriber pause windows and the outbox holding deliveries during them
+-- idempotent: uses IF NOT EXISTS
+
+ALTER TABLE pulse.webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS pause_windows JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN pulse.webhook_subscriptions.pause_windows IS 'recurring windows, as day, start and end minutes and timezone, during which deliveries are held';
+
+CREATE TABLE IF NOT EXISTS pulse.webhook_outbox (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES pulse.webhook_subscriptions(id) ON DELETE CASCADE,
+    community_id UUID NOT NULL,
+    event TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    deliver_after TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_outbox_deliver_after ON pulse.webhook_outbox (deliver_after);
+
+COMMENT ON TABLE pulse.webhook_outbox IS 'notifications held while their subscription is paused, delivered once deliver_after passes';
@@ -0,0 +1,178 @@
+// Package eventbus delivers the domain events use cases publish to the parts of Pulse
+// that react to them, like the webhook worker and the caches.
+//
+// delivery is in-process and synchronous: handlers run in Publish, on the publisher's
+// goroutine, so they must not block. a handler with slow work queues it, as the webhook
+// worker does. with redis, events are also relayed to every other instance, for
+// subscribers that care about the whole cluster, see SubscribeCluster.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// Channel is the redis pub/sub channel events are relayed on.
+const Channel = "pulse:events"
+
+// Handler reacts to a domain event. it runs on the publisher's goroutine and must not block.
+type Handler func(ctx context.Context, event domain.DomainEvent)
+
+// Bus is the domain event bus. safe for concurrent use.
+type Bus struct {
+	instanceID string
+	redis      *redis.Client
+	logger     *logging.Logger
+
+	mu      sync.RWMutex
+	local   map[string][]Handler // events published by this instance
+	cluster map[string][]Handler // events published by any instance
+
+	pubsub   *redis.PubSub
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// New creates an in-process event bus.
+func New(logger *logging.Logger) *Bus {
+	return &Bus{
+		instanceID: uuid.NewString(),
+		logger:     logger.WithComponent("event_bus"),
+		local:      make(map[string][]Handler),
+		cluster:    make(map[string][]Handler),
+	}
+}
+
+// WithRedis relays events between instances over redis pub/sub, once Start is called.
+func (b *Bus) WithRedis(client *redis.Client) *Bus {
+	b.redis = client
+	return b
+}
+
+// Subscribe calls h for every event of the given name published by this instance.
+// for work that must happen once per event, like sending webhooks.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.local[name] = append(b.local[name], h)
+}
+
+// SubscribeCluster calls h for every event of the given name published by any instance,
+// for state every instance keeps, like caches. without redis it's the same as Subscribe.
+func (b *Bus) SubscribeCluster(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cluster[name] = append(b.cluster[name], h)
+}
+
+// Publish delivers an event to this instance's subscribers, then relays it to the other
+// instances. a failed relay is logged, the event was still handled here.
+func (b *Bus) Publish(ctx context.Context, event domain.DomainEvent) {
+	name := event.EventName()
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.local[name])+len(b.cluster[name]))
+	handlers = append(handlers, b.local[name]...)
+	handlers = append(handlers, b.cluster[name]...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		b.dispatch(ctx, name, h, event)
+	}
+
+	if b.redis == nil {
+		return
+	}
+	payload, err := encode(b.instanceID, event)
+	if err != nil {
+		b.logger.WithContext(ctx).Error("encoding domain event failed",
+			"event", name,
+			"error", err.Error(),
+		)
+		return
+	}
+	if err := b.redis.Publish(ctx, Channel, payload).Err(); err != nil {
+		b.logger.WithContext(ctx).Warn("relaying domain event failed",
+			"event", name,
+			"error", err.Error(),
+		)
+	}
+}
+
+// Start listens for events relayed by other instances. a no-op without redis.
+// the subscription reconnects on its own after redis comes back, events published
+// meanwhile are missed: subscribers must tolerate that, caches expire anyway.
+func (b *Bus) Start(ctx context.Context) {
+	if b.redis == nil {
+		return
+	}
+
+	b.pubsub = b.redis.Subscribe(ctx, Channel)
+	b.logger.Info("event bus listening", "channel", Channel)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for msg := range b.pubsub.Channel() {
+			b.receive(ctx, msg.Payload)
+		}
+	}()
+}
+
+// Stop stops listening for relayed events. Publish keeps working.
+func (b *Bus) Stop() {
+	b.stopOnce.Do(func() {
+		if b.pubsub == nil {
+			return
+		}
+		if err := b.pubsub.Close(); err != nil {
+			b.logger.Warn("closing event bus subscription failed", "error", err.Error())
+		}
+		b.wg.Wait()
+		b.logger.Info("event bus stopped")
+	})
+}
+
+// receive delivers an event relayed by another instance to the cluster subscribers.
+// the instance's own events come back too, they were already delivered in Publish.
+func (b *Bus) receive(ctx context.Context, payload string) {
+	origin, event, err := decode(payload)
+	if err != nil {
+		b.logger.Warn("decoding relayed domain event failed", "error", err.Error())
+		return
+	}
+	if origin == b.instanceID || event == nil {
+		return
+	}
+
+	name := event.EventName()
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.cluster[name]...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		b.dispatch(ctx, name, h, event)
+	}
+}
+
+// dispatch runs a handler, a panic is logged rather than taking down the publisher.
+func (b *Bus) dispatch(ctx context.Context, name string, h Handler, event domain.DomainEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.WithContext(ctx).Error("domain event handler panicked",
+				"event", name,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+	h(ctx, event)
+}
@@ -0,0 +1,102 @@
+package eventbus
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+func newTestBus() *Bus {
+	return New(logging.NewWithWriter(io.Discard, slog.LevelError))
+}
+
+func TestBus_PublishDeliversToSubscribers(t *testing.T) {
+	b := newTestBus()
+
+	var local, cluster, other int
+	b.Subscribe(domain.EventSpikeDetected, func(context.Context, domain.DomainEvent) { local++ })
+	b.SubscribeCluster(domain.EventSpikeDetected, func(context.Context, domain.DomainEvent) { cluster++ })
+	b.Subscribe(domain.EventCommunityCreated, func(context.Context, domain.DomainEvent) { other++ })
+	b.Subscribe(domain.EventSpikeDetected, func(context.Context, domain.DomainEvent) { panic("boom") })
+
+	b.Publish(context.Background(), domain.SpikeDetected{})
+
+	if local != 1 || cluster != 1 {
+		t.Errorf("local = %d, cluster = %d, want 1 each", local, cluster)
+	}
+	if other != 0 {
+		t.Errorf("community.created handler ran %d times for a spike", other)
+	}
+}
+
+func TestBus_ReceiveSkipsOwnEvents(t *testing.T) {
+	b := newTestBus()
+
+	var local, cluster int
+	b.Subscribe(domain.EventCommunityCreated, func(context.Context, domain.DomainEvent) { local++ })
+	b.SubscribeCluster(domain.EventCommunityCreated, func(context.Context, domain.DomainEvent) { cluster++ })
+
+	event := domain.CommunityCreated{CommunityID: domain.NewCommunityID(), Visibility: domain.VisibilityPublic}
+	own, err := encode(b.instanceID, event)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	relayed, err := encode("other-instance", event)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	b.receive(context.Background(), string(own))
+	b.receive(context.Background(), string(relayed))
+	b.receive(context.Background(), `{"origin":"other-instance","name":"something.new","event":{}}`)
+
+	if local != 0 {
+		t.Errorf("local handler ran %d times for relayed events, want 0", local)
+	}
+	if cluster != 1 {
+		t.Errorf("cluster handler ran %d times, want 1", cluster)
+	}
+}
+
+func TestWire_RoundTrip(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	id := domain.NewCommunityID()
+
+	tests := []domain.DomainEvent{
+		domain.CommunityCreated{CommunityID: id, Slug: "go", Visibility: domain.VisibilityUnlisted, CreatedAt: at},
+		domain.CommunityDeactivated{CommunityID: id, DeactivatedAt: at},
+		domain.MomentumCalculated{CommunityID: id, OldMomentum: 1, NewMomentum: 2.5, Decayed: true, CalculatedAt: at},
+		domain.SpikeDetected{Spike: domain.MomentumSpike{
+			CommunityID:   id,
+			CommunityName: "Go",
+			OldMomentum:   10,
+			NewMomentum:   30,
+			PercentChange: 2,
+			Timestamp:     at,
+		}},
+	}
+
+	for _, want := range tests {
+		t.Run(want.EventName(), func(t *testing.T) {
+			payload, err := encode("origin", want)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			origin, got, err := decode(string(payload))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if origin != "origin" {
+				t.Errorf("origin = %q, want origin", origin)
+			}
+			if got != want {
+				t.Errorf("decoded %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// DefaultHubBuffer is how many events a live subscriber may fall behind before it's dropped.
+const DefaultHubBuffer = 16
+
+// Hub fans momentum updates and spikes out to the live feeds following each community.
+// subscribe Handle to domain.EventMomentumCalculated and domain.EventSpikeDetected,
+// with SubscribeCluster so feeds on every instance get the updates of every instance.
+//
+// Handle never blocks the publisher: a subscriber whose buffer is full is dropped, its
+// channel closed, and its feed should close so the client reconnects and starts over
+// from the current momentum. safe for concurrent use.
+type Hub struct {
+	buffer int
+
+	mu     sync.Mutex
+	subs   map[domain.CommunityID]map[*HubSubscription]struct{}
+	closed bool
+}
+
+// HubSubscription is a live feed's view of one community's events.
+type HubSubscription struct {
+	hub         *Hub
+	communityID domain.CommunityID
+	events      chan domain.DomainEvent
+	dropped     bool
+}
+
+// NewHub creates a hub whose subscribers may fall buffer events behind,
+// DefaultHubBuffer when not positive.
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = DefaultHubBuffer
+	}
+	return &Hub{
+		buffer: buffer,
+		subs:   make(map[domain.CommunityID]map[*HubSubscription]struct{}),
+	}
+}
+
+// Subscribe follows a community's events until the subscription is closed.
+// after Close, the subscription's channel is closed right away.
+func (h *Hub) Subscribe(communityID domain.CommunityID) *HubSubscription {
+	sub := &HubSubscription{
+		hub:         h,
+		communityID: communityID,
+		events:      make(chan domain.DomainEvent, h.buffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.events)
+		return sub
+	}
+	if h.subs[communityID] == nil {
+		h.subs[communityID] = make(map[*HubSubscription]struct{})
+	}
+	h.subs[communityID][sub] = struct{}{}
+	return sub
+}
+
+// Handle delivers a momentum update or spike to the subscribers of its community.
+// other events are ignored.
+func (h *Hub) Handle(_ context.Context, event domain.DomainEvent) {
+	var communityID domain.CommunityID
+	switch e := event.(type) {
+	case domain.MomentumCalculated:
+		communityID = e.CommunityID
+	case domain.SpikeDetected:
+		communityID = e.Spike.CommunityID
+	default:
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[communityID] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped = true
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribers returns how many live feeds are following any community.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := 0
+	for _, subs := range h.subs {
+		n += len(subs)
+	}
+	return n
+}
+
+// Close closes every subscription, ending their feeds, e.g. on shutdown.
+// connections upgraded to websockets aren't waited for by the http server.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove unregisters a subscription and closes its channel. must hold h.mu.
+func (h *Hub) remove(sub *HubSubscription) {
+	subs, ok := h.subs[sub.communityID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.communityID)
+	}
+	close(sub.events)
+}
+
+// Events returns the community's events, closed when the subscription ends.
+func (s *HubSubscription) Events() <-chan domain.DomainEvent {
+	return s.events
+}
+
+// Dropped reports whether the subscription ended because it fell behind.
+// only meaningful once Events is closed.
+func (s *HubSubscription) Dropped() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.dropped
+}
+
+// Close stops following the community. safe to call more than once.
+func (s *HubSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
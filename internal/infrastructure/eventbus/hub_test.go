@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+func TestHub_DeliversToCommunitySubscribers(t *testing.T) {
+	h := NewHub(4)
+	followed, other := domain.NewCommunityID(), domain.NewCommunityID()
+
+	sub := h.Subscribe(followed)
+	defer sub.Close()
+
+	h.Handle(context.Background(), domain.MomentumCalculated{CommunityID: other, NewMomentum: 3})
+	h.Handle(context.Background(), domain.CommunityCreated{CommunityID: followed})
+	h.Handle(context.Background(), domain.MomentumCalculated{CommunityID: followed, NewMomentum: 5})
+	h.Handle(context.Background(), domain.SpikeDetected{Spike: domain.MomentumSpike{CommunityID: followed}})
+
+	if got := len(sub.Events()); got != 2 {
+		t.Fatalf("buffered %d events, want the followed community's update and spike", got)
+	}
+	if e, ok := (<-sub.Events()).(domain.MomentumCalculated); !ok || e.NewMomentum != 5 {
+		t.Errorf("first event = %+v, want the momentum update", e)
+	}
+}
+
+func TestHub_DropsSlowSubscribers(t *testing.T) {
+	h := NewHub(1)
+	id := domain.NewCommunityID()
+	slow := h.Subscribe(id)
+
+	h.Handle(context.Background(), domain.MomentumCalculated{CommunityID: id})
+	h.Handle(context.Background(), domain.MomentumCalculated{CommunityID: id})
+
+	<-slow.Events()
+	if _, open := <-slow.Events(); open {
+		t.Fatal("expected the channel of a subscriber that fell behind to be closed")
+	}
+	if !slow.Dropped() {
+		t.Error("expected Dropped to report the subscriber fell behind")
+	}
+	if n := h.Subscribers(); n != 0 {
+		t.Errorf("subscribers = %d, want 0", n)
+	}
+	slow.Close() // no double close
+}
+
+func TestHub_Close(t *testing.T) {
+	h := NewHub(0)
+	id := domain.NewCommunityID()
+	sub := h.Subscribe(id)
+
+	h.Close()
+	if _, open := <-sub.Events(); open {
+		t.Fatal("expected Close to end every subscription")
+	}
+	if sub.Dropped() {
+		t.Error("a closed hub isn't a slow subscriber")
+	}
+
+	late := h.Subscribe(id)
+	if _, open := <-late.Events(); open {
+		t.Error("expected subscriptions after Close to end right away")
+	}
+}
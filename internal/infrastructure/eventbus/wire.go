@@ -0,0 +1,160 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// envelope is a relayed event as published on the redis channel.
+type envelope struct {
+	Origin string          `json:"origin"`
+	Name   string          `json:"name"`
+	Event  json.RawMessage `json:"event"`
+}
+
+type communityCreatedWire struct {
+	CommunityID string    `json:"community_id"`
+	Slug        string    `json:"slug"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type communityDeactivatedWire struct {
+	CommunityID   string    `json:"community_id"`
+	DeactivatedAt time.Time `json:"deactivated_at"`
+}
+
+type momentumCalculatedWire struct {
+	CommunityID  string    `json:"community_id"`
+	OldMomentum  float64   `json:"old_momentum"`
+	NewMomentum  float64   `json:"new_momentum"`
+	Decayed      bool      `json:"decayed"`
+	CalculatedAt time.Time `json:"calculated_at"`
+}
+
+type spikeDetectedWire struct {
+	CommunityID   string    `json:"community_id"`
+	CommunityName string    `json:"community_name"`
+	OldMomentum   float64   `json:"old_momentum"`
+	NewMomentum   float64   `json:"new_momentum"`
+	PercentChange float64   `json:"percent_change"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// encode wraps an event for the redis channel. domain ids keep their value unexported,
+// so every event has a wire form.
+func encode(origin string, event domain.DomainEvent) ([]byte, error) {
+	var wire any
+	switch e := event.(type) {
+	case domain.CommunityCreated:
+		wire = communityCreatedWire{
+			CommunityID: e.CommunityID.String(),
+			Slug:        e.Slug,
+			Visibility:  e.Visibility.String(),
+			CreatedAt:   e.CreatedAt,
+		}
+	case domain.CommunityDeactivated:
+		wire = communityDeactivatedWire{
+			CommunityID:   e.CommunityID.String(),
+			DeactivatedAt: e.DeactivatedAt,
+		}
+	case domain.MomentumCalculated:
+		wire = momentumCalculatedWire{
+			CommunityID:  e.CommunityID.String(),
+			OldMomentum:  e.OldMomentum,
+			NewMomentum:  e.NewMomentum,
+			Decayed:      e.Decayed,
+			CalculatedAt: e.CalculatedAt,
+		}
+	case domain.SpikeDetected:
+		wire = spikeDetectedWire{
+			CommunityID:   e.Spike.CommunityID.String(),
+			CommunityName: e.Spike.CommunityName,
+			OldMomentum:   e.Spike.OldMomentum,
+			NewMomentum:   e.Spike.NewMomentum,
+			PercentChange: e.Spike.PercentChange,
+			Timestamp:     e.Spike.Timestamp,
+		}
+	default:
+		return nil, fmt.Errorf("no wire form for %s", event.EventName())
+	}
+
+	raw, err := json.Marshal(wire)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Origin: origin, Name: event.EventName(), Event: raw})
+}
+
+// decode unwraps an event from the redis channel. an event this version doesn't know,
+// from a newer instance during a rollout, decodes to nil.
+func decode(payload string) (origin string, event domain.DomainEvent, err error) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		return "", nil, err
+	}
+
+	switch env.Name {
+	case domain.EventCommunityCreated:
+		var w communityCreatedWire
+		if err := json.Unmarshal(env.Event, &w); err != nil {
+			return "", nil, err
+		}
+		id, err := domain.ParseCommunityID(w.CommunityID)
+		if err != nil {
+			return "", nil, err
+		}
+		visibility, err := domain.ParseCommunityVisibility(w.Visibility)
+		if err != nil {
+			return "", nil, err
+		}
+		event = domain.CommunityCreated{CommunityID: id, Slug: w.Slug, Visibility: visibility, CreatedAt: w.CreatedAt}
+	case domain.EventCommunityDeactivated:
+		var w communityDeactivatedWire
+		if err := json.Unmarshal(env.Event, &w); err != nil {
+			return "", nil, err
+		}
+		id, err := domain.ParseCommunityID(w.CommunityID)
+		if err != nil {
+			return "", nil, err
+		}
+		event = domain.CommunityDeactivated{CommunityID: id, DeactivatedAt: w.DeactivatedAt}
+	case domain.EventMomentumCalculated:
+		var w momentumCalculatedWire
+		if err := json.Unmarshal(env.Event, &w); err != nil {
+			return "", nil, err
+		}
+		id, err := domain.ParseCommunityID(w.CommunityID)
+		if err != nil {
+			return "", nil, err
+		}
+		event = domain.MomentumCalculated{
+			CommunityID:  id,
+			OldMomentum:  w.OldMomentum,
+			NewMomentum:  w.NewMomentum,
+			Decayed:      w.Decayed,
+			CalculatedAt: w.CalculatedAt,
+		}
+	case domain.EventSpikeDetected:
+		var w spikeDetectedWire
+		if err := json.Unmarshal(env.Event, &w); err != nil {
+			return "", nil, err
+		}
+		id, err := domain.ParseCommunityID(w.CommunityID)
+		if err != nil {
+			return "", nil, err
+		}
+		event = domain.SpikeDetected{Spike: domain.MomentumSpike{
+			CommunityID:   id,
+			CommunityName: w.CommunityName,
+			OldMomentum:   w.OldMomentum,
+			NewMomentum:   w.NewMomentum,
+			PercentChange: w.PercentChange,
+			Timestamp:     w.Timestamp,
+		}}
+	}
+	return env.Origin, event, nil
+}
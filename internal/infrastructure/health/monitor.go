@@ -0,0 +1,296 @@
+package health
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// Check probes a dependency, returning an error when it's down.
+type Check func(ctx context.Context) error
+
+// MonitorConfig holds configuration for the health monitor.
+type MonitorConfig struct {
+	// Interval is how often the checks run and heartbeats are sampled.
+	Interval time.Duration
+
+	// Retention is how far back the history goes. Status reports over this window.
+	Retention time.Duration
+
+	// Timeout bounds a single check.
+	Timeout time.Duration
+}
+
+// DefaultMonitorConfig returns sensible defaults: 24 hours of samples every 30 seconds.
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		Interval:  30 * time.Second,
+		Retention: 24 * time.Hour,
+		Timeout:   5 * time.Second,
+	}
+}
+
+// Monitor samples dependency checks and worker heartbeats into a fixed-size ring buffer,
+// so the status page costs a bounded amount of memory however long the process runs.
+type Monitor struct {
+	config MonitorConfig
+	logger *logging.Logger
+
+	mu         sync.Mutex
+	checks     []namedCheck
+	heartbeats map[string]*heartbeat
+	order      []string // heartbeat names in registration order
+	samples    []sample // ring buffer, next is the oldest once full
+	next       int
+	full       bool
+	startedAt  time.Time
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+type heartbeat struct {
+	maxAge time.Duration
+	last   time.Time
+}
+
+// sample is the result of every check and heartbeat at one point in time.
+type sample struct {
+	at      time.Time
+	results []result
+}
+
+type result struct {
+	name    string
+	ok      bool
+	latency time.Duration // checks only
+}
+
+// NewMonitor creates a new Monitor. register checks and heartbeats before calling Run.
+func NewMonitor(config MonitorConfig, logger *logging.Logger) *Monitor {
+	size := int(config.Retention / config.Interval)
+	if size < 1 {
+		size = 1
+	}
+	return &Monitor{
+		config:     config,
+		logger:     logger.WithComponent("health_monitor"),
+		heartbeats: make(map[string]*heartbeat),
+		samples:    make([]sample, size),
+		startedAt:  time.Now(),
+	}
+}
+
+// AddCheck registers a dependency check, run every interval.
+func (m *Monitor) AddCheck(name string, check Check) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks = append(m.checks, namedCheck{name: name, check: check})
+}
+
+// ExpectHeartbeat registers a worker that must Beat at least every maxAge to count as up.
+// registering counts as the first beat, so a worker isn't down before its first tick.
+func (m *Monitor) ExpectHeartbeat(name string, maxAge time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.heartbeats[name]; !ok {
+		m.order = append(m.order, name)
+	}
+	m.heartbeats[name] = &heartbeat{maxAge: maxAge, last: time.Now()}
+}
+
+// Beat records that a worker is alive. beats for unregistered names are ignored.
+func (m *Monitor) Beat(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hb, ok := m.heartbeats[name]; ok {
+		hb.last = time.Now()
+	}
+}
+
+// Run samples every interval until the context is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	m.logger.Info("health monitor started",
+		"interval", m.config.Interval.String(),
+		"retention", m.config.Retention.String(),
+	)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	m.probe(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.probe(ctx, now)
+		}
+	}
+}
+
+// probe runs every check and samples every heartbeat at now.
+// checks run outside the lock, so a slow dependency never blocks Beat.
+func (m *Monitor) probe(ctx context.Context, now time.Time) {
+	m.mu.Lock()
+	checks := append([]namedCheck(nil), m.checks...)
+	m.mu.Unlock()
+
+	results := make([]result, 0, len(checks))
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+		start := time.Now()
+		err := c.check(checkCtx)
+		latency := time.Since(start)
+		cancel()
+
+		if err != nil {
+			m.logger.Warn("health check failed", "check", c.name, "error", err.Error())
+		}
+		results = append(results, result{name: c.name, ok: err == nil, latency: latency})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range m.order {
+		hb := m.heartbeats[name]
+		results = append(results, result{name: name, ok: now.Sub(hb.last) <= hb.maxAge})
+	}
+
+	m.samples[m.next] = sample{at: now, results: results}
+	m.next = (m.next + 1) % len(m.samples)
+	if m.next == 0 {
+		m.full = true
+	}
+}
+
+// Status is a summary of the sampled history, the data behind a status page.
+type Status struct {
+	// Status is "ok" when every component passed its latest sample, "degraded" otherwise.
+	Status     string
+	StartedAt  time.Time
+	Since      time.Time // oldest sample, or StartedAt before the first one
+	Samples    int
+	Components []ComponentStatus
+}
+
+// ComponentStatus summarizes one check or heartbeat.
+type ComponentStatus struct {
+	Name string
+	Kind string // check or heartbeat
+	Up   bool   // passed the latest sample
+
+	// Uptime is the percentage of samples that passed, 0 to 100.
+	Uptime float64
+
+	// latency percentiles of the passing checks, nil for heartbeats or without a passing sample
+	LatencyP50 *time.Duration
+	LatencyP95 *time.Duration
+	LatencyP99 *time.Duration
+
+	// LastBeat is when a heartbeat last beat, nil for checks.
+	LastBeat *time.Time
+}
+
+// Status summarizes the history.
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := m.history()
+	status := Status{
+		Status:    "ok",
+		StartedAt: m.startedAt,
+		Since:     m.startedAt,
+		Samples:   len(history),
+	}
+	if len(history) > 0 {
+		status.Since = history[0].at
+	}
+
+	type tally struct {
+		passed    int
+		total     int
+		latest    bool
+		latencies []time.Duration
+	}
+	tallies := make(map[string]*tally)
+	for _, s := range history {
+		for _, r := range s.results {
+			t, ok := tallies[r.name]
+			if !ok {
+				t = &tally{}
+				tallies[r.name] = t
+			}
+			t.total++
+			t.latest = r.ok
+			if r.ok {
+				t.passed++
+				t.latencies = append(t.latencies, r.latency)
+			}
+		}
+	}
+
+	component := func(name, kind string) ComponentStatus {
+		c := ComponentStatus{Name: name, Kind: kind}
+		t, ok := tallies[name]
+		if !ok {
+			// registered after the last sample
+			c.Up = true
+			return c
+		}
+		c.Up = t.latest
+		c.Uptime = math.Round(float64(t.passed)/float64(t.total)*10000) / 100
+		if kind == "check" && len(t.latencies) > 0 {
+			sort.Slice(t.latencies, func(i, j int) bool { return t.latencies[i] < t.latencies[j] })
+			c.LatencyP50 = percentile(t.latencies, 0.50)
+			c.LatencyP95 = percentile(t.latencies, 0.95)
+			c.LatencyP99 = percentile(t.latencies, 0.99)
+		}
+		return c
+	}
+
+	for _, c := range m.checks {
+		status.Components = append(status.Components, component(c.name, "check"))
+	}
+	for _, name := range m.order {
+		c := component(name, "heartbeat")
+		last := m.heartbeats[name].last
+		c.LastBeat = &last
+		status.Components = append(status.Components, c)
+	}
+
+	for _, c := range status.Components {
+		if !c.Up {
+			status.Status = "degraded"
+			break
+		}
+	}
+	return status
+}
+
+// history returns the samples oldest first. must be called with the lock held.
+func (m *Monitor) history() []sample {
+	if !m.full {
+		return m.samples[:m.next]
+	}
+	ordered := make([]sample, 0, len(m.samples))
+	ordered = append(ordered, m.samples[m.next:]...)
+	return append(ordered, m.samples[:m.next]...)
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) *time.Duration {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	d := sorted[idx]
+	return &d
+}
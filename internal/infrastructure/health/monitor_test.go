@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+func newTestMonitor(samples int) *Monitor {
+	return NewMonitor(MonitorConfig{
+		Interval:  time.Second,
+		Retention: time.Duration(samples) * time.Second,
+		Timeout:   time.Second,
+	}, logging.NewWithWriter(io.Discard, slog.LevelError))
+}
+
+func TestMonitor_UptimeAndLatest(t *testing.T) {
+	m := newTestMonitor(10)
+	fail := false
+	m.AddCheck("database", func(context.Context) error {
+		if fail {
+			return errors.New("down")
+		}
+		return nil
+	})
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		fail = i == 3
+		m.probe(context.Background(), now.Add(time.Duration(i)*time.Second))
+	}
+
+	status := m.Status()
+	if status.Status != "degraded" {
+		t.Errorf("status = %q, want degraded", status.Status)
+	}
+	db := status.Components[0]
+	if db.Up {
+		t.Error("database should be down after a failed latest sample")
+	}
+	if db.Uptime != 75 {
+		t.Errorf("uptime = %v, want 75", db.Uptime)
+	}
+	if db.LatencyP50 == nil || db.LatencyP99 == nil {
+		t.Error("expected latency percentiles from the passing samples")
+	}
+}
+
+func TestMonitor_RingBufferKeepsRetention(t *testing.T) {
+	m := newTestMonitor(3)
+	m.AddCheck("redis", func(context.Context) error { return nil })
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		m.probe(context.Background(), start.Add(time.Duration(i)*time.Second))
+	}
+
+	status := m.Status()
+	if status.Samples != 3 {
+		t.Errorf("samples = %d, want 3", status.Samples)
+	}
+	if want := start.Add(2 * time.Second); !status.Since.Equal(want) {
+		t.Errorf("since = %v, want the oldest kept sample %v", status.Since, want)
+	}
+}
+
+func TestMonitor_StaleHeartbeatIsDown(t *testing.T) {
+	m := newTestMonitor(10)
+	m.ExpectHeartbeat("ingestion", time.Minute)
+
+	now := time.Now()
+	m.probe(context.Background(), now)
+	m.probe(context.Background(), now.Add(2*time.Minute))
+
+	hb := m.Status().Components[0]
+	if hb.Kind != "heartbeat" || hb.Up {
+		t.Errorf("got %+v, want a down heartbeat", hb)
+	}
+	if hb.Uptime != 50 {
+		t.Errorf("uptime = %v, want 50", hb.Uptime)
+	}
+
+	m.Beat("ingestion")
+	m.probe(context.Background(), time.Now())
+	if !m.Status().Components[0].Up {
+		t.Error("heartbeat should be up after a beat")
+	}
+}
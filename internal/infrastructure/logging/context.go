@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+)
+
+// fieldsKey is the context key for request-scoped log fields.
+type fieldsKey struct{}
+
+// contextFields are copied on every change so a context never sees another's fields.
+type contextFields struct {
+	requestID   string
+	userID      string
+	communityID string
+}
+
+func fieldsFrom(ctx context.Context) contextFields {
+	if f, ok := ctx.Value(fieldsKey{}).(contextFields); ok {
+		return f
+	}
+	return contextFields{}
+}
+
+// ContextWithRequestID returns a context whose loggers log request_id.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.requestID = requestID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// ContextWithUserID returns a context whose loggers log user_id (the authenticated user).
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.userID = userID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// ContextWithCommunityID returns a context whose loggers log community_id.
+func ContextWithCommunityID(ctx context.Context, communityID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.communityID = communityID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// WithContext returns a logger with the request_id, user_id and community_id
+// stored in ctx attached, so callers don't repeat them on every line.
+// fields that aren't set are left out.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	f := fieldsFrom(ctx)
+
+	var args []any
+	if f.requestID != "" {
+		args = append(args, "request_id", f.requestID)
+	}
+	if f.userID != "" {
+		args = append(args, "user_id", f.userID)
+	}
+	if f.communityID != "" {
+		args = append(args, "community_id", f.communityID)
+	}
+	if len(args) == 0 {
+		return l
+	}
+
+	return &Logger{
+		Logger: l.With(args...),
+		level:  l.level,
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestWithContext_AddsRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewWithWriter(&buf, slog.LevelInfo).WithComponent("test")
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithUserID(ctx, "user-1")
+	child := ContextWithCommunityID(ctx, "community-1")
+
+	logger.WithContext(child).Info("hello")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+	for key, want := range map[string]string{
+		"request_id":   "req-1",
+		"user_id":      "user-1",
+		"community_id": "community-1",
+		"component":    "test",
+	} {
+		if got := entry[key]; got != want {
+			t.Errorf("%s = %v, want %q", key, got, want)
+		}
+	}
+
+	// the parent context must not see the child's community
+	buf.Reset()
+	logger.WithContext(ctx).Info("parent")
+	var parent map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &parent); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+	if _, ok := parent["community_id"]; ok {
+		t.Error("community_id leaked into parent context")
+	}
+}
+
+func TestWithContext_NoFields(t *testing.T) {
+	logger := NewWithWriter(&bytes.Buffer{}, slog.LevelInfo)
+
+	if got := logger.WithContext(context.Background()); got != logger {
+		t.Error("expected the same logger when the context has no fields")
+	}
+}
@@ -1,42 +1,66 @@
 package logging
 
 import (
-	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 // Logger wraps slog for structured logging across the application.
 // keeps things simple, no fancy abstractions.
 type Logger struct {
 	*slog.Logger
+
+	// level is shared by every logger derived from the same root,
+	// so changing it at runtime affects all components at once.
+	level *slog.LevelVar
 }
 
 // New creates a new logger with JSON output for production use.
 func New() *Logger {
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	})
-	return &Logger{
-		Logger: slog.New(handler),
-	}
+	return NewWithLevel(slog.LevelInfo)
 }
 
 // NewWithLevel creates a logger with a specific log level.
 func NewWithLevel(level slog.Level) *Logger {
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
+	return NewWithWriter(os.Stdout, level)
+}
+
+// NewWithWriter creates a logger that writes JSON to w.
+// CLIs use this to keep logs on stderr and command output on stdout.
+func NewWithWriter(w io.Writer, level slog.Level) *Logger {
+	levelVar := &slog.LevelVar{}
+	levelVar.Set(level)
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: levelVar,
 	})
 	return &Logger{
 		Logger: slog.New(handler),
+		level:  levelVar,
 	}
 }
 
-// WithContext returns a logger with context values attached.
-func (l *Logger) WithContext(ctx context.Context) *Logger {
-	return &Logger{
-		Logger: l.Logger,
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: %w", s, err)
 	}
+	return level, nil
+}
+
+// SetLevel changes the minimum level for this logger and all loggers derived from it.
+// safe to call while other goroutines are logging.
+func (l *Logger) SetLevel(level slog.Level) {
+	l.level.Set(level)
+}
+
+// Level returns the current minimum log level.
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
 }
 
 // WithComponent returns a logger tagged with a component name.
@@ -44,6 +68,7 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 func (l *Logger) WithComponent(name string) *Logger {
 	return &Logger{
 		Logger: l.With("component", name),
+		level:  l.level,
 	}
 }
 
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// communityMomentum is the momentum gauge of the top communities, labelled by slug.
+// each update replaces the whole set and deletes the series of the communities that
+// dropped out, so the label set never outgrows one cycle's top.
+type communityMomentum struct {
+	mu       sync.Mutex
+	gauge    *prometheus.GaugeVec
+	labelled map[string]bool
+}
+
+func newCommunityMomentum(gauge *prometheus.GaugeVec) *communityMomentum {
+	return &communityMomentum{
+		gauge:    gauge,
+		labelled: make(map[string]bool),
+	}
+}
+
+// set replaces the gauge with momentum, by slug.
+func (c *communityMomentum) set(momentum map[string]float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for slug := range c.labelled {
+		if _, ok := momentum[slug]; !ok {
+			c.gauge.DeleteLabelValues(slug)
+			delete(c.labelled, slug)
+		}
+	}
+	for slug, value := range momentum {
+		c.gauge.WithLabelValues(slug).Set(value)
+		c.labelled[slug] = true
+	}
+}
@@ -1,10 +1,25 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
+const (
+	// nativeHistogramBucketFactor controls native histogram resolution.
+	// 1.1 means each bucket is at most 10% wider than the previous one.
+	nativeHistogramBucketFactor = 1.1
+
+	// nativeHistogramMaxBuckets caps memory per series; resolution is reduced past this.
+	nativeHistogramMaxBuckets = 160
+
+	// nativeHistogramMinResetDuration is how long to wait before resetting
+	// a histogram that hit the bucket cap.
+	nativeHistogramMinResetDuration = time.Hour
+)
+
 // Metrics holds all prometheus metrics for pulse.
 // uses a custom registry to avoid polluting the global namespace.
 type Metrics struct {
@@ -13,18 +28,115 @@ type Metrics struct {
 	// http_request_duration_seconds - histogram for api latency
 	HTTPRequestDuration *prometheus.HistogramVec
 
+	// http_response_size_bytes - histogram for response body sizes
+	HTTPResponseSize *prometheus.HistogramVec
+
+	// http_requests_in_flight - gauge for requests being served
+	HTTPRequestsInFlight prometheus.Gauge
+
 	// pulse_events_ingested_total - counter for ingested events
 	EventsIngestedTotal *prometheus.CounterVec
 
+	// pulse_community_events_ingested_total - counter for ingested events of the busiest communities,
+	// nil unless enabled with WithTopCommunities
+	CommunityEventsIngestedTotal *prometheus.CounterVec
+
+	// pulse_community_momentum - gauge for the momentum of the top communities by slug,
+	// nil unless enabled with WithCommunityMomentum
+	CommunityMomentum *prometheus.GaugeVec
+
+	// pulse_ingestion_batch_size - histogram for events saved per ingestion worker flush
+	IngestionBatchSize prometheus.Histogram
+
+	// pulse_ingestion_stale_events_total - counter for events quarantined after waiting too long in the buffer
+	StaleEventsTotal prometheus.Counter
+
 	// pulse_buffer_size - gauge for current event buffer size
 	BufferSize prometheus.Gauge
 
+	// pulse_buffer_capacity - gauge for the ingestion buffer capacity
+	BufferCapacity prometheus.Gauge
+
 	// pulse_momentum_calculation_duration_seconds - histogram for momentum worker
 	MomentumCalculationDuration prometheus.Histogram
+
+	// pulse_momentum_cycles_total - counter for momentum worker cycles by result
+	MomentumCyclesTotal *prometheus.CounterVec
+
+	// pulse_momentum_communities_total - counter for communities processed by momentum cycles, by result
+	MomentumCommunitiesTotal *prometheus.CounterVec
+
+	// pulse_momentum_spikes_total - counter for momentum spikes detected by the worker
+	MomentumSpikesTotal prometheus.Counter
+
+	// pulse_momentum_cycle_lag_seconds - gauge for the time between the last two cycle starts
+	MomentumCycleLag prometheus.Gauge
+
+	// pulse_momentum_last_success_timestamp_seconds - gauge for when the last cycle completed
+	MomentumLastSuccess prometheus.Gauge
+
+	// pulse_momentum_retries_total - counter for communities retried within a cycle after a transient failure
+	MomentumRetriesTotal prometheus.Counter
+
+	// pulse_momentum_stale_communities - gauge for communities that failed too many cycles in a row
+	MomentumStaleCommunities prometheus.Gauge
+
+	// pulse_worker_panics_total - counter for recovered worker goroutine panics
+	WorkerPanicsTotal *prometheus.CounterVec
+
+	// pulse_quota_exceeded_total - counter for events over a daily ingestion quota
+	QuotaExceededTotal *prometheus.CounterVec
+
+	// pulse_rate_limit_requests_total - counter for requests checked against a route group's rate limit
+	RateLimitRequestsTotal *prometheus.CounterVec
+
+	// pulse_leaderboard_resyncs_total - counter for automatic leaderboard rebuilds after redis lost its data
+	LeaderboardResyncsTotal *prometheus.CounterVec
+
+	// pulse_momentum_late_recalculations_total - counter for communities checked after late events
+	LateRecalculationsTotal *prometheus.CounterVec
+
+	topCommunities    *topCommunities
+	communityMomentum *communityMomentum
+}
+
+// Option configures optional metrics at construction.
+type Option func(*Metrics)
+
+// WithTopCommunities counts ingested events per community for the n busiest communities,
+// with the rest under community_id="other". n bounds the label's cardinality.
+func WithTopCommunities(n int) Option {
+	return func(m *Metrics) {
+		m.CommunityEventsIngestedTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_community_events_ingested_total",
+				Help: "Total number of activity events ingested for the busiest communities, the rest as other",
+			},
+			[]string{"community_id"},
+		)
+		m.topCommunities = newTopCommunities(n, m.CommunityEventsIngestedTotal)
+		m.Registry.MustRegister(m.CommunityEventsIngestedTotal)
+	}
+}
+
+// WithCommunityMomentum exports the momentum of the communities passed to
+// SetCommunityMomentum, which the momentum worker keeps to its top communities.
+func WithCommunityMomentum() Option {
+	return func(m *Metrics) {
+		m.CommunityMomentum = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pulse_community_momentum",
+				Help: "Momentum of the top public communities after the last momentum cycle",
+			},
+			[]string{"slug"},
+		)
+		m.communityMomentum = newCommunityMomentum(m.CommunityMomentum)
+		m.Registry.MustRegister(m.CommunityMomentum)
+	}
 }
 
 // New creates and registers all prometheus metrics.
-func New() *Metrics {
+func New(opts ...Option) *Metrics {
 	reg := prometheus.NewRegistry()
 
 	// add standard go runtime and process collectors
@@ -39,49 +151,220 @@ func New() *Metrics {
 				Name:    "http_request_duration_seconds",
 				Help:    "HTTP request duration in seconds",
 				Buckets: prometheus.DefBuckets,
+
+				// native histograms are exposed alongside the classic buckets,
+				// so scrapers without native histogram support keep working
+				NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  nativeHistogramMaxBuckets,
+				NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
 			},
 			[]string{"method", "path", "status"},
 		),
 
+		// 100B to 100MB, event payloads are small and exports are the large ones
+		HTTPResponseSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "http_response_size_bytes",
+				Help:    "HTTP response body size in bytes",
+				Buckets: prometheus.ExponentialBuckets(100, 10, 7),
+			},
+			[]string{"method", "path"},
+		),
+
+		HTTPRequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests being served, including open streams",
+		}),
+
+		// no community label: one series per community grows without bound.
+		// see WithTopCommunities for the busiest communities
 		EventsIngestedTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "pulse_events_ingested_total",
 				Help: "Total number of activity events ingested",
 			},
-			[]string{"community_id", "event_type"},
+			[]string{"event_type"},
 		),
 
+		IngestionBatchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "pulse_ingestion_batch_size",
+			Help:    "Number of events saved per ingestion worker flush",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 11), // 1 to 1024
+
+			NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+			NativeHistogramMaxBucketNumber:  nativeHistogramMaxBuckets,
+			NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
+		}),
+
+		StaleEventsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "pulse_ingestion_stale_events_total",
+			Help: "Total number of events not saved because they waited in the ingestion buffer past the max queue age",
+		}),
+
 		BufferSize: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "pulse_buffer_size",
 			Help: "Current number of events waiting in the ingestion buffer",
 		}),
 
+		BufferCapacity: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pulse_buffer_capacity",
+			Help: "Maximum number of events the ingestion buffer holds before rejecting",
+		}),
+
 		MomentumCalculationDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:    "pulse_momentum_calculation_duration_seconds",
 			Help:    "Duration of momentum calculation cycles in seconds",
 			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10), // 100ms to ~100s
+
+			NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+			NativeHistogramMaxBucketNumber:  nativeHistogramMaxBuckets,
+			NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
 		}),
+
+		MomentumCyclesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_momentum_cycles_total",
+				Help: "Total number of momentum worker cycles, by whether they completed",
+			},
+			[]string{"result"},
+		),
+
+		MomentumCommunitiesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_momentum_communities_total",
+				Help: "Total number of communities processed by momentum worker cycles, by result",
+			},
+			[]string{"result"},
+		),
+
+		MomentumSpikesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "pulse_momentum_spikes_total",
+			Help: "Total number of momentum spikes detected by the momentum worker",
+		}),
+
+		// alert when this grows well past the configured interval: cycles run long or are stuck
+		MomentumCycleLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pulse_momentum_cycle_lag_seconds",
+			Help: "Seconds between the starts of the last two momentum worker cycles",
+		}),
+
+		// time() minus this is how stale momentum is, whether cycles fail or stop running
+		MomentumLastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pulse_momentum_last_success_timestamp_seconds",
+			Help: "Unix time the last momentum worker cycle completed",
+		}),
+
+		MomentumRetriesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "pulse_momentum_retries_total",
+			Help: "Total number of retries of communities that failed transiently within a momentum worker cycle",
+		}),
+
+		MomentumStaleCommunities: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pulse_momentum_stale_communities",
+			Help: "Communities whose momentum calculation failed more cycles in a row than PULSE_MOMENTUM_STALE_AFTER_CYCLES",
+		}),
+
+		WorkerPanicsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_worker_panics_total",
+				Help: "Total number of panics recovered in background worker goroutines",
+			},
+			[]string{"worker"},
+		),
+
+		QuotaExceededTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_quota_exceeded_total",
+				Help: "Total number of events over a daily ingestion quota, by scope and whether they were rejected or degraded",
+			},
+			[]string{"scope", "mode"},
+		),
+
+		RateLimitRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_rate_limit_requests_total",
+				Help: "Total number of requests checked against a rate limit, by route group and result (allowed, limited or error)",
+			},
+			[]string{"group", "result"},
+		),
+
+		LeaderboardResyncsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_leaderboard_resyncs_total",
+				Help: "Total number of automatic leaderboard rebuilds after redis lost its data, by result",
+			},
+			[]string{"result"},
+		),
+
+		LateRecalculationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pulse_momentum_late_recalculations_total",
+				Help: "Total number of communities checked for late events, by result (recalculated, skipped or failure)",
+			},
+			[]string{"result"},
+		),
 	}
 
 	// register all custom metrics
 	reg.MustRegister(
 		m.HTTPRequestDuration,
+		m.HTTPResponseSize,
+		m.HTTPRequestsInFlight,
 		m.EventsIngestedTotal,
+		m.IngestionBatchSize,
+		m.StaleEventsTotal,
 		m.BufferSize,
+		m.BufferCapacity,
 		m.MomentumCalculationDuration,
+		m.MomentumCyclesTotal,
+		m.MomentumCommunitiesTotal,
+		m.MomentumSpikesTotal,
+		m.MomentumCycleLag,
+		m.MomentumLastSuccess,
+		m.MomentumRetriesTotal,
+		m.MomentumStaleCommunities,
+		m.WorkerPanicsTotal,
+		m.QuotaExceededTotal,
+		m.RateLimitRequestsTotal,
+		m.LeaderboardResyncsTotal,
+		m.LateRecalculationsTotal,
 	)
 
+	for _, opt := range opts {
+		opt(m)
+	}
 	return m
 }
 
-// RecordHTTPRequest records the duration of an HTTP request.
-func (m *Metrics) RecordHTTPRequest(method, path, status string, durationSeconds float64) {
-	m.HTTPRequestDuration.WithLabelValues(method, path, status).Observe(durationSeconds)
+// RecordHTTPRequest records the duration and response size of an HTTP request.
+// if traceID is set, it is attached to the duration as an exemplar.
+func (m *Metrics) RecordHTTPRequest(method, path, status string, durationSeconds float64, responseBytes int64, traceID string) {
+	observe(m.HTTPRequestDuration.WithLabelValues(method, path, status), durationSeconds, traceID)
+	m.HTTPResponseSize.WithLabelValues(method, path).Observe(float64(responseBytes))
+}
+
+// RecordEventsIngested adds count events of one type to the events ingested counter.
+func (m *Metrics) RecordEventsIngested(eventType string, count int) {
+	m.EventsIngestedTotal.WithLabelValues(eventType).Add(float64(count))
+}
+
+// RecordCommunityEventsIngested adds count events to a community's counter.
+// a no-op unless WithTopCommunities was passed.
+func (m *Metrics) RecordCommunityEventsIngested(communityID string, count int) {
+	if m.topCommunities == nil {
+		return
+	}
+	m.topCommunities.add(communityID, count)
+}
+
+// RecordIngestionBatch records the size of a saved ingestion batch.
+func (m *Metrics) RecordIngestionBatch(size int) {
+	m.IngestionBatchSize.Observe(float64(size))
 }
 
-// RecordEventIngested increments the events ingested counter.
-func (m *Metrics) RecordEventIngested(communityID, eventType string) {
-	m.EventsIngestedTotal.WithLabelValues(communityID, eventType).Inc()
+// RecordStaleEvents counts events dropped for waiting too long in the ingestion buffer.
+func (m *Metrics) RecordStaleEvents(count int) {
+	m.StaleEventsTotal.Add(float64(count))
 }
 
 // SetBufferSize sets the current buffer size gauge.
@@ -89,7 +372,93 @@ func (m *Metrics) SetBufferSize(size int) {
 	m.BufferSize.Set(float64(size))
 }
 
+// SetBufferCapacity sets the buffer capacity gauge.
+// with pulse_buffer_size this gives buffer saturation.
+func (m *Metrics) SetBufferCapacity(capacity int) {
+	m.BufferCapacity.Set(float64(capacity))
+}
+
 // RecordMomentumCalculation records the duration of a momentum calculation cycle.
-func (m *Metrics) RecordMomentumCalculation(durationSeconds float64) {
-	m.MomentumCalculationDuration.Observe(durationSeconds)
+// if traceID is set, it is attached to the observation as an exemplar.
+func (m *Metrics) RecordMomentumCalculation(durationSeconds float64, traceID string) {
+	observe(m.MomentumCalculationDuration, durationSeconds, traceID)
+}
+
+// RecordMomentumCycle records a completed momentum cycle and its per-community results.
+func (m *Metrics) RecordMomentumCycle(succeeded, failed, spikes int) {
+	m.MomentumCyclesTotal.WithLabelValues("success").Inc()
+	m.MomentumCommunitiesTotal.WithLabelValues("succeeded").Add(float64(succeeded))
+	m.MomentumCommunitiesTotal.WithLabelValues("failed").Add(float64(failed))
+	m.MomentumSpikesTotal.Add(float64(spikes))
+	m.MomentumLastSuccess.SetToCurrentTime()
+}
+
+// RecordMomentumRetries adds the retries of a momentum cycle.
+func (m *Metrics) RecordMomentumRetries(retries int) {
+	m.MomentumRetriesTotal.Add(float64(retries))
+}
+
+// SetMomentumStaleCommunities sets how many communities failed too many cycles in a row.
+func (m *Metrics) SetMomentumStaleCommunities(count int) {
+	m.MomentumStaleCommunities.Set(float64(count))
+}
+
+// SetCommunityMomentum replaces the per-community momentum gauge with momentum, by slug.
+// a no-op unless WithCommunityMomentum was passed.
+func (m *Metrics) SetCommunityMomentum(momentum map[string]float64) {
+	if m.communityMomentum == nil {
+		return
+	}
+	m.communityMomentum.set(momentum)
+}
+
+// RecordMomentumCycleFailure records a momentum cycle that failed before processing any community.
+func (m *Metrics) RecordMomentumCycleFailure() {
+	m.MomentumCyclesTotal.WithLabelValues("failure").Inc()
+}
+
+// SetMomentumCycleLag sets the time between the starts of the last two momentum cycles.
+func (m *Metrics) SetMomentumCycleLag(seconds float64) {
+	m.MomentumCycleLag.Set(seconds)
+}
+
+// RecordWorkerPanic increments the recovered panic counter for a worker.
+func (m *Metrics) RecordWorkerPanic(worker string) {
+	m.WorkerPanicsTotal.WithLabelValues(worker).Inc()
+}
+
+// RecordQuotaExceeded increments the quota exceeded counter.
+// scope is community or organization, mode is reject or degrade.
+func (m *Metrics) RecordQuotaExceeded(scope, mode string) {
+	m.QuotaExceededTotal.WithLabelValues(scope, mode).Inc()
+}
+
+// RecordRateLimit counts a request checked against a route group's rate limit.
+// result is allowed, limited or error.
+func (m *Metrics) RecordRateLimit(group, result string) {
+	m.RateLimitRequestsTotal.WithLabelValues(group, result).Inc()
+}
+
+// RecordLeaderboardResync records an automatic leaderboard rebuild. result is success or failure.
+func (m *Metrics) RecordLeaderboardResync(result string) {
+	m.LeaderboardResyncsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordLateRecalculation records a community checked for late events. result is recalculated, skipped or failure.
+func (m *Metrics) RecordLateRecalculation(result string) {
+	m.LateRecalculationsTotal.WithLabelValues(result).Inc()
+}
+
+// observe records a value, attaching a trace_id exemplar when one is available.
+// exemplars let you jump from a slow bucket straight to the offending trace.
+func observe(obs prometheus.Observer, value float64, traceID string) {
+	if traceID == "" {
+		obs.Observe(value)
+		return
+	}
+	if eo, ok := obs.(prometheus.ExemplarObserver); ok {
+		eo.ObserveWithExemplar(value, prometheus.Labels{"trace_id": traceID})
+		return
+	}
+	obs.Observe(value)
 }
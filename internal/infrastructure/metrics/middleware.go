@@ -1,42 +1,131 @@
 package metrics
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// unmatchedRoute is the path label used for requests that didn't hit a registered route.
+// scanners probing random urls would otherwise explode the label space.
+const unmatchedRoute = "unmatched"
+
 // Middleware returns an Echo middleware that records HTTP request metrics.
+// register it with Echo.Pre, so it wraps every other middleware: requests that
+// end in a recovered panic, a CORS preflight or a 404 are measured too.
 func Middleware(m *Metrics) echo.MiddlewareFunc {
+	// the allowlist is built from the router on first use, since routes are
+	// registered after the middleware and never change once the server runs
+	var (
+		once  sync.Once
+		known map[string]struct{}
+	)
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			once.Do(func() {
+				known = knownRoutes(c.Echo())
+			})
+
+			m.HTTPRequestsInFlight.Inc()
+			defer m.HTTPRequestsInFlight.Dec()
+
 			start := time.Now()
 
-			// process request
+			// process request, routing included: c.Path() is only set after this
 			err := next(c)
 
 			// record duration after request completes
 			duration := time.Since(start).Seconds()
-			status := strconv.Itoa(c.Response().Status)
+			status := strconv.Itoa(responseStatus(c, err))
 			method := c.Request().Method
-			path := normalizePath(c)
+			path := normalizePath(c, known)
 
-			m.RecordHTTPRequest(method, path, status, duration)
+			m.RecordHTTPRequest(method, path, status, duration, c.Response().Size, traceIDFromRequest(c))
 
 			return err
 		}
 	}
 }
 
+// responseStatus is the status the client gets. the request logger normally handles
+// errors before they get here; one that wasn't is answered by the error handler
+// afterwards, with the error's code or a 500.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// knownRoutes returns the allowlist of method+pattern pairs registered on the router.
+// 404 catch-all routes are registered under a special method, so they never match.
+func knownRoutes(e *echo.Echo) map[string]struct{} {
+	routes := e.Routes()
+	known := make(map[string]struct{}, len(routes))
+	for _, r := range routes {
+		known[routeKey(r.Method, r.Path)] = struct{}{}
+	}
+	return known
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
 // normalizePath extracts the route pattern rather than the actual path
 // to prevent high cardinality labels from things like IDs.
 // e.g. /api/v1/communities/123 becomes /api/v1/communities/:id
-func normalizePath(c echo.Context) string {
-	// use the matched route pattern if available
-	if path := c.Path(); path != "" {
-		return path
+// anything not in the known route allowlist collapses into a single "unmatched" label.
+func normalizePath(c echo.Context, known map[string]struct{}) string {
+	path := c.Path()
+	if path == "" {
+		return unmatchedRoute
+	}
+	if _, ok := known[routeKey(c.Request().Method, path)]; !ok {
+		return unmatchedRoute
+	}
+	return path
+}
+
+// traceIDFromRequest extracts the trace id from a W3C traceparent header.
+// format: version-traceid-parentid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+// returns empty string if the header is missing or malformed.
+func traceIDFromRequest(c echo.Context) string {
+	header := c.Request().Header.Get("traceparent")
+	if header == "" {
+		return ""
+	}
+
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || !isLowerHex(parts[1]) {
+		return ""
+	}
+
+	// all-zero trace id is invalid per spec
+	if strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+
+	return parts[1]
+}
+
+// isLowerHex reports whether s is made only of lowercase hex digits, as the spec requires.
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
 	}
-	// fallback to request path for unmatched routes (404s, etc)
-	return c.Request().URL.Path
+	return true
 }
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// otherCommunities is the community_id label shared by communities outside the top.
+	otherCommunities = "other"
+
+	// topCommunitiesInterval is how often the labelled communities are re-ranked.
+	topCommunitiesInterval = time.Minute
+)
+
+// topCommunities counts ingested events per community, labelling only the busiest ones.
+// the ranking is the events seen since the previous one, so a burst moves a community
+// in within a minute. a community that drops out has its series deleted, which keeps
+// the label set at most size plus "other".
+type topCommunities struct {
+	mu       sync.Mutex
+	size     int
+	counter  *prometheus.CounterVec
+	counts   map[string]int64 // events per community since the last ranking
+	labelled map[string]bool
+	rankedAt time.Time
+}
+
+func newTopCommunities(size int, counter *prometheus.CounterVec) *topCommunities {
+	return &topCommunities{
+		size:     size,
+		counter:  counter,
+		counts:   make(map[string]int64),
+		labelled: make(map[string]bool),
+	}
+}
+
+// add counts events for a community under its own label or "other".
+func (t *topCommunities) add(communityID string, count int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts[communityID] += int64(count)
+	if now := time.Now(); now.Sub(t.rankedAt) >= topCommunitiesInterval {
+		t.rank(now)
+	}
+
+	label := otherCommunities
+	if t.labelled[communityID] {
+		label = communityID
+	}
+	t.counter.WithLabelValues(label).Add(float64(count))
+}
+
+// rank replaces the labelled communities with the busiest since the last ranking.
+// must be called with the lock held.
+func (t *topCommunities) rank(now time.Time) {
+	ids := make([]string, 0, len(t.counts))
+	for id := range t.counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if t.counts[ids[i]] != t.counts[ids[j]] {
+			return t.counts[ids[i]] > t.counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > t.size {
+		ids = ids[:t.size]
+	}
+
+	next := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		next[id] = true
+	}
+	for id := range t.labelled {
+		if !next[id] {
+			t.counter.DeleteLabelValues(id)
+		}
+	}
+
+	t.labelled = next
+	t.counts = make(map[string]int64, len(t.counts))
+	t.rankedAt = now
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+const anomalyFlagColumns = `id, community_id, user_id, status, observed_events, expected_events, window_start, detected_at, reviewed_at, reviewed_by`
+
+// AnomalyFlagRepository implements domain.AnomalyFlagRepository using Postgres.
+type AnomalyFlagRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAnomalyFlagRepository creates a new AnomalyFlagRepository.
+func NewAnomalyFlagRepository(pool *pgxpool.Pool) *AnomalyFlagRepository {
+	return &AnomalyFlagRepository{pool: pool}
+}
+
+// Save inserts or updates a flag. only the review fields change after insert.
+func (r *AnomalyFlagRepository) Save(ctx context.Context, flag *domain.AnomalyFlag) error {
+	query := `
+		INSERT INTO pulse.anomaly_flags (` + anomalyFlagColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			reviewed_at = EXCLUDED.reviewed_at,
+			reviewed_by = EXCLUDED.reviewed_by
+	`
+
+	var userID *uuid.UUID
+	if u := flag.UserID(); u != nil {
+		id := u.UUID()
+		userID = &id
+	}
+
+	_, err := r.pool.Exec(ctx, query,
+		flag.ID(),
+		flag.CommunityID().UUID(),
+		userID,
+		flag.Status().String(),
+		flag.Observed(),
+		flag.Expected(),
+		flag.WindowStart(),
+		flag.DetectedAt(),
+		flag.ReviewedAt(),
+		flag.ReviewedBy(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving anomaly flag: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a flag by id.
+func (r *AnomalyFlagRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.AnomalyFlag, error) {
+	query := `SELECT ` + anomalyFlagColumns + ` FROM pulse.anomaly_flags WHERE id = $1`
+	return scanAnomalyFlag(r.pool.QueryRow(ctx, query, id))
+}
+
+// FindPending retrieves the pending flag for a community or a user in it.
+func (r *AnomalyFlagRepository) FindPending(ctx context.Context, communityID domain.CommunityID, userID *domain.UserID) (*domain.AnomalyFlag, error) {
+	query := `
+		SELECT ` + anomalyFlagColumns + `
+		FROM pulse.anomaly_flags
+		WHERE community_id = $1 AND user_id IS NOT DISTINCT FROM $2 AND status = 'pending'
+	`
+
+	var user *uuid.UUID
+	if userID != nil {
+		id := userID.UUID()
+		user = &id
+	}
+	return scanAnomalyFlag(r.pool.QueryRow(ctx, query, communityID.UUID(), user))
+}
+
+// List returns flags with the given status, newest first.
+func (r *AnomalyFlagRepository) List(ctx context.Context, status domain.AnomalyStatus, limit, offset int) ([]*domain.AnomalyFlag, error) {
+	query := `
+		SELECT ` + anomalyFlagColumns + `
+		FROM pulse.anomaly_flags
+		WHERE status = $1
+		ORDER BY detected_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, status.String(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing anomaly flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []*domain.AnomalyFlag
+	for rows.Next() {
+		flag, err := scanAnomalyFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+// SumQuarantinedWeights sums the weights of a community's quarantined events since the cutoff.
+// pending flags quarantine from their window start on, confirmed ones until they were reviewed.
+// flags match events by when they were received, the cutoff by when they occurred, like the momentum window.
+// the outer EXISTS lets the planner skip the events scan for communities without flags.
+func (r *AnomalyFlagRepository) SumQuarantinedWeights(ctx context.Context, communityID domain.CommunityID, since time.Time) (float64, error) {
+	const query = `
+		WITH flags AS (
+			SELECT user_id, window_start, reviewed_at
+			FROM pulse.anomaly_flags
+			WHERE community_id = $1
+			  AND (status = 'pending' OR (status = 'confirmed' AND reviewed_at > $2))
+		)
+		SELECT COALESCE(SUM(
+			CASE WHEN e.event_type = 'leave' THEN -e.weight ELSE e.weight END
+		), 0)
+		FROM pulse.activity_events e
+		WHERE EXISTS (SELECT 1 FROM flags)
+		  AND e.community_id = $1 AND e.occurred_at >= $2
+		  AND EXISTS (
+			SELECT 1 FROM flags f
+			WHERE e.created_at >= f.window_start
+			  AND (f.reviewed_at IS NULL OR e.created_at < f.reviewed_at)
+			  AND (f.user_id IS NULL OR f.user_id = e.user_id)
+		  )
+	`
+
+	var sum float64
+	if err := r.pool.QueryRow(ctx, query, communityID.UUID(), since).Scan(&sum); err != nil {
+		return 0, fmt.Errorf("summing quarantined weights: %w", err)
+	}
+	return sum, nil
+}
+
+// SumQuarantinedWeightsByRegion is SumQuarantinedWeights per region. events without a region are left out.
+func (r *AnomalyFlagRepository) SumQuarantinedWeightsByRegion(ctx context.Context, communityID domain.CommunityID, since time.Time) (map[string]float64, error) {
+	const query = `
+		WITH flags AS (
+			SELECT user_id, window_start, reviewed_at
+			FROM pulse.anomaly_flags
+			WHERE community_id = $1
+			  AND (status = 'pending' OR (status = 'confirmed' AND reviewed_at > $2))
+		)
+		SELECT e.region, SUM(
+			CASE WHEN e.event_type = 'leave' THEN -e.weight ELSE e.weight END
+		)
+		FROM pulse.activity_events e
+		WHERE EXISTS (SELECT 1 FROM flags)
+		  AND e.community_id = $1 AND e.occurred_at >= $2 AND e.region IS NOT NULL
+		  AND EXISTS (
+			SELECT 1 FROM flags f
+			WHERE e.created_at >= f.window_start
+			  AND (f.reviewed_at IS NULL OR e.created_at < f.reviewed_at)
+			  AND (f.user_id IS NULL OR f.user_id = e.user_id)
+		  )
+		GROUP BY e.region
+	`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID(), since)
+	if err != nil {
+		return nil, fmt.Errorf("summing quarantined weights by region: %w", err)
+	}
+	defer rows.Close()
+
+	sums := make(map[string]float64)
+	for rows.Next() {
+		var (
+			region string
+			sum    float64
+		)
+		if err := rows.Scan(&region, &sum); err != nil {
+			return nil, fmt.Errorf("scanning quarantined region weights: %w", err)
+		}
+		sums[region] = sum
+	}
+	return sums, rows.Err()
+}
+
+func scanAnomalyFlag(row pgx.Row) (*domain.AnomalyFlag, error) {
+	var (
+		id, communityID         uuid.UUID
+		userID                  *uuid.UUID
+		status, reviewedBy      string
+		observed                int64
+		expected                float64
+		windowStart, detectedAt time.Time
+		reviewedAt              *time.Time
+	)
+	err := row.Scan(&id, &communityID, &userID, &status, &observed, &expected, &windowStart, &detectedAt, &reviewedAt, &reviewedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning anomaly flag: %w", err)
+	}
+
+	var user *domain.UserID
+	if userID != nil {
+		u := domain.UserIDFromUUID(*userID)
+		user = &u
+	}
+
+	return domain.ReconstructAnomalyFlag(
+		id,
+		domain.CommunityIDFromUUID(communityID),
+		user,
+		domain.AnomalyStatus(status),
+		observed,
+		expected,
+		windowStart,
+		detectedAt,
+		reviewedAt,
+		reviewedBy,
+	), nil
+}
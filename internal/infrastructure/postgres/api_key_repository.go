@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// APIKeyRepository implements domain.APIKeyRepository using Postgres.
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository.
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+// Save persists a new api key.
+func (r *APIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	const query = `
+		INSERT INTO pulse.api_keys (id, user_id, organization_id, name, key_hash, key_hint, created_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		key.ID(),
+		key.UserID().UUID(),
+		nullableOrganizationID(key.OrganizationID()),
+		key.Name(),
+		key.Hash(),
+		key.Hint(),
+		key.CreatedAt(),
+		key.RevokedAt(),
+	)
+	return err
+}
+
+// FindByHash retrieves a key by the hash of its plaintext.
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	const query = `
+		SELECT id, user_id, organization_id, name, key_hash, key_hint, created_at, revoked_at
+		FROM pulse.api_keys
+		WHERE key_hash = $1
+	`
+
+	var (
+		id        uuid.UUID
+		userID    uuid.UUID
+		orgID     *uuid.UUID
+		name      string
+		keyHash   string
+		keyHint   string
+		createdAt time.Time
+		revokedAt *time.Time
+	)
+
+	err := r.pool.QueryRow(ctx, query, hash).Scan(&id, &userID, &orgID, &name, &keyHash, &keyHint, &createdAt, &revokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var organizationID *domain.OrganizationID
+	if orgID != nil {
+		parsed := domain.OrganizationIDFromUUID(*orgID)
+		organizationID = &parsed
+	}
+
+	return domain.ReconstructAPIKey(id, domain.UserIDFromUUID(userID), organizationID, name, keyHash, keyHint, createdAt, revokedAt), nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityEventWeightsRepository implements domain.CommunityEventWeightsRepository using Postgres.
+type CommunityEventWeightsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunityEventWeightsRepository creates a new CommunityEventWeightsRepository.
+func NewCommunityEventWeightsRepository(pool *pgxpool.Pool) *CommunityEventWeightsRepository {
+	return &CommunityEventWeightsRepository{pool: pool}
+}
+
+// FindByCommunity retrieves the event weight overrides for a community.
+func (r *CommunityEventWeightsRepository) FindByCommunity(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityEventWeights, error) {
+	const query = `
+		SELECT weights, updated_at
+		FROM pulse.community_event_weights
+		WHERE community_id = $1
+	`
+
+	var (
+		weightsJSON []byte
+		updatedAt   time.Time
+	)
+
+	err := r.pool.QueryRow(ctx, query, communityID.UUID()).Scan(&weightsJSON, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored map[string]float64
+	if err := json.Unmarshal(weightsJSON, &stored); err != nil {
+		return nil, fmt.Errorf("parsing event weights: %w", err)
+	}
+	weights := make(map[domain.EventType]domain.Weight, len(stored))
+	for et, v := range stored {
+		w, err := domain.NewWeight(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %s: %w", et, err)
+		}
+		weights[domain.EventType(et)] = w
+	}
+
+	return domain.ReconstructCommunityEventWeights(communityID, weights, updatedAt), nil
+}
+
+// Save persists event weight overrides (insert or update), replacing the previous ones.
+func (r *CommunityEventWeightsRepository) Save(ctx context.Context, weights *domain.CommunityEventWeights) error {
+	const query = `
+		INSERT INTO pulse.community_event_weights (community_id, weights, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (community_id) DO UPDATE SET
+			weights = EXCLUDED.weights,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	stored := make(map[string]float64, len(weights.Weights()))
+	for et, w := range weights.Weights() {
+		stored[et.String()] = w.Value()
+	}
+	weightsJSON, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("serializing event weights: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, query,
+		weights.CommunityID().UUID(),
+		string(weightsJSON),
+		weights.UpdatedAt(),
+	)
+	return err
+}
+
+// Delete removes event weight overrides for a community.
+// deleting a community that has no overrides is not an error.
+func (r *CommunityEventWeightsRepository) Delete(ctx context.Context, communityID domain.CommunityID) error {
+	const query = `DELETE FROM pulse.community_event_weights WHERE community_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, communityID.UUID())
+	return err
+}
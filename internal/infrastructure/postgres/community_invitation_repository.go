@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+const invitationColumns = `id, community_id, created_by, token_hash, hint, expires_at, created_at, revoked_at, accepted_by, accepted_at`
+
+// CommunityInvitationRepository implements domain.CommunityInvitationRepository using Postgres.
+type CommunityInvitationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunityInvitationRepository creates a new CommunityInvitationRepository.
+func NewCommunityInvitationRepository(pool *pgxpool.Pool) *CommunityInvitationRepository {
+	return &CommunityInvitationRepository{pool: pool}
+}
+
+// Create persists a new invitation.
+func (r *CommunityInvitationRepository) Create(ctx context.Context, inv *domain.CommunityInvitation) error {
+	const query = `
+		INSERT INTO pulse.community_invitations (id, community_id, created_by, token_hash, hint, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		inv.ID(),
+		inv.CommunityID().UUID(),
+		inv.CreatedBy().UUID(),
+		inv.TokenHash(),
+		inv.Hint(),
+		inv.ExpiresAt(),
+		inv.CreatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving invitation: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves an invitation by its id.
+func (r *CommunityInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.CommunityInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM pulse.community_invitations WHERE id = $1`
+	return scanInvitation(r.pool.QueryRow(ctx, query, id))
+}
+
+// FindByTokenHash retrieves an invitation by the sha256 of its token.
+func (r *CommunityInvitationRepository) FindByTokenHash(ctx context.Context, hash string) (*domain.CommunityInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM pulse.community_invitations WHERE token_hash = $1`
+	return scanInvitation(r.pool.QueryRow(ctx, query, hash))
+}
+
+// ListByCommunity returns a community's invitations, newest first.
+func (r *CommunityInvitationRepository) ListByCommunity(ctx context.Context, communityID domain.CommunityID) ([]*domain.CommunityInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM pulse.community_invitations WHERE community_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID())
+	if err != nil {
+		return nil, fmt.Errorf("listing invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []*domain.CommunityInvitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
+
+// Revoke stores the revocation of a pending invitation.
+func (r *CommunityInvitationRepository) Revoke(ctx context.Context, inv *domain.CommunityInvitation) error {
+	const query = `
+		UPDATE pulse.community_invitations
+		SET revoked_at = $2
+		WHERE id = $1 AND accepted_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, inv.ID(), inv.RevokedAt())
+	if err != nil {
+		return fmt.Errorf("revoking invitation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrInvitationUsed
+	}
+	return nil
+}
+
+// Accept marks the invitation used and adds the member in one transaction.
+// the update only matches a still pending invitation, so two users racing
+// for the same token can't both join.
+func (r *CommunityInvitationRepository) Accept(ctx context.Context, inv *domain.CommunityInvitation, member *domain.CommunityMember) error {
+	const markAccepted = `
+		UPDATE pulse.community_invitations
+		SET accepted_by = $2, accepted_at = $3
+		WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+	`
+	const insertMember = `
+		INSERT INTO pulse.community_members (community_id, user_id, joined_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (community_id, user_id) DO NOTHING
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, markAccepted, inv.ID(), inv.AcceptedBy().UUID(), inv.AcceptedAt())
+	if err != nil {
+		return fmt.Errorf("accepting invitation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrInvitationUsed
+	}
+
+	if _, err := tx.Exec(ctx, insertMember, member.CommunityID().UUID(), member.UserID().UUID(), member.JoinedAt()); err != nil {
+		return fmt.Errorf("saving community member: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanInvitation(row pgx.Row) (*domain.CommunityInvitation, error) {
+	var (
+		id, communityID, createdBy uuid.UUID
+		tokenHash, hint            string
+		expiresAt, createdAt       time.Time
+		revokedAt, acceptedAt      *time.Time
+		acceptedBy                 *uuid.UUID
+	)
+	err := row.Scan(&id, &communityID, &createdBy, &tokenHash, &hint, &expiresAt, &createdAt, &revokedAt, &acceptedBy, &acceptedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning invitation: %w", err)
+	}
+
+	var acceptedUser *domain.UserID
+	if acceptedBy != nil {
+		u := domain.UserIDFromUUID(*acceptedBy)
+		acceptedUser = &u
+	}
+
+	return domain.ReconstructCommunityInvitation(
+		id,
+		domain.CommunityIDFromUUID(communityID),
+		domain.UserIDFromUUID(createdBy),
+		tokenHash,
+		hint,
+		expiresAt,
+		createdAt,
+		revokedAt,
+		acceptedUser,
+		acceptedAt,
+	), nil
+}
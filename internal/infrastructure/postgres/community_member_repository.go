@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityMemberRepository implements domain.CommunityMemberRepository using Postgres.
+type CommunityMemberRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunityMemberRepository creates a new CommunityMemberRepository.
+func NewCommunityMemberRepository(pool *pgxpool.Pool) *CommunityMemberRepository {
+	return &CommunityMemberRepository{pool: pool}
+}
+
+// FindMember retrieves a user's membership in a community.
+func (r *CommunityMemberRepository) FindMember(ctx context.Context, communityID domain.CommunityID, userID domain.UserID) (*domain.CommunityMember, error) {
+	const query = `
+		SELECT role, joined_at
+		FROM pulse.community_members
+		WHERE community_id = $1 AND user_id = $2
+	`
+
+	var (
+		role     string
+		joinedAt time.Time
+	)
+	err := r.pool.QueryRow(ctx, query, communityID.UUID(), userID.UUID()).Scan(&role, &joinedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding community member: %w", err)
+	}
+	return domain.ReconstructCommunityMember(communityID, userID, domain.CommunityRole(role), joinedAt), nil
+}
+
+// ListMembers returns members, longest-standing first.
+func (r *CommunityMemberRepository) ListMembers(ctx context.Context, communityID domain.CommunityID, limit, offset int) ([]*domain.CommunityMember, error) {
+	const query = `
+		SELECT user_id, role, joined_at
+		FROM pulse.community_members
+		WHERE community_id = $1
+		ORDER BY joined_at, user_id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing community members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*domain.CommunityMember
+	for rows.Next() {
+		var (
+			userID   uuid.UUID
+			role     string
+			joinedAt time.Time
+		)
+		if err := rows.Scan(&userID, &role, &joinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, domain.ReconstructCommunityMember(communityID, domain.UserIDFromUUID(userID), domain.CommunityRole(role), joinedAt))
+	}
+	return members, rows.Err()
+}
+
+// UpdateRole stores a member's role.
+func (r *CommunityMemberRepository) UpdateRole(ctx context.Context, member *domain.CommunityMember) error {
+	const query = `
+		UPDATE pulse.community_members
+		SET role = $3
+		WHERE community_id = $1 AND user_id = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, member.CommunityID().UUID(), member.UserID().UUID(), member.Role().String())
+	if err != nil {
+		return fmt.Errorf("updating community member role: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// RemoveMember deletes a membership.
+func (r *CommunityMemberRepository) RemoveMember(ctx context.Context, communityID domain.CommunityID, userID domain.UserID) error {
+	const query = `DELETE FROM pulse.community_members WHERE community_id = $1 AND user_id = $2`
+
+	_, err := r.pool.Exec(ctx, query, communityID.UUID(), userID.UUID())
+	return err
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityPinRepository implements domain.CommunityPinRepository using Postgres.
+type CommunityPinRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunityPinRepository creates a new CommunityPinRepository.
+func NewCommunityPinRepository(pool *pgxpool.Pool) *CommunityPinRepository {
+	return &CommunityPinRepository{pool: pool}
+}
+
+// Save persists a new pin.
+func (r *CommunityPinRepository) Save(ctx context.Context, pin *domain.CommunityPin) error {
+	const query = `
+		INSERT INTO pulse.community_pins (id, community_id, starts_at, ends_at, sponsored, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		pin.ID(),
+		pin.CommunityID().UUID(),
+		pin.StartsAt(),
+		pin.EndsAt(),
+		pin.Sponsored(),
+		pin.CreatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving community pin: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a pin.
+func (r *CommunityPinRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM pulse.community_pins WHERE id = $1`
+
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting community pin: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListEndingAfter returns the pins still active or scheduled at t, by start time.
+func (r *CommunityPinRepository) ListEndingAfter(ctx context.Context, t time.Time) ([]*domain.CommunityPin, error) {
+	const query = `
+		SELECT id, community_id, starts_at, ends_at, sponsored, created_at
+		FROM pulse.community_pins
+		WHERE ends_at > $1
+		ORDER BY starts_at, created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, t)
+	if err != nil {
+		return nil, fmt.Errorf("listing community pins: %w", err)
+	}
+	defer rows.Close()
+
+	var pins []*domain.CommunityPin
+	for rows.Next() {
+		pin, err := scanCommunityPin(rows)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+	return pins, rows.Err()
+}
+
+func scanCommunityPin(row pgx.Row) (*domain.CommunityPin, error) {
+	var (
+		id, communityID           uuid.UUID
+		startsAt, endsAt, created time.Time
+		sponsored                 bool
+	)
+	if err := row.Scan(&id, &communityID, &startsAt, &endsAt, &sponsored, &created); err != nil {
+		return nil, fmt.Errorf("scanning community pin: %w", err)
+	}
+	return domain.ReconstructCommunityPin(id, domain.CommunityIDFromUUID(communityID), startsAt, endsAt, sponsored, created), nil
+}
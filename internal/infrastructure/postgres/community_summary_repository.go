@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunitySummaryRepository implements domain.CommunitySummaryRepository using Postgres.
+type CommunitySummaryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunitySummaryRepository creates a new CommunitySummaryRepository.
+func NewCommunitySummaryRepository(pool *pgxpool.Pool) *CommunitySummaryRepository {
+	return &CommunitySummaryRepository{pool: pool}
+}
+
+// Refresh rewrites every community's summary. events are only read for the summary
+// window, through the occurred_at index, so last_activity_at keeps the previous value
+// when a community had no event since.
+func (r *CommunitySummaryRepository) Refresh(ctx context.Context, now time.Time) (int64, error) {
+	const query = `
+		INSERT INTO pulse.community_summaries (community_id, member_count, events_last_24h, last_activity_at, refreshed_at)
+		SELECT c.id,
+		       COALESCE(m.members, 0),
+		       COALESCE(e.events, 0),
+		       GREATEST(s.last_activity_at, e.last_at),
+		       $2
+		FROM pulse.communities c
+		LEFT JOIN (
+			SELECT community_id, COUNT(*) AS members
+			FROM pulse.community_members
+			GROUP BY community_id
+		) m ON m.community_id = c.id
+		LEFT JOIN (
+			SELECT community_id, COUNT(*) AS events, MAX(occurred_at) AS last_at
+			FROM pulse.activity_events
+			WHERE occurred_at >= $1 AND occurred_at <= $2
+			GROUP BY community_id
+		) e ON e.community_id = c.id
+		LEFT JOIN pulse.community_summaries s ON s.community_id = c.id
+		ON CONFLICT (community_id) DO UPDATE SET
+			member_count = EXCLUDED.member_count,
+			events_last_24h = EXCLUDED.events_last_24h,
+			last_activity_at = EXCLUDED.last_activity_at,
+			refreshed_at = EXCLUDED.refreshed_at
+	`
+
+	tag, err := r.pool.Exec(ctx, query, now.Add(-domain.CommunitySummaryWindow), now)
+	if err != nil {
+		return 0, fmt.Errorf("refreshing community summaries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// FindByCommunityIDs returns the summaries of the communities that have one.
+func (r *CommunitySummaryRepository) FindByCommunityIDs(ctx context.Context, ids []domain.CommunityID) (map[domain.CommunityID]*domain.CommunitySummary, error) {
+	summaries := make(map[domain.CommunityID]*domain.CommunitySummary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	uuids := make([]string, len(ids))
+	for i, id := range ids {
+		uuids[i] = id.String()
+	}
+
+	const query = `
+		SELECT community_id, member_count, events_last_24h, last_activity_at, refreshed_at
+		FROM pulse.community_summaries
+		WHERE community_id = ANY($1)
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("finding community summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			communityID    uuid.UUID
+			memberCount    int
+			eventsLastDay  int64
+			lastActivityAt *time.Time
+			refreshedAt    time.Time
+		)
+		if err := rows.Scan(&communityID, &memberCount, &eventsLastDay, &lastActivityAt, &refreshedAt); err != nil {
+			return nil, fmt.Errorf("scanning community summary: %w", err)
+		}
+		id := domain.CommunityIDFromUUID(communityID)
+		summaries[id] = domain.ReconstructCommunitySummary(id, memberCount, eventsLastDay, lastActivityAt, refreshedAt)
+	}
+	return summaries, rows.Err()
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityWebhookAllowlistRepository implements domain.CommunityWebhookAllowlistRepository using Postgres.
+type CommunityWebhookAllowlistRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunityWebhookAllowlistRepository creates a new CommunityWebhookAllowlistRepository.
+func NewCommunityWebhookAllowlistRepository(pool *pgxpool.Pool) *CommunityWebhookAllowlistRepository {
+	return &CommunityWebhookAllowlistRepository{pool: pool}
+}
+
+// FindByCommunity retrieves the webhook allowlist for a community.
+func (r *CommunityWebhookAllowlistRepository) FindByCommunity(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityWebhookAllowlist, error) {
+	const query = `
+		SELECT domains, updated_at
+		FROM pulse.community_webhook_allowlists
+		WHERE community_id = $1
+	`
+
+	var (
+		domains   []string
+		updatedAt time.Time
+	)
+
+	err := r.pool.QueryRow(ctx, query, communityID.UUID()).Scan(&domains, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.ReconstructCommunityWebhookAllowlist(communityID, domains, updatedAt), nil
+}
+
+// Save persists a webhook allowlist (insert or update), replacing the previous one.
+func (r *CommunityWebhookAllowlistRepository) Save(ctx context.Context, allowlist *domain.CommunityWebhookAllowlist) error {
+	const query = `
+		INSERT INTO pulse.community_webhook_allowlists (community_id, domains, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (community_id) DO UPDATE SET
+			domains = EXCLUDED.domains,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		allowlist.CommunityID().UUID(),
+		allowlist.Domains(),
+		allowlist.UpdatedAt(),
+	)
+	return err
+}
+
+// Delete removes the webhook allowlist for a community.
+// deleting a community that has none is not an error.
+func (r *CommunityWebhookAllowlistRepository) Delete(ctx context.Context, communityID domain.CommunityID) error {
+	const query = `DELETE FROM pulse.community_webhook_allowlists WHERE community_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, communityID.UUID())
+	return err
+}
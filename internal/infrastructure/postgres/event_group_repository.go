@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// EventGroupRepository implements domain.EventGroupRepository using Postgres.
+// joins the unit of work's transaction when the context has one.
+type EventGroupRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewEventGroupRepository creates a new EventGroupRepository.
+func NewEventGroupRepository(pool *pgxpool.Pool) *EventGroupRepository {
+	return &EventGroupRepository{pool: pool}
+}
+
+// FindGroup returns the ids of the events saved under the key since the given time.
+func (r *EventGroupRepository) FindGroup(ctx context.Context, scope string, key domain.IdempotencyKey, since time.Time) ([]domain.EventID, error) {
+	const query = `
+		SELECT event_ids
+		FROM pulse.event_groups
+		WHERE scope = $1 AND idempotency_key = $2 AND created_at >= $3
+	`
+
+	var ids []uuid.UUID
+	err := GetQuerier(ctx, r.pool).QueryRow(ctx, query, scope, key.String(), since).Scan(&ids)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding event group: %w", err)
+	}
+	return toEventIDs(ids), nil
+}
+
+// SaveGroup records the key, replacing one older than since. a concurrent request with the
+// same key waits on the row lock until the first one commits or rolls back.
+func (r *EventGroupRepository) SaveGroup(ctx context.Context, scope string, key domain.IdempotencyKey, ids []domain.EventID, since time.Time) ([]domain.EventID, error) {
+	const query = `
+		INSERT INTO pulse.event_groups (scope, idempotency_key, event_ids, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET event_ids = EXCLUDED.event_ids, created_at = EXCLUDED.created_at
+		WHERE pulse.event_groups.created_at < $4
+	`
+
+	uuids := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		uuids[i] = id.UUID()
+	}
+
+	tag, err := GetQuerier(ctx, r.pool).Exec(ctx, query, scope, key.String(), uuids, since)
+	if err != nil {
+		return nil, fmt.Errorf("saving event group: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return ids, nil
+	}
+
+	// the key is still in use, answer with the events saved under it
+	existing, err := r.FindGroup(ctx, scope, key, since)
+	if err != nil {
+		return nil, err
+	}
+	return existing, domain.ErrDuplicateEventGroup
+}
+
+func toEventIDs(ids []uuid.UUID) []domain.EventID {
+	eventIDs := make([]domain.EventID, len(ids))
+	for i, id := range ids {
+		eventIDs[i] = domain.EventIDFromUUID(id)
+	}
+	return eventIDs
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// EventImportRepository implements domain.EventImportRepository using Postgres.
+type EventImportRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewEventImportRepository creates a new EventImportRepository.
+func NewEventImportRepository(pool *pgxpool.Pool) *EventImportRepository {
+	return &EventImportRepository{pool: pool}
+}
+
+// eventImportRowErrorJSON is how a skipped row is stored in the row_errors column.
+type eventImportRowErrorJSON struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// Save persists an import (insert or update).
+func (r *EventImportRepository) Save(ctx context.Context, imp *domain.EventImport) error {
+	const query = `
+		INSERT INTO pulse.event_imports (id, community_id, requested_by, format, status, imported, rejected, row_errors, error, created_at, updated_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			imported = EXCLUDED.imported,
+			rejected = EXCLUDED.rejected,
+			row_errors = EXCLUDED.row_errors,
+			error = EXCLUDED.error,
+			updated_at = EXCLUDED.updated_at,
+			finished_at = EXCLUDED.finished_at
+	`
+
+	rowErrors := make([]eventImportRowErrorJSON, len(imp.RowErrors()))
+	for i, e := range imp.RowErrors() {
+		rowErrors[i] = eventImportRowErrorJSON{Line: e.Line, Reason: e.Reason}
+	}
+	rowErrorsJSON, err := json.Marshal(rowErrors)
+	if err != nil {
+		return fmt.Errorf("serializing event import errors: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, query,
+		imp.ID(),
+		imp.CommunityID().UUID(),
+		imp.RequestedBy().UUID(),
+		imp.Format().String(),
+		imp.Status().String(),
+		imp.Imported(),
+		imp.Rejected(),
+		string(rowErrorsJSON),
+		imp.Err(),
+		imp.CreatedAt(),
+		imp.UpdatedAt(),
+		imp.FinishedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving event import: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns an import, domain.ErrNotFound if there's none.
+func (r *EventImportRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.EventImport, error) {
+	const query = `
+		SELECT community_id, requested_by, format, status, imported, rejected, row_errors, error, created_at, updated_at, finished_at
+		FROM pulse.event_imports
+		WHERE id = $1
+	`
+
+	var (
+		communityID, requestedBy  uuid.UUID
+		format, status, importErr string
+		imported, rejected        int64
+		rowErrorsJSON             []byte
+		createdAt, updatedAt      time.Time
+		finishedAt                *time.Time
+	)
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&communityID, &requestedBy, &format, &status, &imported, &rejected, &rowErrorsJSON, &importErr, &createdAt, &updatedAt, &finishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding event import: %w", err)
+	}
+
+	var stored []eventImportRowErrorJSON
+	if err := json.Unmarshal(rowErrorsJSON, &stored); err != nil {
+		return nil, fmt.Errorf("parsing event import errors: %w", err)
+	}
+	rowErrors := make([]domain.EventImportRowError, len(stored))
+	for i, e := range stored {
+		rowErrors[i] = domain.EventImportRowError{Line: e.Line, Reason: e.Reason}
+	}
+
+	return domain.ReconstructEventImport(
+		id,
+		domain.CommunityIDFromUUID(communityID),
+		domain.UserIDFromUUID(requestedBy),
+		domain.EventImportFormat(format),
+		domain.EventImportStatus(status),
+		imported,
+		rejected,
+		rowErrors,
+		importErr,
+		createdAt,
+		updatedAt,
+		finishedAt,
+	), nil
+}
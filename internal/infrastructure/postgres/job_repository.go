@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// JobRepository implements domain.JobRepository using Postgres.
+type JobRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewJobRepository creates a new JobRepository.
+func NewJobRepository(pool *pgxpool.Pool) *JobRepository {
+	return &JobRepository{pool: pool}
+}
+
+// Save persists a job (insert or update).
+func (r *JobRepository) Save(ctx context.Context, job *domain.Job) error {
+	const query = `
+		INSERT INTO pulse.jobs (id, kind, owner_id, status, done, total, error, created_at, updated_at, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			done = EXCLUDED.done,
+			total = EXCLUDED.total,
+			error = EXCLUDED.error,
+			updated_at = EXCLUDED.updated_at,
+			started_at = EXCLUDED.started_at,
+			finished_at = EXCLUDED.finished_at
+	`
+
+	var ownerID *uuid.UUID
+	if o := job.OwnerID(); o != nil {
+		id := o.UUID()
+		ownerID = &id
+	}
+
+	_, err := r.pool.Exec(ctx, query,
+		job.ID(),
+		job.Kind().String(),
+		ownerID,
+		job.Status().String(),
+		job.Done(),
+		job.Total(),
+		job.Err(),
+		job.CreatedAt(),
+		job.UpdatedAt(),
+		job.StartedAt(),
+		job.FinishedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving job: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns a job, domain.ErrNotFound if there's none.
+func (r *JobRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	const query = `
+		SELECT kind, owner_id, status, done, total, error, created_at, updated_at, started_at, finished_at
+		FROM pulse.jobs
+		WHERE id = $1
+	`
+
+	var (
+		kind, status, jobErr  string
+		ownerID               *uuid.UUID
+		done, total           int64
+		createdAt, updatedAt  time.Time
+		startedAt, finishedAt *time.Time
+	)
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&kind, &ownerID, &status, &done, &total, &jobErr, &createdAt, &updatedAt, &startedAt, &finishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding job: %w", err)
+	}
+
+	var owner *domain.UserID
+	if ownerID != nil {
+		o := domain.UserIDFromUUID(*ownerID)
+		owner = &o
+	}
+
+	return domain.ReconstructJob(
+		id,
+		domain.JobKind(kind),
+		owner,
+		domain.JobStatus(status),
+		done,
+		total,
+		jobErr,
+		createdAt,
+		updatedAt,
+		startedAt,
+		finishedAt,
+	), nil
+}
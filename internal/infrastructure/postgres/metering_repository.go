@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// MeteringRepository implements domain.MeteringRepository using Postgres.
+type MeteringRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMeteringRepository creates a new MeteringRepository.
+func NewMeteringRepository(pool *pgxpool.Pool) *MeteringRepository {
+	return &MeteringRepository{pool: pool}
+}
+
+// SaveBatch inserts metering records with COPY.
+func (r *MeteringRepository) SaveBatch(ctx context.Context, records []*domain.MeteringRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(records))
+	for i, record := range records {
+		rows[i] = []any{
+			record.ID(),
+			record.Subject().String(),
+			record.SubjectID(),
+			record.Metric().String(),
+			record.Quantity(),
+			record.PeriodStart(),
+			record.PeriodEnd(),
+			record.CreatedAt(),
+		}
+	}
+
+	_, err := r.pool.CopyFrom(
+		ctx,
+		pgx.Identifier{"pulse", "metering_records"},
+		[]string{"id", "subject", "subject_id", "metric", "quantity", "period_start", "period_end", "created_at"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("copying metering records: %w", err)
+	}
+	return nil
+}
+
+// ListByPeriod returns records whose period starts in [from, to), oldest first.
+func (r *MeteringRepository) ListByPeriod(ctx context.Context, from, to time.Time) ([]*domain.MeteringRecord, error) {
+	const query = `
+		SELECT id, subject, subject_id, metric, quantity, period_start, period_end, created_at
+		FROM pulse.metering_records
+		WHERE period_start >= $1 AND period_start < $2
+		ORDER BY period_start, subject, subject_id, metric
+	`
+
+	rows, err := r.pool.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*domain.MeteringRecord
+	for rows.Next() {
+		var (
+			id                              uuid.UUID
+			subject, subjectID, metric      string
+			quantity                        int64
+			periodStart, periodEnd, created time.Time
+		)
+		if err := rows.Scan(&id, &subject, &subjectID, &metric, &quantity, &periodStart, &periodEnd, &created); err != nil {
+			return nil, err
+		}
+		records = append(records, domain.ReconstructMeteringRecord(
+			id,
+			domain.MeterSubject(subject),
+			subjectID,
+			domain.MeterMetric(metric),
+			quantity,
+			periodStart,
+			periodEnd,
+			created,
+		))
+	}
+	return records, rows.Err()
+}
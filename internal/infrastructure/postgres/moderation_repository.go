@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+const sanctionColumns = `community_id, user_id, kind, reason, issued_by, created_at, expires_at`
+
+// ModerationRepository implements domain.ModerationRepository using Postgres.
+type ModerationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewModerationRepository creates a new ModerationRepository.
+func NewModerationRepository(pool *pgxpool.Pool) *ModerationRepository {
+	return &ModerationRepository{pool: pool}
+}
+
+// Apply stores a sanction and its log entry in one transaction.
+// a ban also removes the membership, so banned users lose access to private communities.
+func (r *ModerationRepository) Apply(ctx context.Context, sanction *domain.CommunitySanction, entry *domain.ModerationLogEntry) error {
+	const upsertSanction = `
+		INSERT INTO pulse.community_sanctions (` + sanctionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (community_id, user_id, kind) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			issued_by = EXCLUDED.issued_by,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+	`
+	const deleteMember = `DELETE FROM pulse.community_members WHERE community_id = $1 AND user_id = $2`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, upsertSanction,
+		sanction.CommunityID().UUID(),
+		sanction.UserID().UUID(),
+		sanction.Kind().String(),
+		sanction.Reason(),
+		sanction.IssuedBy().UUID(),
+		sanction.CreatedAt(),
+		sanction.ExpiresAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving sanction: %w", err)
+	}
+
+	if sanction.Kind() == domain.SanctionBan {
+		if _, err := tx.Exec(ctx, deleteMember, sanction.CommunityID().UUID(), sanction.UserID().UUID()); err != nil {
+			return fmt.Errorf("removing banned member: %w", err)
+		}
+	}
+
+	if err := insertModerationLog(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Lift removes a sanction and logs it in one transaction.
+func (r *ModerationRepository) Lift(ctx context.Context, communityID domain.CommunityID, userID domain.UserID, kind domain.SanctionKind, entry *domain.ModerationLogEntry) error {
+	const deleteSanction = `
+		DELETE FROM pulse.community_sanctions
+		WHERE community_id = $1 AND user_id = $2 AND kind = $3
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, deleteSanction, communityID.UUID(), userID.UUID(), kind.String())
+	if err != nil {
+		return fmt.Errorf("lifting sanction: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := insertModerationLog(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// FindActive returns a user's unexpired sanctions in a community.
+func (r *ModerationRepository) FindActive(ctx context.Context, communityID domain.CommunityID, userID domain.UserID) ([]*domain.CommunitySanction, error) {
+	query := `
+		SELECT ` + sanctionColumns + `
+		FROM pulse.community_sanctions
+		WHERE community_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > now())
+	`
+	return r.querySanctions(ctx, query, communityID.UUID(), userID.UUID())
+}
+
+// ListActive returns a community's unexpired sanctions, newest first.
+func (r *ModerationRepository) ListActive(ctx context.Context, communityID domain.CommunityID) ([]*domain.CommunitySanction, error) {
+	query := `
+		SELECT ` + sanctionColumns + `
+		FROM pulse.community_sanctions
+		WHERE community_id = $1 AND (expires_at IS NULL OR expires_at > now())
+		ORDER BY created_at DESC
+	`
+	return r.querySanctions(ctx, query, communityID.UUID())
+}
+
+// Log appends an entry to the moderation log.
+func (r *ModerationRepository) Log(ctx context.Context, entry *domain.ModerationLogEntry) error {
+	return insertModerationLog(ctx, r.pool, entry)
+}
+
+// ListLog returns a community's moderation log, newest first.
+func (r *ModerationRepository) ListLog(ctx context.Context, communityID domain.CommunityID, limit, offset int) ([]*domain.ModerationLogEntry, error) {
+	const query = `
+		SELECT id, actor_id, target_id, action, reason, expires_at, created_at
+		FROM pulse.moderation_log
+		WHERE community_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing moderation log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.ModerationLogEntry
+	for rows.Next() {
+		var (
+			id, actorID, targetID uuid.UUID
+			action, reason        string
+			expiresAt             *time.Time
+			createdAt             time.Time
+		)
+		if err := rows.Scan(&id, &actorID, &targetID, &action, &reason, &expiresAt, &createdAt); err != nil {
+			return nil, fmt.Errorf("scanning moderation log: %w", err)
+		}
+		entries = append(entries, domain.ReconstructModerationLogEntry(
+			id,
+			communityID,
+			domain.UserIDFromUUID(actorID),
+			domain.UserIDFromUUID(targetID),
+			domain.ModerationAction(action),
+			reason,
+			expiresAt,
+			createdAt,
+		))
+	}
+	return entries, rows.Err()
+}
+
+func (r *ModerationRepository) querySanctions(ctx context.Context, query string, args ...any) ([]*domain.CommunitySanction, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing sanctions: %w", err)
+	}
+	defer rows.Close()
+
+	var sanctions []*domain.CommunitySanction
+	for rows.Next() {
+		var (
+			communityID, userID, issuedBy uuid.UUID
+			kind, reason                  string
+			createdAt                     time.Time
+			expiresAt                     *time.Time
+		)
+		if err := rows.Scan(&communityID, &userID, &kind, &reason, &issuedBy, &createdAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("scanning sanction: %w", err)
+		}
+		sanctions = append(sanctions, domain.ReconstructCommunitySanction(
+			domain.CommunityIDFromUUID(communityID),
+			domain.UserIDFromUUID(userID),
+			domain.SanctionKind(kind),
+			reason,
+			domain.UserIDFromUUID(issuedBy),
+			createdAt,
+			expiresAt,
+		))
+	}
+	return sanctions, rows.Err()
+}
+
+func insertModerationLog(ctx context.Context, db execer, entry *domain.ModerationLogEntry) error {
+	const query = `
+		INSERT INTO pulse.moderation_log (id, community_id, actor_id, target_id, action, reason, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := db.Exec(ctx, query,
+		entry.ID(),
+		entry.CommunityID().UUID(),
+		entry.ActorID().UUID(),
+		entry.TargetID().UUID(),
+		entry.Action().String(),
+		entry.Reason(),
+		entry.ExpiresAt(),
+		entry.CreatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving moderation log entry: %w", err)
+	}
+	return nil
+}
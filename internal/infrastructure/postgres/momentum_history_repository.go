@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// MomentumHistoryRepository implements domain.MomentumHistoryRepository using Postgres.
+type MomentumHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMomentumHistoryRepository creates a new MomentumHistoryRepository.
+func NewMomentumHistoryRepository(pool *pgxpool.Pool) *MomentumHistoryRepository {
+	return &MomentumHistoryRepository{pool: pool}
+}
+
+// Record stores a calculated score. a second score at the same instant replaces the first.
+func (r *MomentumHistoryRepository) Record(ctx context.Context, communityID domain.CommunityID, momentum domain.Momentum, at time.Time) error {
+	const query = `
+		INSERT INTO pulse.momentum_history (community_id, calculated_at, momentum)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (community_id, calculated_at) DO UPDATE SET momentum = EXCLUDED.momentum
+	`
+
+	if _, err := r.pool.Exec(ctx, query, communityID.UUID(), at, momentum.Value()); err != nil {
+		return fmt.Errorf("recording momentum history: %w", err)
+	}
+	return nil
+}
+
+// Buckets groups a community's scores since a time into buckets, oldest first.
+// buckets are aligned to multiples of their size since 2000-01-01 UTC.
+func (r *MomentumHistoryRepository) Buckets(ctx context.Context, communityID domain.CommunityID, since time.Time, bucket time.Duration) ([]domain.MomentumBucket, error) {
+	const query = `
+		SELECT date_bin(make_interval(secs => $3), calculated_at, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+		       AVG(momentum), MIN(momentum), MAX(momentum),
+		       (ARRAY_AGG(momentum ORDER BY calculated_at DESC))[1],
+		       COUNT(*)
+		FROM pulse.momentum_history
+		WHERE community_id = $1 AND calculated_at >= $2
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID(), since, bucket.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("listing momentum history: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []domain.MomentumBucket{}
+	for rows.Next() {
+		var b domain.MomentumBucket
+		if err := rows.Scan(&b.Start, &b.Average, &b.Min, &b.Max, &b.Last, &b.Samples); err != nil {
+			return nil, fmt.Errorf("scanning momentum history: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// DeleteBefore removes the scores calculated before t.
+func (r *MomentumHistoryRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	const query = `DELETE FROM pulse.momentum_history WHERE calculated_at < $1`
+
+	tag, err := r.pool.Exec(ctx, query, t)
+	if err != nil {
+		return 0, fmt.Errorf("deleting momentum history: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
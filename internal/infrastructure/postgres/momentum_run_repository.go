@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// MomentumRunRepository implements domain.MomentumRunRepository using Postgres.
+type MomentumRunRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMomentumRunRepository creates a new MomentumRunRepository.
+func NewMomentumRunRepository(pool *pgxpool.Pool) *MomentumRunRepository {
+	return &MomentumRunRepository{pool: pool}
+}
+
+// momentumFailureJSON is how a failure is stored in momentum_runs.failures.
+type momentumFailureJSON struct {
+	CommunityID uuid.UUID `json:"community_id"`
+	Reason      string    `json:"reason"`
+	Transient   bool      `json:"transient"`
+}
+
+// Save persists a finished run.
+func (r *MomentumRunRepository) Save(ctx context.Context, run *domain.MomentumRun) error {
+	const query = `
+		INSERT INTO pulse.momentum_runs (id, triggered_by, started_at, finished_at, processed, succeeded, failed, failures, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	failures := make([]momentumFailureJSON, len(run.Failures()))
+	for i, f := range run.Failures() {
+		failures[i] = momentumFailureJSON{CommunityID: f.CommunityID.UUID(), Reason: f.Reason, Transient: f.Transient}
+	}
+	failuresJSON, err := json.Marshal(failures)
+	if err != nil {
+		return fmt.Errorf("serializing momentum run failures: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, query,
+		run.ID(),
+		run.TriggeredBy().String(),
+		run.StartedAt(),
+		run.FinishedAt(),
+		run.Processed(),
+		run.Succeeded(),
+		run.Failed(),
+		string(failuresJSON),
+		run.Err(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving momentum run: %w", err)
+	}
+	return nil
+}
+
+// List returns runs, newest first.
+func (r *MomentumRunRepository) List(ctx context.Context, limit, offset int) ([]*domain.MomentumRun, error) {
+	const query = `
+		SELECT id, triggered_by, started_at, finished_at, processed, succeeded, failed, failures, error
+		FROM pulse.momentum_runs
+		ORDER BY started_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing momentum runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*domain.MomentumRun
+	for rows.Next() {
+		run, err := scanMomentumRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// DeleteBefore removes the runs started before t.
+func (r *MomentumRunRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	const query = `DELETE FROM pulse.momentum_runs WHERE started_at < $1`
+
+	tag, err := r.pool.Exec(ctx, query, t)
+	if err != nil {
+		return 0, fmt.Errorf("deleting momentum runs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanMomentumRun(row pgx.Row) (*domain.MomentumRun, error) {
+	var (
+		id                           uuid.UUID
+		triggeredBy, runErr          string
+		startedAt, finishedAt        time.Time
+		processed, succeeded, failed int
+		failuresJSON                 []byte
+	)
+	if err := row.Scan(&id, &triggeredBy, &startedAt, &finishedAt, &processed, &succeeded, &failed, &failuresJSON, &runErr); err != nil {
+		return nil, fmt.Errorf("scanning momentum run: %w", err)
+	}
+	trigger, err := domain.ParseMomentumRunTrigger(triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []momentumFailureJSON
+	if err := json.Unmarshal(failuresJSON, &stored); err != nil {
+		return nil, fmt.Errorf("parsing momentum run failures: %w", err)
+	}
+	failures := make([]domain.MomentumFailure, len(stored))
+	for i, f := range stored {
+		failures[i] = domain.MomentumFailure{
+			CommunityID: domain.CommunityIDFromUUID(f.CommunityID),
+			Reason:      f.Reason,
+			Transient:   f.Transient,
+		}
+	}
+	return domain.ReconstructMomentumRun(id, trigger, startedAt, finishedAt, processed, succeeded, failed, failures, runErr), nil
+}
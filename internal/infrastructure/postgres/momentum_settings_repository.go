@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// CommunityMomentumSettingsRepository implements domain.CommunityMomentumSettingsRepository using Postgres.
+type CommunityMomentumSettingsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommunityMomentumSettingsRepository creates a new CommunityMomentumSettingsRepository.
+func NewCommunityMomentumSettingsRepository(pool *pgxpool.Pool) *CommunityMomentumSettingsRepository {
+	return &CommunityMomentumSettingsRepository{pool: pool}
+}
+
+// FindByCommunity retrieves the momentum overrides for a community.
+func (r *CommunityMomentumSettingsRepository) FindByCommunity(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityMomentumSettings, error) {
+	const query = `
+		SELECT time_window_seconds, decay_factor, updated_at
+		FROM pulse.community_momentum_settings
+		WHERE community_id = $1
+	`
+
+	var (
+		timeWindowSeconds *int32
+		decayFactor       *float64
+		updatedAt         time.Time
+	)
+
+	err := r.pool.QueryRow(ctx, query, communityID.UUID()).Scan(&timeWindowSeconds, &decayFactor, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var timeWindow *time.Duration
+	if timeWindowSeconds != nil {
+		d := time.Duration(*timeWindowSeconds) * time.Second
+		timeWindow = &d
+	}
+
+	return domain.ReconstructCommunityMomentumSettings(communityID, timeWindow, decayFactor, updatedAt), nil
+}
+
+// Save persists momentum overrides (insert or update).
+func (r *CommunityMomentumSettingsRepository) Save(ctx context.Context, settings *domain.CommunityMomentumSettings) error {
+	const query = `
+		INSERT INTO pulse.community_momentum_settings (community_id, time_window_seconds, decay_factor, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (community_id) DO UPDATE SET
+			time_window_seconds = EXCLUDED.time_window_seconds,
+			decay_factor = EXCLUDED.decay_factor,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	var timeWindowSeconds *int32
+	if tw := settings.TimeWindow(); tw != nil {
+		seconds := int32(tw.Seconds())
+		timeWindowSeconds = &seconds
+	}
+
+	_, err := r.pool.Exec(ctx, query,
+		settings.CommunityID().UUID(),
+		timeWindowSeconds,
+		settings.DecayFactor(),
+		settings.UpdatedAt(),
+	)
+	return err
+}
+
+// Delete removes momentum overrides for a community.
+// deleting a community that has no overrides is not an error.
+func (r *CommunityMomentumSettingsRepository) Delete(ctx context.Context, communityID domain.CommunityID) error {
+	const query = `DELETE FROM pulse.community_momentum_settings WHERE community_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, communityID.UUID())
+	return err
+}
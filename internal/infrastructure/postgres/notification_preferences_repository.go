@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// NotificationPreferencesRepository implements domain.NotificationPreferencesRepository using Postgres.
+type NotificationPreferencesRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNotificationPreferencesRepository creates a new NotificationPreferencesRepository.
+func NewNotificationPreferencesRepository(pool *pgxpool.Pool) *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{pool: pool}
+}
+
+// FindByUser retrieves a user's notification preferences.
+func (r *NotificationPreferencesRepository) FindByUser(ctx context.Context, userID domain.UserID) (*domain.NotificationPreferences, error) {
+	const query = `
+		SELECT max_per_hour, muted_community_ids, quiet_start_minute, quiet_end_minute, quiet_timezone, updated_at
+		FROM pulse.notification_preferences
+		WHERE user_id = $1
+	`
+
+	var (
+		maxPerHour           int32
+		mutedIDs             []uuid.UUID
+		quietStart, quietEnd *int16
+		quietTimezone        *string
+		updatedAt            time.Time
+	)
+
+	err := r.pool.QueryRow(ctx, query, userID.UUID()).Scan(
+		&maxPerHour, &mutedIDs, &quietStart, &quietEnd, &quietTimezone, &updatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	muted := make([]domain.CommunityID, len(mutedIDs))
+	for i, id := range mutedIDs {
+		muted[i] = domain.CommunityIDFromUUID(id)
+	}
+
+	var quietHours *domain.QuietHours
+	if quietStart != nil && quietEnd != nil && quietTimezone != nil {
+		q := domain.ReconstructQuietHours(int(*quietStart), int(*quietEnd), *quietTimezone)
+		quietHours = &q
+	}
+
+	return domain.ReconstructNotificationPreferences(userID, int(maxPerHour), muted, quietHours, updatedAt), nil
+}
+
+// Save persists notification preferences (insert or update).
+func (r *NotificationPreferencesRepository) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	const query = `
+		INSERT INTO pulse.notification_preferences
+			(user_id, max_per_hour, muted_community_ids, quiet_start_minute, quiet_end_minute, quiet_timezone, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			max_per_hour = EXCLUDED.max_per_hour,
+			muted_community_ids = EXCLUDED.muted_community_ids,
+			quiet_start_minute = EXCLUDED.quiet_start_minute,
+			quiet_end_minute = EXCLUDED.quiet_end_minute,
+			quiet_timezone = EXCLUDED.quiet_timezone,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	mutedIDs := make([]uuid.UUID, len(prefs.MutedCommunities()))
+	for i, id := range prefs.MutedCommunities() {
+		mutedIDs[i] = id.UUID()
+	}
+
+	var (
+		quietStart, quietEnd *int16
+		quietTimezone        *string
+	)
+	if q := prefs.QuietHours(); q != nil {
+		start, end, tz := int16(q.StartMinute()), int16(q.EndMinute()), q.Timezone()
+		quietStart, quietEnd, quietTimezone = &start, &end, &tz
+	}
+
+	_, err := r.pool.Exec(ctx, query,
+		prefs.UserID().UUID(),
+		int32(prefs.MaxPerHour()),
+		mutedIDs,
+		quietStart,
+		quietEnd,
+		quietTimezone,
+		prefs.UpdatedAt(),
+	)
+	return err
+}
+
+// Delete removes a user's notification preferences.
+// deleting preferences that don't exist is not an error.
+func (r *NotificationPreferencesRepository) Delete(ctx context.Context, userID domain.UserID) error {
+	const query = `DELETE FROM pulse.notification_preferences WHERE user_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, userID.UUID())
+	return err
+}
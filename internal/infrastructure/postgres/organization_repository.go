@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// OrganizationRepository implements domain.OrganizationRepository using Postgres.
+type OrganizationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrganizationRepository creates a new OrganizationRepository.
+func NewOrganizationRepository(pool *pgxpool.Pool) *OrganizationRepository {
+	return &OrganizationRepository{pool: pool}
+}
+
+// FindByID retrieves an organization by its ID.
+func (r *OrganizationRepository) FindByID(ctx context.Context, id domain.OrganizationID) (*domain.Organization, error) {
+	const query = `
+		SELECT id, slug, name, created_by, created_at, updated_at
+		FROM pulse.organizations
+		WHERE id = $1
+	`
+	return r.scanOrganization(r.pool.QueryRow(ctx, query, id.UUID()))
+}
+
+// FindBySlug retrieves an organization by its slug.
+func (r *OrganizationRepository) FindBySlug(ctx context.Context, slug domain.Slug) (*domain.Organization, error) {
+	const query = `
+		SELECT id, slug, name, created_by, created_at, updated_at
+		FROM pulse.organizations
+		WHERE slug = $1
+	`
+	return r.scanOrganization(r.pool.QueryRow(ctx, query, slug.String()))
+}
+
+// Create persists a new organization together with its first owner.
+// returns domain.ErrAlreadyExists if the slug is taken.
+func (r *OrganizationRepository) Create(ctx context.Context, org *domain.Organization, owner *domain.OrganizationMember) error {
+	const insertOrg = `
+		INSERT INTO pulse.organizations (id, slug, name, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (slug) DO NOTHING
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, insertOrg,
+		org.ID().UUID(),
+		org.Slug().String(),
+		org.Name(),
+		org.CreatedBy().UUID(),
+		org.CreatedAt(),
+		org.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving organization: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlreadyExists
+	}
+
+	if err := saveMember(ctx, tx, owner); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Save updates an existing organization.
+func (r *OrganizationRepository) Save(ctx context.Context, org *domain.Organization) error {
+	const query = `
+		UPDATE pulse.organizations
+		SET name = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query, org.ID().UUID(), org.Name(), org.UpdatedAt())
+	if err != nil {
+		return fmt.Errorf("saving organization: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// FindMember retrieves a user's membership in an organization.
+func (r *OrganizationRepository) FindMember(ctx context.Context, orgID domain.OrganizationID, userID domain.UserID) (*domain.OrganizationMember, error) {
+	const query = `
+		SELECT organization_id, user_id, role, joined_at
+		FROM pulse.organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`
+
+	var (
+		org      uuid.UUID
+		user     uuid.UUID
+		role     string
+		joinedAt time.Time
+	)
+	err := r.pool.QueryRow(ctx, query, orgID.UUID(), userID.UUID()).Scan(&org, &user, &role, &joinedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding organization member: %w", err)
+	}
+
+	return domain.ReconstructOrganizationMember(
+		domain.OrganizationIDFromUUID(org),
+		domain.UserIDFromUUID(user),
+		domain.OrganizationRole(role),
+		joinedAt,
+	), nil
+}
+
+// ListMembers returns all members, owners first, then admins, then members.
+func (r *OrganizationRepository) ListMembers(ctx context.Context, orgID domain.OrganizationID) ([]*domain.OrganizationMember, error) {
+	const query = `
+		SELECT organization_id, user_id, role, joined_at
+		FROM pulse.organization_members
+		WHERE organization_id = $1
+		ORDER BY CASE role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, joined_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, orgID.UUID())
+	if err != nil {
+		return nil, fmt.Errorf("listing organization members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*domain.OrganizationMember
+	for rows.Next() {
+		var (
+			org      uuid.UUID
+			user     uuid.UUID
+			role     string
+			joinedAt time.Time
+		)
+		if err := rows.Scan(&org, &user, &role, &joinedAt); err != nil {
+			return nil, fmt.Errorf("scanning organization member: %w", err)
+		}
+		members = append(members, domain.ReconstructOrganizationMember(
+			domain.OrganizationIDFromUUID(org),
+			domain.UserIDFromUUID(user),
+			domain.OrganizationRole(role),
+			joinedAt,
+		))
+	}
+
+	return members, rows.Err()
+}
+
+// SaveMember inserts a membership or updates its role.
+func (r *OrganizationRepository) SaveMember(ctx context.Context, member *domain.OrganizationMember) error {
+	return saveMember(ctx, r.pool, member)
+}
+
+// RemoveMember deletes a membership.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID domain.OrganizationID, userID domain.UserID) error {
+	const query = `DELETE FROM pulse.organization_members WHERE organization_id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, orgID.UUID(), userID.UUID())
+	if err != nil {
+		return fmt.Errorf("removing organization member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// CountOwners counts members with the owner role.
+func (r *OrganizationRepository) CountOwners(ctx context.Context, orgID domain.OrganizationID) (int, error) {
+	const query = `SELECT count(*) FROM pulse.organization_members WHERE organization_id = $1 AND role = 'owner'`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, orgID.UUID()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting organization owners: %w", err)
+	}
+	return count, nil
+}
+
+// execer is the part of pgxpool.Pool and pgx.Tx that saveMember needs.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func saveMember(ctx context.Context, db execer, member *domain.OrganizationMember) error {
+	const query = `
+		INSERT INTO pulse.organization_members (organization_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET
+			role = EXCLUDED.role
+	`
+
+	_, err := db.Exec(ctx, query,
+		member.OrganizationID().UUID(),
+		member.UserID().UUID(),
+		member.Role().String(),
+		member.JoinedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving organization member: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) scanOrganization(row pgx.Row) (*domain.Organization, error) {
+	var (
+		id        uuid.UUID
+		slug      string
+		name      string
+		createdBy uuid.UUID
+		createdAt time.Time
+		updatedAt time.Time
+	)
+
+	err := row.Scan(&id, &slug, &name, &createdBy, &createdAt, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning organization: %w", err)
+	}
+
+	return domain.ReconstructOrganization(
+		domain.OrganizationIDFromUUID(id),
+		domain.SlugFromTrusted(slug),
+		name,
+		domain.UserIDFromUUID(createdBy),
+		createdAt,
+		updatedAt,
+	), nil
+}
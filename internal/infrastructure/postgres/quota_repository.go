@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// IngestionQuotaRepository implements domain.IngestionQuotaRepository using Postgres.
+type IngestionQuotaRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIngestionQuotaRepository creates a new IngestionQuotaRepository.
+func NewIngestionQuotaRepository(pool *pgxpool.Pool) *IngestionQuotaRepository {
+	return &IngestionQuotaRepository{pool: pool}
+}
+
+// Find retrieves the quota override for a scope.
+func (r *IngestionQuotaRepository) Find(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID) (*domain.IngestionQuota, error) {
+	const query = `
+		SELECT daily_events, updated_at
+		FROM pulse.ingestion_quotas
+		WHERE scope = $1 AND scope_id = $2
+	`
+
+	var (
+		dailyEvents int64
+		updatedAt   time.Time
+	)
+
+	err := r.pool.QueryRow(ctx, query, scope.String(), scopeID).Scan(&dailyEvents, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.ReconstructIngestionQuota(scope, scopeID, dailyEvents, updatedAt), nil
+}
+
+// Save persists a quota override (insert or update).
+func (r *IngestionQuotaRepository) Save(ctx context.Context, quota *domain.IngestionQuota) error {
+	const query = `
+		INSERT INTO pulse.ingestion_quotas (scope, scope_id, daily_events, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope, scope_id) DO UPDATE SET
+			daily_events = EXCLUDED.daily_events,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		quota.Scope().String(),
+		quota.ScopeID(),
+		quota.DailyEvents(),
+		quota.UpdatedAt(),
+	)
+	return err
+}
+
+// Delete removes a quota override.
+// deleting a scope that has no override is not an error.
+func (r *IngestionQuotaRepository) Delete(ctx context.Context, scope domain.UsageScope, scopeID uuid.UUID) error {
+	const query = `DELETE FROM pulse.ingestion_quotas WHERE scope = $1 AND scope_id = $2`
+
+	_, err := r.pool.Exec(ctx, query, scope.String(), scopeID)
+	return err
+}
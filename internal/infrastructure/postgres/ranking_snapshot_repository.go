@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// RankingSnapshotRepository implements domain.RankingSnapshotRepository using Postgres.
+type RankingSnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRankingSnapshotRepository creates a new RankingSnapshotRepository.
+func NewRankingSnapshotRepository(pool *pgxpool.Pool) *RankingSnapshotRepository {
+	return &RankingSnapshotRepository{pool: pool}
+}
+
+// rankedCommunityJSON is how an entry is stored in ranking_snapshots.entries.
+type rankedCommunityJSON struct {
+	CommunityID uuid.UUID `json:"community_id"`
+	Rank        int       `json:"rank"`
+	Momentum    float64   `json:"momentum"`
+}
+
+// Save persists a snapshot. a second snapshot with the same id replaces the first.
+func (r *RankingSnapshotRepository) Save(ctx context.Context, snapshot *domain.RankingSnapshot) error {
+	const query = `
+		INSERT INTO pulse.ranking_snapshots (id, taken_at, entries)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET
+			taken_at = EXCLUDED.taken_at,
+			entries = EXCLUDED.entries
+	`
+
+	entries := snapshot.Entries()
+	stored := make([]rankedCommunityJSON, len(entries))
+	for i, e := range entries {
+		stored[i] = rankedCommunityJSON{CommunityID: e.CommunityID.UUID(), Rank: e.Rank, Momentum: e.Momentum}
+	}
+	entriesJSON, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("serializing ranking snapshot: %w", err)
+	}
+
+	if _, err := r.pool.Exec(ctx, query, snapshot.ID(), snapshot.TakenAt(), string(entriesJSON)); err != nil {
+		return fmt.Errorf("saving ranking snapshot: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the snapshot taken after a momentum run.
+func (r *RankingSnapshotRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.RankingSnapshot, error) {
+	const query = `
+		SELECT id, taken_at, entries
+		FROM pulse.ranking_snapshots
+		WHERE id = $1
+	`
+
+	return scanRankingSnapshot(r.pool.QueryRow(ctx, query, id))
+}
+
+// LatestAt returns the last snapshot taken at or before t.
+func (r *RankingSnapshotRepository) LatestAt(ctx context.Context, t time.Time) (*domain.RankingSnapshot, error) {
+	const query = `
+		SELECT id, taken_at, entries
+		FROM pulse.ranking_snapshots
+		WHERE taken_at <= $1
+		ORDER BY taken_at DESC
+		LIMIT 1
+	`
+
+	return scanRankingSnapshot(r.pool.QueryRow(ctx, query, t))
+}
+
+// DeleteBefore removes the snapshots taken before t.
+func (r *RankingSnapshotRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	const query = `DELETE FROM pulse.ranking_snapshots WHERE taken_at < $1`
+
+	tag, err := r.pool.Exec(ctx, query, t)
+	if err != nil {
+		return 0, fmt.Errorf("deleting ranking snapshots: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanRankingSnapshot(row pgx.Row) (*domain.RankingSnapshot, error) {
+	var (
+		id          uuid.UUID
+		takenAt     time.Time
+		entriesJSON []byte
+	)
+	err := row.Scan(&id, &takenAt, &entriesJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding ranking snapshot: %w", err)
+	}
+
+	var stored []rankedCommunityJSON
+	if err := json.Unmarshal(entriesJSON, &stored); err != nil {
+		return nil, fmt.Errorf("parsing ranking snapshot: %w", err)
+	}
+	entries := make([]domain.RankedCommunity, len(stored))
+	for i, e := range stored {
+		entries[i] = domain.RankedCommunity{
+			CommunityID: domain.CommunityIDFromUUID(e.CommunityID),
+			Rank:        e.Rank,
+			Momentum:    e.Momentum,
+		}
+	}
+	return domain.ReconstructRankingSnapshot(id, takenAt, entries), nil
+}
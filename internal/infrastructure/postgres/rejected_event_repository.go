@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// RejectedEventRepository implements domain.RejectedEventRepository using Postgres.
+type RejectedEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRejectedEventRepository creates a new RejectedEventRepository.
+func NewRejectedEventRepository(pool *pgxpool.Pool) *RejectedEventRepository {
+	return &RejectedEventRepository{pool: pool}
+}
+
+// SaveBatch inserts rejected events with CopyFrom, like activity event batches.
+func (r *RejectedEventRepository) SaveBatch(ctx context.Context, rejected []*domain.RejectedEvent) error {
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(rejected))
+	for i, rej := range rejected {
+		event := rej.Event()
+
+		var userID any
+		if event.UserID() != nil {
+			userID = event.UserID().UUID()
+		}
+
+		metadataJSON, err := event.MetadataJSON()
+		if err != nil {
+			return fmt.Errorf("serializing metadata for event %s: %w", event.ID().String(), err)
+		}
+
+		rows[i] = []any{
+			event.ID().UUID(),
+			event.CommunityID().UUID(),
+			userID,
+			event.EventType().String(),
+			event.Weight().Value(),
+			string(metadataJSON),
+			event.OccurredAt(),
+			event.CreatedAt(),
+			rej.Reason().String(),
+			rej.RejectedAt(),
+		}
+	}
+
+	_, err := r.pool.CopyFrom(
+		ctx,
+		pgx.Identifier{"pulse", "rejected_events"},
+		[]string{"id", "community_id", "user_id", "event_type", "weight", "metadata", "occurred_at", "created_at", "reason", "rejected_at"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("batch inserting rejected events: %w", err)
+	}
+	return nil
+}
+
+// List retrieves rejected events, newest first.
+func (r *RejectedEventRepository) List(ctx context.Context, limit, offset int) ([]*domain.RejectedEvent, error) {
+	const query = `
+		SELECT id, community_id, user_id, event_type, weight, metadata, occurred_at, created_at, reason, rejected_at
+		FROM pulse.rejected_events
+		ORDER BY rejected_at DESC, id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing rejected events: %w", err)
+	}
+	defer rows.Close()
+
+	var rejected []*domain.RejectedEvent
+	for rows.Next() {
+		rej, err := scanRejectedEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		rejected = append(rejected, rej)
+	}
+	return rejected, rows.Err()
+}
+
+func scanRejectedEvent(row pgx.Row) (*domain.RejectedEvent, error) {
+	var (
+		id, communityID       uuid.UUID
+		userID                *uuid.UUID
+		eventType, reason     string
+		weight                float64
+		metadata              []byte
+		occurredAt, createdAt time.Time
+		rejectedAt            time.Time
+	)
+	if err := row.Scan(&id, &communityID, &userID, &eventType, &weight, &metadata, &occurredAt, &createdAt, &reason, &rejectedAt); err != nil {
+		return nil, fmt.Errorf("scanning rejected event: %w", err)
+	}
+
+	eventTypeParsed, err := domain.ParseEventType(eventType)
+	if err != nil {
+		return nil, fmt.Errorf("corrupted event type in database: %w", err)
+	}
+	weightParsed, err := domain.NewWeight(weight)
+	if err != nil {
+		return nil, fmt.Errorf("corrupted weight in database: %w", err)
+	}
+	reasonParsed, err := domain.ParseRejectReason(reason)
+	if err != nil {
+		return nil, fmt.Errorf("corrupted reject reason in database: %w", err)
+	}
+
+	var user *domain.UserID
+	if userID != nil {
+		u := domain.UserIDFromUUID(*userID)
+		user = &u
+	}
+
+	var metadataMap map[string]any
+	if len(metadata) > 0 && string(metadata) != "null" {
+		if err := json.Unmarshal(metadata, &metadataMap); err != nil {
+			return nil, fmt.Errorf("corrupted metadata json in database: %w", err)
+		}
+	}
+
+	event := domain.ReconstructActivityEvent(
+		domain.EventIDFromUUID(id),
+		domain.CommunityIDFromUUID(communityID),
+		user,
+		eventTypeParsed,
+		weightParsed,
+		metadataMap,
+		occurredAt,
+		createdAt,
+	)
+	return domain.ReconstructRejectedEvent(event, reasonParsed, rejectedAt), nil
+}
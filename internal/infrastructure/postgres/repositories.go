@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joacominatel/pulse/internal/domain"
@@ -44,15 +45,16 @@ func (r *UserRepository) FindByExternalID(ctx context.Context, externalID string
 	return r.scanUser(ctx, query, externalID)
 }
 
-// FindByUsername retrieves a user by their username.
+// FindByUsername retrieves a user by their username, ignoring case.
 func (r *UserRepository) FindByUsername(ctx context.Context, username domain.Username) (*domain.User, error) {
+	// matches the unique index on lower(username)
 	const query = `
 		SELECT id, external_id, username, display_name, avatar_url, bio, created_at, updated_at
 		FROM pulse.users_profile
-		WHERE username = $1
+		WHERE lower(username) = $1
 	`
 
-	return r.scanUser(ctx, query, username.String())
+	return r.scanUser(ctx, query, username.Normalized())
 }
 
 // Save persists a user (insert or update).
@@ -84,6 +86,34 @@ func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// Create inserts a new user.
+// returns domain.ErrAlreadyExists if the external id or the username is taken.
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	const query = `
+		INSERT INTO pulse.users_profile (id, external_id, username, display_name, avatar_url, bio, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		user.ID().UUID(),
+		user.ExternalID(),
+		user.Username().String(),
+		nullableString(user.DisplayName()),
+		nullableString(user.AvatarURL()),
+		nullableString(user.Bio()),
+		user.CreatedAt(),
+		user.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("creating user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlreadyExists
+	}
+	return nil
+}
+
 // Exists checks if a user with the given ID exists.
 func (r *UserRepository) Exists(ctx context.Context, id domain.UserID) (bool, error) {
 	const query = `SELECT EXISTS(SELECT 1 FROM pulse.users_profile WHERE id = $1)`
@@ -96,7 +126,95 @@ func (r *UserRepository) Exists(ctx context.Context, id domain.UserID) (bool, er
 	return exists, nil
 }
 
+// FindByIDs retrieves multiple users by their internal IDs.
+// maintains the order of the input IDs.
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []domain.UserID) ([]*domain.User, error) {
+	if len(ids) == 0 {
+		return []*domain.User{}, nil
+	}
+
+	uuids := make([]string, len(ids))
+	for i, id := range ids {
+		uuids[i] = id.String()
+	}
+
+	const query = `
+		SELECT id, external_id, username, display_name, avatar_url, bio, created_at, updated_at
+		FROM pulse.users_profile
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("finding users by ids: %w", err)
+	}
+	defer rows.Close()
+
+	userMap := make(map[domain.UserID]*domain.User)
+	for rows.Next() {
+		user, err := scanUserRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		userMap[user.ID()] = user
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating users: %w", err)
+	}
+
+	// reorder results to match input order
+	users := make([]*domain.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := userMap[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
+// ExistsBatch checks which of the given users exist.
+func (r *UserRepository) ExistsBatch(ctx context.Context, ids []domain.UserID) (map[domain.UserID]bool, error) {
+	result := make(map[domain.UserID]bool, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	uuids := make([]string, len(ids))
+	for i, id := range ids {
+		uuids[i] = id.String()
+		result[id] = false
+	}
+
+	const query = `SELECT id FROM pulse.users_profile WHERE id = ANY($1)`
+
+	rows, err := r.pool.Query(ctx, query, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("checking users existence: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning user id: %w", err)
+		}
+		result[domain.UserIDFromUUID(id)] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating user ids: %w", err)
+	}
+	return result, nil
+}
+
 func (r *UserRepository) scanUser(ctx context.Context, query string, args ...any) (*domain.User, error) {
+	return scanUserRow(r.pool.QueryRow(ctx, query, args...))
+}
+
+// scanUserRow scans a user from a single row or the current row of a result set.
+func scanUserRow(row pgx.Row) (*domain.User, error) {
 	var (
 		id          string
 		externalID  string
@@ -108,7 +226,7 @@ func (r *UserRepository) scanUser(ctx context.Context, query string, args ...any
 		updatedAt   time.Time
 	)
 
-	err := r.pool.QueryRow(ctx, query, args...).Scan(
+	err := row.Scan(
 		&id, &externalID, &username, &displayName, &avatarURL, &bio, &createdAt, &updatedAt,
 	)
 
@@ -151,7 +269,7 @@ func NewCommunityRepository(pool *pgxpool.Pool) *CommunityRepository {
 // FindByID retrieves a community by its ID.
 func (r *CommunityRepository) FindByID(ctx context.Context, id domain.CommunityID) (*domain.Community, error) {
 	const query = `
-		SELECT id, slug, name, description, creator_id, avatar_url, is_active, 
+		SELECT id, slug, name, description, creator_id, organization_id, avatar_url, is_active, visibility,
 		       current_momentum, momentum_updated_at, created_at, updated_at
 		FROM pulse.communities
 		WHERE id = $1
@@ -163,7 +281,7 @@ func (r *CommunityRepository) FindByID(ctx context.Context, id domain.CommunityI
 // FindBySlug retrieves a community by its URL-friendly slug.
 func (r *CommunityRepository) FindBySlug(ctx context.Context, slug domain.Slug) (*domain.Community, error) {
 	const query = `
-		SELECT id, slug, name, description, creator_id, avatar_url, is_active,
+		SELECT id, slug, name, description, creator_id, organization_id, avatar_url, is_active, visibility,
 		       current_momentum, momentum_updated_at, created_at, updated_at
 		FROM pulse.communities
 		WHERE slug = $1
@@ -175,14 +293,16 @@ func (r *CommunityRepository) FindBySlug(ctx context.Context, slug domain.Slug)
 // Save persists a community (insert or update).
 func (r *CommunityRepository) Save(ctx context.Context, community *domain.Community) error {
 	const query = `
-		INSERT INTO pulse.communities (id, slug, name, description, creator_id, avatar_url, is_active,
-		                               current_momentum, momentum_updated_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO pulse.communities (id, slug, name, description, creator_id, organization_id, avatar_url, is_active,
+		                               visibility, current_momentum, momentum_updated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (id) DO UPDATE SET
 			name = EXCLUDED.name,
+			organization_id = EXCLUDED.organization_id,
 			description = EXCLUDED.description,
 			avatar_url = EXCLUDED.avatar_url,
 			is_active = EXCLUDED.is_active,
+			visibility = EXCLUDED.visibility,
 			current_momentum = EXCLUDED.current_momentum,
 			momentum_updated_at = EXCLUDED.momentum_updated_at,
 			updated_at = EXCLUDED.updated_at
@@ -194,8 +314,10 @@ func (r *CommunityRepository) Save(ctx context.Context, community *domain.Commun
 		community.Name(),
 		nullableString(community.Description()),
 		community.CreatorID().UUID(),
+		nullableOrganizationID(community.OrganizationID()),
 		nullableString(community.AvatarURL()),
 		community.IsActive(),
+		community.Visibility().String(),
 		community.CurrentMomentum().Value(),
 		community.MomentumUpdatedAt(),
 		community.CreatedAt(),
@@ -235,7 +357,7 @@ func (r *CommunityRepository) FindByIDs(ctx context.Context, ids []domain.Commun
 
 	// query using ANY with array
 	const query = `
-		SELECT id, slug, name, description, creator_id, avatar_url, is_active,
+		SELECT id, slug, name, description, creator_id, organization_id, avatar_url, is_active, visibility,
 		       current_momentum, momentum_updated_at, created_at, updated_at
 		FROM pulse.communities
 		WHERE id = ANY($1)
@@ -273,14 +395,20 @@ func (r *CommunityRepository) FindByIDs(ctx context.Context, ids []domain.Commun
 	return communities, nil
 }
 
-// ListByMomentum returns active communities ordered by momentum.
+// leaderboardOrder ranks communities like domain.CompareLeaderboardRank, so ties on momentum
+// keep their order between reads and between postgres and the redis leaderboard.
+// queries using it alias communities as c and left join their summary as s.
+const leaderboardOrder = `c.current_momentum DESC, COALESCE(s.member_count, 0) DESC, c.created_at ASC, c.id ASC`
+
+// ListByMomentum returns active communities ordered by momentum, whatever their visibility.
 func (r *CommunityRepository) ListByMomentum(ctx context.Context, limit, offset int) ([]*domain.Community, error) {
 	const query = `
-		SELECT id, slug, name, description, creator_id, avatar_url, is_active,
-		       current_momentum, momentum_updated_at, created_at, updated_at
-		FROM pulse.communities
-		WHERE is_active = true
-		ORDER BY current_momentum DESC
+		SELECT c.id, c.slug, c.name, c.description, c.creator_id, c.organization_id, c.avatar_url, c.is_active, c.visibility,
+		       c.current_momentum, c.momentum_updated_at, c.created_at, c.updated_at
+		FROM pulse.communities c
+		LEFT JOIN pulse.community_summaries s ON s.community_id = c.id
+		WHERE c.is_active = true
+		ORDER BY ` + leaderboardOrder + `
 		LIMIT $1 OFFSET $2
 	`
 
@@ -302,6 +430,95 @@ func (r *CommunityRepository) ListByMomentum(ctx context.Context, limit, offset
 	return communities, rows.Err()
 }
 
+// ListPublicByMomentum returns active public communities ordered by momentum.
+func (r *CommunityRepository) ListPublicByMomentum(ctx context.Context, limit, offset int) ([]*domain.Community, error) {
+	const query = `
+		SELECT c.id, c.slug, c.name, c.description, c.creator_id, c.organization_id, c.avatar_url, c.is_active, c.visibility,
+		       c.current_momentum, c.momentum_updated_at, c.created_at, c.updated_at
+		FROM pulse.communities c
+		LEFT JOIN pulse.community_summaries s ON s.community_id = c.id
+		WHERE c.is_active = true AND c.visibility = 'public'
+		ORDER BY ` + leaderboardOrder + `
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing public communities: %w", err)
+	}
+	defer rows.Close()
+
+	var communities []*domain.Community
+	for rows.Next() {
+		community, err := r.scanCommunityFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		communities = append(communities, community)
+	}
+
+	return communities, rows.Err()
+}
+
+// ListNewPublic returns active public communities created since a time, newest first.
+func (r *CommunityRepository) ListNewPublic(ctx context.Context, since time.Time, limit int) ([]*domain.Community, error) {
+	const query = `
+		SELECT id, slug, name, description, creator_id, organization_id, avatar_url, is_active, visibility,
+		       current_momentum, momentum_updated_at, created_at, updated_at
+		FROM pulse.communities
+		WHERE is_active = true AND visibility = 'public' AND created_at >= $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing new communities: %w", err)
+	}
+	defer rows.Close()
+
+	var communities []*domain.Community
+	for rows.Next() {
+		community, err := r.scanCommunityFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		communities = append(communities, community)
+	}
+
+	return communities, rows.Err()
+}
+
+// ListByOrganization returns an organization's active, non-private communities ordered by momentum.
+func (r *CommunityRepository) ListByOrganization(ctx context.Context, orgID domain.OrganizationID, limit, offset int) ([]*domain.Community, error) {
+	const query = `
+		SELECT c.id, c.slug, c.name, c.description, c.creator_id, c.organization_id, c.avatar_url, c.is_active, c.visibility,
+		       c.current_momentum, c.momentum_updated_at, c.created_at, c.updated_at
+		FROM pulse.communities c
+		LEFT JOIN pulse.community_summaries s ON s.community_id = c.id
+		WHERE c.organization_id = $1 AND c.is_active = true AND c.visibility <> 'private'
+		ORDER BY ` + leaderboardOrder + `
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, orgID.UUID(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing organization communities: %w", err)
+	}
+	defer rows.Close()
+
+	var communities []*domain.Community
+	for rows.Next() {
+		community, err := r.scanCommunityFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		communities = append(communities, community)
+	}
+
+	return communities, rows.Err()
+}
+
 // UpdateMomentum updates just the momentum fields for a community.
 func (r *CommunityRepository) UpdateMomentum(ctx context.Context, id domain.CommunityID, momentum domain.Momentum) error {
 	const query = `
@@ -331,8 +548,10 @@ func (r *CommunityRepository) scanCommunity(ctx context.Context, query string, a
 		name              string
 		description       *string
 		creatorID         string
+		organizationID    *string
 		avatarURL         *string
 		isActive          bool
+		visibility        string
 		currentMomentum   float64
 		momentumUpdatedAt *time.Time
 		createdAt         time.Time
@@ -340,7 +559,7 @@ func (r *CommunityRepository) scanCommunity(ctx context.Context, query string, a
 	)
 
 	err := row.Scan(
-		&id, &slug, &name, &description, &creatorID, &avatarURL, &isActive,
+		&id, &slug, &name, &description, &creatorID, &organizationID, &avatarURL, &isActive, &visibility,
 		&currentMomentum, &momentumUpdatedAt, &createdAt, &updatedAt,
 	)
 
@@ -362,14 +581,25 @@ func (r *CommunityRepository) scanCommunity(ctx context.Context, query string, a
 		return nil, fmt.Errorf("corrupted creator id in database: %w", err)
 	}
 
+	var organizationIDParsed *domain.OrganizationID
+	if organizationID != nil {
+		parsed, err := domain.ParseOrganizationID(*organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("corrupted organization id in database: %w", err)
+		}
+		organizationIDParsed = &parsed
+	}
+
 	return domain.ReconstructCommunity(
 		communityID,
 		domain.SlugFromTrusted(slug),
 		name,
 		derefString(description),
 		creatorIDParsed,
+		organizationIDParsed,
 		derefString(avatarURL),
 		isActive,
+		domain.CommunityVisibility(visibility),
 		domain.NewMomentum(currentMomentum),
 		momentumUpdatedAt,
 		createdAt,
@@ -384,8 +614,10 @@ func (r *CommunityRepository) scanCommunityFromRows(rows pgx.Rows) (*domain.Comm
 		name              string
 		description       *string
 		creatorID         string
+		organizationID    *string
 		avatarURL         *string
 		isActive          bool
+		visibility        string
 		currentMomentum   float64
 		momentumUpdatedAt *time.Time
 		createdAt         time.Time
@@ -393,7 +625,7 @@ func (r *CommunityRepository) scanCommunityFromRows(rows pgx.Rows) (*domain.Comm
 	)
 
 	err := rows.Scan(
-		&id, &slug, &name, &description, &creatorID, &avatarURL, &isActive,
+		&id, &slug, &name, &description, &creatorID, &organizationID, &avatarURL, &isActive, &visibility,
 		&currentMomentum, &momentumUpdatedAt, &createdAt, &updatedAt,
 	)
 	if err != nil {
@@ -411,14 +643,25 @@ func (r *CommunityRepository) scanCommunityFromRows(rows pgx.Rows) (*domain.Comm
 		return nil, fmt.Errorf("corrupted creator id in database: %w", err)
 	}
 
+	var organizationIDParsed *domain.OrganizationID
+	if organizationID != nil {
+		parsed, err := domain.ParseOrganizationID(*organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("corrupted organization id in database: %w", err)
+		}
+		organizationIDParsed = &parsed
+	}
+
 	return domain.ReconstructCommunity(
 		communityID,
 		domain.SlugFromTrusted(slug),
 		name,
 		derefString(description),
 		creatorIDParsed,
+		organizationIDParsed,
 		derefString(avatarURL),
 		isActive,
+		domain.CommunityVisibility(visibility),
 		domain.NewMomentum(currentMomentum),
 		momentumUpdatedAt,
 		createdAt,
@@ -439,8 +682,8 @@ func NewActivityEventRepository(pool *pgxpool.Pool) *ActivityEventRepository {
 // Save persists a new activity event.
 func (r *ActivityEventRepository) Save(ctx context.Context, event *domain.ActivityEvent) error {
 	const query = `
-        INSERT INTO pulse.activity_events (id, community_id, user_id, event_type, weight, metadata, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO pulse.activity_events (id, community_id, user_id, event_type, weight, metadata, occurred_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
     `
 
 	var userID any
@@ -460,6 +703,7 @@ func (r *ActivityEventRepository) Save(ctx context.Context, event *domain.Activi
 		event.EventType().String(),
 		event.Weight().Value(),
 		string(metadataJSON),
+		event.OccurredAt(),
 		event.CreatedAt(),
 	)
 
@@ -476,8 +720,8 @@ func (r *ActivityEventRepository) SaveBatch(ctx context.Context, events []*domai
 		return nil
 	}
 
-	// use a transaction for atomicity
-	tx, err := r.pool.Begin(ctx)
+	// use a transaction for atomicity, within the unit of work's if there is one
+	tx, err := beginTx(ctx, r.pool)
 	if err != nil {
 		return fmt.Errorf("beginning transaction: %w", err)
 	}
@@ -503,6 +747,7 @@ func (r *ActivityEventRepository) SaveBatch(ctx context.Context, events []*domai
 			event.EventType().String(),
 			event.Weight().Value(),
 			string(metadataJSON),
+			event.OccurredAt(),
 			event.CreatedAt(),
 		}
 	}
@@ -510,7 +755,7 @@ func (r *ActivityEventRepository) SaveBatch(ctx context.Context, events []*domai
 	_, err = tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"pulse", "activity_events"},
-		[]string{"id", "community_id", "user_id", "event_type", "weight", "metadata", "created_at"},
+		[]string{"id", "community_id", "user_id", "event_type", "weight", "metadata", "occurred_at", "created_at"},
 		pgx.CopyFromRows(rows),
 	)
 	if err != nil {
@@ -525,16 +770,23 @@ func (r *ActivityEventRepository) SaveBatch(ctx context.Context, events []*domai
 }
 
 // FindByCommunity retrieves events for a community within a time window.
-func (r *ActivityEventRepository) FindByCommunity(ctx context.Context, communityID domain.CommunityID, since time.Time, limit int) ([]*domain.ActivityEvent, error) {
+// an empty types matches every event type.
+func (r *ActivityEventRepository) FindByCommunity(ctx context.Context, communityID domain.CommunityID, since time.Time, types []domain.EventType, limit int) ([]*domain.ActivityEvent, error) {
 	const query = `
-		SELECT id, community_id, user_id, event_type, weight, metadata, created_at
+		SELECT id, community_id, user_id, event_type, weight, metadata, occurred_at, created_at
 		FROM pulse.activity_events
-		WHERE community_id = $1 AND created_at >= $2
-		ORDER BY created_at DESC
-		LIMIT $3
+		WHERE community_id = $1 AND occurred_at >= $2
+			AND ($3::text[] IS NULL OR event_type::text = ANY($3))
+		ORDER BY occurred_at DESC
+		LIMIT $4
 	`
 
-	rows, err := r.pool.Query(ctx, query, communityID.UUID(), since, limit)
+	var typeFilter []string
+	for _, t := range types {
+		typeFilter = append(typeFilter, t.String())
+	}
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID(), since, typeFilter, limit)
 	if err != nil {
 		return nil, fmt.Errorf("querying activity events: %w", err)
 	}
@@ -546,7 +798,7 @@ func (r *ActivityEventRepository) FindByCommunity(ctx context.Context, community
 // FindByUser retrieves events generated by a user.
 func (r *ActivityEventRepository) FindByUser(ctx context.Context, userID domain.UserID, limit int) ([]*domain.ActivityEvent, error) {
 	const query = `
-		SELECT id, community_id, user_id, event_type, weight, metadata, created_at
+		SELECT id, community_id, user_id, event_type, weight, metadata, occurred_at, created_at
 		FROM pulse.activity_events
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -567,7 +819,7 @@ func (r *ActivityEventRepository) CountByCommunity(ctx context.Context, communit
 	const query = `
 		SELECT COUNT(*)
 		FROM pulse.activity_events
-		WHERE community_id = $1 AND created_at >= $2
+		WHERE community_id = $1 AND occurred_at >= $2
 	`
 
 	var count int64
@@ -578,6 +830,25 @@ func (r *ActivityEventRepository) CountByCommunity(ctx context.Context, communit
 	return count, nil
 }
 
+// DeleteBefore removes up to limit events created before the cutoff.
+// deleting in bounded batches keeps locks and WAL bursts small on big tables.
+func (r *ActivityEventRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	const query = `
+		DELETE FROM pulse.activity_events
+		WHERE id IN (
+			SELECT id FROM pulse.activity_events
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := r.pool.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("deleting events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // SumWeightsByCommunity calculates the total weighted momentum contribution.
 func (r *ActivityEventRepository) SumWeightsByCommunity(ctx context.Context, communityID domain.CommunityID, since time.Time) (float64, error) {
 	// weights are multiplied by sign based on event type
@@ -587,7 +858,7 @@ func (r *ActivityEventRepository) SumWeightsByCommunity(ctx context.Context, com
 			CASE WHEN event_type = 'leave' THEN -weight ELSE weight END
 		), 0)
 		FROM pulse.activity_events
-		WHERE community_id = $1 AND created_at >= $2
+		WHERE community_id = $1 AND occurred_at >= $2
 	`
 
 	var sum float64
@@ -598,6 +869,194 @@ func (r *ActivityEventRepository) SumWeightsByCommunity(ctx context.Context, com
 	return sum, nil
 }
 
+// SumWeightsByRegion is SumWeightsByCommunity per region. events without a region are left out.
+func (r *ActivityEventRepository) SumWeightsByRegion(ctx context.Context, communityID domain.CommunityID, since time.Time) (map[string]float64, error) {
+	const query = `
+		SELECT region, SUM(
+			CASE WHEN event_type = 'leave' THEN -weight ELSE weight END
+		)
+		FROM pulse.activity_events
+		WHERE community_id = $1 AND occurred_at >= $2 AND region IS NOT NULL
+		GROUP BY region
+	`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID(), since)
+	if err != nil {
+		return nil, fmt.Errorf("summing weights by region: %w", err)
+	}
+	defer rows.Close()
+
+	sums := make(map[string]float64)
+	for rows.Next() {
+		var (
+			region string
+			sum    float64
+		)
+		if err := rows.Scan(&region, &sum); err != nil {
+			return nil, fmt.Errorf("scanning region weights: %w", err)
+		}
+		sums[region] = sum
+	}
+	return sums, rows.Err()
+}
+
+// CommunityIngestRates counts each community's events in the recent window, from windowStart on,
+// and in the baseline before it, from baselineStart. communities under minEvents in the window are skipped.
+func (r *ActivityEventRepository) CommunityIngestRates(ctx context.Context, baselineStart, windowStart time.Time, minEvents int64) ([]domain.IngestRate, error) {
+	const query = `
+		SELECT community_id,
+			COUNT(*) FILTER (WHERE created_at >= $2),
+			COUNT(*) FILTER (WHERE created_at < $2)
+		FROM pulse.activity_events
+		WHERE created_at >= $1
+		GROUP BY community_id
+		HAVING COUNT(*) FILTER (WHERE created_at >= $2) >= $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, baselineStart, windowStart, minEvents)
+	if err != nil {
+		return nil, fmt.Errorf("querying community ingest rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []domain.IngestRate
+	for rows.Next() {
+		var (
+			communityID        uuid.UUID
+			observed, baseline int64
+		)
+		if err := rows.Scan(&communityID, &observed, &baseline); err != nil {
+			return nil, fmt.Errorf("scanning ingest rate: %w", err)
+		}
+		rates = append(rates, domain.IngestRate{
+			CommunityID: domain.CommunityIDFromUUID(communityID),
+			Observed:    observed,
+			Baseline:    baseline,
+		})
+	}
+	return rates, rows.Err()
+}
+
+// UserIngestRates is CommunityIngestRates per user in each community. anonymous events are left out.
+func (r *ActivityEventRepository) UserIngestRates(ctx context.Context, baselineStart, windowStart time.Time, minEvents int64) ([]domain.IngestRate, error) {
+	const query = `
+		SELECT community_id, user_id,
+			COUNT(*) FILTER (WHERE created_at >= $2),
+			COUNT(*) FILTER (WHERE created_at < $2)
+		FROM pulse.activity_events
+		WHERE created_at >= $1 AND user_id IS NOT NULL
+		GROUP BY community_id, user_id
+		HAVING COUNT(*) FILTER (WHERE created_at >= $2) >= $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, baselineStart, windowStart, minEvents)
+	if err != nil {
+		return nil, fmt.Errorf("querying user ingest rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []domain.IngestRate
+	for rows.Next() {
+		var (
+			communityID, userID uuid.UUID
+			observed, baseline  int64
+		)
+		if err := rows.Scan(&communityID, &userID, &observed, &baseline); err != nil {
+			return nil, fmt.Errorf("scanning ingest rate: %w", err)
+		}
+		user := domain.UserIDFromUUID(userID)
+		rates = append(rates, domain.IngestRate{
+			CommunityID: domain.CommunityIDFromUUID(communityID),
+			UserID:      &user,
+			Observed:    observed,
+			Baseline:    baseline,
+		})
+	}
+	return rates, rows.Err()
+}
+
+// CommunityVelocities compares each active public community's weighted activity since windowStart
+// with the period from previousStart to windowStart. only growing communities are returned, fastest first.
+func (r *ActivityEventRepository) CommunityVelocities(ctx context.Context, previousStart, windowStart time.Time, limit int) ([]domain.CommunityVelocity, error) {
+	const query = `
+		WITH sums AS (
+			SELECT e.community_id,
+				COALESCE(SUM(CASE WHEN e.event_type = 'leave' THEN -e.weight ELSE e.weight END)
+					FILTER (WHERE e.occurred_at >= $2), 0) AS recent,
+				COALESCE(SUM(CASE WHEN e.event_type = 'leave' THEN -e.weight ELSE e.weight END)
+					FILTER (WHERE e.occurred_at < $2), 0) AS previous
+			FROM pulse.activity_events e
+			JOIN pulse.communities c ON c.id = e.community_id
+			WHERE e.occurred_at >= $1 AND c.is_active = true AND c.visibility = 'public'
+			GROUP BY e.community_id
+		)
+		SELECT community_id, recent, previous
+		FROM sums
+		WHERE recent > previous
+		ORDER BY recent - previous DESC
+		LIMIT $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, previousStart, windowStart, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying community velocities: %w", err)
+	}
+	defer rows.Close()
+
+	var velocities []domain.CommunityVelocity
+	for rows.Next() {
+		var (
+			communityID      uuid.UUID
+			recent, previous float64
+		)
+		if err := rows.Scan(&communityID, &recent, &previous); err != nil {
+			return nil, fmt.Errorf("scanning community velocity: %w", err)
+		}
+		velocities = append(velocities, domain.CommunityVelocity{
+			CommunityID: domain.CommunityIDFromUUID(communityID),
+			Recent:      recent,
+			Previous:    previous,
+		})
+	}
+	return velocities, rows.Err()
+}
+
+// UserParticipation returns the communities a user sent events to since a time, most recent first.
+func (r *ActivityEventRepository) UserParticipation(ctx context.Context, userID domain.UserID, since time.Time, limit int) ([]domain.CommunityParticipation, error) {
+	const query = `
+		SELECT community_id, COUNT(*), MAX(occurred_at)
+		FROM pulse.activity_events
+		WHERE user_id = $1 AND occurred_at >= $2
+		GROUP BY community_id
+		ORDER BY MAX(occurred_at) DESC
+		LIMIT $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID.UUID(), since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying user participation: %w", err)
+	}
+	defer rows.Close()
+
+	var participation []domain.CommunityParticipation
+	for rows.Next() {
+		var (
+			communityID  uuid.UUID
+			events       int64
+			lastActiveAt time.Time
+		)
+		if err := rows.Scan(&communityID, &events, &lastActiveAt); err != nil {
+			return nil, fmt.Errorf("scanning user participation: %w", err)
+		}
+		participation = append(participation, domain.CommunityParticipation{
+			CommunityID:  domain.CommunityIDFromUUID(communityID),
+			Events:       events,
+			LastActiveAt: lastActiveAt,
+		})
+	}
+	return participation, rows.Err()
+}
+
 func (r *ActivityEventRepository) scanEvents(rows pgx.Rows) ([]*domain.ActivityEvent, error) {
 	var events []*domain.ActivityEvent
 
@@ -609,10 +1068,11 @@ func (r *ActivityEventRepository) scanEvents(rows pgx.Rows) ([]*domain.ActivityE
 			eventType   string
 			weight      float64
 			metadata    []byte
+			occurredAt  time.Time
 			createdAt   time.Time
 		)
 
-		err := rows.Scan(&id, &communityID, &userID, &eventType, &weight, &metadata, &createdAt)
+		err := rows.Scan(&id, &communityID, &userID, &eventType, &weight, &metadata, &occurredAt, &createdAt)
 		if err != nil {
 			return nil, fmt.Errorf("scanning event row: %w", err)
 		}
@@ -661,6 +1121,7 @@ func (r *ActivityEventRepository) scanEvents(rows pgx.Rows) ([]*domain.ActivityE
 			eventTypeParsed,
 			weightParsed,
 			metadataMap,
+			occurredAt,
 			createdAt,
 		)
 		events = append(events, event)
@@ -684,3 +1145,10 @@ func derefString(s *string) string {
 	}
 	return *s
 }
+
+func nullableOrganizationID(id *domain.OrganizationID) any {
+	if id == nil {
+		return nil
+	}
+	return id.UUID()
+}
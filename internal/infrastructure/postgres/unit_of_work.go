@@ -70,3 +70,12 @@ func GetQuerier(ctx context.Context, pool *pgxpool.Pool) Querier {
 	}
 	return pool
 }
+
+// beginTx starts a transaction, or a savepoint when the context has the unit of work's
+// transaction, so a repository's own transaction can be part of a bigger one.
+func beginTx(ctx context.Context, pool *pgxpool.Pool) (pgx.Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Begin(ctx)
+	}
+	return pool.Begin(ctx)
+}
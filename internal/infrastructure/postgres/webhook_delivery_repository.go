@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// WebhookDeliveryRepository implements domain.WebhookDeliveryRepository using Postgres.
+type WebhookDeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookDeliveryRepository creates a new WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(pool *pgxpool.Pool) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{pool: pool}
+}
+
+// Save persists a delivery attempt. attempts on a subscription deleted meanwhile are dropped.
+func (r *WebhookDeliveryRepository) Save(ctx context.Context, d *domain.WebhookDelivery) error {
+	const query = `
+		INSERT INTO pulse.webhook_deliveries (id, subscription_id, event, status_code, latency_ms, error, attempted_at)
+		SELECT $1::uuid, id, $3::text, $4::integer, $5::integer, $6::text, $7::timestamptz
+		FROM pulse.webhook_subscriptions
+		WHERE id = $2
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		d.ID(),
+		d.SubscriptionID().String(),
+		d.Event(),
+		d.StatusCode(),
+		d.Latency().Milliseconds(),
+		d.Err(),
+		d.AttemptedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListBySubscription returns a subscription's attempts, newest first.
+func (r *WebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID domain.WebhookSubscriptionID, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	const query = `
+		SELECT id, event, status_code, latency_ms, error, attempted_at
+		FROM pulse.webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY attempted_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, subscriptionID.String(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		var (
+			id          uuid.UUID
+			event       string
+			deliveryErr string
+			statusCode  int
+			latencyMS   int64
+			attemptedAt time.Time
+		)
+		if err := rows.Scan(&id, &event, &statusCode, &latencyMS, &deliveryErr, &attemptedAt); err != nil {
+			return nil, fmt.Errorf("scanning webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, domain.ReconstructWebhookDelivery(
+			id,
+			subscriptionID,
+			event,
+			statusCode,
+			time.Duration(latencyMS)*time.Millisecond,
+			deliveryErr,
+			attemptedAt,
+		))
+	}
+	return deliveries, rows.Err()
+}
+
+// DeleteBefore removes the attempts made before t.
+func (r *WebhookDeliveryRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	const query = `DELETE FROM pulse.webhook_deliveries WHERE attempted_at < $1`
+
+	tag, err := r.pool.Exec(ctx, query, t)
+	if err != nil {
+		return 0, fmt.Errorf("deleting webhook deliveries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
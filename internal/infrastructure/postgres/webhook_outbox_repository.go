@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// WebhookOutboxRepository implements domain.WebhookOutboxRepository using Postgres.
+type WebhookOutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookOutboxRepository creates a new WebhookOutboxRepository.
+func NewWebhookOutboxRepository(pool *pgxpool.Pool) *WebhookOutboxRepository {
+	return &WebhookOutboxRepository{pool: pool}
+}
+
+// Add holds a notification until the entry's deliver_after.
+func (r *WebhookOutboxRepository) Add(ctx context.Context, entry *domain.WebhookOutboxEntry) error {
+	const query = `
+		INSERT INTO pulse.webhook_outbox (id, subscription_id, community_id, event, payload, created_at, deliver_after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		entry.ID(),
+		entry.SubscriptionID().String(),
+		entry.CommunityID().UUID(),
+		entry.Event(),
+		string(entry.Payload()),
+		entry.CreatedAt(),
+		entry.DeliverAfter(),
+	)
+	if err != nil {
+		return fmt.Errorf("saving webhook outbox entry: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue returns the oldest entries due at now and pushes them back by lease in the same
+// statement. SKIP LOCKED keeps instances claiming at the same time from sharing entries.
+func (r *WebhookOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookOutboxEntry, error) {
+	const query = `
+		UPDATE pulse.webhook_outbox o
+		SET deliver_after = $2
+		FROM (
+			SELECT id FROM pulse.webhook_outbox
+			WHERE deliver_after <= $1
+			ORDER BY deliver_after, created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE o.id = due.id
+		RETURNING o.id, o.subscription_id, o.community_id, o.event, o.payload, o.created_at, o.deliver_after
+	`
+
+	rows, err := r.pool.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("claiming webhook outbox entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.WebhookOutboxEntry
+	for rows.Next() {
+		var (
+			id             uuid.UUID
+			subscriptionID string
+			communityID    uuid.UUID
+			event          string
+			payload        []byte
+			createdAt      time.Time
+			deliverAfter   time.Time
+		)
+		if err := rows.Scan(&id, &subscriptionID, &communityID, &event, &payload, &createdAt, &deliverAfter); err != nil {
+			return nil, err
+		}
+
+		subID, err := domain.NewWebhookSubscriptionID(subscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, domain.ReconstructWebhookOutboxEntry(
+			id, subID, domain.CommunityIDFromUUID(communityID), event, payload, createdAt, deliverAfter,
+		))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Reschedule holds an entry until deliverAfter.
+func (r *WebhookOutboxRepository) Reschedule(ctx context.Context, id uuid.UUID, deliverAfter time.Time) error {
+	const query = `UPDATE pulse.webhook_outbox SET deliver_after = $2 WHERE id = $1`
+
+	result, err := r.pool.Exec(ctx, query, id, deliverAfter)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a delivered entry.
+func (r *WebhookOutboxRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM pulse.webhook_outbox WHERE id = $1`
+
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
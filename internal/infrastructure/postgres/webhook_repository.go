@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joacominatel/pulse/internal/domain"
@@ -20,23 +23,45 @@ func NewWebhookSubscriptionRepository(pool *pgxpool.Pool) *WebhookSubscriptionRe
 }
 
 // Save persists a webhook subscription (insert or update).
+// joins the unit of work's transaction when the context has one.
 func (r *WebhookSubscriptionRepository) Save(ctx context.Context, sub *domain.WebhookSubscription) error {
 	const query = `
-		INSERT INTO pulse.webhook_subscriptions (id, user_id, community_id, target_url, secret, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO pulse.webhook_subscriptions (id, user_id, community_id, target_url, secret, payload_version, delivery_mode, channel, proxy_url, pause_windows, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (user_id, community_id) DO UPDATE SET
 			target_url = EXCLUDED.target_url,
 			secret = EXCLUDED.secret,
+			payload_version = EXCLUDED.payload_version,
+			delivery_mode = EXCLUDED.delivery_mode,
+			channel = EXCLUDED.channel,
+			proxy_url = EXCLUDED.proxy_url,
+			pause_windows = EXCLUDED.pause_windows,
 			is_active = EXCLUDED.is_active,
 			updated_at = EXCLUDED.updated_at
 	`
 
-	_, err := r.pool.Exec(ctx, query,
+	// global subscriptions store a null community
+	var communityID any
+	if !sub.IsGlobal() {
+		communityID = sub.CommunityID().UUID()
+	}
+
+	pauseWindows, err := json.Marshal(toPauseWindowsJSON(sub.PauseWindows()))
+	if err != nil {
+		return fmt.Errorf("serializing pause windows: %w", err)
+	}
+
+	_, err = GetQuerier(ctx, r.pool).Exec(ctx, query,
 		sub.ID().String(),
 		sub.UserID().UUID(),
-		sub.CommunityID().UUID(),
+		communityID,
 		sub.TargetURL(),
 		sub.Secret(),
+		sub.PayloadVersion().String(),
+		sub.DeliveryMode().String(),
+		sub.Channel().String(),
+		sub.ProxyURL(),
+		string(pauseWindows),
 		sub.IsActive(),
 		sub.CreatedAt(),
 		sub.UpdatedAt(),
@@ -44,12 +69,26 @@ func (r *WebhookSubscriptionRepository) Save(ctx context.Context, sub *domain.We
 	return err
 }
 
-// FindByCommunity retrieves all active subscriptions for a community.
+// FindByCommunity retrieves all active subscriptions for a community, plus the global ones
+// when it's public. each half of the union uses its own partial index, and a user with both
+// a community and a global subscription only gets the community one.
 func (r *WebhookSubscriptionRepository) FindByCommunity(ctx context.Context, communityID domain.CommunityID) ([]*domain.WebhookSubscription, error) {
 	const query = `
-		SELECT id, user_id, community_id, target_url, secret, is_active, created_at, updated_at
+		SELECT id, user_id, community_id, target_url, secret, payload_version, delivery_mode, channel, proxy_url, pause_windows, is_active, created_at, updated_at
 		FROM pulse.webhook_subscriptions
 		WHERE community_id = $1 AND is_active = true
+		UNION ALL
+		SELECT s.id, s.user_id, s.community_id, s.target_url, s.secret, s.payload_version, s.delivery_mode, s.channel, s.proxy_url, s.pause_windows, s.is_active, s.created_at, s.updated_at
+		FROM pulse.webhook_subscriptions s
+		WHERE s.community_id IS NULL AND s.is_active = true
+		  AND EXISTS (
+			SELECT 1 FROM pulse.communities c
+			WHERE c.id = $1 AND c.visibility = 'public'
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM pulse.webhook_subscriptions own
+			WHERE own.user_id = s.user_id AND own.community_id = $1 AND own.is_active = true
+		  )
 	`
 
 	rows, err := r.pool.Query(ctx, query, communityID.UUID())
@@ -61,10 +100,34 @@ func (r *WebhookSubscriptionRepository) FindByCommunity(ctx context.Context, com
 	return r.scanSubscriptions(rows)
 }
 
+// FindByID retrieves a subscription, domain.ErrNotFound if it doesn't exist.
+func (r *WebhookSubscriptionRepository) FindByID(ctx context.Context, id domain.WebhookSubscriptionID) (*domain.WebhookSubscription, error) {
+	const query = `
+		SELECT id, user_id, community_id, target_url, secret, payload_version, delivery_mode, channel, proxy_url, pause_windows, is_active, created_at, updated_at
+		FROM pulse.webhook_subscriptions
+		WHERE id = $1
+	`
+
+	rows, err := r.pool.Query(ctx, query, id.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs, err := r.scanSubscriptions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return subs[0], nil
+}
+
 // FindByUser retrieves all subscriptions for a user.
 func (r *WebhookSubscriptionRepository) FindByUser(ctx context.Context, userID domain.UserID) ([]*domain.WebhookSubscription, error) {
 	const query = `
-		SELECT id, user_id, community_id, target_url, secret, is_active, created_at, updated_at
+		SELECT id, user_id, community_id, target_url, secret, payload_version, delivery_mode, channel, proxy_url, pause_windows, is_active, created_at, updated_at
 		FROM pulse.webhook_subscriptions
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -79,6 +142,89 @@ func (r *WebhookSubscriptionRepository) FindByUser(ctx context.Context, userID d
 	return r.scanSubscriptions(rows)
 }
 
+// ListByUser returns one page of a user's subscriptions matching the filter.
+// the filters are optional, a null parameter matches every subscription.
+func (r *WebhookSubscriptionRepository) ListByUser(ctx context.Context, userID domain.UserID, filter domain.WebhookSubscriptionFilter) ([]*domain.WebhookSubscription, error) {
+	// ties on the sort column are broken by id so pages don't overlap
+	orderBy := "created_at DESC, id"
+	switch filter.Order {
+	case domain.SubscriptionsOldestFirst:
+		orderBy = "created_at ASC, id"
+	case domain.SubscriptionsRecentlyUpdated:
+		orderBy = "updated_at DESC, id"
+	}
+
+	query := `
+		SELECT id, user_id, community_id, target_url, secret, payload_version, delivery_mode, channel, proxy_url, pause_windows, is_active, created_at, updated_at
+		FROM pulse.webhook_subscriptions
+		WHERE user_id = $1
+		  AND ($2::boolean IS NULL OR (CASE WHEN $2 THEN community_id IS NULL ELSE community_id = $3::uuid END))
+		  AND ($4::boolean IS NULL OR is_active = $4)
+		ORDER BY ` + orderBy + `
+		LIMIT $5 OFFSET $6
+	`
+
+	// $2 is null without a community filter, true for global subscriptions only
+	var (
+		byCommunity *bool
+		communityID *uuid.UUID
+	)
+	if filter.CommunityID != nil {
+		global := filter.CommunityID.IsZero()
+		byCommunity = &global
+		if !global {
+			id := filter.CommunityID.UUID()
+			communityID = &id
+		}
+	}
+
+	rows, err := r.pool.Query(ctx, query, userID.UUID(), byCommunity, communityID, filter.IsActive, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanSubscriptions(rows)
+}
+
+// FindAllByCommunity retrieves every subscription to a community, active or not, oldest first.
+func (r *WebhookSubscriptionRepository) FindAllByCommunity(ctx context.Context, communityID domain.CommunityID) ([]*domain.WebhookSubscription, error) {
+	const query = `
+		SELECT id, user_id, community_id, target_url, secret, payload_version, delivery_mode, channel, proxy_url, pause_windows, is_active, created_at, updated_at
+		FROM pulse.webhook_subscriptions
+		WHERE community_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, communityID.UUID())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanSubscriptions(rows)
+}
+
+// FindByOrganization retrieves every subscription to the communities of an organization,
+// active or not, oldest first.
+func (r *WebhookSubscriptionRepository) FindByOrganization(ctx context.Context, orgID domain.OrganizationID) ([]*domain.WebhookSubscription, error) {
+	const query = `
+		SELECT s.id, s.user_id, s.community_id, s.target_url, s.secret, s.payload_version, s.delivery_mode, s.channel, s.proxy_url, s.pause_windows, s.is_active, s.created_at, s.updated_at
+		FROM pulse.webhook_subscriptions s
+		JOIN pulse.communities c ON c.id = s.community_id
+		WHERE c.organization_id = $1
+		ORDER BY s.created_at, s.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, orgID.UUID())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanSubscriptions(rows)
+}
+
 // Delete removes a subscription.
 func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id domain.WebhookSubscriptionID) error {
 	const query = `DELETE FROM pulse.webhook_subscriptions WHERE id = $1`
@@ -103,20 +249,30 @@ func (r *WebhookSubscriptionRepository) scanSubscriptions(rows pgx.Rows) ([]*dom
 		var (
 			id          string
 			userID      string
-			communityID string
+			communityID *string
 			targetURL   string
 			secret      string
+			version     string
+			mode        string
+			channel     string
+			proxyURL    string
+			pauseJSON   []byte
 			isActive    bool
 			createdAt   time.Time
 			updatedAt   time.Time
 		)
 
-		err := rows.Scan(&id, &userID, &communityID, &targetURL, &secret, &isActive, &createdAt, &updatedAt)
+		err := rows.Scan(&id, &userID, &communityID, &targetURL, &secret, &version, &mode, &channel, &proxyURL, &pauseJSON, &isActive, &createdAt, &updatedAt)
 		if err != nil {
 			return nil, err
 		}
 
-		sub, err := r.buildSubscription(id, userID, communityID, targetURL, secret, isActive, createdAt, updatedAt)
+		var pauseWindows []pauseWindowJSON
+		if err := json.Unmarshal(pauseJSON, &pauseWindows); err != nil {
+			return nil, fmt.Errorf("parsing pause windows: %w", err)
+		}
+
+		sub, err := r.buildSubscription(id, userID, communityID, targetURL, secret, version, mode, channel, proxyURL, fromPauseWindowsJSON(pauseWindows), isActive, createdAt, updatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -132,7 +288,10 @@ func (r *WebhookSubscriptionRepository) scanSubscriptions(rows pgx.Rows) ([]*dom
 
 // buildSubscription constructs a domain subscription from raw values.
 func (r *WebhookSubscriptionRepository) buildSubscription(
-	id, userID, communityID, targetURL, secret string,
+	id, userID string,
+	communityID *string,
+	targetURL, secret, version, mode, channel, proxyURL string,
+	pauseWindows []domain.DeliveryPauseWindow,
 	isActive bool,
 	createdAt, updatedAt time.Time,
 ) (*domain.WebhookSubscription, error) {
@@ -146,9 +305,13 @@ func (r *WebhookSubscriptionRepository) buildSubscription(
 		return nil, err
 	}
 
-	domainCommunityID, err := domain.ParseCommunityID(communityID)
-	if err != nil {
-		return nil, err
+	// null for global subscriptions, which keep the zero community id
+	var domainCommunityID domain.CommunityID
+	if communityID != nil {
+		domainCommunityID, err = domain.ParseCommunityID(*communityID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return domain.ReconstructWebhookSubscription(
@@ -157,8 +320,40 @@ func (r *WebhookSubscriptionRepository) buildSubscription(
 		domainCommunityID,
 		targetURL,
 		secret,
+		domain.WebhookPayloadVersion(version),
+		domain.WebhookDeliveryMode(mode),
+		domain.NotificationChannel(channel),
+		proxyURL,
+		pauseWindows,
 		isActive,
 		createdAt,
 		updatedAt,
 	), nil
 }
+
+// pauseWindowJSON is how a pause window is stored in webhook_subscriptions.pause_windows.
+type pauseWindowJSON struct {
+	Day         string `json:"day,omitempty"`
+	StartMinute int    `json:"start_minute"`
+	EndMinute   int    `json:"end_minute"`
+	Timezone    string `json:"timezone"`
+}
+
+func toPauseWindowsJSON(windows []domain.DeliveryPauseWindow) []pauseWindowJSON {
+	stored := make([]pauseWindowJSON, len(windows))
+	for i, w := range windows {
+		stored[i] = pauseWindowJSON{Day: w.Day(), StartMinute: w.StartMinute(), EndMinute: w.EndMinute(), Timezone: w.Timezone()}
+	}
+	return stored
+}
+
+func fromPauseWindowsJSON(stored []pauseWindowJSON) []domain.DeliveryPauseWindow {
+	if len(stored) == 0 {
+		return nil
+	}
+	windows := make([]domain.DeliveryPauseWindow, len(stored))
+	for i, w := range stored {
+		windows[i] = domain.ReconstructDeliveryPauseWindow(w.Day, w.StartMinute, w.EndMinute, w.Timezone)
+	}
+	return windows
+}
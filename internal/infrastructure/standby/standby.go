@@ -0,0 +1,61 @@
+// Package standby switches an instance between active and warm standby.
+// a standby serves reads and health checks but takes no writes and runs no background
+// work, so a second deployment can wait, connected and warm, to take over the worker
+// role in a blue/green deploy.
+package standby
+
+import (
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// Switch holds whether the instance is a standby. safe for concurrent use.
+type Switch struct {
+	mu      sync.RWMutex
+	standby bool
+	since   time.Time
+	logger  *logging.Logger
+}
+
+// New creates a switch, starting in standby or active.
+func New(standby bool, logger *logging.Logger) *Switch {
+	return &Switch{
+		standby: standby,
+		since:   time.Now().UTC(),
+		logger:  logger.WithComponent("standby"),
+	}
+}
+
+// Standby reports whether the instance is a standby.
+func (s *Switch) Standby() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.standby
+}
+
+// Since returns when the instance entered its current mode, or started.
+func (s *Switch) Since() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.since
+}
+
+// Set makes the instance a standby or active, reporting whether that changed its mode.
+func (s *Switch) Set(standby bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.standby == standby {
+		return false
+	}
+	s.standby = standby
+	s.since = time.Now().UTC()
+	if standby {
+		s.logger.Warn("instance is now a standby: writes rejected, background work stopped")
+	} else {
+		s.logger.Info("instance is now active")
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// AnomalyDetector flags anomalous ingest rates. implemented by application.AnomalyUseCase.
+type AnomalyDetector interface {
+	Detect(ctx context.Context) (*application.DetectAnomaliesOutput, error)
+}
+
+// AnomalyWorkerConfig holds configuration for the anomaly worker.
+type AnomalyWorkerConfig struct {
+	// Interval is how often ingest rates are checked.
+	Interval time.Duration
+
+	// Timeout bounds a single detection run.
+	Timeout time.Duration
+}
+
+// DefaultAnomalyWorkerConfig returns sensible defaults.
+func DefaultAnomalyWorkerConfig() AnomalyWorkerConfig {
+	return AnomalyWorkerConfig{
+		Interval: 5 * time.Minute,
+		Timeout:  time.Minute,
+	}
+}
+
+// AnomalyWorker periodically looks for suspicious ingest rates.
+type AnomalyWorker struct {
+	detector  AnomalyDetector
+	config    AnomalyWorkerConfig
+	logger    *logging.Logger
+	metrics   PanicRecorder
+	heartbeat Heartbeat
+	standby   StandbyState
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewAnomalyWorker creates a new anomaly worker.
+func NewAnomalyWorker(detector AnomalyDetector, config AnomalyWorkerConfig, logger *logging.Logger) *AnomalyWorker {
+	return &AnomalyWorker{
+		detector: detector,
+		config:   config,
+		logger:   logger.WithComponent("anomaly_worker"),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *AnomalyWorker) WithMetrics(m PanicRecorder) *AnomalyWorker {
+	w.metrics = m
+	return w
+}
+
+// WithHeartbeat beats "anomaly" after every run.
+func (w *AnomalyWorker) WithHeartbeat(h Heartbeat) *AnomalyWorker {
+	w.heartbeat = h
+	return w
+}
+
+// WithStandby skips detection while the instance is a standby.
+func (w *AnomalyWorker) WithStandby(s StandbyState) *AnomalyWorker {
+	w.standby = s
+	return w
+}
+
+// Start begins checking every interval.
+func (w *AnomalyWorker) Start(ctx context.Context) {
+	w.logger.Info("anomaly worker starting",
+		"interval", w.config.Interval.String(),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "anomaly", 0, w.logger, w.metrics, w.run)
+	}()
+}
+
+// Stop stops the ticker, waiting for a run in progress.
+func (w *AnomalyWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		close(w.stopped)
+		w.logger.Info("anomaly worker stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *AnomalyWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *AnomalyWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !inStandby(w.standby) {
+				w.detect(ctx)
+			}
+			if w.heartbeat != nil {
+				w.heartbeat.Beat("anomaly")
+			}
+		}
+	}
+}
+
+func (w *AnomalyWorker) detect(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	// errors are logged by the use case; the next run checks again
+	_, _ = w.detector.Detect(ctx)
+}
@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// ErrImportQueueFull is returned by Enqueue when every queue slot is taken.
+var ErrImportQueueFull = errors.New("too many event imports queued, try again later")
+
+// errImportNotStarted is recorded on queued imports dropped by a shutdown.
+var errImportNotStarted = errors.New("server shut down before the import started, upload the file again")
+
+// EventImportRunner imports an uploaded file. implemented by application.EventImportUseCase.
+type EventImportRunner interface {
+	Run(ctx context.Context, imp *domain.EventImport, source io.Reader) error
+	Fail(ctx context.Context, imp *domain.EventImport, cause error) error
+}
+
+// importTask is an import waiting for the worker, with the file its upload was spooled to.
+type importTask struct {
+	imp  *domain.EventImport
+	path string
+}
+
+// EventImportWorker runs bulk event imports one at a time, in upload order, so a large
+// import never competes with another for the database. uploads are spooled to files
+// by the API and deleted once imported.
+type EventImportWorker struct {
+	runner EventImportRunner
+	logger *logging.Logger
+	queue  chan importTask
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewEventImportWorker creates a new event import worker holding up to queueSize waiting imports.
+func NewEventImportWorker(runner EventImportRunner, queueSize int, logger *logging.Logger) *EventImportWorker {
+	return &EventImportWorker{
+		runner:  runner,
+		logger:  logger.WithComponent("event_import_worker"),
+		queue:   make(chan importTask, queueSize),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Enqueue hands an uploaded file to the worker, which owns and deletes it from then on.
+// never blocks: returns ErrImportQueueFull when the queue is full, leaving the file to the caller.
+func (w *EventImportWorker) Enqueue(imp *domain.EventImport, path string) error {
+	select {
+	case w.queue <- importTask{imp: imp, path: path}:
+		return nil
+	default:
+		return ErrImportQueueFull
+	}
+}
+
+// Start begins running queued imports.
+func (w *EventImportWorker) Start(ctx context.Context) {
+	w.logger.Info("event import worker starting",
+		"queue_size", cap(w.queue),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "event_import", 0, w.logger, nil, w.run)
+	}()
+}
+
+// Stop interrupts the running import and fails the queued ones, which would be lost anyway.
+// both are recorded as failed, so their uploaders know to try again.
+func (w *EventImportWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+
+		for {
+			select {
+			case task := <-w.queue:
+				_ = w.runner.Fail(context.Background(), task.imp, errImportNotStarted)
+				w.remove(task.path)
+			default:
+				close(w.stopped)
+				w.logger.Info("event import worker stopped")
+				return
+			}
+		}
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *EventImportWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *EventImportWorker) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-w.queue:
+			w.process(ctx, task)
+		}
+	}
+}
+
+// process imports one file and deletes it. the outcome is recorded and logged by the runner.
+func (w *EventImportWorker) process(ctx context.Context, task importTask) {
+	defer w.remove(task.path)
+
+	f, err := os.Open(task.path)
+	if err != nil {
+		_ = w.runner.Fail(ctx, task.imp, fmt.Errorf("opening upload: %w", err))
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	_ = w.runner.Run(ctx, task.imp, f)
+}
+
+func (w *EventImportWorker) remove(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		w.logger.Warn("failed to delete import upload",
+			"path", path,
+			"error", err.Error(),
+		)
+	}
+}
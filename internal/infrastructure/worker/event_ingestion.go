@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/joacominatel/pulse/internal/application"
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 )
@@ -12,8 +13,13 @@ import (
 // MetricsRecorder abstracts prometheus metrics for the ingestion worker.
 // keeps worker decoupled from metrics package.
 type MetricsRecorder interface {
-	RecordEventIngested(communityID, eventType string)
+	PanicRecorder
+	RecordEventsIngested(eventType string, count int)
+	RecordCommunityEventsIngested(communityID string, count int)
+	RecordIngestionBatch(size int)
+	RecordStaleEvents(count int)
 	SetBufferSize(size int)
+	SetBufferCapacity(capacity int)
 }
 
 // EventIngestionWorkerConfig holds configuration for the ingestion worker.
@@ -30,6 +36,11 @@ type EventIngestionWorkerConfig struct {
 
 	// WorkerCount is the number of concurrent workers processing events.
 	WorkerCount int
+
+	// MaxQueueAge is how long after it was received an event may still be saved, 0 for no limit.
+	// the client got its response long ago, but one that timed out first has retried the
+	// event, and saving both would count it twice. older events are quarantined as stale.
+	MaxQueueAge time.Duration
 }
 
 // DefaultEventIngestionConfig returns sensible defaults for the worker.
@@ -39,17 +50,25 @@ func DefaultEventIngestionConfig() EventIngestionWorkerConfig {
 		BatchSize:     100,   // larger batches for efficiency
 		FlushInterval: 500 * time.Millisecond,
 		WorkerCount:   4, // more workers for parallel DB writes
+		MaxQueueAge:   30 * time.Second,
 	}
 }
 
 // EventIngestionWorker processes activity events from a buffered channel.
 // implements batch saving to reduce database roundtrips.
 type EventIngestionWorker struct {
-	eventChan chan *domain.ActivityEvent
-	repo      domain.ActivityEventRepository
-	config    EventIngestionWorkerConfig
-	logger    *logging.Logger
-	metrics   MetricsRecorder
+	eventChan    chan *domain.ActivityEvent
+	repo         domain.ActivityEventRepository
+	config       EventIngestionWorkerConfig
+	logger       *logging.Logger
+	metrics      MetricsRecorder
+	meter        UsageMeter
+	contributors ContributorTracker
+	validator    EventValidator
+	heartbeat    Heartbeat
+	late         LateEventTracker
+	quarantine   domain.RejectedEventRepository
+	acks         persistAcks
 
 	wg       sync.WaitGroup
 	stopOnce sync.Once
@@ -77,6 +96,48 @@ func (w *EventIngestionWorker) WithMetrics(m MetricsRecorder) *EventIngestionWor
 	return w
 }
 
+// WithMeter meters saved events per community for billing.
+func (w *EventIngestionWorker) WithMeter(m UsageMeter) *EventIngestionWorker {
+	w.meter = m
+	return w
+}
+
+// ContributorTracker counts the distinct users behind saved events. implemented by the redis client.
+type ContributorTracker interface {
+	TrackContributors(ctx context.Context, events []*domain.ActivityEvent) error
+}
+
+// WithContributors tracks unique contributors per community on every flush.
+func (w *EventIngestionWorker) WithContributors(t ContributorTracker) *EventIngestionWorker {
+	w.contributors = t
+	return w
+}
+
+// EventValidator filters a batch down to the events that may be stored.
+// implemented by application.DeferredValidator.
+type EventValidator interface {
+	Validate(ctx context.Context, events []*domain.ActivityEvent) ([]*domain.ActivityEvent, error)
+}
+
+// WithValidator checks every batch before it's saved, for events accepted with deferred validation.
+func (w *EventIngestionWorker) WithValidator(v EventValidator) *EventIngestionWorker {
+	w.validator = v
+	return w
+}
+
+// WithQuarantine keeps stale events in the rejected events table, where admins can review
+// them, instead of only logging them. see EventIngestionWorkerConfig.MaxQueueAge.
+func (w *EventIngestionWorker) WithQuarantine(repo domain.RejectedEventRepository) *EventIngestionWorker {
+	w.quarantine = repo
+	return w
+}
+
+// WithHeartbeat beats "ingestion" on every flush tick, so a worker stuck on a flush shows up.
+func (w *EventIngestionWorker) WithHeartbeat(h Heartbeat) *EventIngestionWorker {
+	w.heartbeat = h
+	return w
+}
+
 // EventChannel returns the channel for submitting events.
 // use this to push events from the use case.
 func (w *EventIngestionWorker) EventChannel() chan<- *domain.ActivityEvent {
@@ -93,9 +154,19 @@ func (w *EventIngestionWorker) Start(ctx context.Context) {
 		"worker_count", w.config.WorkerCount,
 	)
 
+	if w.metrics != nil {
+		w.metrics.SetBufferCapacity(w.config.BufferSize)
+	}
+
 	for i := 0; i < w.config.WorkerCount; i++ {
 		w.wg.Add(1)
-		go w.runWorker(ctx, i)
+		go func(workerID int) {
+			defer w.wg.Done()
+			// restart the loop if a panic escapes, otherwise throughput silently drops
+			supervise(ctx, "event_ingestion", workerID, w.logger, w.metrics, func(ctx context.Context) {
+				w.runWorker(ctx, workerID)
+			})
+		}(i)
 	}
 }
 
@@ -126,8 +197,10 @@ func (w *EventIngestionWorker) QueueSize() int {
 }
 
 // runWorker is the main worker loop.
+// it runs until Stop rather than until ctx is canceled, so events accepted
+// before a graceful shutdown are still saved.
 func (w *EventIngestionWorker) runWorker(ctx context.Context, workerID int) {
-	defer w.wg.Done()
+	ctx = context.WithoutCancel(ctx)
 
 	batch := make([]*domain.ActivityEvent, 0, w.config.BatchSize)
 	ticker := time.NewTicker(w.config.FlushInterval)
@@ -162,12 +235,9 @@ func (w *EventIngestionWorker) runWorker(ctx context.Context, workerID int) {
 		case <-ticker.C:
 			// flush partial batch on timeout
 			flush()
-
-		case <-ctx.Done():
-			// context cancelled, flush and exit
-			flush()
-			w.logger.Debug("worker exiting on context cancel", "worker_id", workerID)
-			return
+			if w.heartbeat != nil {
+				w.heartbeat.Beat("ingestion")
+			}
 		}
 	}
 }
@@ -180,6 +250,27 @@ func (w *EventIngestionWorker) flushBatch(ctx context.Context, batch []*domain.A
 
 	start := time.Now()
 
+	if batch = w.dropStale(ctx, batch, workerID); len(batch) == 0 {
+		return
+	}
+
+	if w.validator != nil {
+		valid, err := w.validator.Validate(ctx, batch)
+		if err != nil {
+			w.logger.Error("batch validation failed",
+				"worker_id", workerID,
+				"batch_size", len(batch),
+				"error", err.Error(),
+			)
+			w.ack(batch, application.ErrEventNotPersisted)
+			return
+		}
+		w.ackFiltered(batch, valid)
+		if batch = valid; len(batch) == 0 {
+			return
+		}
+	}
+
 	// use bulk insert for efficiency
 	err := w.repo.SaveBatch(ctx, batch)
 	duration := time.Since(start)
@@ -191,14 +282,14 @@ func (w *EventIngestionWorker) flushBatch(ctx context.Context, batch []*domain.A
 			"error", err.Error(),
 			"duration_ms", duration.Milliseconds(),
 		)
+		w.ack(batch, application.ErrEventNotPersisted)
 		return
 	}
+	w.ack(batch, nil)
 
-	// record metrics for successfully saved events
+	w.afterSave(ctx, batch)
 	if w.metrics != nil {
-		for _, event := range batch {
-			w.metrics.RecordEventIngested(event.CommunityID().String(), string(event.EventType()))
-		}
+		w.metrics.RecordIngestionBatch(len(batch))
 		// update buffer size after flush
 		w.metrics.SetBufferSize(len(w.eventChan))
 	}
@@ -210,6 +301,95 @@ func (w *EventIngestionWorker) flushBatch(ctx context.Context, batch []*domain.A
 	)
 }
 
+// EventsSaved implements application.SavedEventsHandler, for events saved without going
+// through the buffer, like event groups.
+func (w *EventIngestionWorker) EventsSaved(ctx context.Context, events []*domain.ActivityEvent) {
+	w.afterSave(ctx, events)
+}
+
+// afterSave records saved events in the metrics, the usage meter, the contributor counts
+// and the late event tracker.
+func (w *EventIngestionWorker) afterSave(ctx context.Context, batch []*domain.ActivityEvent) {
+	// record metrics for successfully saved events, one counter update per event type and community
+	if w.metrics != nil {
+		byType := make(map[domain.EventType]int)
+		byCommunity := make(map[domain.CommunityID]int)
+		for _, event := range batch {
+			byType[event.EventType()]++
+			byCommunity[event.CommunityID()]++
+		}
+		for eventType, count := range byType {
+			w.metrics.RecordEventsIngested(eventType.String(), count)
+		}
+		for communityID, count := range byCommunity {
+			w.metrics.RecordCommunityEventsIngested(communityID.String(), count)
+		}
+	}
+
+	if w.meter != nil {
+		for _, event := range batch {
+			w.meter.Add(domain.MeterSubjectCommunity, event.CommunityID().String(), domain.MeterEventsIngested, 1)
+		}
+	}
+
+	// the events are saved, a failed count only makes the estimate a little low
+	if w.contributors != nil {
+		if err := w.contributors.TrackContributors(ctx, batch); err != nil {
+			w.logger.Warn("tracking contributors failed",
+				"batch_size", len(batch),
+				"error", err.Error(),
+			)
+		}
+	}
+
+	// only once they're saved, a recalculation before that wouldn't see them
+	if w.late != nil {
+		w.late.TrackLate(batch)
+	}
+}
+
+// dropStale returns the events received within MaxQueueAge and quarantines the rest.
+func (w *EventIngestionWorker) dropStale(ctx context.Context, batch []*domain.ActivityEvent, workerID int) []*domain.ActivityEvent {
+	if w.config.MaxQueueAge <= 0 {
+		return batch
+	}
+
+	deadline := time.Now().Add(-w.config.MaxQueueAge)
+	fresh := make([]*domain.ActivityEvent, 0, len(batch))
+	var stale []*domain.RejectedEvent
+	for _, event := range batch {
+		if event.CreatedAt().Before(deadline) {
+			stale = append(stale, domain.NewRejectedEvent(domain.SystemClock, event, domain.RejectStale))
+			continue
+		}
+		fresh = append(fresh, event)
+	}
+	if len(stale) == 0 {
+		return batch
+	}
+
+	if w.metrics != nil {
+		w.metrics.RecordStaleEvents(len(stale))
+	}
+	w.logger.Warn("stale events dropped",
+		"worker_id", workerID,
+		"stale", len(stale),
+		"batch_size", len(batch),
+		"max_queue_age", w.config.MaxQueueAge.String(),
+	)
+	w.ackRejected(stale)
+	if w.quarantine != nil {
+		if err := w.quarantine.SaveBatch(ctx, stale); err != nil {
+			w.logger.Error("saving stale events failed",
+				"worker_id", workerID,
+				"stale", len(stale),
+				"error", err.Error(),
+			)
+		}
+	}
+	return fresh
+}
+
 // Stats returns current worker statistics.
 type IngestionStats struct {
 	QueueSize   int
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// savedEvents is an event repository that records saved batches, failing on a canceled
+// context like the postgres one does when it begins the transaction.
+type savedEvents struct {
+	domain.ActivityEventRepository
+
+	mu     sync.Mutex
+	events []*domain.ActivityEvent
+}
+
+func (r *savedEvents) SaveBatch(ctx context.Context, events []*domain.ActivityEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *savedEvents) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func TestEventIngestionWorker_StopSavesBufferedEventsAfterCancel(t *testing.T) {
+	repo := &savedEvents{}
+	w := NewEventIngestionWorker(repo, EventIngestionWorkerConfig{
+		BufferSize:    100,
+		BatchSize:     10,
+		FlushInterval: time.Hour, // only full batches and the drain flush
+		WorkerCount:   2,
+	}, logging.NewWithWriter(io.Discard, slog.LevelError))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Start(ctx)
+
+	const total = 25 // two full batches and a partial one
+	communityID := domain.NewCommunityID()
+	for range total {
+		event, err := domain.NewActivityEvent(domain.SystemClock, communityID, nil, domain.EventTypePost, domain.DefaultEventWeight(), nil)
+		if err != nil {
+			t.Fatalf("NewActivityEvent: %v", err)
+		}
+		w.EventChannel() <- event
+	}
+
+	// the server cancels the workers' context before stopping them
+	cancel()
+	w.Stop()
+
+	if got := repo.count(); got != total {
+		t.Errorf("saved %d events, want all %d accepted before shutdown", got, total)
+	}
+}
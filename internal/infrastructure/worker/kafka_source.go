@@ -0,0 +1,350 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+const (
+	// kafkaIdlePoll is how long the source waits for a message before checking for standby.
+	kafkaIdlePoll = 5 * time.Second
+
+	// kafkaRetryInitial and kafkaRetryMax bound the backoff before events that weren't
+	// saved are sent to the ingestion buffer again.
+	kafkaRetryInitial = 500 * time.Millisecond
+	kafkaRetryMax     = 30 * time.Second
+)
+
+// EventIngester ingests one activity event. implemented by application.IngestEventUseCase.
+type EventIngester interface {
+	Execute(ctx context.Context, input application.IngestEventInput) (*application.IngestEventOutput, error)
+}
+
+// KafkaIngestionConfig holds configuration for the kafka ingestion source.
+type KafkaIngestionConfig struct {
+	// Brokers are the kafka bootstrap servers, host:port.
+	Brokers []string
+
+	// Topic is where activity events are read from, one JSON event per message.
+	Topic string
+
+	// GroupID is the consumer group, whose committed offsets say where consumption resumes.
+	GroupID string
+
+	// BatchSize is how many messages are ingested before their offsets are committed.
+	BatchSize int
+
+	// BatchWait is how long a batch waits to fill before it's ingested anyway.
+	BatchWait time.Duration
+
+	// AckTimeout is how long an event may wait for its batch to be saved before it's
+	// sent again. long, since a resent event is counted twice if the first one is saved.
+	AckTimeout time.Duration
+}
+
+// DefaultKafkaIngestionConfig returns sensible defaults, without brokers or a topic.
+func DefaultKafkaIngestionConfig() KafkaIngestionConfig {
+	return KafkaIngestionConfig{
+		GroupID:    "pulse",
+		BatchSize:  500,
+		BatchWait:  time.Second,
+		AckTimeout: time.Minute,
+	}
+}
+
+// kafkaEventMessage is an activity event as published on the topic, like the body of
+// POST /api/v1/events with the user that would otherwise come from the token.
+type kafkaEventMessage struct {
+	CommunityID string         `json:"community_id"`
+	UserID      *string        `json:"user_id"`
+	EventType   string         `json:"event_type"`
+	Weight      *float64       `json:"weight"`
+	Metadata    map[string]any `json:"metadata"`
+	OccurredAt  *time.Time     `json:"occurred_at"`
+	Region      string         `json:"region"`
+}
+
+// KafkaIngestionSource consumes activity events from a kafka topic and ingests them like
+// events sent to the API, through the ingestion buffer. offsets are committed once every
+// event of a batch is saved or rejected, so events are delivered at least once: a crash
+// before the commit sends the batch again.
+//
+// events that fail validation are logged and skipped, since sending them again can't
+// help. events that weren't saved, because the buffer was full, they waited in it past
+// its deadline, or a flush or lookup failed, are sent again with backoff, holding the
+// batch's commit until they are.
+type KafkaIngestionSource struct {
+	ingester EventIngester
+	config   KafkaIngestionConfig
+	logger   *logging.Logger
+	standby  StandbyState
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewKafkaIngestionSource creates a new kafka ingestion source.
+// the ingester should queue events for the ingestion worker and acknowledge them once saved.
+func NewKafkaIngestionSource(ingester EventIngester, config KafkaIngestionConfig, logger *logging.Logger) *KafkaIngestionSource {
+	return &KafkaIngestionSource{
+		ingester: ingester,
+		config:   config,
+		logger:   logger.WithComponent("kafka_ingestion"),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// WithStandby leaves the consumer group while the instance is a standby,
+// so the active instances get its partitions.
+func (w *KafkaIngestionSource) WithStandby(s StandbyState) *KafkaIngestionSource {
+	w.standby = s
+	return w
+}
+
+// Start begins consuming the topic.
+func (w *KafkaIngestionSource) Start(ctx context.Context) {
+	w.logger.Info("kafka ingestion source starting",
+		"brokers", strings.Join(w.config.Brokers, ","),
+		"topic", w.config.Topic,
+		"group_id", w.config.GroupID,
+		"batch_size", w.config.BatchSize,
+		"batch_wait", w.config.BatchWait.String(),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "kafka_ingestion", 0, w.logger, nil, w.run)
+	}()
+}
+
+// Stop stops consuming. a batch in progress is abandoned without committing its offsets,
+// the next consumer of its partitions reads it again.
+func (w *KafkaIngestionSource) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		close(w.stopped)
+		w.logger.Info("kafka ingestion source stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the source has fully stopped.
+func (w *KafkaIngestionSource) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *KafkaIngestionSource) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if inStandby(w.standby) {
+			sleepCtx(ctx, kafkaIdlePoll)
+			continue
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     w.config.Brokers,
+			Topic:       w.config.Topic,
+			GroupID:     w.config.GroupID,
+			StartOffset: kafka.FirstOffset,
+			ErrorLogger: kafka.LoggerFunc(func(msg string, args ...any) {
+				w.logger.Warn("kafka reader error", "reason", fmt.Sprintf(msg, args...))
+			}),
+		})
+		w.consume(ctx, reader)
+		if err := reader.Close(); err != nil {
+			w.logger.Warn("closing kafka reader failed", "error", err.Error())
+		}
+	}
+}
+
+// consume ingests batches until the context ends or the instance becomes a standby.
+func (w *KafkaIngestionSource) consume(ctx context.Context, reader *kafka.Reader) {
+	for !inStandby(w.standby) {
+		batch, err := w.fetch(ctx, reader)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			w.logger.Warn("kafka fetch failed", "error", err.Error())
+			sleepCtx(ctx, kafkaRetryInitial)
+			continue
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		if !w.ingest(ctx, batch) {
+			return
+		}
+		if err := reader.CommitMessages(ctx, batch...); err != nil {
+			// the events are saved, they'll be consumed again if the partition moves first
+			w.logger.Warn("kafka offset commit failed",
+				"messages", len(batch),
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+// fetch reads up to a batch of messages. it waits kafkaIdlePoll for the first one,
+// returning an empty batch if none came, then up to BatchWait for the rest.
+func (w *KafkaIngestionSource) fetch(ctx context.Context, reader *kafka.Reader) ([]kafka.Message, error) {
+	idleCtx, cancel := context.WithTimeout(ctx, kafkaIdlePoll)
+	first, err := reader.FetchMessage(idleCtx)
+	cancel()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	batch := []kafka.Message{first}
+	batchCtx, cancel := context.WithTimeout(ctx, w.config.BatchWait)
+	defer cancel()
+	for len(batch) < w.config.BatchSize {
+		msg, err := reader.FetchMessage(batchCtx)
+		if err != nil {
+			// the batch goes as it is, a lasting error shows on the next fetch
+			break
+		}
+		batch = append(batch, msg)
+	}
+	return batch, nil
+}
+
+// ingest sends a batch's events to the ingestion buffer and waits for them to be saved,
+// sending the ones that weren't again with backoff. false when the context ended first.
+func (w *KafkaIngestionSource) ingest(ctx context.Context, batch []kafka.Message) bool {
+	pending := batch
+	backoff := kafkaRetryInitial
+	for {
+		pending = w.ingestAll(ctx, pending)
+		if len(pending) == 0 {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		w.logger.Warn("kafka events not saved, retrying",
+			"events", len(pending),
+			"backoff", backoff.String(),
+		)
+		if !sleepCtx(ctx, backoff) {
+			return false
+		}
+		backoff = min(backoff*2, kafkaRetryMax)
+	}
+}
+
+// ingestAll ingests messages concurrently, so they share the ingestion worker's flushes,
+// and returns the ones to send again.
+func (w *KafkaIngestionSource) ingestAll(ctx context.Context, msgs []kafka.Message) []kafka.Message {
+	retry := make([]bool, len(msgs))
+	var wg sync.WaitGroup
+	for i := range msgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			retry[i] = w.ingestOne(ctx, msgs[i])
+		}()
+	}
+	wg.Wait()
+
+	var pending []kafka.Message
+	for i, msg := range msgs {
+		if retry[i] {
+			pending = append(pending, msg)
+		}
+	}
+	return pending
+}
+
+// ingestOne ingests one message, true when it should be sent again.
+func (w *KafkaIngestionSource) ingestOne(ctx context.Context, msg kafka.Message) bool {
+	var event kafkaEventMessage
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		w.reject(msg, fmt.Errorf("invalid JSON: %w", err))
+		return false
+	}
+
+	output, err := w.ingester.Execute(ctx, application.IngestEventInput{
+		CommunityID: event.CommunityID,
+		UserID:      event.UserID,
+		EventType:   event.EventType,
+		Weight:      event.Weight,
+		Metadata:    event.Metadata,
+		OccurredAt:  event.OccurredAt,
+		Region:      event.Region,
+		Ack:         application.AckPersisted,
+		AckTimeout:  w.config.AckTimeout,
+	})
+	switch {
+	case err == nil:
+		// not persisted means still queued when the ack timeout passed, so not known to be saved
+		return !output.Persisted && !output.Muted
+	case retryableIngestError(err):
+		return true
+	default:
+		w.reject(msg, err)
+		return false
+	}
+}
+
+// reject logs a message that can't be ingested. its offset is committed with the batch.
+func (w *KafkaIngestionSource) reject(msg kafka.Message, reason error) {
+	w.logger.Warn("kafka event rejected",
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+		"reason", reason.Error(),
+		"outcome", "rejected",
+	)
+}
+
+// retryableIngestError reports whether an event failed for a reason other than itself:
+// a full buffer, a failed flush, a wait in the buffer past its deadline, or a lookup that
+// couldn't reach its store.
+func retryableIngestError(err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return false
+	case errors.Is(err, application.ErrEventBufferFull),
+		errors.Is(err, application.ErrEventNotPersisted),
+		errors.Is(err, application.ErrEventStale),
+		errors.Is(err, application.ErrLookupFailed),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepCtx waits for d, false if the context ended first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,201 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// LateEventTracker is told about every saved batch, to spot events that arrived late.
+// implemented by LateEventWorker.
+type LateEventTracker interface {
+	TrackLate(events []*domain.ActivityEvent)
+}
+
+// WithLateEvents reports every saved batch to a tracker, see LateEventWorker.
+func (w *EventIngestionWorker) WithLateEvents(t LateEventTracker) *EventIngestionWorker {
+	w.late = t
+	return w
+}
+
+// LateRecalculationRunner recalculates a community that got late events.
+// implemented by application.CalculateMomentumUseCase.
+type LateRecalculationRunner interface {
+	RecalculateLate(ctx context.Context, input application.RecalculateLateInput) (*application.CalculateMomentumOutput, error)
+}
+
+// LateRecalculationRecorder abstracts the late recalculation counter metric.
+type LateRecalculationRecorder interface {
+	PanicRecorder
+	RecordLateRecalculation(result string)
+}
+
+// LateEventConfig holds configuration for the late event worker.
+type LateEventConfig struct {
+	// Interval is how often communities with late events are recalculated.
+	// late events for the same community within an interval share one recalculation.
+	Interval time.Duration
+
+	// Timeout bounds a single recalculation.
+	Timeout time.Duration
+}
+
+// DefaultLateEventConfig returns sensible defaults.
+func DefaultLateEventConfig() LateEventConfig {
+	return LateEventConfig{
+		Interval: 10 * time.Second,
+		Timeout:  30 * time.Second,
+	}
+}
+
+// LateEventWorker recalculates communities whose momentum missed events with a past occurred_at.
+// momentum windows are measured on occurred_at, so an event stored after the calculation of the
+// window it occurred in would only count from the next cycle, minutes later, or not at all when it
+// leaves the window first. pending communities are kept in memory: on shutdown they're dropped,
+// the events are saved and the first cycle after a restart counts them.
+type LateEventWorker struct {
+	runner  LateRecalculationRunner
+	config  LateEventConfig
+	logger  *logging.Logger
+	metrics LateRecalculationRecorder
+
+	mu      sync.Mutex
+	pending map[domain.CommunityID]time.Time // earliest late occurred_at per community
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewLateEventWorker creates a new late event worker.
+func NewLateEventWorker(runner LateRecalculationRunner, config LateEventConfig, logger *logging.Logger) *LateEventWorker {
+	return &LateEventWorker{
+		runner:  runner,
+		config:  config,
+		logger:  logger.WithComponent("late_event_worker"),
+		pending: make(map[domain.CommunityID]time.Time),
+		stopped: make(chan struct{}),
+	}
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *LateEventWorker) WithMetrics(m LateRecalculationRecorder) *LateEventWorker {
+	w.metrics = m
+	return w
+}
+
+// TrackLate queues the communities of events that occurred before they were received.
+// whether their window was already calculated is checked on the next tick.
+func (w *LateEventWorker) TrackLate(events []*domain.ActivityEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, event := range events {
+		occurredAt := event.OccurredAt()
+		if !occurredAt.Before(event.CreatedAt()) {
+			continue
+		}
+		if earliest, ok := w.pending[event.CommunityID()]; !ok || occurredAt.Before(earliest) {
+			w.pending[event.CommunityID()] = occurredAt
+		}
+	}
+}
+
+// Start recalculates pending communities every interval.
+func (w *LateEventWorker) Start(ctx context.Context) {
+	w.logger.Info("late event worker starting",
+		"interval", w.config.Interval.String(),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "late_events", 0, w.logger, w.metrics, w.run)
+	}()
+}
+
+// Stop stops the worker, waiting for a recalculation in progress.
+func (w *LateEventWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		close(w.stopped)
+		w.logger.Info("late event worker stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *LateEventWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *LateEventWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.recalculate(ctx)
+		}
+	}
+}
+
+// recalculate takes the pending communities and recalculates the ones whose window needs it.
+func (w *LateEventWorker) recalculate(ctx context.Context) {
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = make(map[domain.CommunityID]time.Time)
+	w.mu.Unlock()
+
+	var recalculated int
+	for communityID, occurredAt := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+		output, err := w.runner.RecalculateLate(runCtx, application.RecalculateLateInput{
+			CommunityID: communityID.String(),
+			OccurredAt:  occurredAt,
+		})
+		cancel()
+
+		switch {
+		case err != nil:
+			// the next cycle counts the events anyway
+			w.record("failure")
+			w.logger.Warn("late event recalculation failed",
+				"community_id", communityID.String(),
+				"error", err.Error(),
+			)
+		case output != nil:
+			w.record("recalculated")
+			recalculated++
+		default:
+			w.record("skipped")
+		}
+	}
+
+	if recalculated > 0 {
+		w.logger.Info("recalculated momentum for late events",
+			"communities", recalculated,
+			"pending", len(pending),
+		)
+	}
+}
+
+func (w *LateEventWorker) record(result string) {
+	if w.metrics != nil {
+		w.metrics.RecordLateRecalculation(result)
+	}
+}
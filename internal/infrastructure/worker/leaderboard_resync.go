@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// LeaderboardState reports whether the leaderboard cache survived since its last rebuild.
+// implemented by the redis client.
+type LeaderboardState interface {
+	LeaderboardBuilt(ctx context.Context) (bool, error)
+}
+
+// LeaderboardRebuildRunner repopulates the leaderboard cache from postgres.
+// implemented by application.RebuildLeaderboardUseCase.
+type LeaderboardRebuildRunner interface {
+	Execute(ctx context.Context, input application.RebuildLeaderboardInput) (*application.RebuildLeaderboardOutput, error)
+}
+
+// ResyncRecorder abstracts the resync counter metric.
+type ResyncRecorder interface {
+	PanicRecorder
+	RecordLeaderboardResync(result string)
+}
+
+// LeaderboardResyncConfig holds configuration for the leaderboard resync worker.
+type LeaderboardResyncConfig struct {
+	// Interval is how often redis is checked for a lost leaderboard.
+	Interval time.Duration
+
+	// Timeout bounds a single rebuild.
+	Timeout time.Duration
+}
+
+// DefaultLeaderboardResyncConfig returns sensible defaults.
+func DefaultLeaderboardResyncConfig() LeaderboardResyncConfig {
+	return LeaderboardResyncConfig{
+		Interval: 15 * time.Second,
+		Timeout:  5 * time.Minute,
+	}
+}
+
+// LeaderboardResyncWorker rebuilds the leaderboard when redis comes back without it.
+// a restarted or flushed redis only holds the scores written since, so reads would return
+// a partial leaderboard, or fall back to postgres, until every community's next momentum
+// update. the worker notices within an interval, or right away when Kick is called.
+type LeaderboardResyncWorker struct {
+	state   LeaderboardState
+	rebuild LeaderboardRebuildRunner
+	config  LeaderboardResyncConfig
+	logger  *logging.Logger
+	metrics ResyncRecorder
+	standby StandbyState
+
+	kick     chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewLeaderboardResyncWorker creates a new leaderboard resync worker.
+func NewLeaderboardResyncWorker(
+	state LeaderboardState,
+	rebuild LeaderboardRebuildRunner,
+	config LeaderboardResyncConfig,
+	logger *logging.Logger,
+) *LeaderboardResyncWorker {
+	return &LeaderboardResyncWorker{
+		state:   state,
+		rebuild: rebuild,
+		config:  config,
+		logger:  logger.WithComponent("leaderboard_resync_worker"),
+		kick:    make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *LeaderboardResyncWorker) WithMetrics(m ResyncRecorder) *LeaderboardResyncWorker {
+	w.metrics = m
+	return w
+}
+
+// WithStandby skips checks while the instance is a standby, the active instance rebuilds.
+func (w *LeaderboardResyncWorker) WithStandby(s StandbyState) *LeaderboardResyncWorker {
+	w.standby = s
+	return w
+}
+
+// Kick asks for a check now, e.g. after a read found the leaderboard empty.
+// never blocks; kicks during a check are folded into one.
+func (w *LeaderboardResyncWorker) Kick() {
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+// Start checks right away, then every interval.
+func (w *LeaderboardResyncWorker) Start(ctx context.Context) {
+	w.logger.Info("leaderboard resync worker starting",
+		"interval", w.config.Interval.String(),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "leaderboard_resync", 0, w.logger, w.metrics, w.run)
+	}()
+}
+
+// Stop stops the worker, waiting for a rebuild in progress.
+func (w *LeaderboardResyncWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		close(w.stopped)
+		w.logger.Info("leaderboard resync worker stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *LeaderboardResyncWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *LeaderboardResyncWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx)
+		case <-w.kick:
+			w.check(ctx)
+		}
+	}
+}
+
+// check rebuilds the leaderboard if redis lost it. while redis is unreachable
+// there's nothing to rebuild into, so the next check after it's back does it.
+func (w *LeaderboardResyncWorker) check(ctx context.Context) {
+	if inStandby(w.standby) {
+		return
+	}
+	built, err := w.state.LeaderboardBuilt(ctx)
+	if err != nil {
+		w.logger.Debug("leaderboard state unavailable", "reason", err.Error())
+		return
+	}
+	if built {
+		return
+	}
+
+	w.logger.Warn("leaderboard missing from redis, rebuilding from postgres")
+
+	ctx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	output, err := w.rebuild.Execute(ctx, application.RebuildLeaderboardInput{})
+	if errors.Is(err, application.ErrLeaderboardRebuildInProgress) {
+		// someone else is already rebuilding, e.g. an admin
+		return
+	}
+	if err != nil {
+		// logged by the use case; the next check tries again
+		w.record("failure")
+		return
+	}
+
+	w.record("success")
+	w.logger.Info("leaderboard resynced",
+		"communities", output.Communities,
+		"duration_ms", output.Duration.Milliseconds(),
+	)
+}
+
+func (w *LeaderboardResyncWorker) record(result string) {
+	if w.metrics != nil {
+		w.metrics.RecordLeaderboardResync(result)
+	}
+}
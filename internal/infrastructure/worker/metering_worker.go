@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// UsageMeter records billable usage.
+// implemented by application.Meter, kept as an interface so workers only see Add.
+type UsageMeter interface {
+	Add(subject domain.MeterSubject, subjectID string, metric domain.MeterMetric, n int64)
+}
+
+// MeteringFlusher closes a metering period. implemented by application.MeteringUseCase.
+type MeteringFlusher interface {
+	Flush(ctx context.Context) (*application.FlushOutput, error)
+}
+
+// MeteringWorkerConfig holds configuration for the metering worker.
+type MeteringWorkerConfig struct {
+	// Interval is the length of a metering period.
+	Interval time.Duration
+
+	// FlushTimeout bounds a single flush, including exports.
+	FlushTimeout time.Duration
+}
+
+// DefaultMeteringWorkerConfig returns sensible defaults.
+func DefaultMeteringWorkerConfig() MeteringWorkerConfig {
+	return MeteringWorkerConfig{
+		Interval:     time.Hour,
+		FlushTimeout: time.Minute,
+	}
+}
+
+// MeteringWorker periodically turns accumulated usage into metering records.
+// the last period is flushed on Stop, so a clean shutdown loses no usage.
+type MeteringWorker struct {
+	flusher   MeteringFlusher
+	config    MeteringWorkerConfig
+	logger    *logging.Logger
+	metrics   PanicRecorder
+	heartbeat Heartbeat
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewMeteringWorker creates a new metering worker.
+func NewMeteringWorker(flusher MeteringFlusher, config MeteringWorkerConfig, logger *logging.Logger) *MeteringWorker {
+	return &MeteringWorker{
+		flusher: flusher,
+		config:  config,
+		logger:  logger.WithComponent("metering_worker"),
+		stopped: make(chan struct{}),
+	}
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *MeteringWorker) WithMetrics(m PanicRecorder) *MeteringWorker {
+	w.metrics = m
+	return w
+}
+
+// WithHeartbeat beats "metering" after every flush.
+func (w *MeteringWorker) WithHeartbeat(h Heartbeat) *MeteringWorker {
+	w.heartbeat = h
+	return w
+}
+
+// Start begins flushing every interval.
+func (w *MeteringWorker) Start(ctx context.Context) {
+	w.logger.Info("metering worker starting",
+		"interval", w.config.Interval.String(),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "metering", 0, w.logger, w.metrics, w.run)
+	}()
+}
+
+// Stop stops the ticker and flushes the last period.
+func (w *MeteringWorker) Stop() {
+	w.stopOnce.Do(func() {
+		w.logger.Info("metering worker stopping, flushing last period...")
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+
+		// the run context is gone, so flush with a fresh one
+		w.flush(context.Background())
+
+		close(w.stopped)
+		w.logger.Info("metering worker stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *MeteringWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *MeteringWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.flush(ctx)
+			if w.heartbeat != nil {
+				w.heartbeat.Beat("metering")
+			}
+		}
+	}
+}
+
+func (w *MeteringWorker) flush(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.config.FlushTimeout)
+	defer cancel()
+
+	// errors are logged by the use case; unsaved usage is kept for the next flush
+	_, _ = w.flusher.Flush(ctx)
+}
@@ -0,0 +1,377 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// MomentumCycleRunner recalculates every community. implemented by application.CalculateMomentumUseCase.
+type MomentumCycleRunner interface {
+	ExecuteAll(ctx context.Context, input application.CalculateAllInput) (*application.CalculateAllOutput, error)
+}
+
+// MomentumCycleRecorder abstracts the momentum cycle metrics.
+type MomentumCycleRecorder interface {
+	PanicRecorder
+	RecordMomentumCalculation(durationSeconds float64, traceID string)
+	RecordMomentumCycle(succeeded, failed, spikes int)
+	RecordMomentumRetries(retries int)
+	SetMomentumStaleCommunities(count int)
+	RecordMomentumCycleFailure()
+	SetMomentumCycleLag(seconds float64)
+}
+
+// CommunityMomentumRecorder abstracts the per-community momentum gauge.
+type CommunityMomentumRecorder interface {
+	SetCommunityMomentum(momentum map[string]float64)
+}
+
+// MomentumHeartbeat is a Heartbeat whose deadline follows the momentum interval.
+type MomentumHeartbeat interface {
+	Heartbeat
+	ExpectHeartbeat(name string, maxAge time.Duration)
+}
+
+// MomentumWorkerConfig holds configuration for the momentum worker.
+type MomentumWorkerConfig struct {
+	// Interval is how often every community is recalculated.
+	Interval time.Duration
+
+	// StartPaused starts the worker paused, until Resume.
+	StartPaused bool
+}
+
+// DefaultMomentumWorkerConfig returns sensible defaults.
+func DefaultMomentumWorkerConfig() MomentumWorkerConfig {
+	return MomentumWorkerConfig{
+		Interval: 5 * time.Minute,
+	}
+}
+
+// MomentumWorkerStatus is what the momentum worker is doing.
+type MomentumWorkerStatus struct {
+	Paused   bool
+	PausedAt *time.Time // nil while running
+
+	// Running is set while a cycle is in progress, which pausing doesn't interrupt.
+	Running bool
+
+	Interval       time.Duration
+	LastStartedAt  *time.Time // nil before the first cycle
+	LastFinishedAt *time.Time
+}
+
+// MomentumWorker recalculates momentum right away, then every interval. it can be paused
+// to shed the recalculation load, e.g. during a migration, and asked to run a cycle now.
+type MomentumWorker struct {
+	runner    MomentumCycleRunner
+	config    MomentumWorkerConfig
+	logger    *logging.Logger
+	metrics   MomentumCycleRecorder
+	heartbeat MomentumHeartbeat
+	intervals <-chan time.Duration
+	standby   StandbyState
+
+	// gauge of the top communities' momentum, see WithCommunityMomentum
+	communities    domain.CommunityRepository
+	momentumGauge  CommunityMomentumRecorder
+	gaugedTopCount int
+
+	mu             sync.Mutex
+	interval       time.Duration
+	pausedAt       *time.Time
+	running        bool
+	lastStartedAt  *time.Time
+	lastFinishedAt *time.Time
+
+	trigger  chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewMomentumWorker creates a new momentum worker.
+func NewMomentumWorker(runner MomentumCycleRunner, config MomentumWorkerConfig, logger *logging.Logger) *MomentumWorker {
+	w := &MomentumWorker{
+		runner:   runner,
+		config:   config,
+		logger:   logger.WithComponent("momentum_worker"),
+		interval: config.Interval,
+		trigger:  make(chan struct{}, 1),
+		stopped:  make(chan struct{}),
+	}
+	if config.StartPaused {
+		now := time.Now().UTC()
+		w.pausedAt = &now
+	}
+	return w
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *MomentumWorker) WithMetrics(m MomentumCycleRecorder) *MomentumWorker {
+	w.metrics = m
+	return w
+}
+
+// WithCommunityMomentum sets the momentum of the top n public communities on gauge,
+// by slug, after every cycle that completes.
+func (w *MomentumWorker) WithCommunityMomentum(communities domain.CommunityRepository, gauge CommunityMomentumRecorder, n int) *MomentumWorker {
+	w.communities = communities
+	w.momentumGauge = gauge
+	w.gaugedTopCount = n
+	return w
+}
+
+// WithHeartbeat beats "momentum" after every cycle, and every skipped cycle while paused or standing by.
+// three missed cycles count as down.
+func (w *MomentumWorker) WithHeartbeat(h MomentumHeartbeat) *MomentumWorker {
+	w.heartbeat = h
+	return w
+}
+
+// WithIntervals changes the interval to every value received, without restarting the worker.
+func (w *MomentumWorker) WithIntervals(intervals <-chan time.Duration) *MomentumWorker {
+	w.intervals = intervals
+	return w
+}
+
+// WithStandby skips every cycle, scheduled or triggered, while the instance is a standby.
+func (w *MomentumWorker) WithStandby(s StandbyState) *MomentumWorker {
+	w.standby = s
+	return w
+}
+
+// Pause skips the scheduled cycles until Resume. a cycle in progress finishes, and
+// Trigger still runs one. reports whether the worker was running.
+func (w *MomentumWorker) Pause() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pausedAt != nil {
+		return false
+	}
+	now := time.Now().UTC()
+	w.pausedAt = &now
+	w.logger.Warn("momentum worker paused")
+	return true
+}
+
+// Resume runs the scheduled cycles again, starting at the next tick.
+// reports whether the worker was paused.
+func (w *MomentumWorker) Resume() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pausedAt == nil {
+		return false
+	}
+	w.logger.Info("momentum worker resumed",
+		"paused_for", time.Since(*w.pausedAt).Round(time.Second).String(),
+	)
+	w.pausedAt = nil
+	return true
+}
+
+// Trigger asks for a cycle now, even while paused. never blocks; triggers during a
+// cycle are folded into one, run after it.
+func (w *MomentumWorker) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Status returns what the worker is doing.
+func (w *MomentumWorker) Status() MomentumWorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return MomentumWorkerStatus{
+		Paused:         w.pausedAt != nil,
+		PausedAt:       w.pausedAt,
+		Running:        w.running,
+		Interval:       w.interval,
+		LastStartedAt:  w.lastStartedAt,
+		LastFinishedAt: w.lastFinishedAt,
+	}
+}
+
+// Start runs a cycle right away unless paused, then every interval.
+func (w *MomentumWorker) Start(ctx context.Context) {
+	w.logger.Info("momentum worker starting",
+		"interval", w.config.Interval.String(),
+		"paused", w.config.StartPaused,
+	)
+	w.expectHeartbeat(w.config.Interval)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "momentum", 0, w.logger, w.metrics, w.run)
+	}()
+}
+
+// Stop stops the worker, waiting for a cycle in progress.
+func (w *MomentumWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		close(w.stopped)
+		w.logger.Info("momentum worker stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *MomentumWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *MomentumWorker) run(ctx context.Context) {
+	w.mu.Lock()
+	interval := w.interval
+	w.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.scheduled(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-w.intervals:
+			w.mu.Lock()
+			w.interval = next
+			w.mu.Unlock()
+			ticker.Reset(next)
+			w.expectHeartbeat(next)
+			w.logger.Info("momentum worker interval updated", "interval", next.String())
+		case <-ticker.C:
+			w.scheduled(ctx)
+		case <-w.trigger:
+			if inStandby(w.standby) {
+				w.logger.Info("triggered momentum cycle skipped: instance is a standby")
+				continue
+			}
+			w.logger.Info("momentum cycle triggered")
+			w.cycle(ctx, domain.MomentumRunAdmin)
+		}
+	}
+}
+
+// scheduled runs a cycle unless the worker is paused.
+func (w *MomentumWorker) scheduled(ctx context.Context) {
+	w.mu.Lock()
+	paused := w.pausedAt != nil
+	w.mu.Unlock()
+
+	if paused || inStandby(w.standby) {
+		w.logger.Debug("momentum cycle skipped", "paused", paused)
+		w.beat()
+		return
+	}
+	w.cycle(ctx, domain.MomentumRunSchedule)
+}
+
+func (w *MomentumWorker) cycle(ctx context.Context, triggeredBy domain.MomentumRunTrigger) {
+	start := time.Now()
+
+	w.mu.Lock()
+	// lag between cycle starts shows when cycles overrun the interval
+	if w.lastStartedAt != nil && w.metrics != nil {
+		w.metrics.SetMomentumCycleLag(start.Sub(*w.lastStartedAt).Seconds())
+	}
+	startedAt := start.UTC()
+	w.lastStartedAt = &startedAt
+	w.running = true
+	w.mu.Unlock()
+
+	defer func() {
+		finishedAt := time.Now().UTC()
+		w.mu.Lock()
+		w.running = false
+		w.lastFinishedAt = &finishedAt
+		w.mu.Unlock()
+		w.beat()
+	}()
+
+	result, err := w.runner.ExecuteAll(ctx, application.CalculateAllInput{
+		Limit:       0, // process all communities
+		TriggeredBy: triggeredBy,
+	})
+	duration := time.Since(start)
+
+	// record metric regardless of success/failure
+	if w.metrics != nil {
+		w.metrics.RecordMomentumCalculation(duration.Seconds(), "")
+	}
+
+	if err != nil {
+		if w.metrics != nil {
+			w.metrics.RecordMomentumCycleFailure()
+		}
+		w.logger.Error("momentum calculation failed",
+			"error", err.Error(),
+			"duration_ms", duration.Milliseconds(),
+		)
+		return
+	}
+
+	if w.metrics != nil {
+		w.metrics.RecordMomentumCycle(result.Succeeded, result.Failed, result.Spikes)
+		w.metrics.RecordMomentumRetries(result.Retried)
+		w.metrics.SetMomentumStaleCommunities(result.Stale)
+	}
+	w.gaugeCommunityMomentum(ctx)
+
+	w.logger.Info("momentum calculation completed",
+		"processed", result.Processed,
+		"succeeded", result.Succeeded,
+		"failed", result.Failed,
+		"retried", result.Retried,
+		"stale", result.Stale,
+		"spikes", result.Spikes,
+		"duration_ms", duration.Milliseconds(),
+	)
+}
+
+// gaugeCommunityMomentum refreshes the top communities' momentum gauge. a failed listing
+// keeps the previous cycle's values.
+func (w *MomentumWorker) gaugeCommunityMomentum(ctx context.Context) {
+	if w.momentumGauge == nil {
+		return
+	}
+
+	communities, err := w.communities.ListPublicByMomentum(ctx, w.gaugedTopCount, 0)
+	if err != nil {
+		w.logger.Warn("community momentum gauge not refreshed", "error", err.Error())
+		return
+	}
+	momentum := make(map[string]float64, len(communities))
+	for _, c := range communities {
+		momentum[c.Slug().String()] = c.CurrentMomentum().Value()
+	}
+	w.momentumGauge.SetCommunityMomentum(momentum)
+}
+
+func (w *MomentumWorker) beat() {
+	if w.heartbeat != nil {
+		w.heartbeat.Beat("momentum")
+	}
+}
+
+// expectHeartbeat allows three missed cycles, since a cycle may overrun its interval a little.
+func (w *MomentumWorker) expectHeartbeat(interval time.Duration) {
+	if w.heartbeat != nil {
+		w.heartbeat.ExpectHeartbeat("momentum", 3*interval)
+	}
+}
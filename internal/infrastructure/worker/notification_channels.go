@@ -0,0 +1,232 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// Notification is one payload on its way to a community's subscribers.
+type Notification struct {
+	// Event is the notification name, like momentum_spike.
+	Event string
+
+	// Payload is one of WebhookPayload, AnomalyWebhookPayload or WebhookDigestPayload.
+	Payload any
+
+	// bodies holds the payload rendered per version, so it's rendered once per dispatch.
+	bodies map[domain.WebhookPayloadVersion][]byte
+}
+
+// Body renders the payload in a payload version.
+func (n *Notification) Body(version domain.WebhookPayloadVersion) ([]byte, error) {
+	if body, ok := n.bodies[version]; ok {
+		return body, nil
+	}
+	body, err := serializePayload(version, n.Event, n.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if n.bodies == nil {
+		n.bodies = make(map[domain.WebhookPayloadVersion][]byte, 1)
+	}
+	n.bodies[version] = body
+	return body, nil
+}
+
+// NotificationChannel delivers notifications to the subscriptions of one channel.
+// the worker picks the channel from the subscription, after preferences and digests,
+// so a new channel is one implementation registered with WithChannel.
+type NotificationChannel interface {
+	// Deliver sends the notification, reporting how the subscriber answered.
+	Deliver(ctx context.Context, sub *domain.WebhookSubscription, n *Notification, workerID int) DeliveryResult
+}
+
+// DeliveryResult is how one delivery attempt went.
+type DeliveryResult struct {
+	// ID is the delivery id the subscriber saw, uuid.Nil when the channel doesn't send one.
+	ID uuid.UUID
+
+	// StatusCode is the subscriber's response status, 0 when no response came back.
+	StatusCode int
+
+	// Err says why the delivery failed, nil when the subscriber accepted it.
+	Err error
+}
+
+// Delivered reports whether the subscriber accepted the notification.
+func (r DeliveryResult) Delivered() bool {
+	return r.Err == nil
+}
+
+// deliveryFailed is a result without a response, like when the request couldn't be built.
+func deliveryFailed(err error) DeliveryResult {
+	return DeliveryResult{Err: err}
+}
+
+// deliveryResponse is the result for a response: accepted on 2xx, failed otherwise.
+func deliveryResponse(id uuid.UUID, status int) DeliveryResult {
+	result := DeliveryResult{ID: id, StatusCode: status}
+	if status < 200 || status >= 300 {
+		result.Err = fmt.Errorf("subscriber returned status %d", status)
+	}
+	return result
+}
+
+// WithChannel registers a channel, replacing the built-in one of the same name.
+func (w *WebhookWorker) WithChannel(name domain.NotificationChannel, ch NotificationChannel) *WebhookWorker {
+	w.channels[name] = ch
+	return w
+}
+
+// deliver hands the notification to the subscription's channel and logs the attempt,
+// reporting whether the subscriber accepted it.
+func (w *WebhookWorker) deliver(ctx context.Context, sub *domain.WebhookSubscription, n *Notification, workerID int) bool {
+	ch, ok := w.channels[sub.Channel()]
+	if !ok {
+		w.logger.Error("no notification channel for subscription",
+			"worker_id", workerID,
+			"subscription_id", sub.ID().String(),
+			"channel", sub.Channel().String(),
+		)
+		return false
+	}
+
+	start := time.Now()
+	result := ch.Deliver(ctx, sub, n, workerID)
+	w.logDelivery(ctx, sub, n.Event, result, time.Since(start))
+	return result.Delivered()
+}
+
+// logDelivery saves a delivery attempt, see WithDeliveryLog. a failed save is only logged,
+// it doesn't change whether the notification went out.
+func (w *WebhookWorker) logDelivery(ctx context.Context, sub *domain.WebhookSubscription, event string, result DeliveryResult, latency time.Duration) {
+	if w.deliveries == nil {
+		return
+	}
+	delivery := domain.NewWebhookDelivery(domain.SystemClock, result.ID, sub.ID(), event, result.StatusCode, latency, result.Err)
+	if err := w.deliveries.Save(context.WithoutCancel(ctx), delivery); err != nil {
+		w.logger.Warn("webhook delivery log failed",
+			"subscription_id", sub.ID().String(),
+			"delivery_id", delivery.ID().String(),
+			"error", err.Error(),
+		)
+	}
+}
+
+// webhookChannel posts signed JSON in the subscription's payload version.
+type webhookChannel struct {
+	w *WebhookWorker
+}
+
+func (c webhookChannel) Deliver(ctx context.Context, sub *domain.WebhookSubscription, n *Notification, workerID int) DeliveryResult {
+	version := sub.PayloadVersion()
+	body, err := n.Body(version)
+	if err != nil {
+		c.w.logger.Error("failed to serialize payload",
+			"worker_id", workerID,
+			"subscription_id", sub.ID().String(),
+			"payload_version", version.String(),
+			"error", err.Error(),
+		)
+		return deliveryFailed(fmt.Errorf("serializing payload: %w", err))
+	}
+	return c.w.sendWebhook(ctx, sub, n.Event, body, workerID)
+}
+
+// chatChannel posts a readable message to a slack or discord incoming webhook.
+type chatChannel struct {
+	w *WebhookWorker
+
+	// field is the JSON field holding the message: text for slack, content for discord.
+	field string
+}
+
+func (c chatChannel) Deliver(ctx context.Context, sub *domain.WebhookSubscription, n *Notification, workerID int) DeliveryResult {
+	body, err := json.Marshal(map[string]string{c.field: notificationText(n)})
+	if err != nil {
+		return deliveryFailed(fmt.Errorf("serializing message: %w", err))
+	}
+
+	ctx = c.w.withSubscriptionProxy(ctx, sub)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.TargetURL(), bytes.NewReader(body))
+	if err != nil {
+		c.w.logger.Error("failed to create request",
+			"worker_id", workerID,
+			"subscription_id", sub.ID().String(),
+			"error", err.Error(),
+		)
+		return deliveryFailed(fmt.Errorf("creating request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Pulse-Webhook/1.0")
+
+	// the incoming webhook url is a credential, so it's never logged
+	resp, err := c.w.httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		c.w.logger.Warn("chat notification failed",
+			"worker_id", workerID,
+			"subscription_id", sub.ID().String(),
+			"channel", sub.Channel().String(),
+			"error", err.Error(),
+		)
+		return deliveryFailed(err)
+	}
+	defer resp.Body.Close()
+
+	result := deliveryResponse(uuid.Nil, resp.StatusCode)
+	if !result.Delivered() {
+		c.w.logger.Warn("chat notification returned non-success status",
+			"worker_id", workerID,
+			"subscription_id", sub.ID().String(),
+			"channel", sub.Channel().String(),
+			"status", resp.StatusCode,
+		)
+	}
+	return result
+}
+
+// notificationText is the chat message for a notification.
+func notificationText(n *Notification) string {
+	switch p := n.Payload.(type) {
+	case WebhookPayload:
+		return spikeText(p)
+	case WebhookDigestPayload:
+		text := fmt.Sprintf("%d momentum spikes between %s and %s:", p.Count, p.WindowStart, p.WindowEnd)
+		for _, spike := range p.Spikes {
+			text += "\n• " + spikeText(spike)
+		}
+		return text
+	case AnomalyWebhookPayload:
+		subject := "Community " + p.CommunityID
+		if p.UserID != "" {
+			subject = "A user in community " + p.CommunityID
+		}
+		return fmt.Sprintf("%s sent %d events against %.0f expected (%.1fx) and was flagged for review.",
+			subject, p.ObservedEvents, p.ExpectedEvents, p.Ratio)
+	default:
+		return n.Event
+	}
+}
+
+func spikeText(p WebhookPayload) string {
+	text := fmt.Sprintf("%s momentum spiked from %.1f to %.1f (%+.0f%%)",
+		p.CommunityName, p.OldMomentum, p.NewMomentum, p.PercentChange*100)
+	if p.NewRank != nil {
+		text += fmt.Sprintf(", now #%d", *p.NewRank)
+	}
+	return text
+}
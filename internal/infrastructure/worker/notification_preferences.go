@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// PreferencesLookup finds a subscriber's notification preferences.
+// implemented by the notification preferences repository and its cache.
+type PreferencesLookup interface {
+	FindByUser(ctx context.Context, userID domain.UserID) (*domain.NotificationPreferences, error)
+}
+
+// WithPreferences honors each subscriber's muted communities, quiet hours and hourly cap.
+func (w *WebhookWorker) WithPreferences(p PreferencesLookup) *WebhookWorker {
+	w.prefs = p
+	w.limiter = newDeliveryLimiter()
+	return w
+}
+
+// preferencesFor returns a subscriber's preferences, nil for none.
+// seen memoizes lookups for one dispatch, so a user's subscriptions share one lookup.
+// a failed lookup is treated as no preferences: a missed mute beats a missed spike.
+func (w *WebhookWorker) preferencesFor(ctx context.Context, userID domain.UserID, seen map[domain.UserID]*domain.NotificationPreferences) *domain.NotificationPreferences {
+	if w.prefs == nil {
+		return nil
+	}
+	if prefs, ok := seen[userID]; ok {
+		return prefs
+	}
+
+	prefs, err := w.prefs.FindByUser(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		w.logger.Warn("notification preferences unavailable, delivering anyway",
+			"user_id", userID.String(),
+			"error", err.Error(),
+		)
+	}
+	if seen != nil {
+		seen[userID] = prefs
+	}
+	return prefs
+}
+
+// deliveryHeld reports why a delivery to the user can't go out now, or "" if it can.
+// an allowed delivery counts toward the user's hourly cap.
+func (w *WebhookWorker) deliveryHeld(userID domain.UserID, prefs *domain.NotificationPreferences, now time.Time) string {
+	if prefs == nil {
+		return ""
+	}
+	if prefs.InQuietHours(now) {
+		return "quiet_hours"
+	}
+	if !w.limiter.allow(userID, prefs.MaxPerHour(), now) {
+		return "hourly_cap"
+	}
+	return ""
+}
+
+// deliveryLimiter counts each user's deliveries over a rolling hour.
+// counts are per instance, so with several instances a user can get up to the cap from each.
+type deliveryLimiter struct {
+	sent map[domain.UserID][]time.Time
+	mu   sync.Mutex
+}
+
+func newDeliveryLimiter() *deliveryLimiter {
+	return &deliveryLimiter{sent: make(map[domain.UserID][]time.Time)}
+}
+
+// allow records a delivery unless the user already had max in the past hour. max 0 is unlimited.
+func (l *deliveryLimiter) allow(userID domain.UserID, max int, now time.Time) bool {
+	if max == 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := pruneBefore(l.sent[userID], now.Add(-time.Hour))
+	if len(recent) >= max {
+		l.sent[userID] = recent
+		return false
+	}
+	l.sent[userID] = append(recent, now)
+	return true
+}
+
+// cleanup drops users with no deliveries in the past hour.
+func (l *deliveryLimiter) cleanup(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for userID, sent := range l.sent {
+		if recent := pruneBefore(sent, now.Add(-time.Hour)); len(recent) == 0 {
+			delete(l.sent, userID)
+		} else {
+			l.sent[userID] = recent
+		}
+	}
+}
+
+// pruneBefore drops the times before cutoff from a sorted slice.
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// persistAcks holds the requests waiting for their event to be saved, see application.AckPersisted.
+// the zero value is ready to use.
+type persistAcks struct {
+	mu      sync.Mutex
+	waiting map[domain.EventID]chan error
+}
+
+// AwaitPersisted implements application.PersistenceNotifier.
+func (w *EventIngestionWorker) AwaitPersisted(id domain.EventID) (<-chan error, func()) {
+	// buffered, so a flush never blocks on a request that stopped waiting
+	result := make(chan error, 1)
+
+	w.acks.mu.Lock()
+	if w.acks.waiting == nil {
+		w.acks.waiting = make(map[domain.EventID]chan error)
+	}
+	w.acks.waiting[id] = result
+	w.acks.mu.Unlock()
+
+	stop := func() {
+		w.acks.mu.Lock()
+		delete(w.acks.waiting, id)
+		w.acks.mu.Unlock()
+	}
+	return result, stop
+}
+
+// ack tells the requests waiting on any of the events the outcome of their flush.
+func (w *EventIngestionWorker) ack(events []*domain.ActivityEvent, err error) {
+	w.acks.mu.Lock()
+	defer w.acks.mu.Unlock()
+	if len(w.acks.waiting) == 0 {
+		return
+	}
+	for _, event := range events {
+		if result, ok := w.acks.waiting[event.ID()]; ok {
+			result <- err
+			delete(w.acks.waiting, event.ID())
+		}
+	}
+}
+
+// ackRejected tells the requests waiting on quarantined events why they weren't saved.
+// stale events get ErrEventStale, since they're fine to send again.
+func (w *EventIngestionWorker) ackRejected(rejected []*domain.RejectedEvent) {
+	for _, r := range rejected {
+		if r.Reason() == domain.RejectStale {
+			w.ack([]*domain.ActivityEvent{r.Event()}, application.ErrEventStale)
+			continue
+		}
+		w.ack([]*domain.ActivityEvent{r.Event()}, fmt.Errorf("%w: %s", application.ErrEventQuarantined, r.Reason()))
+	}
+}
+
+// ackFiltered tells the requests waiting on events the validator held back that they were quarantined.
+func (w *EventIngestionWorker) ackFiltered(batch, valid []*domain.ActivityEvent) {
+	if len(valid) == len(batch) {
+		return
+	}
+	kept := make(map[domain.EventID]bool, len(valid))
+	for _, event := range valid {
+		kept[event.ID()] = true
+	}
+	var filtered []*domain.ActivityEvent
+	for _, event := range batch {
+		if !kept[event.ID()] {
+			filtered = append(filtered, event)
+		}
+	}
+	w.ack(filtered, application.ErrEventQuarantined)
+}
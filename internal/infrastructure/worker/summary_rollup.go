@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// SummaryRefresher rolls up community summaries. implemented by application.CommunitySummaryUseCase.
+type SummaryRefresher interface {
+	Refresh(ctx context.Context) (int64, error)
+}
+
+// SummaryRollupConfig holds configuration for the summary rollup worker.
+type SummaryRollupConfig struct {
+	// Interval is how often summaries are rolled up.
+	Interval time.Duration
+
+	// Timeout bounds a single rollup.
+	Timeout time.Duration
+}
+
+// DefaultSummaryRollupConfig returns sensible defaults.
+func DefaultSummaryRollupConfig() SummaryRollupConfig {
+	return SummaryRollupConfig{
+		Interval: time.Minute,
+		Timeout:  time.Minute,
+	}
+}
+
+// SummaryRollupWorker keeps the community summaries that listings read up to date.
+// it rolls up once on start, so listings don't wait an interval after a deploy.
+type SummaryRollupWorker struct {
+	refresher SummaryRefresher
+	config    SummaryRollupConfig
+	logger    *logging.Logger
+	metrics   PanicRecorder
+	heartbeat Heartbeat
+	standby   StandbyState
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// NewSummaryRollupWorker creates a new summary rollup worker.
+func NewSummaryRollupWorker(refresher SummaryRefresher, config SummaryRollupConfig, logger *logging.Logger) *SummaryRollupWorker {
+	return &SummaryRollupWorker{
+		refresher: refresher,
+		config:    config,
+		logger:    logger.WithComponent("summary_rollup"),
+		stopped:   make(chan struct{}),
+	}
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *SummaryRollupWorker) WithMetrics(m PanicRecorder) *SummaryRollupWorker {
+	w.metrics = m
+	return w
+}
+
+// WithHeartbeat beats "summary_rollup" after every rollup.
+func (w *SummaryRollupWorker) WithHeartbeat(h Heartbeat) *SummaryRollupWorker {
+	w.heartbeat = h
+	return w
+}
+
+// WithStandby skips rollups while the instance is a standby.
+func (w *SummaryRollupWorker) WithStandby(s StandbyState) *SummaryRollupWorker {
+	w.standby = s
+	return w
+}
+
+// Start rolls up right away, then every interval.
+func (w *SummaryRollupWorker) Start(ctx context.Context) {
+	w.logger.Info("summary rollup worker starting",
+		"interval", w.config.Interval.String(),
+	)
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		supervise(ctx, "summary_rollup", 0, w.logger, w.metrics, w.run)
+	}()
+}
+
+// Stop stops the ticker, waiting for a rollup in progress.
+func (w *SummaryRollupWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		close(w.stopped)
+		w.logger.Info("summary rollup worker stopped")
+	})
+}
+
+// Stopped returns a channel that closes when the worker has fully stopped.
+func (w *SummaryRollupWorker) Stopped() <-chan struct{} {
+	return w.stopped
+}
+
+func (w *SummaryRollupWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if !inStandby(w.standby) {
+			w.rollup(ctx)
+		}
+		if w.heartbeat != nil {
+			w.heartbeat.Beat("summary_rollup")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SummaryRollupWorker) rollup(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	// errors are logged by the use case; the next tick tries again
+	_, _ = w.refresher.Refresh(ctx)
+}
@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+const (
+	// initialRestartBackoff is the delay before restarting a goroutine after its first panic.
+	initialRestartBackoff = 100 * time.Millisecond
+
+	// maxRestartBackoff caps the exponential backoff between restarts.
+	// a goroutine that stays up longer than this resets its backoff.
+	maxRestartBackoff = 30 * time.Second
+)
+
+// PanicRecorder abstracts the panic counter metric.
+// keeps workers decoupled from the metrics package.
+type PanicRecorder interface {
+	RecordWorkerPanic(worker string)
+}
+
+// Heartbeat records that a worker loop is still turning.
+// implemented by health.Monitor, which reports workers that stop beating.
+type Heartbeat interface {
+	Beat(name string)
+}
+
+// StandbyState reports whether the instance is a warm standby, which runs no background work.
+// implemented by standby.Switch.
+type StandbyState interface {
+	Standby() bool
+}
+
+// inStandby reports whether a worker with the given state should skip its work.
+func inStandby(s StandbyState) bool {
+	return s != nil && s.Standby()
+}
+
+// supervise runs fn and restarts it with exponential backoff if it panics.
+// returns when fn returns normally or the context is cancelled.
+// a panic must never silently reduce worker throughput, so every panic
+// is logged with its stack trace and counted.
+func supervise(
+	ctx context.Context,
+	name string,
+	workerID int,
+	logger *logging.Logger,
+	recorder PanicRecorder,
+	fn func(ctx context.Context),
+) {
+	backoff := initialRestartBackoff
+
+	for {
+		start := time.Now()
+		panicked := runRecovered(ctx, name, workerID, logger, recorder, fn)
+		if !panicked {
+			return
+		}
+
+		// a goroutine that ran fine for a while starts over with a short backoff
+		if time.Since(start) > maxRestartBackoff {
+			backoff = initialRestartBackoff
+		}
+
+		logger.Warn("restarting worker goroutine after panic",
+			"worker", name,
+			"worker_id", workerID,
+			"backoff", backoff.String(),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxRestartBackoff {
+			backoff = maxRestartBackoff
+		}
+	}
+}
+
+// runRecovered executes fn and converts a panic into a logged, counted event.
+// returns true if fn panicked.
+func runRecovered(
+	ctx context.Context,
+	name string,
+	workerID int,
+	logger *logging.Logger,
+	recorder PanicRecorder,
+	fn func(ctx context.Context),
+) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+
+			logger.Error("worker goroutine panicked",
+				"worker", name,
+				"worker_id", workerID,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+
+			if recorder != nil {
+				recorder.RecordWorkerPanic(name)
+			}
+		}
+	}()
+
+	fn(ctx)
+	return false
+}
@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// digestWorkerID is the worker id logged for deliveries made by the digest loop.
+const digestWorkerID = -1
+
+// digestBatch holds the spikes waiting for one digest endpoint.
+type digestBatch struct {
+	sub    *domain.WebhookSubscription
+	spikes []WebhookPayload
+
+	// heldSince is set when the batch was held over from an earlier window by the
+	// subscriber's quiet hours or hourly cap, and is the digest's window_start.
+	heldSince time.Time
+}
+
+// digestKey groups digest subscriptions that deliver to the same endpoint the same way,
+// so a user watching many communities gets one call per window instead of one per subscription.
+func digestKey(sub *domain.WebhookSubscription) string {
+	return sub.UserID().String() + "|" + sub.Channel().String() + "|" + sub.TargetURL() + "|" + sub.Secret() + "|" + sub.PayloadVersion().String() + "|" + sub.ProxyURL()
+}
+
+// addToDigest holds a spike for the subscription's next digest.
+func (w *WebhookWorker) addToDigest(sub *domain.WebhookSubscription, spike WebhookPayload) {
+	w.digestMu.Lock()
+	defer w.digestMu.Unlock()
+
+	key := digestKey(sub)
+	batch, ok := w.digests[key]
+	if !ok {
+		batch = &digestBatch{sub: sub}
+		w.digests[key] = batch
+	}
+	batch.spikes = append(batch.spikes, spike)
+}
+
+// runDigests sends the held spikes once per digest window.
+// it runs until Stop rather than until ctx is canceled, so the last window
+// is still flushed on a graceful shutdown.
+func (w *WebhookWorker) runDigests(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+
+	ticker := time.NewTicker(w.config.DigestWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flushDigests(ctx)
+			if w.limiter != nil {
+				w.limiter.cleanup(w.clock.Now())
+			}
+			w.pruneDeliveries(ctx)
+
+		case <-w.digestStop:
+			w.flushDigests(ctx)
+			return
+		}
+	}
+}
+
+// flushDigests sends one momentum_spike_digest per endpoint with spikes in the window that just ended.
+func (w *WebhookWorker) flushDigests(ctx context.Context) {
+	w.digestMu.Lock()
+	batches := w.digests
+	w.digests = make(map[string]*digestBatch)
+	windowStart := w.digestSince
+	windowEnd := w.clock.Now()
+	w.digestSince = windowEnd
+	w.digestMu.Unlock()
+
+	if len(batches) == 0 {
+		return
+	}
+
+	prefs := make(map[domain.UserID]*domain.NotificationPreferences)
+
+	var sent, failed, held int
+	for key, batch := range batches {
+		// a paused endpoint keeps its batch until a flush after the pause window
+		if _, paused := w.paused(batch.sub, windowEnd); paused {
+			w.holdDigest(key, batch, windowStart)
+			held++
+			continue
+		}
+
+		userID := batch.sub.UserID()
+		if reason := w.deliveryHeld(userID, w.preferencesFor(ctx, userID, prefs), windowEnd); reason != "" {
+			w.holdDigest(key, batch, windowStart)
+			held++
+			continue
+		}
+
+		batchStart := windowStart
+		if !batch.heldSince.IsZero() {
+			batchStart = batch.heldSince
+		}
+
+		payload := WebhookDigestPayload{
+			Event:       "momentum_spike_digest",
+			WindowStart: batchStart.UTC().Format(time.RFC3339),
+			WindowEnd:   windowEnd.UTC().Format(time.RFC3339),
+			Count:       len(batch.spikes),
+			Spikes:      batch.spikes,
+		}
+
+		notification := &Notification{Event: payload.Event, Payload: payload}
+		if !w.deliver(ctx, batch.sub, notification, digestWorkerID) {
+			failed++
+			continue
+		}
+		sent++
+
+		// each community in the digest is billed for one delivery
+		if w.meter != nil {
+			for _, spike := range batch.spikes {
+				w.meter.Add(domain.MeterSubjectCommunity, spike.CommunityID, domain.MeterWebhooksDelivered, 1)
+			}
+		}
+	}
+
+	w.logger.Info("webhook digests dispatched",
+		"window_start", windowStart.UTC().Format(time.RFC3339),
+		"sent", sent,
+		"failed", failed,
+		"held", held,
+	)
+}
+
+// holdDigest carries a batch over to the next window, ahead of any spikes added since the flush began.
+func (w *WebhookWorker) holdDigest(key string, batch *digestBatch, windowStart time.Time) {
+	if batch.heldSince.IsZero() {
+		batch.heldSince = windowStart
+	}
+
+	w.digestMu.Lock()
+	defer w.digestMu.Unlock()
+
+	if newer, ok := w.digests[key]; ok {
+		batch.spikes = append(batch.spikes, newer.spikes...)
+	}
+	w.digests[key] = batch
+}
+
+// WebhookDigestPayload batches the momentum spikes of one digest window into a single delivery.
+type WebhookDigestPayload struct {
+	Event       string           `json:"event"`
+	WindowStart string           `json:"window_start"`
+	WindowEnd   string           `json:"window_end"`
+	Count       int              `json:"count"`
+	Spikes      []WebhookPayload `json:"spikes"`
+}
+
+// pruneDeliveries removes the delivery attempts older than domain.WebhookDeliveryRetention.
+func (w *WebhookWorker) pruneDeliveries(ctx context.Context) {
+	if w.deliveries == nil {
+		return
+	}
+	if _, err := w.deliveries.DeleteBefore(ctx, w.clock.Now().Add(-domain.WebhookDeliveryRetention)); err != nil {
+		w.logger.Warn("webhook delivery pruning failed", "error", err.Error())
+	}
+}
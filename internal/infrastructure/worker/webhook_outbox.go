@@ -0,0 +1,260 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+const (
+	// outboxWorkerID is the worker id logged for deliveries made by the outbox loop.
+	outboxWorkerID = -2
+
+	// outboxBatchSize is how many due entries are claimed at a time.
+	outboxBatchSize = 100
+
+	// outboxLease is how long a claimed entry is hidden from other instances. an instance
+	// that dies mid-flush leaves its entries to be delivered again once the lease runs out.
+	outboxLease = 5 * time.Minute
+)
+
+// WithOutbox honors the subscriptions' pause windows: a notification for a paused
+// subscription is saved to the outbox and delivered once the window ends, and digests are
+// held over until then. without it, pause windows are ignored.
+func (w *WebhookWorker) WithOutbox(outbox domain.WebhookOutboxRepository) *WebhookWorker {
+	w.outbox = outbox
+	return w
+}
+
+// paused reports until when a subscription's deliveries are held, see WithOutbox.
+func (w *WebhookWorker) paused(sub *domain.WebhookSubscription, now time.Time) (time.Time, bool) {
+	if w.outbox == nil {
+		return time.Time{}, false
+	}
+	return sub.PausedUntil(now)
+}
+
+// holdForPause saves the notification to the outbox when the subscription is paused,
+// reporting whether it was held. a failed save isn't held: a delivery during the
+// subscriber's maintenance beats a lost one.
+func (w *WebhookWorker) holdForPause(ctx context.Context, sub *domain.WebhookSubscription, communityID domain.CommunityID, n *Notification, now time.Time) bool {
+	until, ok := w.paused(sub, now)
+	if !ok {
+		return false
+	}
+
+	payload, err := json.Marshal(n.Payload)
+	if err == nil {
+		var entry *domain.WebhookOutboxEntry
+		entry, err = domain.NewWebhookOutboxEntry(w.clock, sub.ID(), communityID, n.Event, payload, until)
+		if err == nil {
+			err = w.outbox.Add(ctx, entry)
+		}
+	}
+	if err != nil {
+		w.logger.Error("webhook outbox save failed, delivering anyway",
+			"subscription_id", sub.ID().String(),
+			"event", n.Event,
+			"error", err.Error(),
+		)
+		return false
+	}
+
+	w.logger.Debug("webhook held for subscription pause window",
+		"subscription_id", sub.ID().String(),
+		"event", n.Event,
+		"deliver_after", until.UTC().Format(time.RFC3339),
+	)
+	return true
+}
+
+// runOutbox delivers the outbox entries whose pause window ended, once per OutboxInterval.
+func (w *WebhookWorker) runOutbox(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+
+	ticker := time.NewTicker(w.config.OutboxInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flushOutbox(ctx)
+
+		case <-w.outboxStop:
+			return
+		}
+	}
+}
+
+// flushOutbox claims the due entries in batches and delivers each one.
+func (w *WebhookWorker) flushOutbox(ctx context.Context) {
+	now := w.clock.Now()
+	prefs := make(map[domain.UserID]*domain.NotificationPreferences)
+
+	var sent, failed, held, dropped int
+	for {
+		entries, err := w.outbox.ClaimDue(ctx, now, outboxLease, outboxBatchSize)
+		if err != nil {
+			w.logger.Error("webhook outbox claim failed", "error", err.Error())
+			break
+		}
+
+		for _, entry := range entries {
+			switch w.flushEntry(ctx, entry, now, prefs) {
+			case outboxSent:
+				sent++
+			case outboxFailed:
+				failed++
+			case outboxHeld:
+				held++
+			case outboxDropped:
+				dropped++
+			}
+		}
+
+		if len(entries) < outboxBatchSize {
+			break
+		}
+	}
+
+	if sent+failed+held+dropped == 0 {
+		return
+	}
+	w.logger.Info("webhook outbox flushed",
+		"sent", sent,
+		"failed", failed,
+		"held", held,
+		"dropped", dropped,
+	)
+}
+
+// outboxOutcome is what became of one outbox entry.
+type outboxOutcome int
+
+const (
+	outboxSent outboxOutcome = iota
+	outboxFailed
+	outboxHeld    // rescheduled, or left for its lease to run out
+	outboxDropped // removed without a delivery attempt
+)
+
+// flushEntry delivers one due entry. the subscription is looked up again, since it may
+// have been disabled, paused again or moved off its community's allowlist while it waited.
+func (w *WebhookWorker) flushEntry(ctx context.Context, entry *domain.WebhookOutboxEntry, now time.Time, prefs map[domain.UserID]*domain.NotificationPreferences) outboxOutcome {
+	sub, err := w.subRepo.FindByID(ctx, entry.SubscriptionID())
+	if errors.Is(err, domain.ErrNotFound) {
+		return w.dropEntry(ctx, entry, "subscription deleted")
+	}
+	if err != nil {
+		w.logger.Warn("webhook outbox subscription lookup failed, retrying after the lease",
+			"outbox_id", entry.ID().String(),
+			"subscription_id", entry.SubscriptionID().String(),
+			"error", err.Error(),
+		)
+		return outboxHeld
+	}
+	if !sub.IsActive() {
+		return w.dropEntry(ctx, entry, "subscription inactive")
+	}
+
+	if until, ok := w.paused(sub, now); ok {
+		return w.rescheduleEntry(ctx, entry, until)
+	}
+
+	allowlist, err := w.allowlistFor(ctx, entry.CommunityID())
+	if err != nil {
+		w.logger.Warn("webhook outbox allowlist lookup failed, retrying after the lease",
+			"outbox_id", entry.ID().String(),
+			"subscription_id", entry.SubscriptionID().String(),
+			"error", err.Error(),
+		)
+		return outboxHeld
+	}
+	if !allowlist.AllowsURL(sub.TargetURL()) {
+		return w.dropEntry(ctx, entry, "blocked by community allowlist")
+	}
+
+	userPrefs := w.preferencesFor(ctx, sub.UserID(), prefs)
+	if userPrefs != nil && userPrefs.IsMuted(entry.CommunityID()) {
+		return w.dropEntry(ctx, entry, "community muted")
+	}
+	if reason := w.deliveryHeld(sub.UserID(), userPrefs, now); reason != "" {
+		return w.rescheduleEntry(ctx, entry, now.Add(w.config.OutboxInterval))
+	}
+
+	payload, err := decodeNotificationPayload(entry.Event(), entry.Payload())
+	if err != nil {
+		w.logger.Error("webhook outbox payload unreadable",
+			"outbox_id", entry.ID().String(),
+			"subscription_id", entry.SubscriptionID().String(),
+			"error", err.Error(),
+		)
+		return w.dropEntry(ctx, entry, "unreadable payload")
+	}
+
+	delivered := w.deliver(ctx, sub, &Notification{Event: entry.Event(), Payload: payload}, outboxWorkerID)
+
+	// like any delivery, a failed one isn't retried
+	if err := w.outbox.Delete(ctx, entry.ID()); err != nil {
+		w.logger.Warn("webhook outbox delete failed, the entry may be delivered again",
+			"outbox_id", entry.ID().String(),
+			"subscription_id", entry.SubscriptionID().String(),
+			"error", err.Error(),
+		)
+	}
+	if !delivered {
+		return outboxFailed
+	}
+
+	if w.meter != nil {
+		w.meter.Add(domain.MeterSubjectCommunity, entry.CommunityID().String(), domain.MeterWebhooksDelivered, 1)
+	}
+	return outboxSent
+}
+
+// dropEntry removes an entry that won't be delivered.
+func (w *WebhookWorker) dropEntry(ctx context.Context, entry *domain.WebhookOutboxEntry, reason string) outboxOutcome {
+	w.logger.Debug("webhook outbox entry dropped",
+		"outbox_id", entry.ID().String(),
+		"subscription_id", entry.SubscriptionID().String(),
+		"reason", reason,
+	)
+	if err := w.outbox.Delete(ctx, entry.ID()); err != nil {
+		w.logger.Warn("webhook outbox delete failed",
+			"outbox_id", entry.ID().String(),
+			"error", err.Error(),
+		)
+	}
+	return outboxDropped
+}
+
+// rescheduleEntry holds an entry until later.
+func (w *WebhookWorker) rescheduleEntry(ctx context.Context, entry *domain.WebhookOutboxEntry, until time.Time) outboxOutcome {
+	if err := w.outbox.Reschedule(ctx, entry.ID(), until); err != nil {
+		w.logger.Warn("webhook outbox reschedule failed, retrying after the lease",
+			"outbox_id", entry.ID().String(),
+			"error", err.Error(),
+		)
+	}
+	return outboxHeld
+}
+
+// decodeNotificationPayload restores a payload saved by holdForPause from its event name.
+func decodeNotificationPayload(event string, raw []byte) (any, error) {
+	switch event {
+	case "momentum_spike":
+		var p WebhookPayload
+		err := json.Unmarshal(raw, &p)
+		return p, err
+	case "anomaly_flagged":
+		var p AnomalyWebhookPayload
+		err := json.Unmarshal(raw, &p)
+		return p, err
+	default:
+		return nil, fmt.Errorf("unknown outbox event %q", event)
+	}
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// PayloadSerializer renders a notification payload in one payload version.
+// event is the X-Pulse-Event name, for versions that wrap the payload in an envelope.
+type PayloadSerializer func(event string, payload any) ([]byte, error)
+
+// payloadSerializers holds every payload version the worker can send.
+// a new version gets an entry here and in domain.ParseWebhookPayloadVersion,
+// and the old entries stay so existing receivers keep getting what they parse.
+var payloadSerializers = map[domain.WebhookPayloadVersion]PayloadSerializer{
+	domain.WebhookPayloadV1: serializeV1,
+}
+
+// serializeV1 is the original flat payload: the payload struct as JSON.
+func serializeV1(_ string, payload any) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// serializePayload renders the payload for a subscription's version.
+func serializePayload(version domain.WebhookPayloadVersion, event string, payload any) ([]byte, error) {
+	serialize, ok := payloadSerializers[version]
+	if !ok {
+		return nil, fmt.Errorf("no serializer for payload version %q", version)
+	}
+	return serialize(event, payload)
+}
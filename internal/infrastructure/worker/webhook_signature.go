@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/domain"
+)
+
+// sampleCommunityID stands in for the community of global subscriptions in sample payloads.
+const sampleCommunityID = "00000000-0000-0000-0000-000000000000"
+
+// StringToSign is what a delivery's signature covers: "<timestamp>.<payload>".
+func StringToSign(timestamp string, payload []byte) string {
+	return timestamp + "." + string(payload)
+}
+
+// SignPayload returns the X-Pulse-Signature header for a payload sent at timestamp,
+// "sha256=" followed by the hex HMAC-SHA256 of StringToSign keyed with the secret.
+func SignPayload(timestamp string, payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(StringToSign(timestamp, payload)))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SamplePayload renders a momentum_spike payload for the subscription's payload version,
+// exactly as a delivery would serialize it. global subscriptions get a placeholder community.
+func SamplePayload(sub *domain.WebhookSubscription, now time.Time) ([]byte, error) {
+	communityID := sampleCommunityID
+	if !sub.IsGlobal() {
+		communityID = sub.CommunityID().String()
+	}
+
+	return serializePayload(sub.PayloadVersion(), "momentum_spike", WebhookPayload{
+		Event:         "momentum_spike",
+		CommunityID:   communityID,
+		CommunityName: "Example Community",
+		OldMomentum:   10,
+		NewMomentum:   15,
+		PercentChange: 0.5,
+		Timestamp:     now.UTC().Format(time.RFC3339),
+	})
+}
@@ -3,15 +3,17 @@ package worker
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/joacominatel/pulse/internal/domain"
 	"github.com/joacominatel/pulse/internal/infrastructure/logging"
 )
@@ -29,6 +31,36 @@ type WebhookWorkerConfig struct {
 
 	// Thresholds define when momentum changes are considered spikes.
 	Thresholds domain.MomentumSpikeThresholds
+
+	// DigestWindow is how long spikes for digest subscriptions are held before they're sent together.
+	DigestWindow time.Duration
+
+	// OutboxInterval is how often notifications held for paused subscriptions are checked,
+	// so they go out at most this long after the pause window ends.
+	OutboxInterval time.Duration
+
+	// MaxIdleConns caps idle keep-alive connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost is how many idle connections are kept per host, so a burst
+	// of spikes to one receiver reuses connections instead of dialing each time.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost caps connections per host, so one slow receiver can't take them all. 0 is unlimited.
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept before it's closed.
+	IdleConnTimeout time.Duration
+
+	// TLSSessionCacheSize is how many TLS sessions are kept for resumption, skipping full handshakes on reconnect.
+	TLSSessionCacheSize int
+
+	// Proxy routes every delivery through this proxy. nil falls back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	Proxy *url.URL
+
+	// AllowSubscriptionProxy honors each subscription's own proxy url. off by default, since
+	// it lets subscribers choose where pulse connects from inside the network.
+	AllowSubscriptionProxy bool
 }
 
 // DefaultWebhookWorkerConfig returns sensible defaults.
@@ -38,17 +70,80 @@ func DefaultWebhookWorkerConfig() WebhookWorkerConfig {
 		WorkerCount:    2,
 		RequestTimeout: 5 * time.Second,
 		Thresholds:     domain.DefaultSpikeThresholds(),
+		DigestWindow:   15 * time.Minute,
+		OutboxInterval: time.Minute,
+
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10, // the default of 2 redials under bursts to one receiver
+		MaxConnsPerHost:     20,
+		IdleConnTimeout:     90 * time.Second,
+		TLSSessionCacheSize: 256,
 	}
 }
 
-// WebhookWorker dispatches webhook notifications for momentum spikes.
+// subscriptionProxyKey carries a subscription's proxy url on the delivery request context.
+type subscriptionProxyKey struct{}
+
+// newWebhookTransport builds the transport shared by all webhook deliveries.
+// based on http.DefaultTransport, so proxies from the environment apply unless one is configured.
+func newWebhookTransport(config WebhookWorkerConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	fallback := http.ProxyFromEnvironment
+	if config.Proxy != nil {
+		fallback = http.ProxyURL(config.Proxy)
+	}
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		if config.AllowSubscriptionProxy {
+			if proxy, ok := req.Context().Value(subscriptionProxyKey{}).(*url.URL); ok {
+				return proxy, nil
+			}
+		}
+		return fallback(req)
+	}
+	transport.MaxIdleConns = config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = config.MaxConnsPerHost
+	transport.IdleConnTimeout = config.IdleConnTimeout
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ClientSessionCache: tls.NewLRUClientSessionCache(config.TLSSessionCacheSize),
+	}
+	return transport
+}
+
+// WebhookWorker dispatches webhook notifications for momentum spikes and anomaly flags.
 // implements domain.NotificationService.
 type WebhookWorker struct {
-	spikeChan  chan *domain.MomentumSpike
+	jobs       chan webhookJob
 	subRepo    domain.WebhookSubscriptionRepository
 	httpClient *http.Client
 	config     WebhookWorkerConfig
+	clock      domain.Clock
 	logger     *logging.Logger
+	metrics    PanicRecorder
+	meter      UsageMeter
+	ranks      RankLookup
+	prefs      PreferencesLookup
+	allowlists AllowlistLookup
+	channels   map[domain.NotificationChannel]NotificationChannel
+	limiter    *deliveryLimiter
+	deliveries domain.WebhookDeliveryRepository
+	outbox     domain.WebhookOutboxRepository
+
+	// thresholds can be swapped at runtime on config reload
+	thresholdsMu sync.RWMutex
+	thresholds   domain.MomentumSpikeThresholds
+
+	// spikes held for digest subscriptions, sent once per DigestWindow
+	digestMu    sync.Mutex
+	digests     map[string]*digestBatch
+	digestSince time.Time
+	digestStop  chan struct{}
+	digestDone  chan struct{}
+
+	// notifications held for paused subscriptions, see WithOutbox
+	outboxStop chan struct{}
+	outboxDone chan struct{}
 
 	wg       sync.WaitGroup
 	stopOnce sync.Once
@@ -61,16 +156,80 @@ func NewWebhookWorker(
 	config WebhookWorkerConfig,
 	logger *logging.Logger,
 ) *WebhookWorker {
-	return &WebhookWorker{
-		spikeChan: make(chan *domain.MomentumSpike, config.BufferSize),
-		subRepo:   subRepo,
+	w := &WebhookWorker{
+		jobs:    make(chan webhookJob, config.BufferSize),
+		subRepo: subRepo,
 		httpClient: &http.Client{
-			Timeout: config.RequestTimeout,
+			Timeout:   config.RequestTimeout,
+			Transport: newWebhookTransport(config),
 		},
-		config:  config,
-		logger:  logger.WithComponent("webhook_worker"),
-		stopped: make(chan struct{}),
+		config:     config,
+		thresholds: config.Thresholds,
+		clock:      domain.SystemClock,
+		logger:     logger.WithComponent("webhook_worker"),
+		digests:    make(map[string]*digestBatch),
+		digestStop: make(chan struct{}),
+		digestDone: make(chan struct{}),
+		outboxStop: make(chan struct{}),
+		outboxDone: make(chan struct{}),
+		stopped:    make(chan struct{}),
 	}
+	w.channels = map[domain.NotificationChannel]NotificationChannel{
+		domain.ChannelWebhook: webhookChannel{w: w},
+		domain.ChannelSlack:   chatChannel{w: w, field: "text"},
+		domain.ChannelDiscord: chatChannel{w: w, field: "content"},
+	}
+	return w
+}
+
+// WithClock replaces the system clock, which decides when pause windows and digest
+// windows end and when held notifications are due.
+func (w *WebhookWorker) WithClock(clock domain.Clock) *WebhookWorker {
+	w.clock = clock
+	return w
+}
+
+// WithMetrics sets the metrics recorder for observability.
+func (w *WebhookWorker) WithMetrics(m PanicRecorder) *WebhookWorker {
+	w.metrics = m
+	return w
+}
+
+// WithMeter meters delivered webhooks per community for billing.
+func (w *WebhookWorker) WithMeter(m UsageMeter) *WebhookWorker {
+	w.meter = m
+	return w
+}
+
+// WithDeliveryLog records every delivery attempt, and prunes the records older than
+// domain.WebhookDeliveryRetention once per digest window.
+func (w *WebhookWorker) WithDeliveryLog(deliveries domain.WebhookDeliveryRepository) *WebhookWorker {
+	w.deliveries = deliveries
+	return w
+}
+
+// RankLookup places a community on the leaderboard. implemented by the redis client.
+type RankLookup interface {
+	LeaderboardPosition(ctx context.Context, communityID string, previousMomentum float64) (domain.LeaderboardPosition, error)
+}
+
+// WithRanks adds previous_rank, new_rank and leaderboard_size to momentum_spike payloads.
+func (w *WebhookWorker) WithRanks(r RankLookup) *WebhookWorker {
+	w.ranks = r
+	return w
+}
+
+// AllowlistLookup finds the domains a community's webhooks may be delivered to.
+// implemented by the community webhook allowlist repository.
+type AllowlistLookup interface {
+	FindByCommunity(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityWebhookAllowlist, error)
+}
+
+// WithAllowlists skips subscriptions whose target isn't among their community's allowed
+// domains, including global subscriptions, which get spikes from every public community.
+func (w *WebhookWorker) WithAllowlists(a AllowlistLookup) *WebhookWorker {
+	w.allowlists = a
+	return w
 }
 
 // Start begins the worker goroutines.
@@ -79,20 +238,52 @@ func (w *WebhookWorker) Start(ctx context.Context) {
 		"buffer_size", w.config.BufferSize,
 		"worker_count", w.config.WorkerCount,
 		"request_timeout", w.config.RequestTimeout.String(),
+		"digest_window", w.config.DigestWindow.String(),
+		"outbox", w.outbox != nil,
+		"max_idle_conns_per_host", w.config.MaxIdleConnsPerHost,
+		"max_conns_per_host", w.config.MaxConnsPerHost,
 	)
 
 	for i := 0; i < w.config.WorkerCount; i++ {
 		w.wg.Add(1)
-		go w.runWorker(ctx, i)
+		go func(workerID int) {
+			defer w.wg.Done()
+			supervise(ctx, "webhook", workerID, w.logger, w.metrics, func(ctx context.Context) {
+				w.runWorker(ctx, workerID)
+			})
+		}(i)
+	}
+
+	w.digestSince = w.clock.Now()
+	go func() {
+		defer close(w.digestDone)
+		supervise(ctx, "webhook_digest", 0, w.logger, w.metrics, w.runDigests)
+	}()
+
+	if w.outbox == nil {
+		close(w.outboxDone)
+		return
 	}
+	go func() {
+		defer close(w.outboxDone)
+		supervise(ctx, "webhook_outbox", 0, w.logger, w.metrics, w.runOutbox)
+	}()
 }
 
 // Stop gracefully shuts down the worker.
 func (w *WebhookWorker) Stop() {
 	w.stopOnce.Do(func() {
 		w.logger.Info("webhook worker stopping, draining buffer...")
-		close(w.spikeChan)
+		close(w.jobs)
 		w.wg.Wait()
+
+		// the workers are done adding spikes, send what the current digests hold
+		close(w.digestStop)
+		<-w.digestDone
+
+		close(w.outboxStop)
+		<-w.outboxDone
+
 		close(w.stopped)
 		w.logger.Info("webhook worker stopped")
 	})
@@ -103,62 +294,134 @@ func (w *WebhookWorker) Stopped() <-chan struct{} {
 	return w.stopped
 }
 
+// webhookJob is one notification waiting to be sent to a community's subscribers.
+type webhookJob struct {
+	communityID domain.CommunityID
+	event       string
+	payload     any
+
+	// communityOnly keeps the job away from global subscriptions, which only get spikes.
+	communityOnly bool
+}
+
 // NotifyMomentumSpike queues a momentum spike for notification.
 // implements domain.NotificationService.
 func (w *WebhookWorker) NotifyMomentumSpike(ctx context.Context, spike *domain.MomentumSpike) (int, error) {
+	// actual count will be determined during dispatch
+	// return 0 here as it's async
+	return 0, w.enqueue(ctx, webhookJob{
+		communityID: spike.CommunityID,
+		event:       "momentum_spike",
+		payload: WebhookPayload{
+			Event:         "momentum_spike",
+			CommunityID:   spike.CommunityID.String(),
+			CommunityName: spike.CommunityName,
+			OldMomentum:   spike.OldMomentum,
+			NewMomentum:   spike.NewMomentum,
+			PercentChange: spike.PercentChange,
+			Timestamp:     spike.Timestamp.Format(time.RFC3339),
+		},
+	})
+}
+
+// HandleSpikeDetected queues the momentum_spike webhooks of a published domain.SpikeDetected.
+// meant to be subscribed to the event bus on one instance per event, other events are ignored.
+func (w *WebhookWorker) HandleSpikeDetected(ctx context.Context, event domain.DomainEvent) {
+	e, ok := event.(domain.SpikeDetected)
+	if !ok {
+		return
+	}
+	if _, err := w.NotifyMomentumSpike(ctx, &e.Spike); err != nil {
+		w.logger.WithContext(ctx).Warn("spike notification failed",
+			"community_id", e.Spike.CommunityID.String(),
+			"error", err.Error(),
+		)
+	}
+}
+
+// NotifyAnomaly queues an anomaly_flagged notification for the flagged community.
+// implements application.AnomalyNotifier.
+func (w *WebhookWorker) NotifyAnomaly(ctx context.Context, flag *domain.AnomalyFlag) error {
+	payload := AnomalyWebhookPayload{
+		Event:          "anomaly_flagged",
+		AnomalyID:      flag.ID().String(),
+		CommunityID:    flag.CommunityID().String(),
+		Subject:        "community",
+		ObservedEvents: flag.Observed(),
+		ExpectedEvents: flag.Expected(),
+		Ratio:          flag.Ratio(),
+		WindowStart:    flag.WindowStart().Format(time.RFC3339),
+		Timestamp:      flag.DetectedAt().Format(time.RFC3339),
+	}
+	if u := flag.UserID(); u != nil {
+		payload.Subject = "user"
+		payload.UserID = u.String()
+	}
+
+	return w.enqueue(ctx, webhookJob{
+		communityID:   flag.CommunityID(),
+		event:         payload.Event,
+		payload:       payload,
+		communityOnly: true,
+	})
+}
+
+// enqueue hands a job to the workers without blocking; it's dropped if the buffer is full.
+func (w *WebhookWorker) enqueue(ctx context.Context, job webhookJob) error {
 	select {
-	case w.spikeChan <- spike:
-		w.logger.Debug("spike queued for notification",
-			"community_id", spike.CommunityID.String(),
-			"new_momentum", spike.NewMomentum,
+	case w.jobs <- job:
+		w.logger.Debug("webhook queued for notification",
+			"community_id", job.communityID.String(),
+			"event", job.event,
 		)
-		// actual count will be determined during dispatch
-		// return 0 here as it's async
-		return 0, nil
+		return nil
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return ctx.Err()
 	default:
 		// buffer full, log and drop
-		w.logger.Warn("webhook buffer full, spike dropped",
-			"community_id", spike.CommunityID.String(),
+		w.logger.Warn("webhook buffer full, notification dropped",
+			"community_id", job.communityID.String(),
+			"event", job.event,
 		)
-		return 0, nil
+		return nil
 	}
 }
 
 // Thresholds returns the configured spike thresholds.
 func (w *WebhookWorker) Thresholds() domain.MomentumSpikeThresholds {
-	return w.config.Thresholds
+	w.thresholdsMu.RLock()
+	defer w.thresholdsMu.RUnlock()
+	return w.thresholds
+}
+
+// SetThresholds replaces the spike thresholds.
+// safe to call while the worker is running; applies to the next momentum cycle.
+func (w *WebhookWorker) SetThresholds(t domain.MomentumSpikeThresholds) {
+	w.thresholdsMu.Lock()
+	defer w.thresholdsMu.Unlock()
+	w.thresholds = t
 }
 
 // runWorker is the main worker loop.
+// it runs until Stop rather than until ctx is canceled, so notifications queued
+// before a graceful shutdown are still sent.
 func (w *WebhookWorker) runWorker(ctx context.Context, workerID int) {
-	defer w.wg.Done()
-
-	for {
-		select {
-		case spike, ok := <-w.spikeChan:
-			if !ok {
-				w.logger.Debug("worker exiting after drain", "worker_id", workerID)
-				return
-			}
-			w.dispatchSpike(ctx, spike, workerID)
+	ctx = context.WithoutCancel(ctx)
 
-		case <-ctx.Done():
-			w.logger.Debug("worker exiting on context cancel", "worker_id", workerID)
-			return
-		}
+	for job := range w.jobs {
+		w.dispatch(ctx, job, workerID)
 	}
+	w.logger.Debug("worker exiting after drain", "worker_id", workerID)
 }
 
-// dispatchSpike sends webhook notifications for a spike.
-func (w *WebhookWorker) dispatchSpike(ctx context.Context, spike *domain.MomentumSpike, workerID int) {
+// dispatch sends a notification to every subscriber of the job's community.
+func (w *WebhookWorker) dispatch(ctx context.Context, job webhookJob, workerID int) {
 	// get subscriptions for this community
-	subs, err := w.subRepo.FindByCommunity(ctx, spike.CommunityID)
+	subs, err := w.subRepo.FindByCommunity(ctx, job.communityID)
 	if err != nil {
 		w.logger.Error("failed to fetch subscriptions",
 			"worker_id", workerID,
-			"community_id", spike.CommunityID.String(),
+			"community_id", job.communityID.String(),
 			"error", err.Error(),
 		)
 		return
@@ -166,54 +429,155 @@ func (w *WebhookWorker) dispatchSpike(ctx context.Context, spike *domain.Momentu
 
 	if len(subs) == 0 {
 		w.logger.Debug("no subscriptions for community",
-			"community_id", spike.CommunityID.String(),
+			"community_id", job.communityID.String(),
 		)
 		return
 	}
 
-	// prepare payload
-	payload := WebhookPayload{
-		Event:         "momentum_spike",
-		CommunityID:   spike.CommunityID.String(),
-		CommunityName: spike.CommunityName,
-		OldMomentum:   spike.OldMomentum,
-		NewMomentum:   spike.NewMomentum,
-		PercentChange: spike.PercentChange,
-		Timestamp:     spike.Timestamp.Format(time.RFC3339),
-	}
-
-	payloadBytes, err := json.Marshal(payload)
+	// the allowlist is checked at dispatch too, it may have changed since a subscription was made
+	allowlist, err := w.allowlistFor(ctx, job.communityID)
 	if err != nil {
-		w.logger.Error("failed to marshal payload",
+		w.logger.Error("failed to fetch webhook allowlist, notification dropped",
 			"worker_id", workerID,
+			"community_id", job.communityID.String(),
+			"event", job.event,
 			"error", err.Error(),
 		)
 		return
 	}
 
+	// ranks are looked up at dispatch, after the momentum cycle updated the leaderboard
+	payload := job.payload
+	if spike, ok := payload.(WebhookPayload); ok && w.ranks != nil {
+		payload = w.withRanks(ctx, spike)
+	}
+
+	// each payload version is rendered once, the first time a subscriber needs it
+	notification := &Notification{Event: job.event, Payload: payload}
+	prefs := make(map[domain.UserID]*domain.NotificationPreferences)
+
 	// dispatch to each subscriber
-	var sent, failed int
+	var sent, failed, held, suppressed, blocked, paused int
+	now := w.clock.Now()
 	for _, sub := range subs {
-		if w.sendWebhook(ctx, sub, payloadBytes, workerID) {
+		if job.communityOnly && sub.IsGlobal() {
+			continue
+		}
+
+		if !allowlist.AllowsURL(sub.TargetURL()) {
+			w.logger.Debug("webhook blocked by community allowlist",
+				"subscription_id", sub.ID().String(),
+				"event", job.event,
+			)
+			blocked++
+			continue
+		}
+
+		userPrefs := w.preferencesFor(ctx, sub.UserID(), prefs)
+		if userPrefs != nil && userPrefs.IsMuted(job.communityID) {
+			suppressed++
+			continue
+		}
+
+		// digest subscriptions get spikes in the next digest instead
+		if spike, ok := payload.(WebhookPayload); ok && sub.DeliveryMode() == domain.WebhookDeliveryDigest {
+			w.addToDigest(sub, spike)
+			held++
+			continue
+		}
+
+		// paused subscriptions get it once their pause window ends
+		if w.holdForPause(ctx, sub, job.communityID, notification, now) {
+			paused++
+			continue
+		}
+
+		if reason := w.deliveryHeld(sub.UserID(), userPrefs, now); reason != "" {
+			w.logger.Debug("webhook suppressed by notification preferences",
+				"subscription_id", sub.ID().String(),
+				"event", job.event,
+				"reason", reason,
+			)
+			suppressed++
+			continue
+		}
+
+		if w.deliver(ctx, sub, notification, workerID) {
 			sent++
 		} else {
 			failed++
 		}
 	}
 
-	w.logger.Info("spike notifications dispatched",
+	if w.meter != nil {
+		w.meter.Add(domain.MeterSubjectCommunity, job.communityID.String(), domain.MeterWebhooksDelivered, int64(sent))
+	}
+
+	w.logger.Info("webhook notifications dispatched",
 		"worker_id", workerID,
-		"community_id", spike.CommunityID.String(),
+		"community_id", job.communityID.String(),
+		"event", job.event,
 		"sent", sent,
 		"failed", failed,
+		"held_for_digest", held,
+		"suppressed", suppressed,
+		"blocked", blocked,
+		"paused", paused,
 	)
 }
 
-// sendWebhook sends a single webhook notification.
-func (w *WebhookWorker) sendWebhook(ctx context.Context, sub *domain.WebhookSubscription, payload []byte, workerID int) bool {
-	// compute HMAC signature
-	signature := w.computeSignature(payload, sub.Secret())
+// allowlistFor returns the community's webhook allowlist, nil when it allows every domain.
+// a failed lookup is an error rather than no allowlist: a missed spike beats a leaked one.
+func (w *WebhookWorker) allowlistFor(ctx context.Context, communityID domain.CommunityID) (*domain.CommunityWebhookAllowlist, error) {
+	if w.allowlists == nil {
+		return nil, nil
+	}
+	allowlist, err := w.allowlists.FindByCommunity(ctx, communityID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return allowlist, err
+}
+
+// withRanks fills in the spike's leaderboard position. on failure, or for communities
+// that aren't ranked, the payload goes out without it.
+func (w *WebhookWorker) withRanks(ctx context.Context, payload WebhookPayload) WebhookPayload {
+	pos, err := w.ranks.LeaderboardPosition(ctx, payload.CommunityID, payload.OldMomentum)
+	if err != nil {
+		w.logger.Debug("leaderboard position unavailable",
+			"community_id", payload.CommunityID,
+			"reason", err.Error(),
+		)
+		return payload
+	}
+
+	payload.PreviousRank = &pos.PreviousRank
+	payload.NewRank = &pos.NewRank
+	payload.LeaderboardSize = &pos.Size
+	return payload
+}
+
+// withSubscriptionProxy routes the delivery through the subscription's own proxy, when allowed.
+func (w *WebhookWorker) withSubscriptionProxy(ctx context.Context, sub *domain.WebhookSubscription) context.Context {
+	if w.config.AllowSubscriptionProxy && sub.ProxyURL() != "" {
+		// validated when the subscription was saved
+		if proxy, err := domain.ParseWebhookProxy(sub.ProxyURL()); err == nil {
+			return context.WithValue(ctx, subscriptionProxyKey{}, proxy)
+		}
+	}
+	return ctx
+}
 
+// sendWebhook sends a single webhook notification.
+func (w *WebhookWorker) sendWebhook(ctx context.Context, sub *domain.WebhookSubscription, event string, payload []byte, workerID int) DeliveryResult {
+	// the timestamp is signed with the payload, so a captured delivery can't be replayed
+	// once it's older than the receiver's tolerance
+	timestamp := strconv.FormatInt(w.clock.Now().Unix(), 10)
+	signature := SignPayload(timestamp, payload, sub.Secret())
+	id := uuid.New()
+	deliveryID := id.String()
+
+	ctx = w.withSubscriptionProxy(ctx, sub)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.TargetURL(), bytes.NewReader(payload))
 	if err != nil {
 		w.logger.Error("failed to create request",
@@ -221,12 +585,15 @@ func (w *WebhookWorker) sendWebhook(ctx context.Context, sub *domain.WebhookSubs
 			"target_url", sub.TargetURL(),
 			"error", err.Error(),
 		)
-		return false
+		return deliveryFailed(fmt.Errorf("creating request: %w", err))
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Pulse-Signature", signature)
-	req.Header.Set("X-Pulse-Event", "momentum_spike")
+	req.Header.Set("X-Pulse-Timestamp", timestamp)
+	req.Header.Set("X-Pulse-Delivery-ID", deliveryID)
+	req.Header.Set("X-Pulse-Event", event)
+	req.Header.Set("X-Pulse-Payload-Version", sub.PayloadVersion().String())
 	req.Header.Set("User-Agent", "Pulse-Webhook/1.0")
 
 	resp, err := w.httpClient.Do(req)
@@ -234,33 +601,30 @@ func (w *WebhookWorker) sendWebhook(ctx context.Context, sub *domain.WebhookSubs
 		w.logger.Warn("webhook request failed",
 			"worker_id", workerID,
 			"target_url", sub.TargetURL(),
+			"delivery_id", deliveryID,
 			"error", err.Error(),
 		)
-		return false
+		return DeliveryResult{ID: id, Err: err}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+	result := deliveryResponse(id, resp.StatusCode)
+	if result.Delivered() {
 		w.logger.Debug("webhook delivered",
 			"target_url", sub.TargetURL(),
+			"delivery_id", deliveryID,
 			"status", resp.StatusCode,
 		)
-		return true
+		return result
 	}
 
 	w.logger.Warn("webhook returned non-success status",
 		"worker_id", workerID,
 		"target_url", sub.TargetURL(),
+		"delivery_id", deliveryID,
 		"status", resp.StatusCode,
 	)
-	return false
-}
-
-// computeSignature generates HMAC-SHA256 signature.
-func (w *WebhookWorker) computeSignature(payload []byte, secret string) string {
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(payload)
-	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+	return result
 }
 
 // WebhookPayload is the JSON structure sent to webhook endpoints.
@@ -272,4 +636,24 @@ type WebhookPayload struct {
 	NewMomentum   float64 `json:"new_momentum"`
 	PercentChange float64 `json:"percent_change"`
 	Timestamp     string  `json:"timestamp"`
+
+	// leaderboard position, left out for communities that aren't ranked
+	PreviousRank    *int64 `json:"previous_rank,omitempty"`
+	NewRank         *int64 `json:"new_rank,omitempty"`
+	LeaderboardSize *int64 `json:"leaderboard_size,omitempty"`
+}
+
+// AnomalyWebhookPayload is sent when a community, or a user in it, is flagged for an anomalous ingest rate.
+// the flagged events don't count toward momentum until an admin reviews the flag.
+type AnomalyWebhookPayload struct {
+	Event          string  `json:"event"`
+	AnomalyID      string  `json:"anomaly_id"`
+	CommunityID    string  `json:"community_id"`
+	Subject        string  `json:"subject"` // community or user
+	UserID         string  `json:"user_id,omitempty"`
+	ObservedEvents int64   `json:"observed_events"`
+	ExpectedEvents float64 `json:"expected_events"`
+	Ratio          float64 `json:"ratio"`
+	WindowStart    string  `json:"window_start"`
+	Timestamp      string  `json:"timestamp"`
 }
@@ -0,0 +1,125 @@
+//go:build integration
+
+// Package integration runs pulse end to end against real postgres and redis.
+// run with go test -tags integration ./internal/integration/..., docker is required.
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/joacominatel/pulse/internal/application"
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/cache"
+	"github.com/joacominatel/pulse/internal/infrastructure/eventbus"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+	"github.com/joacominatel/pulse/internal/infrastructure/worker"
+	"github.com/joacominatel/pulse/internal/testsupport"
+)
+
+// TestPipeline_IngestToWebhook follows events from ingestion through a momentum cycle to
+// the leaderboard and a signed spike webhook.
+func TestPipeline_IngestToWebhook(t *testing.T) {
+	env := testsupport.Start(t, testsupport.WithRedis())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	user := env.CreateUser(t, "alice")
+	community := env.CreateCommunity(t, user, "golang", "Go")
+	quiet := env.CreateCommunity(t, user, "quiet", "Quiet")
+	receiver := testsupport.NewReceiver(t)
+	const secret = "whsec_test"
+	env.CreateWebhookSubscription(t, user, community, receiver.URL, secret)
+
+	userRepo := postgres.NewUserRepository(env.Pool)
+	eventRepo := postgres.NewActivityEventRepository(env.Pool)
+	communityRepo := cache.NewCommunityRepositoryWithCache(postgres.NewCommunityRepository(env.Pool), env.Redis, env.Logger)
+
+	webhookWorker := worker.NewWebhookWorker(postgres.NewWebhookSubscriptionRepository(env.Pool), worker.DefaultWebhookWorkerConfig(), env.Logger).
+		WithRanks(env.Redis)
+	webhookWorker.Start(ctx)
+	defer webhookWorker.Stop()
+
+	bus := eventbus.New(env.Logger)
+	bus.Subscribe(domain.EventSpikeDetected, webhookWorker.HandleSpikeDetected)
+
+	// ingest: five posts, 25 raw weight, well above the default spike threshold
+	ingest := application.NewIngestEventUseCase(eventRepo, communityRepo, userRepo, env.Logger)
+	userID := user.ID().String()
+	for i := 0; i < 5; i++ {
+		_, err := ingest.Execute(ctx, application.IngestEventInput{
+			CommunityID: community.ID().String(),
+			UserID:      &userID,
+			EventType:   domain.EventTypePost.String(),
+			Metadata:    map[string]any{"source": "integration"},
+		})
+		if err != nil {
+			t.Fatalf("ingest: %v", err)
+		}
+	}
+	if _, err := ingest.Execute(ctx, application.IngestEventInput{
+		CommunityID: quiet.ID().String(),
+		EventType:   domain.EventTypeView.String(),
+	}); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	// momentum cycle
+	momentum := application.NewCalculateMomentumUseCase(
+		eventRepo,
+		communityRepo,
+		application.DefaultMomentumConfig(),
+		env.Logger,
+		application.WithMomentumEvents(bus),
+		application.WithSpikeThresholds(webhookWorker),
+		application.WithLeaderboard(env.Redis),
+		application.WithLeaderboardSnapshots(env.Redis),
+	)
+	cycle, err := momentum.ExecuteAll(ctx, application.CalculateAllInput{})
+	if err != nil {
+		t.Fatalf("momentum cycle: %v", err)
+	}
+	if cycle.Failed != 0 || cycle.Spikes != 1 {
+		t.Fatalf("cycle = %+v, want no failures and one spike", cycle)
+	}
+
+	// leaderboard
+	entries, err := application.NewLeaderboardUseCase(communityRepo, env.Redis, env.Logger).Execute(ctx, "", 10, 0)
+	if err != nil {
+		t.Fatalf("leaderboard: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("leaderboard has %d entries, want 2", len(entries))
+	}
+	if entries[0].Community.ID() != community.ID() || entries[1].Community.ID() != quiet.ID() {
+		t.Errorf("leaderboard order = %s, %s; want golang, quiet", entries[0].Community.Slug(), entries[1].Community.Slug())
+	}
+	wantMomentum := 25 * application.DefaultMomentumConfig().DecayFactor
+	if math.Abs(entries[0].Momentum-wantMomentum) > 1e-6 {
+		t.Errorf("momentum = %v, want %v", entries[0].Momentum, wantMomentum)
+	}
+
+	// webhook dispatch
+	delivery := receiver.Wait(t, 10*time.Second)
+	if got := delivery.Header.Get("X-Pulse-Event"); got != "momentum_spike" {
+		t.Errorf("X-Pulse-Event = %q, want momentum_spike", got)
+	}
+	timestamp := delivery.Header.Get("X-Pulse-Timestamp")
+	if got, want := delivery.Header.Get("X-Pulse-Signature"), worker.SignPayload(timestamp, delivery.Body, secret); got != want {
+		t.Errorf("X-Pulse-Signature = %q, want %q", got, want)
+	}
+
+	var payload worker.WebhookPayload
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	if payload.CommunityID != community.ID().String() || math.Abs(payload.NewMomentum-wantMomentum) > 1e-6 {
+		t.Errorf("payload = %+v, want community %s at %v", payload, community.ID(), wantMomentum)
+	}
+	if payload.NewRank == nil || *payload.NewRank != 1 {
+		t.Errorf("payload new_rank = %v, want 1", payload.NewRank)
+	}
+}
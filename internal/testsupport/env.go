@@ -0,0 +1,221 @@
+// Package testsupport runs the real infrastructure for integration tests: postgres with
+// every migration applied and optionally redis, in containers started by testcontainers.
+// tests using it need docker and are skipped without it.
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/joacominatel/pulse/internal/infrastructure/cache"
+	"github.com/joacominatel/pulse/internal/infrastructure/config"
+	"github.com/joacominatel/pulse/internal/infrastructure/database"
+	"github.com/joacominatel/pulse/internal/infrastructure/logging"
+)
+
+// the images docker-compose.yml runs locally
+const (
+	postgresImage = "postgres:18-alpine"
+	redisImage    = "redis:8.0-alpine"
+)
+
+// startTimeout bounds pulling and starting the containers.
+const startTimeout = 3 * time.Minute
+
+// ErrNoDocker is returned by New when there's no docker to run the containers.
+var ErrNoDocker = errors.New("docker is not available")
+
+// Env is the infrastructure for one test, or one package with New.
+type Env struct {
+	Conn   *database.Connection
+	Pool   *pgxpool.Pool
+	Logger *logging.Logger
+
+	// Redis is nil unless the env was started WithRedis.
+	Redis *cache.RedisClient
+
+	containers []testcontainers.Container
+}
+
+// Option configures an Env at Start.
+type Option func(*options)
+
+type options struct {
+	redis  bool
+	logger *logging.Logger
+}
+
+// WithRedis starts a redis container too.
+func WithRedis() Option {
+	return func(o *options) {
+		o.redis = true
+	}
+}
+
+// WithLogger sets the logger, by default errors only are logged, to nowhere.
+func WithLogger(logger *logging.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// Start runs the containers and migrates the database for one test, skipping it without docker.
+// everything is torn down when the test ends.
+func Start(tb testing.TB, opts ...Option) *Env {
+	tb.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	env, err := New(ctx, opts...)
+	if errors.Is(err, ErrNoDocker) {
+		tb.Skip(err.Error())
+	}
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(env.Close)
+	return env
+}
+
+// New runs the containers and migrates the database, for setups that outlive a test
+// like a TestMain. the caller closes the env.
+func New(ctx context.Context, opts ...Option) (env *Env, err error) {
+	o := options{logger: logging.NewWithWriter(io.Discard, slog.LevelError)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := dockerHealthy(ctx); err != nil {
+		return nil, err
+	}
+
+	env = &Env{Logger: o.logger}
+	defer func() {
+		if err != nil {
+			env.Close()
+		}
+	}()
+
+	if err := env.startPostgres(ctx); err != nil {
+		return nil, err
+	}
+	if o.redis {
+		if err := env.startRedis(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// Close disconnects and removes the containers.
+func (e *Env) Close() {
+	if e.Redis != nil {
+		_ = e.Redis.Close()
+	}
+	if e.Conn != nil {
+		e.Conn.Close()
+	}
+	for _, ctr := range e.containers {
+		_ = testcontainers.TerminateContainer(ctr)
+	}
+	e.containers = nil
+}
+
+// dockerHealthy returns ErrNoDocker when testcontainers can't reach docker.
+// the provider panics instead of failing on some setups.
+func dockerHealthy(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrNoDocker, r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDocker, err)
+	}
+	defer func() { _ = provider.Close() }()
+	if err := provider.Health(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDocker, err)
+	}
+	return nil
+}
+
+func (e *Env) startPostgres(ctx context.Context) error {
+	cfg := config.DatabaseConfig{
+		User:     "pulse",
+		Password: "pulse_test",
+		Name:     "pulse",
+		SSLMode:  "disable",
+		Schema:   "pulse",
+	}
+	ctr, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase(cfg.Name),
+		tcpostgres.WithUsername(cfg.User),
+		tcpostgres.WithPassword(cfg.Password),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	if ctr != nil {
+		e.containers = append(e.containers, ctr)
+	}
+	if err != nil {
+		return fmt.Errorf("starting postgres: %w", err)
+	}
+
+	cfg.Host, err = ctr.Host(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres host: %w", err)
+	}
+	port, err := ctr.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		return fmt.Errorf("postgres port: %w", err)
+	}
+	cfg.Port = port.Port()
+
+	e.Conn, err = database.New(&cfg, e.Logger)
+	if err != nil {
+		return fmt.Errorf("connecting to postgres: %w", err)
+	}
+	e.Pool = e.Conn.Pool()
+
+	if _, err := e.Pool.Exec(ctx, supabaseStubs); err != nil {
+		return fmt.Errorf("creating supabase stubs: %w", err)
+	}
+	if _, err := database.NewMigrator(e.Conn, e.Logger).Run(ctx); err != nil {
+		return fmt.Errorf("migrating: %w", err)
+	}
+	return nil
+}
+
+func (e *Env) startRedis(ctx context.Context) error {
+	ctr, err := tcredis.Run(ctx, redisImage)
+	if ctr != nil {
+		e.containers = append(e.containers, ctr)
+	}
+	if err != nil {
+		return fmt.Errorf("starting redis: %w", err)
+	}
+	url, err := ctr.ConnectionString(ctx)
+	if err != nil {
+		return fmt.Errorf("redis url: %w", err)
+	}
+
+	e.Redis, err = cache.NewRedisClient(cache.RedisConfig{URL: url}, e.Logger)
+	if err != nil {
+		return fmt.Errorf("creating redis client: %w", err)
+	}
+	if err := e.Redis.Connect(ctx); err != nil {
+		return fmt.Errorf("connecting to redis: %w", err)
+	}
+	return nil
+}
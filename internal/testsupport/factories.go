@@ -0,0 +1,75 @@
+package testsupport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/joacominatel/pulse/internal/domain"
+	"github.com/joacominatel/pulse/internal/infrastructure/postgres"
+)
+
+// CreateUser saves a user with the given username.
+func (e *Env) CreateUser(tb testing.TB, username string) *domain.User {
+	tb.Helper()
+
+	name, err := domain.NewUsername(username)
+	if err != nil {
+		tb.Fatalf("username %q: %v", username, err)
+	}
+	user, err := domain.NewUser(domain.SystemClock, uuid.New().String(), name)
+	if err != nil {
+		tb.Fatalf("new user: %v", err)
+	}
+	if err := postgres.NewUserRepository(e.Pool).Save(context.Background(), user); err != nil {
+		tb.Fatalf("saving user: %v", err)
+	}
+	return user
+}
+
+// CreateCommunity saves a public community created by creator.
+func (e *Env) CreateCommunity(tb testing.TB, creator *domain.User, slug, name string) *domain.Community {
+	tb.Helper()
+
+	s, err := domain.NewSlug(slug)
+	if err != nil {
+		tb.Fatalf("slug %q: %v", slug, err)
+	}
+	community, err := domain.NewCommunity(domain.SystemClock, s, name, creator.ID())
+	if err != nil {
+		tb.Fatalf("new community: %v", err)
+	}
+	if err := postgres.NewCommunityRepository(e.Pool).Save(context.Background(), community); err != nil {
+		tb.Fatalf("saving community: %v", err)
+	}
+	return community
+}
+
+// CreateWebhookSubscription saves a v1 webhook subscription to a community's spikes,
+// delivered one by one.
+func (e *Env) CreateWebhookSubscription(tb testing.TB, owner *domain.User, community *domain.Community, targetURL, secret string) *domain.WebhookSubscription {
+	tb.Helper()
+
+	id, err := domain.NewWebhookSubscriptionID(uuid.New().String())
+	if err != nil {
+		tb.Fatalf("subscription id: %v", err)
+	}
+	sub, err := domain.NewWebhookSubscription(
+		domain.SystemClock,
+		id,
+		owner.ID(),
+		community.ID(),
+		targetURL,
+		secret,
+		domain.WebhookPayloadV1,
+		domain.WebhookDeliveryImmediate,
+	)
+	if err != nil {
+		tb.Fatalf("new subscription: %v", err)
+	}
+	if err := postgres.NewWebhookSubscriptionRepository(e.Pool).Save(context.Background(), sub); err != nil {
+		tb.Fatalf("saving subscription: %v", err)
+	}
+	return sub
+}
@@ -0,0 +1,57 @@
+package testsupport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Delivery is a request the Receiver got.
+type Delivery struct {
+	Header http.Header
+	Body   []byte
+}
+
+// Receiver is a stub webhook endpoint that records deliveries and answers 200.
+type Receiver struct {
+	URL        string
+	deliveries chan Delivery
+}
+
+// NewReceiver starts a receiver, closed when the test ends.
+func NewReceiver(tb testing.TB) *Receiver {
+	tb.Helper()
+
+	r := &Receiver{deliveries: make(chan Delivery, 100)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		select {
+		case r.deliveries <- Delivery{Header: req.Header.Clone(), Body: body}:
+		default:
+			// a test expecting this many deliveries has already failed
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	tb.Cleanup(server.Close)
+	r.URL = server.URL
+	return r
+}
+
+// Wait returns the next delivery, failing the test when none arrives within timeout.
+func (r *Receiver) Wait(tb testing.TB, timeout time.Duration) Delivery {
+	tb.Helper()
+
+	select {
+	case d := <-r.deliveries:
+		return d
+	case <-time.After(timeout):
+		tb.Fatalf("no webhook delivery within %s", timeout)
+		return Delivery{}
+	}
+}